@@ -28,19 +28,25 @@ import (
 
 	"github.com/cgrates/cgrates/config"
 	"github.com/cgrates/cgrates/engine"
+	"github.com/cgrates/cgrates/sessionmanager"
 	"github.com/cgrates/cgrates/utils"
 	"github.com/cgrates/rpcclient"
 	"github.com/fiorix/go-diameter/diam"
+	"github.com/fiorix/go-diameter/diam/avp"
 	"github.com/fiorix/go-diameter/diam/datatype"
 	"github.com/fiorix/go-diameter/diam/sm"
 )
 
 func NewDiameterAgent(cgrCfg *config.CGRConfig, smg rpcclient.RpcClientConnection,
 	pubsubs rpcclient.RpcClientConnection) (*DiameterAgent, error) {
-	da := &DiameterAgent{cgrCfg: cgrCfg, smg: smg, pubsubs: pubsubs, connMux: new(sync.Mutex)}
-	if reflect.ValueOf(da.pubsubs).IsNil() {
+	da := &DiameterAgent{cgrCfg: cgrCfg, smg: smg, pubsubs: pubsubs, connMux: new(sync.Mutex),
+		peerSessions: make(map[string]*diamPeerSession), watchedConns: make(map[diam.Conn]struct{})}
+	if da.pubsubs != nil && reflect.ValueOf(da.pubsubs).IsNil() {
 		da.pubsubs = nil // Empty it so we can check it later
 	}
+	if biClnt, isBiRPC := smg.(*utils.BiRPCInternalClient); isBiRPC {
+		biClnt.SetClientConn(da) // pass the connection to DA back into smg so we can receive the disconnects and re-auths
+	}
 	dictsDir := cgrCfg.DiameterAgentCfg().DictionariesDir
 	if len(dictsDir) != 0 {
 		if err := loadDictionaries(dictsDir, "DiameterAgent"); err != nil {
@@ -51,10 +57,13 @@ func NewDiameterAgent(cgrCfg *config.CGRConfig, smg rpcclient.RpcClientConnectio
 }
 
 type DiameterAgent struct {
-	cgrCfg  *config.CGRConfig
-	smg     rpcclient.RpcClientConnection // Connection towards CGR-SMG component
-	pubsubs rpcclient.RpcClientConnection // Connection towards CGR-PubSub component
-	connMux *sync.Mutex                   // Protect connection for read/write
+	cgrCfg       *config.CGRConfig
+	smg          rpcclient.RpcClientConnection // Connection towards CGR-SMG component
+	pubsubs      rpcclient.RpcClientConnection // Connection towards CGR-PubSub component
+	connMux      *sync.Mutex                   // Protect connection for read/write
+	peerSessions map[string]*diamPeerSession   // peers which opened the sessions, indexed on OriginID
+	watchedConns map[diam.Conn]struct{}        // connections monitored for close so we can clean their sessions
+	pSessMux     sync.RWMutex                  // protects peerSessions and watchedConns
 }
 
 // Creates the message handlers
@@ -68,6 +77,9 @@ func (self *DiameterAgent) handlers() diam.Handler {
 	}
 	dSM := sm.New(settings)
 	dSM.HandleFunc("CCR", self.handleCCR)
+	dSM.HandleFunc("ACR", self.handleACR)
+	dSM.HandleFunc("ASA", self.handleServerAnswer)
+	dSM.HandleFunc("RAA", self.handleServerAnswer)
 	dSM.HandleFunc("ALL", self.handleALL)
 	go func() {
 		for err := range dSM.ErrorReports() {
@@ -77,7 +89,7 @@ func (self *DiameterAgent) handlers() diam.Handler {
 	return dSM
 }
 
-func (self DiameterAgent) processCCR(ccr *CCR, reqProcessor *config.DARequestProcessor,
+func (self *DiameterAgent) processCCR(c diam.Conn, ccr *CCR, reqProcessor *config.DARequestProcessor,
 	processorVars map[string]string, cca *CCA) (bool, error) {
 	passesAllFilters := true
 	for _, fldFilter := range reqProcessor.RequestFilter {
@@ -110,18 +122,7 @@ func (self DiameterAgent) processCCR(ccr *CCR, reqProcessor *config.DARequestPro
 		smgEv[utils.CGRFlags] = reqProcessor.Flags.String() // Populate CGRFlags automatically
 	}
 	if reqProcessor.PublishEvent && self.pubsubs != nil {
-		evt, err := smgEv.AsMapStringString()
-		if err != nil {
-			*cca = *NewBareCCAFromCCR(ccr, self.cgrCfg.DiameterAgentCfg().OriginHost, self.cgrCfg.DiameterAgentCfg().OriginRealm)
-			if err := messageSetAVPsWithPath(cca.diamMessage, []interface{}{"Result-Code"}, strconv.Itoa(DiameterRatingFailed),
-				false, self.cgrCfg.DiameterAgentCfg().Timezone); err != nil {
-				return false, err
-			}
-			utils.Logger.Err(fmt.Sprintf("<DiameterAgent> Processing message: %+v failed converting SMGEvent to pubsub one, error: %s", ccr.diamMessage, err))
-			return false, ErrDiameterRatingFailed
-		}
-		var reply string
-		if err := self.pubsubs.Call("PubSubV1.Publish", engine.CgrEvent(evt), &reply); err != nil {
+		if err := self.publishEvent(smgEv); err != nil {
 			*cca = *NewBareCCAFromCCR(ccr, self.cgrCfg.DiameterAgentCfg().OriginHost, self.cgrCfg.DiameterAgentCfg().OriginRealm)
 			if err := messageSetAVPsWithPath(cca.diamMessage, []interface{}{"Result-Code"}, strconv.Itoa(DiameterRatingFailed),
 				false, self.cgrCfg.DiameterAgentCfg().Timezone); err != nil {
@@ -140,13 +141,18 @@ func (self DiameterAgent) processCCR(ccr *CCR, reqProcessor *config.DARequestPro
 	} else { // Find out maxUsage over APIs
 		switch ccr.CCRequestType {
 		case 1:
-			err = self.smg.Call("SMGenericV2.InitiateSession", smgEv, &maxUsage)
+			if err = self.smg.Call("SMGenericV2.InitiateSession", smgEv, &maxUsage); err == nil {
+				self.trackPeerSession(smgEv.GetOriginID(utils.META_DEFAULT),
+					&diamPeerSession{conn: c, sessionID: ccr.SessionId, originHost: ccr.OriginHost,
+						originRealm: ccr.OriginRealm, applicationID: ccr.diamMessage.Header.ApplicationID})
+			}
 		case 2:
 			err = self.smg.Call("SMGenericV2.UpdateSession", smgEv, &maxUsage)
 		case 3, 4: // Handle them together since we generate CDR for them
 			var rpl string
 			if ccr.CCRequestType == 3 {
 				err = self.smg.Call("SMGenericV1.TerminateSession", smgEv, &rpl)
+				self.untrackPeerSession(smgEv.GetOriginID(utils.META_DEFAULT))
 			} else if ccr.CCRequestType == 4 {
 				err = self.smg.Call("SMGenericV2.ChargeEvent", smgEv.Clone(), &maxUsage)
 				if maxUsage == 0 {
@@ -162,34 +168,9 @@ func (self DiameterAgent) processCCR(ccr *CCR, reqProcessor *config.DARequestPro
 		}
 		if err != nil {
 			utils.Logger.Err(fmt.Sprintf("<DiameterAgent> Processing message: %+v, API error: %s", ccr.diamMessage, err))
-			switch { // Prettify some errors
-			case strings.HasSuffix(err.Error(), utils.ErrAccountNotFound.Error()):
-				processorVars[CGRError] = utils.ErrAccountNotFound.Error()
-			case strings.HasSuffix(err.Error(), utils.ErrUserNotFound.Error()):
-				processorVars[CGRError] = utils.ErrUserNotFound.Error()
-			case strings.HasSuffix(err.Error(), utils.ErrInsufficientCredit.Error()):
-				processorVars[CGRError] = utils.ErrInsufficientCredit.Error()
-			case strings.HasSuffix(err.Error(), utils.ErrAccountDisabled.Error()):
-				processorVars[CGRError] = utils.ErrAccountDisabled.Error()
-			case strings.HasSuffix(err.Error(), utils.ErrRatingPlanNotFound.Error()):
-				processorVars[CGRError] = utils.ErrRatingPlanNotFound.Error()
-			case strings.HasSuffix(err.Error(), utils.ErrUnauthorizedDestination.Error()):
-				processorVars[CGRError] = utils.ErrUnauthorizedDestination.Error()
-			default: // Unknown error
-				processorVars[CGRError] = err.Error()
-				processorVars[CGRResultCode] = strconv.Itoa(DiameterRatingFailed)
-			}
-		}
-		if maxUsage < 0 {
-			maxUsage = 0
+			setProcessorVarsError(err, processorVars)
 		}
-		if prevMaxUsageStr, hasKey := processorVars[CGRMaxUsage]; hasKey {
-			prevMaxUsage, _ := utils.ParseDurationWithNanosecs(prevMaxUsageStr)
-			if prevMaxUsage < maxUsage {
-				maxUsage = prevMaxUsage
-			}
-		}
-		processorVars[CGRMaxUsage] = strconv.FormatInt(maxUsage.Nanoseconds(), 10)
+		setProcessorVarsMaxUsage(maxUsage, processorVars)
 	}
 	if err := messageSetAVPsWithPath(cca.diamMessage, []interface{}{"Result-Code"}, processorVars[CGRResultCode],
 		false, self.cgrCfg.DiameterAgentCfg().Timezone); err != nil {
@@ -209,6 +190,51 @@ func (self DiameterAgent) processCCR(ccr *CCR, reqProcessor *config.DARequestPro
 	return true, nil
 }
 
+// publishEvent sends the event to the PubSub component
+func (self *DiameterAgent) publishEvent(smgEv sessionmanager.SMGenericEvent) error {
+	evt, err := smgEv.AsMapStringString()
+	if err != nil {
+		return err
+	}
+	var reply string
+	return self.pubsubs.Call("PubSubV1.Publish", engine.CgrEvent(evt), &reply)
+}
+
+// setProcessorVarsError populates CGRError and CGRResultCode out of the SMG API error
+func setProcessorVarsError(err error, processorVars map[string]string) {
+	switch { // Prettify some errors
+	case strings.HasSuffix(err.Error(), utils.ErrAccountNotFound.Error()):
+		processorVars[CGRError] = utils.ErrAccountNotFound.Error()
+	case strings.HasSuffix(err.Error(), utils.ErrUserNotFound.Error()):
+		processorVars[CGRError] = utils.ErrUserNotFound.Error()
+	case strings.HasSuffix(err.Error(), utils.ErrInsufficientCredit.Error()):
+		processorVars[CGRError] = utils.ErrInsufficientCredit.Error()
+	case strings.HasSuffix(err.Error(), utils.ErrAccountDisabled.Error()):
+		processorVars[CGRError] = utils.ErrAccountDisabled.Error()
+	case strings.HasSuffix(err.Error(), utils.ErrRatingPlanNotFound.Error()):
+		processorVars[CGRError] = utils.ErrRatingPlanNotFound.Error()
+	case strings.HasSuffix(err.Error(), utils.ErrUnauthorizedDestination.Error()):
+		processorVars[CGRError] = utils.ErrUnauthorizedDestination.Error()
+	default: // Unknown error
+		processorVars[CGRError] = err.Error()
+		processorVars[CGRResultCode] = strconv.Itoa(DiameterRatingFailed)
+	}
+}
+
+// setProcessorVarsMaxUsage populates CGRMaxUsage, keeping the smallest one out of processors
+func setProcessorVarsMaxUsage(maxUsage time.Duration, processorVars map[string]string) {
+	if maxUsage < 0 {
+		maxUsage = 0
+	}
+	if prevMaxUsageStr, hasKey := processorVars[CGRMaxUsage]; hasKey {
+		prevMaxUsage, _ := utils.ParseDurationWithNanosecs(prevMaxUsageStr)
+		if prevMaxUsage < maxUsage {
+			maxUsage = prevMaxUsage
+		}
+	}
+	processorVars[CGRMaxUsage] = strconv.FormatInt(maxUsage.Nanoseconds(), 10)
+}
+
 func (self *DiameterAgent) handlerCCR(c diam.Conn, m *diam.Message) {
 	ccr, err := NewCCRFromDiameterMessage(m, self.cgrCfg.DiameterAgentCfg().DebitInterval)
 	if err != nil {
@@ -219,7 +245,7 @@ func (self *DiameterAgent) handlerCCR(c diam.Conn, m *diam.Message) {
 	var processed, lclProcessed bool
 	processorVars := make(map[string]string) // Shared between processors
 	for _, reqProcessor := range self.cgrCfg.DiameterAgentCfg().RequestProcessors {
-		lclProcessed, err = self.processCCR(ccr, reqProcessor, processorVars, cca)
+		lclProcessed, err = self.processCCR(c, ccr, reqProcessor, processorVars, cca)
 		if lclProcessed { // Process local so we don't overwrite globally
 			processed = lclProcessed
 		}
@@ -252,6 +278,259 @@ func (self *DiameterAgent) handleALL(c diam.Conn, m *diam.Message) {
 	utils.Logger.Warning(fmt.Sprintf("<DiameterAgent> Received unexpected message from %s:\n%s", c.RemoteAddr(), m))
 }
 
+func (self *DiameterAgent) processACR(c diam.Conn, acr *ACR, reqProcessor *config.DARequestProcessor,
+	processorVars map[string]string, aca *ACA) (bool, error) {
+	for _, fldFilter := range reqProcessor.RequestFilter {
+		if passes, _ := passesFieldFilter(acr.diamMessage, fldFilter, nil); !passes {
+			return false, nil // Not going with this processor further
+		}
+	}
+	if reqProcessor.DryRun { // DryRun should log the matching processor as well as the received ACR
+		utils.Logger.Info(fmt.Sprintf("<DiameterAgent> RequestProcessor: %s", reqProcessor.Id))
+		utils.Logger.Info(fmt.Sprintf("<DiameterAgent> ACR message: %s", acr.diamMessage))
+	}
+	if !reqProcessor.AppendCCA {
+		*aca = *NewBareACAFromACR(acr, self.cgrCfg.DiameterAgentCfg().OriginHost, self.cgrCfg.DiameterAgentCfg().OriginRealm)
+	}
+	smgEv, err := acr.AsSMGenericEvent(reqProcessor.CCRFields)
+	if err == nil && reqProcessor.PublishEvent && self.pubsubs != nil {
+		err = self.publishEvent(smgEv)
+	}
+	if err != nil {
+		utils.Logger.Err(fmt.Sprintf("<DiameterAgent> Processing message: %+v, error: %s", acr.diamMessage, err))
+		*aca = *NewBareACAFromACR(acr, self.cgrCfg.DiameterAgentCfg().OriginHost, self.cgrCfg.DiameterAgentCfg().OriginRealm)
+		if err := messageSetAVPsWithPath(aca.diamMessage, []interface{}{"Result-Code"}, strconv.Itoa(DiameterRatingFailed),
+			false, self.cgrCfg.DiameterAgentCfg().Timezone); err != nil {
+			return false, err
+		}
+		return false, ErrDiameterRatingFailed
+	}
+	if len(reqProcessor.Flags) != 0 {
+		smgEv[utils.CGRFlags] = reqProcessor.Flags.String() // Populate CGRFlags automatically
+	}
+	var maxUsage time.Duration
+	processorVars[CGRResultCode] = strconv.Itoa(diam.Success)
+	processorVars[CGRError] = ""
+	if reqProcessor.DryRun { // DryRun does not send over network
+		utils.Logger.Info(fmt.Sprintf("<DiameterAgent> SMGenericEvent: %+v", smgEv))
+		processorVars[CGRResultCode] = strconv.Itoa(diam.LimitedSuccess)
+	} else {
+		originID := smgEv.GetOriginID(utils.META_DEFAULT)
+		switch acr.AccountingRecordType {
+		case AcctRecordStart:
+			if err = self.smg.Call("SMGenericV2.InitiateSession", smgEv, &maxUsage); err == nil {
+				self.trackPeerSession(originID,
+					&diamPeerSession{conn: c, sessionID: acr.SessionId, originHost: acr.OriginHost,
+						originRealm: acr.OriginRealm, applicationID: acr.diamMessage.Header.ApplicationID,
+						accounting: true})
+			}
+		case AcctRecordInterim:
+			err = self.smg.Call("SMGenericV2.UpdateSession", smgEv, &maxUsage)
+		case AcctRecordStop, AcctRecordEvent: // Handle them together since we generate CDR for them
+			var rpl string
+			if acr.AccountingRecordType == AcctRecordStop {
+				err = self.smg.Call("SMGenericV1.TerminateSession", smgEv, &rpl)
+				self.untrackPeerSession(originID)
+			} else {
+				err = self.smg.Call("SMGenericV2.ChargeEvent", smgEv.Clone(), &maxUsage)
+				if maxUsage == 0 {
+					smgEv[utils.Usage] = 0 // For CDR not to debit
+				}
+			}
+			if self.cgrCfg.DiameterAgentCfg().CreateCDR &&
+				(!self.cgrCfg.DiameterAgentCfg().CDRRequiresSession || err == nil || !strings.HasSuffix(err.Error(), utils.ErrNoActiveSession.Error())) { // Check if CDR requires session
+				if errCdr := self.smg.Call("SMGenericV1.ProcessCDR", smgEv, &rpl); errCdr != nil {
+					err = errCdr
+				}
+			}
+		default:
+			err = fmt.Errorf("unsupported Accounting-Record-Type: <%d>", acr.AccountingRecordType)
+		}
+		if err != nil {
+			utils.Logger.Err(fmt.Sprintf("<DiameterAgent> Processing message: %+v, API error: %s", acr.diamMessage, err))
+			setProcessorVarsError(err, processorVars)
+		}
+		setProcessorVarsMaxUsage(maxUsage, processorVars)
+	}
+	if err := messageSetAVPsWithPath(aca.diamMessage, []interface{}{"Result-Code"}, processorVars[CGRResultCode],
+		false, self.cgrCfg.DiameterAgentCfg().Timezone); err != nil {
+		return false, err
+	}
+	if err := aca.SetProcessorAVPs(reqProcessor, processorVars); err != nil {
+		if err := messageSetAVPsWithPath(aca.diamMessage, []interface{}{"Result-Code"}, strconv.Itoa(DiameterRatingFailed),
+			false, self.cgrCfg.DiameterAgentCfg().Timezone); err != nil {
+			return false, err
+		}
+		utils.Logger.Err(fmt.Sprintf("<DiameterAgent> ACA SetProcessorAVPs for message: %+v, error: %s", acr.diamMessage, err))
+		return false, ErrDiameterRatingFailed
+	}
+	if reqProcessor.DryRun {
+		utils.Logger.Info(fmt.Sprintf("<DiameterAgent> ACA message: %s", aca.diamMessage))
+	}
+	return true, nil
+}
+
+func (self *DiameterAgent) handlerACR(c diam.Conn, m *diam.Message) {
+	acr, err := NewACRFromDiameterMessage(m, self.cgrCfg.DiameterAgentCfg().DebitInterval)
+	if err != nil {
+		utils.Logger.Err(fmt.Sprintf("<DiameterAgent> Unmarshaling message: %s, error: %s", m, err))
+		return
+	}
+	aca := NewBareACAFromACR(acr, self.cgrCfg.DiameterAgentCfg().OriginHost, self.cgrCfg.DiameterAgentCfg().OriginRealm)
+	var processed, lclProcessed bool
+	processorVars := make(map[string]string) // Shared between processors
+	for _, reqProcessor := range self.cgrCfg.DiameterAgentCfg().RequestProcessors {
+		lclProcessed, err = self.processACR(c, acr, reqProcessor, processorVars, aca)
+		if lclProcessed { // Process local so we don't overwrite globally
+			processed = lclProcessed
+		}
+		if err != nil || (lclProcessed && !reqProcessor.ContinueOnSuccess) {
+			break
+		}
+	}
+	if err != nil && err != ErrDiameterRatingFailed {
+		utils.Logger.Err(fmt.Sprintf("<DiameterAgent> ACA SetProcessorAVPs for message: %+v, error: %s", acr.diamMessage, err))
+		return
+	} else if !processed {
+		utils.Logger.Err(fmt.Sprintf("<DiameterAgent> No request processor enabled for ACR: %s, ignoring request", acr.diamMessage))
+		return
+	}
+	self.connMux.Lock()
+	defer self.connMux.Unlock()
+	if _, err := aca.AsDiameterMessage().WriteTo(c); err != nil {
+		utils.Logger.Err(fmt.Sprintf("<DiameterAgent> Failed to write message to %s: %s\n%s\n", c.RemoteAddr(), err, aca.AsDiameterMessage()))
+		return
+	}
+}
+
+// Simply dispatch the handling in goroutines
+func (self *DiameterAgent) handleACR(c diam.Conn, m *diam.Message) {
+	go self.handlerACR(c, m)
+}
+
+// handleServerAnswer receives the answers to the ASR and RAR sent by us
+func (self *DiameterAgent) handleServerAnswer(c diam.Conn, m *diam.Message) {
+	rsltCode, err := m.FindAVP(avp.ResultCode, 0)
+	if err != nil {
+		utils.Logger.Warning(fmt.Sprintf("<DiameterAgent> Received answer without Result-Code from %s:\n%s", c.RemoteAddr(), m))
+		return
+	}
+	if avpValAsString(rsltCode) != strconv.Itoa(diam.Success) {
+		utils.Logger.Warning(fmt.Sprintf("<DiameterAgent> Received unsuccessful answer from %s:\n%s", c.RemoteAddr(), m))
+	}
+}
+
+// trackPeerSession remembers the peer which opened the session so we can reach it with server initiated requests
+func (self *DiameterAgent) trackPeerSession(originID string, pSess *diamPeerSession) {
+	self.pSessMux.Lock()
+	defer self.pSessMux.Unlock()
+	self.peerSessions[originID] = pSess
+	if _, watched := self.watchedConns[pSess.conn]; watched {
+		return
+	}
+	cn, canNotify := pSess.conn.(diam.CloseNotifier)
+	if !canNotify {
+		return
+	}
+	self.watchedConns[pSess.conn] = struct{}{}
+	go func() {
+		<-cn.CloseNotify()
+		self.untrackPeerConn(pSess.conn)
+	}()
+}
+
+func (self *DiameterAgent) untrackPeerSession(originID string) {
+	self.pSessMux.Lock()
+	delete(self.peerSessions, originID)
+	self.pSessMux.Unlock()
+}
+
+// untrackPeerConn removes the sessions opened over a connection which went away
+func (self *DiameterAgent) untrackPeerConn(c diam.Conn) {
+	self.pSessMux.Lock()
+	defer self.pSessMux.Unlock()
+	for originID, pSess := range self.peerSessions {
+		if pSess.conn == c {
+			delete(self.peerSessions, originID)
+		}
+	}
+	delete(self.watchedConns, c)
+}
+
+func (self *DiameterAgent) peerSession(originID string) (pSess *diamPeerSession, has bool) {
+	self.pSessMux.RLock()
+	pSess, has = self.peerSessions[originID]
+	self.pSessMux.RUnlock()
+	return
+}
+
+// sendToPeer writes a server initiated request on the connection of the peer
+func (self *DiameterAgent) sendToPeer(pSess *diamPeerSession, m *diam.Message) (err error) {
+	self.connMux.Lock()
+	defer self.connMux.Unlock()
+	_, err = m.WriteTo(pSess.conn)
+	return
+}
+
+// Internal method to disconnect session, sends Abort-Session-Request to the peer which opened it
+func (self *DiameterAgent) V1DisconnectSession(args utils.AttrDisconnectSession, reply *string) error {
+	originID := sessionmanager.SMGenericEvent(args.EventStart).GetOriginID(utils.META_DEFAULT)
+	pSess, has := self.peerSession(originID)
+	if !has {
+		return utils.ErrNotFound
+	}
+	if err := self.sendToPeer(pSess, newASR(pSess, self.cgrCfg.DiameterAgentCfg().OriginHost,
+		self.cgrCfg.DiameterAgentCfg().OriginRealm)); err != nil {
+		utils.Logger.Err(fmt.Sprintf("<DiameterAgent> Error: %s when sending ASR for session: %s, reason: %s", err.Error(), pSess.sessionID, args.Reason))
+		return err
+	}
+	*reply = utils.OK
+	return nil
+}
+
+// Internal method to re-authorize session, sends Re-Auth-Request to the peer which opened it
+func (self *DiameterAgent) V1ReAuthSession(args utils.AttrReAuthSession, reply *string) error {
+	originID := sessionmanager.SMGenericEvent(args.EventStart).GetOriginID(utils.META_DEFAULT)
+	pSess, has := self.peerSession(originID)
+	if !has {
+		return utils.ErrNotFound
+	}
+	if err := self.sendToPeer(pSess, newRAR(pSess, self.cgrCfg.DiameterAgentCfg().OriginHost,
+		self.cgrCfg.DiameterAgentCfg().OriginRealm)); err != nil {
+		utils.Logger.Err(fmt.Sprintf("<DiameterAgent> Error: %s when sending RAR for session: %s", err.Error(), pSess.sessionID))
+		return err
+	}
+	*reply = utils.OK
+	return nil
+}
+
+// rpcclient.RpcClientConnection interface, used by SMG to reach back the agent
+func (self *DiameterAgent) Call(serviceMethod string, args interface{}, reply interface{}) error {
+	parts := strings.Split(serviceMethod, ".")
+	if len(parts) != 2 {
+		return rpcclient.ErrUnsupporteServiceMethod
+	}
+	// get method
+	method := reflect.ValueOf(self).MethodByName(parts[0][len(parts[0])-2:] + parts[1]) // Inherit the version in the method
+	if !method.IsValid() {
+		return rpcclient.ErrUnsupporteServiceMethod
+	}
+	// construct the params
+	params := []reflect.Value{reflect.ValueOf(args), reflect.ValueOf(reply)}
+	ret := method.Call(params)
+	if len(ret) != 1 {
+		return utils.ErrServerError
+	}
+	if ret[0].Interface() == nil {
+		return nil
+	}
+	err, ok := ret[0].Interface().(error)
+	if !ok {
+		return utils.ErrServerError
+	}
+	return err
+}
+
 func (self *DiameterAgent) ListenAndServe() error {
 	return diam.ListenAndServe(self.cgrCfg.DiameterAgentCfg().Listen, self.handlers(), nil)
 }
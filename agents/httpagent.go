@@ -0,0 +1,175 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package agents
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/cgrates/cgrates/config"
+	"github.com/cgrates/cgrates/utils"
+	"github.com/cgrates/rpcclient"
+)
+
+const (
+	EvHttpReq = "HTTP_REQUEST"
+)
+
+func NewHttpAgent(cgrCfg *config.CGRConfig, smg rpcclient.RpcClientConnection) *HttpAgent {
+	return &HttpAgent{cgrCfg: cgrCfg, smg: smg}
+}
+
+// HttpAgent converts plain HTTP requests (eg: webhooks) into SMG calls based on request processors
+type HttpAgent struct {
+	cgrCfg *config.CGRConfig             // reference for future config reloads
+	smg    rpcclient.RpcClientConnection // Connection towards CGR-SMG component
+}
+
+// ServeHTTP implements http.Handler interface
+func (ha *HttpAgent) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	reqVars, err := httpRequestVars(req, ha.cgrCfg.HttpAgentCfg().RequestPayload)
+	if err != nil {
+		utils.Logger.Warning(fmt.Sprintf("<HttpAgent> error: <%s> decoding request", err.Error()))
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	procVars := make(map[string]string)
+	var reply httpReply
+	var processed bool
+	for _, reqProcessor := range ha.cgrCfg.HttpAgentCfg().RequestProcessors {
+		var lclProcessed bool
+		if lclProcessed, err = ha.processRequest(reqProcessor, reqVars, procVars, &reply); lclProcessed {
+			processed = lclProcessed
+		}
+		if err != nil || (lclProcessed && !reqProcessor.ContinueOnSuccess) {
+			break
+		}
+	}
+	if err != nil {
+		utils.Logger.Err(fmt.Sprintf("<HttpAgent> error: <%s> ignoring request: %+v, process vars: %+v",
+			err.Error(), reqVars, procVars))
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	} else if !processed {
+		utils.Logger.Err(fmt.Sprintf("<HttpAgent> No request processor enabled, ignoring request %+v, process vars: %+v",
+			reqVars, procVars))
+		http.Error(w, "no request processor enabled", http.StatusBadRequest)
+		return
+	}
+	body, contentType, err := reply.encode(ha.cgrCfg.HttpAgentCfg().ReplyPayload)
+	if err != nil {
+		utils.Logger.Err(fmt.Sprintf("<HttpAgent> error: <%s> encoding reply: %+v", err.Error(), reply))
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.Write(body)
+}
+
+// processRequest represents one processor processing the request
+// SMG errors do not stop processing, they are available to reply templates as *cgrError
+func (ha *HttpAgent) processRequest(reqProcessor *config.HttpAgntProcCfg,
+	reqVars, processorVars map[string]string, reply *httpReply) (processed bool, err error) {
+	for _, fldFilter := range reqProcessor.RequestFilter {
+		if !httpPassesFieldFilter(reqVars, processorVars, fldFilter) {
+			return false, nil // Not going with this processor further
+		}
+	}
+	for k, v := range reqProcessor.Flags { // update processorVars with flags from processor
+		processorVars[k] = strconv.FormatBool(v)
+	}
+	if reqProcessor.DryRun {
+		utils.Logger.Info(fmt.Sprintf("<HttpAgent> DRY_RUN, HTTP request: %+v", reqVars))
+		utils.Logger.Info(fmt.Sprintf("<HttpAgent> DRY_RUN, process variabiles: %+v", processorVars))
+	}
+	smgEv, err := httpReqAsSMGEvent(reqVars, processorVars, reqProcessor.Flags, reqProcessor.RequestFields)
+	if err != nil {
+		return false, err
+	}
+	if reqProcessor.DryRun {
+		utils.Logger.Info(fmt.Sprintf("<HttpAgent> DRY_RUN, SMGEvent: %+v", smgEv))
+	} else { // process with RPC
+		var maxUsage time.Duration
+		var cgrReply interface{} // so we can store it in processorsVars
+		var errSMG error
+		if reqProcessor.Flags[utils.MetaAuth] {
+			errSMG = ha.smg.Call("SMGenericV2.GetMaxUsage", smgEv, &maxUsage)
+			cgrReply = maxUsage
+		}
+		if errSMG == nil && reqProcessor.Flags[utils.MetaInitiate] {
+			errSMG = ha.smg.Call("SMGenericV2.InitiateSession", smgEv, &maxUsage)
+			cgrReply = maxUsage
+		}
+		if errSMG == nil && reqProcessor.Flags[utils.MetaUpdate] {
+			errSMG = ha.smg.Call("SMGenericV2.UpdateSession", smgEv, &maxUsage)
+			cgrReply = maxUsage
+		}
+		if errSMG == nil && reqProcessor.Flags[utils.MetaTerminate] {
+			var rpl string
+			errSMG = ha.smg.Call("SMGenericV1.TerminateSession", smgEv, &rpl)
+			cgrReply = rpl
+		}
+		if errSMG == nil && reqProcessor.Flags[utils.MetaEvent] {
+			errSMG = ha.smg.Call("SMGenericV2.ChargeEvent", smgEv.Clone(), &maxUsage)
+			cgrReply = maxUsage
+			if maxUsage == 0 {
+				smgEv[utils.Usage] = 0 // For CDR not to debit
+			}
+		}
+		if errSMG == nil && reqProcessor.Flags[utils.MetaReserve] {
+			errSMG = ha.smg.Call("SMGenericV2.ReserveEvent", smgEv.Clone(), &maxUsage)
+			cgrReply = maxUsage
+		}
+		if errSMG == nil && reqProcessor.Flags[utils.MetaCommit] {
+			var rpl string
+			errSMG = ha.smg.Call("SMGenericV1.CommitEvent", smgEv, &rpl)
+			cgrReply = rpl
+		}
+		if errSMG == nil && reqProcessor.Flags[utils.MetaRefund] {
+			var rpl string
+			errSMG = ha.smg.Call("SMGenericV1.RefundEvent", smgEv, &rpl)
+			cgrReply = rpl
+		}
+		if reqProcessor.Flags[utils.MetaCDR] &&
+			(errSMG == nil || reqProcessor.Flags[utils.MetaTerminate]) { // CDR also for failed terminates
+			var rpl string
+			if errCdr := ha.smg.Call("SMGenericV1.ProcessCDR", smgEv, &rpl); errCdr != nil {
+				errSMG = errCdr
+			} else {
+				cgrReply = rpl
+			}
+		}
+		if errSMG != nil {
+			processorVars[MetaCGRError] = errSMG.Error()
+		}
+		processorVars[MetaCGRReply] = utils.ToJSON(cgrReply)
+		processorVars[MetaCGRMaxUsage] = strconv.Itoa(int(maxUsage))
+	}
+	flds, err := httpFieldsPassing(reqVars, processorVars, reqProcessor.ReplyFields)
+	if err != nil {
+		return false, err
+	}
+	*reply = append(*reply, flds...)
+	if reqProcessor.DryRun {
+		utils.Logger.Info(fmt.Sprintf("<HttpAgent> DRY_RUN, HTTP reply: %s", utils.ToJSON(reply)))
+	}
+	return true, nil
+}
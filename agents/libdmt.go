@@ -53,12 +53,21 @@ const (
 	META_VALUE_EXPONENT  = "*value_exponent"
 	META_SUM             = "*sum"
 	DIAMETER_CCR         = "DIAMETER_CCR"
+	DIAMETER_ACR         = "DIAMETER_ACR"
 	DiameterRatingFailed = 5031
 	CGRError             = "CGRError"
 	CGRMaxUsage          = "CGRMaxUsage"
 	CGRResultCode        = "CGRResultCode"
 )
 
+// Accounting-Record-Type values, RFC 6733
+const (
+	AcctRecordEvent   = 1
+	AcctRecordStart   = 2
+	AcctRecordInterim = 3
+	AcctRecordStop    = 4
+)
+
 var (
 	ErrFilterNotPassing     = errors.New("Filter not passing")
 	ErrDiameterRatingFailed = errors.New("Diameter rating failed")
@@ -621,10 +630,16 @@ func (self *CCR) AsDiameterMessage() (*diam.Message, error) {
 
 // Extracts data out of CCR into a SMGenericEvent based on the configured template
 func (self *CCR) AsSMGenericEvent(cfgFlds []*config.CfgCdrField) (sessionmanager.SMGenericEvent, error) {
+	return diamMessageAsSMGenericEvent(self.diamMessage, DIAMETER_CCR, cfgFlds, self.debitInterval)
+}
+
+// diamMessageAsSMGenericEvent extracts data out of a diameter request into a SMGenericEvent based on the configured template
+func diamMessageAsSMGenericEvent(m *diam.Message, evName string,
+	cfgFlds []*config.CfgCdrField, debitInterval time.Duration) (sessionmanager.SMGenericEvent, error) {
 	outMap := make(map[string]string) // work with it so we can append values to keys
-	outMap[utils.EVENT_NAME] = DIAMETER_CCR
+	outMap[utils.EVENT_NAME] = evName
 	for _, cfgFld := range cfgFlds {
-		fmtOut, err := fieldOutVal(self.diamMessage, cfgFld, self.debitInterval, nil)
+		fmtOut, err := fieldOutVal(m, cfgFld, debitInterval, nil)
 		if err != nil {
 			if err == ErrFilterNotPassing {
 				continue // Do nothing in case of Filter not passing
@@ -693,10 +708,17 @@ func (self *CCA) AsDiameterMessage() *diam.Message {
 
 // SetProcessorAVPs will add AVPs to self.diameterMessage based on template defined in processor.CCAFields
 func (self *CCA) SetProcessorAVPs(reqProcessor *config.DARequestProcessor, processorVars map[string]string) error {
+	return setAnswerProcessorAVPs(self.ccrMessage, self.diamMessage, reqProcessor, processorVars, self.timezone)
+}
+
+// setAnswerProcessorAVPs populates the answer based on template defined in processor.CCAFields,
+// the values are looked up first in the request and then in the answer itself
+func setAnswerProcessorAVPs(reqMsg, answMsg *diam.Message, reqProcessor *config.DARequestProcessor,
+	processorVars map[string]string, timezone string) error {
 	for _, cfgFld := range reqProcessor.CCAFields {
-		fmtOut, err := fieldOutVal(self.ccrMessage, cfgFld, nil, processorVars)
+		fmtOut, err := fieldOutVal(reqMsg, cfgFld, nil, processorVars)
 		if err == ErrFilterNotPassing { // Field not in or filter not passing, try match in answer
-			fmtOut, err = fieldOutVal(self.diamMessage, cfgFld, nil, processorVars)
+			fmtOut, err = fieldOutVal(answMsg, cfgFld, nil, processorVars)
 		}
 		if err != nil {
 			if err == ErrFilterNotPassing {
@@ -704,9 +726,9 @@ func (self *CCA) SetProcessorAVPs(reqProcessor *config.DARequestProcessor, proce
 			}
 			return err
 		}
-		if err := messageSetAVPsWithPath(self.diamMessage,
+		if err := messageSetAVPsWithPath(answMsg,
 			splitIntoInterface(cfgFld.FieldId, utils.HIERARCHY_SEP),
-			fmtOut, cfgFld.Append, self.timezone); err != nil {
+			fmtOut, cfgFld.Append, timezone); err != nil {
 			return err
 		}
 		if cfgFld.BreakOnSuccess { // don't look for another field
@@ -715,3 +737,121 @@ func (self *CCA) SetProcessorAVPs(reqProcessor *config.DARequestProcessor, proce
 	}
 	return nil
 }
+
+func NewACRFromDiameterMessage(m *diam.Message, debitInterval time.Duration) (*ACR, error) {
+	var acr ACR
+	if err := m.Unmarshal(&acr); err != nil {
+		return nil, err
+	}
+	acr.diamMessage = m
+	acr.debitInterval = debitInterval
+	return &acr, nil
+}
+
+// Accounting Request, only the fields needed to route and answer it,
+// the rest is extracted via templates out of diamMessage
+type ACR struct {
+	SessionId              string `avp:"Session-Id"`
+	OriginHost             string `avp:"Origin-Host"`
+	OriginRealm            string `avp:"Origin-Realm"`
+	DestinationRealm       string `avp:"Destination-Realm"`
+	AccountingRecordType   int    `avp:"Accounting-Record-Type"`
+	AccountingRecordNumber int    `avp:"Accounting-Record-Number"`
+	AcctApplicationId      int    `avp:"Acct-Application-Id"`
+	diamMessage            *diam.Message
+	debitInterval          time.Duration
+}
+
+// Extracts data out of ACR into a SMGenericEvent based on the configured template
+func (self *ACR) AsSMGenericEvent(cfgFlds []*config.CfgCdrField) (sessionmanager.SMGenericEvent, error) {
+	return diamMessageAsSMGenericEvent(self.diamMessage, DIAMETER_ACR, cfgFlds, self.debitInterval)
+}
+
+func NewBareACAFromACR(acr *ACR, originHost, originRealm string) *ACA {
+	aca := &ACA{SessionId: acr.SessionId, AccountingRecordType: acr.AccountingRecordType,
+		AccountingRecordNumber: acr.AccountingRecordNumber, AcctApplicationId: acr.AcctApplicationId,
+		OriginHost: originHost, OriginRealm: originRealm, acrMessage: acr.diamMessage}
+	aca.diamMessage = diam.NewMessage(acr.diamMessage.Header.CommandCode, acr.diamMessage.Header.CommandFlags&^diam.RequestFlag,
+		acr.diamMessage.Header.ApplicationID, acr.diamMessage.Header.HopByHopID, acr.diamMessage.Header.EndToEndID,
+		acr.diamMessage.Dictionary())
+	aca.diamMessage = aca.AsBareDiameterMessage() // Add the required fields to the diameterMessage
+	return aca
+}
+
+// Accounting Answer, bare structure so we can dynamically manage adding it's fields
+type ACA struct {
+	SessionId              string `avp:"Session-Id"`
+	OriginHost             string `avp:"Origin-Host"`
+	OriginRealm            string `avp:"Origin-Realm"`
+	ResultCode             int    `avp:"Result-Code"`
+	AccountingRecordType   int    `avp:"Accounting-Record-Type"`
+	AccountingRecordNumber int    `avp:"Accounting-Record-Number"`
+	AcctApplicationId      int    `avp:"Acct-Application-Id"`
+	acrMessage             *diam.Message
+	diamMessage            *diam.Message
+	timezone               string
+}
+
+// AsBareDiameterMessage converts ACA into a bare DiameterMessage
+func (self *ACA) AsBareDiameterMessage() *diam.Message {
+	var m diam.Message
+	utils.Clone(self.diamMessage, &m)
+	m.NewAVP(avp.SessionID, avp.Mbit, 0, datatype.UTF8String(self.SessionId))
+	m.NewAVP(avp.OriginHost, avp.Mbit, 0, datatype.DiameterIdentity(self.OriginHost))
+	m.NewAVP(avp.OriginRealm, avp.Mbit, 0, datatype.DiameterIdentity(self.OriginRealm))
+	m.NewAVP(avp.AccountingRecordType, avp.Mbit, 0, datatype.Enumerated(self.AccountingRecordType))
+	m.NewAVP(avp.AccountingRecordNumber, avp.Mbit, 0, datatype.Unsigned32(self.AccountingRecordNumber))
+	if self.AcctApplicationId != 0 {
+		m.NewAVP(avp.AcctApplicationID, avp.Mbit, 0, datatype.Unsigned32(self.AcctApplicationId))
+	}
+	m.NewAVP(avp.ResultCode, avp.Mbit, 0, datatype.Unsigned32(self.ResultCode))
+	return &m
+}
+
+// AsDiameterMessage returns the diameter.Message which can be later written on network
+func (self *ACA) AsDiameterMessage() *diam.Message {
+	return self.diamMessage
+}
+
+// SetProcessorAVPs will add AVPs to self.diameterMessage based on template defined in processor.CCAFields
+func (self *ACA) SetProcessorAVPs(reqProcessor *config.DARequestProcessor, processorVars map[string]string) error {
+	return setAnswerProcessorAVPs(self.acrMessage, self.diamMessage, reqProcessor, processorVars, self.timezone)
+}
+
+// diamPeerSession keeps the data needed to reach back the peer which opened a session
+type diamPeerSession struct {
+	conn          diam.Conn
+	sessionID     string // Diameter Session-Id
+	originHost    string // Origin-Host of the peer, used as Destination-Host in server initiated requests
+	originRealm   string
+	applicationID uint32
+	accounting    bool // session opened with ACR, application is advertised in Acct-Application-Id
+}
+
+// newServerRequest builds the common part of a server initiated request towards the peer of the session
+func newServerRequest(cmdCode uint32, pSess *diamPeerSession, originHost, originRealm string) *diam.Message {
+	m := diam.NewRequest(cmdCode, pSess.applicationID, nil)
+	m.NewAVP(avp.SessionID, avp.Mbit, 0, datatype.UTF8String(pSess.sessionID))
+	m.NewAVP(avp.OriginHost, avp.Mbit, 0, datatype.DiameterIdentity(originHost))
+	m.NewAVP(avp.OriginRealm, avp.Mbit, 0, datatype.DiameterIdentity(originRealm))
+	m.NewAVP(avp.DestinationRealm, avp.Mbit, 0, datatype.DiameterIdentity(pSess.originRealm))
+	m.NewAVP(avp.DestinationHost, avp.Mbit, 0, datatype.DiameterIdentity(pSess.originHost))
+	appIDAVP := avp.AuthApplicationID
+	if pSess.accounting {
+		appIDAVP = avp.AcctApplicationID
+	}
+	m.NewAVP(appIDAVP, avp.Mbit, 0, datatype.Unsigned32(pSess.applicationID))
+	return m
+}
+
+// newASR builds the Abort-Session-Request for the session
+func newASR(pSess *diamPeerSession, originHost, originRealm string) *diam.Message {
+	return newServerRequest(diam.AbortSession, pSess, originHost, originRealm)
+}
+
+// newRAR builds the Re-Auth-Request for the session, asking for authorization only
+func newRAR(pSess *diamPeerSession, originHost, originRealm string) *diam.Message {
+	m := newServerRequest(diam.ReAuth, pSess, originHost, originRealm)
+	m.NewAVP(avp.ReAuthRequestType, avp.Mbit, 0, datatype.Enumerated(0)) // AUTHORIZE_ONLY
+	return m
+}
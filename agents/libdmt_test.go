@@ -512,3 +512,159 @@ func TestPassesFieldFilter(t *testing.T) {
 		t.Error("Does not pass")
 	}
 }
+
+func TestACRAsSMGenericEventAndACA(t *testing.T) {
+	m := diam.NewRequest(diam.Accounting, 3, nil)
+	m.NewAVP(avp.SessionID, avp.Mbit, 0, datatype.UTF8String("acrsess1"))
+	m.NewAVP(avp.OriginHost, avp.Mbit, 0, datatype.DiameterIdentity("client.example.org"))
+	m.NewAVP(avp.OriginRealm, avp.Mbit, 0, datatype.DiameterIdentity("example.org"))
+	m.NewAVP(avp.DestinationRealm, avp.Mbit, 0, datatype.DiameterIdentity("cgrates.org"))
+	m.NewAVP(avp.AccountingRecordType, avp.Mbit, 0, datatype.Enumerated(AcctRecordStart))
+	m.NewAVP(avp.AccountingRecordNumber, avp.Mbit, 0, datatype.Unsigned32(0))
+	m.NewAVP(avp.AcctApplicationID, avp.Mbit, 0, datatype.Unsigned32(3))
+	acr, err := NewACRFromDiameterMessage(m, time.Duration(300)*time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if acr.SessionId != "acrsess1" || acr.OriginHost != "client.example.org" ||
+		acr.AccountingRecordType != AcctRecordStart || acr.AcctApplicationId != 3 {
+		t.Errorf("Unexpected ACR: %+v", acr)
+	}
+	cfgFlds := []*config.CfgCdrField{
+		&config.CfgCdrField{Tag: "OriginID", Type: utils.META_COMPOSED, FieldId: utils.ACCID,
+			Value: utils.ParseRSRFieldsMustCompile("Session-Id", utils.INFIELD_SEP), Mandatory: true},
+	}
+	eSMGEv := sessionmanager.SMGenericEvent{"EventName": DIAMETER_ACR, utils.ACCID: "acrsess1"}
+	if rSMGEv, err := acr.AsSMGenericEvent(cfgFlds); err != nil {
+		t.Error(err)
+	} else if !reflect.DeepEqual(eSMGEv, rSMGEv) {
+		t.Errorf("Expecting: %+v, received: %+v", eSMGEv, rSMGEv)
+	}
+	aca := NewBareACAFromACR(acr, "CGR-DA", "cgrates.org")
+	acaMsg := aca.AsDiameterMessage()
+	if acaMsg.Header.CommandCode != diam.Accounting || acaMsg.Header.CommandFlags&diam.RequestFlag != 0 ||
+		acaMsg.Header.HopByHopID != m.Header.HopByHopID {
+		t.Errorf("Unexpected ACA header: %+v", acaMsg.Header)
+	}
+	if avps, err := acaMsg.FindAVPsWithPath([]interface{}{"Accounting-Record-Type"}, dict.UndefinedVendorID); err != nil {
+		t.Error(err)
+	} else if len(avps) != 1 || avpValAsString(avps[0]) != "2" {
+		t.Errorf("Unexpected Accounting-Record-Type: %+v", avps)
+	}
+	if avps, err := acaMsg.FindAVPsWithPath([]interface{}{"Session-Id"}, dict.UndefinedVendorID); err != nil {
+		t.Error(err)
+	} else if len(avps) != 1 || avpValAsString(avps[0]) != "acrsess1" {
+		t.Errorf("Unexpected Session-Id: %+v", avps)
+	}
+}
+
+func TestNewASRAndRAR(t *testing.T) {
+	pSess := &diamPeerSession{sessionID: "asrsess1", originHost: "client.example.org",
+		originRealm: "example.org", applicationID: 4}
+	asr := newASR(pSess, "CGR-DA", "cgrates.org")
+	if asr.Header.CommandCode != diam.AbortSession || asr.Header.CommandFlags&diam.RequestFlag == 0 ||
+		asr.Header.ApplicationID != 4 {
+		t.Errorf("Unexpected ASR header: %+v", asr.Header)
+	}
+	for avpName, eVal := range map[string]string{
+		"Session-Id":          "asrsess1",
+		"Origin-Host":         "CGR-DA",
+		"Destination-Host":    "client.example.org",
+		"Destination-Realm":   "example.org",
+		"Auth-Application-Id": "4"} {
+		if avps, err := asr.FindAVPsWithPath([]interface{}{avpName}, dict.UndefinedVendorID); err != nil {
+			t.Error(err)
+		} else if len(avps) != 1 || avpValAsString(avps[0]) != eVal {
+			t.Errorf("Unexpected %s: %+v", avpName, avps)
+		}
+	}
+	rar := newRAR(pSess, "CGR-DA", "cgrates.org")
+	if rar.Header.CommandCode != diam.ReAuth {
+		t.Errorf("Unexpected RAR header: %+v", rar.Header)
+	}
+	if avps, err := rar.FindAVPsWithPath([]interface{}{"Re-Auth-Request-Type"}, dict.UndefinedVendorID); err != nil {
+		t.Error(err)
+	} else if len(avps) != 1 || avpValAsString(avps[0]) != "0" {
+		t.Errorf("Unexpected Re-Auth-Request-Type: %+v", avps)
+	}
+}
+
+func TestNewASRAccounting(t *testing.T) {
+	pSess := &diamPeerSession{sessionID: "asrsess2", originHost: "client.example.org",
+		originRealm: "example.org", applicationID: 3, accounting: true}
+	asr := newASR(pSess, "CGR-DA", "cgrates.org")
+	if avps, err := asr.FindAVPsWithPath([]interface{}{"Acct-Application-Id"}, dict.UndefinedVendorID); err != nil {
+		t.Error(err)
+	} else if len(avps) != 1 || avpValAsString(avps[0]) != "3" {
+		t.Errorf("Unexpected Acct-Application-Id: %+v", avps)
+	}
+	if avps, err := asr.FindAVPsWithPath([]interface{}{"Auth-Application-Id"}, dict.UndefinedVendorID); err != nil {
+		t.Error(err)
+	} else if len(avps) != 0 {
+		t.Errorf("Unexpected Auth-Application-Id: %+v", avps)
+	}
+}
+
+// testDiamConn records the messages written towards the peer
+type testDiamConn struct {
+	diam.Conn
+	written bytes.Buffer
+	closed  chan struct{}
+}
+
+func (c *testDiamConn) Write(b []byte) (int, error) {
+	return c.written.Write(b)
+}
+
+func (c *testDiamConn) CloseNotify() <-chan struct{} {
+	return c.closed
+}
+
+func TestDiameterAgentPeerSessions(t *testing.T) {
+	cfg, _ := config.NewDefaultCGRConfig()
+	cfg.DiameterAgentCfg().DictionariesDir = "" // no extra dictionaries needed
+	da, err := NewDiameterAgent(cfg, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn := &testDiamConn{closed: make(chan struct{})}
+	da.trackPeerSession("sess1", &diamPeerSession{conn: conn, sessionID: "diamsess1",
+		originHost: "client.example.org", originRealm: "example.org", applicationID: 4})
+	evStart := map[string]interface{}{utils.ACCID: "sess1"}
+	var reply string
+	if err := da.Call("SMGClientV1.DisconnectSession",
+		utils.AttrDisconnectSession{EventStart: evStart, Reason: "FORCED"}, &reply); err != nil {
+		t.Error(err)
+	} else if reply != utils.OK {
+		t.Errorf("Received reply: %s", reply)
+	}
+	if m, err := diam.ReadMessage(bytes.NewReader(conn.written.Bytes()), dict.Default); err != nil {
+		t.Error(err)
+	} else if m.Header.CommandCode != diam.AbortSession {
+		t.Errorf("Unexpected message written: %s", m)
+	}
+	conn.written.Reset()
+	if err := da.Call("SMGClientV1.ReAuthSession",
+		utils.AttrReAuthSession{EventStart: evStart}, &reply); err != nil {
+		t.Error(err)
+	}
+	if m, err := diam.ReadMessage(bytes.NewReader(conn.written.Bytes()), dict.Default); err != nil {
+		t.Error(err)
+	} else if m.Header.CommandCode != diam.ReAuth {
+		t.Errorf("Unexpected message written: %s", m)
+	}
+	if err := da.Call("SMGClientV1.DisconnectSession",
+		utils.AttrDisconnectSession{EventStart: map[string]interface{}{utils.ACCID: "unknown"}}, &reply); err != utils.ErrNotFound {
+		t.Errorf("Expecting ErrNotFound, received: %v", err)
+	}
+	close(conn.closed) // peer went away, sessions should be cleaned
+	for i := 0; i < 100; i++ {
+		if _, has := da.peerSession("sess1"); !has {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if _, has := da.peerSession("sess1"); has {
+		t.Error("Session not removed on connection close")
+	}
+}
@@ -0,0 +1,233 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package agents
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/cgrates/cgrates/config"
+	"github.com/cgrates/cgrates/sessionmanager"
+	"github.com/cgrates/cgrates/utils"
+)
+
+// httpRequestVars extracts the variables out of HTTP request based on the payload encoding
+// nested JSON fields are reachable with their path, eg: sms>from
+func httpRequestVars(req *http.Request, payload string) (reqVars map[string]string, err error) {
+	reqVars = make(map[string]string)
+	switch payload {
+	case utils.MetaUrl:
+		if err = req.ParseForm(); err != nil {
+			return nil, err
+		}
+		for fldName, vals := range req.Form {
+			reqVars[fldName] = strings.Join(vals, utils.INFIELD_SEP)
+		}
+	case utils.MetaJSON:
+		var body interface{}
+		if err = json.NewDecoder(req.Body).Decode(&body); err != nil {
+			return nil, err
+		}
+		flattenJSONValue("", body, reqVars)
+	default:
+		return nil, fmt.Errorf("unsupported request payload: <%s>", payload)
+	}
+	return
+}
+
+// flattenJSONValue populates out with the leaf values of a decoded JSON, indexed on their path
+func flattenJSONValue(path string, val interface{}, out map[string]string) {
+	switch v := val.(type) {
+	case map[string]interface{}:
+		for fldName, fldVal := range v {
+			flattenJSONValue(httpVarPath(path, fldName), fldVal, out)
+		}
+	case []interface{}:
+		for idx, itm := range v {
+			flattenJSONValue(httpVarPath(path, strconv.Itoa(idx)), itm, out)
+		}
+	case nil:
+		out[path] = ""
+	default:
+		if strVal, canCast := utils.CastFieldIfToString(v); canCast {
+			out[path] = strVal
+		}
+	}
+}
+
+func httpVarPath(path, fldName string) string {
+	if path == "" {
+		return fldName
+	}
+	return path + utils.HIERARCHY_SEP + fldName
+}
+
+// httpVarValue returns the value of a variable, processorVars have priority over the request ones
+func httpVarValue(reqVars, processorVars map[string]string, varName string) (val string, has bool) {
+	if val, has = processorVars[varName]; has {
+		return
+	}
+	val, has = reqVars[varName]
+	return
+}
+
+// httpPassesFieldFilter checks whether fieldFilter matches either in processorVars or the request variables
+func httpPassesFieldFilter(reqVars, processorVars map[string]string, fieldFilter *utils.RSRField) bool {
+	if fieldFilter == nil {
+		return true
+	}
+	val, has := httpVarValue(reqVars, processorVars, fieldFilter.Id)
+	if !has { // no variable found, filter not passing
+		return false
+	}
+	return fieldFilter.FilterPasses(val)
+}
+
+// httpComposedFieldValue extracts the field value out of the request variables
+func httpComposedFieldValue(reqVars, processorVars map[string]string, outTpl utils.RSRFields) (outVal string) {
+	for _, rsrTpl := range outTpl {
+		if rsrTpl.IsStatic() {
+			outVal += rsrTpl.ParseValue("")
+			continue
+		}
+		if val, has := httpVarValue(reqVars, processorVars, rsrTpl.Id); has {
+			outVal += rsrTpl.ParseValue(val)
+		}
+	}
+	return
+}
+
+// httpFieldOutVal formats the field value retrieved from the request variables
+func httpFieldOutVal(reqVars, processorVars map[string]string,
+	cfgFld *config.CfgCdrField) (outVal string, err error) {
+	switch cfgFld.Type {
+	case utils.META_FILLER:
+		outVal = cfgFld.Value.Id()
+		cfgFld.Padding = "right"
+	case utils.META_CONSTANT:
+		outVal = cfgFld.Value.Id()
+	case utils.META_COMPOSED:
+		outVal = httpComposedFieldValue(reqVars, processorVars, cfgFld.Value)
+	default:
+		return "", fmt.Errorf("unsupported configuration field type: <%s>", cfgFld.Type)
+	}
+	return utils.FmtFieldWidth(cfgFld.Tag, outVal, cfgFld.Width, cfgFld.Strip, cfgFld.Padding, cfgFld.Mandatory)
+}
+
+// httpFieldsPassing returns the values of the template fields passing their filters, in template order
+func httpFieldsPassing(reqVars, processorVars map[string]string,
+	cfgFlds []*config.CfgCdrField) (flds []*httpField, err error) {
+	for _, cfgFld := range cfgFlds {
+		passedAllFilters := true
+		for _, fldFilter := range cfgFld.FieldFilter {
+			if !httpPassesFieldFilter(reqVars, processorVars, fldFilter) {
+				passedAllFilters = false
+				break
+			}
+		}
+		if !passedAllFilters {
+			continue
+		}
+		fmtOut, err := httpFieldOutVal(reqVars, processorVars, cfgFld)
+		if err != nil {
+			return nil, err
+		}
+		flds = append(flds, &httpField{ID: cfgFld.FieldId, Value: fmtOut, Append: cfgFld.Append})
+		if cfgFld.BreakOnSuccess {
+			break
+		}
+	}
+	return
+}
+
+// httpField is one field out of templates
+type httpField struct {
+	ID     string
+	Value  string
+	Append bool
+}
+
+// httpReqAsSMGEvent converts the request variables into SMGEvent
+func httpReqAsSMGEvent(reqVars, processorVars map[string]string, procFlags utils.StringMap,
+	cfgFlds []*config.CfgCdrField) (smgEv sessionmanager.SMGenericEvent, err error) {
+	flds, err := httpFieldsPassing(reqVars, processorVars, cfgFlds)
+	if err != nil {
+		return nil, err
+	}
+	outMap := make(map[string]string) // work with it so we can append values to keys
+	outMap[utils.EVENT_NAME] = EvHttpReq
+	for _, fld := range flds {
+		if _, hasKey := outMap[fld.ID]; hasKey && fld.Append {
+			outMap[fld.ID] += fld.Value
+		} else {
+			outMap[fld.ID] = fld.Value
+		}
+	}
+	if len(procFlags) != 0 {
+		outMap[utils.CGRFlags] = procFlags.String()
+	}
+	return sessionmanager.SMGenericEvent(utils.ConvertMapValStrIf(outMap)), nil
+}
+
+// httpReply gathers the reply fields out of processors
+type httpReply []*httpField
+
+// encode writes the reply in the configured payload encoding
+// on *json the field ID is a path into the reply object, eg: sms>status
+func (rpl httpReply) encode(payload string) (body []byte, contentType string, err error) {
+	switch payload {
+	case utils.MetaUrl:
+		vals := make(url.Values)
+		for _, fld := range rpl {
+			if fld.Append {
+				vals.Add(fld.ID, fld.Value)
+			} else {
+				vals.Set(fld.ID, fld.Value)
+			}
+		}
+		return []byte(vals.Encode()), "application/x-www-form-urlencoded", nil
+	case utils.MetaJSON:
+		out := make(map[string]interface{})
+		for _, fld := range rpl {
+			path := strings.Split(fld.ID, utils.HIERARCHY_SEP)
+			obj := out
+			for _, key := range path[:len(path)-1] {
+				nested, isMap := obj[key].(map[string]interface{})
+				if !isMap {
+					nested = make(map[string]interface{})
+					obj[key] = nested
+				}
+				obj = nested
+			}
+			lastKey := path[len(path)-1]
+			if prev, has := obj[lastKey].(string); has && fld.Append {
+				obj[lastKey] = prev + fld.Value
+			} else {
+				obj[lastKey] = fld.Value
+			}
+		}
+		body, err = json.Marshal(out)
+		return body, "application/json", err
+	}
+	return nil, "", fmt.Errorf("unsupported reply payload: <%s>", payload)
+}
@@ -0,0 +1,165 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package agents
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cgrates/cgrates/config"
+	"github.com/cgrates/cgrates/sessionmanager"
+	"github.com/cgrates/cgrates/utils"
+)
+
+func TestHttpRequestVars(t *testing.T) {
+	req := httptest.NewRequest("POST", "/http_agent?type=sms",
+		strings.NewReader("from=1001&to=1002&text=hello"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	eVars := map[string]string{"type": "sms", "from": "1001", "to": "1002", "text": "hello"}
+	if reqVars, err := httpRequestVars(req, utils.MetaUrl); err != nil {
+		t.Error(err)
+	} else if !reflect.DeepEqual(eVars, reqVars) {
+		t.Errorf("Expecting: %+v, received: %+v", eVars, reqVars)
+	}
+	req = httptest.NewRequest("POST", "/http_agent",
+		strings.NewReader(`{"sms":{"from":"1001","to":"1002","parts":2,"flash":false},"tags":["a","b"],"extra":null}`))
+	eVars = map[string]string{"sms>from": "1001", "sms>to": "1002", "sms>parts": "2", "sms>flash": "false",
+		"tags>0": "a", "tags>1": "b", "extra": ""}
+	if reqVars, err := httpRequestVars(req, utils.MetaJSON); err != nil {
+		t.Error(err)
+	} else if !reflect.DeepEqual(eVars, reqVars) {
+		t.Errorf("Expecting: %+v, received: %+v", eVars, reqVars)
+	}
+	req = httptest.NewRequest("POST", "/http_agent", strings.NewReader(`{"sms":`))
+	if _, err := httpRequestVars(req, utils.MetaJSON); err == nil {
+		t.Error("Expecting error on malformed JSON")
+	}
+}
+
+func TestHttpReqAsSMGEvent(t *testing.T) {
+	reqVars := map[string]string{"sms>from": "1001", "sms>to": "1002", "type": "sms"}
+	procVars := map[string]string{"type": "overwritten"}
+	cfgFlds := []*config.CfgCdrField{
+		&config.CfgCdrField{Tag: "TOR", FieldId: utils.TOR, Type: utils.META_CONSTANT,
+			Value: utils.ParseRSRFieldsMustCompile("^*sms", utils.INFIELD_SEP)},
+		&config.CfgCdrField{Tag: "Account", FieldId: utils.Account, Type: utils.META_COMPOSED,
+			Value: utils.ParseRSRFieldsMustCompile("sms>from", utils.INFIELD_SEP), Mandatory: true},
+		&config.CfgCdrField{Tag: "Destination", FieldId: utils.Destination, Type: utils.META_COMPOSED,
+			Value: utils.ParseRSRFieldsMustCompile("^+49;sms>to", utils.INFIELD_SEP)},
+		&config.CfgCdrField{Tag: "Type", FieldId: "Type", Type: utils.META_COMPOSED,
+			Value: utils.ParseRSRFieldsMustCompile("type", utils.INFIELD_SEP)},
+		&config.CfgCdrField{Tag: "NotPassing", FieldId: "NotPassing", Type: utils.META_CONSTANT,
+			FieldFilter: utils.ParseRSRFieldsMustCompile("sms>to(1003)", utils.INFIELD_SEP),
+			Value:       utils.ParseRSRFieldsMustCompile("^x", utils.INFIELD_SEP)},
+	}
+	eSMGEv := sessionmanager.SMGenericEvent{
+		utils.EVENT_NAME:  EvHttpReq,
+		utils.TOR:         "*sms",
+		utils.Account:     "1001",
+		utils.Destination: "+491002",
+		"Type":            "overwritten",
+		utils.CGRFlags:    "*event",
+	}
+	if smgEv, err := httpReqAsSMGEvent(reqVars, procVars, utils.StringMap{utils.MetaEvent: true}, cfgFlds); err != nil {
+		t.Error(err)
+	} else if !reflect.DeepEqual(eSMGEv, smgEv) {
+		t.Errorf("Expecting: %+v, received: %+v", eSMGEv, smgEv)
+	}
+	cfgFlds[1].Value = utils.ParseRSRFieldsMustCompile("missing", utils.INFIELD_SEP)
+	if _, err := httpReqAsSMGEvent(reqVars, procVars, nil, cfgFlds); err == nil {
+		t.Error("Expecting error on missing mandatory field")
+	}
+}
+
+func TestHttpReplyEncode(t *testing.T) {
+	rpl := httpReply{
+		&httpField{ID: "status", Value: "OK"},
+		&httpField{ID: "sms>max_usage", Value: "1"},
+		&httpField{ID: "sms>id", Value: "abc"},
+		&httpField{ID: "sms>id", Value: "def", Append: true},
+	}
+	if body, contentType, err := rpl.encode(utils.MetaJSON); err != nil {
+		t.Error(err)
+	} else if contentType != "application/json" {
+		t.Errorf("Unexpected content type: %s", contentType)
+	} else if eBody := `{"sms":{"id":"abcdef","max_usage":"1"},"status":"OK"}`; string(body) != eBody {
+		t.Errorf("Expecting: %s, received: %s", eBody, string(body))
+	}
+	if body, _, err := rpl.encode(utils.MetaUrl); err != nil {
+		t.Error(err)
+	} else if eBody := "sms%3Eid=abc&sms%3Eid=def&sms%3Emax_usage=1&status=OK"; string(body) != eBody {
+		t.Errorf("Expecting: %s, received: %s", eBody, string(body))
+	}
+}
+
+// testHttpAgentSMG records the calls towards SMG
+type testHttpAgentSMG struct {
+	calls []string
+}
+
+func (smg *testHttpAgentSMG) Call(serviceMethod string, args interface{}, reply interface{}) error {
+	smg.calls = append(smg.calls, serviceMethod)
+	switch rpl := reply.(type) {
+	case *time.Duration:
+		*rpl = time.Duration(1)
+	case *string:
+		*rpl = utils.OK
+	}
+	return nil
+}
+
+func TestHttpAgentServeHTTP(t *testing.T) {
+	cfg, _ := config.NewDefaultCGRConfig()
+	cfg.HttpAgentCfg().RequestProcessors = []*config.HttpAgntProcCfg{
+		&config.HttpAgntProcCfg{
+			Id:            "SMS",
+			RequestFilter: utils.ParseRSRFieldsMustCompile("type(sms)", utils.INFIELD_SEP),
+			Flags:         utils.StringMap{utils.MetaEvent: true, utils.MetaCDR: true},
+			RequestFields: []*config.CfgCdrField{
+				&config.CfgCdrField{Tag: "Account", FieldId: utils.Account, Type: utils.META_COMPOSED,
+					Value: utils.ParseRSRFieldsMustCompile("from", utils.INFIELD_SEP), Mandatory: true},
+			},
+			ReplyFields: []*config.CfgCdrField{
+				&config.CfgCdrField{Tag: "MaxUsage", FieldId: "max_usage", Type: utils.META_COMPOSED,
+					Value: utils.ParseRSRFieldsMustCompile(MetaCGRMaxUsage, utils.INFIELD_SEP)},
+			},
+		},
+	}
+	smg := new(testHttpAgentSMG)
+	ha := NewHttpAgent(cfg, smg)
+	w := httptest.NewRecorder()
+	ha.ServeHTTP(w, httptest.NewRequest("GET", "/http_agent?type=sms&from=1001", nil))
+	if w.Code != http.StatusOK {
+		t.Errorf("Unexpected status: %d, body: %s", w.Code, w.Body.String())
+	} else if eBody := `{"max_usage":"1"}`; w.Body.String() != eBody {
+		t.Errorf("Expecting: %s, received: %s", eBody, w.Body.String())
+	}
+	if eCalls := []string{"SMGenericV2.ChargeEvent", "SMGenericV1.ProcessCDR"}; !reflect.DeepEqual(eCalls, smg.calls) {
+		t.Errorf("Expecting: %+v, received: %+v", eCalls, smg.calls)
+	}
+	w = httptest.NewRecorder()
+	ha.ServeHTTP(w, httptest.NewRequest("GET", "/http_agent?type=mms&from=1001", nil))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Unexpected status: %d", w.Code)
+	}
+}
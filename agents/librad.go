@@ -201,3 +201,14 @@ func radReplyAppendAttributes(reply *radigo.Packet, procVars map[string]string,
 	}
 	return
 }
+
+// radSessionAppendAttributes appends attributes to a dynamic authorization request based on session data
+// session fields are used as processor variables so the template can reference them directly
+func radSessionAppendAttributes(req *radigo.Packet, sessionData map[string]interface{},
+	cfgFlds []*config.CfgCdrField) (err error) {
+	sessionVars, err := sessionmanager.SMGenericEvent(sessionData).AsMapStringString()
+	if err != nil {
+		return
+	}
+	return radReplyAppendAttributes(req, sessionVars, cfgFlds)
+}
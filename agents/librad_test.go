@@ -23,6 +23,7 @@ import (
 	"reflect"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/cgrates/cgrates/config"
 	"github.com/cgrates/cgrates/sessionmanager"
@@ -278,3 +279,81 @@ func TestRadReplyAppendAttributes(t *testing.T) {
 		t.Errorf("Expecting: 30, received: %s", avps[0].GetStringValue())
 	}
 }
+
+func TestRadSessionAppendAttributes(t *testing.T) {
+	req := radigo.NewPacket(RadCoARequest, 1, dictRad, coder, "CGRateS.org")
+	sessionData := map[string]interface{}{
+		utils.ACCID:  "sess1",
+		utils.TOR:    utils.DATA,
+		"RateLimit":  "10M/10M",
+		"Extra":      5,
+		"NotInTempl": "x",
+	}
+	cfgFlds := []*config.CfgCdrField{
+		&config.CfgCdrField{Tag: "SessionID", FieldId: "Acct-Session-Id", Type: utils.META_COMPOSED,
+			Value: utils.ParseRSRFieldsMustCompile(utils.ACCID, utils.INFIELD_SEP)},
+		&config.CfgCdrField{Tag: "RateLimit", FieldId: "Cisco/Cisco-AVPair", Type: utils.META_COMPOSED,
+			FieldFilter: utils.ParseRSRFieldsMustCompile(utils.TOR+"(*data)", utils.INFIELD_SEP),
+			Value:       utils.ParseRSRFieldsMustCompile("^rate-limit=;RateLimit", utils.INFIELD_SEP)},
+		&config.CfgCdrField{Tag: "VoiceOnly", FieldId: "Filter-Id", Type: utils.META_CONSTANT,
+			FieldFilter: utils.ParseRSRFieldsMustCompile(utils.TOR+"(*voice)", utils.INFIELD_SEP),
+			Value:       utils.ParseRSRFieldsMustCompile("^voice", utils.INFIELD_SEP)},
+	}
+	if err := radSessionAppendAttributes(req, sessionData, cfgFlds); err != nil {
+		t.Fatal(err)
+	}
+	if avps := req.AttributesWithName("Acct-Session-Id", ""); len(avps) != 1 ||
+		avps[0].GetStringValue() != "sess1" {
+		t.Errorf("Unexpected Acct-Session-Id: %s", utils.ToJSON(avps))
+	}
+	if avps := req.AttributesWithName("Cisco-AVPair", "Cisco"); len(avps) != 1 ||
+		avps[0].GetStringValue() != "rate-limit=10M/10M" {
+		t.Errorf("Unexpected Cisco-AVPair: %s", utils.ToJSON(avps))
+	}
+	if avps := req.AttributesWithName("Filter-Id", ""); len(avps) != 0 {
+		t.Errorf("Unexpected Filter-Id: %s", utils.ToJSON(avps))
+	}
+}
+
+func TestRadiusAgentDynAuthUnknownSession(t *testing.T) {
+	cfg, _ := config.NewDefaultCGRConfig()
+	ra := &RadiusAgent{cgrCfg: cfg, dynAuthClnts: make(map[string]*radigo.Client),
+		dynAuthSessions: make(map[string]*dynAuthSession)}
+	var reply string
+	if err := ra.Call("SMGClientV1.DisconnectSession",
+		utils.AttrDisconnectSession{EventStart: map[string]interface{}{utils.ACCID: "unknown"}}, &reply); err != utils.ErrNotFound {
+		t.Errorf("Expecting ErrNotFound, received: %v", err)
+	}
+	if err := ra.Call("SMGClientV1.ReAuthSession",
+		utils.AttrReAuthSession{EventStart: map[string]interface{}{utils.ACCID: "unknown"}}, &reply); err != utils.ErrNotFound {
+		t.Errorf("Expecting ErrNotFound, received: %v", err)
+	}
+}
+
+func TestRadiusAgentDynAuthSessionTTL(t *testing.T) {
+	cfg, _ := config.NewDefaultCGRConfig()
+	cfg.RadiusAgentCfg().DynAuthSessionTTL = 10 * time.Millisecond
+	ra := &RadiusAgent{cgrCfg: cfg, dynAuthClnts: make(map[string]*radigo.Client),
+		dynAuthSessions: make(map[string]*dynAuthSession)}
+	reqProcessor := &config.RARequestProcessor{Id: "acct", DynAuthAddress: "127.0.0.1:3799"}
+	ra.setDynAuthSession("sess1", reqProcessor)
+	ra.setDynAuthSession("sess2", reqProcessor)
+	ra.remDynAuthSession("sess2", nil)
+	time.Sleep(5 * time.Millisecond)
+	ra.refreshDynAuthSession("sess1")
+	time.Sleep(7 * time.Millisecond)
+	ra.dynAuthMux.Lock()
+	if _, has := ra.dynAuthSessions["sess1"]; !has {
+		t.Error("session should be refreshed")
+	}
+	if _, has := ra.dynAuthSessions["sess2"]; has {
+		t.Error("session should be removed")
+	}
+	ra.dynAuthMux.Unlock()
+	time.Sleep(20 * time.Millisecond)
+	ra.dynAuthMux.Lock()
+	if len(ra.dynAuthSessions) != 0 {
+		t.Errorf("sessions should expire: %+v", ra.dynAuthSessions)
+	}
+	ra.dynAuthMux.Unlock()
+}
@@ -20,10 +20,15 @@ package agents
 
 import (
 	"fmt"
+	"net"
+	"reflect"
 	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/cgrates/cgrates/config"
+	"github.com/cgrates/cgrates/sessionmanager"
 	"github.com/cgrates/cgrates/utils"
 	"github.com/cgrates/radigo"
 	"github.com/cgrates/rpcclient"
@@ -44,6 +49,16 @@ const (
 	MetaUsageDifference = "*usage_difference"
 )
 
+// Dynamic authorization packet codes, RFC 5176
+const (
+	RadDisconnectRequest radigo.PacketCode = 40
+	RadDisconnectACK     radigo.PacketCode = 41
+	RadDisconnectNAK     radigo.PacketCode = 42
+	RadCoARequest        radigo.PacketCode = 43
+	RadCoAACK            radigo.PacketCode = 44
+	RadCoANAK            radigo.PacketCode = 45
+)
+
 func NewRadiusAgent(cgrCfg *config.CGRConfig, smg rpcclient.RpcClientConnection) (ra *RadiusAgent, err error) {
 	dts := make(map[string]*radigo.Dictionary, len(cgrCfg.RadiusAgentCfg().ClientDictionaries))
 	for clntID, dictPath := range cgrCfg.RadiusAgentCfg().ClientDictionaries {
@@ -54,7 +69,12 @@ func NewRadiusAgent(cgrCfg *config.CGRConfig, smg rpcclient.RpcClientConnection)
 		}
 	}
 	dicts := radigo.NewDictionaries(dts)
-	ra = &RadiusAgent{cgrCfg: cgrCfg, smg: smg}
+	ra = &RadiusAgent{cgrCfg: cgrCfg, smg: smg, dicts: dts,
+		dynAuthClnts:    make(map[string]*radigo.Client),
+		dynAuthSessions: make(map[string]*dynAuthSession)}
+	if biClnt, isBiRPC := smg.(*utils.BiRPCInternalClient); isBiRPC {
+		biClnt.SetClientConn(ra) // pass the connection to RA back into smg so we can receive the disconnects and re-auths
+	}
 	secrets := radigo.NewSecrets(cgrCfg.RadiusAgentCfg().ClientSecrets)
 	ra.rsAuth = radigo.NewServer(cgrCfg.RadiusAgentCfg().ListenNet,
 		cgrCfg.RadiusAgentCfg().ListenAuth, secrets, dicts,
@@ -73,6 +93,18 @@ type RadiusAgent struct {
 	smg    rpcclient.RpcClientConnection // Connection towards CGR-SMG component
 	rsAuth *radigo.Server
 	rsAcct *radigo.Server
+	dicts  map[string]*radigo.Dictionary // client dictionaries, also used towards NAS on dynamic authorization
+
+	dynAuthClnts    map[string]*radigo.Client  // CoA/DM clients, indexed on NAS address
+	dynAuthSessions map[string]*dynAuthSession // sessions reachable over CoA/DM, indexed on OriginID
+	dynAuthReqID    uint8                      // identifier of the last dynamic authorization request
+	dynAuthMux      sync.Mutex                 // protects the dynamic authorization data
+}
+
+// dynAuthSession keeps the processor which started a session reachable over CoA/DM
+type dynAuthSession struct {
+	reqProcessor *config.RARequestProcessor
+	ttlTimer     *time.Timer // removes the session when Accounting-Stop is not received
 }
 
 // handleAuth handles RADIUS Authorization request
@@ -194,12 +226,19 @@ func (ra *RadiusAgent) processRequest(reqProcessor *config.RARequestProcessor,
 		case MetaRadAcctStart:
 			err = ra.smg.Call("SMGenericV2.InitiateSession", smgEv, &maxUsage)
 			cgrReply = maxUsage
+			if err == nil && ra.dynAuthAddress(reqProcessor) != "" {
+				ra.setDynAuthSession(smgEv.GetOriginID(utils.META_DEFAULT), reqProcessor)
+			}
 		case MetaRadAcctUpdate:
 			err = ra.smg.Call("SMGenericV2.UpdateSession", smgEv, &maxUsage)
 			cgrReply = maxUsage
+			if err == nil {
+				ra.refreshDynAuthSession(smgEv.GetOriginID(utils.META_DEFAULT))
+			}
 		case MetaRadAcctStop:
 			var rpl string
 			err = ra.smg.Call("SMGenericV1.TerminateSession", smgEv, &rpl)
+			ra.remDynAuthSession(smgEv.GetOriginID(utils.META_DEFAULT), nil)
 			cgrReply = rpl
 			if ra.cgrCfg.RadiusAgentCfg().CreateCDR {
 				if errCdr := ra.smg.Call("SMGenericV1.ProcessCDR", smgEv, &rpl); errCdr != nil {
@@ -227,6 +266,175 @@ func (ra *RadiusAgent) processRequest(reqProcessor *config.RARequestProcessor,
 	return true, nil
 }
 
+// dynAuthAddress returns the NAS address for the sessions started by reqProcessor
+func (ra *RadiusAgent) dynAuthAddress(reqProcessor *config.RARequestProcessor) string {
+	if reqProcessor.DynAuthAddress != "" {
+		return reqProcessor.DynAuthAddress
+	}
+	return ra.cgrCfg.RadiusAgentCfg().DynAuthAddress
+}
+
+// setDynAuthSession makes the session reachable over CoA/DM, (re)starting its TTL
+func (ra *RadiusAgent) setDynAuthSession(originID string, reqProcessor *config.RARequestProcessor) {
+	ra.dynAuthMux.Lock()
+	defer ra.dynAuthMux.Unlock()
+	if dynSess, has := ra.dynAuthSessions[originID]; has && dynSess.ttlTimer != nil {
+		dynSess.ttlTimer.Stop()
+	}
+	dynSess := &dynAuthSession{reqProcessor: reqProcessor}
+	if ttl := ra.cgrCfg.RadiusAgentCfg().DynAuthSessionTTL; ttl > 0 {
+		dynSess.ttlTimer = time.AfterFunc(ttl, func() { ra.remDynAuthSession(originID, dynSess) })
+	}
+	ra.dynAuthSessions[originID] = dynSess
+}
+
+// refreshDynAuthSession restarts the TTL of the session, on Interim-Update
+func (ra *RadiusAgent) refreshDynAuthSession(originID string) {
+	ra.dynAuthMux.Lock()
+	defer ra.dynAuthMux.Unlock()
+	if dynSess, has := ra.dynAuthSessions[originID]; has && dynSess.ttlTimer != nil {
+		dynSess.ttlTimer.Reset(ra.cgrCfg.RadiusAgentCfg().DynAuthSessionTTL)
+	}
+}
+
+// remDynAuthSession removes the session indexed on originID
+// with dynSess not nil, the session is only removed if it was not replaced meanwhile
+func (ra *RadiusAgent) remDynAuthSession(originID string, dynSess *dynAuthSession) {
+	ra.dynAuthMux.Lock()
+	defer ra.dynAuthMux.Unlock()
+	crntSess, has := ra.dynAuthSessions[originID]
+	if !has || (dynSess != nil && crntSess != dynSess) {
+		return
+	}
+	if crntSess.ttlTimer != nil {
+		crntSess.ttlTimer.Stop()
+	}
+	delete(ra.dynAuthSessions, originID)
+}
+
+// dynAuthClient returns the CoA/DM client towards NAS address, connecting it on first use
+// secret and dictionary are the ones configured for the NAS host or the *default ones
+func (ra *RadiusAgent) dynAuthClient(address string) (clnt *radigo.Client, err error) {
+	if clnt, has := ra.dynAuthClnts[address]; has {
+		return clnt, nil
+	}
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return nil, err
+	}
+	secret, has := ra.cgrCfg.RadiusAgentCfg().ClientSecrets[host]
+	if !has {
+		secret = ra.cgrCfg.RadiusAgentCfg().ClientSecrets[utils.META_DEFAULT]
+	}
+	dict, has := ra.dicts[host]
+	if !has {
+		dict = ra.dicts[utils.META_DEFAULT]
+	}
+	if clnt, err = radigo.NewClient("udp", address, secret, dict,
+		ra.cgrCfg.ConnectAttempts, nil); err != nil {
+		return nil, err
+	}
+	ra.dynAuthClnts[address] = clnt
+	return
+}
+
+// sendDynAuthRequest sends a CoA or Disconnect-Message request towards the NAS which started the session
+// the request attributes are built out of session data based on cfgFlds of the session processor
+func (ra *RadiusAgent) sendDynAuthRequest(sessionData map[string]interface{}, reqCode, ackCode radigo.PacketCode,
+	cfgFlds func(*config.RARequestProcessor) []*config.CfgCdrField) (err error) {
+	originID := sessionmanager.SMGenericEvent(sessionData).GetOriginID(utils.META_DEFAULT)
+	ra.dynAuthMux.Lock()
+	dynSess, has := ra.dynAuthSessions[originID]
+	if !has {
+		ra.dynAuthMux.Unlock()
+		return utils.ErrNotFound
+	}
+	reqProcessor := dynSess.reqProcessor
+	nasAddr := ra.dynAuthAddress(reqProcessor)
+	clnt, err := ra.dynAuthClient(nasAddr)
+	if err != nil {
+		ra.dynAuthMux.Unlock()
+		return
+	}
+	ra.dynAuthReqID++
+	req := clnt.NewRequest(reqCode, ra.dynAuthReqID)
+	ra.dynAuthMux.Unlock()
+	if err = radSessionAppendAttributes(req, sessionData, cfgFlds(reqProcessor)); err != nil {
+		return
+	}
+	if reqProcessor.DryRun {
+		utils.Logger.Info(fmt.Sprintf("<RadiusAgent> DRY_RUN, dynamic authorization request: %s", utils.ToJSON(req)))
+		return
+	}
+	rpl, err := clnt.SendRequest(req)
+	if err != nil {
+		return
+	}
+	if rpl.Code != ackCode {
+		return fmt.Errorf("unexpected reply code: <%d> from NAS: <%s>", rpl.Code, nasAddr)
+	}
+	return
+}
+
+// Internal method to disconnect session, sends Disconnect-Request to the NAS
+func (ra *RadiusAgent) V1DisconnectSession(args utils.AttrDisconnectSession, reply *string) (err error) {
+	if err = ra.sendDynAuthRequest(args.EventStart, RadDisconnectRequest, RadDisconnectACK,
+		func(rp *config.RARequestProcessor) []*config.CfgCdrField {
+			if len(rp.DisconnectFields) != 0 {
+				return rp.DisconnectFields
+			}
+			return ra.cgrCfg.RadiusAgentCfg().DisconnectFields
+		}); err != nil {
+		utils.Logger.Err(fmt.Sprintf("<RadiusAgent> error: <%s> sending Disconnect-Request, reason: <%s>", err.Error(), args.Reason))
+		return
+	}
+	*reply = utils.OK
+	return
+}
+
+// Internal method to re-authorize session, sends CoA-Request to the NAS so it can apply the new session data (eg: rate limits)
+func (ra *RadiusAgent) V1ReAuthSession(args utils.AttrReAuthSession, reply *string) (err error) {
+	if err = ra.sendDynAuthRequest(args.EventStart, RadCoARequest, RadCoAACK,
+		func(rp *config.RARequestProcessor) []*config.CfgCdrField {
+			if len(rp.CoAFields) != 0 {
+				return rp.CoAFields
+			}
+			return ra.cgrCfg.RadiusAgentCfg().CoAFields
+		}); err != nil {
+		utils.Logger.Err(fmt.Sprintf("<RadiusAgent> error: <%s> sending CoA-Request", err.Error()))
+		return
+	}
+	*reply = utils.OK
+	return
+}
+
+// rpcclient.RpcClientConnection interface, used by SMG to reach back the agent
+func (ra *RadiusAgent) Call(serviceMethod string, args interface{}, reply interface{}) error {
+	parts := strings.Split(serviceMethod, ".")
+	if len(parts) != 2 {
+		return rpcclient.ErrUnsupporteServiceMethod
+	}
+	// get method
+	method := reflect.ValueOf(ra).MethodByName(parts[0][len(parts[0])-2:] + parts[1]) // Inherit the version in the method
+	if !method.IsValid() {
+		return rpcclient.ErrUnsupporteServiceMethod
+	}
+	// construct the params
+	params := []reflect.Value{reflect.ValueOf(args), reflect.ValueOf(reply)}
+	ret := method.Call(params)
+	if len(ret) != 1 {
+		return utils.ErrServerError
+	}
+	if ret[0].Interface() == nil {
+		return nil
+	}
+	err, ok := ret[0].Interface().(error)
+	if !ok {
+		return utils.ErrServerError
+	}
+	return err
+}
+
 func (ra *RadiusAgent) ListenAndServe() (err error) {
 	var errListen chan error
 	go func() {
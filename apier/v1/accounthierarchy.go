@@ -0,0 +1,129 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package v1
+
+import (
+	"github.com/cgrates/cgrates/engine"
+	"github.com/cgrates/cgrates/guardian"
+	"github.com/cgrates/cgrates/utils"
+)
+
+type AttrSetAccountParent struct {
+	Tenant          string
+	Account         string
+	ParentAccount   string   // empty to detach the account out of the hierarchy
+	CreditLimit     *float64 // maximum to consume out of the ancestors balances, 0 for unlimited
+	ResetCreditUsed bool     // start counting the credit used from zero
+}
+
+// SetAccountParent moves the account under another parent in the account hierarchy
+func (self *ApierV1) SetAccountParent(attr AttrSetAccountParent, reply *string) (err error) {
+	if missing := utils.MissingStructFields(&attr, []string{"Tenant", "Account"}); len(missing) != 0 {
+		return utils.NewErrMandatoryIeMissing(missing...)
+	}
+	accID := utils.AccountKey(attr.Tenant, attr.Account)
+	_, err = guardian.Guardian.Guard(func() (interface{}, error) {
+		acnt, err := self.DataManager.DataDB().GetAccount(accID)
+		if err != nil {
+			return 0, err
+		}
+		var parent *engine.Account
+		if attr.ParentAccount != "" {
+			if parent, err = self.DataManager.DataDB().GetAccount(
+				utils.AccountKey(attr.Tenant, attr.ParentAccount)); err != nil {
+				return 0, err
+			}
+		}
+		if err = acnt.SetParent(parent); err != nil {
+			return 0, err
+		}
+		if attr.CreditLimit != nil {
+			acnt.CreditLimit = *attr.CreditLimit
+		}
+		if attr.ResetCreditUsed {
+			acnt.CreditUsed = 0
+		}
+		return 0, self.DataManager.DataDB().SetAccount(acnt)
+	}, 0, accID)
+	if err != nil {
+		if err.Error() != utils.ErrNotFound.Error() {
+			err = utils.NewErrServerError(err)
+		}
+		return
+	}
+	*reply = utils.OK
+	return
+}
+
+type AttrGetAccountCostRollup struct {
+	Tenant         string
+	Account        string
+	RunIDs         []string // defaults to *default
+	SetupTimeStart string   // start of the interval, included
+	SetupTimeEnd   string   // end of the interval, excluded
+}
+
+// GetAccountCostRollup returns the costs of the account and of its descendants in the account hierarchy
+func (self *ApierV1) GetAccountCostRollup(attr AttrGetAccountCostRollup, reply *engine.CostRollup) (err error) {
+	if missing := utils.MissingStructFields(&attr, []string{"Tenant", "Account"}); len(missing) != 0 {
+		return utils.NewErrMandatoryIeMissing(missing...)
+	}
+	accID := utils.AccountKey(attr.Tenant, attr.Account)
+	if _, err = self.DataManager.DataDB().GetAccount(accID); err != nil {
+		return
+	}
+	children, err := engine.GetAccountChildren(attr.Tenant)
+	if err != nil {
+		return utils.NewErrServerError(err)
+	}
+	cdrsFltr := &utils.CDRsFilter{Tenants: []string{attr.Tenant}, RunIDs: attr.RunIDs}
+	if len(cdrsFltr.RunIDs) == 0 {
+		cdrsFltr.RunIDs = []string{utils.META_DEFAULT}
+	}
+	for _, acntID := range engine.NewCostRollup(accID, children, nil).AccountIDs() {
+		cdrsFltr.Accounts = append(cdrsFltr.Accounts, utils.NewTenantID(acntID).ID)
+	}
+	if attr.SetupTimeStart != "" {
+		sTime, err := utils.ParseTimeDetectLayout(attr.SetupTimeStart, self.Config.DefaultTimezone)
+		if err != nil {
+			return utils.NewErrServerError(err)
+		}
+		cdrsFltr.SetupTimeStart = &sTime
+	}
+	if attr.SetupTimeEnd != "" {
+		eTime, err := utils.ParseTimeDetectLayout(attr.SetupTimeEnd, self.Config.DefaultTimezone)
+		if err != nil {
+			return utils.NewErrServerError(err)
+		}
+		cdrsFltr.SetupTimeEnd = &eTime
+	}
+	cdrs, _, err := self.CdrDb.GetCDRs(cdrsFltr, false)
+	if err != nil && err != utils.ErrNotFound {
+		return utils.NewErrServerError(err)
+	}
+	costs := make(map[string]float64)
+	for _, cdr := range cdrs {
+		if cdr.Cost == -1 { // not rated
+			continue
+		}
+		costs[utils.ConcatenatedKey(cdr.Tenant, cdr.Account)] += cdr.Cost
+	}
+	*reply = *engine.NewCostRollup(accID, children, costs)
+	return
+}
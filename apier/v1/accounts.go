@@ -413,6 +413,7 @@ type AttrAddBalance struct {
 	Value          float64
 	ExpiryTime     *string
 	RatingSubject  *string
+	Currency       *string
 	Categories     *string
 	DestinationIds *string
 	TimingIds      *string
@@ -469,6 +470,7 @@ func (self *ApierV1) modifyBalance(aType string, attr *AttrAddBalance, reply *st
 			Value:          &utils.ValueFormula{Static: attr.Value},
 			ExpirationDate: expTime,
 			RatingSubject:  attr.RatingSubject,
+			Currency:       attr.Currency,
 			Weight:         attr.Weight,
 			Blocker:        attr.Blocker,
 			Disabled:       attr.Disabled,
@@ -536,6 +538,7 @@ func (self *ApierV1) SetBalance(attr *utils.AttrSetBalance, reply *string) error
 			Type:           utils.StringPointer(attr.BalanceType),
 			ExpirationDate: expTime,
 			RatingSubject:  attr.RatingSubject,
+			Currency:       attr.Currency,
 			Weight:         attr.Weight,
 			Blocker:        attr.Blocker,
 			Disabled:       attr.Disabled,
@@ -596,6 +599,7 @@ func (self *ApierV1) RemoveBalances(attr *utils.AttrSetBalance, reply *string) e
 			Type:           utils.StringPointer(attr.BalanceType),
 			ExpirationDate: expTime,
 			RatingSubject:  attr.RatingSubject,
+			Currency:       attr.Currency,
 			Weight:         attr.Weight,
 			Blocker:        attr.Blocker,
 			Disabled:       attr.Disabled,
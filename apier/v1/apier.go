@@ -1299,6 +1299,27 @@ func (self *ApierV1) FlushCache(args utils.AttrReloadCache, reply *string) (err
 			cache.RemKey(utils.AttributeProfilePrefix+key, true, utils.NonTransactional)
 		}
 	}
+	if args.ExchangeRateIDs == nil {
+		cache.RemPrefixKey(utils.ExchangeRatePrefix, true, utils.NonTransactional)
+	} else if len(*args.ExchangeRateIDs) != 0 {
+		for _, key := range *args.ExchangeRateIDs {
+			cache.RemKey(utils.ExchangeRatePrefix+key, true, utils.NonTransactional)
+		}
+	}
+	if args.TaxProfileIDs == nil {
+		cache.RemPrefixKey(utils.TaxProfilePrefix, true, utils.NonTransactional)
+	} else if len(*args.TaxProfileIDs) != 0 {
+		for _, key := range *args.TaxProfileIDs {
+			cache.RemKey(utils.TaxProfilePrefix+key, true, utils.NonTransactional)
+		}
+	}
+	if args.FeeProfileIDs == nil {
+		cache.RemPrefixKey(utils.FeeProfilePrefix, true, utils.NonTransactional)
+	} else if len(*args.FeeProfileIDs) != 0 {
+		for _, key := range *args.FeeProfileIDs {
+			cache.RemKey(utils.FeeProfilePrefix+key, true, utils.NonTransactional)
+		}
+	}
 
 	*reply = utils.OK
 	return
@@ -1327,6 +1348,7 @@ func (self *ApierV1) GetCacheStats(attrs utils.AttrCacheStats, reply *utils.Cach
 	cs.Filters = cache.CountEntries(utils.FilterPrefix)
 	cs.SupplierProfiles = cache.CountEntries(utils.SupplierProfilePrefix)
 	cs.AttributeProfiles = cache.CountEntries(utils.AttributeProfilePrefix)
+	cs.Partitions = cache.GetPartitionMetrics(attrs.CacheIDs)
 
 	if self.CdrStatsSrv != nil {
 		var queueIds []string
@@ -1346,6 +1368,24 @@ func (self *ApierV1) GetCacheStats(attrs utils.AttrCacheStats, reply *utils.Cach
 	return nil
 }
 
+// GetPrecacheStatus reports the cache warm-up progress, Ready mirrors the engine cacheDoneChan
+func (self *ApierV1) GetPrecacheStatus(ignore string, reply *utils.PrecacheStatus) error {
+	ps := &utils.PrecacheStatus{Partitions: cache.GetPrecacheStatus()}
+	if self.ServManager != nil {
+		ps.Ready = self.ServManager.CacheReady()
+	} else {
+		ps.Ready = len(ps.Partitions) != 0
+		for _, status := range ps.Partitions {
+			if status != utils.MetaReady {
+				ps.Ready = false
+				break
+			}
+		}
+	}
+	*reply = *ps
+	return nil
+}
+
 // GetCacheKeys returns a list of keys available in cache based on query arguments
 // If keys are provided in arguments, they will be checked for existence
 func (v1 *ApierV1) GetCacheKeys(args utils.ArgsCacheKeys, reply *utils.ArgsCache) (err error) {
@@ -1793,6 +1833,9 @@ func (self *ApierV1) LoadTariffPlanFromFolder(attrs utils.AttrLoadTpFromFolder,
 			path.Join(attrs.FolderPath, utils.FiltersCsv),
 			path.Join(attrs.FolderPath, utils.SuppliersCsv),
 			path.Join(attrs.FolderPath, utils.AttributesCsv),
+			path.Join(attrs.FolderPath, utils.ExchangeRatesCsv),
+			path.Join(attrs.FolderPath, utils.TaxProfilesCsv),
+			path.Join(attrs.FolderPath, utils.FeeProfilesCsv),
 		), "", self.Config.DefaultTimezone)
 	if err := loader.LoadAll(); err != nil {
 		return utils.NewErrServerError(err)
@@ -1834,7 +1877,10 @@ func (self *ApierV1) LoadTariffPlanFromFolder(attrs utils.AttrLoadTpFromFolder,
 		utils.ThresholdProfilePrefix,
 		utils.FilterPrefix,
 		utils.SupplierProfilePrefix,
-		utils.AttributeProfilePrefix} {
+		utils.AttributeProfilePrefix,
+		utils.ExchangeRatePrefix,
+		utils.TaxProfilePrefix,
+		utils.FeeProfilePrefix} {
 		loadedIDs, _ := loader.GetLoadedIds(prfx)
 		if err := self.DataManager.CacheDataFromDB(prfx, loadedIDs, true); err != nil {
 			return utils.NewErrServerError(err)
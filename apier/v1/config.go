@@ -0,0 +1,70 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package v1
+
+import (
+	"github.com/cgrates/cgrates/config"
+	"github.com/cgrates/cgrates/servmanager"
+	"github.com/cgrates/cgrates/utils"
+)
+
+// NewConfigSv1 initializes ConfigSv1
+func NewConfigSv1(cfg *config.CGRConfig, srvMngr *servmanager.ServiceManager) *ConfigSv1 {
+	return &ConfigSv1{cfg: cfg, srvMngr: srvMngr}
+}
+
+// ConfigSv1 exports RPC methods for the running configuration
+type ConfigSv1 struct {
+	cfg     *config.CGRConfig
+	srvMngr *servmanager.ServiceManager
+}
+
+// Call implements rpcclient.RpcClientConnection interface for internal RPC
+func (cSv1 *ConfigSv1) Call(serviceMethod string, args interface{}, reply interface{}) error {
+	return utils.APIerRPCCall(cSv1, serviceMethod, args, reply)
+}
+
+// AttrGetJSONSection selects the section returned by GetJSONSection
+type AttrGetJSONSection struct {
+	Section string
+}
+
+// GetJSONSection returns the effective configuration of one section, with secrets masked
+func (cSv1 *ConfigSv1) GetJSONSection(args AttrGetJSONSection, reply *interface{}) (err error) {
+	if missing := utils.MissingStructFields(&args, []string{"Section"}); len(missing) != 0 {
+		return utils.NewErrMandatoryIeMissing(missing...)
+	}
+	sectionVal, err := cSv1.cfg.GetJSONSection(args.Section)
+	if err != nil {
+		return err
+	}
+	*reply = sectionVal
+	return
+}
+
+// GetConfig returns the effective configuration of all sections, with secrets masked
+func (cSv1 *ConfigSv1) GetConfig(ignore string, reply *map[string]interface{}) error {
+	*reply = cSv1.cfg.GetJSONConfig()
+	return nil
+}
+
+// ReloadConfig re-reads the configuration folder and applies the changes on the running engine
+func (cSv1 *ConfigSv1) ReloadConfig(args servmanager.ArgReloadConfig, reply *servmanager.ReloadConfigReply) error {
+	return cSv1.srvMngr.V1ReloadConfig(args, reply)
+}
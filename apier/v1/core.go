@@ -0,0 +1,44 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package v1
+
+import (
+	"github.com/cgrates/cgrates/servmanager"
+	"github.com/cgrates/cgrates/utils"
+)
+
+// NewCoreSv1 initializes CoreSv1
+func NewCoreSv1(srvMngr *servmanager.ServiceManager) *CoreSv1 {
+	return &CoreSv1{srvMngr: srvMngr}
+}
+
+// CoreSv1 exports RPC methods reporting the state of the engine
+type CoreSv1 struct {
+	srvMngr *servmanager.ServiceManager
+}
+
+// Call implements rpcclient.RpcClientConnection interface for internal RPC
+func (cSv1 *CoreSv1) Call(serviceMethod string, args interface{}, reply interface{}) error {
+	return utils.APIerRPCCall(cSv1, serviceMethod, args, reply)
+}
+
+// Status returns the health of the engine services, connections and databases
+func (cSv1 *CoreSv1) Status(ignore string, reply *servmanager.EngineHealth) error {
+	return cSv1.srvMngr.V1Status(ignore, reply)
+}
@@ -43,6 +43,11 @@ func (rsv1 *ResourceSv1) GetResourcesForEvent(args utils.ArgRSv1ResourceUsage, r
 	return rsv1.rls.V1ResourcesForEvent(args, reply)
 }
 
+// GetResource returns a resource with its usages
+func (rsv1 *ResourceSv1) GetResource(args *utils.TenantID, reply *engine.Resource) error {
+	return rsv1.rls.V1GetResource(args, reply)
+}
+
 // AllowUsage checks if there are limits imposed for event
 func (rsv1 *ResourceSv1) AllowUsage(args utils.ArgRSv1ResourceUsage, allowed *bool) error {
 	return rsv1.rls.V1AllowUsage(args, allowed)
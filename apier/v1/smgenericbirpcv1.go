@@ -40,12 +40,16 @@ func (self *SMGenericBiRpcV1) Handlers() map[string]interface{} {
 		"SMGenericV1.UpdateSession":           self.UpdateSession,
 		"SMGenericV1.TerminateSession":        self.TerminateSession,
 		"SMGenericV1.ChargeEvent":             self.ChargeEvent,
+		"SMGenericV1.ReserveEvent":            self.ReserveEvent,
+		"SMGenericV1.CommitEvent":             self.CommitEvent,
+		"SMGenericV1.RefundEvent":             self.RefundEvent,
 		"SMGenericV1.ProcessCDR":              self.ProcessCDR,
 		"SMGenericV1.GetActiveSessions":       self.GetActiveSessions,
 		"SMGenericV1.GetActiveSessionsCount":  self.GetActiveSessionsCount,
 		"SMGenericV1.GetṔassiveSessions":      self.GetṔassiveSessions,
 		"SMGenericV1.GetPassiveSessionsCount": self.GetPassiveSessionsCount,
 		"SMGenericV1.ReplicateActiveSessions": self.ReplicateActiveSessions,
+		"SMGenericV1.ReAuthorizeSessions":     self.ReAuthorizeSessions,
 	}
 }
 
@@ -79,6 +83,21 @@ func (self *SMGenericBiRpcV1) ChargeEvent(clnt *rpc2.Client, ev sessionmanager.S
 	return self.sm.BiRPCV1ChargeEvent(clnt, ev, maxUsage)
 }
 
+// Reserves the cost of individual Events (eg SMS) until committed or refunded
+func (self *SMGenericBiRpcV1) ReserveEvent(clnt *rpc2.Client, ev sessionmanager.SMGenericEvent, maxUsage *float64) error {
+	return self.sm.BiRPCV1ReserveEvent(clnt, ev, maxUsage)
+}
+
+// Confirms a reserved Event (eg SMS delivered)
+func (self *SMGenericBiRpcV1) CommitEvent(clnt *rpc2.Client, ev sessionmanager.SMGenericEvent, reply *string) error {
+	return self.sm.BiRPCV1CommitEvent(clnt, ev, reply)
+}
+
+// Refunds a reserved Event (eg SMS not delivered)
+func (self *SMGenericBiRpcV1) RefundEvent(clnt *rpc2.Client, ev sessionmanager.SMGenericEvent, reply *string) error {
+	return self.sm.BiRPCV1RefundEvent(clnt, ev, reply)
+}
+
 // Called on session end, should send the CDR to CDRS
 func (self *SMGenericBiRpcV1) ProcessCDR(clnt *rpc2.Client, ev sessionmanager.SMGenericEvent, reply *string) error {
 	return self.sm.BiRPCV1ProcessCDR(clnt, ev, reply)
@@ -107,3 +126,7 @@ func (self *SMGenericBiRpcV1) ReplicateActiveSessions(clnt *rpc2.Client, args se
 func (self *SMGenericBiRpcV1) ReplicatePassiveSessions(clnt *rpc2.Client, args sessionmanager.ArgsReplicateSessions, reply *string) error {
 	return self.sm.BiRPCV1ReplicateActiveSessions(clnt, args, reply)
 }
+
+func (self *SMGenericBiRpcV1) ReAuthorizeSessions(clnt *rpc2.Client, attrs map[string]string, reply *string) error {
+	return self.sm.BiRPCV1ReAuthorizeSessions(clnt, attrs, reply)
+}
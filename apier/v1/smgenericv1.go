@@ -66,6 +66,21 @@ func (self *SMGenericV1) ChargeEvent(ev sessionmanager.SMGenericEvent, maxUsage
 	return self.SMG.BiRPCV1ChargeEvent(nil, ev, maxUsage)
 }
 
+// Reserves the cost of individual Events (eg SMS) until committed or refunded
+func (self *SMGenericV1) ReserveEvent(ev sessionmanager.SMGenericEvent, maxUsage *float64) error {
+	return self.SMG.BiRPCV1ReserveEvent(nil, ev, maxUsage)
+}
+
+// Confirms a reserved Event (eg SMS delivered)
+func (self *SMGenericV1) CommitEvent(ev sessionmanager.SMGenericEvent, reply *string) error {
+	return self.SMG.BiRPCV1CommitEvent(nil, ev, reply)
+}
+
+// Refunds a reserved Event (eg SMS not delivered)
+func (self *SMGenericV1) RefundEvent(ev sessionmanager.SMGenericEvent, reply *string) error {
+	return self.SMG.BiRPCV1RefundEvent(nil, ev, reply)
+}
+
 // Called on session end, should send the CDR to CDRS
 func (self *SMGenericV1) ProcessCDR(ev sessionmanager.SMGenericEvent, reply *string) error {
 	return self.SMG.BiRPCV1ProcessCDR(nil, ev, reply)
@@ -87,6 +102,10 @@ func (self *SMGenericV1) GetPassiveSessionsCount(attrs map[string]string, reply
 	return self.SMG.BiRPCV1GetPassiveSessionsCount(nil, attrs, reply)
 }
 
+func (self *SMGenericV1) ReAuthorizeSessions(attrs map[string]string, reply *string) error {
+	return self.SMG.BiRPCV1ReAuthorizeSessions(nil, attrs, reply)
+}
+
 func (self *SMGenericV1) SetPassiveSessions(args sessionmanager.ArgsSetPassiveSessions, reply *string) error {
 	return self.SMG.BiRPCV1SetPassiveSessions(nil, args, reply)
 }
@@ -103,3 +103,8 @@ func (stsv1 *StatSv1) GetQueueStringMetrics(args *utils.TenantID, reply *map[str
 func (stsv1 *StatSv1) GetQueueFloatMetrics(args *utils.TenantID, reply *map[string]float64) (err error) {
 	return stsv1.sS.V1GetQueueFloatMetrics(args, reply)
 }
+
+// ResetStatQueue clears the items and metrics of a Queue
+func (stsv1 *StatSv1) ResetStatQueue(args *utils.TenantID, reply *string) (err error) {
+	return stsv1.sS.V1ResetStatQueue(args, reply)
+}
@@ -0,0 +1,179 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package v1
+
+import (
+	"time"
+
+	"github.com/cgrates/cgrates/cache"
+	"github.com/cgrates/cgrates/engine"
+	"github.com/cgrates/cgrates/guardian"
+	"github.com/cgrates/cgrates/utils"
+)
+
+// GetFeeProfile returns a Fee Profile
+func (apierV1 *ApierV1) GetFeeProfile(arg utils.TenantID, reply *engine.FeeProfile) error {
+	if missing := utils.MissingStructFields(&arg, []string{"Tenant", "ID"}); len(missing) != 0 { //Params missing
+		return utils.NewErrMandatoryIeMissing(missing...)
+	}
+	if fep, err := apierV1.DataManager.GetFeeProfile(arg.Tenant, arg.ID, false, utils.NonTransactional); err != nil {
+		if err.Error() != utils.ErrNotFound.Error() {
+			err = utils.NewErrServerError(err)
+		}
+		return err
+	} else {
+		*reply = *fep
+	}
+	return nil
+}
+
+// SetFeeProfile add/update a Fee Profile
+func (apierV1 *ApierV1) SetFeeProfile(fep *engine.FeeProfile, reply *string) error {
+	if missing := utils.MissingStructFields(fep, []string{"Tenant", "ID", "Period"}); len(missing) != 0 {
+		return utils.NewErrMandatoryIeMissing(missing...)
+	}
+	if _, _, err := fep.PeriodBounds(time.Now()); err != nil {
+		return utils.NewErrServerError(err)
+	}
+	if err := apierV1.DataManager.SetFeeProfile(fep); err != nil {
+		return utils.APIErrorHandler(err)
+	}
+	cache.RemKey(utils.FeeProfilePrefix+fep.TenantID(), true, "") // ToDo: Remove here with autoreload
+	*reply = utils.OK
+	return nil
+}
+
+// RemFeeProfile removes a Fee Profile
+func (apierV1 *ApierV1) RemFeeProfile(arg utils.TenantID, reply *string) error {
+	if missing := utils.MissingStructFields(&arg, []string{"Tenant", "ID"}); len(missing) != 0 { //Params missing
+		return utils.NewErrMandatoryIeMissing(missing...)
+	}
+	if err := apierV1.DataManager.RemoveFeeProfile(arg.Tenant, arg.ID, utils.NonTransactional); err != nil {
+		if err.Error() != utils.ErrNotFound.Error() {
+			err = utils.NewErrServerError(err)
+		}
+		return err
+	}
+	*reply = utils.OK
+	return nil
+}
+
+type AttrSetAccountSubscription struct {
+	Tenant           string
+	Account          string
+	FeeProfileID     string
+	ActivationTime   string // defaults to now for new subscriptions
+	DeactivationTime string // empty for subscriptions without end
+}
+
+// SetAccountSubscription subscribes an account to a FeeProfile or updates the subscription, charging the fees already due
+func (apierV1 *ApierV1) SetAccountSubscription(attr AttrSetAccountSubscription, reply *string) (err error) {
+	if missing := utils.MissingStructFields(&attr, []string{"Tenant", "Account", "FeeProfileID"}); len(missing) != 0 {
+		return utils.NewErrMandatoryIeMissing(missing...)
+	}
+	if _, err = apierV1.DataManager.GetFeeProfile(attr.Tenant, attr.FeeProfileID, false, utils.NonTransactional); err != nil {
+		if err.Error() != utils.ErrNotFound.Error() {
+			err = utils.NewErrServerError(err)
+		}
+		return
+	}
+	var aTime, dTime time.Time
+	if attr.ActivationTime != "" {
+		if aTime, err = utils.ParseTimeDetectLayout(attr.ActivationTime, apierV1.Config.DefaultTimezone); err != nil {
+			return
+		}
+	}
+	if attr.DeactivationTime != "" {
+		if dTime, err = utils.ParseTimeDetectLayout(attr.DeactivationTime, apierV1.Config.DefaultTimezone); err != nil {
+			return
+		}
+	}
+	accID := utils.AccountKey(attr.Tenant, attr.Account)
+	_, err = guardian.Guardian.Guard(func() (interface{}, error) {
+		acnt, err := apierV1.DataManager.DataDB().GetAccount(accID)
+		if err != nil {
+			return 0, err
+		}
+		if acnt.Subscriptions == nil {
+			acnt.Subscriptions = make(map[string]*engine.Subscription)
+		}
+		sub, has := acnt.Subscriptions[attr.FeeProfileID]
+		if !has {
+			sub = &engine.Subscription{FeeProfileID: attr.FeeProfileID, ActivationTime: time.Now()}
+			acnt.Subscriptions[attr.FeeProfileID] = sub
+		}
+		if !aTime.IsZero() {
+			sub.ActivationTime = aTime
+		}
+		sub.DeactivationTime = dTime
+		if _, err := acnt.ChargeSubscriptions(time.Now()); err != nil {
+			return 0, err
+		}
+		return 0, apierV1.DataManager.DataDB().SetAccount(acnt)
+	}, 0, accID)
+	if err != nil {
+		if err.Error() != utils.ErrNotFound.Error() {
+			err = utils.NewErrServerError(err)
+		}
+		return
+	}
+	*reply = utils.OK
+	return
+}
+
+type AttrRemAccountSubscription struct {
+	Tenant       string
+	Account      string
+	FeeProfileID string
+}
+
+// RemAccountSubscription ends the subscription of an account, refunding the pro-rated fee charged in advance
+func (apierV1 *ApierV1) RemAccountSubscription(attr AttrRemAccountSubscription, reply *string) (err error) {
+	if missing := utils.MissingStructFields(&attr, []string{"Tenant", "Account", "FeeProfileID"}); len(missing) != 0 {
+		return utils.NewErrMandatoryIeMissing(missing...)
+	}
+	accID := utils.AccountKey(attr.Tenant, attr.Account)
+	_, err = guardian.Guardian.Guard(func() (interface{}, error) {
+		acnt, err := apierV1.DataManager.DataDB().GetAccount(accID)
+		if err != nil {
+			return 0, err
+		}
+		sub, has := acnt.Subscriptions[attr.FeeProfileID]
+		if !has {
+			return 0, utils.ErrNotFound
+		}
+		now := time.Now()
+		if sub.DeactivationTime.IsZero() || sub.DeactivationTime.After(now) {
+			sub.DeactivationTime = now
+		}
+		if _, err := acnt.ChargeSubscriptions(now); err != nil {
+			return 0, err
+		}
+		delete(acnt.Subscriptions, attr.FeeProfileID)
+		return 0, apierV1.DataManager.DataDB().SetAccount(acnt)
+	}, 0, accID)
+	if err != nil {
+		if err.Error() != utils.ErrNotFound.Error() {
+			err = utils.NewErrServerError(err)
+		}
+		return
+	}
+	*reply = utils.OK
+	return
+}
@@ -0,0 +1,119 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package v1
+
+import (
+	"github.com/cgrates/cgrates/cache"
+	"github.com/cgrates/cgrates/engine"
+	"github.com/cgrates/cgrates/utils"
+)
+
+type AttrExchangeRate struct {
+	FromCurrency string
+	ToCurrency   string
+}
+
+// GetExchangeRate returns the exchange rate table between two currencies
+func (apierV1 *ApierV1) GetExchangeRate(arg AttrExchangeRate, reply *engine.ExchangeRate) error {
+	if missing := utils.MissingStructFields(&arg, []string{"FromCurrency", "ToCurrency"}); len(missing) != 0 { //Params missing
+		return utils.NewErrMandatoryIeMissing(missing...)
+	}
+	if xr, err := apierV1.DataManager.GetExchangeRate(arg.FromCurrency, arg.ToCurrency, false, utils.NonTransactional); err != nil {
+		if err.Error() != utils.ErrNotFound.Error() {
+			err = utils.NewErrServerError(err)
+		}
+		return err
+	} else {
+		*reply = *xr
+	}
+	return nil
+}
+
+// SetExchangeRate add/update an exchange rate table
+func (apierV1 *ApierV1) SetExchangeRate(xr *engine.ExchangeRate, reply *string) error {
+	if missing := utils.MissingStructFields(xr, []string{"FromCurrency", "ToCurrency"}); len(missing) != 0 {
+		return utils.NewErrMandatoryIeMissing(missing...)
+	}
+	xr.Sort()
+	if err := apierV1.DataManager.SetExchangeRate(xr); err != nil {
+		return utils.APIErrorHandler(err)
+	}
+	cache.RemKey(utils.ExchangeRatePrefix+xr.ID(), true, "") // ToDo: Remove here with autoreload
+	*reply = utils.OK
+	return nil
+}
+
+// RemExchangeRate removes the exchange rate table between two currencies
+func (apierV1 *ApierV1) RemExchangeRate(arg AttrExchangeRate, reply *string) error {
+	if missing := utils.MissingStructFields(&arg, []string{"FromCurrency", "ToCurrency"}); len(missing) != 0 { //Params missing
+		return utils.NewErrMandatoryIeMissing(missing...)
+	}
+	if err := apierV1.DataManager.RemoveExchangeRate(arg.FromCurrency, arg.ToCurrency, utils.NonTransactional); err != nil {
+		if err.Error() != utils.ErrNotFound.Error() {
+			err = utils.NewErrServerError(err)
+		}
+		return err
+	}
+	*reply = utils.OK
+	return nil
+}
+
+// GetTaxProfile returns a Tax Profile
+func (apierV1 *ApierV1) GetTaxProfile(arg utils.TenantID, reply *engine.TaxProfile) error {
+	if missing := utils.MissingStructFields(&arg, []string{"Tenant", "ID"}); len(missing) != 0 { //Params missing
+		return utils.NewErrMandatoryIeMissing(missing...)
+	}
+	if txPrf, err := apierV1.DataManager.GetTaxProfile(arg.Tenant, arg.ID, false, utils.NonTransactional); err != nil {
+		if err.Error() != utils.ErrNotFound.Error() {
+			err = utils.NewErrServerError(err)
+		}
+		return err
+	} else {
+		*reply = *txPrf
+	}
+	return nil
+}
+
+// SetTaxProfile add/update a Tax Profile
+func (apierV1 *ApierV1) SetTaxProfile(txPrf *engine.TaxProfile, reply *string) error {
+	if missing := utils.MissingStructFields(txPrf, []string{"Tenant", "ID"}); len(missing) != 0 {
+		return utils.NewErrMandatoryIeMissing(missing...)
+	}
+	if err := apierV1.DataManager.SetTaxProfile(txPrf); err != nil {
+		return utils.APIErrorHandler(err)
+	}
+	cache.RemKey(utils.TaxProfilePrefix+txPrf.TenantID(), true, "") // ToDo: Remove here with autoreload
+	*reply = utils.OK
+	return nil
+}
+
+// RemTaxProfile removes a Tax Profile
+func (apierV1 *ApierV1) RemTaxProfile(arg utils.TenantID, reply *string) error {
+	if missing := utils.MissingStructFields(&arg, []string{"Tenant", "ID"}); len(missing) != 0 { //Params missing
+		return utils.NewErrMandatoryIeMissing(missing...)
+	}
+	if err := apierV1.DataManager.RemoveTaxProfile(arg.Tenant, arg.ID, utils.NonTransactional); err != nil {
+		if err.Error() != utils.ErrNotFound.Error() {
+			err = utils.NewErrServerError(err)
+		}
+		return err
+	}
+	*reply = utils.OK
+	return nil
+}
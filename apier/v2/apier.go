@@ -147,6 +147,9 @@ func (self *ApierV2) LoadTariffPlanFromFolder(attrs utils.AttrLoadTpFromFolder,
 			path.Join(attrs.FolderPath, utils.FiltersCsv),
 			path.Join(attrs.FolderPath, utils.SuppliersCsv),
 			path.Join(attrs.FolderPath, utils.AttributesCsv),
+			path.Join(attrs.FolderPath, utils.ExchangeRatesCsv),
+			path.Join(attrs.FolderPath, utils.TaxProfilesCsv),
+			path.Join(attrs.FolderPath, utils.FeeProfilesCsv),
 		), "", self.Config.DefaultTimezone)
 	if err := loader.LoadAll(); err != nil {
 		return utils.NewErrServerError(err)
@@ -189,7 +192,10 @@ func (self *ApierV2) LoadTariffPlanFromFolder(attrs utils.AttrLoadTpFromFolder,
 		utils.ThresholdProfilePrefix,
 		utils.FilterPrefix,
 		utils.SupplierProfilePrefix,
-		utils.SupplierProfilePrefix} {
+		utils.SupplierProfilePrefix,
+		utils.ExchangeRatePrefix,
+		utils.TaxProfilePrefix,
+		utils.FeeProfilePrefix} {
 		loadedIDs, _ := loader.GetLoadedIds(prfx)
 		if err := self.DataManager.CacheDataFromDB(prfx, loadedIDs, true); err != nil {
 			return utils.NewErrServerError(err)
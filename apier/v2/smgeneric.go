@@ -48,3 +48,8 @@ func (smgv2 *SMGenericV2) UpdateSession(ev sessionmanager.SMGenericEvent, maxUsa
 func (smgv2 *SMGenericV2) ChargeEvent(ev sessionmanager.SMGenericEvent, maxUsage *time.Duration) error {
 	return smgv2.SMG.BiRPCV2ChargeEvent(nil, ev, maxUsage)
 }
+
+// Reserves the cost of individual Events (eg SMS) until committed or refunded
+func (smgv2 *SMGenericV2) ReserveEvent(ev sessionmanager.SMGenericEvent, maxUsage *time.Duration) error {
+	return smgv2.SMG.BiRPCV2ReserveEvent(nil, ev, maxUsage)
+}
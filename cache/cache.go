@@ -21,6 +21,7 @@ package cache
 import (
 	"fmt"
 	"reflect"
+	"sort"
 	"sync"
 	"time"
 
@@ -45,6 +46,9 @@ var (
 	transactionBuffer map[string][]*transactionItem // Queue tasks based on transactionID
 	transBufMux       sync.Mutex                    // Protects the transactionBuffer
 	transactionMux    sync.Mutex                    // Queue transactions on commit
+
+	precacheStatus = make(map[string]string) // warm-up status indexed on partition
+	precacheMux    sync.RWMutex
 )
 
 type transactionItem struct {
@@ -62,6 +66,7 @@ func init() {
 func NewCache(cacheCfg config.CacheConfig) {
 	cfg = cacheCfg
 	cache = newLRUTTL(cacheCfg)
+	replPartitions = replicatedPartitions(cacheCfg)
 	transactionBuffer = make(map[string][]*transactionItem) // map[transactionID][]*transactionItem
 }
 
@@ -84,17 +89,23 @@ func CommitTransaction(transID string) {
 	transBufMux.Lock()
 	// apply all transactioned items in one shot
 	cacheMux.Lock()
+	var replItems []*ReplicationItem
 	for _, item := range transactionBuffer[transID] {
 		switch item.verb {
 		case REM:
-			RemKey(item.key, true, transID)
+			cache.Delete(item.key)
+			replItems = appendReplicated(replItems, REM, item.key)
 		case REM_PREFIX:
-			RemPrefixKey(item.key, true, transID)
+			cache.DeletePrefix(item.key)
+			replItems = appendReplicated(replItems, REM_PREFIX, item.key)
 		case ADD:
-			Set(item.key, item.value, true, transID)
+			if set(item.key, item.value) {
+				replItems = appendReplicated(replItems, REM, item.key)
+			}
 		}
 	}
 	cacheMux.Unlock()
+	replicateItems(replItems)
 	delete(transactionBuffer, transID)
 	transBufMux.Unlock()
 	transactionMux.Unlock()
@@ -107,8 +118,9 @@ func Set(key string, value interface{}, commit bool, transID string) {
 			cacheMux.Lock()
 			defer cacheMux.Unlock()
 		}
-		cache.Put(key, value)
-		//log.Println("ADD: ", key)
+		if set(key, value) && transID == "" { // first time caching is not replicated so peers can populate their own
+			replicateItems(appendReplicated(nil, REM, key))
+		}
 	} else {
 		transBufMux.Lock()
 		transactionBuffer[transID] = append(transactionBuffer[transID], &transactionItem{verb: ADD, key: key, value: value})
@@ -116,6 +128,11 @@ func Set(key string, value interface{}, commit bool, transID string) {
 	}
 }
 
+// set puts the value in cache, returning true if the key was already cached, cacheMux should be locked
+func set(key string, value interface{}) (updated bool) {
+	return cache.Put(key, value)
+}
+
 // RemKey removes a specific item from cache
 func RemKey(key string, commit bool, transID string) {
 	if commit {
@@ -124,6 +141,9 @@ func RemKey(key string, commit bool, transID string) {
 			defer cacheMux.Unlock()
 		}
 		cache.Delete(key)
+		if transID == "" {
+			replicateItems(appendReplicated(nil, REM, key))
+		}
 	} else {
 		transBufMux.Lock()
 		transactionBuffer[transID] = append(transactionBuffer[transID], &transactionItem{verb: REM, key: key})
@@ -139,6 +159,9 @@ func RemPrefixKey(prefix string, commit bool, transID string) {
 			defer cacheMux.Unlock()
 		}
 		cache.DeletePrefix(prefix)
+		if transID == "" {
+			replicateItems(appendReplicated(nil, REM_PREFIX, prefix))
+		}
 	} else {
 		transBufMux.Lock()
 		transactionBuffer[transID] = append(transactionBuffer[transID], &transactionItem{verb: REM_PREFIX, key: prefix})
@@ -153,6 +176,16 @@ func Flush() {
 	cacheMux.Unlock()
 }
 
+// ReloadCacheConfig rebuilds the cache instances out of a new configuration,
+// cached items are kept, the ones over the new partition limits being evicted
+func ReloadCacheConfig(cacheCfg config.CacheConfig) {
+	cacheMux.Lock()
+	cfg = cacheCfg
+	cache = cache.(cacheLRUTTL).reload(cacheCfg)
+	replPartitions = replicatedPartitions(cacheCfg)
+	cacheMux.Unlock()
+}
+
 // The function to extract a value for a key that never expire
 func Get(key string) (interface{}, bool) {
 	cacheMux.RLock()
@@ -198,3 +231,68 @@ func GetEntryKeys(prefix string) (keys []string) {
 	defer cacheMux.RUnlock()
 	return cache.GetKeysForPrefix(prefix)
 }
+
+// GetPartitionMetrics returns the usage metrics of the cache partitions, all of them if none requested
+func GetPartitionMetrics(partitions []string) (mtrcs map[string]*utils.CachePartitionMetrics) {
+	cacheMux.RLock()
+	mtrcs = cache.Metrics()
+	cacheMux.RUnlock()
+	if len(partitions) == 0 {
+		return
+	}
+	filtered := make(map[string]*utils.CachePartitionMetrics, len(partitions))
+	for _, partition := range partitions {
+		if pm, has := mtrcs[partition]; has {
+			filtered[partition] = pm
+		}
+	}
+	return filtered
+}
+
+// SetPrecacheStatus records the warm-up status of a partition, <*loading|*ready>
+func SetPrecacheStatus(partition, status string) {
+	precacheMux.Lock()
+	precacheStatus[partition] = status
+	precacheMux.Unlock()
+}
+
+// GetPrecacheStatus returns a copy of the warm-up status indexed on partition
+func GetPrecacheStatus() (status map[string]string) {
+	precacheMux.RLock()
+	status = make(map[string]string, len(precacheStatus))
+	for partition, st := range precacheStatus {
+		status[partition] = st
+	}
+	precacheMux.RUnlock()
+	return
+}
+
+// PromMetrics exports the per partition cache statistics
+func PromMetrics() []*utils.PromMetric {
+	mtrcs := GetPartitionMetrics(nil)
+	partitions := make([]string, 0, len(mtrcs))
+	for partition := range mtrcs {
+		partitions = append(partitions, partition)
+	}
+	sort.Strings(partitions)
+	items := &utils.PromMetric{Name: utils.PromNamespace + "_cache_items",
+		Help: "Items stored in cache partition", Type: utils.PromGauge}
+	hits := &utils.PromMetric{Name: utils.PromNamespace + "_cache_hits_total",
+		Help: "Cache lookups finding the item", Type: utils.PromCounter}
+	misses := &utils.PromMetric{Name: utils.PromNamespace + "_cache_misses_total",
+		Help: "Cache lookups not finding the item", Type: utils.PromCounter}
+	evictions := &utils.PromMetric{Name: utils.PromNamespace + "_cache_evictions_total",
+		Help: "Items dropped because the partition limit was reached", Type: utils.PromCounter}
+	expiries := &utils.PromMetric{Name: utils.PromNamespace + "_cache_expiries_total",
+		Help: "Items dropped because their TTL expired", Type: utils.PromCounter}
+	for _, partition := range partitions {
+		pm := mtrcs[partition]
+		lbls := map[string]string{"partition": partition}
+		items.Samples = append(items.Samples, &utils.PromSample{Labels: lbls, Value: float64(pm.Items)})
+		hits.Samples = append(hits.Samples, &utils.PromSample{Labels: lbls, Value: float64(pm.Hits)})
+		misses.Samples = append(misses.Samples, &utils.PromSample{Labels: lbls, Value: float64(pm.Misses)})
+		evictions.Samples = append(evictions.Samples, &utils.PromSample{Labels: lbls, Value: float64(pm.Evictions)})
+		expiries.Samples = append(expiries.Samples, &utils.PromSample{Labels: lbls, Value: float64(pm.Expiries)})
+	}
+	return []*utils.PromMetric{items, hits, misses, evictions, expiries}
+}
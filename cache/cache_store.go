@@ -20,6 +20,9 @@ package cache
 
 import (
 	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/cgrates/cgrates/config"
 	"github.com/cgrates/cgrates/utils"
@@ -27,20 +30,126 @@ import (
 )
 
 type cacheStore interface {
-	Put(string, interface{})
+	Put(string, interface{}) bool
 	Get(string) (interface{}, bool)
 	Delete(string)
 	DeletePrefix(string)
 	CountEntriesForPrefix(string) int
 	GetKeysForPrefix(string) []string
 	Clear()
+	Metrics() map[string]*utils.CachePartitionMetrics
 }
 
-type cacheLRUTTL map[string]*ltcache.Cache
+// partitionMetrics counts the usage of one cache partition
+type partitionMetrics struct {
+	hits      int64
+	misses    int64
+	evictions int64 // items dropped because of the partition limit
+	expiries  int64 // items dropped because of the TTL
+}
+
+// cachedItem wraps the values stored in ltcache so the reason of an eviction can be found out in onEvicted
+type cachedItem struct {
+	value   interface{}
+	expires int64 // UnixNano when the TTL expires, 0 without TTL
+}
+
+// lruTTLInstance is one cache partition together with its metrics
+type lruTTLInstance struct {
+	*ltcache.Cache
+	partition string // partition name as defined in CacheConfig
+	ttl       time.Duration
+	staticTTL bool
+	metrics   *partitionMetrics
+	itmsMux   sync.RWMutex
+	items     map[string]*cachedItem // items in ltcache, checked without altering the LRU order or TTL
+}
+
+func newLRUTTLInstance(partition string, limit int, ttl time.Duration, staticTTL bool) (ci *lruTTLInstance) {
+	ci = &lruTTLInstance{
+		partition: partition,
+		ttl:       ttl,
+		staticTTL: staticTTL,
+		metrics:   new(partitionMetrics),
+		items:     make(map[string]*cachedItem),
+	}
+	ci.Cache = ltcache.New(limit, ttl, staticTTL, ci.onEvicted)
+	return
+}
+
+// onEvicted is passed to ltcache, items removed or replaced on purpose are not counted
+func (ci *lruTTLInstance) onEvicted(itmID string, value interface{}) {
+	itm, canCast := value.(*cachedItem)
+	if !canCast {
+		return
+	}
+	ci.itmsMux.Lock()
+	if ci.items[itmID] != itm {
+		ci.itmsMux.Unlock()
+		return
+	}
+	delete(ci.items, itmID)
+	ci.itmsMux.Unlock()
+	if expires := atomic.LoadInt64(&itm.expires); expires != 0 && time.Now().UnixNano() >= expires {
+		atomic.AddInt64(&ci.metrics.expiries, 1)
+	} else {
+		atomic.AddInt64(&ci.metrics.evictions, 1)
+	}
+}
+
+// expiryTime returns the moment an item touched now expires, computed before ltcache does it so it is never later
+func (ci *lruTTLInstance) expiryTime() int64 {
+	if ci.ttl <= 0 {
+		return 0
+	}
+	return time.Now().Add(ci.ttl).UnixNano()
+}
+
+// set caches the value, returning true if the item was already cached
+func (ci *lruTTLInstance) set(itmID string, value interface{}) (updated bool) {
+	itm := &cachedItem{value: value, expires: ci.expiryTime()}
+	ci.itmsMux.Lock()
+	_, updated = ci.items[itmID]
+	ci.items[itmID] = itm // indexed before ltcache so the eviction of the previous value is not counted
+	ci.itmsMux.Unlock()
+	ci.Set(itmID, itm)
+	return
+}
+
+func (ci *lruTTLInstance) get(itmID string) (value interface{}, has bool) {
+	expires := ci.expiryTime()
+	var x interface{}
+	if x, has = ci.Get(itmID); !has {
+		atomic.AddInt64(&ci.metrics.misses, 1)
+		return
+	}
+	atomic.AddInt64(&ci.metrics.hits, 1)
+	itm := x.(*cachedItem)
+	if !ci.staticTTL && expires != 0 {
+		atomic.StoreInt64(&itm.expires, expires)
+	}
+	return itm.value, true
+}
+
+func (ci *lruTTLInstance) remove(itmID string) {
+	ci.itmsMux.Lock()
+	delete(ci.items, itmID)
+	ci.itmsMux.Unlock()
+	ci.Remove(itmID)
+}
+
+func (ci *lruTTLInstance) clear() {
+	ci.itmsMux.Lock()
+	ci.items = make(map[string]*cachedItem)
+	ci.itmsMux.Unlock()
+	ci.Clear()
+}
+
+type cacheLRUTTL map[string]*lruTTLInstance
 
 func newLRUTTL(cfg config.CacheConfig) (c cacheLRUTTL) {
-	c = map[string]*ltcache.Cache{
-		utils.ANY: ltcache.New(ltcache.UnlimitedCaching, ltcache.UnlimitedCaching, false, nil), // no limits for default cache instance
+	c = map[string]*lruTTLInstance{
+		utils.ANY: newLRUTTLInstance(utils.META_ANY, ltcache.UnlimitedCaching, ltcache.UnlimitedCaching, false), // no limits for default cache instance
 	}
 	if cfg == nil {
 		return
@@ -51,12 +160,32 @@ func newLRUTTL(cfg config.CacheConfig) (c cacheLRUTTL) {
 		if prefixKey, has := utils.CacheInstanceToPrefix[cfgKey]; has {
 			cacheInstanceID = prefixKey // old aliases, backwards compatibility purpose
 		}
-		c[cacheInstanceID] = ltcache.New(cfg[cfgKey].Limit, cfg[cfgKey].TTL, cfg[cfgKey].StaticTTL, nil)
+		c[cacheInstanceID] = newLRUTTLInstance(cfgKey, cfg[cfgKey].Limit, cfg[cfgKey].TTL, cfg[cfgKey].StaticTTL)
+	}
+	return
+}
+
+// reload returns the cache instances built out of cfg, holding the items and metrics of cs
+// items are cached again within the new limits, their TTL starting over
+func (cs cacheLRUTTL) reload(cfg config.CacheConfig) (c cacheLRUTTL) {
+	c = newLRUTTL(cfg)
+	for instID, oldInst := range cs {
+		newInst, has := c[instID]
+		if !has { // partition not configured anymore, items are cached by *any from now on
+			newInst = c[utils.ANY]
+		} else {
+			newInst.metrics = oldInst.metrics
+		}
+		oldInst.itmsMux.RLock()
+		for itmID, itm := range oldInst.items {
+			newInst.set(itmID, itm.value)
+		}
+		oldInst.itmsMux.RUnlock()
 	}
 	return
 }
 
-func (cs cacheLRUTTL) cacheInstance(instID string) (c *ltcache.Cache) {
+func (cs cacheLRUTTL) cacheInstance(instID string) (c *lruTTLInstance) {
 	var ok bool
 	if c, ok = cs[instID]; !ok {
 		c = cs[utils.ANY]
@@ -64,21 +193,21 @@ func (cs cacheLRUTTL) cacheInstance(instID string) (c *ltcache.Cache) {
 	return
 }
 
-func (cs cacheLRUTTL) Put(key string, value interface{}) {
-	cs.cacheInstance(key[:PREFIX_LEN]).Set(key[PREFIX_LEN:], value)
+func (cs cacheLRUTTL) Put(key string, value interface{}) bool {
+	return cs.cacheInstance(key[:PREFIX_LEN]).set(key[PREFIX_LEN:], value)
 }
 
 func (cs cacheLRUTTL) Get(key string) (interface{}, bool) {
-	return cs.cacheInstance(key[:PREFIX_LEN]).Get(key[PREFIX_LEN:])
+	return cs.cacheInstance(key[:PREFIX_LEN]).get(key[PREFIX_LEN:])
 }
 
 func (cs cacheLRUTTL) Delete(key string) {
-	cs.cacheInstance(key[:PREFIX_LEN]).Remove(key[PREFIX_LEN:])
+	cs.cacheInstance(key[:PREFIX_LEN]).remove(key[PREFIX_LEN:])
 }
 
 func (cs cacheLRUTTL) DeletePrefix(prefix string) {
 	if c, hasInst := cs[prefix]; hasInst {
-		c.Clear()
+		c.clear()
 	}
 }
 
@@ -104,6 +233,21 @@ func (cs cacheLRUTTL) GetKeysForPrefix(prefix string) (keys []string) {
 
 func (cs cacheLRUTTL) Clear() {
 	for _, cInst := range cs {
-		cInst.Clear()
+		cInst.clear()
 	}
 }
+
+// Metrics returns the usage counters indexed on partition name
+func (cs cacheLRUTTL) Metrics() (mtrcs map[string]*utils.CachePartitionMetrics) {
+	mtrcs = make(map[string]*utils.CachePartitionMetrics, len(cs))
+	for _, cInst := range cs {
+		mtrcs[cInst.partition] = &utils.CachePartitionMetrics{
+			Items:     cInst.Len(),
+			Hits:      atomic.LoadInt64(&cInst.metrics.hits),
+			Misses:    atomic.LoadInt64(&cInst.metrics.misses),
+			Evictions: atomic.LoadInt64(&cInst.metrics.evictions),
+			Expiries:  atomic.LoadInt64(&cInst.metrics.expiries),
+		}
+	}
+	return
+}
@@ -19,10 +19,13 @@ package cache
 
 import (
 	"math/rand"
+	"reflect"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/cgrates/cgrates/config"
 	"github.com/cgrates/cgrates/utils"
 )
 
@@ -190,3 +193,76 @@ func BenchmarkGet(b *testing.B) {
 		cache.Get(cacheItems[rand.Intn(max-min)+min][0])
 	}
 }
+
+func TestCacheStoreMetrics(t *testing.T) {
+	cs := newLRUTTL(config.CacheConfig{
+		utils.CacheDestinations: &config.CacheParamConfig{Limit: 2},
+	})
+	cs.Put(utils.DESTINATION_PREFIX+"DST1", "1")
+	cs.Put(utils.DESTINATION_PREFIX+"DST2", "2")
+	if updated := cs.Put(utils.DESTINATION_PREFIX+"DST2", "2"); !updated {
+		t.Error("expecting item to be updated")
+	}
+	cs.Put(utils.DESTINATION_PREFIX+"DST3", "3") // evicts DST1
+	cs.Get(utils.DESTINATION_PREFIX + "DST3")
+	cs.Get(utils.DESTINATION_PREFIX + "DST1")
+	cs.Delete(utils.DESTINATION_PREFIX + "DST2") // removed on purpose, not an eviction
+	cs.Get("xxx_key")
+	eMtrcs := map[string]*utils.CachePartitionMetrics{
+		utils.META_ANY:          &utils.CachePartitionMetrics{Misses: 1},
+		utils.CacheDestinations: &utils.CachePartitionMetrics{Items: 1, Hits: 1, Misses: 1, Evictions: 1},
+	}
+	if mtrcs := cs.Metrics(); !reflect.DeepEqual(eMtrcs, mtrcs) {
+		t.Errorf("expecting: %s, received: %s", utils.ToJSON(eMtrcs), utils.ToJSON(mtrcs))
+	}
+}
+
+func TestCacheStoreEvictionReason(t *testing.T) {
+	ci := newLRUTTLInstance(utils.CacheDestinations, 10, time.Minute, false)
+	ci.set("DST1", "1")
+	ci.set("DST2", "2")
+	ci.set("DST3", "3")
+	ci.itmsMux.RLock()
+	expired, evicted, replaced := ci.items["DST1"], ci.items["DST2"], ci.items["DST3"]
+	ci.itmsMux.RUnlock()
+	atomic.StoreInt64(&expired.expires, time.Now().Add(-time.Second).UnixNano())
+	ci.onEvicted("DST1", expired) // dropped by ltcache after TTL
+	ci.onEvicted("DST2", evicted) // dropped by ltcache because of the limit
+	ci.set("DST3", "3")
+	ci.onEvicted("DST3", replaced) // previous value, not counted
+	if expiries, evictions := atomic.LoadInt64(&ci.metrics.expiries),
+		atomic.LoadInt64(&ci.metrics.evictions); expiries != 1 || evictions != 1 {
+		t.Errorf("expiries: %d, evictions: %d", expiries, evictions)
+	}
+	if updated := ci.set("DST1", "1"); updated {
+		t.Error("evicted item should not be seen as cached")
+	}
+}
+
+func TestCacheReloadKeepsItems(t *testing.T) {
+	cs := newLRUTTL(config.CacheConfig{
+		utils.CacheDestinations: &config.CacheParamConfig{Limit: 3},
+	})
+	cs.Put(utils.DESTINATION_PREFIX+"DST1", "1")
+	cs.Put(utils.DESTINATION_PREFIX+"DST2", "2")
+	cs.Get(utils.DESTINATION_PREFIX + "DST1")
+	cs = cs.reload(config.CacheConfig{
+		utils.CacheDestinations: &config.CacheParamConfig{Limit: 10},
+	})
+	for _, itmID := range []string{"DST1", "DST2"} {
+		if _, has := cs.Get(utils.DESTINATION_PREFIX + itmID); !has {
+			t.Errorf("item %s lost on reload", itmID)
+		}
+	}
+	if hits := cs.Metrics()[utils.CacheDestinations].Hits; hits != 3 {
+		t.Errorf("metrics not kept on reload, hits: %d", hits)
+	}
+}
+
+func TestCachePrecacheStatus(t *testing.T) {
+	SetPrecacheStatus(utils.CacheDestinations, utils.MetaLoading)
+	SetPrecacheStatus(utils.CacheDestinations, utils.MetaReady)
+	if status := GetPrecacheStatus(); status[utils.CacheDestinations] != utils.MetaReady {
+		t.Errorf("received: %+v", status)
+	}
+}
@@ -0,0 +1,112 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package cache
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/cgrates/cgrates/config"
+	"github.com/cgrates/cgrates/utils"
+	"github.com/cgrates/rpcclient"
+)
+
+var (
+	replPartitions utils.StringMap               // prefixes of the partitions marked with replicate, protected by cacheMux
+	replConn       rpcclient.RpcClientConnection // towards the peer engines, nil disables replication
+	replConnMux    sync.RWMutex                  // protects replConn
+)
+
+// ReplicationItem is a cache change broadcast to the peer engines.
+// Writes over cached keys travel as removals so the peers load the new value out of DataDB on next access
+type ReplicationItem struct {
+	Verb string // REM or REM_PREFIX
+	Key  string
+}
+
+// ArgsReplicateItems is passed to CacheSv1.ReplicateItems
+type ArgsReplicateItems struct {
+	Items []*ReplicationItem
+}
+
+// SetReplicationConn sets the connection towards the peer engines, nil to disable replication
+func SetReplicationConn(conn rpcclient.RpcClientConnection) {
+	replConnMux.Lock()
+	replConn = conn
+	replConnMux.Unlock()
+}
+
+// replicatedPartitions returns the prefixes of the partitions marked with replicate in cacheCfg
+func replicatedPartitions(cacheCfg config.CacheConfig) (prfxs utils.StringMap) {
+	prfxs = make(utils.StringMap)
+	for cfgKey, prtCfg := range cacheCfg {
+		if !prtCfg.Replicate {
+			continue
+		}
+		if prefixKey, has := utils.CacheInstanceToPrefix[cfgKey]; has {
+			cfgKey = prefixKey
+		}
+		prfxs[cfgKey] = true
+	}
+	return
+}
+
+// appendReplicated adds the item to items if its partition is replicated, cacheMux should be locked
+func appendReplicated(items []*ReplicationItem, verb, key string) []*ReplicationItem {
+	if len(key) < PREFIX_LEN || !replPartitions[key[:PREFIX_LEN]] {
+		return items
+	}
+	return append(items, &ReplicationItem{Verb: verb, Key: key})
+}
+
+// replicateItems broadcasts items towards the peer engines.
+// Asynchronous since the removals can be applied in any order
+func replicateItems(items []*ReplicationItem) {
+	if len(items) == 0 {
+		return
+	}
+	replConnMux.RLock()
+	conn := replConn
+	replConnMux.RUnlock()
+	if conn == nil {
+		return
+	}
+	go func() {
+		var reply string
+		if err := conn.Call(utils.CacheSv1ReplicateItems,
+			ArgsReplicateItems{Items: items}, &reply); err != nil {
+			utils.Logger.Warning(fmt.Sprintf("<%s> failed replicating %d items, error: %s",
+				utils.Cache, len(items), err.Error()))
+		}
+	}()
+}
+
+// ApplyReplicatedItems removes the items received from a peer engine, without replicating them further
+func ApplyReplicatedItems(items []*ReplicationItem) {
+	cacheMux.Lock()
+	defer cacheMux.Unlock()
+	for _, item := range items {
+		switch item.Verb {
+		case REM:
+			cache.Delete(item.Key)
+		case REM_PREFIX:
+			cache.DeletePrefix(item.Key)
+		}
+	}
+}
@@ -0,0 +1,92 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package cache
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/cgrates/cgrates/config"
+	"github.com/cgrates/cgrates/utils"
+)
+
+// replRecorder captures the items sent towards peers
+type replRecorder chan []*ReplicationItem
+
+func (rr replRecorder) Call(serviceMethod string, args interface{}, reply interface{}) error {
+	rr <- args.(ArgsReplicateItems).Items
+	*reply.(*string) = utils.OK
+	return nil
+}
+
+func (rr replRecorder) expect(t *testing.T, eItems []*ReplicationItem) {
+	select {
+	case items := <-rr:
+		if eItems == nil {
+			t.Errorf("Unexpected replication: %s", utils.ToJSON(items))
+		} else if !reflect.DeepEqual(eItems, items) {
+			t.Errorf("Expecting: %s, received: %s", utils.ToJSON(eItems), utils.ToJSON(items))
+		}
+	case <-time.After(50 * time.Millisecond):
+		if eItems != nil {
+			t.Errorf("Expecting replication of: %s", utils.ToJSON(eItems))
+		}
+	}
+}
+
+func TestCacheReplication(t *testing.T) {
+	dfCfg, _ := config.NewDefaultCGRConfig()
+	defer func() {
+		SetReplicationConn(nil)
+		NewCache(dfCfg.CacheCfg())
+	}()
+	NewCache(config.CacheConfig{
+		utils.CacheDestinations: &config.CacheParamConfig{Limit: -1, Replicate: true},
+		utils.CacheRatingPlans:  &config.CacheParamConfig{Limit: -1}})
+	rr := make(replRecorder, 1)
+	SetReplicationConn(rr)
+	dstKey := utils.DESTINATION_PREFIX + "DST_1"
+	Set(dstKey, "dst1", true, "") // first caching stays local
+	rr.expect(t, nil)
+	Set(dstKey, "dst1_new", true, "") // update invalidates peers
+	rr.expect(t, []*ReplicationItem{{Verb: REM, Key: dstKey}})
+	RemKey(dstKey, true, "")
+	rr.expect(t, []*ReplicationItem{{Verb: REM, Key: dstKey}})
+	Set(utils.RATING_PLAN_PREFIX+"RP_1", "rp1", true, "")
+	RemKey(utils.RATING_PLAN_PREFIX+"RP_1", true, "") // partition not replicated
+	rr.expect(t, nil)
+	// transactions replicate on commit, in one batch
+	Set(dstKey, "dst1", true, "")
+	transID := BeginTransaction()
+	Set(dstKey, "dst1_new", false, transID)
+	RemPrefixKey(utils.DESTINATION_PREFIX, false, transID)
+	rr.expect(t, nil)
+	CommitTransaction(transID)
+	rr.expect(t, []*ReplicationItem{
+		{Verb: REM, Key: dstKey},
+		{Verb: REM_PREFIX, Key: utils.DESTINATION_PREFIX}})
+	// items received from peers are not replicated further
+	Set(dstKey, "dst1", true, "")
+	ApplyReplicatedItems([]*ReplicationItem{{Verb: REM, Key: dstKey}})
+	if _, has := Get(dstKey); has {
+		t.Error("Replicated removal not applied")
+	}
+	rr.expect(t, nil)
+}
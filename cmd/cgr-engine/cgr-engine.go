@@ -164,6 +164,7 @@ func startSmGeneric(internalSMGChan, internalRaterChan,
 	if err = sm.Connect(); err != nil {
 		utils.Logger.Err(fmt.Sprintf("<SMGeneric> error: %s!", err))
 	}
+	utils.RegisterMetricsCollector(utils.SMG, sm.PromMetrics)
 	// Pass internal connection via BiRPCClient
 	internalSMGChan <- sm
 	// Register RPC handler
@@ -181,7 +182,8 @@ func startSmGeneric(internalSMGChan, internalRaterChan,
 	}
 }
 
-func startSMAsterisk(internalSMGChan chan rpcclient.RpcClientConnection, exitChan chan bool) {
+func startSMAsterisk(internalSMGChan chan rpcclient.RpcClientConnection,
+	srvManager *servmanager.ServiceManager, exitChan chan bool) {
 	utils.Logger.Info("Starting CGRateS SMAsterisk service.")
 	/*
 		var smgConn *rpcclient.RpcClientPool
@@ -198,6 +200,7 @@ func startSMAsterisk(internalSMGChan chan rpcclient.RpcClientConnection, exitCha
 	smgRpcConn := <-internalSMGChan
 	internalSMGChan <- smgRpcConn
 	birpcClnt := utils.NewBiRPCInternalClient(smgRpcConn.(*sessionmanager.SMGeneric))
+	srvManager.SetServiceStarted(utils.SMAsteriskS, nil)
 	for connIdx := range cfg.SMAsteriskCfg().AsteriskConns { // Instantiate connections towards asterisk servers
 		smgRpcConn := <-internalSMGChan
 		internalSMGChan <- smgRpcConn
@@ -214,11 +217,18 @@ func startSMAsterisk(internalSMGChan chan rpcclient.RpcClientConnection, exitCha
 	exitChan <- true
 }
 
-func startDiameterAgent(internalSMGChan, internalPubSubSChan chan rpcclient.RpcClientConnection, exitChan chan bool) {
+func startDiameterAgent(internalSMGChan, internalPubSubSChan chan rpcclient.RpcClientConnection,
+	srvManager *servmanager.ServiceManager, exitChan chan bool) {
 	var err error
 	utils.Logger.Info("Starting CGRateS DiameterAgent service")
-	var smgConn, pubsubConn *rpcclient.RpcClientPool
-	if len(cfg.DiameterAgentCfg().SMGenericConns) != 0 {
+	var smgConn rpcclient.RpcClientConnection
+	var pubsubConn *rpcclient.RpcClientPool
+	if len(cfg.DiameterAgentCfg().SMGenericConns) == 1 &&
+		cfg.DiameterAgentCfg().SMGenericConns[0].Address == utils.MetaInternal { // use BiRPC so we can receive the disconnects and re-auths
+		smgRpcConn := <-internalSMGChan
+		internalSMGChan <- smgRpcConn
+		smgConn = utils.NewBiRPCInternalClient(smgRpcConn.(*sessionmanager.SMGeneric))
+	} else if len(cfg.DiameterAgentCfg().SMGenericConns) != 0 {
 		smgConn, err = engine.NewRPCPool(rpcclient.POOL_FIRST, cfg.ConnectAttempts, cfg.Reconnects, cfg.ConnectTimeout, cfg.ReplyTimeout,
 			cfg.DiameterAgentCfg().SMGenericConns, internalSMGChan, cfg.InternalTtl)
 		if err != nil {
@@ -226,6 +236,7 @@ func startDiameterAgent(internalSMGChan, internalPubSubSChan chan rpcclient.RpcC
 			exitChan <- true
 			return
 		}
+		utils.Logger.Warning("<DiameterAgent> SMG connection is not a single *internal one, ASR/RAR towards peers are disabled")
 	}
 	if len(cfg.DiameterAgentCfg().PubSubConns) != 0 {
 		pubsubConn, err = engine.NewRPCPool(rpcclient.POOL_FIRST, cfg.ConnectAttempts, cfg.Reconnects, cfg.ConnectTimeout, cfg.ReplyTimeout,
@@ -242,17 +253,24 @@ func startDiameterAgent(internalSMGChan, internalPubSubSChan chan rpcclient.RpcC
 		exitChan <- true
 		return
 	}
+	srvManager.SetServiceStarted(utils.DiameterAgent, nil)
 	if err = da.ListenAndServe(); err != nil {
 		utils.Logger.Err(fmt.Sprintf("<DiameterAgent> error: %s!", err))
 	}
 	exitChan <- true
 }
 
-func startRadiusAgent(internalSMGChan chan rpcclient.RpcClientConnection, exitChan chan bool) {
+func startRadiusAgent(internalSMGChan chan rpcclient.RpcClientConnection,
+	srvManager *servmanager.ServiceManager, exitChan chan bool) {
 	var err error
 	utils.Logger.Info("Starting CGRateS RadiusAgent service")
-	var smgConn *rpcclient.RpcClientPool
-	if len(cfg.RadiusAgentCfg().SMGenericConns) != 0 {
+	var smgConn rpcclient.RpcClientConnection
+	if len(cfg.RadiusAgentCfg().SMGenericConns) == 1 &&
+		cfg.RadiusAgentCfg().SMGenericConns[0].Address == utils.MetaInternal { // use BiRPC so we can receive the disconnects and re-auths
+		smgRpcConn := <-internalSMGChan
+		internalSMGChan <- smgRpcConn
+		smgConn = utils.NewBiRPCInternalClient(smgRpcConn.(*sessionmanager.SMGeneric))
+	} else if len(cfg.RadiusAgentCfg().SMGenericConns) != 0 {
 		smgConn, err = engine.NewRPCPool(rpcclient.POOL_FIRST, cfg.ConnectAttempts,
 			cfg.Reconnects, cfg.ConnectTimeout, cfg.ReplyTimeout,
 			cfg.RadiusAgentCfg().SMGenericConns, internalSMGChan, cfg.InternalTtl)
@@ -268,13 +286,34 @@ func startRadiusAgent(internalSMGChan chan rpcclient.RpcClientConnection, exitCh
 		exitChan <- true
 		return
 	}
+	srvManager.SetServiceStarted(utils.RadiusAgent, nil)
 	if err = ra.ListenAndServe(); err != nil {
 		utils.Logger.Err(fmt.Sprintf("<RadiusAgent> error: <%s>", err.Error()))
 	}
 	exitChan <- true
 }
 
-func startSmFreeSWITCH(internalRaterChan, internalCDRSChan, rlsChan chan rpcclient.RpcClientConnection, cdrDb engine.CdrStorage, exitChan chan bool) {
+func startHttpAgent(internalSMGChan chan rpcclient.RpcClientConnection,
+	server *utils.Server, srvManager *servmanager.ServiceManager, exitChan chan bool) {
+	utils.Logger.Info("Starting CGRateS HttpAgent service")
+	var smgConn *rpcclient.RpcClientPool
+	if len(cfg.HttpAgentCfg().SMGenericConns) != 0 {
+		var err error
+		smgConn, err = engine.NewRPCPool(rpcclient.POOL_FIRST, cfg.ConnectAttempts,
+			cfg.Reconnects, cfg.ConnectTimeout, cfg.ReplyTimeout,
+			cfg.HttpAgentCfg().SMGenericConns, internalSMGChan, cfg.InternalTtl)
+		if err != nil {
+			utils.Logger.Crit(fmt.Sprintf("<HttpAgent> Could not connect to SMG: %s", err.Error()))
+			exitChan <- true
+			return
+		}
+	}
+	server.RegisterHttpFunc(cfg.HttpAgentCfg().Url, agents.NewHttpAgent(cfg, smgConn).ServeHTTP)
+	srvManager.SetServiceStarted(utils.HTTPAgent, nil)
+}
+
+func startSmFreeSWITCH(internalRaterChan, internalCDRSChan, rlsChan chan rpcclient.RpcClientConnection, cdrDb engine.CdrStorage,
+	srvManager *servmanager.ServiceManager, exitChan chan bool) {
 	var err error
 	utils.Logger.Info("Starting CGRateS SMFreeSWITCH service")
 	var ralsConn, cdrsConn, rlsConn *rpcclient.RpcClientPool
@@ -307,13 +346,15 @@ func startSmFreeSWITCH(internalRaterChan, internalCDRSChan, rlsChan chan rpcclie
 	}
 	sm := sessionmanager.NewFSSessionManager(cfg.SmFsConfig, ralsConn, cdrsConn, rlsConn, cfg.DefaultTimezone)
 	smRpc.SMs = append(smRpc.SMs, sm)
+	srvManager.SetServiceStarted(utils.SMFreeSWITCH, nil)
 	if err = sm.Connect(); err != nil {
 		utils.Logger.Err(fmt.Sprintf("<SMFreeSWITCH> error: %s!", err))
 	}
 	exitChan <- true
 }
 
-func startSmKamailio(internalRaterChan, internalCDRSChan, internalRsChan chan rpcclient.RpcClientConnection, cdrDb engine.CdrStorage, exitChan chan bool) {
+func startSmKamailio(internalRaterChan, internalCDRSChan, internalRsChan chan rpcclient.RpcClientConnection, cdrDb engine.CdrStorage,
+	srvManager *servmanager.ServiceManager, exitChan chan bool) {
 	var err error
 	utils.Logger.Info("Starting CGRateS SMKamailio service.")
 	var ralsConn, cdrsConn, rlSConn *rpcclient.RpcClientPool
@@ -346,13 +387,15 @@ func startSmKamailio(internalRaterChan, internalCDRSChan, internalRsChan chan rp
 	}
 	sm, _ := sessionmanager.NewKamailioSessionManager(cfg.SmKamConfig, ralsConn, cdrsConn, rlSConn, cfg.DefaultTimezone)
 	smRpc.SMs = append(smRpc.SMs, sm)
+	srvManager.SetServiceStarted(utils.SMKamailio, nil)
 	if err = sm.Connect(); err != nil {
 		utils.Logger.Err(fmt.Sprintf("<SMKamailio> error: %s!", err))
 	}
 	exitChan <- true
 }
 
-func startSmOpenSIPS(internalRaterChan, internalCDRSChan chan rpcclient.RpcClientConnection, cdrDb engine.CdrStorage, exitChan chan bool) {
+func startSmOpenSIPS(internalRaterChan, internalCDRSChan chan rpcclient.RpcClientConnection, cdrDb engine.CdrStorage,
+	srvManager *servmanager.ServiceManager, exitChan chan bool) {
 	var err error
 	utils.Logger.Info("Starting CGRateS SMOpenSIPS service.")
 	var ralsConn, cdrsConn *rpcclient.RpcClientPool
@@ -376,6 +419,7 @@ func startSmOpenSIPS(internalRaterChan, internalCDRSChan chan rpcclient.RpcClien
 	}
 	sm, _ := sessionmanager.NewOSipsSessionManager(cfg.SmOsipsConfig, cfg.Reconnects, ralsConn, cdrsConn, cfg.DefaultTimezone)
 	smRpc.SMs = append(smRpc.SMs, sm)
+	srvManager.SetServiceStarted(utils.SMOpenSIPS, nil)
 	if err := sm.Connect(); err != nil {
 		utils.Logger.Err(fmt.Sprintf("<SM-OpenSIPS> error: %s!", err))
 	}
@@ -386,9 +430,11 @@ func startCDRS(internalCdrSChan chan rpcclient.RpcClientConnection,
 	cdrDb engine.CdrStorage, dm *engine.DataManager,
 	internalRaterChan, internalPubSubSChan, internalAttributeSChan, internalUserSChan, internalAliaseSChan,
 	internalCdrStatSChan, internalThresholdSChan, internalStatSChan chan rpcclient.RpcClientConnection,
-	server *utils.Server, exitChan chan bool) {
+	server *utils.Server, exitChan chan bool, filterSChan chan *engine.FilterS) {
 	var err error
 	utils.Logger.Info("Starting CGRateS CDRS service.")
+	filterS := <-filterSChan
+	filterSChan <- filterS
 	var ralConn, pubSubConn, usersConn, attrSConn, aliasesConn, cdrstatsConn, thresholdSConn, statsConn *rpcclient.RpcClientPool
 	if len(cfg.CDRSRaterConns) != 0 { // Conn pool towards RAL
 		ralConn, err = engine.NewRPCPool(rpcclient.POOL_FIRST, cfg.ConnectAttempts, cfg.Reconnects, cfg.ConnectTimeout, cfg.ReplyTimeout,
@@ -464,8 +510,9 @@ func startCDRS(internalCdrSChan chan rpcclient.RpcClientConnection,
 		}
 	}
 	cdrServer, _ := engine.NewCdrServer(cfg, cdrDb, dm, ralConn, pubSubConn,
-		attrSConn, usersConn, aliasesConn, cdrstatsConn, thresholdSConn, statsConn)
+		attrSConn, usersConn, aliasesConn, cdrstatsConn, thresholdSConn, statsConn, filterS)
 	cdrServer.SetTimeToLive(cfg.ResponseCacheTTL, nil)
+	utils.RegisterMetricsCollector(utils.CDRs, cdrServer.PromMetrics)
 	utils.Logger.Info("Registering CDRS HTTP Handlers.")
 	cdrServer.RegisterHandlersToServer(server)
 	utils.Logger.Info("Registering CDRS RPC service.")
@@ -546,7 +593,8 @@ func startUsersServer(internalUserSChan chan rpcclient.RpcClientConnection, dm *
 
 // startAttributeService fires up the AttributeS
 func startAttributeService(internalAttributeSChan chan rpcclient.RpcClientConnection, cfg *config.CGRConfig,
-	dm *engine.DataManager, server *utils.Server, exitChan chan bool, filterSChan chan *engine.FilterS) {
+	dm *engine.DataManager, server *utils.Server, srvManager *servmanager.ServiceManager,
+	exitChan chan bool, filterSChan chan *engine.FilterS) {
 	filterS := <-filterSChan
 	filterSChan <- filterS
 	aS, err := engine.NewAttributeService(dm, filterS, cfg.AttributeSCfg().IndexedFields)
@@ -564,6 +612,7 @@ func startAttributeService(internalAttributeSChan chan rpcclient.RpcClientConnec
 		return
 	}()
 	aSv1 := v1.NewAttributeSv1(aS)
+	srvManager.RegisterConfigReloader(config.ATTRIBUTE_JSN, aS)
 	server.RpcRegister(aSv1)
 	internalAttributeSChan <- aSv1
 }
@@ -589,6 +638,7 @@ func startResourceService(internalRsChan, internalThresholdSChan chan rpcclient.
 		exitChan <- true
 		return
 	}
+	utils.RegisterMetricsCollector(utils.ResourceS, rS.PromMetrics)
 	utils.Logger.Info(fmt.Sprintf("Starting Resource Service"))
 	go func() {
 		if err := rS.ListenAndServe(exitChan); err != nil {
@@ -606,7 +656,8 @@ func startResourceService(internalRsChan, internalThresholdSChan chan rpcclient.
 
 // startStatService fires up the StatS
 func startStatService(internalStatSChan, internalThresholdSChan chan rpcclient.RpcClientConnection, cfg *config.CGRConfig,
-	dm *engine.DataManager, server *utils.Server, exitChan chan bool, filterSChan chan *engine.FilterS) {
+	dm *engine.DataManager, server *utils.Server, srvManager *servmanager.ServiceManager,
+	exitChan chan bool, filterSChan chan *engine.FilterS) {
 	var err error
 	var thdSConn *rpcclient.RpcClientPool
 	filterS := <-filterSChan
@@ -626,6 +677,7 @@ func startStatService(internalStatSChan, internalThresholdSChan chan rpcclient.R
 		exitChan <- true
 		return
 	}
+	utils.RegisterMetricsCollector(utils.StatS, sS.PromMetrics)
 	utils.Logger.Info(fmt.Sprintf("Starting Stat Service"))
 	go func() {
 		if err := sS.ListenAndServe(exitChan); err != nil {
@@ -636,17 +688,30 @@ func startStatService(internalStatSChan, internalThresholdSChan chan rpcclient.R
 		return
 	}()
 	stsV1 := v1.NewStatSv1(sS)
+	srvManager.RegisterConfigReloader(config.STATS_JSON, sS)
 	server.RpcRegister(stsV1)
 	internalStatSChan <- stsV1
 }
 
+// startCacheReplication connects to the peer engines receiving the changes of replicated cache partitions
+func startCacheReplication() {
+	replConn, err := engine.NewRPCPool(rpcclient.POOL_BROADCAST, cfg.ConnectAttempts, cfg.Reconnects,
+		cfg.ConnectTimeout, cfg.ReplyTimeout, cfg.CacheReplicationConns, nil, cfg.InternalTtl)
+	if err != nil { // peers might not be started yet, connections are retried on first replication
+		utils.Logger.Warning(fmt.Sprintf("<%s> could not connect to replication peers, error: %s",
+			utils.Cache, err.Error()))
+	}
+	cache.SetReplicationConn(replConn)
+}
+
 // startThresholdService fires up the ThresholdS
-func startThresholdService(internalThresholdSChan chan rpcclient.RpcClientConnection, cfg *config.CGRConfig,
-	dm *engine.DataManager, server *utils.Server, exitChan chan bool, filterSChan chan *engine.FilterS) {
+func startThresholdService(internalThresholdSChan, internalStatSChan, internalRsChan chan rpcclient.RpcClientConnection,
+	cfg *config.CGRConfig, dm *engine.DataManager, server *utils.Server, srvManager *servmanager.ServiceManager,
+	exitChan chan bool, filterSChan chan *engine.FilterS) {
 	filterS := <-filterSChan
 	filterSChan <- filterS
 	tS, err := engine.NewThresholdService(dm, cfg.ThresholdSCfg().IndexedFields,
-		cfg.ThresholdSCfg().StoreInterval, filterS)
+		cfg.ThresholdSCfg().StoreInterval, filterS, nil, nil)
 	if err != nil {
 		utils.Logger.Crit(fmt.Sprintf("<ThresholdS> Could not init, error: %s", err.Error()))
 		exitChan <- true
@@ -662,13 +727,40 @@ func startThresholdService(internalThresholdSChan chan rpcclient.RpcClientConnec
 		return
 	}()
 	tSv1 := v1.NewThresholdSv1(tS)
+	srvManager.RegisterConfigReloader(config.THRESHOLDS_JSON, tS)
 	server.RpcRegister(tSv1)
 	internalThresholdSChan <- tSv1
+	// StatS and ResourceS can connect to ThresholdS, connect back to them only after ThresholdS is available
+	if len(cfg.ThresholdSCfg().StatSConns) != 0 {
+		statSConn, err := engine.NewRPCPool(rpcclient.POOL_FIRST,
+			cfg.ConnectAttempts, cfg.Reconnects,
+			cfg.ConnectTimeout, cfg.ReplyTimeout, cfg.ThresholdSCfg().StatSConns,
+			internalStatSChan, cfg.InternalTtl)
+		if err != nil {
+			utils.Logger.Crit(fmt.Sprintf("<ThresholdS> Could not connect to StatS: %s", err.Error()))
+			exitChan <- true
+			return
+		}
+		tS.SetStatSConnection(statSConn)
+	}
+	if len(cfg.ThresholdSCfg().ResourceSConns) != 0 {
+		resourceSConn, err := engine.NewRPCPool(rpcclient.POOL_FIRST,
+			cfg.ConnectAttempts, cfg.Reconnects,
+			cfg.ConnectTimeout, cfg.ReplyTimeout, cfg.ThresholdSCfg().ResourceSConns,
+			internalRsChan, cfg.InternalTtl)
+		if err != nil {
+			utils.Logger.Crit(fmt.Sprintf("<ThresholdS> Could not connect to ResourceS: %s", err.Error()))
+			exitChan <- true
+			return
+		}
+		tS.SetResourceSConnection(resourceSConn)
+	}
 }
 
 // startSupplierService fires up the ThresholdS
 func startSupplierService(internalSupplierSChan, internalRsChan, internalStatSChan chan rpcclient.RpcClientConnection,
-	cfg *config.CGRConfig, dm *engine.DataManager, server *utils.Server, exitChan chan bool, filterSChan chan *engine.FilterS) {
+	cfg *config.CGRConfig, dm *engine.DataManager, server *utils.Server, srvManager *servmanager.ServiceManager,
+	exitChan chan bool, filterSChan chan *engine.FilterS) {
 	var err error
 	filterS := <-filterSChan
 	filterSChan <- filterS
@@ -715,6 +807,7 @@ func startSupplierService(internalSupplierSChan, internalRsChan, internalStatSCh
 		return
 	}()
 	splV1 := v1.NewSupplierSv1(splS)
+	srvManager.RegisterConfigReloader(config.SupplierSJson, splS)
 	server.RpcRegister(splV1)
 	internalSupplierSChan <- splV1
 }
@@ -759,6 +852,7 @@ func startRpc(server *utils.Server, internalRaterChan,
 		cfg.HTTPListen,
 		cfg.HTTPJsonRPCURL,
 		cfg.HTTPWSURL,
+		cfg.HTTPMetricsURL,
 		cfg.HTTPUseBasicAuth,
 		cfg.HTTPAuthUsers,
 	)
@@ -841,6 +935,7 @@ func main() {
 		lgLevel = *logLevel
 	}
 	utils.Logger.SetLogLevel(lgLevel)
+	utils.Logger.SetLogFormat(cfg.LogFormat)
 
 	var loadDb engine.LoadStorage
 	var cdrDb engine.CdrStorage
@@ -909,10 +1004,30 @@ func main() {
 	filterSChan := make(chan *engine.FilterS, 1)
 
 	// Start ServiceManager
-	srvManager := servmanager.NewServiceManager(cfg, dm, exitChan, cacheDoneChan)
+	srvManager := servmanager.NewServiceManager(cfg, dm, loadDb, exitChan, cacheDoneChan)
+	server.RpcRegister(v1.NewConfigSv1(cfg, srvManager))
+	server.RpcRegister(v1.NewCoreSv1(srvManager))
+	if cfg.HTTPHealthURL != "" {
+		server.RegisterHttpFunc(cfg.HTTPHealthURL, srvManager.HealthHandler)
+		server.RegisterHttpFunc(cfg.HTTPHealthURL+"/live", srvManager.LivenessHandler)
+		server.RegisterHttpFunc(cfg.HTTPHealthURL+"/ready", srvManager.ReadinessHandler)
+	}
+	server.RpcRegister(v1.NewCacheSv1())
+	utils.RegisterMetricsCollector(utils.Cache, cache.PromMetrics)
+	utils.RegisterMetricsCollector(utils.ServiceManager, srvManager.PromMetrics)
+
+	// Replicate cache changes towards peer engines
+	if len(cfg.CacheReplicationConns) != 0 {
+		go startCacheReplication()
+	}
 
 	// Start rater service
 	if cfg.RALsEnabled {
+		srvManager.RegisterService(utils.RALService, internalRaterChan, map[string][]*config.HaPoolConfig{
+			utils.ThresholdS: cfg.RALsThresholdSConns, utils.CDRStatS: cfg.RALsCDRStatSConns,
+			utils.StatService: cfg.RALsStatSConns, utils.HistoryS: cfg.RALsHistorySConns,
+			utils.PubSubS: cfg.RALsPubSubSConns, utils.AttributeS: cfg.RALsAttributeSConns,
+			utils.UserS: cfg.RALsUserSConns, utils.AliaseS: cfg.RALsAliasSConns})
 		go startRater(internalRaterChan, cacheDoneChan, internalThresholdSChan,
 			internalCdrStatSChan, internalStatSChan, internalHistorySChan,
 			internalPubSubSChan, internalAttributeSChan,
@@ -927,14 +1042,20 @@ func main() {
 
 	// Start CDR Server
 	if cfg.CDRSEnabled {
+		srvManager.RegisterService(utils.CDRs, internalCdrSChan, map[string][]*config.HaPoolConfig{
+			utils.RALService: cfg.CDRSRaterConns, utils.PubSubS: cfg.CDRSPubSubSConns,
+			utils.AttributeS: cfg.CDRSAttributeSConns, utils.UserS: cfg.CDRSUserSConns,
+			utils.AliaseS: cfg.CDRSAliaseSConns, utils.CDRStatS: cfg.CDRSCDRStatSConns,
+			utils.ThresholdS: cfg.CDRSThresholdSConns, utils.StatService: cfg.CDRSStatSConns})
 		go startCDRS(internalCdrSChan, cdrDb, dm,
 			internalRaterChan, internalPubSubSChan, internalAttributeSChan,
 			internalUserSChan, internalAliaseSChan, internalCdrStatSChan,
-			internalThresholdSChan, internalStatSChan, server, exitChan)
+			internalThresholdSChan, internalStatSChan, server, exitChan, filterSChan)
 	}
 
 	// Start CDR Stats server
 	if cfg.CDRStatsEnabled {
+		srvManager.RegisterService(utils.CDRStatS, internalCdrStatSChan, nil)
 		go startCdrStats(internalCdrStatSChan, dm, server)
 	}
 
@@ -943,23 +1064,34 @@ func main() {
 
 	// Start SM-Generic
 	if cfg.SmGenericConfig.Enabled {
+		srvManager.RegisterService(utils.SessionS, internalSMGChan, map[string][]*config.HaPoolConfig{
+			utils.RALService: cfg.SmGenericConfig.RALsConns, utils.CDRs: cfg.SmGenericConfig.CDRsConns,
+			utils.SessionS: cfg.SmGenericConfig.SMGReplicationConns})
 		go startSmGeneric(internalSMGChan, internalRaterChan, internalCdrSChan, server, exitChan)
 	}
 	// Start SM-FreeSWITCH
 	if cfg.SmFsConfig.Enabled {
-		go startSmFreeSWITCH(internalRaterChan, internalCdrSChan, internalRsChan, cdrDb, exitChan)
+		srvManager.RegisterService(utils.SMFreeSWITCH, nil, map[string][]*config.HaPoolConfig{
+			utils.RALService: cfg.SmFsConfig.RALsConns, utils.CDRs: cfg.SmFsConfig.CDRsConns,
+			utils.ResourceS: cfg.SmFsConfig.RLsConns})
+		go startSmFreeSWITCH(internalRaterChan, internalCdrSChan, internalRsChan, cdrDb, srvManager, exitChan)
 		// close all sessions on shutdown
 		go shutdownSessionmanagerSingnalHandler(exitChan)
 	}
 
 	// Start SM-Kamailio
 	if cfg.SmKamConfig.Enabled {
-		go startSmKamailio(internalRaterChan, internalCdrSChan, internalRsChan, cdrDb, exitChan)
+		srvManager.RegisterService(utils.SMKamailio, nil, map[string][]*config.HaPoolConfig{
+			utils.RALService: cfg.SmKamConfig.RALsConns, utils.CDRs: cfg.SmKamConfig.CDRsConns,
+			utils.ResourceS: cfg.SmKamConfig.RLsConns})
+		go startSmKamailio(internalRaterChan, internalCdrSChan, internalRsChan, cdrDb, srvManager, exitChan)
 	}
 
 	// Start SM-OpenSIPS
 	if cfg.SmOsipsConfig.Enabled {
-		go startSmOpenSIPS(internalRaterChan, internalCdrSChan, cdrDb, exitChan)
+		srvManager.RegisterService(utils.SMOpenSIPS, nil, map[string][]*config.HaPoolConfig{
+			utils.RALService: cfg.SmOsipsConfig.RALsConns, utils.CDRs: cfg.SmOsipsConfig.CDRsConns})
+		go startSmOpenSIPS(internalRaterChan, internalCdrSChan, cdrDb, srvManager, exitChan)
 	}
 
 	// Register session manager service // FixMe: make sure this is thread safe
@@ -969,60 +1101,87 @@ func main() {
 	}
 
 	if cfg.SMAsteriskCfg().Enabled {
-		go startSMAsterisk(internalSMGChan, exitChan)
+		srvManager.RegisterService(utils.SMAsteriskS, nil, map[string][]*config.HaPoolConfig{
+			utils.SessionS: cfg.SMAsteriskCfg().SMGConns})
+		go startSMAsterisk(internalSMGChan, srvManager, exitChan)
 	}
 
 	if cfg.DiameterAgentCfg().Enabled {
-		go startDiameterAgent(internalSMGChan, internalPubSubSChan, exitChan)
+		srvManager.RegisterService(utils.DiameterAgent, nil, map[string][]*config.HaPoolConfig{
+			utils.SessionS: cfg.DiameterAgentCfg().SMGenericConns, utils.PubSubS: cfg.DiameterAgentCfg().PubSubConns})
+		go startDiameterAgent(internalSMGChan, internalPubSubSChan, srvManager, exitChan)
 	}
 
 	if cfg.RadiusAgentCfg().Enabled {
-		go startRadiusAgent(internalSMGChan, exitChan)
+		srvManager.RegisterService(utils.RadiusAgent, nil, map[string][]*config.HaPoolConfig{
+			utils.SessionS: cfg.RadiusAgentCfg().SMGenericConns})
+		go startRadiusAgent(internalSMGChan, srvManager, exitChan)
+	}
+
+	if cfg.HttpAgentCfg().Enabled {
+		srvManager.RegisterService(utils.HTTPAgent, nil, map[string][]*config.HaPoolConfig{
+			utils.SessionS: cfg.HttpAgentCfg().SMGenericConns})
+		go startHttpAgent(internalSMGChan, server, srvManager, exitChan)
 	}
 
 	// Start HistoryS service
 	if cfg.HistoryServerEnabled {
+		srvManager.RegisterService(utils.HistoryS, internalHistorySChan, nil)
 		go startHistoryServer(internalHistorySChan, server, exitChan)
 	}
 
 	// Start PubSubS service
 	if cfg.PubSubServerEnabled {
+		srvManager.RegisterService(utils.PubSubS, internalPubSubSChan, nil)
 		go startPubSubServer(internalPubSubSChan, dm, server, exitChan)
 	}
 
 	// Start Aliases service
 	if cfg.AliasesServerEnabled {
+		srvManager.RegisterService(utils.AliaseS, internalAliaseSChan, nil)
 		go startAliasesServer(internalAliaseSChan, dm, server, exitChan)
 	}
 
 	// Start users service
 	if cfg.UserServerEnabled {
+		srvManager.RegisterService(utils.UserS, internalUserSChan, nil)
 		go startUsersServer(internalUserSChan, dm, server, exitChan)
 	}
 	// Start FilterS
 	go startFilterService(filterSChan, internalStatSChan, cfg, dm, exitChan)
 
 	if cfg.AttributeSCfg().Enabled {
-		go startAttributeService(internalAttributeSChan, cfg, dm, server, exitChan, filterSChan)
+		srvManager.RegisterService(utils.AttributeS, internalAttributeSChan, nil)
+		go startAttributeService(internalAttributeSChan, cfg, dm, server, srvManager, exitChan, filterSChan)
 	}
 
 	// Start RL service
 	if cfg.ResourceSCfg().Enabled {
+		srvManager.RegisterService(utils.ResourceS, internalRsChan, map[string][]*config.HaPoolConfig{
+			utils.ThresholdS: cfg.ResourceSCfg().ThresholdSConns})
 		go startResourceService(internalRsChan,
 			internalThresholdSChan, cfg, dm, server, exitChan, filterSChan)
 	}
 
 	if cfg.StatSCfg().Enabled {
-		go startStatService(internalStatSChan, internalThresholdSChan, cfg, dm, server, exitChan, filterSChan)
+		srvManager.RegisterService(utils.StatService, internalStatSChan, map[string][]*config.HaPoolConfig{
+			utils.ThresholdS: cfg.StatSCfg().ThresholdSConns})
+		go startStatService(internalStatSChan, internalThresholdSChan, cfg, dm, server, srvManager, exitChan, filterSChan)
 	}
 
 	if cfg.ThresholdSCfg().Enabled {
-		go startThresholdService(internalThresholdSChan, cfg, dm, server, exitChan, filterSChan)
+		srvManager.RegisterService(utils.ThresholdS, internalThresholdSChan, map[string][]*config.HaPoolConfig{
+			utils.StatService: cfg.ThresholdSCfg().StatSConns, utils.ResourceS: cfg.ThresholdSCfg().ResourceSConns})
+		go startThresholdService(internalThresholdSChan, internalStatSChan, internalRsChan,
+			cfg, dm, server, srvManager, exitChan, filterSChan)
 	}
 
 	if cfg.SupplierSCfg().Enabled {
+		srvManager.RegisterService(utils.SupplierS, internalSupplierSChan, map[string][]*config.HaPoolConfig{
+			utils.RALService: cfg.SupplierSCfg().RALsConns, utils.ResourceS: cfg.SupplierSCfg().ResourceSConns,
+			utils.StatService: cfg.SupplierSCfg().StatSConns})
 		go startSupplierService(internalSupplierSChan, internalRsChan, internalStatSChan,
-			cfg, dm, server, exitChan, filterSChan)
+			cfg, dm, server, srvManager, exitChan, filterSChan)
 	}
 
 	// Serve rpc connections
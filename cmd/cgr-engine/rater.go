@@ -272,6 +272,9 @@ func startRater(internalRaterChan chan rpcclient.RpcClientConnection, cacheDoneC
 	if thdS != nil {
 		engine.SetThresholdS(thdS) // temporary architectural fix until we will have separate AccountS
 	}
+	if cfg.RALsBalanceEvInterval > 0 {
+		go engine.NewBalanceExpiryWatcher(dm, cfg.RALsBalanceEvInterval, cfg.RALsBalanceLeadTimes).ListenAndServe()
+	}
 	if attrS != nil {
 		responder.AttributeS = attrS
 	}
@@ -152,6 +152,9 @@ func main() {
 			path.Join(*dataPath, utils.FiltersCsv),
 			path.Join(*dataPath, utils.SuppliersCsv),
 			path.Join(*dataPath, utils.AttributesCsv),
+			path.Join(*dataPath, utils.ExchangeRatesCsv),
+			path.Join(*dataPath, utils.TaxProfilesCsv),
+			path.Join(*dataPath, utils.FeeProfilesCsv),
 		)
 	}
 
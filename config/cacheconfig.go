@@ -29,6 +29,7 @@ type CacheParamConfig struct {
 	TTL       time.Duration
 	StaticTTL bool
 	Precache  bool
+	Replicate bool // broadcast changes to the cache_replication peers
 }
 
 func (self *CacheParamConfig) loadFromJsonCfg(jsnCfg *CacheParamJsonCfg) error {
@@ -50,6 +51,9 @@ func (self *CacheParamConfig) loadFromJsonCfg(jsnCfg *CacheParamJsonCfg) error {
 	if jsnCfg.Precache != nil {
 		self.Precache = *jsnCfg.Precache
 	}
+	if jsnCfg.Replicate != nil {
+		self.Replicate = *jsnCfg.Replicate
+	}
 	return nil
 }
 
@@ -25,6 +25,7 @@ import (
 	"path/filepath"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/cgrates/cgrates/utils"
@@ -76,6 +77,7 @@ func NewDefaultCGRConfig() (*CGRConfig, error) {
 	cfg.smAsteriskCfg = new(SMAsteriskCfg)
 	cfg.diameterAgentCfg = new(DiameterAgentCfg)
 	cfg.radiusAgentCfg = new(RadiusAgentCfg)
+	cfg.httpAgentCfg = new(HttpAgentCfg)
 	cfg.filterSCfg = new(FilterSCfg)
 	cfg.ConfigReloads = make(map[string]chan struct{})
 	cfg.ConfigReloads[utils.CDRC] = make(chan struct{}, 1)
@@ -175,6 +177,7 @@ func NewCGRConfigFromFolder(cfgDir string) (*CGRConfig, error) {
 			return nil, fmt.Errorf("No config file found on path %s", cfgDir)
 		}
 	}
+	cfg.ConfigPath = cfgDir
 	if err := cfg.checkConfigSanity(); err != nil {
 		return nil, err
 	}
@@ -191,6 +194,8 @@ type CGRConfig struct {
 	DataDbUser               string // The user to sign in as.
 	DataDbPass               string // The user's password.
 	LoadHistorySize          int    // Maximum number of records to archive in load history
+	DataDbSnapshotPath       string // File used to persist the *internal data_db
+	DataDbSnapshotInterval   time.Duration
 	StorDBType               string // Should reflect the database type used to store logs
 	StorDBHost               string // The host to connect to. Values that start with / are for UNIX domain sockets.
 	StorDBPort               string // Th e port to bind to.
@@ -201,19 +206,25 @@ type CGRConfig struct {
 	StorDBMaxIdleConns       int    // Maximum idle connections to keep opened
 	StorDBConnMaxLifetime    int
 	StorDBCDRSIndexes        []string
+	StorDBSnapshotPath       string // File used to persist the *internal stor_db
+	StorDBSnapshotInterval   time.Duration
 	DBDataEncoding           string // The encoding used to store object data in strings: <msgpack|json>
 	cacheConfig              CacheConfig
+	CacheReplicationConns    []*HaPoolConfig   // peer engines receiving the changes of replicated cache partitions
 	RPCJSONListen            string            // RPC JSON listening address
 	RPCGOBListen             string            // RPC GOB listening address
 	HTTPListen               string            // HTTP listening address
 	HTTPJsonRPCURL           string            // JSON RPC relative URL ("" to disable)
 	HTTPWSURL                string            // WebSocket relative URL ("" to disable)
+	HTTPMetricsURL           string            // Prometheus metrics relative URL ("" to disable)
+	HTTPHealthURL            string            // health report relative URL, liveness and readiness under it ("" to disable)
 	HTTPUseBasicAuth         bool              // Use basic auth for HTTP API
 	HTTPAuthUsers            map[string]string // Basic auth user:password map (base64 passwords)
 	DefaultReqType           string            // Use this request type if not defined on top
 	DefaultCategory          string            // set default type of record
 	DefaultTenant            string            // set default tenant
 	DefaultTimezone          string            // default timezone for timestamps where not specified <""|UTC|Local|$IANA_TZ_DB>
+	DefaultCurrency          string            // currency of the RatingPlans without one defined
 	Reconnects               int               // number of recconect attempts in case of connection lost <-1 for infinite | nb>
 	ConnectTimeout           time.Duration     // timeout for RPC connection attempts
 	ReplyTimeout             time.Duration     // timeout replies if not reaching back
@@ -229,6 +240,7 @@ type CGRConfig struct {
 	LockingTimeout           time.Duration   // locking mechanism timeout to avoid deadlocks
 	Logger                   string          // dictates the way logs are displayed/stored
 	LogLevel                 int             // system wide log level, nothing higher than this will be logged
+	LogFormat                string          // format of the log messages <*text|*json>
 	RALsEnabled              bool            // start standalone server (no balancer)
 	RALsThresholdSConns      []*HaPoolConfig // address where to reach ThresholdS config
 	RALsCDRStatSConns        []*HaPoolConfig // address where to reach the cdrstats service. Empty to disable stats gathering  <""|internal|x.y.z.y:1234>
@@ -241,12 +253,16 @@ type CGRConfig struct {
 	RpSubjectPrefixMatching  bool // enables prefix matching for the rating profile subject
 	LcrSubjectPrefixMatching bool // enables prefix matching for the lcr subject
 	RALsMaxComputedUsage     map[string]time.Duration
+	RALsBalanceEvInterval    time.Duration   // check the balances for expiry events, 0 to disable
+	RALsBalanceLeadTimes     []time.Duration // send BalanceExpiring events this long before the expiry
+	RALsBalanceEvPostURLs    []string        // post the balance events also to these addresses
 	SchedulerEnabled         bool
 	CDRSEnabled              bool              // Enable CDR Server service
 	CDRSExtraFields          []*utils.RSRField // Extra fields to store in CDRs
 	CDRSStoreCdrs            bool              // store cdrs in storDb
 	CDRScdrAccountSummary    bool
 	CDRSSMCostRetries        int
+	CDRSApplyTaxes           bool
 	CDRSRaterConns           []*HaPoolConfig // address where to reach the Rater for cost calculation: <""|internal|x.y.z.y:1234>
 	CDRSPubSubSConns         []*HaPoolConfig // address where to reach the pubsub service: <""|internal|x.y.z.y:1234>
 	CDRSAttributeSConns      []*HaPoolConfig // address where to reach the users service: <""|internal|x.y.z.y:1234>
@@ -267,6 +283,7 @@ type CGRConfig struct {
 	smAsteriskCfg            *SMAsteriskCfg           // SMAsterisk Configuration
 	diameterAgentCfg         *DiameterAgentCfg        // DiameterAgent configuration
 	radiusAgentCfg           *RadiusAgentCfg          // RadiusAgent configuration
+	httpAgentCfg             *HttpAgentCfg            // HttpAgent configuration
 	filterSCfg               *FilterSCfg              // FilterS configuration
 	HistoryServerEnabled     bool                     // Starts History as server: <true|false>.
 	HistoryDir               string                   // Location on disk where to store history files.
@@ -287,12 +304,26 @@ type CGRConfig struct {
 	DataFolderPath           string                   // Path towards data folder, for tests internal usage, not loading out of .json options
 	sureTaxCfg               *SureTaxCfg              // Load here SureTax configuration, as pointer so we can have runtime reloads in the future
 	ConfigReloads            map[string]chan struct{} // Signals to specific entities that a config reload should occur
+	ConfigPath               string                   // Folder the configuration was loaded from, reused on reloads
+	rawCfg                   map[string]interface{}   // Effective configuration in the layout of the .json files
+	rawCfgMux                sync.RWMutex             // protects rawCfg
+	cfgMux                   sync.RWMutex             // protects the sections swapped in on reloads
 	// Cache defaults loaded from json and needing clones
 	dfltCdreProfile *CdreConfig // Default cdreConfig profile
 	dfltCdrcProfile *CdrcConfig // Default cdrcConfig profile
 }
 
 func (self *CGRConfig) checkConfigSanity() error {
+	// General checks
+	if self.LogFormat != "" && !utils.IsSliceMember([]string{utils.MetaText, utils.MetaJSON}, self.LogFormat) {
+		return fmt.Errorf("<general> unsupported log_format: <%s>", self.LogFormat)
+	}
+	// Cache checks
+	for _, connCfg := range self.CacheReplicationConns {
+		if connCfg.Address == utils.MetaInternal {
+			return errors.New("<cache> replication_conns cannot point towards *internal")
+		}
+	}
 	// Rater checks
 	if self.RALsEnabled {
 		for _, connCfg := range self.RALsCDRStatSConns {
@@ -534,6 +565,29 @@ func (self *CGRConfig) checkConfigSanity() error {
 				return errors.New("SMGeneric not enabled but referenced by RadiusAgent component")
 			}
 		}
+		dynAuth := self.radiusAgentCfg.DynAuthAddress != ""
+		for _, reqProcessor := range self.radiusAgentCfg.RequestProcessors {
+			if reqProcessor.DynAuthAddress != "" {
+				dynAuth = true
+			}
+		}
+		if dynAuth && (len(self.radiusAgentCfg.SMGenericConns) != 1 ||
+			self.radiusAgentCfg.SMGenericConns[0].Address != utils.MetaInternal) {
+			return errors.New("RadiusAgent dynamic authorization requires a single *internal SMGeneric connection")
+		}
+	}
+	if self.httpAgentCfg.Enabled {
+		for _, haSMGConn := range self.httpAgentCfg.SMGenericConns {
+			if haSMGConn.Address == utils.MetaInternal && !self.SmGenericConfig.Enabled {
+				return errors.New("SMGeneric not enabled but referenced by HttpAgent component")
+			}
+		}
+		if !utils.IsSliceMember([]string{utils.MetaUrl, utils.MetaJSON}, self.httpAgentCfg.RequestPayload) {
+			return fmt.Errorf("<HttpAgent> unsupported request payload: <%s>", self.httpAgentCfg.RequestPayload)
+		}
+		if !utils.IsSliceMember([]string{utils.MetaUrl, utils.MetaJSON}, self.httpAgentCfg.ReplyPayload) {
+			return fmt.Errorf("<HttpAgent> unsupported reply payload: <%s>", self.httpAgentCfg.ReplyPayload)
+		}
 	}
 	// ResourceLimiter checks
 	if self.resourceSCfg != nil && self.resourceSCfg.Enabled {
@@ -551,6 +605,19 @@ func (self *CGRConfig) checkConfigSanity() error {
 			}
 		}
 	}
+	// ThresholdS checks
+	if self.thresholdSCfg != nil && self.thresholdSCfg.Enabled {
+		for _, connCfg := range self.thresholdSCfg.StatSConns {
+			if connCfg.Address == utils.MetaInternal && !self.statsCfg.Enabled {
+				return errors.New("StatS not enabled but requested by ThresholdS component.")
+			}
+		}
+		for _, connCfg := range self.thresholdSCfg.ResourceSConns {
+			if connCfg.Address == utils.MetaInternal && !self.resourceSCfg.Enabled {
+				return errors.New("ResourceS not enabled but requested by ThresholdS component.")
+			}
+		}
+	}
 	// SupplierS checks
 	if self.supplierSCfg != nil && self.supplierSCfg.Enabled {
 		for _, connCfg := range self.supplierSCfg.RALsConns {
@@ -578,6 +645,9 @@ func (self *CGRConfig) checkConfigSanity() error {
 
 // Loads from json configuration object, will be used for defaults, config from file and reload, might need lock
 func (self *CGRConfig) loadFromJsonCfg(jsnCfg *CgrJsonCfg) (err error) {
+	if err = self.mergeRawJSON(jsnCfg); err != nil {
+		return
+	}
 
 	// Load sections out of JSON config, stop on error
 	jsnGeneralCfg, err := jsnCfg.GeneralJsonCfg()
@@ -591,6 +661,11 @@ func (self *CGRConfig) loadFromJsonCfg(jsnCfg *CgrJsonCfg) (err error) {
 		return err
 	}
 
+	jsnCacheReplCfg, err := jsnCfg.CacheReplicationJsonCfg()
+	if err != nil {
+		return err
+	}
+
 	jsnListenCfg, err := jsnCfg.ListenJsonCfg()
 	if err != nil {
 		return err
@@ -681,6 +756,11 @@ func (self *CGRConfig) loadFromJsonCfg(jsnCfg *CgrJsonCfg) (err error) {
 		return err
 	}
 
+	jsnHttpAgntCfg, err := jsnCfg.HttpAgentJsonCfg()
+	if err != nil {
+		return err
+	}
+
 	jsnHistServCfg, err := jsnCfg.HistServJsonCfg()
 	if err != nil {
 		return err
@@ -758,6 +838,14 @@ func (self *CGRConfig) loadFromJsonCfg(jsnCfg *CgrJsonCfg) (err error) {
 		if jsnDataDbCfg.Load_history_size != nil {
 			self.LoadHistorySize = *jsnDataDbCfg.Load_history_size
 		}
+		if jsnDataDbCfg.Snapshot_path != nil {
+			self.DataDbSnapshotPath = *jsnDataDbCfg.Snapshot_path
+		}
+		if jsnDataDbCfg.Snapshot_interval != nil {
+			if self.DataDbSnapshotInterval, err = utils.ParseDurationWithNanosecs(*jsnDataDbCfg.Snapshot_interval); err != nil {
+				return err
+			}
+		}
 	}
 
 	if jsnStorDbCfg != nil {
@@ -791,6 +879,14 @@ func (self *CGRConfig) loadFromJsonCfg(jsnCfg *CgrJsonCfg) (err error) {
 		if jsnStorDbCfg.Cdrs_indexes != nil {
 			self.StorDBCDRSIndexes = *jsnStorDbCfg.Cdrs_indexes
 		}
+		if jsnStorDbCfg.Snapshot_path != nil {
+			self.StorDBSnapshotPath = *jsnStorDbCfg.Snapshot_path
+		}
+		if jsnStorDbCfg.Snapshot_interval != nil {
+			if self.StorDBSnapshotInterval, err = utils.ParseDurationWithNanosecs(*jsnStorDbCfg.Snapshot_interval); err != nil {
+				return err
+			}
+		}
 	}
 
 	if jsnGeneralCfg != nil {
@@ -803,6 +899,9 @@ func (self *CGRConfig) loadFromJsonCfg(jsnCfg *CgrJsonCfg) (err error) {
 		if jsnGeneralCfg.Log_level != nil {
 			self.LogLevel = *jsnGeneralCfg.Log_level
 		}
+		if jsnGeneralCfg.Log_format != nil {
+			self.LogFormat = *jsnGeneralCfg.Log_format
+		}
 
 		if jsnGeneralCfg.Dbdata_encoding != nil {
 			self.DBDataEncoding = *jsnGeneralCfg.Dbdata_encoding
@@ -855,6 +954,9 @@ func (self *CGRConfig) loadFromJsonCfg(jsnCfg *CgrJsonCfg) (err error) {
 		if jsnGeneralCfg.Default_timezone != nil {
 			self.DefaultTimezone = *jsnGeneralCfg.Default_timezone
 		}
+		if jsnGeneralCfg.Default_currency != nil {
+			self.DefaultCurrency = *jsnGeneralCfg.Default_currency
+		}
 		if jsnGeneralCfg.Internal_ttl != nil {
 			if self.InternalTtl, err = utils.ParseDurationWithNanosecs(*jsnGeneralCfg.Internal_ttl); err != nil {
 				return err
@@ -873,6 +975,14 @@ func (self *CGRConfig) loadFromJsonCfg(jsnCfg *CgrJsonCfg) (err error) {
 		}
 	}
 
+	if jsnCacheReplCfg != nil && jsnCacheReplCfg.Replication_conns != nil {
+		self.CacheReplicationConns = make([]*HaPoolConfig, len(*jsnCacheReplCfg.Replication_conns))
+		for idx, jsnHaCfg := range *jsnCacheReplCfg.Replication_conns {
+			self.CacheReplicationConns[idx] = NewDfltHaPoolConfig()
+			self.CacheReplicationConns[idx].loadFromJsonCfg(jsnHaCfg)
+		}
+	}
+
 	if jsnListenCfg != nil {
 		if jsnListenCfg.Rpc_json != nil {
 			self.RPCJSONListen = *jsnListenCfg.Rpc_json
@@ -892,6 +1002,12 @@ func (self *CGRConfig) loadFromJsonCfg(jsnCfg *CgrJsonCfg) (err error) {
 		if jsnHttpCfg.Ws_url != nil {
 			self.HTTPWSURL = *jsnHttpCfg.Ws_url
 		}
+		if jsnHttpCfg.Metrics_url != nil {
+			self.HTTPMetricsURL = *jsnHttpCfg.Metrics_url
+		}
+		if jsnHttpCfg.Health_url != nil {
+			self.HTTPHealthURL = *jsnHttpCfg.Health_url
+		}
 		if jsnHttpCfg.Use_basic_auth != nil {
 			self.HTTPUseBasicAuth = *jsnHttpCfg.Use_basic_auth
 		}
@@ -980,6 +1096,22 @@ func (self *CGRConfig) loadFromJsonCfg(jsnCfg *CgrJsonCfg) (err error) {
 				}
 			}
 		}
+		if jsnRALsCfg.Balance_events_interval != nil {
+			if self.RALsBalanceEvInterval, err = utils.ParseDurationWithNanosecs(*jsnRALsCfg.Balance_events_interval); err != nil {
+				return
+			}
+		}
+		if jsnRALsCfg.Balance_expiring_lead_times != nil {
+			self.RALsBalanceLeadTimes = make([]time.Duration, len(*jsnRALsCfg.Balance_expiring_lead_times))
+			for i, leadTime := range *jsnRALsCfg.Balance_expiring_lead_times {
+				if self.RALsBalanceLeadTimes[i], err = utils.ParseDurationWithNanosecs(leadTime); err != nil {
+					return
+				}
+			}
+		}
+		if jsnRALsCfg.Balance_events_post_urls != nil {
+			self.RALsBalanceEvPostURLs = *jsnRALsCfg.Balance_events_post_urls
+		}
 	}
 	if jsnSchedCfg != nil && jsnSchedCfg.Enabled != nil {
 		self.SchedulerEnabled = *jsnSchedCfg.Enabled
@@ -999,6 +1131,9 @@ func (self *CGRConfig) loadFromJsonCfg(jsnCfg *CgrJsonCfg) (err error) {
 		if jsnCdrsCfg.Sm_cost_retries != nil {
 			self.CDRSSMCostRetries = *jsnCdrsCfg.Sm_cost_retries
 		}
+		if jsnCdrsCfg.Apply_taxes != nil {
+			self.CDRSApplyTaxes = *jsnCdrsCfg.Apply_taxes
+		}
 		if jsnCdrsCfg.Rals_conns != nil {
 			self.CDRSRaterConns = make([]*HaPoolConfig, len(*jsnCdrsCfg.Rals_conns))
 			for idx, jsnHaCfg := range *jsnCdrsCfg.Rals_conns {
@@ -1175,6 +1310,12 @@ func (self *CGRConfig) loadFromJsonCfg(jsnCfg *CgrJsonCfg) (err error) {
 		}
 	}
 
+	if jsnHttpAgntCfg != nil {
+		if err := self.httpAgentCfg.loadFromJsonCfg(jsnHttpAgntCfg); err != nil {
+			return err
+		}
+	}
+
 	if jsnHistServCfg != nil {
 		if jsnHistServCfg.Enabled != nil {
 			self.HistoryServerEnabled = *jsnHistServCfg.Enabled
@@ -1299,7 +1440,13 @@ func (self *CGRConfig) RadiusAgentCfg() *RadiusAgentCfg {
 	return self.radiusAgentCfg
 }
 
+func (self *CGRConfig) HttpAgentCfg() *HttpAgentCfg {
+	return self.httpAgentCfg
+}
+
 func (cfg *CGRConfig) AttributeSCfg() *AttributeSCfg {
+	cfg.cfgMux.RLock()
+	defer cfg.cfgMux.RUnlock()
 	return cfg.attributeSCfg
 }
 
@@ -1308,16 +1455,21 @@ func (self *CGRConfig) ResourceSCfg() *ResourceSConfig {
 	return self.resourceSCfg
 }
 
-// ToDo: fix locking
 func (cfg *CGRConfig) StatSCfg() *StatSCfg {
+	cfg.cfgMux.RLock()
+	defer cfg.cfgMux.RUnlock()
 	return cfg.statsCfg
 }
 
 func (cfg *CGRConfig) ThresholdSCfg() *ThresholdSCfg {
+	cfg.cfgMux.RLock()
+	defer cfg.cfgMux.RUnlock()
 	return cfg.thresholdSCfg
 }
 
 func (cfg *CGRConfig) SupplierSCfg() *SupplierSCfg {
+	cfg.cfgMux.RLock()
+	defer cfg.cfgMux.RUnlock()
 	return cfg.supplierSCfg
 }
 
@@ -1333,5 +1485,7 @@ func (cfg *CGRConfig) FilterSCfg() *FilterSCfg {
 }
 
 func (cfg *CGRConfig) CacheCfg() CacheConfig {
+	cfg.cfgMux.RLock()
+	defer cfg.cfgMux.RUnlock()
 	return cfg.cacheConfig
 }
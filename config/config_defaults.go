@@ -31,6 +31,7 @@ const CGRATES_CFG_JSON = `
 	"instance_id": "",										// identifier of this instance in the cluster, if empty it will be autogenerated
 	"logger":"*syslog",										// controls the destination of logs <*syslog|*stdout>
 	"log_level": 6,											// control the level of messages logged (0-emerg to 7-debug)
+	"log_format": "*text",									// format of the logged messages <*text|*json>
 	"http_skip_tls_verify": false,							// if enabled Http Client will accept any TLS certificate
 	"rounding_decimals": 5,									// system level precision for floats
 	"dbdata_encoding": "msgpack",							// encoding used to store object data in strings: <msgpack|json>
@@ -41,6 +42,7 @@ const CGRATES_CFG_JSON = `
 	"default_category": "call",								// default category to consider when missing from requests
 	"default_tenant": "cgrates.org",						// default tenant to consider when missing from requests
 	"default_timezone": "Local",							// default timezone for timestamps where not specified <""|UTC|Local|$IANA_TZ_DB>
+	"default_currency": "",									// currency of the RatingPlans without one defined
 	"connect_attempts": 3,									// initial server connect attempts
 	"reconnects": -1,										// number of retries in case of connection lost
 	"connect_timeout": "1s",								// consider connection unsuccessful on timeout, 0 to disable the feature
@@ -52,27 +54,31 @@ const CGRATES_CFG_JSON = `
 
 
 "data_db": {								// database used to store runtime data (eg: accounts, cdr stats)
-	"db_type": "redis",						// data_db type: <redis|mongo>
+	"db_type": "redis",						// data_db type: <redis|mongo|*internal|*embedded>
 	"db_host": "127.0.0.1",					// data_db host address
 	"db_port": 6379, 						// data_db port to reach the database
-	"db_name": "10", 						// data_db database name to connect to
+	"db_name": "10", 						// data_db database name to connect to, directory of the database for *embedded
 	"db_user": "cgrates", 					// username to use when connecting to data_db
 	"db_password": "", 						// password to use when connecting to data_db
 	"load_history_size": 10,				// Number of records in the load history
+	"snapshot_path": "",						// file where the *internal data_db is persisted, empty to keep it only in memory
+	"snapshot_interval": "0s",				// interval between *internal data_db snapshots, 0 to save only on shutdown
 },
 
 
 "stor_db": {								// database used to store offline tariff plans and CDRs
-	"db_type": "mysql",						// stor database type to use: <mongo|mysql|postgres>
+	"db_type": "mysql",						// stor database type to use: <mongo|mysql|postgres|sqlite|*internal>
 	"db_host": "127.0.0.1",					// the host to connect to
 	"db_port": 3306,						// the port to reach the stordb
-	"db_name": "cgrates",					// stor database name
+	"db_name": "cgrates",					// stor database name, path to the database file for sqlite
 	"db_user": "cgrates",					// username to use when connecting to stordb
 	"db_password": "",						// password to use when connecting to stordb
 	"max_open_conns": 100,					// maximum database connections opened, not applying for mongo
 	"max_idle_conns": 10,					// maximum database connections idle, not applying for mongo
 	"conn_max_lifetime": 0, 				// maximum amount of time in seconds a connection may be reused (0 for unlimited), not applying for mongo
 	"cdrs_indexes": [],						// indexes on cdrs table to speed up queries, used only in case of mongo
+	"snapshot_path": "",						// file where the *internal stor_db is persisted, empty to keep it only in memory
+	"snapshot_interval": "0s",				// interval between *internal stor_db snapshots, 0 to save only on shutdown
 },
 
 
@@ -86,6 +92,8 @@ const CGRATES_CFG_JSON = `
 "http": {									// HTTP server configuration
 	"json_rpc_url": "/jsonrpc",				// JSON RPC relative URL ("" to disable)
 	"ws_url": "/ws",						// WebSockets relative URL ("" to disable)
+	"metrics_url": "/metrics",				// Prometheus metrics relative URL ("" to disable)
+	"health_url": "/health",				// health report relative URL, with <health_url>/live and <health_url>/ready probes ("" to disable)
 	"use_basic_auth": false,				// use basic authentication
 	"auth_users": {}						// basic authentication usernames and base64-encoded passwords (eg: { "username1": "cGFzc3dvcmQ=", "username2": "cGFzc3dvcmQy "})
 },
@@ -97,31 +105,39 @@ const CGRATES_CFG_JSON = `
 
 
 "cache":{
-	"destinations": {"limit": -1, "ttl": "", "static_ttl": false, "precache": false},			// destination caching
-	"reverse_destinations": {"limit": -1, "ttl": "", "static_ttl": false, "precache": false},	// reverse destinations index caching
-	"rating_plans": {"limit": -1, "ttl": "", "static_ttl": false, "precache": false},			// rating plans caching
-	"rating_profiles": {"limit": -1, "ttl": "", "static_ttl": false, "precache": false},		// rating profiles caching
-	"lcr_rules": {"limit": -1, "ttl": "", "static_ttl": false, "precache": false},				// lcr rules caching
-	"cdr_stats": {"limit": -1, "ttl": "", "static_ttl": false, "precache": false},				// cdr stats queues caching
-	"actions": {"limit": -1, "ttl": "", "static_ttl": false, "precache": false},				// actions caching
-	"action_plans": {"limit": -1, "ttl": "", "static_ttl": false, "precache": false},			// action plans caching
-	"account_action_plans": {"limit": -1, "ttl": "", "static_ttl": false, "precache": false},	// account action plans index caching
-	"action_triggers": {"limit": -1, "ttl": "", "static_ttl": false, "precache": false},		// action triggers caching
-	"shared_groups": {"limit": -1, "ttl": "", "static_ttl": false, "precache": false},			// shared groups caching
-	"aliases": {"limit": -1, "ttl": "", "static_ttl": false, "precache": false},				// aliases caching
-	"reverse_aliases": {"limit": -1, "ttl": "", "static_ttl": false, "precache": false},		// reverse aliases index caching
-	"derived_chargers": {"limit": -1, "ttl": "", "static_ttl": false, "precache": false},		// derived charging rule caching
-	"timings": {"limit": -1, "ttl": "", "static_ttl": false, "precache": false},				// timings caching
-	"resource_profiles": {"limit": -1, "ttl": "", "static_ttl": false, "precache": false},		// control resource profiles caching
-	"resources": {"limit": -1, "ttl": "", "static_ttl": false, "precache": false},				// control resources caching
+	"destinations": {"limit": -1, "ttl": "", "static_ttl": false, "precache": false, "replicate": false},			// destination caching
+	"reverse_destinations": {"limit": -1, "ttl": "", "static_ttl": false, "precache": false, "replicate": false},	// reverse destinations index caching
+	"rating_plans": {"limit": -1, "ttl": "", "static_ttl": false, "precache": false, "replicate": false},			// rating plans caching
+	"rating_profiles": {"limit": -1, "ttl": "", "static_ttl": false, "precache": false, "replicate": false},		// rating profiles caching
+	"lcr_rules": {"limit": -1, "ttl": "", "static_ttl": false, "precache": false, "replicate": false},				// lcr rules caching
+	"cdr_stats": {"limit": -1, "ttl": "", "static_ttl": false, "precache": false, "replicate": false},				// cdr stats queues caching
+	"actions": {"limit": -1, "ttl": "", "static_ttl": false, "precache": false, "replicate": false},				// actions caching
+	"action_plans": {"limit": -1, "ttl": "", "static_ttl": false, "precache": false, "replicate": false},			// action plans caching
+	"account_action_plans": {"limit": -1, "ttl": "", "static_ttl": false, "precache": false, "replicate": false},	// account action plans index caching
+	"action_triggers": {"limit": -1, "ttl": "", "static_ttl": false, "precache": false, "replicate": false},		// action triggers caching
+	"shared_groups": {"limit": -1, "ttl": "", "static_ttl": false, "precache": false, "replicate": false},			// shared groups caching
+	"aliases": {"limit": -1, "ttl": "", "static_ttl": false, "precache": false, "replicate": false},				// aliases caching
+	"reverse_aliases": {"limit": -1, "ttl": "", "static_ttl": false, "precache": false, "replicate": false},		// reverse aliases index caching
+	"derived_chargers": {"limit": -1, "ttl": "", "static_ttl": false, "precache": false, "replicate": false},		// derived charging rule caching
+	"timings": {"limit": -1, "ttl": "", "static_ttl": false, "precache": false, "replicate": false},				// timings caching
+	"resource_profiles": {"limit": -1, "ttl": "", "static_ttl": false, "precache": false, "replicate": false},		// control resource profiles caching
+	"resources": {"limit": -1, "ttl": "", "static_ttl": false, "precache": false, "replicate": false},				// control resources caching
 	"event_resources": {"limit": -1, "ttl": "1m", "static_ttl": false},							// matching resources to events
-	"statqueue_profiles": {"limit": -1, "ttl": "1m", "static_ttl": false, "precache": false},	// statqueue profiles
-	"statqueues": {"limit": -1, "ttl": "1m", "static_ttl": false, "precache": false},			// statqueues with metrics
-	"threshold_profiles": {"limit": -1, "ttl": "", "static_ttl": false, "precache": false},		// control threshold profiles caching
-	"thresholds": {"limit": -1, "ttl": "", "static_ttl": false, "precache": false},				// control thresholds caching
-	"filters": {"limit": -1, "ttl": "", "static_ttl": false, "precache": false},				// control filters caching
-	"supplier_profiles": {"limit": -1, "ttl": "", "static_ttl": false, "precache": false},		// control supplier profile caching
-	"attribute_profiles": {"limit": -1, "ttl": "", "static_ttl": false, "precache": false},		// control attribute profile caching
+	"statqueue_profiles": {"limit": -1, "ttl": "1m", "static_ttl": false, "precache": false, "replicate": false},	// statqueue profiles
+	"statqueues": {"limit": -1, "ttl": "1m", "static_ttl": false, "precache": false, "replicate": false},			// statqueues with metrics
+	"threshold_profiles": {"limit": -1, "ttl": "", "static_ttl": false, "precache": false, "replicate": false},		// control threshold profiles caching
+	"thresholds": {"limit": -1, "ttl": "", "static_ttl": false, "precache": false, "replicate": false},				// control thresholds caching
+	"filters": {"limit": -1, "ttl": "", "static_ttl": false, "precache": false, "replicate": false},				// control filters caching
+	"supplier_profiles": {"limit": -1, "ttl": "", "static_ttl": false, "precache": false, "replicate": false},		// control supplier profile caching
+	"attribute_profiles": {"limit": -1, "ttl": "", "static_ttl": false, "precache": false, "replicate": false},		// control attribute profile caching
+	"exchange_rates": {"limit": -1, "ttl": "", "static_ttl": false, "precache": false, "replicate": false},			// control exchange rates caching
+	"tax_profiles": {"limit": -1, "ttl": "", "static_ttl": false, "precache": false, "replicate": false},			// control tax profiles caching
+	"fee_profiles": {"limit": -1, "ttl": "", "static_ttl": false, "precache": false, "replicate": false},			// control fee profiles caching
+},
+
+
+"cache_replication": {
+	"replication_conns": [],				// peer engines receiving the changes of the cache partitions marked with replicate: <""|x.y.z.y:1234>
 },
 
 
@@ -148,6 +164,9 @@ const CGRATES_CFG_JSON = `
 		"*data": "107374182400",
 		"*sms": "10000"
 	},
+	"balance_events_interval": "0s",		// interval to check the balances for expiry events, 0 to disable
+	"balance_expiring_lead_times": [],		// time before the balance expiry to send the BalanceExpiring event, one event per lead time: <["72h","24h"]>
+	"balance_events_post_urls": [],			// post the balance lifecycle events also to these addresses: <http://|amqp://>
 },
 
 
@@ -156,6 +175,7 @@ const CGRATES_CFG_JSON = `
 	"extra_fields": [],						// extra fields to store in CDRs for non-generic CDRs
 	"store_cdrs": true,						// store cdrs in storDb
 	"sm_cost_retries": 5,					// number of queries to sm_costs before recalculating CDR
+	"apply_taxes": false,					// apply local tax profiles after rating the CDR
 	"rals_conns": [
 		{"address": "*internal"}			// address where to reach the Rater for cost calculation, empty to disable functionality: <""|*internal|x.y.z.y:1234>
 	],
@@ -286,6 +306,7 @@ const CGRATES_CFG_JSON = `
 	//"session_ttl_last_used": "",			// tweak LastUsed for sessions timing-out, not defined by default
 	//"session_ttl_usage": "",				// tweak Usage for sessions timing-out, not defined by default
 	"session_indexes": [],					// index sessions based on these fields for GetActiveSessions API
+	"event_reservation_ttl": "1h",			// time after an unconfirmed event reservation is refunded, 0 to never expire
 },
 
 
@@ -367,7 +388,7 @@ const CGRATES_CFG_JSON = `
 	"listen": "127.0.0.1:3868",									// address where to listen for diameter requests <x.y.z.y:1234>
 	"dictionaries_dir": "/usr/share/cgrates/diameter/dict/",	// path towards directory holding additional dictionaries to load
 	"sm_generic_conns": [
-		{"address": "*internal"}								// connection towards SMG component for session management
+		{"address": "*internal"}								// connection towards SMG component for session management, ASR/RAR are only sent with a single *internal connection
 	],
 	"pubsubs_conns": [],										// address where to reach the pubusb service, empty to disable pubsub functionality: <""|*internal|x.y.z.y:1234>
 	"create_cdr": true,											// create CDR out of CCR terminate and send it to SMG component
@@ -399,6 +420,23 @@ const CGRATES_CFG_JSON = `
 	"create_cdr": true,											// create CDR out of Accounting-Stop and send it to SMG component
 	"cdr_requires_session": false,								// only create CDR if there is an active session at terminate
 	"timezone": "",												// timezone for timestamps where not specified, empty for general defaults <""|UTC|Local|$IANA_TZ_DB>
+	"dynauth_address": "",										// NAS address receiving CoA and Disconnect-Message requests, empty to disable; requires a single *internal sm_generic_conns <""|x.y.z.y:3799>
+	"dynauth_session_ttl": "3h",								// forget sessions without Accounting-Stop after this interval, refreshed by Interim-Update
+	"disconnect_fields": [],									// Disconnect-Request template, populated out of session data
+	"coa_fields": [],											// CoA-Request template, populated out of session data
+	"request_processors": [],
+},
+
+
+"http_agent": {
+	"enabled": false,											// enables the http agent: <true|false>
+	"url": "/http_agent",										// path where the agent listens for requests on the http server
+	"sm_generic_conns": [
+		{"address": "*internal"}								// connection towards SMG component for session management
+	],
+	"request_payload": "*url",									// encoding of the requests: <*url|*json>
+	"reply_payload": "*json",									// encoding of the replies: <*url|*json>
+	"timezone": "",												// timezone for timestamps where not specified, empty for general defaults <""|UTC|Local|$IANA_TZ_DB>
 	"request_processors": [],
 },
 
@@ -30,6 +30,7 @@ import (
 const (
 	GENERAL_JSN     = "general"
 	CACHE_JSN       = "cache"
+	CacheReplJSN    = "cache_replication"
 	LISTEN_JSN      = "listen"
 	HTTP_JSN        = "http"
 	DATADB_JSN      = "data_db"
@@ -53,6 +54,7 @@ const (
 	OSIPS_JSN       = "opensips"
 	DA_JSN          = "diameter_agent"
 	RA_JSN          = "radius_agent"
+	HttpAgentJson   = "http_agent"
 	HISTSERV_JSN    = "historys"
 	PUBSUBSERV_JSN  = "pubsubs"
 	ALIASESSERV_JSN = "aliases"
@@ -114,6 +116,18 @@ func (self CgrJsonCfg) CacheJsonCfg() (*CacheJsonCfg, error) {
 	return cfg, nil
 }
 
+func (jsnCfg CgrJsonCfg) CacheReplicationJsonCfg() (*CacheReplicationJsonCfg, error) {
+	rawCfg, hasKey := jsnCfg[CacheReplJSN]
+	if !hasKey {
+		return nil, nil
+	}
+	cfg := new(CacheReplicationJsonCfg)
+	if err := json.Unmarshal(*rawCfg, cfg); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
 func (self CgrJsonCfg) ListenJsonCfg() (*ListenJsonCfg, error) {
 	rawCfg, hasKey := self[LISTEN_JSN]
 	if !hasKey {
@@ -318,6 +332,18 @@ func (self CgrJsonCfg) RadiusAgentJsonCfg() (*RadiusAgentJsonCfg, error) {
 	return cfg, nil
 }
 
+func (self CgrJsonCfg) HttpAgentJsonCfg() (*HttpAgentJsonCfg, error) {
+	rawCfg, hasKey := self[HttpAgentJson]
+	if !hasKey {
+		return nil, nil
+	}
+	cfg := new(HttpAgentJsonCfg)
+	if err := json.Unmarshal(*rawCfg, cfg); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
 func (self CgrJsonCfg) HistServJsonCfg() (*HistServJsonCfg, error) {
 	rawCfg, hasKey := self[HISTSERV_JSN]
 	if !hasKey {
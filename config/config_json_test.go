@@ -41,6 +41,7 @@ func TestDfGeneralJsonCfg(t *testing.T) {
 		Instance_id:          utils.StringPointer(""),
 		Logger:               utils.StringPointer(utils.MetaSysLog),
 		Log_level:            utils.IntPointer(utils.LOGLEVEL_INFO),
+		Log_format:           utils.StringPointer(utils.MetaText),
 		Http_skip_tls_verify: utils.BoolPointer(false),
 		Rounding_decimals:    utils.IntPointer(5),
 		Dbdata_encoding:      utils.StringPointer("msgpack"),
@@ -51,6 +52,7 @@ func TestDfGeneralJsonCfg(t *testing.T) {
 		Default_category:     utils.StringPointer("call"),
 		Default_tenant:       utils.StringPointer("cgrates.org"),
 		Default_timezone:     utils.StringPointer("Local"),
+		Default_currency:     utils.StringPointer(""),
 		Connect_attempts:     utils.IntPointer(3),
 		Reconnects:           utils.IntPointer(-1),
 		Connect_timeout:      utils.StringPointer("1s"),
@@ -70,78 +72,87 @@ func TestCacheJsonCfg(t *testing.T) {
 	eCfg := &CacheJsonCfg{
 		utils.CacheDestinations: &CacheParamJsonCfg{Limit: utils.IntPointer(-1),
 			Ttl: utils.StringPointer(""), Static_ttl: utils.BoolPointer(false),
-			Precache: utils.BoolPointer(false)},
+			Precache: utils.BoolPointer(false), Replicate: utils.BoolPointer(false)},
 		utils.CacheReverseDestinations: &CacheParamJsonCfg{Limit: utils.IntPointer(-1),
 			Ttl: utils.StringPointer(""), Static_ttl: utils.BoolPointer(false),
-			Precache: utils.BoolPointer(false)},
+			Precache: utils.BoolPointer(false), Replicate: utils.BoolPointer(false)},
 		utils.CacheRatingPlans: &CacheParamJsonCfg{Limit: utils.IntPointer(-1),
 			Ttl: utils.StringPointer(""), Static_ttl: utils.BoolPointer(false),
-			Precache: utils.BoolPointer(false)},
+			Precache: utils.BoolPointer(false), Replicate: utils.BoolPointer(false)},
 		utils.CacheRatingProfiles: &CacheParamJsonCfg{Limit: utils.IntPointer(-1),
 			Ttl: utils.StringPointer(""), Static_ttl: utils.BoolPointer(false),
-			Precache: utils.BoolPointer(false)},
+			Precache: utils.BoolPointer(false), Replicate: utils.BoolPointer(false)},
 		utils.CacheLCRRules: &CacheParamJsonCfg{Limit: utils.IntPointer(-1),
 			Ttl: utils.StringPointer(""), Static_ttl: utils.BoolPointer(false),
-			Precache: utils.BoolPointer(false)},
+			Precache: utils.BoolPointer(false), Replicate: utils.BoolPointer(false)},
 		utils.CacheCDRStatS: &CacheParamJsonCfg{Limit: utils.IntPointer(-1),
 			Ttl: utils.StringPointer(""), Static_ttl: utils.BoolPointer(false),
-			Precache: utils.BoolPointer(false)},
+			Precache: utils.BoolPointer(false), Replicate: utils.BoolPointer(false)},
 		utils.CacheActions: &CacheParamJsonCfg{Limit: utils.IntPointer(-1),
 			Ttl: utils.StringPointer(""), Static_ttl: utils.BoolPointer(false),
-			Precache: utils.BoolPointer(false)},
+			Precache: utils.BoolPointer(false), Replicate: utils.BoolPointer(false)},
 		utils.CacheActionPlans: &CacheParamJsonCfg{Limit: utils.IntPointer(-1),
 			Ttl: utils.StringPointer(""), Static_ttl: utils.BoolPointer(false),
-			Precache: utils.BoolPointer(false)},
+			Precache: utils.BoolPointer(false), Replicate: utils.BoolPointer(false)},
 		utils.CacheAccountActionPlans: &CacheParamJsonCfg{Limit: utils.IntPointer(-1),
 			Ttl: utils.StringPointer(""), Static_ttl: utils.BoolPointer(false),
-			Precache: utils.BoolPointer(false)},
+			Precache: utils.BoolPointer(false), Replicate: utils.BoolPointer(false)},
 		utils.CacheActionTriggers: &CacheParamJsonCfg{Limit: utils.IntPointer(-1),
 			Ttl: utils.StringPointer(""), Static_ttl: utils.BoolPointer(false),
-			Precache: utils.BoolPointer(false)},
+			Precache: utils.BoolPointer(false), Replicate: utils.BoolPointer(false)},
 		utils.CacheSharedGroups: &CacheParamJsonCfg{Limit: utils.IntPointer(-1),
 			Ttl: utils.StringPointer(""), Static_ttl: utils.BoolPointer(false),
-			Precache: utils.BoolPointer(false)},
+			Precache: utils.BoolPointer(false), Replicate: utils.BoolPointer(false)},
 		utils.CacheAliases: &CacheParamJsonCfg{Limit: utils.IntPointer(-1),
 			Ttl: utils.StringPointer(""), Static_ttl: utils.BoolPointer(false),
-			Precache: utils.BoolPointer(false)},
+			Precache: utils.BoolPointer(false), Replicate: utils.BoolPointer(false)},
 		utils.CacheReverseAliases: &CacheParamJsonCfg{Limit: utils.IntPointer(-1),
 			Ttl: utils.StringPointer(""), Static_ttl: utils.BoolPointer(false),
-			Precache: utils.BoolPointer(false)},
+			Precache: utils.BoolPointer(false), Replicate: utils.BoolPointer(false)},
 		utils.CacheDerivedChargers: &CacheParamJsonCfg{Limit: utils.IntPointer(-1),
 			Ttl: utils.StringPointer(""), Static_ttl: utils.BoolPointer(false),
-			Precache: utils.BoolPointer(false)},
+			Precache: utils.BoolPointer(false), Replicate: utils.BoolPointer(false)},
 		utils.CacheTimings: &CacheParamJsonCfg{Limit: utils.IntPointer(-1),
 			Ttl: utils.StringPointer(""), Static_ttl: utils.BoolPointer(false),
-			Precache: utils.BoolPointer(false)},
+			Precache: utils.BoolPointer(false), Replicate: utils.BoolPointer(false)},
 		utils.CacheResourceProfiles: &CacheParamJsonCfg{Limit: utils.IntPointer(-1),
 			Ttl: utils.StringPointer(""), Static_ttl: utils.BoolPointer(false),
-			Precache: utils.BoolPointer(false)},
+			Precache: utils.BoolPointer(false), Replicate: utils.BoolPointer(false)},
 		utils.CacheResources: &CacheParamJsonCfg{Limit: utils.IntPointer(-1),
 			Ttl: utils.StringPointer(""), Static_ttl: utils.BoolPointer(false),
-			Precache: utils.BoolPointer(false)},
+			Precache: utils.BoolPointer(false), Replicate: utils.BoolPointer(false)},
 		utils.CacheEventResources: &CacheParamJsonCfg{Limit: utils.IntPointer(-1),
 			Ttl: utils.StringPointer("1m"), Static_ttl: utils.BoolPointer(false)},
 		utils.CacheStatQueueProfiles: &CacheParamJsonCfg{Limit: utils.IntPointer(-1),
 			Ttl: utils.StringPointer("1m"), Static_ttl: utils.BoolPointer(false),
-			Precache: utils.BoolPointer(false)},
+			Precache: utils.BoolPointer(false), Replicate: utils.BoolPointer(false)},
 		utils.CacheStatQueues: &CacheParamJsonCfg{Limit: utils.IntPointer(-1),
 			Ttl: utils.StringPointer("1m"), Static_ttl: utils.BoolPointer(false),
-			Precache: utils.BoolPointer(false)},
+			Precache: utils.BoolPointer(false), Replicate: utils.BoolPointer(false)},
 		utils.CacheThresholdProfiles: &CacheParamJsonCfg{Limit: utils.IntPointer(-1),
 			Ttl: utils.StringPointer(""), Static_ttl: utils.BoolPointer(false),
-			Precache: utils.BoolPointer(false)},
+			Precache: utils.BoolPointer(false), Replicate: utils.BoolPointer(false)},
 		utils.CacheThresholds: &CacheParamJsonCfg{Limit: utils.IntPointer(-1),
 			Ttl: utils.StringPointer(""), Static_ttl: utils.BoolPointer(false),
-			Precache: utils.BoolPointer(false)},
+			Precache: utils.BoolPointer(false), Replicate: utils.BoolPointer(false)},
 		utils.CacheFilters: &CacheParamJsonCfg{Limit: utils.IntPointer(-1),
 			Ttl: utils.StringPointer(""), Static_ttl: utils.BoolPointer(false),
-			Precache: utils.BoolPointer(false)},
+			Precache: utils.BoolPointer(false), Replicate: utils.BoolPointer(false)},
 		utils.CacheSupplierProfiles: &CacheParamJsonCfg{Limit: utils.IntPointer(-1),
 			Ttl: utils.StringPointer(""), Static_ttl: utils.BoolPointer(false),
-			Precache: utils.BoolPointer(false)},
+			Precache: utils.BoolPointer(false), Replicate: utils.BoolPointer(false)},
 		utils.CacheAttributeProfiles: &CacheParamJsonCfg{Limit: utils.IntPointer(-1),
 			Ttl: utils.StringPointer(""), Static_ttl: utils.BoolPointer(false),
-			Precache: utils.BoolPointer(false)},
+			Precache: utils.BoolPointer(false), Replicate: utils.BoolPointer(false)},
+		utils.CacheExchangeRates: &CacheParamJsonCfg{Limit: utils.IntPointer(-1),
+			Ttl: utils.StringPointer(""), Static_ttl: utils.BoolPointer(false),
+			Precache: utils.BoolPointer(false), Replicate: utils.BoolPointer(false)},
+		utils.CacheTaxProfiles: &CacheParamJsonCfg{Limit: utils.IntPointer(-1),
+			Ttl: utils.StringPointer(""), Static_ttl: utils.BoolPointer(false),
+			Precache: utils.BoolPointer(false), Replicate: utils.BoolPointer(false)},
+		utils.CacheFeeProfiles: &CacheParamJsonCfg{Limit: utils.IntPointer(-1),
+			Ttl: utils.StringPointer(""), Static_ttl: utils.BoolPointer(false),
+			Precache: utils.BoolPointer(false), Replicate: utils.BoolPointer(false)},
 	}
 
 	if gCfg, err := dfCgrJsonCfg.CacheJsonCfg(); err != nil {
@@ -172,6 +183,8 @@ func TestDfDataDbJsonCfg(t *testing.T) {
 		Db_user:           utils.StringPointer("cgrates"),
 		Db_password:       utils.StringPointer(""),
 		Load_history_size: utils.IntPointer(10),
+		Snapshot_path:     utils.StringPointer(""),
+		Snapshot_interval: utils.StringPointer("0s"),
 	}
 	if cfg, err := dfCgrJsonCfg.DbJsonCfg(DATADB_JSN); err != nil {
 		t.Error(err)
@@ -192,6 +205,8 @@ func TestDfStorDBJsonCfg(t *testing.T) {
 		Max_idle_conns:    utils.IntPointer(10),
 		Conn_max_lifetime: utils.IntPointer(0),
 		Cdrs_indexes:      utils.StringSlicePointer([]string{}),
+		Snapshot_path:     utils.StringPointer(""),
+		Snapshot_interval: utils.StringPointer("0s"),
 	}
 	if cfg, err := dfCgrJsonCfg.DbJsonCfg(STORDB_JSN); err != nil {
 		t.Error(err)
@@ -218,6 +233,9 @@ func TestDfRalsJsonCfg(t *testing.T) {
 			utils.VOICE: "72h",
 			utils.DATA:  "107374182400",
 			utils.SMS:   "10000"},
+		Balance_events_interval:     utils.StringPointer("0s"),
+		Balance_expiring_lead_times: &[]string{},
+		Balance_events_post_urls:    &[]string{},
 	}
 	if cfg, err := dfCgrJsonCfg.RalsJsonCfg(); err != nil {
 		t.Error(err)
@@ -241,6 +259,7 @@ func TestDfCdrsJsonCfg(t *testing.T) {
 		Extra_fields:    utils.StringSlicePointer([]string{}),
 		Store_cdrs:      utils.BoolPointer(true),
 		Sm_cost_retries: utils.IntPointer(5),
+		Apply_taxes:     utils.BoolPointer(false),
 		Rals_conns: &[]*HaPoolJsonCfg{
 			&HaPoolJsonCfg{
 				Address: utils.StringPointer("*internal"),
@@ -472,6 +491,7 @@ func TestSmGenericJsonCfg(t *testing.T) {
 		Max_call_duration:     utils.StringPointer("3h"),
 		Session_ttl:           utils.StringPointer("0s"),
 		Session_indexes:       utils.StringSlicePointer([]string{}),
+		Event_reservation_ttl: utils.StringPointer("1h"),
 	}
 	if cfg, err := dfCgrJsonCfg.SmGenericJsonCfg(); err != nil {
 		t.Error(err)
@@ -642,6 +662,10 @@ func TestRadiusAgentJsonCfg(t *testing.T) {
 		Create_cdr:           utils.BoolPointer(true),
 		Cdr_requires_session: utils.BoolPointer(false),
 		Timezone:             utils.StringPointer(""),
+		Dynauth_address:      utils.StringPointer(""),
+		Dynauth_session_ttl:  utils.StringPointer("3h"),
+		Disconnect_fields:    &[]*CdrFieldJsonCfg{},
+		Coa_fields:           &[]*CdrFieldJsonCfg{},
 		Request_processors:   &[]*RAReqProcessorJsnCfg{},
 	}
 	if cfg, err := dfCgrJsonCfg.RadiusAgentJsonCfg(); err != nil {
@@ -652,6 +676,26 @@ func TestRadiusAgentJsonCfg(t *testing.T) {
 	}
 }
 
+func TestHttpAgentJsonCfg(t *testing.T) {
+	eCfg := &HttpAgentJsonCfg{
+		Enabled: utils.BoolPointer(false),
+		Url:     utils.StringPointer("/http_agent"),
+		Sm_generic_conns: &[]*HaPoolJsonCfg{
+			&HaPoolJsonCfg{
+				Address: utils.StringPointer(utils.MetaInternal),
+			}},
+		Request_payload:    utils.StringPointer(utils.MetaUrl),
+		Reply_payload:      utils.StringPointer(utils.MetaJSON),
+		Timezone:           utils.StringPointer(""),
+		Request_processors: &[]*HttpAgentProcessorJsnCfg{},
+	}
+	if cfg, err := dfCgrJsonCfg.HttpAgentJsonCfg(); err != nil {
+		t.Error(err)
+	} else if !reflect.DeepEqual(eCfg, cfg) {
+		t.Errorf("Received: %s", utils.ToJSON(cfg))
+	}
+}
+
 func TestDfHistServJsonCfg(t *testing.T) {
 	eCfg := &HistServJsonCfg{
 		Enabled:       utils.BoolPointer(false),
@@ -711,6 +755,17 @@ func TestDfAttributeServJsonCfg(t *testing.T) {
 	}
 }
 
+func TestDfCacheReplicationJsonCfg(t *testing.T) {
+	eCfg := &CacheReplicationJsonCfg{
+		Replication_conns: &[]*HaPoolJsonCfg{},
+	}
+	if cfg, err := dfCgrJsonCfg.CacheReplicationJsonCfg(); err != nil {
+		t.Error(err)
+	} else if !reflect.DeepEqual(eCfg, cfg) {
+		t.Errorf("Expected: %s, received: %s", utils.ToJSON(eCfg), utils.ToJSON(cfg))
+	}
+}
+
 func TestDfFilterSJsonCfg(t *testing.T) {
 	eCfg := &FilterSJsonCfg{
 		Stats_conns: &[]*HaPoolJsonCfg{},
@@ -898,6 +953,8 @@ func TestDfHttpJsonCfg(t *testing.T) {
 	eCfg := &HTTPJsonCfg{
 		Json_rpc_url:   utils.StringPointer("/jsonrpc"),
 		Ws_url:         utils.StringPointer("/ws"),
+		Metrics_url:    utils.StringPointer("/metrics"),
+		Health_url:     utils.StringPointer("/health"),
 		Use_basic_auth: utils.BoolPointer(false),
 		Auth_users:     utils.MapStringStringPointer(map[string]string{})}
 	if cfg, err := dfCgrJsonCfg.HttpJsonCfg(); err != nil {
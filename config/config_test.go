@@ -190,6 +190,9 @@ func TestCgrCfgJSONDefaultsGeneral(t *testing.T) {
 	if cgrCfg.DefaultTimezone != "Local" {
 		t.Error(cgrCfg.DefaultTimezone)
 	}
+	if cgrCfg.DefaultCurrency != "" {
+		t.Error(cgrCfg.DefaultCurrency)
+	}
 	if cgrCfg.ConnectAttempts != 3 {
 		t.Error(cgrCfg.ConnectAttempts)
 	}
@@ -217,6 +220,9 @@ func TestCgrCfgJSONDefaultsGeneral(t *testing.T) {
 	if cgrCfg.LogLevel != 6 {
 		t.Error(cgrCfg.LogLevel)
 	}
+	if cgrCfg.LogFormat != utils.MetaText {
+		t.Error(cgrCfg.LogFormat)
+	}
 }
 
 func TestCgrCfgJSONDefaultsListen(t *testing.T) {
@@ -253,6 +259,12 @@ func TestCgrCfgJSONDefaultsjsnDataDb(t *testing.T) {
 	if cgrCfg.LoadHistorySize != 10 {
 		t.Error(cgrCfg.LoadHistorySize)
 	}
+	if cgrCfg.DataDbSnapshotPath != "" {
+		t.Error(cgrCfg.DataDbSnapshotPath)
+	}
+	if cgrCfg.DataDbSnapshotInterval != 0 {
+		t.Error(cgrCfg.DataDbSnapshotInterval)
+	}
 }
 
 func TestCgrCfgJSONDefaultsStorDB(t *testing.T) {
@@ -284,6 +296,12 @@ func TestCgrCfgJSONDefaultsStorDB(t *testing.T) {
 	if !reflect.DeepEqual(cgrCfg.StorDBCDRSIndexes, Eslice) {
 		t.Error(cgrCfg.StorDBCDRSIndexes)
 	}
+	if cgrCfg.StorDBSnapshotPath != "" {
+		t.Error(cgrCfg.StorDBSnapshotPath)
+	}
+	if cgrCfg.StorDBSnapshotInterval != 0 {
+		t.Error(cgrCfg.StorDBSnapshotInterval)
+	}
 }
 
 func TestCgrCfgJSONDefaultsRALs(t *testing.T) {
@@ -330,6 +348,15 @@ func TestCgrCfgJSONDefaultsRALs(t *testing.T) {
 	if !reflect.DeepEqual(eMaxCU, cgrCfg.RALsMaxComputedUsage) {
 		t.Errorf("Expecting: %+v, received: %+v", eMaxCU, cgrCfg.RALsMaxComputedUsage)
 	}
+	if cgrCfg.RALsBalanceEvInterval != 0 {
+		t.Error(cgrCfg.RALsBalanceEvInterval)
+	}
+	if !reflect.DeepEqual([]time.Duration{}, cgrCfg.RALsBalanceLeadTimes) {
+		t.Errorf("Received: %+v", cgrCfg.RALsBalanceLeadTimes)
+	}
+	if !reflect.DeepEqual([]string{}, cgrCfg.RALsBalanceEvPostURLs) {
+		t.Errorf("Received: %+v", cgrCfg.RALsBalanceEvPostURLs)
+	}
 }
 
 func TestCgrCfgJSONDefaultsScheduler(t *testing.T) {
@@ -356,6 +383,9 @@ func TestCgrCfgJSONDefaultsCDRS(t *testing.T) {
 	if cgrCfg.CDRSSMCostRetries != 5 {
 		t.Error(cgrCfg.CDRSSMCostRetries)
 	}
+	if cgrCfg.CDRSApplyTaxes {
+		t.Error(cgrCfg.CDRSApplyTaxes)
+	}
 	if !reflect.DeepEqual(cgrCfg.CDRSRaterConns, []*HaPoolConfig{&HaPoolConfig{Address: "*internal"}}) {
 		t.Error(cgrCfg.CDRSRaterConns)
 	}
@@ -460,6 +490,7 @@ func TestCgrCfgJSONDefaultsSMGenericCfg(t *testing.T) {
 		MaxCallDuration:     3 * time.Hour,
 		SessionTTL:          0 * time.Second,
 		SessionIndexes:      utils.StringMap{},
+		EventReservationTTL: time.Hour,
 	}
 
 	if !reflect.DeepEqual(cgrCfg.SmGenericConfig, eSmGeCfg) {
@@ -518,6 +549,12 @@ func TestCgrCfgJSONDefaultsCacheCFG(t *testing.T) {
 		utils.CacheSupplierProfiles: &CacheParamConfig{Limit: -1,
 			TTL: time.Duration(0), StaticTTL: false, Precache: false},
 		utils.CacheAttributeProfiles: &CacheParamConfig{Limit: -1,
+			TTL: time.Duration(0), StaticTTL: false, Precache: false},
+		utils.CacheExchangeRates: &CacheParamConfig{Limit: -1,
+			TTL: time.Duration(0), StaticTTL: false, Precache: false},
+		utils.CacheTaxProfiles: &CacheParamConfig{Limit: -1,
+			TTL: time.Duration(0), StaticTTL: false, Precache: false},
+		utils.CacheFeeProfiles: &CacheParamConfig{Limit: -1,
 			TTL: time.Duration(0), StaticTTL: false, Precache: false}}
 	if !reflect.DeepEqual(eCacheCfg, cgrCfg.CacheCfg()) {
 		t.Errorf("received: %s, \nexpecting: %s",
@@ -634,6 +671,12 @@ func TestCgrCfgJSONDefaultsUserS(t *testing.T) {
 	}
 }
 
+func TestCgrCfgJSONDefaultCacheReplicationConns(t *testing.T) {
+	if !reflect.DeepEqual(cgrCfg.CacheReplicationConns, []*HaPoolConfig{}) {
+		t.Errorf("received: %+v", cgrCfg.CacheReplicationConns)
+	}
+}
+
 func TestCgrCfgJSONDefaultFiltersCfg(t *testing.T) {
 	eFiltersCfg := &FilterSCfg{
 		StatSConns: []*HaPoolConfig{},
@@ -689,6 +732,47 @@ func TestCgrCfgJSONDefaultThresholdSCfg(t *testing.T) {
 	}
 }
 
+func TestCgrCfgJSONThresholdSConns(t *testing.T) {
+	jsnCfg := `
+{
+"stats": {
+	"enabled": true,
+},
+"thresholds": {
+	"enabled": true,
+	"stats_conns": [
+		{"address": "*internal"},
+	],
+	"resources_conns": [
+		{"address": "127.0.0.1:2012", "transport": "*json"},
+	],
+},
+}`
+	eStatSConns := []*HaPoolConfig{&HaPoolConfig{Address: utils.MetaInternal}}
+	eResourceSConns := []*HaPoolConfig{&HaPoolConfig{Address: "127.0.0.1:2012", Transport: utils.MetaJSONrpc}}
+	if cgrCfg, err := NewCGRConfigFromJsonStringWithDefaults(jsnCfg); err != nil {
+		t.Error(err)
+	} else if !reflect.DeepEqual(eStatSConns, cgrCfg.thresholdSCfg.StatSConns) {
+		t.Errorf("expecting: %s, received: %s", utils.ToJSON(eStatSConns), utils.ToJSON(cgrCfg.thresholdSCfg.StatSConns))
+	} else if !reflect.DeepEqual(eResourceSConns, cgrCfg.thresholdSCfg.ResourceSConns) {
+		t.Errorf("expecting: %s, received: %s", utils.ToJSON(eResourceSConns), utils.ToJSON(cgrCfg.thresholdSCfg.ResourceSConns))
+	}
+	jsnCfg = `
+{
+"thresholds": {
+	"enabled": true,
+	"resources_conns": [
+		{"address": "*internal"},
+	],
+},
+}`
+	if cgrCfg, err := NewCGRConfigFromJsonStringWithDefaults(jsnCfg); err != nil {
+		t.Error(err)
+	} else if err := cgrCfg.checkConfigSanity(); err == nil {
+		t.Error("expecting error on ResourceS not enabled")
+	}
+}
+
 func TestCgrCfgJSONDefaultSupplierSCfg(t *testing.T) {
 	eSupplSCfg := &SupplierSCfg{
 		Enabled:       false,
@@ -822,6 +906,12 @@ func TestCgrCfgJSONDefaultsHTTP(t *testing.T) {
 	if cgrCfg.HTTPWSURL != "/ws" {
 		t.Error(cgrCfg.HTTPWSURL)
 	}
+	if cgrCfg.HTTPMetricsURL != "/metrics" {
+		t.Error(cgrCfg.HTTPMetricsURL)
+	}
+	if cgrCfg.HTTPHealthURL != "/health" {
+		t.Error(cgrCfg.HTTPHealthURL)
+	}
 	if cgrCfg.HTTPUseBasicAuth != false {
 		t.Error(cgrCfg.HTTPUseBasicAuth)
 	}
@@ -830,6 +920,44 @@ func TestCgrCfgJSONDefaultsHTTP(t *testing.T) {
 	}
 }
 
+func TestHttpAgentCfg(t *testing.T) {
+	eCfg := &HttpAgentCfg{
+		Enabled:        false,
+		Url:            "/http_agent",
+		SMGenericConns: []*HaPoolConfig{&HaPoolConfig{Address: utils.MetaInternal}},
+		RequestPayload: utils.MetaUrl,
+		ReplyPayload:   utils.MetaJSON,
+		Timezone:       "",
+	}
+	if !reflect.DeepEqual(eCfg, cgrCfg.HttpAgentCfg()) {
+		t.Errorf("expecting: %s, received: %s", utils.ToJSON(eCfg), utils.ToJSON(cgrCfg.HttpAgentCfg()))
+	}
+	jsnCfg := &HttpAgentJsonCfg{
+		Request_processors: &[]*HttpAgentProcessorJsnCfg{
+			&HttpAgentProcessorJsnCfg{
+				Id:             utils.StringPointer("SMS"),
+				Request_filter: utils.StringPointer("type(sms)"),
+				Flags:          &[]string{utils.MetaEvent, utils.MetaCDR},
+				Request_fields: &[]*CdrFieldJsonCfg{
+					&CdrFieldJsonCfg{Tag: utils.StringPointer("Account"), Field_id: utils.StringPointer(utils.Account),
+						Type: utils.StringPointer(utils.META_COMPOSED), Value: utils.StringPointer("from")}},
+			},
+		},
+	}
+	haCfg := new(HttpAgentCfg)
+	if err := haCfg.loadFromJsonCfg(jsnCfg); err != nil {
+		t.Fatal(err)
+	}
+	if len(haCfg.RequestProcessors) != 1 {
+		t.Fatalf("unexpected processors: %s", utils.ToJSON(haCfg.RequestProcessors))
+	}
+	rp := haCfg.RequestProcessors[0]
+	if rp.Id != "SMS" || !rp.Flags[utils.MetaEvent] || !rp.Flags[utils.MetaCDR] ||
+		len(rp.RequestFilter) != 1 || len(rp.RequestFields) != 1 || rp.RequestFields[0].FieldId != utils.Account {
+		t.Errorf("unexpected processor: %s", utils.ToJSON(rp))
+	}
+}
+
 func TestRadiusAgentCfg(t *testing.T) {
 	testRA := &RadiusAgentCfg{
 		Enabled:            false,
@@ -878,3 +1006,64 @@ func TestRadiusAgentCfg(t *testing.T) {
 		t.Errorf("received: %+v, expecting: %+v", cgrCfg.radiusAgentCfg.RequestProcessors, testRA.RequestProcessors)
 	}
 }
+
+func TestRadiusAgentCfgDynAuth(t *testing.T) {
+	if cgrCfg.radiusAgentCfg.DynAuthAddress != "" {
+		t.Errorf("received: %s", cgrCfg.radiusAgentCfg.DynAuthAddress)
+	}
+	if cgrCfg.radiusAgentCfg.DynAuthSessionTTL != time.Duration(3*time.Hour) {
+		t.Errorf("received: %v", cgrCfg.radiusAgentCfg.DynAuthSessionTTL)
+	}
+	if len(cgrCfg.radiusAgentCfg.DisconnectFields) != 0 || len(cgrCfg.radiusAgentCfg.CoAFields) != 0 {
+		t.Errorf("received: %+v", cgrCfg.radiusAgentCfg)
+	}
+	jsnCfg := `
+{
+"rals": {
+	"enabled": true,
+},
+"cdrs": {
+	"enabled": true,
+},
+"sm_generic": {
+	"enabled": true,
+},
+"radius_agent": {
+	"enabled": true,
+	"dynauth_address": "127.0.0.1:3799",
+	"dynauth_session_ttl": "1h",
+	"disconnect_fields": [
+		{"tag": "SessionID", "field_id": "Acct-Session-Id", "type": "*composed", "value": "OriginID"},
+	],
+	"request_processors": [
+		{
+			"id": "kamailio_acct",
+			"dynauth_address": "127.0.0.2:3799",
+			"coa_fields": [
+				{"tag": "Filter", "field_id": "Filter-Id", "type": "*constant", "value": "^voice"},
+			],
+		},
+	],
+},
+}`
+	cfg, err := NewCGRConfigFromJsonStringWithDefaults(jsnCfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	raCfg := cfg.RadiusAgentCfg()
+	if raCfg.DynAuthAddress != "127.0.0.1:3799" || raCfg.DynAuthSessionTTL != time.Hour ||
+		len(raCfg.DisconnectFields) != 1 || raCfg.DisconnectFields[0].FieldId != "Acct-Session-Id" {
+		t.Errorf("received: %s", utils.ToJSON(raCfg))
+	}
+	if len(raCfg.RequestProcessors) != 1 || raCfg.RequestProcessors[0].DynAuthAddress != "127.0.0.2:3799" ||
+		len(raCfg.RequestProcessors[0].CoAFields) != 1 {
+		t.Errorf("received: %s", utils.ToJSON(raCfg.RequestProcessors))
+	}
+	if err := cfg.checkConfigSanity(); err != nil {
+		t.Error(err)
+	}
+	raCfg.SMGenericConns = []*HaPoolConfig{&HaPoolConfig{Address: "127.0.0.1:2012"}}
+	if err := cfg.checkConfigSanity(); err == nil {
+		t.Error("expecting error on non *internal SMGeneric connection")
+	}
+}
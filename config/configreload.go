@@ -0,0 +1,254 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package config
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+
+	"github.com/cgrates/cgrates/utils"
+)
+
+// hotReloadFields lists per section the options which can be applied on a running engine,
+// utils.META_ANY marks the sections which can be reloaded as a whole.
+// Only sections read through locked accessors are listed, the exported fields are read unlocked by the services
+var hotReloadFields = map[string]utils.StringMap{
+	CACHE_JSN:       utils.NewStringMap(utils.META_ANY),
+	CDRE_JSN:        utils.NewStringMap(utils.META_ANY),
+	CDRC_JSN:        utils.NewStringMap(utils.META_ANY),
+	ATTRIBUTE_JSN:   utils.NewStringMap("indexed_fields"),
+	STATS_JSON:      utils.NewStringMap("indexed_fields"),
+	THRESHOLDS_JSON: utils.NewStringMap("indexed_fields"),
+	SupplierSJson:   utils.NewStringMap("indexed_fields"),
+}
+
+// secretJSONFields are masked out when exporting the configuration
+var secretJSONFields = utils.NewStringMap("password", "db_password", "auth_password",
+	"validation_key", "auth_users", "client_secrets")
+
+// mergeRawJSON merges the sections out of jsnCfg over the effective JSON configuration
+func (cfg *CGRConfig) mergeRawJSON(jsnCfg *CgrJsonCfg) error {
+	cfg.rawCfgMux.Lock()
+	defer cfg.rawCfgMux.Unlock()
+	if cfg.rawCfg == nil {
+		cfg.rawCfg = make(map[string]interface{})
+	}
+	for section, rawMsg := range *jsnCfg {
+		if rawMsg == nil {
+			continue
+		}
+		var val interface{}
+		if err := json.Unmarshal(*rawMsg, &val); err != nil {
+			return err
+		}
+		cfg.rawCfg[section] = mergeJSONValues(cfg.rawCfg[section], val)
+	}
+	return nil
+}
+
+// mergeJSONValues overwrites dst with src, objects are merged key by key
+func mergeJSONValues(dst, src interface{}) interface{} {
+	dstMp, dstIsMp := dst.(map[string]interface{})
+	srcMp, srcIsMp := src.(map[string]interface{})
+	if !dstIsMp || !srcIsMp {
+		return src
+	}
+	mrgd := make(map[string]interface{}, len(dstMp)) // new map so we do not alter values shared with other configs
+	for k, v := range dstMp {
+		mrgd[k] = v
+	}
+	for k, v := range srcMp {
+		mrgd[k] = mergeJSONValues(mrgd[k], v)
+	}
+	return mrgd
+}
+
+// maskJSONSecrets returns a copy of val with the secretJSONFields masked
+func maskJSONSecrets(val interface{}) interface{} {
+	switch v := val.(type) {
+	case map[string]interface{}:
+		mskd := make(map[string]interface{}, len(v))
+		for k, fldVal := range v {
+			if secretJSONFields[k] {
+				mskd[k] = maskJSONValue(fldVal)
+			} else {
+				mskd[k] = maskJSONSecrets(fldVal)
+			}
+		}
+		return mskd
+	case []interface{}:
+		mskd := make([]interface{}, len(v))
+		for i, itm := range v {
+			mskd[i] = maskJSONSecrets(itm)
+		}
+		return mskd
+	}
+	return val
+}
+
+// maskJSONValue replaces the non empty strings in val, including the ones in objects, with utils.MaskedSecret
+func maskJSONValue(val interface{}) interface{} {
+	switch v := val.(type) {
+	case string:
+		if v != "" {
+			return utils.MaskedSecret
+		}
+	case map[string]interface{}:
+		mskd := make(map[string]interface{}, len(v))
+		for k, fldVal := range v {
+			mskd[k] = maskJSONValue(fldVal)
+		}
+		return mskd
+	}
+	return val
+}
+
+// GetJSONSection returns the effective configuration of a section, in the layout of the .json files and with secrets masked
+func (cfg *CGRConfig) GetJSONSection(section string) (interface{}, error) {
+	cfg.rawCfgMux.RLock()
+	defer cfg.rawCfgMux.RUnlock()
+	val, has := cfg.rawCfg[section]
+	if !has {
+		return nil, utils.ErrNotFound
+	}
+	return maskJSONSecrets(val), nil
+}
+
+// GetJSONConfig returns the effective configuration of all sections, in the layout of the .json files and with secrets masked
+func (cfg *CGRConfig) GetJSONConfig() map[string]interface{} {
+	cfg.rawCfgMux.RLock()
+	defer cfg.rawCfgMux.RUnlock()
+	return maskJSONSecrets(cfg.rawCfg).(map[string]interface{})
+}
+
+// changedJSONFields returns the sections differing in newCfg together with the changed options,
+// nil options for sections which are not JSON objects
+func (cfg *CGRConfig) changedJSONFields(newCfg *CGRConfig) (chngd map[string][]string) {
+	cfg.rawCfgMux.RLock()
+	defer cfg.rawCfgMux.RUnlock()
+	newCfg.rawCfgMux.RLock()
+	defer newCfg.rawCfgMux.RUnlock()
+	chngd = make(map[string][]string)
+	sections := make(utils.StringMap)
+	for section := range cfg.rawCfg {
+		sections[section] = true
+	}
+	for section := range newCfg.rawCfg {
+		sections[section] = true
+	}
+	for section := range sections {
+		oldVal, newVal := cfg.rawCfg[section], newCfg.rawCfg[section]
+		if reflect.DeepEqual(oldVal, newVal) {
+			continue
+		}
+		oldMp, oldIsMp := oldVal.(map[string]interface{})
+		newMp, newIsMp := newVal.(map[string]interface{})
+		if !oldIsMp || !newIsMp {
+			chngd[section] = nil
+			continue
+		}
+		var flds []string
+		for fld := range mergeJSONValues(oldMp, newMp).(map[string]interface{}) {
+			if !reflect.DeepEqual(oldMp[fld], newMp[fld]) {
+				flds = append(flds, fld)
+			}
+		}
+		sort.Strings(flds)
+		chngd[section] = flds
+	}
+	return
+}
+
+// ReloadConfig applies on the running configuration the sections changed in newCfg which support runtime reloads.
+// Returns the changed sections split in reloaded ones and ones needing an engine restart
+func (cfg *CGRConfig) ReloadConfig(newCfg *CGRConfig) (reloaded, restartRequired []string, err error) {
+	chngd := cfg.changedJSONFields(newCfg)
+	sections := make([]string, 0, len(chngd))
+	for section := range chngd {
+		sections = append(sections, section)
+	}
+	sort.Strings(sections)
+	for _, section := range sections {
+		hotFlds, canReload := hotReloadFields[section]
+		if canReload && !hotFlds[utils.META_ANY] {
+			if chngd[section] == nil {
+				canReload = false
+			}
+			for _, fld := range chngd[section] {
+				if !hotFlds[fld] {
+					canReload = false
+					break
+				}
+			}
+		}
+		if !canReload {
+			restartRequired = append(restartRequired, section)
+			continue
+		}
+		if err = cfg.reloadSection(newCfg, section); err != nil {
+			return nil, nil, err
+		}
+		reloaded = append(reloaded, section)
+	}
+	return
+}
+
+// reloadSection applies the section out of newCfg, only hot reloadable options differing from the running ones
+func (cfg *CGRConfig) reloadSection(newCfg *CGRConfig, section string) (err error) {
+	newCfg.rawCfgMux.RLock()
+	newVal := newCfg.rawCfg[section]
+	newCfg.rawCfgMux.RUnlock()
+	switch section {
+	case CDRC_JSN: // profiles are replaced as a whole, same as ApierV1.ReloadCdrcConfig
+		cfg.CdrcProfiles = newCfg.CdrcProfiles
+		select {
+		case cfg.ConfigReloads[utils.CDRC] <- struct{}{}:
+		default: // reload already pending
+		}
+	case CDRE_JSN:
+		cdreReloadStruct := <-cfg.ConfigReloads[utils.CDRE] // Lock CDRE reads
+		cfg.CdreProfiles = newCfg.CdreProfiles
+		cfg.ConfigReloads[utils.CDRE] <- cdreReloadStruct
+	default: // newCfg was loaded apart, its values are swapped in so the running ones are never partially updated
+		cfg.cfgMux.Lock()
+		cfg.swapSection(newCfg, section)
+		cfg.cfgMux.Unlock()
+	}
+	cfg.rawCfgMux.Lock()
+	cfg.rawCfg[section] = newVal
+	cfg.rawCfgMux.Unlock()
+	return
+}
+
+// swapSection replaces the values of a hot reloadable section with the ones in newCfg, cfgMux should be locked
+func (cfg *CGRConfig) swapSection(newCfg *CGRConfig, section string) {
+	switch section {
+	case CACHE_JSN:
+		cfg.cacheConfig = newCfg.cacheConfig
+	case ATTRIBUTE_JSN:
+		cfg.attributeSCfg = newCfg.attributeSCfg
+	case STATS_JSON:
+		cfg.statsCfg = newCfg.statsCfg
+	case THRESHOLDS_JSON:
+		cfg.thresholdSCfg = newCfg.thresholdSCfg
+	case SupplierSJson:
+		cfg.supplierSCfg = newCfg.supplierSCfg
+	}
+}
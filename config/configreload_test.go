@@ -0,0 +1,142 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package config
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/cgrates/cgrates/utils"
+)
+
+func TestCgrCfgGetJSONSection(t *testing.T) {
+	cgrCfg, err := NewCGRConfigFromJsonStringWithDefaults(`{
+"stor_db": {"db_password": "CGRateS.org"},
+"http": {"auth_users": {"cgrates": "cGFzc3dvcmQ="}},
+"thresholds": {"indexed_fields": ["Account"]},
+}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cgrCfg.GetJSONSection("not_a_section"); err != utils.ErrNotFound {
+		t.Errorf("Expecting: %v, received: %v", utils.ErrNotFound, err)
+	}
+	sectionVal, err := cgrCfg.GetJSONSection(THRESHOLDS_JSON)
+	if err != nil {
+		t.Fatal(err)
+	}
+	eThdS := map[string]interface{}{
+		"enabled":        false,
+		"store_interval": "",
+		"indexed_fields": []interface{}{"Account"},
+	}
+	if !reflect.DeepEqual(eThdS, sectionVal) {
+		t.Errorf("Expecting: %+v, received: %+v", eThdS, sectionVal)
+	}
+	jsnCfg := cgrCfg.GetJSONConfig()
+	if storDB := jsnCfg[STORDB_JSN].(map[string]interface{}); storDB["db_password"] != utils.MaskedSecret {
+		t.Errorf("Unmasked db_password: %+v", storDB["db_password"])
+	} else if storDB["db_type"] != "mysql" {
+		t.Errorf("Unexpected db_type: %+v", storDB["db_type"])
+	}
+	eAuthUsers := map[string]interface{}{"cgrates": utils.MaskedSecret}
+	if authUsers := jsnCfg[HTTP_JSN].(map[string]interface{})["auth_users"]; !reflect.DeepEqual(eAuthUsers, authUsers) {
+		t.Errorf("Expecting: %+v, received: %+v", eAuthUsers, authUsers)
+	}
+	if cgrCfg.StorDBPass != "CGRateS.org" { // masking should not alter the loaded config
+		t.Errorf("Unexpected StorDBPass: %s", cgrCfg.StorDBPass)
+	}
+}
+
+func TestCgrCfgReloadConfig(t *testing.T) {
+	cgrCfg, err := NewDefaultCGRConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	newCfg, err := NewCGRConfigFromJsonStringWithDefaults(`{
+"general": {"rounding_decimals": 3},
+"listen": {"rpc_json": "127.0.0.1:3012"},
+"sm_generic": {"debit_interval": "10s"},
+"thresholds": {"indexed_fields": ["Account"]},
+"stats": {"enabled": true, "indexed_fields": ["Account"]},
+}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	reloaded, restartRequired, err := cgrCfg.ReloadConfig(newCfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	eReloaded := []string{THRESHOLDS_JSON}
+	if !reflect.DeepEqual(eReloaded, reloaded) {
+		t.Errorf("Expecting: %+v, received: %+v", eReloaded, reloaded)
+	}
+	eRestartRequired := []string{GENERAL_JSN, LISTEN_JSN, SMGENERIC_JSON, STATS_JSON}
+	if !reflect.DeepEqual(eRestartRequired, restartRequired) {
+		t.Errorf("Expecting: %+v, received: %+v", eRestartRequired, restartRequired)
+	}
+	if !reflect.DeepEqual([]string{"Account"}, cgrCfg.ThresholdSCfg().IndexedFields) {
+		t.Errorf("Unexpected thresholds IndexedFields: %v", cgrCfg.ThresholdSCfg().IndexedFields)
+	}
+	// exported fields are read without locks by the running services so they wait for a restart
+	if cgrCfg.RPCJSONListen == "127.0.0.1:3012" || cgrCfg.StatSCfg().Enabled ||
+		cgrCfg.RoundingDecimals == 3 || cgrCfg.SmGenericConfig.DebitInterval == newCfg.SmGenericConfig.DebitInterval {
+		t.Error("Applied section which requires restart")
+	}
+	if sectionVal, err := cgrCfg.GetJSONSection(THRESHOLDS_JSON); err != nil {
+		t.Error(err)
+	} else if flds := sectionVal.(map[string]interface{})["indexed_fields"]; !reflect.DeepEqual([]interface{}{"Account"}, flds) {
+		t.Errorf("Unexpected indexed_fields: %v", flds)
+	}
+	// second reload keeps reporting the sections still waiting for a restart
+	if reloaded, restartRequired, err = cgrCfg.ReloadConfig(newCfg); err != nil {
+		t.Error(err)
+	} else if len(reloaded) != 0 {
+		t.Errorf("Unexpected reloaded: %+v", reloaded)
+	} else if !reflect.DeepEqual(eRestartRequired, restartRequired) {
+		t.Errorf("Expecting: %+v, received: %+v", eRestartRequired, restartRequired)
+	}
+}
+
+func TestCgrCfgReloadConfigSwapsSections(t *testing.T) {
+	cgrCfg, err := NewDefaultCGRConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	thdSCfg := cgrCfg.ThresholdSCfg()
+	newCfg, err := NewCGRConfigFromJsonStringWithDefaults(`{
+"cache": {"destinations": {"limit": 10}},
+"thresholds": {"indexed_fields": ["Account"]},
+}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := cgrCfg.ReloadConfig(newCfg); err != nil {
+		t.Fatal(err)
+	}
+	if len(thdSCfg.IndexedFields) != 0 {
+		t.Errorf("running section altered in place: %+v", thdSCfg.IndexedFields)
+	}
+	if cgrCfg.ThresholdSCfg() != newCfg.ThresholdSCfg() {
+		t.Error("thresholds section not swapped in")
+	}
+	if lmt := cgrCfg.CacheCfg()[utils.CacheDestinations].Limit; lmt != 10 {
+		t.Errorf("Unexpected destinations limit: %d", lmt)
+	}
+}
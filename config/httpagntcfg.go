@@ -0,0 +1,127 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package config
+
+import (
+	"github.com/cgrates/cgrates/utils"
+)
+
+type HttpAgentCfg struct {
+	Enabled           bool
+	Url               string          // path where the agent listens on the HTTP server
+	SMGenericConns    []*HaPoolConfig // connections towards SMG component
+	RequestPayload    string          // encoding of the requests <*url|*json>
+	ReplyPayload      string          // encoding of the replies <*url|*json>
+	Timezone          string
+	RequestProcessors []*HttpAgntProcCfg
+}
+
+func (self *HttpAgentCfg) loadFromJsonCfg(jsnCfg *HttpAgentJsonCfg) error {
+	if jsnCfg == nil {
+		return nil
+	}
+	if jsnCfg.Enabled != nil {
+		self.Enabled = *jsnCfg.Enabled
+	}
+	if jsnCfg.Url != nil {
+		self.Url = *jsnCfg.Url
+	}
+	if jsnCfg.Sm_generic_conns != nil {
+		self.SMGenericConns = make([]*HaPoolConfig, len(*jsnCfg.Sm_generic_conns))
+		for idx, jsnHaCfg := range *jsnCfg.Sm_generic_conns {
+			self.SMGenericConns[idx] = NewDfltHaPoolConfig()
+			self.SMGenericConns[idx].loadFromJsonCfg(jsnHaCfg)
+		}
+	}
+	if jsnCfg.Request_payload != nil {
+		self.RequestPayload = *jsnCfg.Request_payload
+	}
+	if jsnCfg.Reply_payload != nil {
+		self.ReplyPayload = *jsnCfg.Reply_payload
+	}
+	if jsnCfg.Timezone != nil {
+		self.Timezone = *jsnCfg.Timezone
+	}
+	if jsnCfg.Request_processors != nil {
+		for _, reqProcJsn := range *jsnCfg.Request_processors {
+			rp := new(HttpAgntProcCfg)
+			var haveID bool
+			for _, rpSet := range self.RequestProcessors {
+				if reqProcJsn.Id != nil && rpSet.Id == *reqProcJsn.Id {
+					rp = rpSet // Will load data into the one set
+					haveID = true
+					break
+				}
+			}
+			if err := rp.loadFromJsonCfg(reqProcJsn); err != nil {
+				return err
+			}
+			if !haveID {
+				self.RequestProcessors = append(self.RequestProcessors, rp)
+			}
+		}
+	}
+	return nil
+}
+
+// One HTTP request processor configuration
+type HttpAgntProcCfg struct {
+	Id                string
+	DryRun            bool
+	RequestFilter     utils.RSRFields
+	Flags             utils.StringMap // actions towards SMG <*auth|*initiate|*update|*terminate|*event|*cdr>
+	ContinueOnSuccess bool
+	RequestFields     []*CfgCdrField
+	ReplyFields       []*CfgCdrField
+}
+
+func (self *HttpAgntProcCfg) loadFromJsonCfg(jsnCfg *HttpAgentProcessorJsnCfg) error {
+	if jsnCfg == nil {
+		return nil
+	}
+	if jsnCfg.Id != nil {
+		self.Id = *jsnCfg.Id
+	}
+	if jsnCfg.Dry_run != nil {
+		self.DryRun = *jsnCfg.Dry_run
+	}
+	var err error
+	if jsnCfg.Request_filter != nil {
+		if self.RequestFilter, err = utils.ParseRSRFields(*jsnCfg.Request_filter, utils.INFIELD_SEP); err != nil {
+			return err
+		}
+	}
+	if jsnCfg.Flags != nil {
+		self.Flags = utils.StringMapFromSlice(*jsnCfg.Flags)
+	}
+	if jsnCfg.Continue_on_success != nil {
+		self.ContinueOnSuccess = *jsnCfg.Continue_on_success
+	}
+	if jsnCfg.Request_fields != nil {
+		if self.RequestFields, err = CfgCdrFieldsFromCdrFieldsJsonCfg(*jsnCfg.Request_fields); err != nil {
+			return err
+		}
+	}
+	if jsnCfg.Reply_fields != nil {
+		if self.ReplyFields, err = CfgCdrFieldsFromCdrFieldsJsonCfg(*jsnCfg.Reply_fields); err != nil {
+			return err
+		}
+	}
+	return nil
+}
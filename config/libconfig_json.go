@@ -23,6 +23,7 @@ type GeneralJsonCfg struct {
 	Instance_id          *string
 	Logger               *string
 	Log_level            *int
+	Log_format           *string
 	Http_skip_tls_verify *bool
 	Rounding_decimals    *int
 	Dbdata_encoding      *string
@@ -33,6 +34,7 @@ type GeneralJsonCfg struct {
 	Default_category     *string
 	Default_tenant       *string
 	Default_timezone     *string
+	Default_currency     *string
 	Connect_attempts     *int
 	Reconnects           *int
 	Connect_timeout      *string
@@ -53,6 +55,8 @@ type ListenJsonCfg struct {
 type HTTPJsonCfg struct {
 	Json_rpc_url   *string
 	Ws_url         *string
+	Metrics_url    *string
+	Health_url     *string
 	Use_basic_auth *bool
 	Auth_users     *map[string]string
 }
@@ -70,6 +74,8 @@ type DbJsonCfg struct {
 	Conn_max_lifetime *int // Used only in case of storDb
 	Load_history_size *int // Used in case of dataDb to limit the length of the loads history
 	Cdrs_indexes      *[]string
+	Snapshot_path     *string // Used only in case of *internal
+	Snapshot_interval *string // Used only in case of *internal
 }
 
 // Filters config
@@ -91,6 +97,9 @@ type RalsJsonCfg struct {
 	Rp_subject_prefix_matching  *bool
 	Lcr_subject_prefix_matching *bool
 	Max_computed_usage          *map[string]string
+	Balance_events_interval     *string
+	Balance_expiring_lead_times *[]string
+	Balance_events_post_urls    *[]string
 }
 
 // Scheduler config section
@@ -104,6 +113,7 @@ type CdrsJsonCfg struct {
 	Extra_fields       *[]string
 	Store_cdrs         *bool
 	Sm_cost_retries    *int
+	Apply_taxes        *bool
 	Rals_conns         *[]*HaPoolJsonCfg
 	Pubsubs_conns      *[]*HaPoolJsonCfg
 	Attributes_conns   *[]*HaPoolJsonCfg
@@ -209,6 +219,7 @@ type SmGenericJsonCfg struct {
 	Session_ttl_last_used *string
 	Session_ttl_usage     *string
 	Session_indexes       *[]string
+	Event_reservation_ttl *string
 }
 
 // SM-FreeSWITCH config section
@@ -259,10 +270,16 @@ type CacheParamJsonCfg struct {
 	Ttl        *string
 	Static_ttl *bool
 	Precache   *bool
+	Replicate  *bool
 }
 
 type CacheJsonCfg map[string]*CacheParamJsonCfg
 
+// Cache replication config
+type CacheReplicationJsonCfg struct {
+	Replication_conns *[]*HaPoolJsonCfg
+}
+
 // Represents one connection instance towards FreeSWITCH
 type FsConnJsonCfg struct {
 	Address    *string
@@ -352,6 +369,10 @@ type RadiusAgentJsonCfg struct {
 	Create_cdr           *bool
 	Cdr_requires_session *bool
 	Timezone             *string
+	Dynauth_address      *string
+	Dynauth_session_ttl  *string
+	Disconnect_fields    *[]*CdrFieldJsonCfg
+	Coa_fields           *[]*CdrFieldJsonCfg
 	Request_processors   *[]*RAReqProcessorJsnCfg
 }
 
@@ -364,6 +385,30 @@ type RAReqProcessorJsnCfg struct {
 	Append_reply        *bool
 	Request_fields      *[]*CdrFieldJsonCfg
 	Reply_fields        *[]*CdrFieldJsonCfg
+	Dynauth_address     *string
+	Disconnect_fields   *[]*CdrFieldJsonCfg
+	Coa_fields          *[]*CdrFieldJsonCfg
+}
+
+// HTTP Agent configuration section
+type HttpAgentJsonCfg struct {
+	Enabled            *bool
+	Url                *string
+	Sm_generic_conns   *[]*HaPoolJsonCfg
+	Request_payload    *string
+	Reply_payload      *string
+	Timezone           *string
+	Request_processors *[]*HttpAgentProcessorJsnCfg
+}
+
+type HttpAgentProcessorJsnCfg struct {
+	Id                  *string
+	Dry_run             *bool
+	Request_filter      *string
+	Flags               *[]string
+	Continue_on_success *bool
+	Request_fields      *[]*CdrFieldJsonCfg
+	Reply_fields        *[]*CdrFieldJsonCfg
 }
 
 // History server config section
@@ -413,9 +458,11 @@ type StatServJsonCfg struct {
 
 // Threshold service config section
 type ThresholdSJsonCfg struct {
-	Enabled        *bool
-	Store_interval *string
-	Indexed_fields *[]string
+	Enabled         *bool
+	Store_interval  *string
+	Indexed_fields  *[]string
+	Stats_conns     *[]*HaPoolJsonCfg
+	Resources_conns *[]*HaPoolJsonCfg
 }
 
 // Supplier service config section
@@ -19,6 +19,8 @@ along with this program.  If not, see <http://www.gnu.org/licenses/>
 package config
 
 import (
+	"time"
+
 	"github.com/cgrates/cgrates/utils"
 )
 
@@ -33,10 +35,14 @@ type RadiusAgentCfg struct {
 	CreateCDR          bool
 	CDRRequiresSession bool
 	Timezone           string
+	DynAuthAddress     string         // NAS address receiving CoA and Disconnect-Message requests (RFC 5176), empty to disable
+	DynAuthSessionTTL  time.Duration  // sessions without Accounting-Stop are not reachable over CoA/DM after this interval
+	DisconnectFields   []*CfgCdrField // Disconnect-Request template, populated out of session data
+	CoAFields          []*CfgCdrField // CoA-Request template, populated out of session data
 	RequestProcessors  []*RARequestProcessor
 }
 
-func (self *RadiusAgentCfg) loadFromJsonCfg(jsnCfg *RadiusAgentJsonCfg) error {
+func (self *RadiusAgentCfg) loadFromJsonCfg(jsnCfg *RadiusAgentJsonCfg) (err error) {
 	if jsnCfg == nil {
 		return nil
 	}
@@ -84,6 +90,24 @@ func (self *RadiusAgentCfg) loadFromJsonCfg(jsnCfg *RadiusAgentJsonCfg) error {
 	if jsnCfg.Timezone != nil {
 		self.Timezone = *jsnCfg.Timezone
 	}
+	if jsnCfg.Dynauth_address != nil {
+		self.DynAuthAddress = *jsnCfg.Dynauth_address
+	}
+	if jsnCfg.Dynauth_session_ttl != nil {
+		if self.DynAuthSessionTTL, err = utils.ParseDurationWithNanosecs(*jsnCfg.Dynauth_session_ttl); err != nil {
+			return err
+		}
+	}
+	if jsnCfg.Disconnect_fields != nil {
+		if self.DisconnectFields, err = CfgCdrFieldsFromCdrFieldsJsonCfg(*jsnCfg.Disconnect_fields); err != nil {
+			return err
+		}
+	}
+	if jsnCfg.Coa_fields != nil {
+		if self.CoAFields, err = CfgCdrFieldsFromCdrFieldsJsonCfg(*jsnCfg.Coa_fields); err != nil {
+			return err
+		}
+	}
 	if jsnCfg.Request_processors != nil {
 		for _, reqProcJsn := range *jsnCfg.Request_processors {
 			rp := new(RARequestProcessor)
@@ -116,6 +140,9 @@ type RARequestProcessor struct {
 	AppendReply       bool
 	RequestFields     []*CfgCdrField
 	ReplyFields       []*CfgCdrField
+	DynAuthAddress    string         // overwrites the agent dynauth_address for sessions started by this processor
+	DisconnectFields  []*CfgCdrField // overwrites the agent disconnect_fields
+	CoAFields         []*CfgCdrField // overwrites the agent coa_fields
 }
 
 func (self *RARequestProcessor) loadFromJsonCfg(jsnCfg *RAReqProcessorJsnCfg) error {
@@ -153,5 +180,18 @@ func (self *RARequestProcessor) loadFromJsonCfg(jsnCfg *RAReqProcessorJsnCfg) er
 			return err
 		}
 	}
+	if jsnCfg.Dynauth_address != nil {
+		self.DynAuthAddress = *jsnCfg.Dynauth_address
+	}
+	if jsnCfg.Disconnect_fields != nil {
+		if self.DisconnectFields, err = CfgCdrFieldsFromCdrFieldsJsonCfg(*jsnCfg.Disconnect_fields); err != nil {
+			return err
+		}
+	}
+	if jsnCfg.Coa_fields != nil {
+		if self.CoAFields, err = CfgCdrFieldsFromCdrFieldsJsonCfg(*jsnCfg.Coa_fields); err != nil {
+			return err
+		}
+	}
 	return nil
 }
@@ -102,6 +102,7 @@ type SmGenericConfig struct {
 	SessionTTLLastUsed  *time.Duration
 	SessionTTLUsage     *time.Duration
 	SessionIndexes      utils.StringMap
+	EventReservationTTL time.Duration
 }
 
 func (self *SmGenericConfig) loadFromJsonCfg(jsnCfg *SmGenericJsonCfg) error {
@@ -173,6 +174,11 @@ func (self *SmGenericConfig) loadFromJsonCfg(jsnCfg *SmGenericJsonCfg) error {
 	if jsnCfg.Session_indexes != nil {
 		self.SessionIndexes = utils.StringMapFromSlice(*jsnCfg.Session_indexes)
 	}
+	if jsnCfg.Event_reservation_ttl != nil {
+		if self.EventReservationTTL, err = utils.ParseDurationWithNanosecs(*jsnCfg.Event_reservation_ttl); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -25,9 +25,11 @@ import (
 )
 
 type ThresholdSCfg struct {
-	Enabled       bool
-	StoreInterval time.Duration // Dump regularly from cache into dataDB
-	IndexedFields []string
+	Enabled        bool
+	StoreInterval  time.Duration // Dump regularly from cache into dataDB
+	IndexedFields  []string
+	StatSConns     []*HaPoolConfig // used by threshold actions towards StatS
+	ResourceSConns []*HaPoolConfig // used by threshold actions towards ResourceS
 }
 
 func (t *ThresholdSCfg) loadFromJsonCfg(jsnCfg *ThresholdSJsonCfg) (err error) {
@@ -48,5 +50,19 @@ func (t *ThresholdSCfg) loadFromJsonCfg(jsnCfg *ThresholdSJsonCfg) (err error) {
 			t.IndexedFields[i] = fID
 		}
 	}
+	if jsnCfg.Stats_conns != nil {
+		t.StatSConns = make([]*HaPoolConfig, len(*jsnCfg.Stats_conns))
+		for idx, jsnHaCfg := range *jsnCfg.Stats_conns {
+			t.StatSConns[idx] = NewDfltHaPoolConfig()
+			t.StatSConns[idx].loadFromJsonCfg(jsnHaCfg)
+		}
+	}
+	if jsnCfg.Resources_conns != nil {
+		t.ResourceSConns = make([]*HaPoolConfig, len(*jsnCfg.Resources_conns))
+		for idx, jsnHaCfg := range *jsnCfg.Resources_conns {
+			t.ResourceSConns[idx] = NewDfltHaPoolConfig()
+			t.ResourceSConns[idx].loadFromJsonCfg(jsnHaCfg)
+		}
+	}
 	return nil
 }
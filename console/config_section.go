@@ -0,0 +1,64 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package console
+
+import (
+	"github.com/cgrates/cgrates/apier/v1"
+)
+
+func init() {
+	c := &CmdGetConfigSection{
+		name:      "config_section",
+		rpcMethod: "ConfigSv1.GetJSONSection",
+	}
+	commands[c.Name()] = c
+	c.CommandExecuter = &CommandExecuter{c}
+}
+
+// Commander implementation
+type CmdGetConfigSection struct {
+	name      string
+	rpcMethod string
+	rpcParams *v1.AttrGetJSONSection
+	*CommandExecuter
+}
+
+func (self *CmdGetConfigSection) Name() string {
+	return self.name
+}
+
+func (self *CmdGetConfigSection) RpcMethod() string {
+	return self.rpcMethod
+}
+
+func (self *CmdGetConfigSection) RpcParams(reset bool) interface{} {
+	if reset || self.rpcParams == nil {
+		self.rpcParams = new(v1.AttrGetJSONSection)
+	}
+	return self.rpcParams
+}
+
+func (self *CmdGetConfigSection) PostprocessRpcParams() error {
+	return nil
+}
+
+func (self *CmdGetConfigSection) RpcResult() interface{} {
+	var s interface{}
+	return &s
+}
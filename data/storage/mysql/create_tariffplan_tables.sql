@@ -71,6 +71,7 @@ CREATE TABLE `tp_destination_rates` (
   `rounding_decimals` tinyint(4) NOT NULL,
   `max_cost` decimal(7,4) NOT NULL,
   `max_cost_strategy` varchar(16) NOT NULL,
+  `volume_counter` varchar(64) NOT NULL,
   `created_at` TIMESTAMP,
   PRIMARY KEY (`id`),
   KEY `tpid` (`tpid`),
@@ -90,6 +91,7 @@ CREATE TABLE `tp_rating_plans` (
   `destrates_tag` varchar(64) NOT NULL,
   `timing_tag` varchar(64) NOT NULL,
   `weight` DECIMAL(8,2) NOT NULL,
+  `currency` varchar(3) NOT NULL,
   `created_at` TIMESTAMP,
   PRIMARY KEY (`id`),
   KEY `tpid` (`tpid`),
@@ -241,6 +243,7 @@ CREATE TABLE `tp_account_actions` (
   `action_triggers_tag` varchar(64),
   `allow_negative` BOOLEAN NOT NULL,
   `disabled` BOOLEAN NOT NULL,
+  `fee_profile_ids` varchar(255) NOT NULL,
   `created_at` TIMESTAMP,
   PRIMARY KEY (`id`),
   KEY `tpid` (`tpid`),
@@ -477,7 +480,7 @@ CREATE TABLE tp_filters (
   `tpid` varchar(64) NOT NULL,
   `tenant` varchar(64) NOT NULL,
   `id` varchar(64) NOT NULL,
-  `filter_type` varchar(16) NOT NULL,
+  `filter_type` varchar(32) NOT NULL,
   `filter_field_name` varchar(64) NOT NULL,
   `filter_field_values` varchar(256) NOT NULL,
   `activation_interval` varchar(64) NOT NULL,
@@ -545,6 +548,67 @@ CREATE TABLE tp_attributes (
     `id`,`filter_ids`,`field_name`,`initial`,`substitute` )
 );
 
+--
+-- Table structure for table `tp_exchange_rates`
+--
+
+DROP TABLE IF EXISTS tp_exchange_rates;
+CREATE TABLE tp_exchange_rates (
+  `pk` int(11) NOT NULL AUTO_INCREMENT,
+  `tpid` varchar(64) NOT NULL,
+  `from_currency` varchar(3) NOT NULL,
+  `to_currency` varchar(3) NOT NULL,
+  `activation_time` varchar(64) NOT NULL,
+  `rate` DECIMAL(20,8) NOT NULL,
+  `created_at` TIMESTAMP,
+  PRIMARY KEY (`pk`),
+  KEY `tpid` (`tpid`),
+  UNIQUE KEY `unique_tp_exchange_rates` (`tpid`,`from_currency`,`to_currency`,`activation_time`)
+);
+
+--
+-- Table structure for table `tp_tax_profiles`
+--
+
+DROP TABLE IF EXISTS tp_tax_profiles;
+CREATE TABLE tp_tax_profiles (
+  `pk` int(11) NOT NULL AUTO_INCREMENT,
+  `tpid` varchar(64) NOT NULL,
+  `tenant` varchar(64) NOT NULL,
+  `id` varchar(64) NOT NULL,
+  `filter_ids` varchar(64) NOT NULL,
+  `activation_interval` varchar(64) NOT NULL,
+  `tax_id` varchar(64) NOT NULL,
+  `tax_type` varchar(16) NOT NULL,
+  `tax_value` DECIMAL(20,4) NOT NULL,
+  `tax_tiers` varchar(255) NOT NULL,
+  `weight` decimal(8,2) NOT NULL,
+  `created_at` TIMESTAMP,
+  PRIMARY KEY (`pk`),
+  KEY `tpid` (`tpid`),
+  UNIQUE KEY `unique_tp_tax_profiles` (`tpid`,`tenant`,`id`,`filter_ids`,`tax_id`)
+);
+
+--
+-- Table structure for table `tp_fee_profiles`
+--
+
+DROP TABLE IF EXISTS tp_fee_profiles;
+CREATE TABLE tp_fee_profiles (
+  `pk` int(11) NOT NULL AUTO_INCREMENT,
+  `tpid` varchar(64) NOT NULL,
+  `tenant` varchar(64) NOT NULL,
+  `id` varchar(64) NOT NULL,
+  `period` varchar(16) NOT NULL,
+  `amount` DECIMAL(20,4) NOT NULL,
+  `category` varchar(32) NOT NULL,
+  `pro_rate` BOOLEAN NOT NULL,
+  `created_at` TIMESTAMP,
+  PRIMARY KEY (`pk`),
+  KEY `tpid` (`tpid`),
+  UNIQUE KEY `unique_tp_fee_profiles` (`tpid`,`tenant`,`id`)
+);
+
 --
 -- Table structure for table `versions`
 --
@@ -68,6 +68,7 @@ CREATE TABLE tp_destination_rates (
   rounding_decimals SMALLINT NOT NULL,
   max_cost NUMERIC(7,4) NOT NULL,
   max_cost_strategy VARCHAR(16) NOT NULL,
+  volume_counter VARCHAR(64) NOT NULL,
   created_at TIMESTAMP WITH TIME ZONE,
   UNIQUE (tpid, tag , destinations_tag)
 );
@@ -86,6 +87,7 @@ CREATE TABLE tp_rating_plans (
   destrates_tag VARCHAR(64) NOT NULL,
   timing_tag VARCHAR(64) NOT NULL,
   weight NUMERIC(8,2) NOT NULL,
+  currency VARCHAR(3) NOT NULL,
   created_at TIMESTAMP WITH TIME ZONE,
   UNIQUE (tpid, tag, destrates_tag, timing_tag)
 );
@@ -236,6 +238,7 @@ CREATE TABLE tp_account_actions (
   action_triggers_tag VARCHAR(64),
   allow_negative BOOLEAN NOT NULL,
   disabled BOOLEAN NOT NULL,
+  fee_profile_ids VARCHAR(255) NOT NULL,
   created_at TIMESTAMP WITH TIME ZONE,
   UNIQUE (tpid, loadid, tenant, account)
 );
@@ -471,7 +474,7 @@ CREATE TABLE tp_filters (
   "tpid" varchar(64) NOT NULL,
   "tenant" varchar(64) NOT NULL,
   "id" varchar(64) NOT NULL,
-  "filter_type" varchar(16) NOT NULL,
+  "filter_type" varchar(32) NOT NULL,
   "filter_field_name" varchar(64) NOT NULL,
   "filter_field_values" varchar(256) NOT NULL,
   "activation_interval" varchar(64) NOT NULL,
@@ -535,6 +538,65 @@ CREATE INDEX tp_suppliers_unique ON tp_suppliers  ("tpid",  "tenant", "id",
     "filter_ids","field_name","initial","substitute");
 
 
+--
+-- Table structure for table `tp_exchange_rates`
+--
+
+DROP TABLE IF EXISTS tp_exchange_rates;
+CREATE TABLE tp_exchange_rates (
+  "pk" SERIAL PRIMARY KEY,
+  "tpid" varchar(64) NOT NULL,
+  "from_currency" varchar(3) NOT NULL,
+  "to_currency" varchar(3) NOT NULL,
+  "activation_time" varchar(64) NOT NULL,
+  "rate" NUMERIC(20,8) NOT NULL,
+  "created_at" TIMESTAMP WITH TIME ZONE
+);
+CREATE INDEX tp_exchange_rates_ids ON tp_exchange_rates (tpid);
+CREATE INDEX tp_exchange_rates_unique ON tp_exchange_rates ("tpid", "from_currency", "to_currency", "activation_time");
+
+--
+-- Table structure for table `tp_tax_profiles`
+--
+
+DROP TABLE IF EXISTS tp_tax_profiles;
+CREATE TABLE tp_tax_profiles (
+  "pk" SERIAL PRIMARY KEY,
+  "tpid" varchar(64) NOT NULL,
+  "tenant" varchar(64) NOT NULL,
+  "id" varchar(64) NOT NULL,
+  "filter_ids" varchar(64) NOT NULL,
+  "activation_interval" varchar(64) NOT NULL,
+  "tax_id" varchar(64) NOT NULL,
+  "tax_type" varchar(16) NOT NULL,
+  "tax_value" NUMERIC(20,4) NOT NULL,
+  "tax_tiers" varchar(255) NOT NULL,
+  "weight" decimal(8,2) NOT NULL,
+  "created_at" TIMESTAMP WITH TIME ZONE
+);
+CREATE INDEX tp_tax_profiles_ids ON tp_tax_profiles (tpid);
+CREATE INDEX tp_tax_profiles_unique ON tp_tax_profiles ("tpid", "tenant", "id",
+  "filter_ids", "tax_id");
+
+--
+-- Table structure for table `tp_fee_profiles`
+--
+
+DROP TABLE IF EXISTS tp_fee_profiles;
+CREATE TABLE tp_fee_profiles (
+  "pk" SERIAL PRIMARY KEY,
+  "tpid" varchar(64) NOT NULL,
+  "tenant" varchar(64) NOT NULL,
+  "id" varchar(64) NOT NULL,
+  "period" varchar(16) NOT NULL,
+  "amount" NUMERIC(20,4) NOT NULL,
+  "category" varchar(32) NOT NULL,
+  "pro_rate" BOOLEAN NOT NULL,
+  "created_at" TIMESTAMP WITH TIME ZONE
+);
+CREATE INDEX tp_fee_profiles_ids ON tp_fee_profiles (tpid);
+CREATE INDEX tp_fee_profiles_unique ON tp_fee_profiles ("tpid", "tenant", "id");
+
 --
 -- Table structure for table `versions`
 --
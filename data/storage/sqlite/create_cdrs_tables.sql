@@ -0,0 +1,59 @@
+--
+-- Table structure for table `cdrs`
+--
+
+DROP TABLE IF EXISTS cdrs;
+CREATE TABLE cdrs (
+ id INTEGER PRIMARY KEY AUTOINCREMENT,
+ cgrid VARCHAR(40) NOT NULL,
+ run_id VARCHAR(64) NOT NULL,
+ origin_host VARCHAR(64) NOT NULL,
+ source VARCHAR(64) NOT NULL,
+ origin_id VARCHAR(128) NOT NULL,
+ tor VARCHAR(16) NOT NULL,
+ request_type VARCHAR(24) NOT NULL,
+ tenant VARCHAR(64) NOT NULL,
+ category VARCHAR(32) NOT NULL,
+ account VARCHAR(128) NOT NULL,
+ subject VARCHAR(128) NOT NULL,
+ destination VARCHAR(128) NOT NULL,
+ setup_time DATETIME NOT NULL,
+ answer_time DATETIME NOT NULL,
+ usage BIGINT NOT NULL,
+ extra_fields TEXT NOT NULL,
+ cost_source VARCHAR(64) NOT NULL,
+ cost NUMERIC(20,4) DEFAULT NULL,
+ cost_details TEXT,
+ extra_info text,
+ created_at DATETIME,
+ updated_at DATETIME NULL,
+ deleted_at DATETIME NULL,
+ UNIQUE (cgrid, run_id, origin_id)
+);
+;
+DROP INDEX IF EXISTS deleted_at_cp_idx;
+CREATE INDEX deleted_at_cp_idx ON cdrs (deleted_at);
+
+
+DROP TABLE IF EXISTS sm_costs;
+CREATE TABLE sm_costs (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  cgrid VARCHAR(40) NOT NULL,
+  run_id  VARCHAR(64) NOT NULL,
+  origin_host VARCHAR(64) NOT NULL,
+  origin_id VARCHAR(64) NOT NULL,
+  cost_source VARCHAR(64) NOT NULL,
+  usage BIGINT NOT NULL,
+  cost_details TEXT,
+  created_at DATETIME,
+  deleted_at DATETIME NULL,
+  UNIQUE (cgrid, run_id)
+);
+DROP INDEX IF EXISTS cgrid_smcost_idx;
+CREATE INDEX cgrid_smcost_idx ON sm_costs (cgrid, run_id);
+DROP INDEX IF EXISTS origin_smcost_idx;
+CREATE INDEX origin_smcost_idx ON sm_costs (origin_host, origin_id);
+DROP INDEX IF EXISTS run_origin_smcost_idx;
+CREATE INDEX run_origin_smcost_idx ON sm_costs (run_id, origin_id);
+DROP INDEX IF EXISTS deleted_at_smcost_idx;
+CREATE INDEX deleted_at_smcost_idx ON sm_costs (deleted_at);
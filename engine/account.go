@@ -39,6 +39,12 @@ type Account struct {
 	BalanceMap        map[string]Balances
 	UnitCounters      UnitCounters
 	ActionTriggers    ActionTriggers
+	VolumeCounters    map[string]time.Duration // usage cumulated in the current period, used by volume tiered rates
+	Subscriptions     map[string]*Subscription // recurrent fees, indexed on FeeProfileID
+	Reservations      map[string]*Reservation  // credit held for sessions, indexed on reservation ID
+	ParentID          string                   // parent in the account hierarchy, its monetary balances back the debits of this account
+	CreditLimit       float64                  // maximum to consume out of the ancestors balances, 0 for unlimited
+	CreditUsed        float64                  // consumed so far out of the ancestors balances
 	AllowNegative     bool
 	Disabled          bool
 	executingTriggers bool
@@ -78,7 +84,8 @@ func (ub *Account) getCreditForPrefix(cd *CallDescriptor) (duration time.Duratio
 			extendedMinuteBalances = append(extendedMinuteBalances, mb)
 		}
 	}
-	credit = extendedCreditBalances.GetTotalValue()
+	credit = extendedCreditBalances.availableValue() +
+		ub.getAncestorCredit(cd.Destination, cd.Category, cd.Direction)
 	balances = extendedMinuteBalances
 	for _, b := range balances {
 		d, c := b.GetMinutesForCredit(cd, credit)
@@ -150,6 +157,9 @@ func (acc *Account) setBalanceAction(a *Action) error {
 	} else {
 		a.Balance.ModifyBalance(balance)
 	}
+	if !found {
+		acc.publishBalanceEvent(utils.BalanceCreated, balance, nil)
+	}
 	// modify if necessary the shared groups here
 	if !found || !previousSharedGroups.Equal(balance.SharedGroups) {
 		_, err := guardian.Guardian.Guard(func() (interface{}, error) {
@@ -244,6 +254,7 @@ func (ub *Account) debitBalanceAction(a *Action, reset bool) error {
 			}
 		}
 		ub.BalanceMap[balanceType] = append(ub.BalanceMap[balanceType], bClone)
+		ub.publishBalanceEvent(utils.BalanceCreated, bClone, nil)
 		_, err := guardian.Guardian.Guard(func() (interface{}, error) {
 			sgs := make([]string, len(bClone.SharedGroups))
 			i := 0
@@ -272,6 +283,7 @@ func (ub *Account) debitBalanceAction(a *Action, reset bool) error {
 			return err
 		}
 	}
+	ub.publishExhaustedBalances()
 	ub.InitCounters()
 	ub.ExecuteActionTriggers(nil)
 	return nil
@@ -302,6 +314,7 @@ func (ub *Account) getBalancesForPrefix(prefix, category, direction, tor string,
 			continue
 		}
 		b.account = ub
+		b.reserved = ub.reservedValue(b.Uuid)
 
 		if len(b.DestinationIDs) > 0 && b.DestinationIDs[utils.ANY] == false {
 			for _, p := range utils.SplitPrefix(prefix, MIN_PREFIX_MATCH) {
@@ -368,6 +381,9 @@ func (account *Account) getAlldBalancesForPrefix(destination, category, directio
 			bc = append(bc, b)
 		}
 	}
+	if balanceType == utils.MONETARY && account.ParentID != "" { // walk the hierarchy
+		bc = append(bc, account.getAncestorBalances(destination, category, direction)...)
+	}
 	return
 }
 
@@ -549,22 +565,24 @@ func (ub *Account) debitCreditBalance(cd *CallDescriptor, count bool, dryRun boo
 
 				cost := increment.Cost
 				defaultBalance := ub.GetDefaultMoneyBalance()
-				defaultBalance.SubstractValue(cost)
+				debit, xRate := defaultBalance.forceExchangeCost(cost, ts.RateInterval, ts.TimeStart)
+				defaultBalance.SubstractValue(debit)
 				increment.BalanceInfo.Monetary = &MonetaryInfo{
-					UUID:  defaultBalance.Uuid,
-					ID:    defaultBalance.ID,
-					Value: defaultBalance.Value,
+					UUID:         defaultBalance.Uuid,
+					ID:           defaultBalance.ID,
+					Value:        defaultBalance.Value,
+					ExchangeRate: xRate,
 				}
 				increment.BalanceInfo.AccountID = ub.ID
 				increment.paid = true
 				if count {
 					ub.countUnits(
-						cost,
+						debit,
 						utils.MONETARY,
 						leftCC,
 						&Balance{
 							Directions:     utils.StringMap{leftCC.Direction: true},
-							Value:          cost,
+							Value:          debit,
 							DestinationIDs: utils.NewStringMap(leftCC.Destination),
 						})
 				}
@@ -573,10 +591,15 @@ func (ub *Account) debitCreditBalance(cd *CallDescriptor, count bool, dryRun boo
 	}
 
 COMMIT:
+	if count {
+		ub.countVolumeUsage(cc)
+	}
 	if !dryRun {
 		// save darty shared balances
 		usefulMoneyBalances.SaveDirtyBalances(ub)
 		usefulUnitBalances.SaveDirtyBalances(ub)
+		usefulMoneyBalances.saveCreditLimiters(ub)
+		ub.publishExhaustedBalances()
 	}
 	//log.Printf("Final CC: %+v", cc)
 	return
@@ -762,8 +785,10 @@ func (acc *Account) CleanExpiredStuff() {
 	for key, bm := range acc.BalanceMap {
 		for i := 0; i < len(bm); i++ {
 			if bm[i].IsExpired() {
+				acc.publishBalanceEvent(utils.BalanceExpired, bm[i], nil)
 				// delete it
 				bm = append(bm[:i], bm[i+1:]...)
+				i--
 			}
 		}
 		acc.BalanceMap[key] = bm
@@ -774,6 +799,12 @@ func (acc *Account) CleanExpiredStuff() {
 			acc.ActionTriggers = append(acc.ActionTriggers[:i], acc.ActionTriggers[i+1:]...)
 		}
 	}
+
+	for rsvID, rsv := range acc.Reservations {
+		if rsv.IsExpired(time.Now()) {
+			delete(acc.Reservations, rsvID)
+		}
+	}
 }
 
 func (acc *Account) allBalancesExpired() bool {
@@ -821,6 +852,9 @@ func (account *Account) GetUniqueSharedGroupMembers(cd *CallDescriptor) (utils.S
 			memberIds[memberID] = true
 		}
 	}
+	for _, anc := range account.getAncestors() { // ancestors balances are debited too
+		memberIds[anc.ID] = true
+	}
 	return memberIds, nil
 }
 
@@ -836,26 +870,80 @@ func (acc *Account) Clone() *Account {
 		ActionTriggers: nil, // not used when cloned (dryRun)
 		AllowNegative:  acc.AllowNegative,
 		Disabled:       acc.Disabled,
+		ParentID:       acc.ParentID,
+		CreditLimit:    acc.CreditLimit,
+		CreditUsed:     acc.CreditUsed,
 	}
 	for key, balanceChain := range acc.BalanceMap {
 		newAcc.BalanceMap[key] = balanceChain.Clone()
 	}
+	if acc.VolumeCounters != nil {
+		newAcc.VolumeCounters = make(map[string]time.Duration, len(acc.VolumeCounters))
+		for cntID, usage := range acc.VolumeCounters {
+			newAcc.VolumeCounters[cntID] = usage
+		}
+	}
+	if acc.Reservations != nil {
+		newAcc.Reservations = make(map[string]*Reservation, len(acc.Reservations))
+		for rsvID, rsv := range acc.Reservations {
+			newAcc.Reservations[rsvID] = rsv.Clone()
+		}
+	}
 	return newAcc
 }
 
+// countVolumeUsage cumulates the usage rated on volume tiered rates into the account VolumeCounters
+func (acc *Account) countVolumeUsage(cc *CallCost) {
+	for _, ts := range cc.Timespans {
+		if ts.RateInterval == nil || ts.RateInterval.Rating == nil ||
+			ts.RateInterval.Rating.VolumeCounter == "" {
+			continue
+		}
+		cntID := ts.RateInterval.Rating.VolumeCounter
+		if acc.VolumeCounters == nil {
+			acc.VolumeCounters = make(map[string]time.Duration)
+		}
+		acc.VolumeCounters[cntID] += ts.GetDuration()
+		for _, inc := range ts.Increments { // mark the increments so we can refund the usage
+			if inc.BalanceInfo == nil {
+				inc.BalanceInfo = &DebitInfo{}
+			}
+			inc.BalanceInfo.VolumeCounter = cntID
+		}
+	}
+}
+
+// refundVolumeUsage removes the usage of a refunded increment out of the account VolumeCounters
+func (acc *Account) refundVolumeUsage(inc *Increment) {
+	if inc.BalanceInfo == nil || inc.BalanceInfo.VolumeCounter == "" {
+		return
+	}
+	if usage, has := acc.VolumeCounters[inc.BalanceInfo.VolumeCounter]; has {
+		if usage -= inc.Duration; usage < 0 {
+			usage = 0
+		}
+		acc.VolumeCounters[inc.BalanceInfo.VolumeCounter] = usage
+	}
+}
+
 func (acc *Account) DebitConnectionFee(cc *CallCost, usefulMoneyBalances Balances, count bool, block bool) (bool, Balance) {
 	var debitedBalance Balance
 
 	if cc.deductConnectFee {
 		connectFee := cc.GetConnectFee()
+		var feeRI *RateInterval
+		var feeTime time.Time
+		if len(cc.Timespans) != 0 {
+			feeRI, feeTime = cc.Timespans[0].RateInterval, cc.Timespans[0].TimeStart
+		}
 		//log.Print("CONNECT FEE: %f", connectFee)
 		connectFeePaid := false
 		for _, b := range usefulMoneyBalances {
-			if b.GetValue() >= connectFee {
-				b.SubstractValue(connectFee)
+			if fee, _, err := b.exchangeCost(connectFee, feeRI, feeTime); err == nil && b.availableValue() >= fee {
+				b.SubstractValue(fee)
 				// the conect fee is not refundable!
 				if count {
-					acc.countUnits(connectFee, utils.MONETARY, cc, b)
+					acc.countUnits(fee, utils.MONETARY, cc, b)
 				}
 				connectFeePaid = true
 				debitedBalance = *b
@@ -870,11 +958,12 @@ func (acc *Account) DebitConnectionFee(cc *CallCost, usefulMoneyBalances Balance
 			cc.negativeConnectFee = true
 			// there are no money for the connect fee; go negative
 			b := acc.GetDefaultMoneyBalance()
-			b.SubstractValue(connectFee)
+			fee, _ := b.forceExchangeCost(connectFee, feeRI, feeTime)
+			b.SubstractValue(fee)
 			debitedBalance = *b
 			// the conect fee is not refundable!
 			if count {
-				acc.countUnits(connectFee, utils.MONETARY, cc, b)
+				acc.countUnits(fee, utils.MONETARY, cc, b)
 			}
 		}
 	}
@@ -0,0 +1,233 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package engine
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/cgrates/cgrates/utils"
+)
+
+// maxHierarchyDepth limits the number of ancestors walked for one account
+const maxHierarchyDepth = 16
+
+// creditLeft returns the credit still available out of the parent balances,
+// limited is false for accounts without CreditLimit
+func (acc *Account) creditLeft() (left float64, limited bool) {
+	if acc.CreditLimit <= 0 {
+		return 0, false
+	}
+	return utils.Round(acc.CreditLimit-acc.CreditUsed, globalRoundingDecimals, utils.ROUNDING_MIDDLE), true
+}
+
+// getAncestors returns the accounts up in the hierarchy, parent first,
+// stopping at the disabled or missing ones
+func (acc *Account) getAncestors() (ancs []*Account) {
+	visited := utils.StringMap{acc.ID: true}
+	for parentID := acc.ParentID; parentID != "" && len(ancs) < maxHierarchyDepth; {
+		if visited[parentID] {
+			utils.Logger.Warning(fmt.Sprintf("<AccountS> loop in the hierarchy of account: %s", acc.ID))
+			break
+		}
+		visited[parentID] = true
+		parent, err := dm.DataDB().GetAccount(parentID)
+		if err != nil || parent.Disabled {
+			break
+		}
+		ancs = append(ancs, parent)
+		parentID = parent.ParentID
+	}
+	return
+}
+
+// getAncestorBalances returns the monetary balances of the ancestors, parent ones first,
+// each limited by the credit left of the accounts below its owner
+func (acc *Account) getAncestorBalances(destination, category, direction string) (bc Balances) {
+	chain := []*Account{acc}
+	for _, anc := range acc.getAncestors() {
+		for _, b := range anc.getBalancesForPrefix(destination, category, direction, utils.MONETARY, "") {
+			b.creditLimiters = chain
+			bc = append(bc, b)
+		}
+		chain = append(chain[:len(chain):len(chain)], anc)
+	}
+	return
+}
+
+// getAncestorCredit returns the credit the account can use out of the ancestors balances
+func (acc *Account) getAncestorCredit(destination, category, direction string) (credit float64) {
+	ancs := acc.getAncestors()
+	for i := len(ancs) - 1; i >= 0; i-- {
+		credit += ancs[i].getBalancesForPrefix(destination, category, direction, utils.MONETARY, "").availableValue()
+		child := acc
+		if i > 0 {
+			child = ancs[i-1]
+		}
+		if left, limited := child.creditLeft(); limited && left < credit {
+			credit = left
+		}
+	}
+	if credit < 0 {
+		credit = 0
+	}
+	return
+}
+
+// SetParent moves the account under the parent in the hierarchy, nil parent detaches the account
+func (acc *Account) SetParent(parent *Account) error {
+	if parent == nil {
+		acc.ParentID = ""
+		return nil
+	}
+	if utils.NewTenantID(parent.ID).Tenant != utils.NewTenantID(acc.ID).Tenant {
+		return fmt.Errorf("parent account %s out of another tenant", parent.ID)
+	}
+	anc := parent
+	for depth := 1; ; depth++ {
+		if anc.ID == acc.ID {
+			return fmt.Errorf("account %s is an ancestor of %s", acc.ID, parent.ID)
+		}
+		if depth > maxHierarchyDepth {
+			return fmt.Errorf("hierarchy deeper than %d accounts", maxHierarchyDepth)
+		}
+		if anc.ParentID == "" {
+			break
+		}
+		var err error
+		if anc, err = dm.DataDB().GetAccount(anc.ParentID); err != nil {
+			break // missing ancestor ends the hierarchy
+		}
+	}
+	acc.ParentID = parent.ID
+	return nil
+}
+
+// getAncestorIDs returns the IDs of the accounts up in the hierarchy
+func getAncestorIDs(acntID string) (ids []string) {
+	acc, err := dm.DataDB().GetAccount(acntID)
+	if err != nil {
+		return
+	}
+	for _, anc := range acc.getAncestors() {
+		ids = append(ids, anc.ID)
+	}
+	return
+}
+
+// refundCreditUsed gives back to the accounts between acntID and the ancestor the credit used out of the ancestor balances,
+// the modified accounts are left in accountsCache for saving
+func refundCreditUsed(acntID, ancestorID string, refund float64, accountsCache map[string]*Account) {
+	var chain []*Account
+	for acntID != ancestorID {
+		if acntID == "" || len(chain) > maxHierarchyDepth { // not an ancestor, ie: shared group member
+			return
+		}
+		acc, has := accountsCache[acntID]
+		if !has {
+			var err error
+			if acc, err = dm.DataDB().GetAccount(acntID); err != nil {
+				return
+			}
+			accountsCache[acntID] = acc
+		}
+		chain = append(chain, acc)
+		acntID = acc.ParentID
+	}
+	for _, acc := range chain {
+		acc.CreditUsed = utils.Round(acc.CreditUsed-refund, globalRoundingDecimals, utils.ROUNDING_MIDDLE)
+	}
+}
+
+// GetAccountChildren returns the direct children of the accounts in the tenant, indexed on parent ID
+func GetAccountChildren(tenant string) (children map[string][]string, err error) {
+	keys, err := dm.DataDB().GetKeysForPrefix(utils.ACCOUNT_PREFIX + tenant + utils.CONCATENATED_KEY_SEP)
+	if err != nil {
+		return
+	}
+	children = make(map[string][]string)
+	for _, key := range keys {
+		acc, err := dm.DataDB().GetAccount(strings.TrimPrefix(key, utils.ACCOUNT_PREFIX))
+		if err != nil {
+			return nil, err
+		}
+		if acc.ParentID != "" {
+			children[acc.ParentID] = append(children[acc.ParentID], acc.ID)
+		}
+	}
+	for _, ids := range children {
+		sort.Strings(ids)
+	}
+	return
+}
+
+// CostRollup sums up the costs of an account and of its descendants in the hierarchy
+type CostRollup struct {
+	Account   string
+	Cost      float64 // cost of the account own CDRs
+	TotalCost float64 // including the costs of the descendants
+	Children  []*CostRollup
+}
+
+// NewCostRollup builds the roll-up for the account out of the children map and the costs indexed on account ID
+func NewCostRollup(acntID string, children map[string][]string, costs map[string]float64) *CostRollup {
+	return newCostRollup(acntID, children, costs, utils.StringMap{})
+}
+
+func newCostRollup(acntID string, children map[string][]string, costs map[string]float64, visited utils.StringMap) *CostRollup {
+	visited[acntID] = true
+	cost := utils.Round(costs[acntID], globalRoundingDecimals, utils.ROUNDING_MIDDLE)
+	cr := &CostRollup{Account: acntID, Cost: cost, TotalCost: cost}
+	for _, childID := range children[acntID] {
+		if visited[childID] {
+			continue
+		}
+		childCR := newCostRollup(childID, children, costs, visited)
+		cr.TotalCost += childCR.TotalCost
+		cr.Children = append(cr.Children, childCR)
+	}
+	cr.TotalCost = utils.Round(cr.TotalCost, globalRoundingDecimals, utils.ROUNDING_MIDDLE)
+	return cr
+}
+
+// AccountIDs returns the IDs of the accounts in the roll-up
+func (cr *CostRollup) AccountIDs() (ids []string) {
+	ids = append(ids, cr.Account)
+	for _, child := range cr.Children {
+		ids = append(ids, child.AccountIDs()...)
+	}
+	return
+}
+
+// saveCreditLimiters saves the descendants which debited the ancestors balances, acc is saved by the caller
+func (bc Balances) saveCreditLimiters(acc *Account) {
+	saved := utils.StringMap{acc.ID: true}
+	for _, b := range bc {
+		if !b.dirty {
+			continue
+		}
+		for _, lmtr := range b.creditLimiters {
+			if !saved[lmtr.ID] {
+				dm.DataDB().SetAccount(lmtr)
+				saved[lmtr.ID] = true
+			}
+		}
+	}
+}
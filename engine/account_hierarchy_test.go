@@ -0,0 +1,153 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package engine
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/cgrates/cgrates/utils"
+)
+
+func TestAccountSetParent(t *testing.T) {
+	grandParent := &Account{ID: "cgrates.org:hier_grandparent"}
+	parent := &Account{ID: "cgrates.org:hier_parent", ParentID: grandParent.ID}
+	for _, acc := range []*Account{grandParent, parent} {
+		if err := dm.DataDB().SetAccount(acc); err != nil {
+			t.Fatal(err)
+		}
+	}
+	child := &Account{ID: "cgrates.org:hier_child"}
+	if err := child.SetParent(parent); err != nil {
+		t.Error(err)
+	} else if child.ParentID != parent.ID {
+		t.Errorf("Expecting: %s, received: %s", parent.ID, child.ParentID)
+	}
+	if err := grandParent.SetParent(child); err == nil {
+		t.Error("Expecting error on hierarchy loop")
+	}
+	if err := child.SetParent(&Account{ID: "itsyscom.com:hier_parent"}); err == nil {
+		t.Error("Expecting error on parent out of another tenant")
+	}
+	if err := child.SetParent(nil); err != nil {
+		t.Error(err)
+	} else if child.ParentID != "" {
+		t.Errorf("Unexpected parent: %s", child.ParentID)
+	}
+}
+
+func TestAccountGetAncestorCredit(t *testing.T) {
+	grandParent := &Account{ID: "cgrates.org:hier_credit_grandparent", BalanceMap: map[string]Balances{
+		utils.MONETARY: Balances{&Balance{Uuid: "hier_gp_money", Value: 50}}}}
+	parent := &Account{ID: "cgrates.org:hier_credit_parent", ParentID: grandParent.ID, CreditLimit: 40,
+		BalanceMap: map[string]Balances{
+			utils.MONETARY: Balances{&Balance{Uuid: "hier_p_money", Value: 20}}}}
+	for _, acc := range []*Account{grandParent, parent} {
+		if err := dm.DataDB().SetAccount(acc); err != nil {
+			t.Fatal(err)
+		}
+	}
+	child := &Account{ID: "cgrates.org:hier_credit_child", ParentID: parent.ID, CreditLimit: 100}
+	if credit := child.getAncestorCredit("0723", "call", utils.OUT); credit != 60 {
+		t.Errorf("Expecting 60, received: %v", credit)
+	}
+	child.CreditUsed = 90
+	if credit := child.getAncestorCredit("0723", "call", utils.OUT); credit != 10 {
+		t.Errorf("Expecting 10, received: %v", credit)
+	}
+}
+
+func TestAccountDebitParentBalance(t *testing.T) {
+	parent := &Account{ID: "cgrates.org:hier_debit_parent", BalanceMap: map[string]Balances{
+		utils.MONETARY: Balances{&Balance{Uuid: "hier_debit_money", Value: 100}}}}
+	if err := dm.DataDB().SetAccount(parent); err != nil {
+		t.Fatal(err)
+	}
+	cc := &CallCost{
+		Direction:   utils.OUT,
+		Destination: "0723045326",
+		Timespans: []*TimeSpan{
+			&TimeSpan{
+				TimeStart:    time.Date(2013, 9, 24, 10, 48, 0, 0, time.UTC),
+				TimeEnd:      time.Date(2013, 9, 24, 10, 49, 0, 0, time.UTC),
+				RateInterval: &RateInterval{Rating: &RIRate{Rates: RateGroups{&Rate{GroupIntervalStart: 0, Value: 1, RateIncrement: 10 * time.Second, RateUnit: time.Second}}}},
+			},
+		},
+		TOR: utils.VOICE,
+	}
+	cd := &CallDescriptor{
+		TimeStart:    cc.Timespans[0].TimeStart,
+		TimeEnd:      cc.Timespans[0].TimeEnd,
+		Direction:    cc.Direction,
+		Destination:  cc.Destination,
+		TOR:          cc.TOR,
+		testCallcost: cc,
+	}
+	child := &Account{ID: "cgrates.org:hier_debit_child", ParentID: parent.ID, CreditLimit: 30}
+	if _, err := child.debitCreditBalance(cd, false, false, false); err == nil {
+		t.Error("Expecting not enough credit error")
+	}
+	if child.CreditUsed != 30 {
+		t.Errorf("Expecting 30, received: %v", child.CreditUsed)
+	}
+	if rcv, err := dm.DataDB().GetAccount(parent.ID); err != nil {
+		t.Error(err)
+	} else if val := rcv.BalanceMap[utils.MONETARY][0].GetValue(); val != 70 {
+		t.Errorf("Expecting 70, received: %v", val)
+	}
+	// refunding gives back the credit
+	accountsCache := map[string]*Account{child.ID: child}
+	refundCreditUsed(child.ID, parent.ID, 10, accountsCache)
+	if child.CreditUsed != 20 {
+		t.Errorf("Expecting 20, received: %v", child.CreditUsed)
+	}
+	refundCreditUsed(child.ID, "cgrates.org:hier_not_ancestor", 10, accountsCache)
+	if child.CreditUsed != 20 {
+		t.Errorf("Expecting 20, received: %v", child.CreditUsed)
+	}
+}
+
+func TestNewCostRollup(t *testing.T) {
+	children := map[string][]string{
+		"cgrates.org:reseller": []string{"cgrates.org:dep1", "cgrates.org:dep2"},
+		"cgrates.org:dep1":     []string{"cgrates.org:user1"},
+	}
+	costs := map[string]float64{
+		"cgrates.org:reseller": 1,
+		"cgrates.org:dep1":     2,
+		"cgrates.org:dep2":     3.5,
+		"cgrates.org:user1":    4,
+		"cgrates.org:other":    10,
+	}
+	eCR := &CostRollup{Account: "cgrates.org:reseller", Cost: 1, TotalCost: 10.5,
+		Children: []*CostRollup{
+			&CostRollup{Account: "cgrates.org:dep1", Cost: 2, TotalCost: 6,
+				Children: []*CostRollup{
+					&CostRollup{Account: "cgrates.org:user1", Cost: 4, TotalCost: 4}}},
+			&CostRollup{Account: "cgrates.org:dep2", Cost: 3.5, TotalCost: 3.5},
+		}}
+	if cr := NewCostRollup("cgrates.org:reseller", children, costs); !reflect.DeepEqual(eCR, cr) {
+		t.Errorf("Expecting: %s, received: %s", utils.ToJSON(eCR), utils.ToJSON(cr))
+	}
+	eIDs := []string{"cgrates.org:reseller", "cgrates.org:dep1", "cgrates.org:user1", "cgrates.org:dep2"}
+	if ids := eCR.AccountIDs(); !reflect.DeepEqual(eIDs, ids) {
+		t.Errorf("Expecting: %v, received: %v", eIDs, ids)
+	}
+}
@@ -2089,6 +2089,35 @@ func TestAccountGetBalancesForPrefixMixedBad(t *testing.T) {
 	}
 }
 
+func TestAccountCountVolumeUsage(t *testing.T) {
+	acc := &Account{ID: "cgrates.org:volume"}
+	ri := &RateInterval{Rating: &RIRate{VolumeCounter: "VOL_MONTH",
+		Rates: RateGroups{&Rate{Value: 1, RateIncrement: time.Minute, RateUnit: time.Minute}}}}
+	t1 := time.Date(2017, time.March, 1, 10, 0, 0, 0, time.UTC)
+	cc := &CallCost{Timespans: TimeSpans{
+		&TimeSpan{TimeStart: t1, TimeEnd: t1.Add(2 * time.Minute), RateInterval: ri,
+			Increments: Increments{&Increment{Duration: time.Minute, BalanceInfo: &DebitInfo{}},
+				&Increment{Duration: time.Minute, BalanceInfo: &DebitInfo{}}}},
+		&TimeSpan{TimeStart: t1.Add(2 * time.Minute), TimeEnd: t1.Add(3 * time.Minute),
+			RateInterval: &RateInterval{Rating: &RIRate{}}},
+	}}
+	acc.countVolumeUsage(cc)
+	if acc.VolumeCounters["VOL_MONTH"] != 2*time.Minute {
+		t.Errorf("Wrong volume counters: %+v", acc.VolumeCounters)
+	}
+	inc := cc.Timespans[0].Increments[0]
+	if inc.BalanceInfo.VolumeCounter != "VOL_MONTH" {
+		t.Errorf("Increment not marked: %+v", inc.BalanceInfo)
+	}
+	acc.refundVolumeUsage(inc)
+	if acc.VolumeCounters["VOL_MONTH"] != time.Minute {
+		t.Errorf("Wrong volume counters after refund: %+v", acc.VolumeCounters)
+	}
+	if clnAcc := acc.Clone(); !reflect.DeepEqual(acc.VolumeCounters, clnAcc.VolumeCounters) {
+		t.Errorf("Expecting: %+v, received: %+v", acc.VolumeCounters, clnAcc.VolumeCounters)
+	}
+}
+
 func TestAccountNewAccountSummaryFromJSON(t *testing.T) {
 	if acnt, err := NewAccountSummaryFromJSON("null"); err != nil {
 		t.Error(err)
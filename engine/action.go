@@ -69,6 +69,8 @@ const (
 	DEBIT_RESET               = "*debit_reset"
 	DEBIT                     = "*debit"
 	RESET_COUNTERS            = "*reset_counters"
+	RESET_VOLUME_COUNTERS     = "*reset_volume_counters"
+	CHARGE_SUBSCRIPTIONS      = "*charge_subscriptions"
 	ENABLE_ACCOUNT            = "*enable_account"
 	DISABLE_ACCOUNT           = "*disable_account"
 	CALL_URL                  = "*call_url"
@@ -79,6 +81,14 @@ const (
 	SET_DDESTINATIONS         = "*set_ddestinations"
 	TRANSFER_MONETARY_DEFAULT = "*transfer_monetary_default"
 	CGR_RPC                   = "*cgr_rpc"
+	HTTP_POST_EVENT           = "*http_post_event"
+	AMQP_POST_EVENT           = "*amqp_post_event"
+	DISABLE_SUPPLIER          = "*disable_supplier"
+	ENABLE_SUPPLIER           = "*enable_supplier"
+	ALLOCATE_RESOURCE         = "*allocate_resource"
+	RELEASE_RESOURCE          = "*release_resource"
+	RESET_STAT_QUEUE          = "*reset_stat_queue"
+	EMIT_EVENT                = "*emit_event"
 )
 
 func (a *Action) Clone() *Action {
@@ -104,6 +114,8 @@ func getActionFunc(typ string) (actionTypeFunc, bool) {
 		DEBIT_RESET:               debitResetAction,
 		DEBIT:                     debitAction,
 		RESET_COUNTERS:            resetCountersAction,
+		RESET_VOLUME_COUNTERS:     resetVolumeCountersAction,
+		CHARGE_SUBSCRIPTIONS:      chargeSubscriptionsAction,
 		ENABLE_ACCOUNT:            enableAccountAction,
 		DISABLE_ACCOUNT:           disableAccountAction,
 		CALL_URL:                  callUrl,
@@ -354,6 +366,30 @@ func resetCountersAction(ub *Account, sq *CDRStatsQueueTriggered, a *Action, acs
 	return
 }
 
+// resetVolumeCountersAction starts a new volume period for the counters in ExtraParameters, all if empty
+func resetVolumeCountersAction(ub *Account, sq *CDRStatsQueueTriggered, a *Action, acs Actions) (err error) {
+	if ub == nil {
+		return errors.New("nil account")
+	}
+	if a.ExtraParameters == "" {
+		ub.VolumeCounters = nil
+		return
+	}
+	for _, cntID := range strings.Split(a.ExtraParameters, utils.INFIELD_SEP) {
+		delete(ub.VolumeCounters, cntID)
+	}
+	return
+}
+
+// chargeSubscriptionsAction charges the subscription fees due, each period is charged only once
+func chargeSubscriptionsAction(ub *Account, sq *CDRStatsQueueTriggered, a *Action, acs Actions) (err error) {
+	if ub == nil {
+		return errors.New("nil account")
+	}
+	_, err = ub.ChargeSubscriptions(time.Now())
+	return
+}
+
 func genericMakeNegative(a *Action) {
 	if a.Balance != nil && a.Balance.GetValue() > 0 { // only apply if not allready negative
 		a.Balance.SetValue(-a.Balance.GetValue())
@@ -0,0 +1,263 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package engine
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/cgrates/cgrates/cache"
+	"github.com/cgrates/cgrates/config"
+	"github.com/cgrates/cgrates/utils"
+	"github.com/streadway/amqp"
+)
+
+// thresholdActionFunc is an action executed by ThresholdS with the event which triggered the threshold
+type thresholdActionFunc func(tS *ThresholdService, t *Threshold, ev *utils.CGREvent, a *Action) error
+
+func getThresholdActionFunc(typ string) (thresholdActionFunc, bool) {
+	thdActionFuncMap := map[string]thresholdActionFunc{
+		HTTP_POST_EVENT:   httpPostEventAction,
+		AMQP_POST_EVENT:   amqpPostEventAction,
+		DISABLE_SUPPLIER:  disableSupplierAction,
+		ENABLE_SUPPLIER:   enableSupplierAction,
+		ALLOCATE_RESOURCE: allocateResourceAction,
+		RELEASE_RESOURCE:  releaseResourceAction,
+		RESET_STAT_QUEUE:  resetStatQueueAction,
+		EMIT_EVENT:        emitEventAction,
+	}
+	f, exists := thdActionFuncMap[typ]
+	return f, exists
+}
+
+// ThresholdEvent is the content posted out by *http_post_event and *amqp_post_event
+type ThresholdEvent struct {
+	Threshold *Threshold
+	Event     *utils.CGREvent
+}
+
+func thresholdEventJSON(t *Threshold, ev *utils.CGREvent) ([]byte, error) {
+	return json.Marshal(&ThresholdEvent{Threshold: t, Event: ev})
+}
+
+// httpPostEventAction posts the threshold together with its event as JSON to the URL in ExtraParameters
+func httpPostEventAction(tS *ThresholdService, t *Threshold, ev *utils.CGREvent, a *Action) error {
+	jsn, err := thresholdEventJSON(t, ev)
+	if err != nil {
+		return err
+	}
+	cfg := config.CgrConfig()
+	ffn := &utils.FallbackFileName{Module: fmt.Sprintf("%s>%s", utils.ActionsPoster, a.ActionType),
+		Transport: utils.MetaHTTPjson, Address: a.ExtraParameters,
+		RequestID: utils.GenUUID(), FileSuffix: utils.JSNSuffix}
+	_, err = utils.NewHTTPPoster(cfg.HttpSkipTlsVerify, cfg.ReplyTimeout).Post(a.ExtraParameters,
+		utils.CONTENT_JSON, jsn, cfg.PosterAttempts, path.Join(cfg.FailedPostsDir, ffn.AsString()))
+	return err
+}
+
+// amqpPostEventAction posts the threshold together with its event as JSON to the AMQP URL in ExtraParameters
+func amqpPostEventAction(tS *ThresholdService, t *Threshold, ev *utils.CGREvent, a *Action) error {
+	jsn, err := thresholdEventJSON(t, ev)
+	if err != nil {
+		return err
+	}
+	cfg := config.CgrConfig()
+	ffn := &utils.FallbackFileName{Module: fmt.Sprintf("%s>%s", utils.ActionsPoster, a.ActionType),
+		Transport: utils.MetaAMQPjsonMap, Address: a.ExtraParameters,
+		RequestID: utils.GenUUID(), FileSuffix: utils.JSNSuffix}
+	amqpPoster, err := utils.AMQPPostersCache.GetAMQPPoster(a.ExtraParameters,
+		cfg.PosterAttempts, cfg.FailedPostsDir)
+	if err != nil {
+		return err
+	}
+	var chn *amqp.Channel
+	chn, err = amqpPoster.Post(nil, utils.CONTENT_JSON, jsn, ffn.AsString())
+	if chn != nil {
+		chn.Close()
+	}
+	return err
+}
+
+// setSupplierDisabled changes the Disabled flag of one supplier, ExtraParameters: SupplierProfileID:SupplierID
+// the cached profile is not modified, a copy of it is stored instead
+func setSupplierDisabled(tS *ThresholdService, t *Threshold, a *Action, disabled bool) (err error) {
+	params := strings.Split(a.ExtraParameters, utils.InInFieldSep)
+	if len(params) != 2 || params[0] == "" || params[1] == "" {
+		return fmt.Errorf("invalid parameters for action %s: <%s>", a.ActionType, a.ExtraParameters)
+	}
+	splPrfl, err := tS.dm.GetSupplierProfile(t.Tenant, params[0], true, utils.NonTransactional)
+	if err != nil {
+		return
+	}
+	splIdx := -1
+	for i, spl := range splPrfl.Suppliers {
+		if spl.ID == params[1] {
+			splIdx = i
+			break
+		}
+	}
+	if splIdx == -1 {
+		return utils.ErrNotFound
+	}
+	if splPrfl.Suppliers[splIdx].Disabled == disabled { // nothing to change
+		return
+	}
+	newPrfl := *splPrfl
+	newPrfl.Suppliers = make([]*Supplier, len(splPrfl.Suppliers))
+	copy(newPrfl.Suppliers, splPrfl.Suppliers)
+	newSpl := *splPrfl.Suppliers[splIdx]
+	newSpl.Disabled = disabled
+	newPrfl.Suppliers[splIdx] = &newSpl
+	if err = tS.dm.SetSupplierProfile(&newPrfl); err != nil {
+		return
+	}
+	cache.RemKey(utils.SupplierProfilePrefix+newPrfl.TenantID(), true, "")
+	return
+}
+
+// disableSupplierAction excludes the supplier from sorting until *enable_supplier or next TariffPlan load
+func disableSupplierAction(tS *ThresholdService, t *Threshold, ev *utils.CGREvent, a *Action) (err error) {
+	return setSupplierDisabled(tS, t, a, true)
+}
+
+// enableSupplierAction puts back a supplier disabled with *disable_supplier
+func enableSupplierAction(tS *ThresholdService, t *Threshold, ev *utils.CGREvent, a *Action) (err error) {
+	return setSupplierDisabled(tS, t, a, false)
+}
+
+// resourceUsageArgs builds the ResourceS arguments out of ExtraParameters: [UsageID][:Units]
+// UsageID defaults to the threshold ID and Units to 1
+func resourceUsageArgs(t *Threshold, ev *utils.CGREvent, a *Action) (args utils.ArgRSv1ResourceUsage, err error) {
+	args = utils.ArgRSv1ResourceUsage{CGREvent: *ev, UsageID: t.ID, Units: 1}
+	params := strings.Split(a.ExtraParameters, utils.InInFieldSep)
+	if params[0] != "" {
+		args.UsageID = params[0]
+	}
+	if len(params) > 1 {
+		if args.Units, err = strconv.ParseFloat(params[1], 64); err != nil {
+			return
+		}
+	}
+	return
+}
+
+// allocateResourceAction allocates resources matching the event
+func allocateResourceAction(tS *ThresholdService, t *Threshold, ev *utils.CGREvent, a *Action) (err error) {
+	resourceS := tS.resourceSConn()
+	if resourceS == nil {
+		return fmt.Errorf("no connection to %s", utils.ResourceS)
+	}
+	args, err := resourceUsageArgs(t, ev, a)
+	if err != nil {
+		return
+	}
+	var reply string
+	return resourceS.Call(utils.ResourceSv1AllocateResource, args, &reply)
+}
+
+// releaseResourceAction releases the resources allocated with the UsageID in ExtraParameters
+func releaseResourceAction(tS *ThresholdService, t *Threshold, ev *utils.CGREvent, a *Action) (err error) {
+	resourceS := tS.resourceSConn()
+	if resourceS == nil {
+		return fmt.Errorf("no connection to %s", utils.ResourceS)
+	}
+	args, err := resourceUsageArgs(t, ev, a)
+	if err != nil {
+		return
+	}
+	var reply string
+	return resourceS.Call(utils.ResourceSv1ReleaseResource, args, &reply)
+}
+
+// resetStatQueueAction resets the StatQueue in ExtraParameters
+// or the one which generated the event if ExtraParameters are empty
+func resetStatQueueAction(tS *ThresholdService, t *Threshold, ev *utils.CGREvent, a *Action) (err error) {
+	statS := tS.statSConn()
+	if statS == nil {
+		return fmt.Errorf("no connection to %s", utils.StatService)
+	}
+	sqID := a.ExtraParameters
+	if sqID == "" {
+		if sqID, err = ev.FieldAsString(utils.StatID); err != nil {
+			return
+		}
+	}
+	var reply string
+	return statS.Call(utils.StatSv1ResetStatQueue,
+		&utils.TenantID{Tenant: t.Tenant, ID: sqID}, &reply)
+}
+
+// emitEventMaxHops limits the chain of events emitted by thresholds triggered out of emitted events
+const emitEventMaxHops = 5
+
+// emitEventAction builds a ThresholdHit event out of the original one and sends it
+// to the subsystems in ExtraParameters (*thresholds;*stats), defaulting to *thresholds
+func emitEventAction(tS *ThresholdService, t *Threshold, ev *utils.CGREvent, a *Action) (err error) {
+	var hops int
+	if hopsIface, has := ev.Event[utils.EmitHops]; has {
+		var hopsFlt float64
+		if hopsFlt, err = utils.IfaceAsFloat64(hopsIface); err != nil {
+			return
+		}
+		hops = int(hopsFlt)
+	}
+	if hops >= emitEventMaxHops {
+		return fmt.Errorf("maximum hops reached for action %s, event: %s", a.ActionType, ev.TenantID())
+	}
+	emitEv := &utils.CGREvent{
+		Tenant: ev.Tenant,
+		ID:     utils.GenUUID(),
+		Event:  make(map[string]interface{}, len(ev.Event)+4),
+	}
+	for k, v := range ev.Event {
+		emitEv.Event[k] = v
+	}
+	emitEv.Event[utils.EventType] = utils.ThresholdHit
+	emitEv.Event[utils.ThresholdID] = t.ID
+	emitEv.Event[utils.Hits] = t.Hits
+	emitEv.Event[utils.EmitHops] = hops + 1
+	subsystems := []string{utils.MetaThresholds}
+	if a.ExtraParameters != "" {
+		subsystems = strings.Split(a.ExtraParameters, utils.INFIELD_SEP)
+	}
+	for _, subsys := range subsystems {
+		switch subsys {
+		case utils.MetaThresholds:
+			if _, err = tS.processEvent(emitEv); err != nil && err != utils.ErrNotFound {
+				return
+			}
+		case utils.MetaStats:
+			statS := tS.statSConn()
+			if statS == nil {
+				return fmt.Errorf("no connection to %s", utils.StatService)
+			}
+			var reply string
+			if err = statS.Call(utils.StatSv1ProcessEvent, emitEv, &reply); err != nil &&
+				err.Error() != utils.ErrNotFound.Error() {
+				return
+			}
+		default:
+			return fmt.Errorf("unsupported subsystem for action %s: <%s>", a.ActionType, subsys)
+		}
+	}
+	return nil
+}
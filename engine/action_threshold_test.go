@@ -0,0 +1,195 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package engine
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/cgrates/cgrates/utils"
+)
+
+type mockThdActConn struct {
+	calls map[string]interface{} // map[serviceMethod]args
+}
+
+func (mc *mockThdActConn) Call(serviceMethod string, args interface{}, reply interface{}) error {
+	mc.calls[serviceMethod] = args
+	if rpl, canCast := reply.(*string); canCast {
+		*rpl = utils.OK
+	}
+	return nil
+}
+
+func TestThresholdActionsHTTPPostEvent(t *testing.T) {
+	var rcv ThresholdEvent
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		json.Unmarshal(body, &rcv)
+	}))
+	defer srv.Close()
+	thd := &Threshold{Tenant: "cgrates.org", ID: "THD_ASR", Hits: 3}
+	ev := &utils.CGREvent{Tenant: "cgrates.org", ID: "EV1",
+		Event: map[string]interface{}{
+			utils.EventType: utils.StatUpdate,
+			utils.StatID:    "STATS_1",
+			utils.MetaASR:   35.0}}
+	a := &Action{ActionType: HTTP_POST_EVENT, ExtraParameters: srv.URL}
+	actFunc, has := getThresholdActionFunc(a.ActionType)
+	if !has {
+		t.Fatalf("no action function for %s", a.ActionType)
+	}
+	if err := actFunc(nil, thd, ev, a); err != nil {
+		t.Fatal(err)
+	}
+	if rcv.Threshold == nil || rcv.Threshold.ID != thd.ID || rcv.Threshold.Hits != thd.Hits {
+		t.Errorf("received threshold: %+v", rcv.Threshold)
+	}
+	if rcv.Event == nil || !reflect.DeepEqual(ev.Event, rcv.Event.Event) {
+		t.Errorf("expecting: %+v, received: %+v", ev, rcv.Event)
+	}
+}
+
+func TestThresholdActionsResetStatQueue(t *testing.T) {
+	conn := &mockThdActConn{calls: make(map[string]interface{})}
+	tS, _ := NewThresholdService(dm, nil, 0, nil, conn, nil)
+	thd := &Threshold{Tenant: "cgrates.org", ID: "THD_ASR"}
+	ev := &utils.CGREvent{Tenant: "cgrates.org", ID: "EV1",
+		Event: map[string]interface{}{utils.StatID: "STATS_1"}}
+	if err := resetStatQueueAction(tS, thd, ev,
+		&Action{ActionType: RESET_STAT_QUEUE}); err != nil {
+		t.Fatal(err)
+	}
+	eArgs := &utils.TenantID{Tenant: "cgrates.org", ID: "STATS_1"}
+	if args := conn.calls[utils.StatSv1ResetStatQueue]; !reflect.DeepEqual(eArgs, args) {
+		t.Errorf("expecting: %+v, received: %+v", eArgs, args)
+	}
+	if err := resetStatQueueAction(tS, thd, ev,
+		&Action{ActionType: RESET_STAT_QUEUE, ExtraParameters: "STATS_2"}); err != nil {
+		t.Fatal(err)
+	}
+	eArgs = &utils.TenantID{Tenant: "cgrates.org", ID: "STATS_2"}
+	if args := conn.calls[utils.StatSv1ResetStatQueue]; !reflect.DeepEqual(eArgs, args) {
+		t.Errorf("expecting: %+v, received: %+v", eArgs, args)
+	}
+	tS.SetStatSConnection(nil)
+	if err := resetStatQueueAction(tS, thd, ev,
+		&Action{ActionType: RESET_STAT_QUEUE}); err == nil {
+		t.Error("expecting error without StatS connection")
+	}
+}
+
+func TestThresholdActionsResourceUsage(t *testing.T) {
+	conn := &mockThdActConn{calls: make(map[string]interface{})}
+	tS, _ := NewThresholdService(dm, nil, 0, nil, nil, conn)
+	thd := &Threshold{Tenant: "cgrates.org", ID: "THD_ACNT_1001"}
+	ev := &utils.CGREvent{Tenant: "cgrates.org", ID: "EV1",
+		Event: map[string]interface{}{utils.Account: "1001"}}
+	if err := allocateResourceAction(tS, thd, ev,
+		&Action{ActionType: ALLOCATE_RESOURCE}); err != nil {
+		t.Fatal(err)
+	}
+	eArgs := utils.ArgRSv1ResourceUsage{CGREvent: *ev, UsageID: "THD_ACNT_1001", Units: 1}
+	if args := conn.calls[utils.ResourceSv1AllocateResource]; !reflect.DeepEqual(eArgs, args) {
+		t.Errorf("expecting: %+v, received: %+v", eArgs, args)
+	}
+	if err := releaseResourceAction(tS, thd, ev,
+		&Action{ActionType: RELEASE_RESOURCE, ExtraParameters: "BLOCK_1001:2"}); err != nil {
+		t.Fatal(err)
+	}
+	eArgs = utils.ArgRSv1ResourceUsage{CGREvent: *ev, UsageID: "BLOCK_1001", Units: 2}
+	if args := conn.calls[utils.ResourceSv1ReleaseResource]; !reflect.DeepEqual(eArgs, args) {
+		t.Errorf("expecting: %+v, received: %+v", eArgs, args)
+	}
+	if err := allocateResourceAction(tS, thd, ev,
+		&Action{ActionType: ALLOCATE_RESOURCE, ExtraParameters: "BLOCK_1001:two"}); err == nil {
+		t.Error("expecting error on invalid units")
+	}
+}
+
+func TestThresholdActionsDisableSupplier(t *testing.T) {
+	splPrfl := &SupplierProfile{
+		Tenant: "cgrates.org",
+		ID:     "SPP_THD_1",
+		Suppliers: []*Supplier{
+			&Supplier{ID: "supplier1", Weight: 10},
+			&Supplier{ID: "supplier2", Weight: 20},
+		},
+	}
+	if err := dm.SetSupplierProfile(splPrfl); err != nil {
+		t.Fatal(err)
+	}
+	tS, _ := NewThresholdService(dm, nil, 0, nil, nil, nil)
+	thd := &Threshold{Tenant: "cgrates.org", ID: "THD_ASR"}
+	ev := &utils.CGREvent{Tenant: "cgrates.org", ID: "EV1"}
+	if err := disableSupplierAction(tS, thd, ev,
+		&Action{ActionType: DISABLE_SUPPLIER, ExtraParameters: "SPP_THD_1"}); err == nil {
+		t.Error("expecting error on invalid parameters")
+	}
+	if err := disableSupplierAction(tS, thd, ev,
+		&Action{ActionType: DISABLE_SUPPLIER, ExtraParameters: "SPP_THD_1:supplier1"}); err != nil {
+		t.Fatal(err)
+	}
+	if splPrfl.Suppliers[0].Disabled {
+		t.Error("original profile should not be modified")
+	}
+	if rcv, err := dm.GetSupplierProfile("cgrates.org", "SPP_THD_1", false, utils.NonTransactional); err != nil {
+		t.Error(err)
+	} else if len(rcv.Suppliers) != 2 || !rcv.Suppliers[0].Disabled || rcv.Suppliers[1].Disabled {
+		t.Errorf("unexpected suppliers: %s", utils.ToJSON(rcv.Suppliers))
+	}
+	if err := enableSupplierAction(tS, thd, ev,
+		&Action{ActionType: ENABLE_SUPPLIER, ExtraParameters: "SPP_THD_1:supplier1"}); err != nil {
+		t.Fatal(err)
+	}
+	if rcv, err := dm.GetSupplierProfile("cgrates.org", "SPP_THD_1", false, utils.NonTransactional); err != nil {
+		t.Error(err)
+	} else if rcv.Suppliers[0].Disabled {
+		t.Errorf("unexpected suppliers: %s", utils.ToJSON(rcv.Suppliers))
+	}
+	if err := enableSupplierAction(tS, thd, ev,
+		&Action{ActionType: ENABLE_SUPPLIER, ExtraParameters: "SPP_THD_1:supplier3"}); err != utils.ErrNotFound {
+		t.Errorf("expecting: %v, received: %v", utils.ErrNotFound, err)
+	}
+}
+
+func TestThresholdActionsEmitEventHops(t *testing.T) {
+	conn := &mockThdActConn{calls: make(map[string]interface{})}
+	tS, _ := NewThresholdService(dm, nil, 0, nil, conn, nil)
+	thd := &Threshold{Tenant: "cgrates.org", ID: "THD_EMIT", Hits: 1}
+	ev := &utils.CGREvent{Tenant: "cgrates.org", ID: "EV1",
+		Event: map[string]interface{}{utils.Account: "1001"}}
+	if err := emitEventAction(tS, thd, ev,
+		&Action{ActionType: EMIT_EVENT, ExtraParameters: utils.MetaStats}); err != nil {
+		t.Fatal(err)
+	}
+	emitEv := conn.calls[utils.StatSv1ProcessEvent].(*utils.CGREvent)
+	if emitEv.Event[utils.EmitHops] != 1 || emitEv.Event[utils.ThresholdID] != "THD_EMIT" {
+		t.Errorf("unexpected event: %s", utils.ToJSON(emitEv))
+	}
+	ev.Event[utils.EmitHops] = float64(emitEventMaxHops) // as received over JSON
+	if err := emitEventAction(tS, thd, ev,
+		&Action{ActionType: EMIT_EVENT, ExtraParameters: utils.MetaStats}); err == nil {
+		t.Error("expecting error on maximum hops")
+	}
+}
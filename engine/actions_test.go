@@ -1305,6 +1305,35 @@ func TestActionResetCounterCredit(t *testing.T) {
 	}
 }
 
+func TestActionResetVolumeCounters(t *testing.T) {
+	ub := &Account{
+		ID: "TEST_UB",
+		VolumeCounters: map[string]time.Duration{
+			"VOL_MONTH": 90 * time.Minute,
+			"VOL_WEEK":  30 * time.Minute,
+			"VOL_DAY":   10 * time.Minute,
+		},
+	}
+	a := &Action{ActionType: RESET_VOLUME_COUNTERS, ExtraParameters: "VOL_WEEK;VOL_DAY"}
+	if err := resetVolumeCountersAction(ub, nil, a, nil); err != nil {
+		t.Error(err)
+	}
+	eCounters := map[string]time.Duration{"VOL_MONTH": 90 * time.Minute}
+	if !reflect.DeepEqual(eCounters, ub.VolumeCounters) {
+		t.Errorf("Expecting: %+v, received: %+v", eCounters, ub.VolumeCounters)
+	}
+	a.ExtraParameters = ""
+	if err := resetVolumeCountersAction(ub, nil, a, nil); err != nil {
+		t.Error(err)
+	}
+	if len(ub.VolumeCounters) != 0 {
+		t.Errorf("Volume counters not reset: %+v", ub.VolumeCounters)
+	}
+	if err := resetVolumeCountersAction(nil, nil, a, nil); err == nil {
+		t.Error("Expecting error on nil account")
+	}
+}
+
 func TestActionTriggerLogging(t *testing.T) {
 	at := &ActionTrigger{
 		ID: "some_uuid",
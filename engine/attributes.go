@@ -20,6 +20,7 @@ package engine
 
 import (
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/cgrates/cgrates/config"
@@ -35,6 +36,7 @@ type AttributeService struct {
 	dm            *DataManager
 	filterS       *FilterS
 	indexedFields []string
+	ifMux         sync.RWMutex // protects indexedFields
 }
 
 // ListenAndServe will initialize the service
@@ -52,6 +54,14 @@ func (alS *AttributeService) Shutdown() (err error) {
 	return
 }
 
+// ReloadConfig applies the indexed_fields out of the running configuration
+func (alS *AttributeService) ReloadConfig(cfg *config.CGRConfig) error {
+	alS.ifMux.Lock()
+	alS.indexedFields = cfg.AttributeSCfg().IndexedFields
+	alS.ifMux.Unlock()
+	return nil
+}
+
 // matchingAttributeProfilesForEvent returns ordered list of matching resources which are active by the time of the call
 func (alS *AttributeService) matchingAttributeProfilesForEvent(ev *utils.CGREvent) (aPrfls AttributeProfiles, err error) {
 	var attrIdxKey string
@@ -61,7 +71,10 @@ func (alS *AttributeService) matchingAttributeProfilesForEvent(ev *utils.CGREven
 	}
 	attrIdxKey = utils.ConcatenatedKey(ev.Tenant, contextVal)
 	matchingAPs := make(map[string]*AttributeProfile)
-	aPrflIDs, err := matchingItemIDsForEvent(ev.Event, alS.indexedFields,
+	alS.ifMux.RLock()
+	indexedFields := alS.indexedFields
+	alS.ifMux.RUnlock()
+	aPrflIDs, err := matchingItemIDsForEvent(ev.Event, indexedFields,
 		alS.dm, utils.AttributeProfilesStringIndex+attrIdxKey)
 	if err != nil {
 		return nil, err
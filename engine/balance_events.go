@@ -0,0 +1,208 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package engine
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/cgrates/cgrates/config"
+	"github.com/cgrates/cgrates/guardian"
+	"github.com/cgrates/cgrates/utils"
+	"github.com/streadway/amqp"
+)
+
+// getBalanceType returns the type of the balance within the account
+func (acc *Account) getBalanceType(b *Balance) string {
+	for balanceType, bc := range acc.BalanceMap {
+		for _, blnc := range bc {
+			if blnc == b {
+				return balanceType
+			}
+		}
+	}
+	return ""
+}
+
+// publishBalanceEvent sends the balance lifecycle event to ThresholdS and posts it to the configured addresses
+func (acc *Account) publishBalanceEvent(evType string, b *Balance, extra map[string]interface{}) {
+	acntTnt := utils.NewTenantID(acc.ID)
+	ev := &utils.CGREvent{
+		Tenant: acntTnt.Tenant,
+		ID:     utils.GenUUID(),
+		Event: map[string]interface{}{
+			utils.EventType:   evType,
+			utils.EventSource: utils.AccountService,
+			utils.Account:     acntTnt.ID,
+			utils.BalanceID:   b.ID,
+			utils.BalanceUUID: b.Uuid,
+			utils.BalanceType: acc.getBalanceType(b),
+			utils.Units:       b.Value}}
+	if !b.ExpirationDate.IsZero() {
+		ev.Event[utils.ExpiryTime] = b.ExpirationDate.Format(time.RFC3339)
+	}
+	for k, v := range extra {
+		ev.Event[k] = v
+	}
+	if thresholdS != nil {
+		var hits int
+		if err := thresholdS.Call(utils.ThresholdSv1ProcessEvent, ev, &hits); err != nil {
+			utils.Logger.Warning(
+				fmt.Sprintf("<AccountS> error: %s processing balance event %+v with ThresholdS.", err.Error(), ev))
+		}
+	}
+	postURLs := config.CgrConfig().RALsBalanceEvPostURLs
+	if len(postURLs) == 0 {
+		return
+	}
+	jsn, err := json.Marshal(ev)
+	if err != nil {
+		utils.Logger.Warning(
+			fmt.Sprintf("<AccountS> error: %s marshaling balance event %+v.", err.Error(), ev))
+		return
+	}
+	for _, addr := range postURLs {
+		go postBalanceEvent(addr, jsn)
+	}
+}
+
+// postBalanceEvent posts the JSON event to the HTTP or AMQP address
+func postBalanceEvent(addr string, jsn []byte) {
+	cfg := config.CgrConfig()
+	ffn := &utils.FallbackFileName{Module: utils.BalanceEventsPoster,
+		Transport: utils.MetaHTTPjson, Address: addr,
+		RequestID: utils.GenUUID(), FileSuffix: utils.JSNSuffix}
+	var err error
+	if strings.HasPrefix(addr, "amqp") {
+		ffn.Transport = utils.MetaAMQPjsonMap
+		var amqpPoster *utils.AMQPPoster
+		if amqpPoster, err = utils.AMQPPostersCache.GetAMQPPoster(addr,
+			cfg.PosterAttempts, cfg.FailedPostsDir); err == nil {
+			var chn *amqp.Channel
+			chn, err = amqpPoster.Post(nil, utils.CONTENT_JSON, jsn, ffn.AsString())
+			if chn != nil {
+				chn.Close()
+			}
+		}
+	} else {
+		_, err = utils.NewHTTPPoster(cfg.HttpSkipTlsVerify, cfg.ReplyTimeout).Post(addr,
+			utils.CONTENT_JSON, jsn, cfg.PosterAttempts, path.Join(cfg.FailedPostsDir, ffn.AsString()))
+	}
+	if err != nil {
+		utils.Logger.Warning(
+			fmt.Sprintf("<AccountS> error: %s posting balance event to: %s", err.Error(), addr))
+	}
+}
+
+// publishExhaustedBalances sends the BalanceExhausted events for the balances consumed since last publish
+func (acc *Account) publishExhaustedBalances() {
+	for _, bc := range acc.BalanceMap {
+		for _, b := range bc {
+			if b.exhausted {
+				b.exhausted = false
+				acc.publishBalanceEvent(utils.BalanceExhausted, b, nil)
+			}
+		}
+	}
+}
+
+// BalanceExpiryWatcher checks periodically the accounts, sending the BalanceExpiring events
+// and removing the expired balances
+type BalanceExpiryWatcher struct {
+	dm        *DataManager
+	interval  time.Duration
+	leadTimes []time.Duration
+	lastCheck time.Time
+	stopChan  chan struct{}
+}
+
+func NewBalanceExpiryWatcher(dm *DataManager, interval time.Duration, leadTimes []time.Duration) *BalanceExpiryWatcher {
+	return &BalanceExpiryWatcher{dm: dm, interval: interval, leadTimes: leadTimes,
+		lastCheck: time.Now().Add(-interval), stopChan: make(chan struct{})}
+}
+
+// ListenAndServe runs the checks until Shutdown is called
+func (bew *BalanceExpiryWatcher) ListenAndServe() {
+	utils.Logger.Info(fmt.Sprintf("<AccountS> starting balance expiry watcher, interval: %s", bew.interval))
+	for {
+		bew.checkAccounts(time.Now())
+		select {
+		case <-bew.stopChan:
+			return
+		case <-time.After(bew.interval):
+		}
+	}
+}
+
+func (bew *BalanceExpiryWatcher) Shutdown() {
+	close(bew.stopChan)
+}
+
+// checkAccounts goes through all the accounts, each under its own lock
+func (bew *BalanceExpiryWatcher) checkAccounts(now time.Time) {
+	keys, err := bew.dm.DataDB().GetKeysForPrefix(utils.ACCOUNT_PREFIX)
+	if err != nil {
+		utils.Logger.Warning(fmt.Sprintf("<AccountS> error: %s querying accounts for balance expiry", err.Error()))
+		return
+	}
+	for _, key := range keys {
+		acntID := key[len(utils.ACCOUNT_PREFIX):]
+		if _, err := guardian.Guardian.Guard(func() (interface{}, error) {
+			return nil, bew.checkAccount(acntID, now)
+		}, 0, utils.ACCOUNT_PREFIX+acntID); err != nil {
+			utils.Logger.Warning(fmt.Sprintf("<AccountS> error: %s checking balance expiry for account: %s", err.Error(), acntID))
+		}
+	}
+	bew.lastCheck = now
+}
+
+// checkAccount sends the BalanceExpiring events for the lead times passed since last check,
+// removing the expired balances out of the account
+func (bew *BalanceExpiryWatcher) checkAccount(acntID string, now time.Time) (err error) {
+	acc, err := bew.dm.DataDB().GetAccount(acntID)
+	if err != nil {
+		return
+	}
+	var hasExpired bool
+	for _, bc := range acc.BalanceMap {
+		for _, b := range bc {
+			if b.ExpirationDate.IsZero() {
+				continue
+			}
+			if !b.ExpirationDate.After(now) {
+				hasExpired = true
+				continue
+			}
+			for _, leadTime := range bew.leadTimes {
+				if notifyTime := b.ExpirationDate.Add(-leadTime); notifyTime.After(bew.lastCheck) && !notifyTime.After(now) {
+					acc.publishBalanceEvent(utils.BalanceExpiring, b,
+						map[string]interface{}{utils.LeadTime: leadTime.String()})
+				}
+			}
+		}
+	}
+	if !hasExpired {
+		return
+	}
+	acc.CleanExpiredStuff()
+	return bew.dm.DataDB().SetAccount(acc)
+}
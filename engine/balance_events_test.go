@@ -0,0 +1,137 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package engine
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cgrates/cgrates/utils"
+)
+
+type mockBlcEvConn struct {
+	evs []*utils.CGREvent
+}
+
+func (mc *mockBlcEvConn) Call(serviceMethod string, args interface{}, reply interface{}) error {
+	if serviceMethod == utils.ThresholdSv1ProcessEvent {
+		mc.evs = append(mc.evs, args.(*utils.CGREvent))
+	}
+	return nil
+}
+
+// eventsOfType returns the events with the EventType and BalanceID received
+func (mc *mockBlcEvConn) eventsOfType(evType, balanceID string) (evs []*utils.CGREvent) {
+	for _, ev := range mc.evs {
+		if ev.Event[utils.EventType] == evType && ev.Event[utils.BalanceID] == balanceID {
+			evs = append(evs, ev)
+		}
+	}
+	return
+}
+
+func TestBalanceEventsCreatedExhausted(t *testing.T) {
+	thdS := new(mockBlcEvConn)
+	SetThresholdS(thdS)
+	defer SetThresholdS(nil)
+	acc := &Account{ID: "cgrates.org:blc_events"}
+	if err := acc.debitBalanceAction(&Action{Balance: &BalanceFilter{
+		ID:    utils.StringPointer("BUNDLE"),
+		Type:  utils.StringPointer(utils.MONETARY),
+		Value: &utils.ValueFormula{Static: -10}}}, false); err != nil {
+		t.Fatal(err)
+	}
+	if evs := thdS.eventsOfType(utils.BalanceCreated, "BUNDLE"); len(evs) != 1 {
+		t.Fatalf("Expecting one BalanceCreated event, received: %s", utils.ToJSON(thdS.evs))
+	} else if evs[0].Event[utils.Account] != "blc_events" ||
+		evs[0].Event[utils.BalanceType] != utils.MONETARY || evs[0].Event[utils.Units] != 10.0 {
+		t.Errorf("Unexpected event: %s", utils.ToJSON(evs[0]))
+	}
+	if err := acc.debitBalanceAction(&Action{Balance: &BalanceFilter{
+		ID:    utils.StringPointer("BUNDLE"),
+		Type:  utils.StringPointer(utils.MONETARY),
+		Value: &utils.ValueFormula{Static: 10}}}, false); err != nil {
+		t.Fatal(err)
+	}
+	if evs := thdS.eventsOfType(utils.BalanceExhausted, "BUNDLE"); len(evs) != 1 {
+		t.Errorf("Expecting one BalanceExhausted event, received: %s", utils.ToJSON(thdS.evs))
+	}
+	// already exhausted, no new event
+	if err := acc.debitBalanceAction(&Action{Balance: &BalanceFilter{
+		ID:    utils.StringPointer("BUNDLE"),
+		Type:  utils.StringPointer(utils.MONETARY),
+		Value: &utils.ValueFormula{Static: 1}}}, false); err != nil {
+		t.Fatal(err)
+	}
+	if evs := thdS.eventsOfType(utils.BalanceExhausted, "BUNDLE"); len(evs) != 1 {
+		t.Errorf("Expecting one BalanceExhausted event, received: %s", utils.ToJSON(thdS.evs))
+	}
+}
+
+func TestBalanceExpiryWatcherCheckAccount(t *testing.T) {
+	thdS := new(mockBlcEvConn)
+	SetThresholdS(thdS)
+	defer SetThresholdS(nil)
+	now := time.Now()
+	acc := &Account{ID: "cgrates.org:blc_expiry", BalanceMap: map[string]Balances{
+		utils.VOICE: Balances{
+			&Balance{Uuid: "uuid_expiring", ID: "EXPIRING", Value: 10,
+				ExpirationDate: now.Add(24*time.Hour - 30*time.Second)},
+			&Balance{Uuid: "uuid_later", ID: "LATER", Value: 10,
+				ExpirationDate: now.Add(72 * time.Hour)},
+		},
+		utils.MONETARY: Balances{
+			&Balance{Uuid: "uuid_expired", ID: "EXPIRED", Value: 10,
+				ExpirationDate: now.Add(-time.Hour)},
+			&Balance{Uuid: "uuid_default", ID: utils.META_DEFAULT, Value: 10},
+		}}}
+	if err := dm.DataDB().SetAccount(acc); err != nil {
+		t.Fatal(err)
+	}
+	bew := NewBalanceExpiryWatcher(dm, time.Minute, []time.Duration{24 * time.Hour})
+	bew.lastCheck = now.Add(-time.Minute)
+	if err := bew.checkAccount(acc.ID, now); err != nil {
+		t.Fatal(err)
+	}
+	if evs := thdS.eventsOfType(utils.BalanceExpiring, "EXPIRING"); len(evs) != 1 {
+		t.Errorf("Expecting one BalanceExpiring event, received: %s", utils.ToJSON(thdS.evs))
+	} else if evs[0].Event[utils.LeadTime] != "24h0m0s" {
+		t.Errorf("Unexpected event: %s", utils.ToJSON(evs[0]))
+	}
+	if evs := thdS.eventsOfType(utils.BalanceExpiring, "LATER"); len(evs) != 0 {
+		t.Errorf("Unexpected events: %s", utils.ToJSON(evs))
+	}
+	if evs := thdS.eventsOfType(utils.BalanceExpired, "EXPIRED"); len(evs) != 1 {
+		t.Errorf("Expecting one BalanceExpired event, received: %s", utils.ToJSON(thdS.evs))
+	}
+	if rcv, err := dm.DataDB().GetAccount(acc.ID); err != nil {
+		t.Error(err)
+	} else if len(rcv.BalanceMap[utils.MONETARY]) != 1 || len(rcv.BalanceMap[utils.VOICE]) != 2 {
+		t.Errorf("Unexpected balances: %s", utils.ToJSON(rcv.BalanceMap))
+	}
+	// next check within the same lead time does not send the event again
+	bew.lastCheck = now
+	thdS.evs = nil
+	if err := bew.checkAccount(acc.ID, now.Add(time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+	if len(thdS.evs) != 0 {
+		t.Errorf("Unexpected events: %s", utils.ToJSON(thdS.evs))
+	}
+}
@@ -35,6 +35,7 @@ type BalanceFilter struct {
 	Weight         *float64
 	DestinationIDs *utils.StringMap
 	RatingSubject  *string
+	Currency       *string
 	Categories     *utils.StringMap
 	SharedGroups   *utils.StringMap
 	TimingIDs      *utils.StringMap
@@ -54,6 +55,7 @@ func (bp *BalanceFilter) CreateBalance() *Balance {
 		Weight:         bp.GetWeight(),
 		DestinationIDs: bp.GetDestinationIDs(),
 		RatingSubject:  bp.GetRatingSubject(),
+		Currency:       bp.GetCurrency(),
 		Categories:     bp.GetCategories(),
 		SharedGroups:   bp.GetSharedGroups(),
 		Timings:        bp.Timings,
@@ -83,6 +85,10 @@ func (bf *BalanceFilter) Clone() *BalanceFilter {
 		result.RatingSubject = new(string)
 		*result.RatingSubject = *bf.RatingSubject
 	}
+	if bf.Currency != nil {
+		result.Currency = new(string)
+		*result.Currency = *bf.Currency
+	}
 	if bf.Type != nil {
 		result.Type = new(string)
 		*result.Type = *bf.Type
@@ -151,6 +157,9 @@ func (bf *BalanceFilter) LoadFromBalance(b *Balance) *BalanceFilter {
 	if b.RatingSubject != "" {
 		bf.RatingSubject = &b.RatingSubject
 	}
+	if b.Currency != "" {
+		bf.Currency = &b.Currency
+	}
 	if !b.Categories.IsEmpty() {
 		bf.Categories = &b.Categories
 	}
@@ -272,6 +281,13 @@ func (bp *BalanceFilter) GetRatingSubject() string {
 	return *bp.RatingSubject
 }
 
+func (bp *BalanceFilter) GetCurrency() string {
+	if bp == nil || bp.Currency == nil {
+		return ""
+	}
+	return *bp.Currency
+}
+
 func (bp *BalanceFilter) GetDisabled() bool {
 	if bp == nil || bp.Disabled == nil {
 		return false
@@ -326,6 +342,9 @@ func (bf *BalanceFilter) ModifyBalance(b *Balance) {
 	if bf.RatingSubject != nil {
 		b.RatingSubject = *bf.RatingSubject
 	}
+	if bf.Currency != nil {
+		b.Currency = *bf.Currency
+	}
 	if bf.Categories != nil {
 		b.Categories = *bf.Categories
 	}
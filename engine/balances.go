@@ -39,6 +39,7 @@ type Balance struct {
 	Weight         float64
 	DestinationIDs utils.StringMap
 	RatingSubject  string
+	Currency       string // monetary balances only, empty means the rating currency
 	Categories     utils.StringMap
 	SharedGroups   utils.StringMap
 	Timings        []*RITiming
@@ -48,7 +49,10 @@ type Balance struct {
 	Blocker        bool
 	precision      int
 	account        *Account // used to store ub reference for shared balances
+	reserved       float64  // value held by the account reservations, not available for debits
 	dirty          bool
+	exhausted      bool       // value reached zero, BalanceExhausted event not yet published
+	creditLimiters []*Account // descendants debiting this balance, limited by their CreditLimit
 }
 
 func (b *Balance) Equal(o *Balance) bool {
@@ -65,6 +69,7 @@ func (b *Balance) Equal(o *Balance) bool {
 		b.DestinationIDs.Equal(o.DestinationIDs) &&
 		b.Directions.Equal(o.Directions) &&
 		b.RatingSubject == o.RatingSubject &&
+		b.Currency == o.Currency &&
 		b.Categories.Equal(o.Categories) &&
 		b.SharedGroups.Equal(o.SharedGroups) &&
 		b.Disabled == o.Disabled &&
@@ -90,7 +95,8 @@ func (b *Balance) MatchFilter(o *BalanceFilter, skipIds bool) bool {
 		(o.Categories == nil || b.Categories.Includes(*o.Categories)) &&
 		(o.TimingIDs == nil || b.TimingIDs.Includes(*o.TimingIDs)) &&
 		(o.SharedGroups == nil || b.SharedGroups.Includes(*o.SharedGroups)) &&
-		(o.RatingSubject == nil || b.RatingSubject == *o.RatingSubject)
+		(o.RatingSubject == nil || b.RatingSubject == *o.RatingSubject) &&
+		(o.Currency == nil || b.Currency == *o.Currency)
 }
 
 func (b *Balance) HardMatchFilter(o *BalanceFilter, skipIds bool) bool {
@@ -112,7 +118,8 @@ func (b *Balance) HardMatchFilter(o *BalanceFilter, skipIds bool) bool {
 		(o.Categories == nil || b.Categories.Equal(*o.Categories)) &&
 		(o.TimingIDs == nil || b.TimingIDs.Equal(*o.TimingIDs)) &&
 		(o.SharedGroups == nil || b.SharedGroups.Equal(*o.SharedGroups)) &&
-		(o.RatingSubject == nil || b.RatingSubject == *o.RatingSubject)
+		(o.RatingSubject == nil || b.RatingSubject == *o.RatingSubject) &&
+		(o.Currency == nil || b.Currency == *o.Currency)
 }
 
 // the default balance has standard Id
@@ -175,6 +182,7 @@ func (b *Balance) Clone() *Balance {
 		ExpirationDate: b.ExpirationDate,
 		Weight:         b.Weight,
 		RatingSubject:  b.RatingSubject,
+		Currency:       b.Currency,
 		Categories:     b.Categories,
 		SharedGroups:   b.SharedGroups,
 		TimingIDs:      b.TimingIDs,
@@ -192,6 +200,29 @@ func (b *Balance) Clone() *Balance {
 	return n
 }
 
+// exchangeCost converts the cost rated on ri into the balance currency
+// xRate is 0 when no conversion was needed
+func (b *Balance) exchangeCost(cost float64, ri *RateInterval, t time.Time) (debit, xRate float64, err error) {
+	if xRate, err = exchangeRateAt(ratingCurrency(ri), b.Currency, t); err != nil {
+		return
+	}
+	if xRate == 1 {
+		return cost, 0, nil
+	}
+	return utils.Round(cost*xRate, globalRoundingDecimals, utils.ROUNDING_MIDDLE), xRate, nil
+}
+
+// forceExchangeCost is used when going negative, falling back on the unconverted cost
+// since the debit cannot be skipped
+func (b *Balance) forceExchangeCost(cost float64, ri *RateInterval, t time.Time) (debit, xRate float64) {
+	var err error
+	if debit, xRate, err = b.exchangeCost(cost, ri, t); err != nil {
+		utils.Logger.Warning(fmt.Sprintf("<RALs> cannot convert cost for balance: %s, error: %s, debiting unconverted", b.ID, err.Error()))
+		return cost, 0
+	}
+	return
+}
+
 func (b *Balance) getMatchingPrefixAndDestID(dest string) (prefix, destId string) {
 	if len(b.DestinationIDs) != 0 && b.DestinationIDs[utils.ANY] == false {
 		for _, p := range utils.SplitPrefix(dest, MIN_PREFIX_MATCH) {
@@ -210,7 +241,7 @@ func (b *Balance) getMatchingPrefixAndDestID(dest string) (prefix, destId string
 // Returns the available number of seconds for a specified credit
 func (b *Balance) GetMinutesForCredit(origCD *CallDescriptor, initialCredit float64) (duration time.Duration, credit float64) {
 	cd := origCD.Clone()
-	availableDuration := time.Duration(b.GetValue()) * time.Second
+	availableDuration := time.Duration(b.availableValue()) * time.Second
 	duration = availableDuration
 	credit = initialCredit
 	cc, err := b.GetCost(cd, false)
@@ -285,6 +316,18 @@ func (b *Balance) GetValue() float64 {
 	return b.Value
 }
 
+// availableValue returns the value which can be debited, excluding the reserved one
+// and limited by the credit left to the descendants debiting it
+func (b *Balance) availableValue() (val float64) {
+	val = b.GetValue() - b.reserved
+	for _, acc := range b.creditLimiters {
+		if left, limited := acc.creditLeft(); limited && left < val {
+			val = left
+		}
+	}
+	return
+}
+
 func (b *Balance) AddValue(amount float64) {
 	b.SetValue(b.GetValue() + amount)
 }
@@ -294,8 +337,16 @@ func (b *Balance) SubstractValue(amount float64) {
 }
 
 func (b *Balance) SetValue(amount float64) {
+	prevValue := b.Value
 	b.Value = amount
 	b.Value = utils.Round(b.GetValue(), globalRoundingDecimals, utils.ROUNDING_MIDDLE)
+	if prevValue > 0 && b.Value <= 0 {
+		b.exhausted = true
+	}
+	for _, acc := range b.creditLimiters {
+		acc.CreditUsed = utils.Round(acc.CreditUsed+prevValue-b.Value,
+			globalRoundingDecimals, utils.ROUNDING_MIDDLE)
+	}
 	b.dirty = true
 }
 
@@ -306,7 +357,7 @@ func (b *Balance) SetDirty() {
 // debitUnits will debit units for call descriptor.
 // returns the amount debited within cc
 func (b *Balance) debitUnits(cd *CallDescriptor, ub *Account, moneyBalances Balances, count bool, dryRun, debitConnectFee bool) (cc *CallCost, err error) {
-	if !b.IsActiveAt(cd.TimeStart) || b.GetValue() <= 0 {
+	if !b.IsActiveAt(cd.TimeStart) || b.availableValue() <= 0 {
 		return
 	}
 	if duration, err := utils.ParseZeroRatingSubject(cd.TOR, b.RatingSubject); err == nil {
@@ -353,7 +404,7 @@ func (b *Balance) debitUnits(cd *CallDescriptor, ub *Account, moneyBalances Bala
 				amount = utils.Round(amount/b.Factor.GetValue(cd.TOR),
 					globalRoundingDecimals, utils.ROUNDING_UP)
 			}
-			if b.GetValue() >= amount {
+			if b.availableValue() >= amount {
 				b.SubstractValue(amount)
 				inc.BalanceInfo.Unit = &UnitInfo{
 					UUID:          b.Uuid,
@@ -477,17 +528,19 @@ func (b *Balance) debitUnits(cd *CallDescriptor, ub *Account, moneyBalances Bala
 					continue
 				}
 				var moneyBal *Balance
+				var moneyDebit, moneyXRate float64
 				for _, mb := range moneyBalances {
-					if mb.GetValue() >= cost {
-						moneyBal = mb
+					if debit, xRate, err := mb.exchangeCost(cost, ts.RateInterval, ts.TimeStart); err == nil && mb.availableValue() >= debit {
+						moneyBal, moneyDebit, moneyXRate = mb, debit, xRate
 						break
 					}
 				}
 				if cost != 0 && moneyBal == nil && (!dryRun || ub.AllowNegative) { // Fix for issue #685
 					utils.Logger.Warning(fmt.Sprintf("<RALs> Going negative on account %s with AllowNegative: false", cd.GetAccountKey()))
 					moneyBal = ub.GetDefaultMoneyBalance()
+					moneyDebit, moneyXRate = moneyBal.forceExchangeCost(cost, ts.RateInterval, ts.TimeStart)
 				}
-				if b.GetValue() >= amount && (moneyBal != nil || cost == 0) {
+				if b.availableValue() >= amount && (moneyBal != nil || cost == 0) {
 					b.SubstractValue(amount)
 					inc.BalanceInfo.Unit = &UnitInfo{
 						UUID:          b.Uuid,
@@ -500,11 +553,12 @@ func (b *Balance) debitUnits(cd *CallDescriptor, ub *Account, moneyBalances Bala
 					}
 					inc.BalanceInfo.AccountID = ub.ID
 					if cost != 0 {
-						moneyBal.SubstractValue(cost)
+						moneyBal.SubstractValue(moneyDebit)
 						inc.BalanceInfo.Monetary = &MonetaryInfo{
-							UUID:  moneyBal.Uuid,
-							ID:    moneyBal.ID,
-							Value: moneyBal.Value,
+							UUID:         moneyBal.Uuid,
+							ID:           moneyBal.ID,
+							Value:        moneyBal.Value,
+							ExchangeRate: moneyXRate,
 						}
 						cd.MaxCostSoFar += cost
 					}
@@ -512,7 +566,7 @@ func (b *Balance) debitUnits(cd *CallDescriptor, ub *Account, moneyBalances Bala
 					if count {
 						ub.countUnits(amount, cc.TOR, cc, b)
 						if cost != 0 {
-							ub.countUnits(cost, utils.MONETARY, cc, moneyBal)
+							ub.countUnits(moneyDebit, utils.MONETARY, cc, moneyBal)
 						}
 					}
 				} else {
@@ -537,7 +591,7 @@ func (b *Balance) debitUnits(cd *CallDescriptor, ub *Account, moneyBalances Bala
 }
 
 func (b *Balance) debitMoney(cd *CallDescriptor, ub *Account, moneyBalances Balances, count bool, dryRun, debitConnectFee bool) (cc *CallCost, err error) {
-	if !b.IsActiveAt(cd.TimeStart) || b.GetValue() <= 0 {
+	if !b.IsActiveAt(cd.TimeStart) || b.availableValue() <= 0 {
 		return
 	}
 	//log.Print("B: ", utils.ToJSON(b))
@@ -642,13 +696,18 @@ func (b *Balance) debitMoney(cd *CallDescriptor, ub *Account, moneyBalances Bala
 				continue
 			}
 
-			if b.GetValue() >= amount {
-				b.SubstractValue(amount)
+			debit, xRate, xErr := b.exchangeCost(amount, ts.RateInterval, ts.TimeStart)
+			if xErr != nil {
+				utils.Logger.Warning(fmt.Sprintf("<RALs> cannot convert cost for balance: %s, error: %s", b.ID, xErr.Error()))
+			}
+			if xErr == nil && b.availableValue() >= debit {
+				b.SubstractValue(debit)
 				cd.MaxCostSoFar += amount
 				inc.BalanceInfo.Monetary = &MonetaryInfo{
-					UUID:  b.Uuid,
-					ID:    b.ID,
-					Value: b.Value,
+					UUID:         b.Uuid,
+					ID:           b.ID,
+					Value:        b.Value,
+					ExchangeRate: xRate,
 				}
 				inc.BalanceInfo.AccountID = ub.ID
 				if b.RatingSubject != "" {
@@ -656,7 +715,7 @@ func (b *Balance) debitMoney(cd *CallDescriptor, ub *Account, moneyBalances Bala
 				}
 				inc.paid = true
 				if count {
-					ub.countUnits(amount, utils.MONETARY, cc, b)
+					ub.countUnits(debit, utils.MONETARY, cc, b)
 				}
 			} else {
 				inc.paid = false
@@ -725,6 +784,17 @@ func (bc Balances) GetTotalValue() (total float64) {
 	return
 }
 
+// availableValue returns the total value which can be debited out of the balances
+func (bc Balances) availableValue() (total float64) {
+	for _, b := range bc {
+		if !b.IsExpired() && b.IsActive() {
+			total += b.availableValue()
+		}
+	}
+	total = utils.Round(total, globalRoundingDecimals, utils.ROUNDING_MIDDLE)
+	return
+}
+
 func (bc Balances) Equal(o Balances) bool {
 	if len(bc) != len(o) {
 		return false
@@ -820,6 +890,7 @@ func (bc Balances) SaveDirtyBalances(acc *Account) {
 			})
 		}
 		if b.account != nil && b.account != acc && b.dirty && savedAccounts[b.account.ID] == nil {
+			b.account.publishExhaustedBalances()
 			dm.DataDB().SetAccount(b.account)
 			savedAccounts[b.account.ID] = b.account
 		}
@@ -22,6 +22,7 @@ import (
 	"errors"
 	"time"
 
+	"github.com/cgrates/cgrates/config"
 	"github.com/cgrates/cgrates/utils"
 )
 
@@ -32,6 +33,8 @@ type CallCost struct {
 	Timespans                                                       TimeSpans
 	RatedUsage                                                      float64
 	AccountSummary                                                  *AccountSummary
+	Currency                                                        string       // currency of the rating plan used
+	Taxes                                                           []*TaxCharge // taxes applied on top of Cost
 	deductConnectFee                                                bool
 	negativeConnectFee                                              bool // the connect fee went negative on default balance
 	maxCostDisconect                                                bool
@@ -180,6 +183,27 @@ func (cc *CallCost) updateCost() {
 		cost = utils.Round(cost, globalRoundingDecimals, utils.ROUNDING_MIDDLE) // just get rid of the extra decimals
 	}
 	cc.Cost = cost
+	cc.updateCurrency()
+}
+
+// updateCurrency populates the Currency out of the rates used
+func (cc *CallCost) updateCurrency() {
+	for _, ts := range cc.Timespans {
+		if ts.RateInterval != nil && ts.RateInterval.Rating != nil &&
+			ts.RateInterval.Rating.Currency != "" {
+			cc.Currency = ts.RateInterval.Rating.Currency
+			return
+		}
+	}
+	cc.Currency = config.CgrConfig().DefaultCurrency
+}
+
+// GetTaxCost returns the sum of the taxes applied
+func (cc *CallCost) GetTaxCost() (taxCost float64) {
+	for _, tc := range cc.Taxes {
+		taxCost += tc.Amount
+	}
+	return utils.Round(taxCost, globalRoundingDecimals, utils.ROUNDING_MIDDLE)
 }
 
 // Round creates the RoundIncrements in timespans
@@ -159,7 +159,8 @@ type CallDescriptor struct {
 	DryRun              bool
 	DenyNegativeAccount bool // prevent account going on negative during debit
 	account             *Account
-	testCallcost        *CallCost // testing purpose only!
+	volumeUsage         map[string]time.Duration // usage cumulated on the account volume counters before this call
+	testCallcost        *CallCost                // testing purpose only!
 }
 
 // AsCGREvent converts the CallDescriptor into CGREvent
@@ -259,7 +260,7 @@ func (cd *CallDescriptor) getAccount() (ub *Account, err error) {
 		return nil, utils.ErrAccountDisabled
 	}
 	if err != nil || cd.account == nil {
-		utils.Logger.Warning(fmt.Sprintf("Account: %s, not found (%v)", cd.GetAccountKey(), err))
+		utils.Logger.Log(utils.LOGLEVEL_WARNING, cd.CgrID, fmt.Sprintf("Account: %s, not found (%v)", cd.GetAccountKey(), err))
 		return nil, utils.ErrAccountNotFound
 	}
 	return cd.account, err
@@ -278,12 +279,12 @@ func (cd *CallDescriptor) LoadRatingPlans() (err error) {
 	}
 	//load the rating plans
 	if err != nil {
-		utils.Logger.Err(fmt.Sprintf("Rating plan not found for destination %s and account: %s, subject: %s", cd.Destination, cd.GetAccountKey(), cd.GetKey(cd.Subject)))
+		utils.Logger.Log(utils.LOGLEVEL_ERROR, cd.CgrID, fmt.Sprintf("Rating plan not found for destination %s and account: %s, subject: %s", cd.Destination, cd.GetAccountKey(), cd.GetKey(cd.Subject)))
 		return utils.ErrRatingPlanNotFound
 
 	}
 	if !cd.continousRatingInfos() {
-		utils.Logger.Err(fmt.Sprintf("Destination %s not authorized for account: %s, subject: %s", cd.Destination, cd.GetAccountKey(), cd.GetKey(cd.Subject)))
+		utils.Logger.Log(utils.LOGLEVEL_ERROR, cd.CgrID, fmt.Sprintf("Destination %s not authorized for account: %s, subject: %s", cd.Destination, cd.GetAccountKey(), cd.GetKey(cd.Subject)))
 		return utils.ErrUnauthorizedDestination
 	}
 	return
@@ -413,7 +414,8 @@ func (cd *CallDescriptor) GetAccountKey() string {
 
 // Splits the received timespan into sub time spans according to the activation periods intervals.
 func (cd *CallDescriptor) splitInTimeSpans() (timespans []*TimeSpan) {
-	firstSpan := &TimeSpan{TimeStart: cd.TimeStart, TimeEnd: cd.TimeEnd, DurationIndex: cd.DurationIndex}
+	firstSpan := &TimeSpan{TimeStart: cd.TimeStart, TimeEnd: cd.TimeEnd, DurationIndex: cd.DurationIndex,
+		volumeUsage: cd.volumeUsage}
 
 	timespans = append(timespans, firstSpan)
 	if len(cd.RatingInfos) == 0 {
@@ -603,6 +605,7 @@ func (cd *CallDescriptor) getCost() (*CallCost, error) {
 		//utils.Logger.Err(fmt.Sprintf("error getting cost for key <%s>: %s", cd.GetKey(cd.Subject), err.Error()))
 		return &CallCost{Cost: -1}, err
 	}
+	cd.loadVolumeUsage()
 	timespans := cd.splitInTimeSpans()
 	cost := 0.0
 
@@ -627,6 +630,7 @@ func (cd *CallDescriptor) getCost() (*CallCost, error) {
 	//utils.Logger.Info(fmt.Sprintf("<Rater> Get Cost: %s => %v", cd.GetKey(), cc))
 	cc.Timespans.Compress()
 	cc.UpdateRatedUsage()
+	cc.updateCurrency()
 	return cc, err
 }
 
@@ -748,10 +752,11 @@ func (cd *CallDescriptor) debit(account *Account, dryRun bool, goNegative bool)
 		cd.TOR = utils.VOICE
 	}
 	//log.Printf("Debit CD: %+v", cd)
+	cd.setVolumeUsage(account)
 	cc, err = account.debitCreditBalance(cd, !dryRun, dryRun, goNegative)
 	//log.Printf("HERE: %+v %v", cc, err)
 	if err != nil {
-		utils.Logger.Err(fmt.Sprintf("<Rater> Error getting cost for account key <%s>: %s", cd.GetAccountKey(), err.Error()))
+		utils.Logger.Log(utils.LOGLEVEL_ERROR, cd.CgrID, fmt.Sprintf("<Rater> Error getting cost for account key <%s>: %s", cd.GetAccountKey(), err.Error()))
 		return nil, err
 	}
 	cc.updateCost()
@@ -875,14 +880,17 @@ func (cd *CallDescriptor) MaxDebit() (cc *CallCost, err error) {
 // refundIncrements has no locks
 func (cd *CallDescriptor) refundIncrements() (err error) {
 	accountsCache := make(map[string]*Account)
+	defer func() { // will save the accounts only once at the end of the function
+		for _, account := range accountsCache {
+			dm.DataDB().SetAccount(account)
+		}
+	}()
 	for _, increment := range cd.Increments {
 		account, found := accountsCache[increment.BalanceInfo.AccountID]
 		if !found {
 			if acc, err := dm.DataDB().GetAccount(increment.BalanceInfo.AccountID); err == nil && acc != nil {
 				account = acc
 				accountsCache[increment.BalanceInfo.AccountID] = account
-				// will save the account only once at the end of the function
-				defer dm.DataDB().SetAccount(account)
 			}
 		}
 		if account == nil {
@@ -890,6 +898,7 @@ func (cd *CallDescriptor) refundIncrements() (err error) {
 			continue
 		}
 		//utils.Logger.Info(fmt.Sprintf("Refunding increment %+v", increment))
+		account.refundVolumeUsage(increment)
 		var balance *Balance
 		unitType := cd.TOR
 		cc := cd.CreateCallCost()
@@ -905,8 +914,15 @@ func (cd *CallDescriptor) refundIncrements() (err error) {
 			if balance = account.BalanceMap[utils.MONETARY].GetBalance(increment.BalanceInfo.Monetary.UUID); balance == nil {
 				return
 			}
-			balance.AddValue(increment.Cost)
-			account.countUnits(-increment.Cost, utils.MONETARY, cc, balance)
+			refund := increment.Cost
+			if increment.BalanceInfo.Monetary.ExchangeRate != 0 { // debited in the balance currency
+				refund = utils.Round(refund*increment.BalanceInfo.Monetary.ExchangeRate, globalRoundingDecimals, utils.ROUNDING_MIDDLE)
+			}
+			balance.AddValue(refund)
+			account.countUnits(-refund, utils.MONETARY, cc, balance)
+			if account.ID != cd.GetAccountKey() { // debited out of an ancestor
+				refundCreditUsed(cd.GetAccountKey(), account.ID, refund, accountsCache)
+			}
 		}
 	}
 	return
@@ -926,6 +942,13 @@ func (cd *CallDescriptor) RefundIncrements() (err error) {
 			accMap[utils.ACCOUNT_PREFIX+increment.BalanceInfo.AccountID] = true
 		}
 	}
+	if len(accMap) != 0 && (len(accMap) > 1 || !accMap[utils.ACCOUNT_PREFIX+cd.GetAccountKey()]) {
+		// refunds into other accounts, the hierarchy credit used is updated too
+		accMap[utils.ACCOUNT_PREFIX+cd.GetAccountKey()] = true
+		for _, ancID := range getAncestorIDs(cd.GetAccountKey()) {
+			accMap[utils.ACCOUNT_PREFIX+ancID] = true
+		}
+	}
 	_, err = guardian.Guardian.Guard(func() (iface interface{}, err error) {
 		err = cd.refundIncrements()
 		return
@@ -1014,7 +1037,51 @@ func (cd *CallDescriptor) Clone() *CallDescriptor {
 		DryRun:          cd.DryRun,
 		CgrID:           cd.CgrID,
 		RunID:           cd.RunID,
+		volumeUsage:     cd.volumeUsage,
+	}
+}
+
+// hasVolumeCounters checks if any of the loaded rate intervals applies its rates on a volume counter
+func (cd *CallDescriptor) hasVolumeCounters() bool {
+	for _, rInfo := range cd.RatingInfos {
+		for _, ri := range rInfo.RateIntervals {
+			if ri.Rating != nil && ri.Rating.VolumeCounter != "" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// setVolumeUsage populates the usage cumulated on the account volume counters,
+// the part of the call already counted by previous debits (DurationIndex) is excluded
+func (cd *CallDescriptor) setVolumeUsage(acnt *Account) {
+	callUsage := cd.DurationIndex - cd.GetDuration()
+	if callUsage < 0 {
+		callUsage = 0
+	}
+	cd.volumeUsage = make(map[string]time.Duration, len(acnt.VolumeCounters))
+	for cntID, usage := range acnt.VolumeCounters {
+		if usage -= callUsage; usage < 0 {
+			usage = 0
+		}
+		cd.volumeUsage[cntID] = usage
+	}
+}
+
+// loadVolumeUsage queries the account volume counters when rating on volume tiers outside of a debit
+func (cd *CallDescriptor) loadVolumeUsage() {
+	if cd.volumeUsage != nil || !cd.hasVolumeCounters() {
+		return
+	}
+	acnt := cd.account
+	if acnt == nil {
+		var err error
+		if acnt, err = dm.DataDB().GetAccount(cd.GetAccountKey()); err != nil {
+			return // rate without volume usage
+		}
 	}
+	cd.setVolumeUsage(acnt)
 }
 
 func (cd *CallDescriptor) GetLCRFromStorage() (*LCR, error) {
@@ -1740,6 +1740,21 @@ func TestCDRefundIncrementsZeroValue(t *testing.T) {
 	}
 }
 
+func TestCDSetVolumeUsage(t *testing.T) {
+	acnt := &Account{VolumeCounters: map[string]time.Duration{
+		"VOL_MONTH": 10 * time.Minute, "VOL_DAY": time.Minute}}
+	cd := &CallDescriptor{
+		TimeStart:     time.Date(2017, time.March, 1, 10, 2, 0, 0, time.UTC),
+		TimeEnd:       time.Date(2017, time.March, 1, 10, 3, 0, 0, time.UTC),
+		DurationIndex: 3 * time.Minute,
+	}
+	cd.setVolumeUsage(acnt) // first two minutes of the call were counted on previous debits
+	eUsage := map[string]time.Duration{"VOL_MONTH": 8 * time.Minute, "VOL_DAY": 0}
+	if !reflect.DeepEqual(eUsage, cd.volumeUsage) {
+		t.Errorf("Expecting: %+v, received: %+v", eUsage, cd.volumeUsage)
+	}
+}
+
 func TestCDDebitBalanceSubjectWithFallback(t *testing.T) {
 	acnt := &Account{
 		ID: "TCDDBSWF:account1",
@@ -129,6 +129,39 @@ func (cdr *CDR) FormatCost(shiftDecimals, roundDecimals int) string {
 	return strconv.FormatFloat(cost, 'f', roundDecimals, 64)
 }
 
+// TaxCost returns the amount of the tax with taxID or the total of taxes if taxID is empty
+func (cdr *CDR) TaxCost(taxID string) (taxCost float64) {
+	if cdr.CostDetails == nil {
+		return
+	}
+	if taxID == "" {
+		return cdr.CostDetails.GetTaxCost()
+	}
+	for _, tc := range cdr.CostDetails.Taxes {
+		if tc.TaxID == taxID {
+			taxCost += tc.Amount
+		}
+	}
+	return
+}
+
+// Formats the tax cost on export, taxID empty for the total of taxes
+func (cdr *CDR) FormatTaxCost(taxID string, shiftDecimals, roundDecimals int) string {
+	taxCost := cdr.TaxCost(taxID)
+	if shiftDecimals != 0 {
+		taxCost = taxCost * math.Pow10(shiftDecimals)
+	}
+	return strconv.FormatFloat(taxCost, 'f', roundDecimals, 64)
+}
+
+// taxIDFromField returns the tax ID out of a TaxCost:<TaxID> field
+func taxIDFromField(fldID string) (taxID string, isTax bool) {
+	if !strings.HasPrefix(fldID, utils.TaxCost+utils.CONCATENATED_KEY_SEP) {
+		return
+	}
+	return fldID[len(utils.TaxCost+utils.CONCATENATED_KEY_SEP):], true
+}
+
 // Formats usage on export
 func (cdr *CDR) FormatUsage(layout string) string {
 	if utils.IsSliceMember([]string{utils.DATA, utils.SMS, utils.MMS, utils.GENERIC}, cdr.ToR) {
@@ -186,7 +219,17 @@ func (cdr *CDR) FieldAsString(rsrFld *utils.RSRField) string {
 		return rsrFld.ParseValue(cdr.CostDetailsJson())
 	case utils.PartialField:
 		return rsrFld.ParseValue(strconv.FormatBool(cdr.Partial))
+	case utils.Currency:
+		if cdr.CostDetails == nil {
+			return rsrFld.ParseValue("")
+		}
+		return rsrFld.ParseValue(cdr.CostDetails.Currency)
+	case utils.TaxCost:
+		return rsrFld.ParseValue(strconv.FormatFloat(cdr.TaxCost(""), 'f', -1, 64))
 	default:
+		if taxID, isTax := taxIDFromField(rsrFld.Id); isTax {
+			return rsrFld.ParseValue(strconv.FormatFloat(cdr.TaxCost(taxID), 'f', -1, 64))
+		}
 		return rsrFld.ParseValue(cdr.ExtraFields[rsrFld.Id])
 	}
 }
@@ -620,6 +663,8 @@ func (cdr *CDR) exportFieldValue(cfgCdrFld *config.CfgCdrField) (string, error)
 		switch rsrFld.Id {
 		case utils.COST:
 			cdrVal = cdr.FormatCost(cfgCdrFld.CostShiftDigits, cfgCdrFld.RoundingDecimals)
+		case utils.TaxCost:
+			cdrVal = cdr.FormatTaxCost("", cfgCdrFld.CostShiftDigits, cfgCdrFld.RoundingDecimals)
 		case utils.Usage:
 			cdrVal = cdr.FormatUsage(cfgCdrFld.Layout)
 		case utils.SetupTime:
@@ -632,7 +677,11 @@ func (cdr *CDR) exportFieldValue(cfgCdrFld *config.CfgCdrField) (string, error)
 				cdrVal = utils.MaskSuffix(cdrVal, cfgCdrFld.MaskLen)
 			}
 		default:
-			cdrVal = cdr.FieldAsString(rsrFld)
+			if taxID, isTax := taxIDFromField(rsrFld.Id); isTax {
+				cdrVal = cdr.FormatTaxCost(taxID, cfgCdrFld.CostShiftDigits, cfgCdrFld.RoundingDecimals)
+			} else {
+				cdrVal = cdr.FieldAsString(rsrFld)
+			}
 		}
 		retVal += cdrVal
 	}
@@ -24,6 +24,7 @@ import (
 	"net/http"
 	"reflect"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/cgrates/cgrates/cache"
@@ -70,7 +71,8 @@ func fsCdrHandler(w http.ResponseWriter, r *http.Request) {
 }
 
 func NewCdrServer(cgrCfg *config.CGRConfig, cdrDb CdrStorage, dm *DataManager, rater, pubsub,
-	attrs, users, aliases, cdrstats, thdS, stats rpcclient.RpcClientConnection) (*CdrServer, error) {
+	attrs, users, aliases, cdrstats, thdS, stats rpcclient.RpcClientConnection,
+	filterS *FilterS) (*CdrServer, error) {
 	if rater != nil && reflect.ValueOf(rater).IsNil() { // Work around so we store actual nil instead of nil interface value, faster to check here than in CdrServer code
 		rater = nil
 	}
@@ -98,10 +100,13 @@ func NewCdrServer(cgrCfg *config.CGRConfig, cdrDb CdrStorage, dm *DataManager, r
 	return &CdrServer{cgrCfg: cgrCfg, cdrDb: cdrDb, dm: dm,
 		rals: rater, pubsub: pubsub, users: users, aliases: aliases,
 		cdrstats: cdrstats, stats: stats, thdS: thdS, guard: guardian.Guardian,
+		taxS:       NewTaxService(dm, filterS),
 		httpPoster: utils.NewHTTPPoster(cgrCfg.HttpSkipTlsVerify, cgrCfg.ReplyTimeout)}, nil
 }
 
 type CdrServer struct {
+	processedCDRs uint64 // accessed atomically, keep first for alignment
+	failedCDRs    uint64
 	cgrCfg        *config.CGRConfig
 	cdrDb         CdrStorage
 	dm            *DataManager
@@ -113,6 +118,7 @@ type CdrServer struct {
 	cdrstats      rpcclient.RpcClientConnection
 	thdS          rpcclient.RpcClientConnection
 	stats         rpcclient.RpcClientConnection
+	taxS          *TaxService
 	guard         *guardian.GuardianLock
 	responseCache *cache.ResponseCache
 	httpPoster    *utils.HTTPPoster // used for replication
@@ -133,6 +139,18 @@ func (self *CdrServer) getCache() *cache.ResponseCache {
 	return self.responseCache
 }
 
+// PromMetrics exports the CDR processing counters
+func (self *CdrServer) PromMetrics() []*utils.PromMetric {
+	return []*utils.PromMetric{
+		&utils.PromMetric{Name: utils.PromNamespace + "_cdrs_processed_total",
+			Help: "CDRs successfully processed by CDRs", Type: utils.PromCounter,
+			Samples: []*utils.PromSample{&utils.PromSample{Value: float64(atomic.LoadUint64(&self.processedCDRs))}}},
+		&utils.PromMetric{Name: utils.PromNamespace + "_cdrs_failed_total",
+			Help: "CDRs which could not be processed by CDRs", Type: utils.PromCounter,
+			Samples: []*utils.PromSample{&utils.PromSample{Value: float64(atomic.LoadUint64(&self.failedCDRs))}}},
+	}
+}
+
 func (self *CdrServer) RegisterHandlersToServer(server *utils.Server) {
 	cdrServer = self // Share the server object for handlers
 	server.RegisterHttpFunc("/cdr_http", cgrCdrHandler)
@@ -170,6 +188,13 @@ func (self *CdrServer) storeSMCost(smCost *SMCost, checkDuplicate bool) error {
 
 // Returns error if not able to properly store the CDR, mediation is async since we can always recover offline
 func (self *CdrServer) processCdr(cdr *CDR) (err error) {
+	defer func() {
+		if err != nil {
+			atomic.AddUint64(&self.failedCDRs, 1)
+		} else {
+			atomic.AddUint64(&self.processedCDRs, 1)
+		}
+	}()
 	if cdr.RequestType == "" {
 		cdr.RequestType = self.cgrCfg.DefaultReqType
 	}
@@ -194,7 +219,7 @@ func (self *CdrServer) processCdr(cdr *CDR) (err error) {
 			cdr.CostDetails.UpdateRatedUsage()
 		}
 		if err := self.cdrDb.SetCDR(cdr, false); err != nil {
-			utils.Logger.Err(fmt.Sprintf("<CDRS> Storing primary CDR %+v, got error: %s", cdr, err.Error()))
+			utils.Logger.Log(utils.LOGLEVEL_ERROR, cdr.CGRID, fmt.Sprintf("<CDRS> Storing primary CDR %+v, got error: %s", cdr, err.Error()))
 			return err // Error is propagated back and we don't continue processing the CDR if we cannot store it
 		}
 	}
@@ -202,7 +227,7 @@ func (self *CdrServer) processCdr(cdr *CDR) (err error) {
 		var hits int
 		cgrEv := cdr.AsCGREvent()
 		if err := self.thdS.Call(utils.ThresholdSv1ProcessEvent, cgrEv, &hits); err != nil {
-			utils.Logger.Warning(
+			utils.Logger.Log(utils.LOGLEVEL_WARNING, cdr.CGRID,
 				fmt.Sprintf("<CDRS> error: %s processing CDR event %+v with thdS.", err.Error(), cgrEv))
 		}
 	}
@@ -229,7 +254,7 @@ func (self *CdrServer) processCdr(cdr *CDR) (err error) {
 func (self *CdrServer) deriveRateStoreStatsReplicate(cdr *CDR, store, cdrstats, replicate bool) (err error) {
 	cdrRuns, err := self.deriveCdrs(cdr)
 	if err != nil {
-		utils.Logger.Err(fmt.Sprintf("<CDRS> Deriving CDR %+v, got error: %s", cdr, err.Error()))
+		utils.Logger.Log(utils.LOGLEVEL_ERROR, cdr.CGRID, fmt.Sprintf("<CDRS> Deriving CDR %+v, got error: %s", cdr, err.Error()))
 		return err
 	}
 	var ratedCDRs []*CDR // Gather all CDRs received from rating subsystem
@@ -246,7 +271,7 @@ func (self *CdrServer) deriveRateStoreStatsReplicate(cdr *CDR, store, cdrstats,
 			}
 		}
 		if err := LoadUserProfile(cdrRun, utils.EXTRA_FIELDS); err != nil {
-			utils.Logger.Err(fmt.Sprintf("<CDRS> UserS handling for CDR %+v, got error: %s", cdrRun, err.Error()))
+			utils.Logger.Log(utils.LOGLEVEL_ERROR, cdrRun.CGRID, fmt.Sprintf("<CDRS> UserS handling for CDR %+v, got error: %s", cdrRun, err.Error()))
 			continue
 		}
 		if err := LoadAlias(&AttrMatchingAlias{
@@ -258,7 +283,7 @@ func (self *CdrServer) deriveRateStoreStatsReplicate(cdr *CDR, store, cdrstats,
 			Subject:     cdrRun.Subject,
 			Context:     utils.MetaRating,
 		}, cdrRun, utils.EXTRA_FIELDS); err != nil && err != utils.ErrNotFound {
-			utils.Logger.Err(fmt.Sprintf("<CDRS> Aliasing CDR %+v, got error: %s", cdrRun, err.Error()))
+			utils.Logger.Log(utils.LOGLEVEL_ERROR, cdrRun.CGRID, fmt.Sprintf("<CDRS> Aliasing CDR %+v, got error: %s", cdrRun, err.Error()))
 			continue
 		}
 		rcvRatedCDRs, err := self.rateCDR(cdrRun)
@@ -286,7 +311,7 @@ func (self *CdrServer) deriveRateStoreStatsReplicate(cdr *CDR, store, cdrstats,
 				ratedCDR.CostDetails.UpdateRatedUsage()
 			}
 			if err := self.cdrDb.SetCDR(ratedCDR, true); err != nil {
-				utils.Logger.Err(fmt.Sprintf("<CDRS> Storing rated CDR %+v, got error: %s", ratedCDR, err.Error()))
+				utils.Logger.Log(utils.LOGLEVEL_ERROR, ratedCDR.CGRID, fmt.Sprintf("<CDRS> Storing rated CDR %+v, got error: %s", ratedCDR, err.Error()))
 			}
 		}
 	}
@@ -296,7 +321,7 @@ func (self *CdrServer) deriveRateStoreStatsReplicate(cdr *CDR, store, cdrstats,
 			if self.cdrstats != nil {
 				var out int
 				if err := self.cdrstats.Call("CDRStatsV1.AppendCDR", ratedCDR, &out); err != nil {
-					utils.Logger.Err(fmt.Sprintf("<CDRS> Could not send CDR to cdrstats: %s", err.Error()))
+					utils.Logger.Log(utils.LOGLEVEL_ERROR, ratedCDR.CGRID, fmt.Sprintf("<CDRS> Could not send CDR to cdrstats: %s", err.Error()))
 				}
 			}
 			if self.stats != nil {
@@ -347,7 +372,7 @@ func (self *CdrServer) deriveCdrs(cdr *CDR) (drvdCDRs []*CDR, err error) {
 		Account: cdr.Account, Subject: cdr.Subject, Destination: cdr.Destination}
 	var dcs utils.DerivedChargers
 	if err := self.rals.Call("Responder.GetDerivedChargers", attrsDC, &dcs); err != nil {
-		utils.Logger.Err(fmt.Sprintf("Could not get derived charging for cgrid %s, error: %s", cdr.CGRID, err.Error()))
+		utils.Logger.Log(utils.LOGLEVEL_ERROR, cdr.CGRID, fmt.Sprintf("Could not get derived charging for cgrid %s, error: %s", cdr.CGRID, err.Error()))
 		return nil, err
 	}
 	for _, dc := range dcs.Chargers {
@@ -382,7 +407,7 @@ func (self *CdrServer) deriveCdrs(cdr *CDR) (drvdCDRs []*CDR, err error) {
 		forkedCdr, err := cdr.ForkCdr(dc.RunID, dcRequestTypeFld, dcTenantFld, dcCategoryFld, dcAcntFld, dcSubjFld, dcDstFld,
 			dcSTimeFld, dcATimeFld, dcDurFld, dcRatedFld, dcCostFld, dcExtraFields, true, self.cgrCfg.DefaultTimezone)
 		if err != nil {
-			utils.Logger.Err(fmt.Sprintf("Could not fork CGR with cgrid %s, run: %s, error: %s", cdr.CGRID, dc.RunID, err.Error()))
+			utils.Logger.Log(utils.LOGLEVEL_ERROR, cdr.CGRID, fmt.Sprintf("Could not fork CGR with cgrid %s, run: %s, error: %s", cdr.CGRID, dc.RunID, err.Error()))
 			continue // do not add it to the forked CDR list
 		}
 		if !forkedCdr.Rated {
@@ -435,9 +460,9 @@ func (self *CdrServer) rateCDR(cdr *CDR) ([]*CDR, error) {
 				cdrClone.CostSource = smCost.CostSource
 				cdrsRated = append(cdrsRated, cdrClone)
 			}
-			return cdrsRated, nil
+			return cdrsRated, self.applyTaxes(cdrsRated)
 		} else { //calculate CDR as for pseudoprepaid
-			utils.Logger.Warning(fmt.Sprintf("<Cdrs> WARNING: Could not find CallCostLog for cgrid: %s, source: %s, runid: %s, will recalculate", cdr.CGRID, utils.SESSION_MANAGER_SOURCE, cdr.RunID))
+			utils.Logger.Log(utils.LOGLEVEL_WARNING, cdr.CGRID, fmt.Sprintf("<Cdrs> WARNING: Could not find CallCostLog for cgrid: %s, source: %s, runid: %s, will recalculate", cdr.CGRID, utils.SESSION_MANAGER_SOURCE, cdr.RunID))
 			qryCC, err = self.getCostFromRater(cdr)
 		}
 	} else {
@@ -449,7 +474,21 @@ func (self *CdrServer) rateCDR(cdr *CDR) ([]*CDR, error) {
 		cdr.Cost = qryCC.Cost
 		cdr.CostDetails = qryCC
 	}
-	return []*CDR{cdr}, nil
+	cdrsRated = []*CDR{cdr}
+	return cdrsRated, self.applyTaxes(cdrsRated)
+}
+
+// applyTaxes attaches the local taxes to the rated CDRs when enabled in config
+func (self *CdrServer) applyTaxes(cdrs []*CDR) error {
+	if !self.cgrCfg.CDRSApplyTaxes {
+		return nil
+	}
+	for _, cdr := range cdrs {
+		if err := self.taxS.ApplyTaxes(cdr); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 // Retrive the cost from engine
@@ -511,7 +550,7 @@ func (self *CdrServer) RateCDRs(cdrFltr *utils.CDRsFilter, sendToStats bool) err
 	}
 	for _, cdr := range cdrs {
 		if err := self.deriveRateStoreStatsReplicate(cdr, self.cgrCfg.CDRSStoreCdrs, sendToStats, len(self.cgrCfg.CDRSOnlineCDRExports) != 0); err != nil {
-			utils.Logger.Err(fmt.Sprintf("<CDRS> Processing CDR %+v, got error: %s", cdr, err.Error()))
+			utils.Logger.Log(utils.LOGLEVEL_ERROR, cdr.CGRID, fmt.Sprintf("<CDRS> Processing CDR %+v, got error: %s", cdr, err.Error()))
 		}
 	}
 	return nil
@@ -630,7 +669,7 @@ func (self *CdrServer) V1RateCDRs(attrs utils.AttrRateCDRs, reply *string) error
 	}
 	for _, cdr := range cdrs {
 		if err := self.deriveRateStoreStatsReplicate(cdr, storeCDRs, sendToStats, replicate); err != nil {
-			utils.Logger.Err(fmt.Sprintf("<CDRS> Processing CDR %+v, got error: %s", cdr, err.Error()))
+			utils.Logger.Log(utils.LOGLEVEL_ERROR, cdr.CGRID, fmt.Sprintf("<CDRS> Processing CDR %+v, got error: %s", cdr, err.Error()))
 		}
 	}
 	return nil
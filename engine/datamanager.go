@@ -17,6 +17,7 @@ package engine
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/cgrates/cgrates/cache"
 	"github.com/cgrates/cgrates/config"
@@ -54,9 +55,12 @@ func (dm *DataManager) LoadDataDBCache(dstIDs, rvDstIDs, rplIDs, rpfIDs, actIDs,
 				utils.SHARED_GROUP_PREFIX, utils.ALIASES_PREFIX, utils.REVERSE_ALIASES_PREFIX, utils.StatQueuePrefix,
 				utils.StatQueueProfilePrefix, utils.ThresholdPrefix, utils.ThresholdProfilePrefix,
 				utils.FilterPrefix, utils.SupplierProfilePrefix, utils.AttributeProfilePrefix}, k) && cacheCfg.Precache {
+				partition := utils.CachePrefixToInstance[k]
+				cache.SetPrecacheStatus(partition, utils.MetaLoading)
 				if err := dm.PreloadCacheForPrefix(k); err != nil && err != utils.ErrInvalidKey {
 					return err
 				}
+				cache.SetPrecacheStatus(partition, utils.MetaReady)
 			}
 		}
 		return
@@ -85,9 +89,12 @@ func (dm *DataManager) LoadDataDBCache(dstIDs, rvDstIDs, rplIDs, rpfIDs, actIDs,
 			utils.SupplierProfilePrefix:      splPrflIDs,
 			utils.AttributeProfilePrefix:     alsPrfIDs,
 		} {
+			partition := utils.CachePrefixToInstance[key]
+			cache.SetPrecacheStatus(partition, utils.MetaLoading) // warm-up progress, queried by readiness probes
 			if err = dm.CacheDataFromDB(key, ids, false); err != nil {
 				return
 			}
+			cache.SetPrecacheStatus(partition, utils.MetaReady)
 		}
 	}
 	return
@@ -142,7 +149,10 @@ func (dm *DataManager) CacheDataFromDB(prfx string, ids []string, mustBeCached b
 		utils.ThresholdProfilePrefix,
 		utils.FilterPrefix,
 		utils.SupplierProfilePrefix,
-		utils.AttributeProfilePrefix}, prfx) {
+		utils.AttributeProfilePrefix,
+		utils.ExchangeRatePrefix,
+		utils.TaxProfilePrefix,
+		utils.FeeProfilePrefix}, prfx) {
 		return utils.NewCGRError(utils.MONGO,
 			utils.MandatoryIEMissingCaps,
 			utils.UnsupportedCachePrefix,
@@ -234,6 +244,18 @@ func (dm *DataManager) CacheDataFromDB(prfx string, ids []string, mustBeCached b
 		case utils.AttributeProfilePrefix:
 			tntID := utils.NewTenantID(dataID)
 			_, err = dm.GetAttributeProfile(tntID.Tenant, tntID.ID, true, utils.NonTransactional)
+		case utils.ExchangeRatePrefix:
+			xrtID := strings.SplitN(dataID, utils.CONCATENATED_KEY_SEP, 2)
+			if len(xrtID) != 2 {
+				return utils.ErrInvalidKey
+			}
+			_, err = dm.GetExchangeRate(xrtID[0], xrtID[1], true, utils.NonTransactional)
+		case utils.TaxProfilePrefix:
+			tntID := utils.NewTenantID(dataID)
+			_, err = dm.GetTaxProfile(tntID.Tenant, tntID.ID, true, utils.NonTransactional)
+		case utils.FeeProfilePrefix:
+			tntID := utils.NewTenantID(dataID)
+			_, err = dm.GetFeeProfile(tntID.Tenant, tntID.ID, true, utils.NonTransactional)
 		}
 		if err != nil {
 			return utils.NewCGRError(utils.MONGO,
@@ -1023,3 +1045,105 @@ func (dm *DataManager) RemoveAttributeProfile(tenant, id, transactionID string)
 		cacheCommit(transactionID), transactionID)
 	return
 }
+
+func (dm *DataManager) GetExchangeRate(fromCurrency, toCurrency string, skipCache bool, transactionID string) (xr *ExchangeRate, err error) {
+	key := utils.ExchangeRatePrefix + utils.ConcatenatedKey(fromCurrency, toCurrency)
+	if !skipCache {
+		if x, ok := cache.Get(key); ok {
+			if x == nil {
+				return nil, utils.ErrNotFound
+			}
+			return x.(*ExchangeRate), nil
+		}
+	}
+	xr, err = dm.dataDB.GetExchangeRateDrv(fromCurrency, toCurrency)
+	if err != nil {
+		if err == utils.ErrNotFound {
+			cache.Set(key, nil, cacheCommit(transactionID), transactionID)
+		}
+		return nil, err
+	}
+	cache.Set(key, xr, cacheCommit(transactionID), transactionID)
+	return
+}
+
+func (dm *DataManager) SetExchangeRate(xr *ExchangeRate) (err error) {
+	return dm.DataDB().SetExchangeRateDrv(xr)
+}
+
+func (dm *DataManager) RemoveExchangeRate(fromCurrency, toCurrency, transactionID string) (err error) {
+	if err = dm.DataDB().RemoveExchangeRateDrv(fromCurrency, toCurrency); err != nil {
+		return
+	}
+	cache.RemKey(utils.ExchangeRatePrefix+utils.ConcatenatedKey(fromCurrency, toCurrency),
+		cacheCommit(transactionID), transactionID)
+	return
+}
+
+func (dm *DataManager) GetTaxProfile(tenant, id string, skipCache bool, transactionID string) (txPrf *TaxProfile, err error) {
+	key := utils.TaxProfilePrefix + utils.ConcatenatedKey(tenant, id)
+	if !skipCache {
+		if x, ok := cache.Get(key); ok {
+			if x == nil {
+				return nil, utils.ErrNotFound
+			}
+			return x.(*TaxProfile), nil
+		}
+	}
+	txPrf, err = dm.dataDB.GetTaxProfileDrv(tenant, id)
+	if err != nil {
+		if err == utils.ErrNotFound {
+			cache.Set(key, nil, cacheCommit(transactionID), transactionID)
+		}
+		return nil, err
+	}
+	cache.Set(key, txPrf, cacheCommit(transactionID), transactionID)
+	return
+}
+
+func (dm *DataManager) SetTaxProfile(txPrf *TaxProfile) (err error) {
+	return dm.DataDB().SetTaxProfileDrv(txPrf)
+}
+
+func (dm *DataManager) RemoveTaxProfile(tenant, id, transactionID string) (err error) {
+	if err = dm.DataDB().RemoveTaxProfileDrv(tenant, id); err != nil {
+		return
+	}
+	cache.RemKey(utils.TaxProfilePrefix+utils.ConcatenatedKey(tenant, id),
+		cacheCommit(transactionID), transactionID)
+	return
+}
+
+func (dm *DataManager) GetFeeProfile(tenant, id string, skipCache bool, transactionID string) (fep *FeeProfile, err error) {
+	key := utils.FeeProfilePrefix + utils.ConcatenatedKey(tenant, id)
+	if !skipCache {
+		if x, ok := cache.Get(key); ok {
+			if x == nil {
+				return nil, utils.ErrNotFound
+			}
+			return x.(*FeeProfile), nil
+		}
+	}
+	fep, err = dm.dataDB.GetFeeProfileDrv(tenant, id)
+	if err != nil {
+		if err == utils.ErrNotFound {
+			cache.Set(key, nil, cacheCommit(transactionID), transactionID)
+		}
+		return nil, err
+	}
+	cache.Set(key, fep, cacheCommit(transactionID), transactionID)
+	return
+}
+
+func (dm *DataManager) SetFeeProfile(fep *FeeProfile) (err error) {
+	return dm.DataDB().SetFeeProfileDrv(fep)
+}
+
+func (dm *DataManager) RemoveFeeProfile(tenant, id, transactionID string) (err error) {
+	if err = dm.DataDB().RemoveFeeProfileDrv(tenant, id); err != nil {
+		return
+	}
+	cache.RemKey(utils.FeeProfilePrefix+utils.ConcatenatedKey(tenant, id),
+		cacheCommit(transactionID), transactionID)
+	return
+}
@@ -40,6 +40,13 @@ func NewEventCostFromCallCost(cc *CallCost, cgrID, runID string) (ec *EventCost)
 	ec.CGRID = cgrID
 	ec.RunID = runID
 	ec.AccountSummary = cc.AccountSummary
+	ec.Currency = cc.Currency
+	if len(cc.Taxes) != 0 {
+		ec.Taxes = make([]*TaxCharge, len(cc.Taxes))
+		for i, tc := range cc.Taxes {
+			ec.Taxes[i] = tc.Clone()
+		}
+	}
 	if len(cc.Timespans) != 0 {
 		ec.Charges = make([]*ChargingInterval, len(cc.Timespans))
 		ec.StartTime = cc.Timespans[0].TimeStart
@@ -67,10 +74,11 @@ func NewEventCostFromCallCost(cc *CallCost, cgrID, runID string) (ec *EventCost)
 				if incr.BalanceInfo.Monetary != nil {
 					if uuid := ec.Accounting.GetIDWithSet(
 						&BalanceCharge{
-							AccountID:   incr.BalanceInfo.AccountID,
-							BalanceUUID: incr.BalanceInfo.Monetary.UUID,
-							Units:       incr.Cost,
-							RatingID:    ec.ratingIDForRateInterval(incr.BalanceInfo.Monetary.RateInterval, rf),
+							AccountID:    incr.BalanceInfo.AccountID,
+							BalanceUUID:  incr.BalanceInfo.Monetary.UUID,
+							Units:        incr.Cost,
+							RatingID:     ec.ratingIDForRateInterval(incr.BalanceInfo.Monetary.RateInterval, rf),
+							ExchangeRate: incr.BalanceInfo.Monetary.ExchangeRate,
 						}); uuid != "" {
 						ecUUID = uuid
 					}
@@ -85,10 +93,11 @@ func NewEventCostFromCallCost(cc *CallCost, cgrID, runID string) (ec *EventCost)
 			} else if incr.BalanceInfo.Monetary != nil { // Only monetary
 				cIt.AccountingID = ec.Accounting.GetIDWithSet(
 					&BalanceCharge{
-						AccountID:   incr.BalanceInfo.AccountID,
-						BalanceUUID: incr.BalanceInfo.Monetary.UUID,
-						Units:       incr.Cost,
-						RatingID:    ec.ratingIDForRateInterval(incr.BalanceInfo.Monetary.RateInterval, rf)})
+						AccountID:    incr.BalanceInfo.AccountID,
+						BalanceUUID:  incr.BalanceInfo.Monetary.UUID,
+						Units:        incr.Cost,
+						RatingID:     ec.ratingIDForRateInterval(incr.BalanceInfo.Monetary.RateInterval, rf),
+						ExchangeRate: incr.BalanceInfo.Monetary.ExchangeRate})
 			}
 			cIl.Increments[j] = cIt
 		}
@@ -106,6 +115,8 @@ type EventCost struct {
 	Cost           *float64 // pointer so we can nil it when dirty
 	Charges        []*ChargingInterval
 	AccountSummary *AccountSummary // Account summary at the end of the event calculation
+	Currency       string
+	Taxes          []*TaxCharge // taxes applied on top of Cost
 	Rating         Rating
 	Accounting     Accounting
 	RatingFilters  RatingFilters
@@ -142,6 +153,8 @@ func (ec *EventCost) ratingIDForRateInterval(ri *RateInterval, rf RatingMatchedF
 			RoundingDecimals: ri.Rating.RoundingDecimals,
 			MaxCost:          ri.Rating.MaxCost,
 			MaxCostStrategy:  ri.Rating.MaxCostStrategy,
+			Currency:         ri.Rating.Currency,
+			VolumeCounter:    ri.Rating.VolumeCounter,
 			TimingID:         tmID,
 			RatesID:          rtUUID,
 			RatingFiltersID:  rfUUID})
@@ -156,7 +169,8 @@ func (ec *EventCost) rateIntervalForRatingID(ratingID string) (ri *RateInterval)
 	ri.Rating = &RIRate{ConnectFee: cIlRU.ConnectFee,
 		RoundingMethod:   cIlRU.RoundingMethod,
 		RoundingDecimals: cIlRU.RoundingDecimals,
-		MaxCost:          cIlRU.MaxCost, MaxCostStrategy: cIlRU.MaxCostStrategy}
+		MaxCost:          cIlRU.MaxCost, MaxCostStrategy: cIlRU.MaxCostStrategy,
+		Currency: cIlRU.Currency, VolumeCounter: cIlRU.VolumeCounter}
 	if cIlRU.RatesID != "" {
 		ri.Rating.Rates = ec.Rates[cIlRU.RatesID]
 	}
@@ -188,6 +202,13 @@ func (ec *EventCost) Clone() (cln *EventCost) {
 	if ec.AccountSummary != nil {
 		cln.AccountSummary = ec.AccountSummary.Clone()
 	}
+	cln.Currency = ec.Currency
+	if ec.Taxes != nil {
+		cln.Taxes = make([]*TaxCharge, len(ec.Taxes))
+		for i, tc := range ec.Taxes {
+			cln.Taxes[i] = tc.Clone()
+		}
+	}
 	cln.Rating = ec.Rating.Clone()
 	cln.Accounting = ec.Accounting.Clone()
 	cln.RatingFilters = ec.RatingFilters.Clone()
@@ -253,7 +274,7 @@ func (ec *EventCost) ComputeEventCostUsageIndexes() {
 func (ec *EventCost) AsCallCost() *CallCost {
 	cc := &CallCost{
 		Cost: ec.GetCost(), RatedUsage: float64(ec.GetUsage().Nanoseconds()),
-		AccountSummary: ec.AccountSummary}
+		AccountSummary: ec.AccountSummary, Currency: ec.Currency, Taxes: ec.Taxes}
 	cc.Timespans = make(TimeSpans, len(ec.Charges))
 	for i, cIl := range ec.Charges {
 		ts := &TimeSpan{Cost: cIl.Cost(),
@@ -279,6 +300,9 @@ func (ec *EventCost) AsCallCost() *CallCost {
 		}
 		for j, cInc := range cIl.Increments {
 			incr := &Increment{Duration: cInc.Usage, Cost: cInc.Cost, CompressFactor: cInc.CompressFactor, BalanceInfo: new(DebitInfo)}
+			if ts.RateInterval != nil {
+				incr.BalanceInfo.VolumeCounter = ts.RateInterval.Rating.VolumeCounter
+			}
 			if cInc.AccountingID != "" {
 				cBC := ec.Accounting[cInc.AccountingID]
 				incr.BalanceInfo.AccountID = cBC.AccountID
@@ -306,7 +330,7 @@ func (ec *EventCost) AsCallCost() *CallCost {
 					}
 				}
 				if cBC.ExtraChargeID != utils.META_NONE {
-					incr.BalanceInfo.Monetary = &MonetaryInfo{UUID: cBC.BalanceUUID}
+					incr.BalanceInfo.Monetary = &MonetaryInfo{UUID: cBC.BalanceUUID, ExchangeRate: cBC.ExchangeRate}
 					incr.BalanceInfo.Monetary.RateInterval = ec.rateIntervalForRatingID(cBC.RatingID)
 				}
 			}
@@ -364,6 +388,9 @@ func (ec *EventCost) AppendChargingIntervalFromEventCost(oEC *EventCost, cIlIdx
 func (ec *EventCost) Merge(ecs ...*EventCost) {
 	for _, newEC := range ecs {
 		ec.AccountSummary = newEC.AccountSummary // updated AccountSummary information
+		if ec.Currency == "" {
+			ec.Currency = newEC.Currency
+		}
 		for cIlIdx := range newEC.Charges {
 			ec.AppendChargingIntervalFromEventCost(newEC, cIlIdx)
 		}
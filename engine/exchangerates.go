@@ -0,0 +1,105 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package engine
+
+import (
+	"sort"
+	"time"
+
+	"github.com/cgrates/cgrates/config"
+	"github.com/cgrates/cgrates/utils"
+)
+
+// CurrencyRate is the exchange rate valid starting with ActivationTime
+type CurrencyRate struct {
+	ActivationTime time.Time
+	Rate           float64 // units of ToCurrency for one unit of FromCurrency
+}
+
+// ExchangeRate holds the history of rates used to convert FromCurrency into ToCurrency
+type ExchangeRate struct {
+	FromCurrency string
+	ToCurrency   string
+	Rates        []*CurrencyRate
+}
+
+// ID returns the key of the ExchangeRate
+func (xr *ExchangeRate) ID() string {
+	return utils.ConcatenatedKey(xr.FromCurrency, xr.ToCurrency)
+}
+
+// Sort orders the Rates based on their ActivationTime
+func (xr *ExchangeRate) Sort() {
+	sort.Slice(xr.Rates, func(i, j int) bool {
+		return xr.Rates[i].ActivationTime.Before(xr.Rates[j].ActivationTime)
+	})
+}
+
+// RateAt returns the rate active at the given time, Rates should be sorted
+func (xr *ExchangeRate) RateAt(t time.Time) (rate float64, has bool) {
+	for _, cr := range xr.Rates {
+		if cr.ActivationTime.After(t) {
+			break
+		}
+		rate, has = cr.Rate, true
+	}
+	return
+}
+
+// ratingCurrency returns the currency the rates of ri are expressed in,
+// the default one for the RatingPlans without currency
+func ratingCurrency(ri *RateInterval) string {
+	if ri != nil && ri.Rating != nil && ri.Rating.Currency != "" {
+		return ri.Rating.Currency
+	}
+	return config.CgrConfig().DefaultCurrency
+}
+
+// exchangeRateAt returns the rate converting one unit of from currency into to currency at the given time
+// the inverse table is used if no direct one is defined
+func exchangeRateAt(from, to string, t time.Time) (rate float64, err error) {
+	if from == "" || to == "" || from == to {
+		return 1, nil
+	}
+	var xr *ExchangeRate
+	if xr, err = dm.GetExchangeRate(from, to, false, utils.NonTransactional); err == nil {
+		if rate, has := xr.RateAt(t); has {
+			return rate, nil
+		}
+	} else if err != utils.ErrNotFound {
+		return
+	}
+	if xr, err = dm.GetExchangeRate(to, from, false, utils.NonTransactional); err == nil {
+		if rate, has := xr.RateAt(t); has && rate != 0 {
+			return 1 / rate, nil
+		}
+	} else if err != utils.ErrNotFound {
+		return
+	}
+	return 0, utils.ErrExchangeRateNotFound
+}
+
+// ConvertCurrency converts the amount between currencies using the rate active at the given time
+func ConvertCurrency(amount float64, from, to string, t time.Time) (float64, error) {
+	rate, err := exchangeRateAt(from, to, t)
+	if err != nil {
+		return 0, err
+	}
+	return utils.Round(amount*rate, globalRoundingDecimals, utils.ROUNDING_MIDDLE), nil
+}
@@ -0,0 +1,127 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package engine
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cgrates/cgrates/utils"
+)
+
+func TestExchangeRateRateAt(t *testing.T) {
+	xr := &ExchangeRate{FromCurrency: "EUR", ToCurrency: "USD",
+		Rates: []*CurrencyRate{
+			&CurrencyRate{ActivationTime: time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC), Rate: 1.2},
+			&CurrencyRate{ActivationTime: time.Date(2014, 1, 1, 0, 0, 0, 0, time.UTC), Rate: 1.12},
+		}}
+	xr.Sort()
+	if _, has := xr.RateAt(time.Date(2013, 1, 1, 0, 0, 0, 0, time.UTC)); has {
+		t.Error("Expecting no rate before the first activation time")
+	}
+	if rate, has := xr.RateAt(time.Date(2016, 1, 1, 0, 0, 0, 0, time.UTC)); !has || rate != 1.12 {
+		t.Errorf("Expecting 1.12, received: %v", rate)
+	}
+	if rate, has := xr.RateAt(time.Date(2018, 6, 1, 0, 0, 0, 0, time.UTC)); !has || rate != 1.2 {
+		t.Errorf("Expecting 1.2, received: %v", rate)
+	}
+}
+
+func TestExchangeRateConvertCurrency(t *testing.T) {
+	xr := &ExchangeRate{FromCurrency: "GBP", ToCurrency: "CHF",
+		Rates: []*CurrencyRate{
+			&CurrencyRate{ActivationTime: time.Date(2014, 1, 1, 0, 0, 0, 0, time.UTC), Rate: 2},
+		}}
+	if err := dm.SetExchangeRate(xr); err != nil {
+		t.Fatal(err)
+	}
+	tm := time.Date(2016, 1, 1, 0, 0, 0, 0, time.UTC)
+	if amount, err := ConvertCurrency(10, "GBP", "GBP", tm); err != nil {
+		t.Error(err)
+	} else if amount != 10 {
+		t.Errorf("Expecting 10, received: %v", amount)
+	}
+	if amount, err := ConvertCurrency(10, "GBP", "CHF", tm); err != nil {
+		t.Error(err)
+	} else if amount != 20 {
+		t.Errorf("Expecting 20, received: %v", amount)
+	}
+	if amount, err := ConvertCurrency(10, "CHF", "GBP", tm); err != nil { // inverse table
+		t.Error(err)
+	} else if amount != 5 {
+		t.Errorf("Expecting 5, received: %v", amount)
+	}
+	if _, err := ConvertCurrency(10, "GBP", "CHF",
+		time.Date(2013, 1, 1, 0, 0, 0, 0, time.UTC)); err != utils.ErrExchangeRateNotFound {
+		t.Errorf("Expecting: %v, received: %v", utils.ErrExchangeRateNotFound, err)
+	}
+	if _, err := ConvertCurrency(10, "GBP", "JPY", tm); err != utils.ErrExchangeRateNotFound {
+		t.Errorf("Expecting: %v, received: %v", utils.ErrExchangeRateNotFound, err)
+	}
+}
+
+func TestDebitCreditMoneyExchangeRate(t *testing.T) {
+	xr := &ExchangeRate{FromCurrency: "GBP", ToCurrency: "NOK",
+		Rates: []*CurrencyRate{
+			&CurrencyRate{ActivationTime: time.Date(2013, 1, 1, 0, 0, 0, 0, time.UTC), Rate: 2},
+		}}
+	if err := dm.SetExchangeRate(xr); err != nil {
+		t.Fatal(err)
+	}
+	cc := &CallCost{
+		Direction:   utils.OUT,
+		Destination: "0723045326",
+		Timespans: []*TimeSpan{
+			&TimeSpan{
+				TimeStart:     time.Date(2013, 9, 24, 10, 48, 0, 0, time.UTC),
+				TimeEnd:       time.Date(2013, 9, 24, 10, 48, 20, 0, time.UTC),
+				DurationIndex: 0,
+				ratingInfo:    &RatingInfo{},
+				RateInterval: &RateInterval{Rating: &RIRate{Currency: "GBP",
+					Rates: RateGroups{&Rate{GroupIntervalStart: 0, Value: 1, RateIncrement: 10 * time.Second, RateUnit: time.Second}}}},
+			},
+		},
+		TOR: utils.VOICE,
+	}
+	cd := &CallDescriptor{
+		TimeStart:     cc.Timespans[0].TimeStart,
+		TimeEnd:       cc.Timespans[0].TimeEnd,
+		Direction:     cc.Direction,
+		Destination:   cc.Destination,
+		TOR:           cc.TOR,
+		DurationIndex: cc.GetDuration(),
+		testCallcost:  cc,
+	}
+	acnt := &Account{ID: "cgrates.org:xrate", BalanceMap: map[string]Balances{
+		utils.MONETARY: Balances{&Balance{Uuid: "nok", Value: 100, Currency: "NOK"}},
+	}}
+	cc, err := acnt.debitCreditBalance(cd, false, false, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if acnt.BalanceMap[utils.MONETARY][0].GetValue() != 60 {
+		t.Errorf("Expecting 60, received: %v", acnt.BalanceMap[utils.MONETARY][0].GetValue())
+	}
+	if incCost := cc.Timespans[0].Increments[0].Cost; incCost != 10 {
+		t.Errorf("Expecting increment cost 10 in rating currency, received: %v", incCost)
+	}
+	if xRate := cc.Timespans[0].Increments[0].BalanceInfo.Monetary.ExchangeRate; xRate != 2 {
+		t.Errorf("Expecting exchange rate 2, received: %v", xRate)
+	}
+}
@@ -53,73 +53,47 @@ func (rfi *ReqFilterIndexer) ChangedKeys(reverse bool) utils.StringMap {
 
 // IndexFilters parses reqFltrs, adding itemID in the indexes and marks the changed keys in chngdIndxKeys
 func (rfi *ReqFilterIndexer) IndexFilters(itemID string, reqFltrs *Filter) {
-	var hasMetaString bool
-	if _, hasIt := rfi.reveseIndex[itemID]; !hasIt {
-		rfi.reveseIndex[itemID] = make(map[string]utils.StringMap)
-	}
-	for _, fltr := range reqFltrs.RequestFilters {
-		if fltr.Type != MetaString {
-			continue
-		}
-		hasMetaString = true // Mark that we found at least one metatring so we don't index globally
-		if _, hastIt := rfi.indexes[fltr.FieldName]; !hastIt {
-			rfi.indexes[fltr.FieldName] = make(map[string]utils.StringMap)
-		}
-		if _, hastIt := rfi.reveseIndex[itemID][fltr.FieldName]; !hastIt {
-			rfi.reveseIndex[itemID][fltr.FieldName] = make(utils.StringMap)
-		}
-		for _, fldVal := range fltr.Values {
-			if _, hasIt := rfi.indexes[fltr.FieldName][fldVal]; !hasIt {
-				rfi.indexes[fltr.FieldName][fldVal] = make(utils.StringMap)
-			}
-			rfi.indexes[fltr.FieldName][fldVal][itemID] = true
-			rfi.reveseIndex[itemID][fltr.FieldName][fldVal] = true
-			rfi.chngdIndxKeys[utils.ConcatenatedKey(fltr.FieldName, fldVal)] = true
-		}
-		rfi.chngdRevIndxKeys[utils.ConcatenatedKey(itemID, fltr.FieldName)] = true
-	}
-	if !hasMetaString {
-		if _, hasIt := rfi.indexes[utils.NOT_AVAILABLE]; !hasIt {
-			rfi.indexes[utils.NOT_AVAILABLE] = make(map[string]utils.StringMap)
-		}
-		if _, hastIt := rfi.reveseIndex[itemID][utils.NOT_AVAILABLE]; !hastIt {
-			rfi.reveseIndex[itemID][utils.NOT_AVAILABLE] = make(utils.StringMap)
-		}
-		if _, hasIt := rfi.indexes[utils.NOT_AVAILABLE][utils.NOT_AVAILABLE]; !hasIt {
-			rfi.indexes[utils.NOT_AVAILABLE][utils.NOT_AVAILABLE] = make(utils.StringMap)
-		}
-		rfi.reveseIndex[itemID][utils.NOT_AVAILABLE][utils.NOT_AVAILABLE] = true
-		rfi.indexes[utils.NOT_AVAILABLE][utils.NOT_AVAILABLE][itemID] = true // Fields without real field index will be located in map[NOT_AVAILABLE][NOT_AVAILABLE][rl.ID]
-	}
-	return
+	rfi.indexRequestFilters(itemID, reqFltrs.RequestFilters)
 }
 
 // IndexTPFilter parses reqFltrs, adding itemID in the indexes and marks the changed keys in chngdIndxKeys
 func (rfi *ReqFilterIndexer) IndexTPFilter(tpFltr *utils.TPFilterProfile, itemID string) {
+	rfs := make([]*RequestFilter, len(tpFltr.Filters))
+	for i, fltr := range tpFltr.Filters {
+		rfs[i] = &RequestFilter{Type: fltr.Type, FieldName: fltr.FieldName, Values: fltr.Values}
+		if fltr.Type == MetaOr {
+			rfs[i].CompileValues() // on errors the filter will not be indexed
+		}
+	}
+	rfi.indexRequestFilters(itemID, rfs)
+}
+
+// indexRequestFilters indexes the *string rules within rfs, including the *or groups made only of *string rules
+// the items without such rules are indexed under NOT_AVAILABLE so they are always checked
+func (rfi *ReqFilterIndexer) indexRequestFilters(itemID string, rfs []*RequestFilter) {
 	var hasMetaString bool
 	if _, hasIt := rfi.reveseIndex[itemID]; !hasIt {
 		rfi.reveseIndex[itemID] = make(map[string]utils.StringMap)
 	}
-	for _, fltr := range tpFltr.Filters {
-		if fltr.Type != MetaString {
-			continue
-		}
-		hasMetaString = true // Mark that we found at least one metatring so we don't index globally
-		if _, hastIt := rfi.indexes[fltr.FieldName]; !hastIt {
-			rfi.indexes[fltr.FieldName] = make(map[string]utils.StringMap)
-		}
-		if _, hastIt := rfi.reveseIndex[itemID][fltr.FieldName]; !hastIt {
-			rfi.reveseIndex[itemID][fltr.FieldName] = make(utils.StringMap)
-		}
-		for _, fldVal := range fltr.Values {
-			if _, hasIt := rfi.indexes[fltr.FieldName][fldVal]; !hasIt {
-				rfi.indexes[fltr.FieldName][fldVal] = make(utils.StringMap)
+	for _, rf := range rfs {
+		for _, fltr := range rf.indexableFilters() {
+			hasMetaString = true // Mark that we found at least one metatring so we don't index globally
+			if _, hastIt := rfi.indexes[fltr.FieldName]; !hastIt {
+				rfi.indexes[fltr.FieldName] = make(map[string]utils.StringMap)
+			}
+			if _, hastIt := rfi.reveseIndex[itemID][fltr.FieldName]; !hastIt {
+				rfi.reveseIndex[itemID][fltr.FieldName] = make(utils.StringMap)
+			}
+			for _, fldVal := range fltr.Values {
+				if _, hasIt := rfi.indexes[fltr.FieldName][fldVal]; !hasIt {
+					rfi.indexes[fltr.FieldName][fldVal] = make(utils.StringMap)
+				}
+				rfi.indexes[fltr.FieldName][fldVal][itemID] = true
+				rfi.reveseIndex[itemID][fltr.FieldName][fldVal] = true
+				rfi.chngdIndxKeys[utils.ConcatenatedKey(fltr.FieldName, fldVal)] = true
 			}
-			rfi.indexes[fltr.FieldName][fldVal][itemID] = true
-			rfi.reveseIndex[itemID][fltr.FieldName][fldVal] = true
-			rfi.chngdIndxKeys[utils.ConcatenatedKey(fltr.FieldName, fldVal)] = true
+			rfi.chngdRevIndxKeys[utils.ConcatenatedKey(itemID, fltr.FieldName)] = true
 		}
-		rfi.chngdRevIndxKeys[utils.ConcatenatedKey(itemID, fltr.FieldName)] = true
 	}
 	if !hasMetaString {
 		if _, hasIt := rfi.indexes[utils.NOT_AVAILABLE]; !hasIt {
@@ -236,6 +210,9 @@ func GetDBIndexKey(itemType, dbKeySuffix string, reverse bool) (dbKey string) {
 	case utils.AttributeProfilePrefix:
 		idxPrefix = utils.AttributeProfilesStringIndex
 		rIdxPrefix = utils.AttributeProfilesStringRevIndex
+	case utils.TaxProfilePrefix:
+		idxPrefix = utils.TaxProfilesStringIndex
+		rIdxPrefix = utils.TaxProfilesStringRevIndex
 	}
 	if reverse {
 		return rIdxPrefix + dbKeySuffix
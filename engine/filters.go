@@ -18,7 +18,9 @@ package engine
 import (
 	"errors"
 	"fmt"
+	"net"
 	"reflect"
+	"regexp"
 	"strconv"
 	"strings"
 	"sync"
@@ -42,8 +44,22 @@ const (
 	MetaLessOrEqual    = "*lte"
 	MetaGreaterThan    = "*gt"
 	MetaGreaterOrEqual = "*gte"
+	MetaSuffix         = "*suffix"
+	MetaRegex          = "*regex"
+	MetaExists         = "*exists"
+	MetaEmpty          = "*empty"
+	MetaRange          = "*range"
+	MetaCIDR           = "*cidr"
+	MetaOr             = "*or"
+	MetaRangeSep       = ".."    // separates the bounds of a *range value, ie: 10s..1m
+	MetaNot            = "*not_" // prefix negating the filter type, ie: *not_string
 )
 
+// supportedFilterTypes lists the filter types, each can be negated with MetaNot prefix
+var supportedFilterTypes = []string{MetaString, MetaStringPrefix, MetaTimings, MetaRSRFields, MetaStatS,
+	MetaDestinations, MetaLessThan, MetaLessOrEqual, MetaGreaterThan, MetaGreaterOrEqual,
+	MetaSuffix, MetaRegex, MetaExists, MetaEmpty, MetaRange, MetaCIDR, MetaOr}
+
 func NewFilterS(cfg *config.CGRConfig, statSChan chan rpcclient.RpcClientConnection, dm *DataManager) *FilterS {
 	return &FilterS{statSChan: statSChan, dm: dm}
 }
@@ -84,28 +100,17 @@ func (fS *FilterS) PassFiltersForEvent(tenant string, ev map[string]interface{},
 			continue
 		}
 		for _, fltr := range f.RequestFilters {
-			switch fltr.Type {
-			case MetaString:
-				pass, err = fltr.passString(ev, "")
-			case MetaStringPrefix:
-				pass, err = fltr.passStringPrefix(ev, "")
-			case MetaTimings:
-				pass, err = fltr.passTimings(ev, "")
-			case MetaDestinations:
-				pass, err = fltr.passDestinations(ev, "")
-			case MetaRSRFields:
-				pass, err = fltr.passRSRFields(ev, "")
-			case MetaStatS:
+			if rfType, _ := fltr.baseType(); !utils.IsSliceMember(supportedFilterTypes, rfType) {
+				return false, fmt.Errorf("tenant: %s filter: %s unsupported filter type: <%s>", tenant, fltrID, fltr.Type)
+			}
+			var statS rpcclient.RpcClientConnection
+			if fltr.requiresStatS() {
 				if err = fS.connStatS(); err != nil {
 					return false, err
 				}
-				pass, err = fltr.passStatS(ev, "", fS.statSConns)
-			case MetaLessThan, MetaLessOrEqual, MetaGreaterThan, MetaGreaterOrEqual:
-				pass, err = fltr.passGreaterThan(ev, "")
-			default:
-				return false, fmt.Errorf("tenant: %s filter: %s unsupported filter type: <%s>", tenant, fltrID, fltr.Type)
+				statS = fS.statSConns
 			}
-			if !pass || err != nil {
+			if pass, err = fltr.Pass(ev, "", statS); !pass || err != nil {
 				return pass, err
 			}
 		}
@@ -136,19 +141,21 @@ func (f *Filter) Compile() (err error) {
 }
 
 func NewRequestFilter(rfType, fieldName string, vals []string) (*RequestFilter, error) {
-	if !utils.IsSliceMember([]string{MetaString, MetaStringPrefix, MetaTimings, MetaRSRFields, MetaStatS, MetaDestinations,
-		MetaLessThan, MetaLessOrEqual, MetaGreaterThan, MetaGreaterOrEqual}, rfType) {
+	rf := &RequestFilter{Type: rfType, FieldName: fieldName, Values: vals}
+	baseType, _ := rf.baseType()
+	if !utils.IsSliceMember(supportedFilterTypes, baseType) {
 		return nil, fmt.Errorf("Unsupported filter Type: %s", rfType)
 	}
 	if fieldName == "" && utils.IsSliceMember([]string{MetaString, MetaStringPrefix, MetaTimings, MetaDestinations,
-		MetaLessThan, MetaLessOrEqual, MetaGreaterThan, MetaGreaterOrEqual}, rfType) {
+		MetaLessThan, MetaLessOrEqual, MetaGreaterThan, MetaGreaterOrEqual,
+		MetaSuffix, MetaRegex, MetaExists, MetaEmpty, MetaRange, MetaCIDR}, baseType) {
 		return nil, fmt.Errorf("FieldName is mandatory for Type: %s", rfType)
 	}
 	if len(vals) == 0 && utils.IsSliceMember([]string{MetaString, MetaStringPrefix, MetaTimings, MetaRSRFields,
-		MetaDestinations, MetaDestinations, MetaLessThan, MetaLessOrEqual, MetaGreaterThan, MetaGreaterOrEqual}, rfType) {
+		MetaDestinations, MetaDestinations, MetaLessThan, MetaLessOrEqual, MetaGreaterThan, MetaGreaterOrEqual,
+		MetaSuffix, MetaRegex, MetaRange, MetaCIDR, MetaOr}, baseType) {
 		return nil, fmt.Errorf("Values is mandatory for Type: %s", rfType)
 	}
-	rf := &RequestFilter{Type: rfType, FieldName: fieldName, Values: vals}
 	if err := rf.CompileValues(); err != nil {
 		return nil, err
 	}
@@ -161,23 +168,158 @@ type RFStatSThreshold struct {
 	ThresholdValue float64
 }
 
+// RFRange is the compiled *range value, missing bounds are nil
+// bounds are float64, time.Duration or time.Time
+type RFRange struct {
+	MinValue interface{}
+	MaxValue interface{}
+}
+
+// parseRangeBound detects the type of the bound out of it's string value
+func parseRangeBound(bound string) (interface{}, error) {
+	if bound == "" {
+		return nil, nil
+	}
+	if f, err := strconv.ParseFloat(bound, 64); err == nil {
+		return f, nil
+	}
+	if d, err := time.ParseDuration(bound); err == nil {
+		return d, nil
+	}
+	if t, err := utils.ParseTimeDetectLayout(bound, "Local"); err == nil {
+		return t, nil
+	}
+	return nil, fmt.Errorf("unsupported range bound <%s>", bound)
+}
+
+// NewRFRange compiles a range out of it's value in the format Min..Max, one of the bounds can be missing
+func NewRFRange(val string) (rng *RFRange, err error) {
+	valSplt := strings.Split(val, MetaRangeSep)
+	if len(valSplt) != 2 {
+		return nil, fmt.Errorf("Value %s needs to contain 2 bounds separated by %s", val, MetaRangeSep)
+	}
+	rng = new(RFRange)
+	if rng.MinValue, err = parseRangeBound(valSplt[0]); err != nil {
+		return nil, err
+	}
+	if rng.MaxValue, err = parseRangeBound(valSplt[1]); err != nil {
+		return nil, err
+	}
+	if rng.MinValue == nil && rng.MaxValue == nil {
+		return nil, fmt.Errorf("Value %s needs at least one bound", val)
+	}
+	if rng.MinValue != nil && rng.MaxValue != nil &&
+		reflect.TypeOf(rng.MinValue) != reflect.TypeOf(rng.MaxValue) {
+		return nil, fmt.Errorf("Value %s contains bounds of different types", val)
+	}
+	return
+}
+
+// Contains checks if the field value is within range: MinValue <= fldVal < MaxValue
+func (rng *RFRange) Contains(fldVal interface{}) (bool, error) {
+	bound := rng.MinValue
+	if bound == nil {
+		bound = rng.MaxValue
+	}
+	if _, isTime := bound.(time.Time); isTime {
+		var tm time.Time
+		switch fld := fldVal.(type) {
+		case time.Time:
+			tm = fld
+		case string:
+			var err error
+			if tm, err = utils.ParseTimeDetectLayout(fld, "Local"); err != nil {
+				return false, err
+			}
+		default:
+			return false, fmt.Errorf("cannot compare <%v> with time", fldVal)
+		}
+		return (rng.MinValue == nil || !tm.Before(rng.MinValue.(time.Time))) &&
+			(rng.MaxValue == nil || tm.Before(rng.MaxValue.(time.Time))), nil
+	}
+	f, err := utils.IfaceAsFloat64(fldVal) // durations are compared as nanoseconds
+	if err != nil {
+		return false, err
+	}
+	if rng.MinValue != nil {
+		if minVal, _ := utils.IfaceAsFloat64(rng.MinValue); f < minVal {
+			return false, nil
+		}
+	}
+	if rng.MaxValue != nil {
+		if maxVal, _ := utils.IfaceAsFloat64(rng.MaxValue); f >= maxVal {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
 // RequestFilter filters requests coming into various places
 // Pass rule: default negative, one mathing rule should pass the filter
+// The *not_ prefixed types are passing when the underlying type is not
+// The *or type will pass if one of the rules in Values passes, each of them in the format Type:FieldName:Value
 type RequestFilter struct {
-	Type            string              // Filter type (*string, *timing, *rsr_filters, *stats, *lt, *lte, *gt, *gte)
+	Type            string              // Filter type (*string, *timing, *rsr_filters, *stats, *lt, *lte, *gt, *gte, *suffix, *regex, *exists, *empty, *range, *cidr, *or)
 	FieldName       string              // Name of the field providing us the Values to check (used in case of some )
 	Values          []string            // Filter definition
 	rsrFields       utils.RSRFields     // Cache here the RSRFilter Values
 	statSThresholds []*RFStatSThreshold // Cached compiled RFStatsThreshold out of Values
+	orFilters       []*RequestFilter    // Cached compiled rules of the *or type
+	regexps         []*regexp.Regexp    // Cached compiled *regex Values
+	ranges          []*RFRange          // Cached compiled *range Values
+	ipNets          []*net.IPNet        // Cached compiled *cidr Values
+}
+
+// baseType returns the filter type without the negation prefix and whether the filter is negated
+func (rf *RequestFilter) baseType() (rfType string, negative bool) {
+	if strings.HasPrefix(rf.Type, MetaNot) {
+		return utils.MetaPrefix + rf.Type[len(MetaNot):], true
+	}
+	return rf.Type, false
+}
+
+// requiresStatS checks if the filter needs a connection towards StatS to pass
+func (rf *RequestFilter) requiresStatS() bool {
+	rfType, _ := rf.baseType()
+	if rfType == MetaStatS {
+		return true
+	}
+	for _, orFltr := range rf.orFilters {
+		if orFltr.requiresStatS() {
+			return true
+		}
+	}
+	return false
+}
+
+// indexableFilters returns the *string filters which can be indexed out of this one
+// ie: an *or containing only *string rules can be indexed since one of them needs to match
+func (rf *RequestFilter) indexableFilters() []*RequestFilter {
+	switch rf.Type {
+	case MetaString:
+		return []*RequestFilter{rf}
+	case MetaOr:
+		if len(rf.orFilters) == 0 {
+			return nil
+		}
+		for _, orFltr := range rf.orFilters {
+			if orFltr.Type != MetaString {
+				return nil
+			}
+		}
+		return rf.orFilters
+	}
+	return nil
 }
 
 // Separate method to compile RSR fields
 func (rf *RequestFilter) CompileValues() (err error) {
-	if rf.Type == MetaRSRFields {
+	rfType, _ := rf.baseType()
+	if rfType == MetaRSRFields {
 		if rf.rsrFields, err = utils.ParseRSRFieldsFromSlice(rf.Values); err != nil {
 			return
 		}
-	} else if rf.Type == MetaStatS {
+	} else if rfType == MetaStatS {
 		rf.statSThresholds = make([]*RFStatSThreshold, len(rf.Values))
 		for i, val := range rf.Values {
 			valSplt := strings.Split(val, utils.InInFieldSep)
@@ -197,30 +339,96 @@ func (rf *RequestFilter) CompileValues() (err error) {
 			}
 			rf.statSThresholds[i] = st
 		}
+	} else if rfType == MetaRegex {
+		rf.regexps = make([]*regexp.Regexp, len(rf.Values))
+		for i, val := range rf.Values {
+			if rf.regexps[i], err = regexp.Compile(val); err != nil {
+				return
+			}
+		}
+	} else if rfType == MetaRange {
+		rf.ranges = make([]*RFRange, len(rf.Values))
+		for i, val := range rf.Values {
+			if rf.ranges[i], err = NewRFRange(val); err != nil {
+				return
+			}
+		}
+	} else if rfType == MetaCIDR {
+		rf.ipNets = make([]*net.IPNet, len(rf.Values))
+		for i, val := range rf.Values {
+			if _, rf.ipNets[i], err = net.ParseCIDR(val); err != nil {
+				return
+			}
+		}
+	} else if rfType == MetaOr {
+		rf.orFilters = make([]*RequestFilter, len(rf.Values))
+		for i, val := range rf.Values {
+			valSplt := strings.SplitN(val, utils.InInFieldSep, 3)
+			if len(valSplt) != 3 {
+				return fmt.Errorf("Value %s needs to contain at least 3 items", val)
+			}
+			if orType, _ := (&RequestFilter{Type: valSplt[0]}).baseType(); orType == MetaOr {
+				return fmt.Errorf("Value %s contains nested %s", val, MetaOr)
+			}
+			if rf.orFilters[i], err = NewRequestFilter(valSplt[0], valSplt[1], []string{valSplt[2]}); err != nil {
+				return
+			}
+		}
 	}
 	return
 }
 
 // Pass is the method which should be used from outside.
-func (fltr *RequestFilter) Pass(req interface{}, extraFieldsLabel string, rpcClnt rpcclient.RpcClientConnection) (bool, error) {
-	switch fltr.Type {
+func (fltr *RequestFilter) Pass(req interface{}, extraFieldsLabel string, rpcClnt rpcclient.RpcClientConnection) (pass bool, err error) {
+	rfType, negative := fltr.baseType()
+	switch rfType {
 	case MetaString:
-		return fltr.passString(req, extraFieldsLabel)
+		pass, err = fltr.passString(req, extraFieldsLabel)
 	case MetaStringPrefix:
-		return fltr.passStringPrefix(req, extraFieldsLabel)
+		pass, err = fltr.passStringPrefix(req, extraFieldsLabel)
 	case MetaTimings:
-		return fltr.passTimings(req, extraFieldsLabel)
+		pass, err = fltr.passTimings(req, extraFieldsLabel)
 	case MetaDestinations:
-		return fltr.passDestinations(req, extraFieldsLabel)
+		pass, err = fltr.passDestinations(req, extraFieldsLabel)
 	case MetaRSRFields:
-		return fltr.passRSRFields(req, extraFieldsLabel)
+		pass, err = fltr.passRSRFields(req, extraFieldsLabel)
 	case MetaStatS:
-		return fltr.passStatS(req, extraFieldsLabel, rpcClnt)
+		pass, err = fltr.passStatS(req, extraFieldsLabel, rpcClnt)
 	case MetaLessThan, MetaLessOrEqual, MetaGreaterThan, MetaGreaterOrEqual:
-		return fltr.passGreaterThan(req, extraFieldsLabel)
+		pass, err = fltr.passGreaterThan(req, extraFieldsLabel)
+	case MetaSuffix:
+		pass, err = fltr.passSuffix(req, extraFieldsLabel)
+	case MetaRegex:
+		pass, err = fltr.passRegex(req, extraFieldsLabel)
+	case MetaExists:
+		pass, err = fltr.passExists(req, extraFieldsLabel)
+	case MetaEmpty:
+		pass, err = fltr.passEmpty(req, extraFieldsLabel)
+	case MetaRange:
+		pass, err = fltr.passRange(req, extraFieldsLabel)
+	case MetaCIDR:
+		pass, err = fltr.passCIDR(req, extraFieldsLabel)
+	case MetaOr:
+		pass, err = fltr.passOr(req, extraFieldsLabel, rpcClnt)
 	default:
 		return false, utils.ErrNotImplemented
 	}
+	if err != nil {
+		return false, err
+	}
+	return pass != negative, nil
+}
+
+// passOr will pass if at least one of the compiled rules passes
+func (fltr *RequestFilter) passOr(req interface{}, extraFieldsLabel string, rpcClnt rpcclient.RpcClientConnection) (bool, error) {
+	for _, orFltr := range fltr.orFilters {
+		if pass, err := orFltr.Pass(req, extraFieldsLabel, rpcClnt); err != nil {
+			return false, err
+		} else if pass {
+			return true, nil
+		}
+	}
+	return false, nil
 }
 
 func (fltr *RequestFilter) passString(req interface{}, extraFieldsLabel string) (bool, error) {
@@ -255,6 +463,109 @@ func (fltr *RequestFilter) passStringPrefix(req interface{}, extraFieldsLabel st
 	return false, nil
 }
 
+func (fltr *RequestFilter) passSuffix(req interface{}, extraFieldsLabel string) (bool, error) {
+	strVal, err := utils.ReflectFieldAsString(req, fltr.FieldName, extraFieldsLabel)
+	if err != nil {
+		if err == utils.ErrNotFound {
+			return false, nil
+		}
+		return false, err
+	}
+	for _, sfx := range fltr.Values {
+		if strings.HasSuffix(strVal, sfx) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (fltr *RequestFilter) passRegex(req interface{}, extraFieldsLabel string) (bool, error) {
+	strVal, err := utils.ReflectFieldAsString(req, fltr.FieldName, extraFieldsLabel)
+	if err != nil {
+		if err == utils.ErrNotFound {
+			return false, nil
+		}
+		return false, err
+	}
+	for _, re := range fltr.regexps {
+		if re.MatchString(strVal) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// passExists will pass if the field is present in the request, independent of it's value
+func (fltr *RequestFilter) passExists(req interface{}, extraFieldsLabel string) (bool, error) {
+	if _, err := utils.ReflectFieldInterface(req, fltr.FieldName, extraFieldsLabel); err != nil {
+		if err == utils.ErrNotFound {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// passEmpty will pass if the field is missing or it's value is empty
+func (fltr *RequestFilter) passEmpty(req interface{}, extraFieldsLabel string) (bool, error) {
+	fldIf, err := utils.ReflectFieldInterface(req, fltr.FieldName, extraFieldsLabel)
+	if err != nil {
+		if err == utils.ErrNotFound {
+			return true, nil
+		}
+		return false, err
+	}
+	if fldIf == nil {
+		return true, nil
+	}
+	fldVal := reflect.ValueOf(fldIf)
+	switch fldVal.Kind() {
+	case reflect.String, reflect.Slice, reflect.Map, reflect.Array:
+		return fldVal.Len() == 0, nil
+	case reflect.Ptr, reflect.Interface:
+		return fldVal.IsNil(), nil
+	}
+	return false, nil
+}
+
+func (fltr *RequestFilter) passRange(req interface{}, extraFieldsLabel string) (bool, error) {
+	fldIf, err := utils.ReflectFieldInterface(req, fltr.FieldName, extraFieldsLabel)
+	if err != nil {
+		if err == utils.ErrNotFound {
+			return false, nil
+		}
+		return false, err
+	}
+	for _, rng := range fltr.ranges {
+		if inRange, err := rng.Contains(fldIf); err != nil {
+			return false, err
+		} else if inRange {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (fltr *RequestFilter) passCIDR(req interface{}, extraFieldsLabel string) (bool, error) {
+	strVal, err := utils.ReflectFieldAsString(req, fltr.FieldName, extraFieldsLabel)
+	if err != nil {
+		if err == utils.ErrNotFound {
+			return false, nil
+		}
+		return false, err
+	}
+	ip := net.ParseIP(strVal)
+	if ip == nil { // not an IP address, cannot be part of the network
+		return false, nil
+	}
+	for _, ipNet := range fltr.ipNets {
+		if ipNet.Contains(ip) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
 // ToDo when Timings will be available in DataDb
 func (fltr *RequestFilter) passTimings(req interface{}, extraFieldsLabel string) (bool, error) {
 	return false, utils.ErrNotImplemented
@@ -331,17 +642,18 @@ func (fltr *RequestFilter) passGreaterThan(req interface{}, extraFieldsLabel str
 	if fldStr, castStr := fldIf.(string); castStr { // attempt converting string since deserialization fails here (ie: time.Time fields)
 		fldIf = utils.StringToInterface(fldStr)
 	}
+	rfType, _ := fltr.baseType()
 	for _, val := range fltr.Values {
 		orEqual := false
-		if fltr.Type == MetaGreaterOrEqual ||
-			fltr.Type == MetaLessThan {
+		if rfType == MetaGreaterOrEqual ||
+			rfType == MetaLessThan {
 			orEqual = true
 		}
 		if gte, err := utils.GreaterThan(fldIf, utils.StringToInterface(val), orEqual); err != nil {
 			return false, err
-		} else if utils.IsSliceMember([]string{MetaGreaterThan, MetaGreaterOrEqual}, fltr.Type) && gte {
+		} else if utils.IsSliceMember([]string{MetaGreaterThan, MetaGreaterOrEqual}, rfType) && gte {
 			return true, nil
-		} else if !gte && utils.IsSliceMember([]string{MetaLessThan, MetaLessOrEqual}, fltr.Type) && !gte {
+		} else if !gte && utils.IsSliceMember([]string{MetaLessThan, MetaLessOrEqual}, rfType) && !gte {
 			return true, nil
 		}
 	}
@@ -268,3 +268,248 @@ func TestReqFilterNewRequestFilter(t *testing.T) {
 		t.Errorf("Expecting: %+v, received: %+v", erf, rf)
 	}
 }
+
+func TestReqFilterPassNot(t *testing.T) {
+	ev := map[string]interface{}{
+		utils.Account:     "1001",
+		utils.Destination: "+4986517174963",
+		"ASR":             35,
+	}
+	rf, err := NewRequestFilter(MetaNot+"string", utils.Account, []string{"1002"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if passes, err := rf.Pass(ev, "", nil); err != nil {
+		t.Error(err)
+	} else if !passes {
+		t.Error("Not passing")
+	}
+	rf, err = NewRequestFilter(MetaNot+"string_prefix", utils.Destination, []string{"+49"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if passes, err := rf.Pass(ev, "", nil); err != nil {
+		t.Error(err)
+	} else if passes {
+		t.Error("Passing")
+	}
+	rf, err = NewRequestFilter(MetaNot+"gte", "ASR", []string{"40"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if passes, err := rf.Pass(ev, "", nil); err != nil {
+		t.Error(err)
+	} else if !passes {
+		t.Error("Not passing")
+	}
+	if _, err := NewRequestFilter(MetaNot+"unsupported", utils.Account, []string{"1001"}); err == nil {
+		t.Error("Expecting error for unsupported type")
+	}
+}
+
+func TestReqFilterPassOr(t *testing.T) {
+	cache.Set(utils.REVERSE_DESTINATION_PREFIX+"+4930", []string{"DE_BERLIN"}, true, "")
+	if _, err := NewRequestFilter(MetaOr, "", []string{"*string:Account"}); err == nil {
+		t.Error("Expecting error for invalid rule")
+	}
+	if _, err := NewRequestFilter(MetaOr, "", []string{"*or:Account:1001"}); err == nil {
+		t.Error("Expecting error for nested *or")
+	}
+	// destination DE_BERLIN or account 1001, but not prefix +49301
+	rfOr, err := NewRequestFilter(MetaOr, "", []string{"*destinations:Destination:DE_BERLIN", "*string:Account:1001"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rfNot, err := NewRequestFilter(MetaNot+"string_prefix", utils.Destination, []string{"+49301"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tc := range []struct {
+		ev    map[string]interface{}
+		ePass bool
+	}{
+		{map[string]interface{}{utils.Account: "1002", utils.Destination: "+49302"}, true},
+		{map[string]interface{}{utils.Account: "1001", utils.Destination: "+40"}, true},
+		{map[string]interface{}{utils.Account: "1002", utils.Destination: "+40"}, false},
+		{map[string]interface{}{utils.Account: "1001", utils.Destination: "+493011"}, false},
+	} {
+		pass := true
+		for _, rf := range []*RequestFilter{rfOr, rfNot} {
+			if passes, err := rf.Pass(tc.ev, "", nil); err != nil {
+				t.Error(err)
+			} else if !passes {
+				pass = false
+			}
+		}
+		if pass != tc.ePass {
+			t.Errorf("event: %+v, expecting pass: %v", tc.ev, tc.ePass)
+		}
+	}
+	rfNotOr, err := NewRequestFilter(MetaNot+"or", "", []string{"*string:Account:1001", "*string:Account:1002"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if passes, err := rfNotOr.Pass(map[string]interface{}{utils.Account: "1003"}, "", nil); err != nil {
+		t.Error(err)
+	} else if !passes {
+		t.Error("Not passing")
+	}
+}
+
+func TestReqFilterIndexOrFilters(t *testing.T) {
+	rfi := NewReqFilterIndexer(dm, utils.ThresholdProfilePrefix, "cgrates.org")
+	rfOrStr, _ := NewRequestFilter(MetaOr, "", []string{"*string:Account:1001", "*string:Subject:1002"})
+	rfi.IndexFilters("TH1", &Filter{Tenant: "cgrates.org", ID: "FLTR_1",
+		RequestFilters: []*RequestFilter{rfOrStr}})
+	rfOrMixed, _ := NewRequestFilter(MetaOr, "", []string{"*string:Account:1003", "*string_prefix:Destination:+49"})
+	rfNotStr, _ := NewRequestFilter(MetaNot+"string", utils.Account, []string{"1004"})
+	rfi.IndexFilters("TH2", &Filter{Tenant: "cgrates.org", ID: "FLTR_2",
+		RequestFilters: []*RequestFilter{rfOrMixed, rfNotStr}})
+	eIdxes := map[string]map[string]utils.StringMap{
+		utils.Account: {
+			"1001": utils.StringMap{"TH1": true},
+		},
+		"Subject": {
+			"1002": utils.StringMap{"TH1": true},
+		},
+		utils.NOT_AVAILABLE: {
+			utils.NOT_AVAILABLE: utils.StringMap{"TH2": true},
+		},
+	}
+	if !reflect.DeepEqual(eIdxes, rfi.indexes) {
+		t.Errorf("Expecting: %+v, received: %+v", eIdxes, rfi.indexes)
+	}
+}
+
+func TestReqFilterPassSuffixRegex(t *testing.T) {
+	ev := map[string]interface{}{
+		"APN":           "internet.operator.net",
+		utils.Account:   "1001",
+		utils.SUBJECT:   "",
+		"FramedAddress": "10.10.1.5",
+	}
+	rf, err := NewRequestFilter(MetaSuffix, "APN", []string{".operator.com", ".operator.net"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if passes, err := rf.Pass(ev, "", nil); err != nil {
+		t.Error(err)
+	} else if !passes {
+		t.Error("Not passing")
+	}
+	if _, err := NewRequestFilter(MetaRegex, "APN", []string{"^internet(.*"}); err == nil {
+		t.Error("Expecting error on invalid regexp")
+	}
+	rf, err = NewRequestFilter(MetaRegex, utils.Account, []string{"^10[0-9]{2}$"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rf.regexps) != 1 {
+		t.Errorf("Regexps not compiled: %+v", rf.regexps)
+	}
+	if passes, err := rf.Pass(ev, "", nil); err != nil {
+		t.Error(err)
+	} else if !passes {
+		t.Error("Not passing")
+	}
+	rf, err = NewRequestFilter(MetaRegex, "APN", []string{"^ims\\."})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if passes, err := rf.Pass(ev, "", nil); err != nil {
+		t.Error(err)
+	} else if passes {
+		t.Error("Passing")
+	}
+}
+
+func TestReqFilterPassExistsEmpty(t *testing.T) {
+	ev := map[string]interface{}{
+		utils.Account: "1001",
+		utils.SUBJECT: "",
+	}
+	for _, tc := range []struct {
+		rfType, fldName string
+		ePass           bool
+	}{
+		{MetaExists, utils.Account, true},
+		{MetaExists, utils.SUBJECT, true},
+		{MetaExists, utils.Destination, false},
+		{MetaEmpty, utils.Account, false},
+		{MetaEmpty, utils.SUBJECT, true},
+		{MetaEmpty, utils.Destination, true},
+		{MetaNot + "empty", utils.Account, true},
+	} {
+		rf, err := NewRequestFilter(tc.rfType, tc.fldName, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if passes, err := rf.Pass(ev, "", nil); err != nil {
+			t.Error(err)
+		} else if passes != tc.ePass {
+			t.Errorf("filter: %s on %s, expecting: %v", tc.rfType, tc.fldName, tc.ePass)
+		}
+	}
+	if _, err := NewRequestFilter(MetaExists, "", nil); err == nil {
+		t.Error("Expecting error on missing FieldName")
+	}
+}
+
+func TestReqFilterPassRange(t *testing.T) {
+	for _, val := range []string{"10", "..", "10..20..30", "10..1m", "10..abc"} {
+		if _, err := NewRequestFilter(MetaRange, utils.Usage, []string{val}); err == nil {
+			t.Errorf("Expecting error on value: %s", val)
+		}
+	}
+	ev := map[string]interface{}{
+		utils.Usage:     "45s",
+		utils.Cost:      1.5,
+		utils.SetupTime: "2018-01-07T17:00:10Z",
+	}
+	for _, tc := range []struct {
+		fldName string
+		vals    []string
+		ePass   bool
+	}{
+		{utils.Usage, []string{"30s..1m"}, true},
+		{utils.Usage, []string{"..45s"}, false},
+		{utils.Usage, []string{"..10s", "45s.."}, true},
+		{utils.Cost, []string{"1..2"}, true},
+		{utils.Cost, []string{"-1..1.5"}, false},
+		{utils.SetupTime, []string{"2018-01-01T00:00:00Z..2018-02-01T00:00:00Z"}, true},
+		{utils.SetupTime, []string{"2018-02-01T00:00:00Z.."}, false},
+		{utils.Destination, []string{"1..2"}, false},
+	} {
+		rf, err := NewRequestFilter(MetaRange, tc.fldName, tc.vals)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if passes, err := rf.Pass(ev, "", nil); err != nil {
+			t.Error(err)
+		} else if passes != tc.ePass {
+			t.Errorf("field: %s, range: %v, expecting: %v", tc.fldName, tc.vals, tc.ePass)
+		}
+	}
+}
+
+func TestReqFilterPassCIDR(t *testing.T) {
+	if _, err := NewRequestFilter(MetaCIDR, "FramedAddress", []string{"10.0.0.0"}); err == nil {
+		t.Error("Expecting error on invalid network")
+	}
+	rf, err := NewRequestFilter(MetaCIDR, "FramedAddress", []string{"10.10.0.0/16", "2001:db8::/32"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for addr, ePass := range map[string]bool{
+		"10.10.1.5":   true,
+		"10.11.1.5":   false,
+		"2001:db8::1": true,
+		"unknown":     false,
+	} {
+		if passes, err := rf.Pass(map[string]interface{}{"FramedAddress": addr}, "", nil); err != nil {
+			t.Error(err)
+		} else if passes != ePass {
+			t.Errorf("address: %s, expecting: %v", addr, ePass)
+		}
+	}
+}
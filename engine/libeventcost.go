@@ -159,6 +159,7 @@ type BalanceCharge struct {
 	RatingID      string  // special price applied on this balance
 	Units         float64 // number of units charged
 	ExtraChargeID string  // used in cases when paying *voice with *monetary
+	ExchangeRate  float64 // rate applied when the balance currency differs from the rating one
 }
 
 func (bc *BalanceCharge) Equals(oBC *BalanceCharge) bool {
@@ -166,7 +167,8 @@ func (bc *BalanceCharge) Equals(oBC *BalanceCharge) bool {
 		bc.BalanceUUID == oBC.BalanceUUID &&
 		bc.RatingID == oBC.RatingID &&
 		bc.Units == oBC.Units &&
-		bc.ExtraChargeID == oBC.ExtraChargeID
+		bc.ExtraChargeID == oBC.ExtraChargeID &&
+		bc.ExchangeRate == oBC.ExchangeRate
 }
 
 func (bc *BalanceCharge) Clone() *BalanceCharge {
@@ -224,6 +226,8 @@ type RatingUnit struct {
 	RoundingDecimals int
 	MaxCost          float64
 	MaxCostStrategy  string
+	Currency         string
+	VolumeCounter    string
 	TimingID         string // This RatingUnit is bounded to specific timing profile
 	RatesID          string
 	RatingFiltersID  string
@@ -235,6 +239,8 @@ func (ru *RatingUnit) Equals(oRU *RatingUnit) bool {
 		ru.RoundingDecimals == oRU.RoundingDecimals &&
 		ru.MaxCost == oRU.MaxCost &&
 		ru.MaxCostStrategy == oRU.MaxCostStrategy &&
+		ru.Currency == oRU.Currency &&
+		ru.VolumeCounter == oRU.VolumeCounter &&
 		ru.TimingID == oRU.TimingID &&
 		ru.RatesID == oRU.RatesID &&
 		ru.RatingFiltersID == oRU.RatingFiltersID
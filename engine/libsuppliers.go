@@ -57,16 +57,55 @@ func (sSpls *SortedSuppliers) SortCost() {
 	})
 }
 
+// SortQuality is part of sort interface,
+// sort based on metricID value with fallback on Weight
+// suppliers without metric value are always sorted last
+func (sSpls *SortedSuppliers) SortQuality(metricID string, ascending bool) {
+	sort.Slice(sSpls.SortedSuppliers, func(i, j int) bool {
+		iVal, iHas := sSpls.SortedSuppliers[i].SortingData[metricID]
+		jVal, jHas := sSpls.SortedSuppliers[j].SortingData[metricID]
+		if iHas != jHas {
+			return iHas
+		}
+		if !iHas || iVal.(float64) == jVal.(float64) {
+			return sSpls.SortedSuppliers[i].SortingData[utils.Weight].(float64) > sSpls.SortedSuppliers[j].SortingData[utils.Weight].(float64)
+		}
+		if ascending {
+			return iVal.(float64) < jVal.(float64)
+		}
+		return iVal.(float64) > jVal.(float64)
+	})
+}
+
+// SortLoad is part of sort interface,
+// sort based on Load with fallback on Weight
+func (sSpls *SortedSuppliers) SortLoad() {
+	sort.Slice(sSpls.SortedSuppliers, func(i, j int) bool {
+		if sSpls.SortedSuppliers[i].SortingData[utils.Load].(float64) == sSpls.SortedSuppliers[j].SortingData[utils.Load].(float64) {
+			return sSpls.SortedSuppliers[i].SortingData[utils.Weight].(float64) > sSpls.SortedSuppliers[j].SortingData[utils.Weight].(float64)
+		}
+		return sSpls.SortedSuppliers[i].SortingData[utils.Load].(float64) < sSpls.SortedSuppliers[j].SortingData[utils.Load].(float64)
+	})
+}
+
 // SuppliersSorter is the interface which needs to be implemented by supplier sorters
 type SuppliersSorter interface {
 	SortSuppliers(string, []*Supplier, *utils.CGREvent) (*SortedSuppliers, error)
 }
 
+// SuppliersParamsSorter is implemented by the sorters configurable out of profile SortingParams
+type SuppliersParamsSorter interface {
+	SortSuppliersWithParams(string, []*Supplier, *utils.CGREvent, []string) (*SortedSuppliers, error)
+}
+
 // NewSupplierSortDispatcher constructs SupplierSortDispatcher
 func NewSupplierSortDispatcher(lcrS *SupplierService) (ssd SupplierSortDispatcher, err error) {
 	ssd = make(map[string]SuppliersSorter)
 	ssd[utils.MetaWeight] = NewWeightSorter()
 	ssd[utils.MetaLeastCost] = NewLeastCostSorter(lcrS)
+	ssd[utils.MetaHighestQuality] = NewHighestQualitySorter(lcrS)
+	ssd[utils.MetaLoadDistribution] = NewLoadDistributionSorter(lcrS)
+	ssd[utils.MetaLeastCostWithQuality] = NewLeastCostWithQualitySorter(lcrS)
 	return
 }
 
@@ -75,11 +114,14 @@ func NewSupplierSortDispatcher(lcrS *SupplierService) (ssd SupplierSortDispatche
 type SupplierSortDispatcher map[string]SuppliersSorter
 
 func (ssd SupplierSortDispatcher) SortSuppliers(prflID, strategy string,
-	suppls []*Supplier, suplEv *utils.CGREvent) (sortedSuppls *SortedSuppliers, err error) {
+	suppls []*Supplier, suplEv *utils.CGREvent, sortingParams []string) (sortedSuppls *SortedSuppliers, err error) {
 	sd, has := ssd[strategy]
 	if !has {
 		return nil, fmt.Errorf("unsupported sorting strategy: %s", strategy)
 	}
+	if psd, canCast := sd.(SuppliersParamsSorter); canCast {
+		return psd.SortSuppliersWithParams(prflID, suppls, suplEv, sortingParams)
+	}
 	return sd.SortSuppliers(prflID, suppls, suplEv)
 }
 
@@ -139,3 +139,250 @@ func TestLibSuppliersSortWeight(t *testing.T) {
 		t.Errorf("Expecting: %+v, received: %+v", eSpls.Sorting, result.Sorting)
 	}
 }
+
+func TestLibSuppliersSortQuality(t *testing.T) {
+	sSpls := &SortedSuppliers{
+		SortedSuppliers: []*SortedSupplier{
+			&SortedSupplier{
+				SupplierID: "supplier1",
+				SortingData: map[string]interface{}{
+					utils.Weight: 10.0,
+				},
+			},
+			&SortedSupplier{
+				SupplierID: "supplier2",
+				SortingData: map[string]interface{}{
+					utils.MetaASR: 50.0,
+					utils.Weight:  10.0,
+				},
+			},
+			&SortedSupplier{
+				SupplierID: "supplier3",
+				SortingData: map[string]interface{}{
+					utils.MetaASR: 70.0,
+					utils.Weight:  10.0,
+				},
+			},
+			&SortedSupplier{
+				SupplierID: "supplier4",
+				SortingData: map[string]interface{}{
+					utils.MetaASR: 50.0,
+					utils.Weight:  20.0,
+				},
+			},
+		},
+	}
+	sSpls.SortQuality(utils.MetaASR, false)
+	var rcvIDs []string
+	for _, sSpl := range sSpls.SortedSuppliers {
+		rcvIDs = append(rcvIDs, sSpl.SupplierID)
+	}
+	if eIDs := []string{"supplier3", "supplier4", "supplier2", "supplier1"}; !reflect.DeepEqual(eIDs, rcvIDs) {
+		t.Errorf("Expecting: %+v, received: %+v", eIDs, rcvIDs)
+	}
+	sSpls.SortQuality(utils.MetaASR, true)
+	rcvIDs = nil
+	for _, sSpl := range sSpls.SortedSuppliers {
+		rcvIDs = append(rcvIDs, sSpl.SupplierID)
+	}
+	if eIDs := []string{"supplier4", "supplier2", "supplier3", "supplier1"}; !reflect.DeepEqual(eIDs, rcvIDs) {
+		t.Errorf("Expecting: %+v, received: %+v", eIDs, rcvIDs)
+	}
+}
+
+func TestLibSuppliersSortLoad(t *testing.T) {
+	sSpls := &SortedSuppliers{
+		SortedSuppliers: []*SortedSupplier{
+			&SortedSupplier{
+				SupplierID: "supplier1",
+				SortingData: map[string]interface{}{
+					utils.Load:   2.0,
+					utils.Weight: 10.0,
+				},
+			},
+			&SortedSupplier{
+				SupplierID: "supplier2",
+				SortingData: map[string]interface{}{
+					utils.Load:   1.0,
+					utils.Weight: 10.0,
+				},
+			},
+			&SortedSupplier{
+				SupplierID: "supplier3",
+				SortingData: map[string]interface{}{
+					utils.Load:   1.0,
+					utils.Weight: 20.0,
+				},
+			},
+		},
+	}
+	sSpls.SortLoad()
+	var rcvIDs []string
+	for _, sSpl := range sSpls.SortedSuppliers {
+		rcvIDs = append(rcvIDs, sSpl.SupplierID)
+	}
+	if eIDs := []string{"supplier3", "supplier2", "supplier1"}; !reflect.DeepEqual(eIDs, rcvIDs) {
+		t.Errorf("Expecting: %+v, received: %+v", eIDs, rcvIDs)
+	}
+}
+
+// mockSplConn emulates StatS and ResourceS for supplier sorting
+type mockSplConn struct {
+	metrics   map[string]map[string]float64 // map[StatID]map[MetricID]Value
+	resources map[string]*Resource
+}
+
+func (mc *mockSplConn) Call(serviceMethod string, args interface{}, reply interface{}) error {
+	tntID := args.(*utils.TenantID)
+	switch serviceMethod {
+	case utils.StatSv1GetQueueFloatMetrics:
+		metrics, has := mc.metrics[tntID.ID]
+		if !has {
+			return utils.ErrNotFound
+		}
+		*reply.(*map[string]float64) = metrics
+	case utils.ResourceSv1GetResource:
+		res, has := mc.resources[tntID.ID]
+		if !has {
+			return utils.ErrNotFound
+		}
+		*reply.(*Resource) = *res
+	default:
+		return utils.ErrNotImplemented
+	}
+	return nil
+}
+
+func TestLibSuppliersHighestQualitySorter(t *testing.T) {
+	spS := &SupplierService{statS: &mockSplConn{
+		metrics: map[string]map[string]float64{
+			"STATS_1": map[string]float64{utils.MetaASR: 40, utils.MetaPDD: 2},
+			"STATS_2": map[string]float64{utils.MetaASR: 80, utils.MetaPDD: STATS_NA},
+			"STATS_3": map[string]float64{utils.MetaPDD: 1},
+		}}}
+	spls := []*Supplier{
+		&Supplier{ID: "supplier1", StatIDs: []string{"STATS_1"}, Weight: 10},
+		&Supplier{ID: "supplier2", StatIDs: []string{"STATS_2", "STATS_3"}, Weight: 10},
+		&Supplier{ID: "supplier3", StatIDs: []string{"STATS_NOT_FOUND"}, Weight: 20},
+	}
+	ev := &utils.CGREvent{Tenant: "cgrates.org", ID: "supplierevent1"}
+	hqs := NewHighestQualitySorter(spS)
+	for _, tc := range []struct {
+		params []string
+		eIDs   []string
+	}{
+		{nil, []string{"supplier2", "supplier1", "supplier3"}},
+		{[]string{utils.MetaPDD, utils.MetaAscending}, []string{"supplier2", "supplier1", "supplier3"}},
+		{[]string{utils.MetaPDD, utils.MetaDescending}, []string{"supplier1", "supplier2", "supplier3"}},
+	} {
+		sSpls, err := hqs.SortSuppliersWithParams("SPL_QUALITY", spls, ev, tc.params)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var rcvIDs []string
+		for _, sSpl := range sSpls.SortedSuppliers {
+			rcvIDs = append(rcvIDs, sSpl.SupplierID)
+		}
+		if !reflect.DeepEqual(tc.eIDs, rcvIDs) {
+			t.Errorf("params: %+v, expecting: %+v, received: %+v", tc.params, tc.eIDs, rcvIDs)
+		}
+	}
+	if _, err := hqs.SortSuppliersWithParams("SPL_QUALITY", spls, ev,
+		[]string{utils.MetaASR, "*unknown"}); err == nil {
+		t.Error("expecting error")
+	}
+}
+
+func TestLibSuppliersLoadDistributionSorter(t *testing.T) {
+	spS := &SupplierService{resourceS: &mockSplConn{
+		resources: map[string]*Resource{
+			"RES_1": &Resource{Tenant: "cgrates.org", ID: "RES_1",
+				Usages: map[string]*ResourceUsage{
+					"RU_1": &ResourceUsage{Tenant: "cgrates.org", ID: "RU_1", Units: 4},
+					"RU_2": &ResourceUsage{Tenant: "cgrates.org", ID: "RU_2", Units: 2}}},
+			"RES_2": &Resource{Tenant: "cgrates.org", ID: "RES_2",
+				Usages: map[string]*ResourceUsage{
+					"RU_3": &ResourceUsage{Tenant: "cgrates.org", ID: "RU_3", Units: 4}}},
+		}}}
+	spls := []*Supplier{
+		&Supplier{ID: "supplier1", ResourceIDs: []string{"RES_1"}, Weight: 10},
+		&Supplier{ID: "supplier2", ResourceIDs: []string{"RES_2"}, Weight: 10},
+		&Supplier{ID: "supplier3", ResourceIDs: []string{"RES_NOT_FOUND"}, Weight: 10},
+	}
+	ev := &utils.CGREvent{Tenant: "cgrates.org", ID: "supplierevent1"}
+	lds := NewLoadDistributionSorter(spS)
+	sSpls, err := lds.SortSuppliersWithParams("SPL_LOAD", spls, ev,
+		[]string{"supplier1:3", "*default:1", "supplier3:0"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	eSpls := []*SortedSupplier{
+		&SortedSupplier{
+			SupplierID: "supplier1",
+			SortingData: map[string]interface{}{
+				utils.Weight:        10.0,
+				utils.ResourceUsage: 6.0,
+				utils.Ratio:         3.0,
+				utils.Load:          2.0,
+			},
+		},
+		&SortedSupplier{
+			SupplierID: "supplier2",
+			SortingData: map[string]interface{}{
+				utils.Weight:        10.0,
+				utils.ResourceUsage: 4.0,
+				utils.Ratio:         1.0,
+				utils.Load:          4.0,
+			},
+		},
+	}
+	if !reflect.DeepEqual(eSpls, sSpls.SortedSuppliers) {
+		t.Errorf("Expecting: %s, received: %s",
+			utils.ToJSON(eSpls), utils.ToJSON(sSpls.SortedSuppliers))
+	}
+	if _, err := lds.SortSuppliersWithParams("SPL_LOAD", spls, ev, []string{"supplier1"}); err == nil {
+		t.Error("expecting error")
+	}
+}
+
+func TestLibSuppliersParseQualityFloors(t *testing.T) {
+	eQfs := []*qualityFloor{
+		&qualityFloor{metricID: utils.MetaASR, value: 50},
+		&qualityFloor{metricID: utils.MetaPDD, value: 3, ascending: true},
+	}
+	if qfs, err := parseQualityFloors([]string{"*asr:50", "*pdd:3:*asc"}); err != nil {
+		t.Error(err)
+	} else if !reflect.DeepEqual(eQfs, qfs) {
+		t.Errorf("Expecting: %+v, received: %+v", eQfs, qfs)
+	}
+	if !eQfs[0].passes(50) || eQfs[0].passes(49.9) {
+		t.Error("wrong floor check for *asr")
+	}
+	if !eQfs[1].passes(2) || eQfs[1].passes(3.5) {
+		t.Error("wrong floor check for *pdd")
+	}
+	for _, params := range [][]string{{"*asr"}, {"*asr:a"}, {"*pdd:3:*unknown"}} {
+		if _, err := parseQualityFloors(params); err == nil {
+			t.Errorf("params: %+v, expecting error", params)
+		}
+	}
+}
+
+func TestLibSuppliersSortDispatcherParams(t *testing.T) {
+	ssd := SupplierSortDispatcher{
+		utils.MetaWeight:           NewWeightSorter(),
+		utils.MetaLoadDistribution: NewLoadDistributionSorter(&SupplierService{resourceS: &mockSplConn{}}),
+	}
+	spls := []*Supplier{&Supplier{ID: "supplier1", Weight: 10}}
+	ev := &utils.CGREvent{Tenant: "cgrates.org", ID: "supplierevent1"}
+	if sSpls, err := ssd.SortSuppliers("SPL_WEIGHT", utils.MetaWeight, spls, ev,
+		[]string{"ignored"}); err != nil {
+		t.Error(err)
+	} else if len(sSpls.SortedSuppliers) != 1 {
+		t.Errorf("unexpected suppliers: %s", utils.ToJSON(sSpls))
+	}
+	if _, err := ssd.SortSuppliers("SPL_LOAD", utils.MetaLoadDistribution, spls, ev,
+		[]string{"supplier1"}); err == nil {
+		t.Error("expecting error for invalid sorting params")
+	}
+}
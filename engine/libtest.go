@@ -51,7 +51,7 @@ func InitDataDb(cfg *config.CGRConfig) error {
 }
 
 func InitStorDb(cfg *config.CGRConfig) error {
-	x := []string{utils.MYSQL, utils.POSTGRES}
+	x := []string{utils.MYSQL, utils.POSTGRES, utils.SQLITE}
 	storDb, err := ConfigureLoadStorage(cfg.StorDBType, cfg.StorDBHost, cfg.StorDBPort, cfg.StorDBName, cfg.StorDBUser, cfg.StorDBPass, cfg.DBDataEncoding,
 		cfg.StorDBMaxOpenConns, cfg.StorDBMaxIdleConns, cfg.StorDBConnMaxLifetime, cfg.StorDBCDRSIndexes)
 	if err != nil {
@@ -138,6 +138,9 @@ func LoadTariffPlanFromFolder(tpPath, timezone string, dm *DataManager, disable_
 		path.Join(tpPath, utils.FiltersCsv),
 		path.Join(tpPath, utils.SuppliersCsv),
 		path.Join(tpPath, utils.AttributesCsv),
+		path.Join(tpPath, utils.ExchangeRatesCsv),
+		path.Join(tpPath, utils.TaxProfilesCsv),
+		path.Join(tpPath, utils.FeeProfilesCsv),
 	), "", timezone)
 	if err := loader.LoadAll(); err != nil {
 		return utils.NewErrServerError(err)
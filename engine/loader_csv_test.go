@@ -289,6 +289,9 @@ cgrates.org,FLTR_1,*rsr_fields,,Subject(~^1.*1$);Destination(1002),
 cgrates.org,FLTR_ACNT_dan,*string,Account,dan,2014-07-29T15:00:00Z
 cgrates.org,FLTR_DST_DE,*destinations,Destination,DST_DE,2014-07-29T15:00:00Z
 cgrates.org,FLTR_DST_NL,*destinations,Destination,DST_NL,2014-07-29T15:00:00Z
+cgrates.org,FLTR_RAD,*cidr,FramedIPAddress,10.0.0.0/8;172.16.0.0/12,2014-07-29T15:00:00Z
+cgrates.org,FLTR_RAD,*suffix,CalledStationId,.mnc001.mcc226.gprs,
+cgrates.org,FLTR_RAD,*range,Usage,1s..1h,
 `
 	sppProfiles = `
 #Tenant,ID,FilterIDs,ActivationInterval,Sorting,SortingParams,SupplierID,SupplierFilterIDs,SupplierAccountIDs,SupplierRatingPlanIDs,SupplierResourceIDs,SupplierStatIDs,SupplierWeight,Blocker,Weight
@@ -301,6 +304,20 @@ cgrates.org,SPP_1,,,,,supplier1,,,,ResGroup4,Stat3,10,,
 #,Tenant,ID,Context,FilterIDs,ActivationInterval,FieldName,Initial,Substitute,Append,Weight
 cgrates.org,ALS1,con1,FLTR_1,2014-07-29T15:00:00Z,Field1,Initial1,Sub1,true,20
 cgrates.org,ALS1,,,,Field2,Initial2,Sub2,false,
+`
+	exchangeRates = `
+#FromCurrency,ToCurrency,ActivationTime,Rate
+EUR,USD,2014-07-29T15:00:00Z,1.12
+EUR,USD,2017-01-01T00:00:00Z,1.05
+`
+	taxProfiles = `
+#Tenant,ID,FilterIDs,ActivationInterval,TaxID,TaxType,TaxValue,TaxTiers,Weight
+cgrates.org,TAX_1,FLTR_1,2014-07-29T15:00:00Z,VAT,*percent,19,,20
+cgrates.org,TAX_1,,,SERVICE,*tiered,0,0:5;100:2,
+`
+	feeProfiles = `
+#Tenant,ID,Period,Amount,Category,ProRate
+cgrates.org,FEE_MONTHLY,*monthly,10,subscription,true
 `
 )
 
@@ -309,7 +326,8 @@ var csvr *TpReader
 func init() {
 	csvr = NewTpReader(dm.dataDB, NewStringCSVStorage(',', destinations, timings, rates, destinationRates, ratingPlans, ratingProfiles,
 		sharedGroups, lcrs, actions, actionPlans, actionTriggers, accountActions, derivedCharges,
-		cdrStats, users, aliases, resProfiles, stats, thresholds, filters, sppProfiles, attributeProfiles), testTPID, "")
+		cdrStats, users, aliases, resProfiles, stats, thresholds, filters, sppProfiles, attributeProfiles,
+		exchangeRates, taxProfiles, feeProfiles), testTPID, "")
 
 	if err := csvr.LoadDestinations(); err != nil {
 		log.Print("error in LoadDestinations:", err)
@@ -344,6 +362,9 @@ func init() {
 	if err := csvr.LoadActionTriggers(); err != nil {
 		log.Print("error in LoadActionTriggers:", err)
 	}
+	if err := csvr.LoadFeeProfiles(); err != nil {
+		log.Print("error in LoadFeeProfiles:", err)
+	}
 	if err := csvr.LoadAccountActions(); err != nil {
 		log.Print("error in LoadAccountActions:", err)
 	}
@@ -377,6 +398,12 @@ func init() {
 	if err := csvr.LoadAttributeProfiles(); err != nil {
 		log.Print("error in LoadAttributeProfiles:", err)
 	}
+	if err := csvr.LoadExchangeRates(); err != nil {
+		log.Print("error in LoadExchangeRates:", err)
+	}
+	if err := csvr.LoadTaxProfiles(); err != nil {
+		log.Print("error in LoadTaxProfiles:", err)
+	}
 	csvr.WriteToDatabase(false, false, false)
 	cache.Flush()
 	dm.LoadDataDBCache(nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
@@ -1621,6 +1648,31 @@ func TestLoadFilters(t *testing.T) {
 				ActivationTime: "2014-07-29T15:00:00Z",
 			},
 		},
+		utils.TenantID{Tenant: "cgrates.org", ID: "FLTR_RAD"}: &utils.TPFilterProfile{
+			TPid:   testTPID,
+			Tenant: "cgrates.org",
+			ID:     "FLTR_RAD",
+			Filters: []*utils.TPFilter{
+				&utils.TPFilter{
+					FieldName: "FramedIPAddress",
+					Type:      "*cidr",
+					Values:    []string{"10.0.0.0/8", "172.16.0.0/12"},
+				},
+				&utils.TPFilter{
+					FieldName: "CalledStationId",
+					Type:      "*suffix",
+					Values:    []string{".mnc001.mcc226.gprs"},
+				},
+				&utils.TPFilter{
+					FieldName: "Usage",
+					Type:      "*range",
+					Values:    []string{"1s..1h"},
+				},
+			},
+			ActivationInterval: &utils.TPActivationInterval{
+				ActivationTime: "2014-07-29T15:00:00Z",
+			},
+		},
 	}
 	fltrKey := utils.TenantID{Tenant: "cgrates.org", ID: "FLTR_1"}
 	if len(csvr.filters) != len(eFilters) {
@@ -1628,6 +1680,15 @@ func TestLoadFilters(t *testing.T) {
 	} else if !reflect.DeepEqual(eFilters[fltrKey], csvr.filters[fltrKey]) {
 		t.Errorf("Expecting: %+v, received: %+v", eFilters[fltrKey], csvr.filters[fltrKey])
 	}
+	fltrKey = utils.TenantID{Tenant: "cgrates.org", ID: "FLTR_RAD"}
+	if !reflect.DeepEqual(eFilters[fltrKey], csvr.filters[fltrKey]) {
+		t.Errorf("Expecting: %+v, received: %+v", eFilters[fltrKey], csvr.filters[fltrKey])
+	} else if fltr, err := APItoFilter(csvr.filters[fltrKey], "UTC"); err != nil {
+		t.Error(err)
+	} else if len(fltr.RequestFilters) != 3 || len(fltr.RequestFilters[0].ipNets) != 2 ||
+		len(fltr.RequestFilters[2].ranges) != 1 {
+		t.Errorf("Filter not compiled: %s", utils.ToJSON(fltr))
+	}
 }
 
 func TestLoadSupplierProfiles(t *testing.T) {
@@ -1711,6 +1772,49 @@ func TestLoadAttributeProfiles(t *testing.T) {
 	}
 }
 
+func TestLoadExchangeRates(t *testing.T) {
+	eXRs := map[string]*utils.TPExchangeRate{
+		"EUR:USD": &utils.TPExchangeRate{
+			TPid:         testTPID,
+			FromCurrency: "EUR",
+			ToCurrency:   "USD",
+			Rates: []*utils.TPCurrencyRate{
+				&utils.TPCurrencyRate{ActivationTime: "2014-07-29T15:00:00Z", Rate: 1.12},
+				&utils.TPCurrencyRate{ActivationTime: "2017-01-01T00:00:00Z", Rate: 1.05},
+			},
+		},
+	}
+	if !reflect.DeepEqual(eXRs, csvr.exchangeRates) {
+		t.Errorf("Expecting: %s, received: %s", utils.ToJSON(eXRs), utils.ToJSON(csvr.exchangeRates))
+	}
+}
+
+func TestLoadTaxProfiles(t *testing.T) {
+	eTxPrfls := map[utils.TenantID]*utils.TPTaxProfile{
+		utils.TenantID{Tenant: "cgrates.org", ID: "TAX_1"}: &utils.TPTaxProfile{
+			TPid:      testTPID,
+			Tenant:    "cgrates.org",
+			ID:        "TAX_1",
+			FilterIDs: []string{"FLTR_1"},
+			ActivationInterval: &utils.TPActivationInterval{
+				ActivationTime: "2014-07-29T15:00:00Z",
+			},
+			Taxes: []*utils.TPTax{
+				&utils.TPTax{ID: "VAT", Type: utils.MetaPercent, Value: 19},
+				&utils.TPTax{ID: "SERVICE", Type: utils.MetaTiered,
+					Tiers: []*utils.TPTaxTier{
+						&utils.TPTaxTier{Threshold: 0, Value: 5},
+						&utils.TPTaxTier{Threshold: 100, Value: 2},
+					}},
+			},
+			Weight: 20,
+		},
+	}
+	if !reflect.DeepEqual(eTxPrfls, csvr.taxProfiles) {
+		t.Errorf("Expecting: %s, received: %s", utils.ToJSON(eTxPrfls), utils.ToJSON(csvr.taxProfiles))
+	}
+}
+
 func TestLoadResource(t *testing.T) {
 	eResources := []*utils.TenantID{
 		&utils.TenantID{
@@ -1760,3 +1864,74 @@ func TestLoadThresholds(t *testing.T) {
 		t.Errorf("Failed to load thresholds: %s", utils.ToIJSON(csvr.thresholds))
 	}
 }
+
+func TestLoadFeeProfiles(t *testing.T) {
+	eFePrfls := map[utils.TenantID]*utils.TPFeeProfile{
+		utils.TenantID{Tenant: "cgrates.org", ID: "FEE_MONTHLY"}: &utils.TPFeeProfile{
+			TPid:     testTPID,
+			Tenant:   "cgrates.org",
+			ID:       "FEE_MONTHLY",
+			Period:   utils.MetaMonthly,
+			Amount:   10,
+			Category: "subscription",
+			ProRate:  true,
+		},
+	}
+	if !reflect.DeepEqual(eFePrfls, csvr.feeProfiles) {
+		t.Errorf("Expecting: %s, received: %s", utils.ToJSON(eFePrfls), utils.ToJSON(csvr.feeProfiles))
+	}
+	acntActions := `
+#Tenant,Account,ActionPlanId,ActionTriggersId,AllowNegative,Disabled,FeeProfileIDs
+cgrates.org,subscriber,,,,,FEE_MONTHLY
+cgrates.org,nofees,,,,
+`
+	tpr := NewTpReader(dm.dataDB, NewStringCSVStorage(',', "", "", "", "", "", "", "", "", "", "", "",
+		acntActions, "", "", "", "", "", "", "", "", "", "", "", "", feeProfiles), testTPID, "")
+	if err := tpr.LoadFeeProfiles(); err != nil {
+		t.Fatal(err)
+	}
+	if err := tpr.LoadAccountActions(); err != nil {
+		t.Fatal(err)
+	}
+	if acnt := tpr.accountActions["cgrates.org:nofees"]; acnt == nil || len(acnt.Subscriptions) != 0 {
+		t.Errorf("Wrong account loaded: %s", utils.ToJSON(acnt))
+	}
+	acnt := tpr.accountActions["cgrates.org:subscriber"]
+	if acnt == nil {
+		t.Fatal("Subscriber account not loaded")
+	}
+	if sub, has := acnt.Subscriptions["FEE_MONTHLY"]; !has {
+		t.Errorf("Subscription not loaded: %s", utils.ToJSON(acnt.Subscriptions))
+	} else if sub.FeeProfileID != "FEE_MONTHLY" || sub.ActivationTime.IsZero() {
+		t.Errorf("Wrong subscription: %s", utils.ToJSON(sub))
+	}
+}
+
+func TestLoadStatProfilesHistogram(t *testing.T) {
+	statsCsv := `
+#Tenant[0],Id[1],FilterIDs[2],ActivationInterval[3],QueueLength[4],TTL[5],Metrics[6],Blocker[7],Stored[8],Weight[9],MinItems[10],Thresholds[11]
+cgrates.org,StatsHist,FLTR_1,2014-07-29T15:00:00Z,100,1s,*asr;*histogram_pdd#500ms&1s&3s;*p95_acd,true,true,20,2,THRESH1
+`
+	csvStor := NewStringCSVStorage(',', "", "", "", "", "", "", "", "", "", "", "", "", "", "", "",
+		"", "", statsCsv, "", "", "", "", "", "", "")
+	tps, err := csvStor.GetTPStats(testTPID, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tps) != 1 {
+		t.Fatalf("unexpected stats: %s", utils.ToJSON(tps))
+	}
+	eMetrics := []string{"*asr", "*histogram_pdd#500ms&1s&3s", "*p95_acd"}
+	if !reflect.DeepEqual(eMetrics, tps[0].Metrics) {
+		t.Errorf("expecting: %+v, received: %+v", eMetrics, tps[0].Metrics)
+	}
+	sqp, err := APItoStats(tps[0], "UTC")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, metricID := range sqp.Metrics {
+		if _, err := NewStatMetric(metricID, sqp.MinItems); err != nil {
+			t.Errorf("metricID: %s, error: %v", metricID, err)
+		}
+	}
+}
@@ -110,6 +110,9 @@ func TestLoaderITRemoveLoad(t *testing.T) {
 		path.Join(*dataDir, "tariffplans", *tpCsvScenario, utils.FiltersCsv),
 		path.Join(*dataDir, "tariffplans", *tpCsvScenario, utils.SuppliersCsv),
 		path.Join(*dataDir, "tariffplans", *tpCsvScenario, utils.AttributesCsv),
+		path.Join(*dataDir, "tariffplans", *tpCsvScenario, utils.ExchangeRatesCsv),
+		path.Join(*dataDir, "tariffplans", *tpCsvScenario, utils.TaxProfilesCsv),
+		path.Join(*dataDir, "tariffplans", *tpCsvScenario, utils.FeeProfilesCsv),
 	), "", "")
 
 	if err = loader.LoadDestinations(); err != nil {
@@ -211,6 +214,9 @@ func TestLoaderITLoadFromCSV(t *testing.T) {
 		path.Join(*dataDir, "tariffplans", *tpCsvScenario, utils.FiltersCsv),
 		path.Join(*dataDir, "tariffplans", *tpCsvScenario, utils.SuppliersCsv),
 		path.Join(*dataDir, "tariffplans", *tpCsvScenario, utils.AttributesCsv),
+		path.Join(*dataDir, "tariffplans", *tpCsvScenario, utils.ExchangeRatesCsv),
+		path.Join(*dataDir, "tariffplans", *tpCsvScenario, utils.TaxProfilesCsv),
+		path.Join(*dataDir, "tariffplans", *tpCsvScenario, utils.FeeProfilesCsv),
 	), "", "")
 
 	if err = loader.LoadDestinations(); err != nil {
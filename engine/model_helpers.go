@@ -23,6 +23,7 @@ import (
 	"log"
 	"reflect"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -34,12 +35,17 @@ func csvLoad(s interface{}, values []string) (interface{}, error) {
 	fieldValueMap := make(map[string]string)
 	st := reflect.TypeOf(s)
 	numFields := st.NumField()
+	var nrColumns int
 	for i := 0; i < numFields; i++ {
 		field := st.Field(i)
 		re := field.Tag.Get("re")
 		index := field.Tag.Get("index")
 		if index != "" {
+			nrColumns++
 			idx, err := strconv.Atoi(index)
+			if err == nil && len(values) <= idx && field.Tag.Get("optional") == "true" {
+				continue // trailing column missing from older files
+			}
 			if err != nil || len(values) <= idx {
 				return nil, fmt.Errorf("invalid %v.%v index %v", st.Name(), field.Name, index)
 			}
@@ -51,6 +57,9 @@ func csvLoad(s interface{}, values []string) (interface{}, error) {
 			fieldValueMap[field.Name] = values[idx]
 		}
 	}
+	if len(values) > nrColumns {
+		return nil, fmt.Errorf("invalid %v number of fields %v", st.Name(), len(values))
+	}
 	elem := reflect.New(st).Elem()
 	for fieldName, fieldValue := range fieldValueMap {
 		field := elem.FieldByName(fieldName)
@@ -93,6 +102,7 @@ func csvLoad(s interface{}, values []string) (interface{}, error) {
 
 func csvDump(s interface{}) ([]string, error) {
 	fieldIndexMap := make(map[string]int)
+	optIndexes := make(map[int]bool)
 	st := reflect.ValueOf(s)
 	if st.Kind() == reflect.Ptr {
 		st = st.Elem()
@@ -108,6 +118,9 @@ func csvDump(s interface{}) ([]string, error) {
 				return nil, fmt.Errorf("invalid %v.%v index %v", stcopy.Name(), field.Name, index)
 			} else {
 				fieldIndexMap[field.Name] = idx
+				if field.Tag.Get("optional") == "true" {
+					optIndexes[idx] = true
+				}
 			}
 		}
 	}
@@ -127,6 +140,10 @@ func csvDump(s interface{}) ([]string, error) {
 			}
 		}
 	}
+	// leave out the empty optional columns so the files load also with older versions
+	for len(result) != 0 && optIndexes[len(result)-1] && result[len(result)-1] == "" {
+		result = result[:len(result)-1]
+	}
 	return result, nil
 }
 
@@ -172,6 +189,8 @@ func modelEqual(this interface{}, other interface{}) bool {
 	return true
 }
 
+// getColumnCount returns the number of CSV columns of the model,
+// -1 if some are optional since csvLoad checks the number of fields then
 func getColumnCount(s interface{}) int {
 	st := reflect.TypeOf(s)
 	numFields := st.NumField()
@@ -180,6 +199,9 @@ func getColumnCount(s interface{}) int {
 		field := st.Field(i)
 		index := field.Tag.Get("index")
 		if index != "" {
+			if field.Tag.Get("optional") == "true" {
+				return -1
+			}
 			count++
 		}
 	}
@@ -411,6 +433,7 @@ func (tps TpDestinationRates) AsMapDestinationRates() (map[string]*utils.TPDesti
 					RoundingDecimals: tp.RoundingDecimals,
 					MaxCost:          tp.MaxCost,
 					MaxCostStrategy:  tp.MaxCostStrategy,
+					VolumeCounter:    tp.VolumeCounter,
 				},
 			},
 		}
@@ -460,6 +483,7 @@ func APItoModelDestinationRate(d *utils.TPDestinationRate) (result TpDestination
 				RoundingDecimals: dr.RoundingDecimals,
 				MaxCost:          dr.MaxCost,
 				MaxCostStrategy:  dr.MaxCostStrategy,
+				VolumeCounter:    dr.VolumeCounter,
 			})
 		}
 		if len(d.DestinationRates) == 0 {
@@ -489,8 +513,9 @@ func (tps TpRatingPlans) AsMapTPRatingPlans() (map[string]*utils.TPRatingPlan, e
 	result := make(map[string]*utils.TPRatingPlan)
 	for _, tp := range tps {
 		rp := &utils.TPRatingPlan{
-			TPid: tp.Tpid,
-			ID:   tp.Tag,
+			TPid:     tp.Tpid,
+			ID:       tp.Tag,
+			Currency: tp.Currency,
 		}
 		rpb := &utils.TPRatingPlanBinding{
 			DestinationRatesId: tp.DestratesTag,
@@ -502,6 +527,9 @@ func (tps TpRatingPlans) AsMapTPRatingPlans() (map[string]*utils.TPRatingPlan, e
 			result[rp.ID] = rp
 		} else {
 			existing.RatingPlanBindings = append(existing.RatingPlanBindings, rpb)
+			if existing.Currency == "" {
+				existing.Currency = tp.Currency
+			}
 		}
 	}
 	return result, nil
@@ -535,6 +563,7 @@ func GetRateInterval(rpl *utils.TPRatingPlanBinding, dr *utils.DestinationRate)
 			RoundingDecimals: dr.RoundingDecimals,
 			MaxCost:          dr.MaxCost,
 			MaxCostStrategy:  dr.MaxCostStrategy,
+			VolumeCounter:    dr.VolumeCounter,
 			tag:              dr.Rate.ID,
 		},
 	}
@@ -563,6 +592,17 @@ func MapTPRatingPlanBindings(s []*utils.TPRatingPlan) map[string][]*utils.TPRati
 	return result
 }
 
+// MapTPRatingPlanCurrencies returns the currency of each RatingPlan indexed on ID
+func MapTPRatingPlanCurrencies(s []*utils.TPRatingPlan) map[string]string {
+	result := make(map[string]string)
+	for _, e := range s {
+		if e.Currency != "" {
+			result[e.ID] = e.Currency
+		}
+	}
+	return result
+}
+
 func APItoModelRatingPlan(rp *utils.TPRatingPlan) (result TpRatingPlans) {
 	if rp != nil {
 		for _, rpb := range rp.RatingPlanBindings {
@@ -572,12 +612,14 @@ func APItoModelRatingPlan(rp *utils.TPRatingPlan) (result TpRatingPlans) {
 				DestratesTag: rpb.DestinationRatesId,
 				TimingTag:    rpb.TimingId,
 				Weight:       rpb.Weight,
+				Currency:     rp.Currency,
 			})
 		}
 		if len(rp.RatingPlanBindings) == 0 {
 			result = append(result, TpRatingPlan{
-				Tpid: rp.TPid,
-				Tag:  rp.ID,
+				Tpid:     rp.TPid,
+				Tag:      rp.ID,
+				Currency: rp.Currency,
 			})
 		}
 	}
@@ -1081,6 +1123,7 @@ func (tps TpAccountActions) AsMapTPAccountActions() (map[string]*utils.TPAccount
 			ActionTriggersId: tp.ActionTriggersTag,
 			AllowNegative:    tp.AllowNegative,
 			Disabled:         tp.Disabled,
+			FeeProfileIDs:    tp.FeeProfileIDs,
 		}
 		result[aas.KeyId()] = aas
 	}
@@ -1120,6 +1163,7 @@ func APItoModelAccountAction(aa *utils.TPAccountActions) *TpAccountAction {
 		ActionTriggersTag: aa.ActionTriggersId,
 		AllowNegative:     aa.AllowNegative,
 		Disabled:          aa.Disabled,
+		FeeProfileIDs:     aa.FeeProfileIDs,
 	}
 }
 
@@ -2759,3 +2803,239 @@ func APItoAttributeProfile(tpTH *utils.TPAttributeProfile, timezone string) (th
 	}
 	return th, nil
 }
+
+type TpExchangeRates []*TpExchangeRate
+
+func (tps TpExchangeRates) AsTPExchangeRates() (result []*utils.TPExchangeRate) {
+	mst := make(map[string]*utils.TPExchangeRate)
+	for _, tp := range tps {
+		key := utils.ConcatenatedKey(tp.FromCurrency, tp.ToCurrency)
+		xr, found := mst[key]
+		if !found {
+			xr = &utils.TPExchangeRate{
+				TPid:         tp.Tpid,
+				FromCurrency: tp.FromCurrency,
+				ToCurrency:   tp.ToCurrency,
+			}
+		}
+		xr.Rates = append(xr.Rates, &utils.TPCurrencyRate{
+			ActivationTime: tp.ActivationTime,
+			Rate:           tp.Rate,
+		})
+		mst[key] = xr
+	}
+	result = make([]*utils.TPExchangeRate, len(mst))
+	i := 0
+	for _, xr := range mst {
+		result[i] = xr
+		i++
+	}
+	return
+}
+
+func APItoModelTPExchangeRate(xr *utils.TPExchangeRate) (mdls TpExchangeRates) {
+	for _, rate := range xr.Rates {
+		mdls = append(mdls, &TpExchangeRate{
+			Tpid:           xr.TPid,
+			FromCurrency:   xr.FromCurrency,
+			ToCurrency:     xr.ToCurrency,
+			ActivationTime: rate.ActivationTime,
+			Rate:           rate.Rate,
+		})
+	}
+	return
+}
+
+func APItoExchangeRate(tpXR *utils.TPExchangeRate, timezone string) (xr *ExchangeRate, err error) {
+	xr = &ExchangeRate{
+		FromCurrency: tpXR.FromCurrency,
+		ToCurrency:   tpXR.ToCurrency,
+		Rates:        make([]*CurrencyRate, len(tpXR.Rates)),
+	}
+	for i, rate := range tpXR.Rates {
+		xr.Rates[i] = &CurrencyRate{Rate: rate.Rate}
+		if xr.Rates[i].ActivationTime, err = utils.ParseTimeDetectLayout(rate.ActivationTime, timezone); err != nil {
+			return nil, err
+		}
+	}
+	xr.Sort()
+	return
+}
+
+type TpTaxProfiles []*TpTaxProfile
+
+func (tps TpTaxProfiles) AsTPTaxProfiles() (result []*utils.TPTaxProfile, err error) {
+	mst := make(map[string]*utils.TPTaxProfile)
+	for _, tp := range tps {
+		key := utils.ConcatenatedKey(tp.Tenant, tp.ID)
+		txp, found := mst[key]
+		if !found {
+			txp = &utils.TPTaxProfile{
+				TPid:   tp.Tpid,
+				Tenant: tp.Tenant,
+				ID:     tp.ID,
+			}
+		}
+		if tp.Weight != 0 {
+			txp.Weight = tp.Weight
+		}
+		if len(tp.ActivationInterval) != 0 {
+			txp.ActivationInterval = new(utils.TPActivationInterval)
+			aiSplt := strings.Split(tp.ActivationInterval, utils.INFIELD_SEP)
+			if len(aiSplt) == 2 {
+				txp.ActivationInterval.ActivationTime = aiSplt[0]
+				txp.ActivationInterval.ExpiryTime = aiSplt[1]
+			} else if len(aiSplt) == 1 {
+				txp.ActivationInterval.ActivationTime = aiSplt[0]
+			}
+		}
+		if tp.FilterIDs != "" {
+			txp.FilterIDs = append(txp.FilterIDs, strings.Split(tp.FilterIDs, utils.INFIELD_SEP)...)
+		}
+		if tp.TaxID != "" {
+			tax := &utils.TPTax{
+				ID:    tp.TaxID,
+				Type:  tp.TaxType,
+				Value: tp.TaxValue,
+			}
+			if tp.TaxTiers != "" {
+				for _, tierStr := range strings.Split(tp.TaxTiers, utils.INFIELD_SEP) {
+					tierSplt := strings.Split(tierStr, utils.CONCATENATED_KEY_SEP)
+					if len(tierSplt) != 2 {
+						return nil, fmt.Errorf("invalid tax tier: <%s>", tierStr)
+					}
+					tier := new(utils.TPTaxTier)
+					if tier.Threshold, err = strconv.ParseFloat(tierSplt[0], 64); err != nil {
+						return nil, err
+					}
+					if tier.Value, err = strconv.ParseFloat(tierSplt[1], 64); err != nil {
+						return nil, err
+					}
+					tax.Tiers = append(tax.Tiers, tier)
+				}
+			}
+			txp.Taxes = append(txp.Taxes, tax)
+		}
+		mst[key] = txp
+	}
+	result = make([]*utils.TPTaxProfile, len(mst))
+	i := 0
+	for _, txp := range mst {
+		result[i] = txp
+		i++
+	}
+	return
+}
+
+func APItoModelTPTaxProfile(txp *utils.TPTaxProfile) (mdls TpTaxProfiles) {
+	if len(txp.Taxes) == 0 {
+		return
+	}
+	for i, tax := range txp.Taxes {
+		mdl := &TpTaxProfile{
+			Tpid:     txp.TPid,
+			Tenant:   txp.Tenant,
+			ID:       txp.ID,
+			TaxID:    tax.ID,
+			TaxType:  tax.Type,
+			TaxValue: tax.Value,
+		}
+		if i == 0 {
+			if txp.ActivationInterval != nil {
+				if txp.ActivationInterval.ActivationTime != "" {
+					mdl.ActivationInterval = txp.ActivationInterval.ActivationTime
+				}
+				if txp.ActivationInterval.ExpiryTime != "" {
+					mdl.ActivationInterval += utils.INFIELD_SEP + txp.ActivationInterval.ExpiryTime
+				}
+			}
+			mdl.FilterIDs = strings.Join(txp.FilterIDs, utils.INFIELD_SEP)
+			mdl.Weight = txp.Weight
+		}
+		for j, tier := range tax.Tiers {
+			if j != 0 {
+				mdl.TaxTiers += utils.INFIELD_SEP
+			}
+			mdl.TaxTiers += strconv.FormatFloat(tier.Threshold, 'f', -1, 64) +
+				utils.CONCATENATED_KEY_SEP + strconv.FormatFloat(tier.Value, 'f', -1, 64)
+		}
+		mdls = append(mdls, mdl)
+	}
+	return
+}
+
+func APItoTaxProfile(tpTXP *utils.TPTaxProfile, timezone string) (txp *TaxProfile, err error) {
+	txp = &TaxProfile{
+		Tenant:    tpTXP.Tenant,
+		ID:        tpTXP.ID,
+		Weight:    tpTXP.Weight,
+		FilterIDs: []string{},
+		Taxes:     make([]*Tax, len(tpTXP.Taxes)),
+	}
+	for _, fli := range tpTXP.FilterIDs {
+		txp.FilterIDs = append(txp.FilterIDs, fli)
+	}
+	for i, tpTax := range tpTXP.Taxes {
+		tax := &Tax{
+			ID:    tpTax.ID,
+			Type:  tpTax.Type,
+			Value: tpTax.Value,
+		}
+		for _, tpTier := range tpTax.Tiers {
+			tax.Tiers = append(tax.Tiers, &TaxTier{Threshold: tpTier.Threshold, Value: tpTier.Value})
+		}
+		sort.Slice(tax.Tiers, func(i, j int) bool { return tax.Tiers[i].Threshold < tax.Tiers[j].Threshold })
+		txp.Taxes[i] = tax
+	}
+	if tpTXP.ActivationInterval != nil {
+		if txp.ActivationInterval, err = tpTXP.ActivationInterval.AsActivationInterval(timezone); err != nil {
+			return nil, err
+		}
+	}
+	return txp, nil
+}
+
+type TpFeeProfiles []*TpFeeProfile
+
+func (tps TpFeeProfiles) AsTPFeeProfiles() (result []*utils.TPFeeProfile) {
+	result = make([]*utils.TPFeeProfile, len(tps))
+	for i, tp := range tps {
+		result[i] = &utils.TPFeeProfile{
+			TPid:     tp.Tpid,
+			Tenant:   tp.Tenant,
+			ID:       tp.ID,
+			Period:   tp.Period,
+			Amount:   tp.Amount,
+			Category: tp.Category,
+			ProRate:  tp.ProRate,
+		}
+	}
+	return
+}
+
+func APItoModelTPFeeProfile(fep *utils.TPFeeProfile) *TpFeeProfile {
+	return &TpFeeProfile{
+		Tpid:     fep.TPid,
+		Tenant:   fep.Tenant,
+		ID:       fep.ID,
+		Period:   fep.Period,
+		Amount:   fep.Amount,
+		Category: fep.Category,
+		ProRate:  fep.ProRate,
+	}
+}
+
+func APItoFeeProfile(tpFEP *utils.TPFeeProfile) (fep *FeeProfile, err error) {
+	fep = &FeeProfile{
+		Tenant:   tpFEP.Tenant,
+		ID:       tpFEP.ID,
+		Period:   tpFEP.Period,
+		Amount:   tpFEP.Amount,
+		Category: tpFEP.Category,
+		ProRate:  tpFEP.ProRate,
+	}
+	if _, _, err = fep.PeriodBounds(time.Now()); err != nil { // validate the period
+		return nil, err
+	}
+	return
+}
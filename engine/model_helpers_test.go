@@ -42,6 +42,28 @@ func TestModelHelperCsvLoadInt(t *testing.T) {
 	}
 }
 
+func TestModelHelperCsvLoadOptional(t *testing.T) {
+	if getColumnCount(TpRatingPlan{}) != -1 {
+		t.Errorf("expecting variable number of fields for: %+v", TpRatingPlan{})
+	}
+	l, err := csvLoad(TpRatingPlan{}, []string{"RP_1", "DR_1", "*any", "10"})
+	tpd, ok := l.(TpRatingPlan)
+	if err != nil || !ok || tpd.Tag != "RP_1" || tpd.Currency != "" {
+		t.Errorf("model load failed: %+v, err: %v", tpd, err)
+	}
+	l, err = csvLoad(TpRatingPlan{}, []string{"RP_1", "DR_1", "*any", "10", "EUR"})
+	tpd, ok = l.(TpRatingPlan)
+	if err != nil || !ok || tpd.Currency != "EUR" {
+		t.Errorf("model load failed: %+v, err: %v", tpd, err)
+	}
+	if _, err = csvLoad(TpRatingPlan{}, []string{"RP_1", "DR_1", "*any"}); err == nil {
+		t.Error("expecting error for missing mandatory column")
+	}
+	if _, err = csvLoad(TpRatingPlan{}, []string{"RP_1", "DR_1", "*any", "10", "EUR", "extra"}); err == nil {
+		t.Error("expecting error for too many fields")
+	}
+}
+
 func TestModelHelperCsvDump(t *testing.T) {
 	tpd := TpDestination{
 		Tag:    "TEST_DEST",
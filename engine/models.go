@@ -71,6 +71,7 @@ type TpDestinationRate struct {
 	RoundingDecimals int     `index:"4" re:"\d+"`
 	MaxCost          float64 `index:"5" re:"\d+\.*\d*s*"`
 	MaxCostStrategy  string  `index:"6" re:"\*free|\*disconnect"`
+	VolumeCounter    string  `index:"7" re:"" optional:"true"`
 	CreatedAt        time.Time
 }
 
@@ -81,6 +82,7 @@ type TpRatingPlan struct {
 	DestratesTag string  `index:"1" re:"\w+\s*,\s*|\*any"`
 	TimingTag    string  `index:"2" re:"\w+\s*,\s*|\*any"`
 	Weight       float64 `index:"3" re:"\d+.?\d*"`
+	Currency     string  `index:"4" re:"" optional:"true"`
 	CreatedAt    time.Time
 }
 
@@ -223,6 +225,7 @@ type TpAccountAction struct {
 	ActionTriggersTag string `index:"3" re:"\w+\s*"`
 	AllowNegative     bool   `index:"4" re:""`
 	Disabled          bool   `index:"5" re:""`
+	FeeProfileIDs     string `index:"6" re:"" optional:"true"`
 	CreatedAt         time.Time
 }
 
@@ -556,3 +559,40 @@ type TPAttribute struct {
 	Weight             float64 `index:"9" re:"\d+\.?\d*"`
 	CreatedAt          time.Time
 }
+
+type TpExchangeRate struct {
+	PK             uint `gorm:"primary_key"`
+	Tpid           string
+	FromCurrency   string  `index:"0" re:""`
+	ToCurrency     string  `index:"1" re:""`
+	ActivationTime string  `index:"2" re:""`
+	Rate           float64 `index:"3" re:"\d+\.?\d*"`
+	CreatedAt      time.Time
+}
+
+type TpTaxProfile struct {
+	PK                 uint `gorm:"primary_key"`
+	Tpid               string
+	Tenant             string  `index:"0" re:""`
+	ID                 string  `index:"1" re:""`
+	FilterIDs          string  `index:"2" re:""`
+	ActivationInterval string  `index:"3" re:""`
+	TaxID              string  `index:"4" re:""`
+	TaxType            string  `index:"5" re:""`
+	TaxValue           float64 `index:"6" re:""`
+	TaxTiers           string  `index:"7" re:""`
+	Weight             float64 `index:"8" re:"\d+\.?\d*"`
+	CreatedAt          time.Time
+}
+
+type TpFeeProfile struct {
+	PK        uint `gorm:"primary_key"`
+	Tpid      string
+	Tenant    string  `index:"0" re:""`
+	ID        string  `index:"1" re:""`
+	Period    string  `index:"2" re:""`
+	Amount    float64 `index:"3" re:"\d+\.?\d*"`
+	Category  string  `index:"4" re:""`
+	ProRate   bool    `index:"5" re:""`
+	CreatedAt time.Time
+}
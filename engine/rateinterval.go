@@ -214,12 +214,20 @@ type RIRate struct {
 	RoundingDecimals int
 	MaxCost          float64
 	MaxCostStrategy  string
+	Currency         string     // currency the rates are expressed in, inherited from the RatingPlan
+	VolumeCounter    string     // when set, the GroupIntervalStart is matched against the usage cumulated by the account on this counter
 	Rates            RateGroups // GroupRateInterval (start time): Rate
 	tag              string     // loading validation only
 }
 
 func (rir *RIRate) Stringify() string {
 	str := fmt.Sprintf("%v %v %v %v %v", rir.ConnectFee, rir.RoundingMethod, rir.RoundingDecimals, rir.MaxCost, rir.MaxCostStrategy)
+	if rir.Currency != "" { // keep the tags of the plans without currency unchanged
+		str += " " + rir.Currency
+	}
+	if rir.VolumeCounter != "" {
+		str += " " + rir.VolumeCounter
+	}
 	for _, r := range rir.Rates {
 		str += r.Stringify()
 	}
@@ -30,6 +30,7 @@ The struture that is saved to storage.
 */
 type RatingPlan struct {
 	Id               string
+	Currency         string
 	Timings          map[string]*RITiming
 	Ratings          map[string]*RIRate
 	DestinationRates map[string]RPRateList
@@ -78,6 +79,9 @@ func (rp *RatingPlan) AddRateInterval(dId string, ris ...*RateInterval) {
 			rpr.Timing = timingTag
 		}
 		if ri.Rating != nil {
+			if ri.Rating.Currency == "" {
+				ri.Rating.Currency = rp.Currency
+			}
 			ratingTag := ri.Rating.Stringify()
 			rp.Ratings[ratingTag] = ri.Rating
 			rpr.Rating = ratingTag
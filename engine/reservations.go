@@ -0,0 +1,186 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package engine
+
+import (
+	"time"
+
+	"github.com/cgrates/cgrates/guardian"
+	"github.com/cgrates/cgrates/utils"
+)
+
+// Reservation holds credit out of the account balances until committed or released,
+// making it unavailable for the other debits on the same balances
+type Reservation struct {
+	ID          string
+	BalanceType string
+	Holds       []*BalanceHold // in the order the balances were reserved
+	ExpiryTime  time.Time      // zero for reservations without TTL
+}
+
+// BalanceHold is the value reserved out of one balance
+type BalanceHold struct {
+	BalanceUUID string
+	Value       float64
+}
+
+func (rsv *Reservation) IsExpired(now time.Time) bool {
+	return !rsv.ExpiryTime.IsZero() && !rsv.ExpiryTime.After(now)
+}
+
+// GetValue returns the total value still held by the reservation
+func (rsv *Reservation) GetValue() (val float64) {
+	for _, hld := range rsv.Holds {
+		val += hld.Value
+	}
+	return utils.Round(val, globalRoundingDecimals, utils.ROUNDING_MIDDLE)
+}
+
+func (rsv *Reservation) Clone() *Reservation {
+	cln := &Reservation{ID: rsv.ID, BalanceType: rsv.BalanceType,
+		ExpiryTime: rsv.ExpiryTime, Holds: make([]*BalanceHold, len(rsv.Holds))}
+	for i, hld := range rsv.Holds {
+		cln.Holds[i] = &BalanceHold{BalanceUUID: hld.BalanceUUID, Value: hld.Value}
+	}
+	return cln
+}
+
+// reservedValue returns the value held out of the balance by the active reservations
+func (acc *Account) reservedValue(balanceUUID string) (val float64) {
+	now := time.Now()
+	for _, rsv := range acc.Reservations {
+		if rsv.IsExpired(now) {
+			continue
+		}
+		for _, hld := range rsv.Holds {
+			if hld.BalanceUUID == balanceUUID {
+				val += hld.Value
+			}
+		}
+	}
+	return
+}
+
+// holdBalance reserves value out of the balances of balanceType, highest weight first
+func (acc *Account) holdBalance(rsvID, balanceType string, value float64, expiryTime time.Time) (rsv *Reservation, err error) {
+	if _, has := acc.Reservations[rsvID]; has {
+		return nil, utils.ErrExists
+	}
+	var balances Balances
+	for _, b := range acc.BalanceMap[balanceType] {
+		if !b.Disabled && !b.IsExpired() && b.IsActive() {
+			balances = append(balances, b)
+		}
+	}
+	balances.Sort()
+	rsv = &Reservation{ID: rsvID, BalanceType: balanceType, ExpiryTime: expiryTime}
+	left := value
+	for _, b := range balances {
+		if left <= 0 {
+			break
+		}
+		avail := utils.Round(b.GetValue()-acc.reservedValue(b.Uuid),
+			globalRoundingDecimals, utils.ROUNDING_MIDDLE)
+		if avail <= 0 {
+			continue
+		}
+		if avail > left {
+			avail = left
+		}
+		rsv.Holds = append(rsv.Holds, &BalanceHold{BalanceUUID: b.Uuid, Value: avail})
+		left = utils.Round(left-avail, globalRoundingDecimals, utils.ROUNDING_MIDDLE)
+	}
+	if left > 0 {
+		return nil, utils.ErrInsufficientCredit
+	}
+	if acc.Reservations == nil {
+		acc.Reservations = make(map[string]*Reservation)
+	}
+	acc.Reservations[rsvID] = rsv
+	return
+}
+
+// commitReservation debits value out of the held balances, the rest stays reserved until released
+func (acc *Account) commitReservation(rsvID string, value float64) (err error) {
+	rsv, has := acc.Reservations[rsvID]
+	if !has || rsv.IsExpired(time.Now()) {
+		return utils.ErrNotFound
+	}
+	if value > rsv.GetValue() {
+		return utils.ErrInsufficientCredit
+	}
+	left := value
+	var holds []*BalanceHold
+	for _, hld := range rsv.Holds {
+		if left > 0 {
+			debit := hld.Value
+			if debit > left {
+				debit = left
+			}
+			if b := acc.BalanceMap[rsv.BalanceType].GetBalance(hld.BalanceUUID); b != nil {
+				b.SubstractValue(debit)
+				left = utils.Round(left-debit, globalRoundingDecimals, utils.ROUNDING_MIDDLE)
+			}
+			hld.Value = utils.Round(hld.Value-debit, globalRoundingDecimals, utils.ROUNDING_MIDDLE)
+		}
+		if hld.Value > 0 {
+			holds = append(holds, hld)
+		}
+	}
+	if left > 0 { // balances removed meanwhile, debit the rest out of the default one
+		if rsv.BalanceType != utils.MONETARY {
+			return utils.ErrInsufficientCredit
+		}
+		acc.GetDefaultMoneyBalance().SubstractValue(left)
+	}
+	if rsv.Holds = holds; len(rsv.Holds) == 0 {
+		delete(acc.Reservations, rsvID)
+	}
+	acc.publishExhaustedBalances()
+	acc.ExecuteActionTriggers(nil)
+	return
+}
+
+// releaseReservation frees the value still held by the reservation
+func (acc *Account) releaseReservation(rsvID string) (err error) {
+	if _, has := acc.Reservations[rsvID]; !has {
+		return utils.ErrNotFound
+	}
+	delete(acc.Reservations, rsvID)
+	return
+}
+
+// updateAccountReservations runs f on the account under lock, saving the account afterwards
+func updateAccountReservations(tenant, account string, f func(acc *Account) error) (err error) {
+	accID := utils.ConcatenatedKey(tenant, account)
+	_, err = guardian.Guardian.Guard(func() (interface{}, error) {
+		acc, err := dm.DataDB().GetAccount(accID)
+		if err != nil {
+			return nil, err
+		}
+		if acc.Disabled {
+			return nil, utils.ErrAccountDisabled
+		}
+		if err = f(acc); err != nil {
+			return nil, err
+		}
+		return nil, dm.DataDB().SetAccount(acc)
+	}, 0, utils.ACCOUNT_PREFIX+accID)
+	return
+}
@@ -0,0 +1,199 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package engine
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cgrates/cgrates/utils"
+)
+
+func TestAccountHoldBalance(t *testing.T) {
+	acc := &Account{ID: "cgrates.org:rsv", BalanceMap: map[string]Balances{
+		utils.MONETARY: Balances{
+			&Balance{Uuid: "low", Value: 10, Weight: 10},
+			&Balance{Uuid: "high", Value: 20, Weight: 20},
+		}}}
+	rsv, err := acc.holdBalance("RSV1", utils.MONETARY, 25, time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rsv.Holds) != 2 || rsv.Holds[0].BalanceUUID != "high" || rsv.Holds[0].Value != 20 ||
+		rsv.Holds[1].BalanceUUID != "low" || rsv.Holds[1].Value != 5 {
+		t.Errorf("Unexpected holds: %s", utils.ToJSON(rsv.Holds))
+	}
+	if _, err := acc.holdBalance("RSV1", utils.MONETARY, 1, time.Time{}); err != utils.ErrExists {
+		t.Errorf("Expecting: %v, received: %v", utils.ErrExists, err)
+	}
+	if _, err := acc.holdBalance("RSV2", utils.MONETARY, 6, time.Time{}); err != utils.ErrInsufficientCredit {
+		t.Errorf("Expecting: %v, received: %v", utils.ErrInsufficientCredit, err)
+	}
+	if _, err := acc.holdBalance("RSV2", utils.MONETARY, 5, time.Time{}); err != nil {
+		t.Error(err)
+	}
+	// expired reservations do not hold credit anymore
+	acc.Reservations["RSV2"].ExpiryTime = time.Now().Add(-time.Second)
+	if val := acc.reservedValue("low"); val != 5 {
+		t.Errorf("Expecting 5, received: %v", val)
+	}
+	acc.CleanExpiredStuff()
+	if _, has := acc.Reservations["RSV2"]; has {
+		t.Error("Expired reservation not removed")
+	}
+}
+
+func TestAccountCommitReservation(t *testing.T) {
+	acc := &Account{ID: "cgrates.org:rsv", BalanceMap: map[string]Balances{
+		utils.MONETARY: Balances{
+			&Balance{Uuid: "low", Value: 10, Weight: 10},
+			&Balance{Uuid: "high", Value: 20, Weight: 20},
+		}}}
+	if _, err := acc.holdBalance("RSV1", utils.MONETARY, 25, time.Time{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := acc.commitReservation("RSV1", 30); err != utils.ErrInsufficientCredit {
+		t.Errorf("Expecting: %v, received: %v", utils.ErrInsufficientCredit, err)
+	}
+	if err := acc.commitReservation("RSV1", 22); err != nil {
+		t.Fatal(err)
+	}
+	if high, low := acc.BalanceMap[utils.MONETARY].GetBalance("high").GetValue(),
+		acc.BalanceMap[utils.MONETARY].GetBalance("low").GetValue(); high != 0 || low != 8 {
+		t.Errorf("Unexpected balance values, high: %v, low: %v", high, low)
+	}
+	if val := acc.Reservations["RSV1"].GetValue(); val != 3 {
+		t.Errorf("Expecting 3 still reserved, received: %v", val)
+	}
+	if err := acc.releaseReservation("RSV1"); err != nil {
+		t.Error(err)
+	}
+	if len(acc.Reservations) != 0 {
+		t.Errorf("Unexpected reservations: %s", utils.ToJSON(acc.Reservations))
+	}
+	if err := acc.commitReservation("RSV1", 1); err != utils.ErrNotFound {
+		t.Errorf("Expecting: %v, received: %v", utils.ErrNotFound, err)
+	}
+}
+
+func TestAccountDebitWithReservation(t *testing.T) {
+	cc := &CallCost{
+		Direction:   utils.OUT,
+		Destination: "0723045326",
+		Timespans: []*TimeSpan{
+			&TimeSpan{
+				TimeStart:    time.Date(2013, 9, 24, 10, 48, 0, 0, time.UTC),
+				TimeEnd:      time.Date(2013, 9, 24, 10, 49, 0, 0, time.UTC),
+				RateInterval: &RateInterval{Rating: &RIRate{Rates: RateGroups{&Rate{GroupIntervalStart: 0, Value: 1, RateIncrement: 10 * time.Second, RateUnit: time.Second}}}},
+			},
+		},
+		TOR: utils.VOICE,
+	}
+	cd := &CallDescriptor{
+		TimeStart:    cc.Timespans[0].TimeStart,
+		TimeEnd:      cc.Timespans[0].TimeEnd,
+		Direction:    cc.Direction,
+		Destination:  cc.Destination,
+		TOR:          cc.TOR,
+		testCallcost: cc,
+	}
+	acc := &Account{ID: "cgrates.org:rsv", BalanceMap: map[string]Balances{
+		utils.MONETARY: Balances{&Balance{Uuid: "money", Value: 50}},
+	}}
+	if _, err := acc.holdBalance("RSV1", utils.MONETARY, 30, time.Time{}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := acc.debitCreditBalance(cd, false, false, false); err == nil {
+		t.Error("Expecting not enough credit error")
+	}
+	if val := acc.BalanceMap[utils.MONETARY][0].GetValue(); val != 30 {
+		t.Errorf("Debit should not touch the reserved credit, balance value: %v", val)
+	}
+}
+
+func TestReservationMaxSessionDuration(t *testing.T) {
+	acc := &Account{ID: "vdf:rsvmax", BalanceMap: map[string]Balances{
+		utils.MONETARY: Balances{&Balance{Uuid: "rsvmax_money", Value: 50, Weight: 10}},
+	}}
+	if err := dm.DataDB().SetAccount(acc); err != nil {
+		t.Fatal(err)
+	}
+	cd := &CallDescriptor{
+		TimeStart:   time.Date(2013, 10, 21, 18, 34, 0, 0, time.UTC),
+		TimeEnd:     time.Date(2013, 10, 21, 18, 44, 0, 0, time.UTC),
+		Direction:   utils.OUT,
+		Category:    "0",
+		Tenant:      "vdf",
+		Subject:     "rif",
+		Account:     "rsvmax",
+		Destination: "0723",
+		TOR:         utils.VOICE,
+	}
+	if result, err := cd.Clone().GetMaxSessionDuration(); err != nil {
+		t.Fatal(err)
+	} else if result != 100*time.Second {
+		t.Errorf("Expecting 1m40s, received: %v", result)
+	}
+	if _, err := acc.holdBalance("RSV1", utils.MONETARY, 30, time.Time{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := dm.DataDB().SetAccount(acc); err != nil {
+		t.Fatal(err)
+	}
+	// only the 20 left out of the reservation are offered to the session
+	if _, credit, _ := acc.getCreditForPrefix(cd.Clone()); credit != 20 {
+		t.Errorf("Expecting 20 credit, received: %v", credit)
+	}
+	if result, err := cd.Clone().GetMaxSessionDuration(); err != nil {
+		t.Fatal(err)
+	} else if result != 40*time.Second {
+		t.Errorf("Expecting 40s, received: %v", result)
+	}
+}
+
+func TestReservationConnectFee(t *testing.T) {
+	acc := &Account{ID: "cgrates.org:rsvfee", BalanceMap: map[string]Balances{
+		utils.MONETARY: Balances{
+			&Balance{Uuid: "high", Value: 10, Weight: 20},
+			&Balance{Uuid: "low", Value: 10, Weight: 10},
+		},
+	}}
+	if _, err := acc.holdBalance("RSV1", utils.MONETARY, 8, time.Time{}); err != nil {
+		t.Fatal(err)
+	}
+	for _, b := range acc.BalanceMap[utils.MONETARY] {
+		b.reserved = acc.reservedValue(b.Uuid)
+	}
+	cc := &CallCost{
+		Timespans: TimeSpans{
+			&TimeSpan{RateInterval: &RateInterval{Rating: &RIRate{ConnectFee: 5}}},
+		},
+		deductConnectFee: true,
+	}
+	if ok, _ := acc.DebitConnectionFee(cc, acc.BalanceMap[utils.MONETARY], false, false); !ok {
+		t.Fatal("Connect fee not debited")
+	}
+	// the high balance has only 2 available out of the reservation
+	if val := acc.BalanceMap[utils.MONETARY][0].GetValue(); val != 10 {
+		t.Errorf("Connect fee paid out of the reserved credit, balance value: %v", val)
+	}
+	if val := acc.BalanceMap[utils.MONETARY][1].GetValue(); val != 5 {
+		t.Errorf("Expecting 5 left, received: %v", val)
+	}
+}
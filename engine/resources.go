@@ -305,6 +305,28 @@ type ResourceService struct {
 	stopBackup       chan struct{}                // control storing process
 }
 
+// PromMetrics exports the usage of each stored Resource
+func (rS *ResourceService) PromMetrics() []*utils.PromMetric {
+	pm := &utils.PromMetric{Name: utils.PromNamespace + "_resource_usage",
+		Help: "Units allocated out of each Resource", Type: utils.PromGauge}
+	keys, err := rS.dm.DataDB().GetKeysForPrefix(utils.ResourcesPrefix)
+	if err != nil {
+		utils.Logger.Warning(fmt.Sprintf("<ResourceS> error: %s querying Resource keys for metrics", err.Error()))
+		return nil
+	}
+	for _, key := range keys {
+		guardian.Guardian.GuardIDs(config.CgrConfig().LockingTimeout, key)
+		tntID := utils.NewTenantID(key[len(utils.ResourcesPrefix):])
+		if r, err := rS.dm.GetResource(tntID.Tenant, tntID.ID, false, utils.NonTransactional); err == nil {
+			pm.Samples = append(pm.Samples, &utils.PromSample{
+				Labels: map[string]string{"tenant": r.Tenant, "resource": r.ID},
+				Value:  r.totalUsage()})
+		}
+		guardian.Guardian.UnguardIDs(key)
+	}
+	return []*utils.PromMetric{pm}
+}
+
 // Called to start the service
 func (rS *ResourceService) ListenAndServe(exitChan chan bool) error {
 	go rS.runBackup() // start backup loop
@@ -530,6 +552,22 @@ func (rS *ResourceService) V1ResourcesForEvent(args utils.ArgRSv1ResourceUsage,
 	return
 }
 
+// V1GetResource returns a resource with its current usages
+func (rS *ResourceService) V1GetResource(args *utils.TenantID, reply *Resource) (err error) {
+	if missing := utils.MissingStructFields(args, []string{"Tenant", "ID"}); len(missing) != 0 { //Params missing
+		return utils.NewErrMandatoryIeMissing(missing...)
+	}
+	r, err := rS.dm.GetResource(args.Tenant, args.ID, false, "")
+	if err != nil {
+		if err != utils.ErrNotFound {
+			err = utils.NewErrServerError(err)
+		}
+		return err
+	}
+	*reply = *r
+	return
+}
+
 // V1AllowUsage queries service to find if an Usage is allowed
 func (rS *ResourceService) V1AllowUsage(args utils.ArgRSv1ResourceUsage, allow *bool) (err error) {
 	if missing := utils.MissingStructFields(&args, []string{"CGREvent.Tenant", "UsageID"}); len(missing) != 0 { //Params missing
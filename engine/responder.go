@@ -667,6 +667,82 @@ func (rs *Responder) GetLCR(attrs *AttrGetLcr, reply *LCRCost) (err error) {
 	return nil
 }
 
+type AttrHoldBalance struct {
+	Tenant        string
+	Account       string
+	ReservationID string
+	BalanceType   string        // defaults to *monetary
+	Value         float64       // amount or units to hold
+	TTL           time.Duration // zero for reservations without expiry
+}
+
+// HoldBalance reserves credit on the account, the held value is not available for the other debits until released
+func (rs *Responder) HoldBalance(arg *AttrHoldBalance, reply *Reservation) (err error) {
+	if missing := utils.MissingStructFields(arg, []string{"Tenant", "Account", "ReservationID"}); len(missing) != 0 {
+		return utils.NewErrMandatoryIeMissing(missing...)
+	}
+	if arg.Value <= 0 {
+		return utils.NewErrMandatoryIeMissing("Value")
+	}
+	if arg.BalanceType == "" {
+		arg.BalanceType = utils.MONETARY
+	}
+	var expiryTime time.Time
+	if arg.TTL != 0 {
+		expiryTime = time.Now().Add(arg.TTL)
+	}
+	var rsv *Reservation
+	if err = updateAccountReservations(arg.Tenant, arg.Account, func(acc *Account) (err error) {
+		rsv, err = acc.holdBalance(arg.ReservationID, arg.BalanceType, arg.Value, expiryTime)
+		return
+	}); err != nil {
+		return
+	}
+	*reply = *rsv
+	return
+}
+
+type AttrCommitReservation struct {
+	Tenant        string
+	Account       string
+	ReservationID string
+	Value         float64 // part of the reservation to debit
+}
+
+// CommitReservation debits part of the held credit, the rest stays reserved until released
+func (rs *Responder) CommitReservation(arg *AttrCommitReservation, reply *string) (err error) {
+	if missing := utils.MissingStructFields(arg, []string{"Tenant", "Account", "ReservationID"}); len(missing) != 0 {
+		return utils.NewErrMandatoryIeMissing(missing...)
+	}
+	if err = updateAccountReservations(arg.Tenant, arg.Account, func(acc *Account) error {
+		return acc.commitReservation(arg.ReservationID, arg.Value)
+	}); err != nil {
+		return
+	}
+	*reply = utils.OK
+	return
+}
+
+type AttrReleaseReservation struct {
+	Tenant        string
+	Account       string
+	ReservationID string
+}
+
+// ReleaseReservation frees the credit still held by the reservation
+func (rs *Responder) ReleaseReservation(arg *AttrReleaseReservation, reply *string) (err error) {
+	if missing := utils.MissingStructFields(arg, []string{"Tenant", "Account", "ReservationID"}); len(missing) != 0 {
+		return utils.NewErrMandatoryIeMissing(missing...)
+	}
+	if err = updateAccountReservations(arg.Tenant, arg.Account, func(acc *Account) error {
+		return acc.releaseReservation(arg.ReservationID)
+	}); err != nil {
+		return
+	}
+	*reply = utils.OK
+	return
+}
+
 func (rs *Responder) Status(arg string, reply *map[string]interface{}) (err error) {
 	if arg != "" { // Introduce  delay in answer, used in some automated tests
 		if delay, err := utils.ParseDurationWithNanosecs(arg); err == nil {
@@ -662,3 +662,33 @@ func TestResponderGobSMCost(t *testing.T) {
 		t.Error("wrong transmission")
 	}
 }
+
+func TestResponderReservations(t *testing.T) {
+	acc := &Account{ID: "cgrates.org:rsv_responder", BalanceMap: map[string]Balances{
+		utils.MONETARY: Balances{&Balance{Uuid: "rsv_money", Value: 10}},
+	}}
+	if err := dm.DataDB().SetAccount(acc); err != nil {
+		t.Fatal(err)
+	}
+	var rsv Reservation
+	if err := rsponder.HoldBalance(&AttrHoldBalance{Tenant: "cgrates.org", Account: "rsv_responder",
+		ReservationID: "RSV1", Value: 4, TTL: time.Hour}, &rsv); err != nil {
+		t.Fatal(err)
+	} else if rsv.GetValue() != 4 || rsv.ExpiryTime.IsZero() {
+		t.Errorf("Unexpected reservation: %s", utils.ToJSON(rsv))
+	}
+	var reply string
+	if err := rsponder.CommitReservation(&AttrCommitReservation{Tenant: "cgrates.org", Account: "rsv_responder",
+		ReservationID: "RSV1", Value: 1.5}, &reply); err != nil {
+		t.Fatal(err)
+	}
+	if err := rsponder.ReleaseReservation(&AttrReleaseReservation{Tenant: "cgrates.org", Account: "rsv_responder",
+		ReservationID: "RSV1"}, &reply); err != nil {
+		t.Fatal(err)
+	}
+	if acc, err := dm.DataDB().GetAccount("cgrates.org:rsv_responder"); err != nil {
+		t.Error(err)
+	} else if val := acc.BalanceMap[utils.MONETARY][0].GetValue(); val != 8.5 || len(acc.Reservations) != 0 {
+		t.Errorf("Unexpected account: %s", utils.ToJSON(acc))
+	}
+}
@@ -0,0 +1,85 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package engine
+
+import (
+	"fmt"
+
+	"github.com/cgrates/cgrates/utils"
+)
+
+func NewHighestQualitySorter(spS *SupplierService) *HighestQualitySorter {
+	return &HighestQualitySorter{spS: spS,
+		sorting: utils.MetaHighestQuality}
+}
+
+// HighestQualitySorter sorts suppliers based on StatS metrics
+// sortingParams: [metricID, direction], defaults to [*asr, *desc]
+type HighestQualitySorter struct {
+	sorting string
+	spS     *SupplierService
+}
+
+// SortSuppliers sorts with the default parameters
+func (hqs *HighestQualitySorter) SortSuppliers(prflID string,
+	suppls []*Supplier, ev *utils.CGREvent) (sortedSuppls *SortedSuppliers, err error) {
+	return hqs.SortSuppliersWithParams(prflID, suppls, ev, nil)
+}
+
+func (hqs *HighestQualitySorter) SortSuppliersWithParams(prflID string,
+	suppls []*Supplier, ev *utils.CGREvent, sortingParams []string) (sortedSuppls *SortedSuppliers, err error) {
+	metricID := utils.MetaASR
+	ascending := false
+	if len(sortingParams) > 0 && sortingParams[0] != "" {
+		metricID = sortingParams[0]
+	}
+	if len(sortingParams) > 1 {
+		switch sortingParams[1] {
+		case utils.MetaAscending:
+			ascending = true
+		case utils.MetaDescending:
+		default:
+			return nil, fmt.Errorf("unsupported sorting direction: %s", sortingParams[1])
+		}
+	}
+	sortedSuppls = &SortedSuppliers{ProfileID: prflID,
+		Sorting:         hqs.sorting,
+		SortedSuppliers: make([]*SortedSupplier, len(suppls))}
+	for i, s := range suppls {
+		srtData := map[string]interface{}{
+			utils.Weight: s.Weight,
+		}
+		metrics, err := hqs.spS.statMetrics(ev.Tenant, s.StatIDs, []string{metricID})
+		if err != nil {
+			return nil, err
+		}
+		if val, has := metrics[metricID]; has {
+			srtData[metricID] = val
+		} else {
+			utils.Logger.Warning(
+				fmt.Sprintf("<%s> profile: %s, supplier with ID: %s, missing metric: %s",
+					utils.SupplierS, prflID, s.ID, metricID))
+		}
+		sortedSuppls.SortedSuppliers[i] = &SortedSupplier{
+			SupplierID:  s.ID,
+			SortingData: srtData}
+	}
+	sortedSuppls.SortQuality(metricID, ascending)
+	return
+}
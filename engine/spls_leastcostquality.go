@@ -0,0 +1,147 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package engine
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/cgrates/cgrates/utils"
+)
+
+func NewLeastCostWithQualitySorter(spS *SupplierService) *LeastCostWithQualitySorter {
+	return &LeastCostWithQualitySorter{spS: spS,
+		sorting: utils.MetaLeastCostWithQuality}
+}
+
+// LeastCostWithQualitySorter sorts suppliers based on their cost,
+// dropping the ones with StatS metrics below quality floor
+// sortingParams: list of MetricID:Floor[:*asc], *asc meaning lower values are better, ie: *asr:50 or *pdd:3:*asc
+type LeastCostWithQualitySorter struct {
+	sorting string
+	spS     *SupplierService
+}
+
+// qualityFloor is the minimum quality requested for one metric
+type qualityFloor struct {
+	metricID  string
+	value     float64
+	ascending bool
+}
+
+// passes checks the metric value against the floor
+func (qf *qualityFloor) passes(val float64) bool {
+	if qf.ascending {
+		return val <= qf.value
+	}
+	return val >= qf.value
+}
+
+// parseQualityFloors converts sortingParams into list of qualityFloor
+func parseQualityFloors(sortingParams []string) (qfs []*qualityFloor, err error) {
+	for _, param := range sortingParams {
+		splt := strings.Split(param, utils.CONCATENATED_KEY_SEP)
+		if len(splt) < 2 || len(splt) > 3 {
+			return nil, fmt.Errorf("invalid quality floor: %s", param)
+		}
+		qf := &qualityFloor{metricID: splt[0]}
+		if qf.value, err = strconv.ParseFloat(splt[1], 64); err != nil {
+			return nil, fmt.Errorf("invalid quality floor: %s", param)
+		}
+		if len(splt) == 3 {
+			switch splt[2] {
+			case utils.MetaAscending:
+				qf.ascending = true
+			case utils.MetaDescending:
+			default:
+				return nil, fmt.Errorf("invalid quality floor: %s", param)
+			}
+		}
+		qfs = append(qfs, qf)
+	}
+	return
+}
+
+// SortSuppliers sorts with the default parameters
+func (lcq *LeastCostWithQualitySorter) SortSuppliers(prflID string,
+	suppls []*Supplier, ev *utils.CGREvent) (sortedSuppls *SortedSuppliers, err error) {
+	return lcq.SortSuppliersWithParams(prflID, suppls, ev, nil)
+}
+
+func (lcq *LeastCostWithQualitySorter) SortSuppliersWithParams(prflID string,
+	suppls []*Supplier, ev *utils.CGREvent, sortingParams []string) (sortedSuppls *SortedSuppliers, err error) {
+	qfs, err := parseQualityFloors(sortingParams)
+	if err != nil {
+		return nil, err
+	}
+	metricIDs := make([]string, len(qfs))
+	for i, qf := range qfs {
+		metricIDs[i] = qf.metricID
+	}
+	sortedSuppls = &SortedSuppliers{ProfileID: prflID,
+		Sorting:         lcq.sorting,
+		SortedSuppliers: make([]*SortedSupplier, 0)}
+	for _, s := range suppls {
+		srtData := map[string]interface{}{
+			utils.Weight: s.Weight,
+		}
+		if len(qfs) != 0 {
+			metrics, err := lcq.spS.statMetrics(ev.Tenant, s.StatIDs, metricIDs)
+			if err != nil {
+				return nil, err
+			}
+			var belowFloor bool
+			for _, qf := range qfs {
+				val, has := metrics[qf.metricID]
+				if !has { // no quality information, cannot drop
+					continue
+				}
+				srtData[qf.metricID] = val
+				if !qf.passes(val) {
+					belowFloor = true
+					break
+				}
+			}
+			if belowFloor {
+				utils.Logger.Info(
+					fmt.Sprintf("<%s> profile: %s ignoring supplier with ID: %s, quality below floor",
+						utils.SupplierS, prflID, s.ID))
+				continue
+			}
+		}
+		costData, err := lcq.spS.costForEvent(ev, s.AccountIDs, s.RatingPlanIDs)
+		if err != nil {
+			return nil, err
+		} else if len(costData) == 0 {
+			utils.Logger.Warning(
+				fmt.Sprintf("<%s> profile: %s ignoring supplier with ID: %s, missing cost information",
+					utils.SupplierS, prflID, s.ID))
+			continue
+		}
+		for k, v := range costData {
+			srtData[k] = v
+		}
+		sortedSuppls.SortedSuppliers = append(sortedSuppls.SortedSuppliers, &SortedSupplier{
+			SupplierID:  s.ID,
+			SortingData: srtData})
+	}
+	sortedSuppls.SortCost()
+	return
+}
@@ -0,0 +1,97 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package engine
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/cgrates/cgrates/utils"
+)
+
+func NewLoadDistributionSorter(spS *SupplierService) *LoadDistributionSorter {
+	return &LoadDistributionSorter{spS: spS,
+		sorting: utils.MetaLoadDistribution}
+}
+
+// LoadDistributionSorter sorts suppliers based on their ResourceS usage compared to configured ratio
+// sortingParams: list of SupplierID:Ratio, with *default:Ratio used for the suppliers not listed
+type LoadDistributionSorter struct {
+	sorting string
+	spS     *SupplierService
+}
+
+// parseLoadRatios converts sortingParams into map[SupplierID]Ratio
+func parseLoadRatios(sortingParams []string) (ratios map[string]float64, err error) {
+	ratios = map[string]float64{utils.META_DEFAULT: 1}
+	for _, param := range sortingParams {
+		splRatio := strings.Split(param, utils.CONCATENATED_KEY_SEP)
+		if len(splRatio) != 2 {
+			return nil, fmt.Errorf("invalid load ratio: %s", param)
+		}
+		if ratios[splRatio[0]], err = strconv.ParseFloat(splRatio[1], 64); err != nil {
+			return nil, fmt.Errorf("invalid load ratio: %s", param)
+		}
+	}
+	return
+}
+
+// SortSuppliers sorts with the default parameters
+func (lds *LoadDistributionSorter) SortSuppliers(prflID string,
+	suppls []*Supplier, ev *utils.CGREvent) (sortedSuppls *SortedSuppliers, err error) {
+	return lds.SortSuppliersWithParams(prflID, suppls, ev, nil)
+}
+
+func (lds *LoadDistributionSorter) SortSuppliersWithParams(prflID string,
+	suppls []*Supplier, ev *utils.CGREvent, sortingParams []string) (sortedSuppls *SortedSuppliers, err error) {
+	ratios, err := parseLoadRatios(sortingParams)
+	if err != nil {
+		return nil, err
+	}
+	sortedSuppls = &SortedSuppliers{ProfileID: prflID,
+		Sorting:         lds.sorting,
+		SortedSuppliers: make([]*SortedSupplier, 0)}
+	for _, s := range suppls {
+		ratio, has := ratios[s.ID]
+		if !has {
+			ratio = ratios[utils.META_DEFAULT]
+		}
+		if ratio <= 0 {
+			utils.Logger.Warning(
+				fmt.Sprintf("<%s> profile: %s ignoring supplier with ID: %s, ratio: %v",
+					utils.SupplierS, prflID, s.ID, ratio))
+			continue
+		}
+		usage, err := lds.spS.resourceUsage(ev.Tenant, s.ResourceIDs)
+		if err != nil {
+			return nil, err
+		}
+		sortedSuppls.SortedSuppliers = append(sortedSuppls.SortedSuppliers, &SortedSupplier{
+			SupplierID: s.ID,
+			SortingData: map[string]interface{}{
+				utils.Weight:        s.Weight,
+				utils.ResourceUsage: usage,
+				utils.Ratio:         ratio,
+				utils.Load:          usage / ratio,
+			}})
+	}
+	sortedSuppls.SortLoad()
+	return
+}
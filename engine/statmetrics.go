@@ -20,10 +20,14 @@ package engine
 
 import (
 	"fmt"
-	"github.com/cgrates/cgrates/config"
-	"github.com/cgrates/cgrates/utils"
+	"math"
+	"sort"
 	"strconv"
+	"strings"
 	"time"
+
+	"github.com/cgrates/cgrates/config"
+	"github.com/cgrates/cgrates/utils"
 )
 
 // NewStatMetric instantiates the StatMetric
@@ -38,10 +42,34 @@ func NewStatMetric(metricID string, minItems int) (sm StatMetric, err error) {
 		utils.MetaPDD: NewPDD,
 		utils.MetaDDC: NewDCC,
 	}
-	if _, has := metrics[metricID]; !has {
-		return nil, fmt.Errorf("unsupported metric: %s", metricID)
+	if _, has := metrics[metricID]; has {
+		return metrics[metricID](minItems)
 	}
-	return metrics[metricID](minItems)
+	// field metrics read their value out of the event field following the separator, ie: *sum#Usage
+	fieldMetrics := map[string]func(int, string) (StatMetric, error){
+		utils.MetaSum:      NewStatSum,
+		utils.MetaAverage:  NewStatAverage,
+		utils.MetaMax:      NewStatMax,
+		utils.MetaMin:      NewStatMin,
+		utils.MetaDistinct: NewStatDistinct,
+	}
+	if metricFld := strings.SplitN(metricID, utils.HashtagSep, 2); len(metricFld) == 2 {
+		if _, has := fieldMetrics[metricFld[0]]; has {
+			if metricFld[1] == "" {
+				return nil, fmt.Errorf("missing field name for metric: %s", metricID)
+			}
+			return fieldMetrics[metricFld[0]](minItems, metricFld[1])
+		}
+	}
+	// distribution metrics carry their parameters inside metricID, ie: *p95_acd, *median_cost, *histogram_pdd#1s&3s
+	switch {
+	case strings.HasPrefix(metricID, utils.MetaHistogram+utils.UnderscoreSep):
+		return NewStatHistogram(metricID, minItems)
+	case strings.HasPrefix(metricID, utils.MetaMedian+utils.UnderscoreSep),
+		strings.HasPrefix(metricID, utils.MetaPercentile):
+		return NewStatPercentile(metricID, minItems)
+	}
+	return nil, fmt.Errorf("unsupported metric: %s", metricID)
 }
 
 // StatMetric is the interface which a metric should implement
@@ -641,3 +669,830 @@ func (ddc *StatDDC) Marshal(ms Marshaler) (marshaled []byte, err error) {
 func (ddc *StatDDC) LoadMarshaled(ms Marshaler, marshaled []byte) (err error) {
 	return ms.Unmarshal(marshaled, ddc)
 }
+
+// distribution sources, used by percentile and histogram metrics
+const (
+	statSourceACD  = "acd"  // Usage of answered events
+	statSourcePDD  = "pdd"  // PDD of events
+	statSourceCost = "cost" // Cost of answered events
+)
+
+// statSourceIsDuration returns true if the values out of source are time.Duration
+func statSourceIsDuration(source string) bool {
+	return source != statSourceCost
+}
+
+// statSourceValue extracts the value for source out of event
+// returns utils.ErrNotFound if the event should not be considered in distribution
+func statSourceValue(source string, ev *utils.CGREvent) (val float64, err error) {
+	if source != statSourcePDD {
+		var at time.Time
+		if at, err = ev.FieldAsTime(utils.AnswerTime,
+			config.CgrConfig().DefaultTimezone); err != nil {
+			return
+		} else if at.IsZero() {
+			return 0, utils.ErrNotFound
+		}
+	}
+	switch source {
+	case statSourceACD, statSourcePDD:
+		fldName := utils.Usage
+		if source == statSourcePDD {
+			fldName = utils.PDD
+		}
+		var dur time.Duration
+		if dur, err = ev.FieldAsDuration(fldName); err != nil {
+			return
+		}
+		return float64(dur.Nanoseconds()), nil
+	case statSourceCost:
+		if val, err = ev.FieldAsFloat64(utils.COST); err != nil {
+			return
+		} else if val < 0 {
+			return 0, utils.ErrNotFound
+		}
+		return
+	}
+	return 0, fmt.Errorf("unsupported source: %s", source)
+}
+
+// parseStatSource validates the source of a distribution metric
+func parseStatSource(metricID, source string) (err error) {
+	switch source {
+	case statSourceACD, statSourcePDD, statSourceCost:
+		return
+	}
+	return fmt.Errorf("unsupported metric: %s", metricID)
+}
+
+// NewStatPercentile builds the percentile metric out of metricID
+// accepted formats: *p<percentile>_<source> or *median_<source>, ie: *p95_acd, *median_cost
+func NewStatPercentile(metricID string, minItems int) (StatMetric, error) {
+	var percentile float64
+	var source string
+	if strings.HasPrefix(metricID, utils.MetaMedian+utils.UnderscoreSep) {
+		percentile = 50
+		source = strings.TrimPrefix(metricID, utils.MetaMedian+utils.UnderscoreSep)
+	} else {
+		prcntSrc := strings.SplitN(strings.TrimPrefix(metricID, utils.MetaPercentile),
+			utils.UnderscoreSep, 2)
+		if len(prcntSrc) != 2 {
+			return nil, fmt.Errorf("unsupported metric: %s", metricID)
+		}
+		var err error
+		if percentile, err = strconv.ParseFloat(prcntSrc[0], 64); err != nil ||
+			percentile <= 0 || percentile > 100 {
+			return nil, fmt.Errorf("unsupported metric: %s", metricID)
+		}
+		source = prcntSrc[1]
+	}
+	if err := parseStatSource(metricID, source); err != nil {
+		return nil, err
+	}
+	return &StatPercentile{Source: source, Percentile: percentile,
+		Events: make(map[string]float64), Ignored: make(utils.StringMap),
+		MinItems: minItems}, nil
+}
+
+// StatPercentile implements percentile metrics (median included) over one source
+type StatPercentile struct {
+	Source     string
+	Percentile float64
+	Events     map[string]float64 // map[EventTenantID]Value, durations as nanoseconds
+	Ignored    utils.StringMap    // events not matching the source, ie: unanswered
+	MinItems   int
+	val        *float64 // cached percentile value
+}
+
+// getValue returns the cached percentile value, computing it if needed
+func (sp *StatPercentile) getValue() float64 {
+	if sp.val == nil {
+		if (sp.MinItems > 0 && len(sp.Events)+len(sp.Ignored) < sp.MinItems) ||
+			len(sp.Events) == 0 {
+			sp.val = utils.Float64Pointer(STATS_NA)
+		} else {
+			vals := make([]float64, 0, len(sp.Events))
+			for _, val := range sp.Events {
+				vals = append(vals, val)
+			}
+			sort.Float64s(vals)
+			// linear interpolation between closest ranks
+			pos := sp.Percentile / 100 * float64(len(vals)-1)
+			lIdx := int(math.Floor(pos))
+			hIdx := int(math.Ceil(pos))
+			val := vals[lIdx] + (vals[hIdx]-vals[lIdx])*(pos-float64(lIdx))
+			if statSourceIsDuration(sp.Source) {
+				val = math.Floor(val)
+			} else {
+				val = utils.Round(val, config.CgrConfig().RoundingDecimals,
+					utils.ROUNDING_MIDDLE)
+			}
+			sp.val = utils.Float64Pointer(val)
+		}
+	}
+	return *sp.val
+}
+
+func (sp *StatPercentile) GetValue() (v interface{}) {
+	val := sp.getValue()
+	if statSourceIsDuration(sp.Source) {
+		if val == STATS_NA {
+			return time.Duration((-1) * time.Nanosecond)
+		}
+		return time.Duration(int64(val))
+	}
+	return val
+}
+
+func (sp *StatPercentile) GetStringValue(fmtOpts string) (valStr string) {
+	val := sp.getValue()
+	switch {
+	case val == STATS_NA:
+		valStr = utils.NOT_AVAILABLE
+	case statSourceIsDuration(sp.Source):
+		valStr = fmt.Sprintf("%+v", time.Duration(int64(val)))
+	default:
+		valStr = strconv.FormatFloat(val, 'f', -1, 64)
+	}
+	return
+}
+
+func (sp *StatPercentile) GetFloat64Value() (v float64) {
+	if v = sp.getValue(); v != STATS_NA && statSourceIsDuration(sp.Source) {
+		v = time.Duration(int64(v)).Seconds()
+	}
+	return
+}
+
+func (sp *StatPercentile) AddEvent(ev *utils.CGREvent) (err error) {
+	var val float64
+	if val, err = statSourceValue(sp.Source, ev); err != nil {
+		if err != utils.ErrNotFound {
+			return
+		}
+		sp.Ignored[ev.TenantID()] = true
+		delete(sp.Events, ev.TenantID())
+		err = nil
+	} else {
+		sp.Events[ev.TenantID()] = val
+		delete(sp.Ignored, ev.TenantID())
+	}
+	sp.val = nil
+	return
+}
+
+func (sp *StatPercentile) RemEvent(evTenantID string) (err error) {
+	if _, has := sp.Events[evTenantID]; has {
+		delete(sp.Events, evTenantID)
+	} else if _, has := sp.Ignored[evTenantID]; has {
+		delete(sp.Ignored, evTenantID)
+	} else {
+		return utils.ErrNotFound
+	}
+	sp.val = nil
+	return
+}
+
+func (sp *StatPercentile) Marshal(ms Marshaler) (marshaled []byte, err error) {
+	return ms.Marshal(sp)
+}
+
+func (sp *StatPercentile) LoadMarshaled(ms Marshaler, marshaled []byte) (err error) {
+	return ms.Unmarshal(marshaled, sp)
+}
+
+// NewStatHistogram builds the histogram metric out of metricID
+// accepted format: *histogram_<source>#<bucket1>&<bucket2>, ie: *histogram_pdd#500ms&1s&3s
+// buckets are upper bounds, values above the last one are counted in the +Inf bucket
+func NewStatHistogram(metricID string, minItems int) (StatMetric, error) {
+	srcBuckets := strings.SplitN(strings.TrimPrefix(metricID, utils.MetaHistogram+utils.UnderscoreSep),
+		utils.HashtagSep, 2)
+	if len(srcBuckets) != 2 || len(srcBuckets[1]) == 0 {
+		return nil, fmt.Errorf("missing buckets for metric: %s", metricID)
+	}
+	source := srcBuckets[0]
+	if err := parseStatSource(metricID, source); err != nil {
+		return nil, err
+	}
+	bucketStrs := strings.Split(srcBuckets[1], utils.HistogramSep)
+	buckets := make([]float64, len(bucketStrs))
+	for i, bucketStr := range bucketStrs {
+		if statSourceIsDuration(source) {
+			dur, err := utils.ParseDurationWithNanosecs(bucketStr)
+			if err != nil {
+				return nil, fmt.Errorf("invalid bucket: %s for metric: %s", bucketStr, metricID)
+			}
+			buckets[i] = float64(dur.Nanoseconds())
+		} else {
+			val, err := strconv.ParseFloat(bucketStr, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid bucket: %s for metric: %s", bucketStr, metricID)
+			}
+			buckets[i] = val
+		}
+	}
+	sort.Float64s(buckets)
+	return &StatHistogram{Source: source, Buckets: buckets,
+		Counts: make([]int64, len(buckets)+1), Events: make(map[string]int),
+		Ignored: make(utils.StringMap), MinItems: minItems}, nil
+}
+
+// StatHistogram implements distribution of one source over configured buckets
+type StatHistogram struct {
+	Source   string
+	Buckets  []float64      // bucket upper bounds, durations as nanoseconds
+	Counts   []int64        // events per bucket, last one is +Inf
+	Events   map[string]int // map[EventTenantID]BucketIndex
+	Ignored  utils.StringMap
+	MinItems int
+}
+
+// bucketLabel returns the label of the bucket with index idx
+func (sh *StatHistogram) bucketLabel(idx int) string {
+	if idx == len(sh.Buckets) {
+		return utils.HistogramInf
+	}
+	if statSourceIsDuration(sh.Source) {
+		return time.Duration(int64(sh.Buckets[idx])).String()
+	}
+	return strconv.FormatFloat(sh.Buckets[idx], 'f', -1, 64)
+}
+
+// notAvailable returns true if the histogram does not have enough items
+func (sh *StatHistogram) notAvailable() bool {
+	return (sh.MinItems > 0 && len(sh.Events)+len(sh.Ignored) < sh.MinItems) ||
+		len(sh.Events) == 0
+}
+
+// GetValue returns the number of events per bucket, indexed on bucket upper bound
+func (sh *StatHistogram) GetValue() (v interface{}) {
+	vals := make(map[string]int64, len(sh.Counts))
+	if sh.notAvailable() {
+		return vals
+	}
+	for i, cnt := range sh.Counts {
+		vals[sh.bucketLabel(i)] = cnt
+	}
+	return vals
+}
+
+func (sh *StatHistogram) GetStringValue(fmtOpts string) (valStr string) {
+	if sh.notAvailable() {
+		return utils.NOT_AVAILABLE
+	}
+	bktStrs := make([]string, len(sh.Counts))
+	for i, cnt := range sh.Counts {
+		bktStrs[i] = sh.bucketLabel(i) + utils.CONCATENATED_KEY_SEP + strconv.FormatInt(cnt, 10)
+	}
+	return strings.Join(bktStrs, utils.HistogramSep)
+}
+
+// GetFloat64Value returns the number of events considered in the histogram
+func (sh *StatHistogram) GetFloat64Value() (v float64) {
+	if sh.notAvailable() {
+		return STATS_NA
+	}
+	return float64(len(sh.Events))
+}
+
+func (sh *StatHistogram) AddEvent(ev *utils.CGREvent) (err error) {
+	var val float64
+	if val, err = statSourceValue(sh.Source, ev); err != nil {
+		if err != utils.ErrNotFound {
+			return
+		}
+		if idx, has := sh.Events[ev.TenantID()]; has {
+			sh.Counts[idx] -= 1
+			delete(sh.Events, ev.TenantID())
+		}
+		sh.Ignored[ev.TenantID()] = true
+		return nil
+	}
+	if idx, has := sh.Events[ev.TenantID()]; has { // event replaced
+		sh.Counts[idx] -= 1
+	}
+	idx := sort.SearchFloat64s(sh.Buckets, val) // first bucket with upper bound >= val
+	sh.Counts[idx] += 1
+	sh.Events[ev.TenantID()] = idx
+	delete(sh.Ignored, ev.TenantID())
+	return
+}
+
+func (sh *StatHistogram) RemEvent(evTenantID string) (err error) {
+	if idx, has := sh.Events[evTenantID]; has {
+		sh.Counts[idx] -= 1
+		delete(sh.Events, evTenantID)
+	} else if _, has := sh.Ignored[evTenantID]; has {
+		delete(sh.Ignored, evTenantID)
+	} else {
+		return utils.ErrNotFound
+	}
+	return
+}
+
+func (sh *StatHistogram) Marshal(ms Marshaler) (marshaled []byte, err error) {
+	return ms.Marshal(sh)
+}
+
+func (sh *StatHistogram) LoadMarshaled(ms Marshaler, marshaled []byte) (err error) {
+	return ms.Unmarshal(marshaled, sh)
+}
+
+// statFieldIsDuration returns true for the event fields known to carry durations
+func statFieldIsDuration(fldName string) bool {
+	return fldName == utils.Usage || fldName == utils.PDD
+}
+
+// statFieldValue returns the value of fldName in event as float64
+// durations are returned as nanoseconds, signaled by isDuration
+func statFieldValue(ev *utils.CGREvent, fldName string) (val float64, isDuration bool, err error) {
+	iface, has := ev.Event[fldName]
+	if !has {
+		return 0, false, utils.ErrNotFound
+	}
+	if _, isDuration = iface.(time.Duration); isDuration || statFieldIsDuration(fldName) {
+		var dur time.Duration
+		if dur, err = ev.FieldAsDuration(fldName); err != nil {
+			return
+		}
+		return float64(dur.Nanoseconds()), true, nil
+	}
+	val, err = utils.IfaceAsFloat64(iface)
+	return
+}
+
+// formatStatFieldValue returns the string representation of a field metric value
+func formatStatFieldValue(val float64, isDuration bool) string {
+	if isDuration {
+		return time.Duration(int64(val)).String()
+	}
+	return strconv.FormatFloat(val, 'f', -1, 64)
+}
+
+func NewStatSum(minItems int, fieldName string) (StatMetric, error) {
+	return &StatSum{FieldName: fieldName, Duration: statFieldIsDuration(fieldName),
+		Events: make(map[string]float64), Ignored: make(utils.StringMap), MinItems: minItems}, nil
+}
+
+// StatSum implements the sum of values in FieldName
+type StatSum struct {
+	FieldName string
+	Duration  bool // values in FieldName are durations, kept as nanoseconds
+	Sum       float64
+	Events    map[string]float64 // map[EventTenantID]Value
+	Ignored   utils.StringMap    // events missing FieldName
+	MinItems  int
+	val       *float64 // cached sum value
+}
+
+// getValue returns sum.val
+func (sum *StatSum) getValue() float64 {
+	if sum.val == nil {
+		if (sum.MinItems > 0 && len(sum.Events)+len(sum.Ignored) < sum.MinItems) ||
+			len(sum.Events) == 0 {
+			sum.val = utils.Float64Pointer(STATS_NA)
+		} else {
+			sum.val = utils.Float64Pointer(utils.Round(sum.Sum,
+				config.CgrConfig().RoundingDecimals, utils.ROUNDING_MIDDLE))
+		}
+	}
+	return *sum.val
+}
+
+func (sum *StatSum) GetValue() (v interface{}) {
+	if sum.Duration {
+		return time.Duration(int64(sum.getValue()))
+	}
+	return sum.getValue()
+}
+
+func (sum *StatSum) GetStringValue(fmtOpts string) (valStr string) {
+	if val := sum.getValue(); val == STATS_NA {
+		valStr = utils.NOT_AVAILABLE
+	} else {
+		valStr = formatStatFieldValue(val, sum.Duration)
+	}
+	return
+}
+
+// GetFloat64Value returns durations as seconds, same as *acd
+func (sum *StatSum) GetFloat64Value() (v float64) {
+	if v = sum.getValue(); v != STATS_NA && sum.Duration {
+		v = time.Duration(int64(v)).Seconds()
+	}
+	return
+}
+
+func (sum *StatSum) AddEvent(ev *utils.CGREvent) (err error) {
+	var val float64
+	var isDuration bool
+	if val, isDuration, err = statFieldValue(ev, sum.FieldName); err != nil {
+		if err != utils.ErrNotFound {
+			return
+		}
+		sum.Ignored[ev.TenantID()] = true
+		return nil
+	}
+	if isDuration {
+		sum.Duration = true
+	}
+	sum.Sum += val
+	sum.Events[ev.TenantID()] = val
+	sum.val = nil
+	return
+}
+
+func (sum *StatSum) RemEvent(evTenantID string) (err error) {
+	if val, has := sum.Events[evTenantID]; has {
+		sum.Sum -= val
+		delete(sum.Events, evTenantID)
+	} else if _, has := sum.Ignored[evTenantID]; has {
+		delete(sum.Ignored, evTenantID)
+	} else {
+		return utils.ErrNotFound
+	}
+	sum.val = nil
+	return
+}
+
+func (sum *StatSum) Marshal(ms Marshaler) (marshaled []byte, err error) {
+	return ms.Marshal(sum)
+}
+
+func (sum *StatSum) LoadMarshaled(ms Marshaler, marshaled []byte) (err error) {
+	return ms.Unmarshal(marshaled, sum)
+}
+
+func NewStatAverage(minItems int, fieldName string) (StatMetric, error) {
+	return &StatAverage{FieldName: fieldName, Duration: statFieldIsDuration(fieldName),
+		Events: make(map[string]float64), Ignored: make(utils.StringMap), MinItems: minItems}, nil
+}
+
+// StatAverage implements the average of values in FieldName
+type StatAverage struct {
+	FieldName string
+	Duration  bool // values in FieldName are durations, kept as nanoseconds
+	Sum       float64
+	Events    map[string]float64 // map[EventTenantID]Value
+	Ignored   utils.StringMap    // events missing FieldName
+	MinItems  int
+	val       *float64 // cached average value
+}
+
+// getValue returns avg.val
+func (avg *StatAverage) getValue() float64 {
+	if avg.val == nil {
+		if (avg.MinItems > 0 && len(avg.Events)+len(avg.Ignored) < avg.MinItems) ||
+			len(avg.Events) == 0 {
+			avg.val = utils.Float64Pointer(STATS_NA)
+		} else {
+			avg.val = utils.Float64Pointer(utils.Round(avg.Sum/float64(len(avg.Events)),
+				config.CgrConfig().RoundingDecimals, utils.ROUNDING_MIDDLE))
+		}
+	}
+	return *avg.val
+}
+
+func (avg *StatAverage) GetValue() (v interface{}) {
+	if avg.Duration {
+		return time.Duration(int64(avg.getValue()))
+	}
+	return avg.getValue()
+}
+
+func (avg *StatAverage) GetStringValue(fmtOpts string) (valStr string) {
+	if val := avg.getValue(); val == STATS_NA {
+		valStr = utils.NOT_AVAILABLE
+	} else {
+		valStr = formatStatFieldValue(val, avg.Duration)
+	}
+	return
+}
+
+// GetFloat64Value returns durations as seconds, same as *acd
+func (avg *StatAverage) GetFloat64Value() (v float64) {
+	if v = avg.getValue(); v != STATS_NA && avg.Duration {
+		v = time.Duration(int64(v)).Seconds()
+	}
+	return
+}
+
+func (avg *StatAverage) AddEvent(ev *utils.CGREvent) (err error) {
+	var val float64
+	var isDuration bool
+	if val, isDuration, err = statFieldValue(ev, avg.FieldName); err != nil {
+		if err != utils.ErrNotFound {
+			return
+		}
+		avg.Ignored[ev.TenantID()] = true
+		return nil
+	}
+	if isDuration {
+		avg.Duration = true
+	}
+	avg.Sum += val
+	avg.Events[ev.TenantID()] = val
+	avg.val = nil
+	return
+}
+
+func (avg *StatAverage) RemEvent(evTenantID string) (err error) {
+	if val, has := avg.Events[evTenantID]; has {
+		avg.Sum -= val
+		delete(avg.Events, evTenantID)
+	} else if _, has := avg.Ignored[evTenantID]; has {
+		delete(avg.Ignored, evTenantID)
+	} else {
+		return utils.ErrNotFound
+	}
+	avg.val = nil
+	return
+}
+
+func (avg *StatAverage) Marshal(ms Marshaler) (marshaled []byte, err error) {
+	return ms.Marshal(avg)
+}
+
+func (avg *StatAverage) LoadMarshaled(ms Marshaler, marshaled []byte) (err error) {
+	return ms.Unmarshal(marshaled, avg)
+}
+
+func NewStatMax(minItems int, fieldName string) (StatMetric, error) {
+	return &StatMax{FieldName: fieldName, Duration: statFieldIsDuration(fieldName),
+		Events: make(map[string]float64), Ignored: make(utils.StringMap), MinItems: minItems}, nil
+}
+
+// StatMax implements the maximum of values in FieldName
+type StatMax struct {
+	FieldName string
+	Duration  bool               // values in FieldName are durations, kept as nanoseconds
+	Events    map[string]float64 // map[EventTenantID]Value
+	Ignored   utils.StringMap    // events missing FieldName
+	MinItems  int
+	val       *float64 // cached max value
+}
+
+// getValue returns sMax.val
+func (sMax *StatMax) getValue() float64 {
+	if sMax.val == nil {
+		if (sMax.MinItems > 0 && len(sMax.Events)+len(sMax.Ignored) < sMax.MinItems) ||
+			len(sMax.Events) == 0 {
+			sMax.val = utils.Float64Pointer(STATS_NA)
+		} else {
+			maxVal := math.Inf(-1)
+			for _, val := range sMax.Events {
+				if val > maxVal {
+					maxVal = val
+				}
+			}
+			sMax.val = utils.Float64Pointer(maxVal)
+		}
+	}
+	return *sMax.val
+}
+
+func (sMax *StatMax) GetValue() (v interface{}) {
+	if sMax.Duration {
+		return time.Duration(int64(sMax.getValue()))
+	}
+	return sMax.getValue()
+}
+
+func (sMax *StatMax) GetStringValue(fmtOpts string) (valStr string) {
+	if val := sMax.getValue(); val == STATS_NA {
+		valStr = utils.NOT_AVAILABLE
+	} else {
+		valStr = formatStatFieldValue(val, sMax.Duration)
+	}
+	return
+}
+
+// GetFloat64Value returns durations as seconds, same as *acd
+func (sMax *StatMax) GetFloat64Value() (v float64) {
+	if v = sMax.getValue(); v != STATS_NA && sMax.Duration {
+		v = time.Duration(int64(v)).Seconds()
+	}
+	return
+}
+
+func (sMax *StatMax) AddEvent(ev *utils.CGREvent) (err error) {
+	var val float64
+	var isDuration bool
+	if val, isDuration, err = statFieldValue(ev, sMax.FieldName); err != nil {
+		if err != utils.ErrNotFound {
+			return
+		}
+		sMax.Ignored[ev.TenantID()] = true
+		return nil
+	}
+	if isDuration {
+		sMax.Duration = true
+	}
+	sMax.Events[ev.TenantID()] = val
+	sMax.val = nil
+	return
+}
+
+func (sMax *StatMax) RemEvent(evTenantID string) (err error) {
+	if _, has := sMax.Events[evTenantID]; has {
+		delete(sMax.Events, evTenantID)
+	} else if _, has := sMax.Ignored[evTenantID]; has {
+		delete(sMax.Ignored, evTenantID)
+	} else {
+		return utils.ErrNotFound
+	}
+	sMax.val = nil
+	return
+}
+
+func (sMax *StatMax) Marshal(ms Marshaler) (marshaled []byte, err error) {
+	return ms.Marshal(sMax)
+}
+
+func (sMax *StatMax) LoadMarshaled(ms Marshaler, marshaled []byte) (err error) {
+	return ms.Unmarshal(marshaled, sMax)
+}
+
+func NewStatMin(minItems int, fieldName string) (StatMetric, error) {
+	return &StatMin{FieldName: fieldName, Duration: statFieldIsDuration(fieldName),
+		Events: make(map[string]float64), Ignored: make(utils.StringMap), MinItems: minItems}, nil
+}
+
+// StatMin implements the minimum of values in FieldName
+type StatMin struct {
+	FieldName string
+	Duration  bool               // values in FieldName are durations, kept as nanoseconds
+	Events    map[string]float64 // map[EventTenantID]Value
+	Ignored   utils.StringMap    // events missing FieldName
+	MinItems  int
+	val       *float64 // cached min value
+}
+
+// getValue returns sMin.val
+func (sMin *StatMin) getValue() float64 {
+	if sMin.val == nil {
+		if (sMin.MinItems > 0 && len(sMin.Events)+len(sMin.Ignored) < sMin.MinItems) ||
+			len(sMin.Events) == 0 {
+			sMin.val = utils.Float64Pointer(STATS_NA)
+		} else {
+			minVal := math.Inf(1)
+			for _, val := range sMin.Events {
+				if val < minVal {
+					minVal = val
+				}
+			}
+			sMin.val = utils.Float64Pointer(minVal)
+		}
+	}
+	return *sMin.val
+}
+
+func (sMin *StatMin) GetValue() (v interface{}) {
+	if sMin.Duration {
+		return time.Duration(int64(sMin.getValue()))
+	}
+	return sMin.getValue()
+}
+
+func (sMin *StatMin) GetStringValue(fmtOpts string) (valStr string) {
+	if val := sMin.getValue(); val == STATS_NA {
+		valStr = utils.NOT_AVAILABLE
+	} else {
+		valStr = formatStatFieldValue(val, sMin.Duration)
+	}
+	return
+}
+
+// GetFloat64Value returns durations as seconds, same as *acd
+func (sMin *StatMin) GetFloat64Value() (v float64) {
+	if v = sMin.getValue(); v != STATS_NA && sMin.Duration {
+		v = time.Duration(int64(v)).Seconds()
+	}
+	return
+}
+
+func (sMin *StatMin) AddEvent(ev *utils.CGREvent) (err error) {
+	var val float64
+	var isDuration bool
+	if val, isDuration, err = statFieldValue(ev, sMin.FieldName); err != nil {
+		if err != utils.ErrNotFound {
+			return
+		}
+		sMin.Ignored[ev.TenantID()] = true
+		return nil
+	}
+	if isDuration {
+		sMin.Duration = true
+	}
+	sMin.Events[ev.TenantID()] = val
+	sMin.val = nil
+	return
+}
+
+func (sMin *StatMin) RemEvent(evTenantID string) (err error) {
+	if _, has := sMin.Events[evTenantID]; has {
+		delete(sMin.Events, evTenantID)
+	} else if _, has := sMin.Ignored[evTenantID]; has {
+		delete(sMin.Ignored, evTenantID)
+	} else {
+		return utils.ErrNotFound
+	}
+	sMin.val = nil
+	return
+}
+
+func (sMin *StatMin) Marshal(ms Marshaler) (marshaled []byte, err error) {
+	return ms.Marshal(sMin)
+}
+
+func (sMin *StatMin) LoadMarshaled(ms Marshaler, marshaled []byte) (err error) {
+	return ms.Unmarshal(marshaled, sMin)
+}
+
+func NewStatDistinct(minItems int, fieldName string) (StatMetric, error) {
+	return &StatDistinct{FieldName: fieldName, FieldValues: make(map[string]utils.StringMap),
+		Events: make(map[string]string), Ignored: make(utils.StringMap), MinItems: minItems}, nil
+}
+
+// StatDistinct implements the distinct count of values in FieldName
+type StatDistinct struct {
+	FieldName   string
+	FieldValues map[string]utils.StringMap // map[FieldValue]map[EventTenantID]bool
+	Events      map[string]string          // map[EventTenantID]FieldValue
+	Ignored     utils.StringMap            // events missing FieldName
+	MinItems    int
+}
+
+// notAvailable returns true if there are not enough items for the metric
+func (dst *StatDistinct) notAvailable() bool {
+	return (dst.MinItems > 0 && len(dst.Events)+len(dst.Ignored) < dst.MinItems) ||
+		len(dst.FieldValues) == 0
+}
+
+func (dst *StatDistinct) GetValue() (v interface{}) {
+	if dst.notAvailable() {
+		return STATS_NA
+	}
+	return len(dst.FieldValues)
+}
+
+func (dst *StatDistinct) GetStringValue(fmtOpts string) (valStr string) {
+	if dst.notAvailable() {
+		valStr = utils.NOT_AVAILABLE
+	} else {
+		valStr = strconv.Itoa(len(dst.FieldValues))
+	}
+	return
+}
+
+func (dst *StatDistinct) GetFloat64Value() (v float64) {
+	if dst.notAvailable() {
+		v = STATS_NA
+	} else {
+		v = float64(len(dst.FieldValues))
+	}
+	return
+}
+
+func (dst *StatDistinct) AddEvent(ev *utils.CGREvent) (err error) {
+	var fldVal string
+	if fldVal, err = ev.FieldAsString(dst.FieldName); err != nil {
+		if err != utils.ErrNotFound {
+			return
+		}
+		dst.Ignored[ev.TenantID()] = true
+		return nil
+	}
+	if _, has := dst.FieldValues[fldVal]; !has {
+		dst.FieldValues[fldVal] = make(utils.StringMap)
+	}
+	dst.FieldValues[fldVal][ev.TenantID()] = true
+	dst.Events[ev.TenantID()] = fldVal
+	return
+}
+
+func (dst *StatDistinct) RemEvent(evTenantID string) (err error) {
+	fldVal, has := dst.Events[evTenantID]
+	if !has {
+		if _, has := dst.Ignored[evTenantID]; !has {
+			return utils.ErrNotFound
+		}
+		delete(dst.Ignored, evTenantID)
+		return
+	}
+	delete(dst.Events, evTenantID)
+	if len(dst.FieldValues[fldVal]) == 1 {
+		delete(dst.FieldValues, fldVal)
+		return
+	}
+	delete(dst.FieldValues[fldVal], evTenantID)
+	return
+}
+
+func (dst *StatDistinct) Marshal(ms Marshaler) (marshaled []byte, err error) {
+	return ms.Marshal(dst)
+}
+
+func (dst *StatDistinct) LoadMarshaled(ms Marshaler, marshaled []byte) (err error) {
+	return ms.Unmarshal(marshaled, dst)
+}
@@ -18,6 +18,8 @@ along with this program.  If not, see <http://www.gnu.org/licenses/>
 package engine
 
 import (
+	"reflect"
+	"strconv"
 	"testing"
 	"time"
 
@@ -938,3 +940,292 @@ func TestDDCGetFloat64Value(t *testing.T) {
 		t.Errorf("wrong ddc value: %v", strVal)
 	}
 }
+
+func TestNewStatMetricDistribution(t *testing.T) {
+	for _, metricID := range []string{"*p95_acd", "*p99.9_pdd", "*median_cost",
+		"*histogram_pdd#1s&500ms&3s", "*histogram_cost#0.1&1"} {
+		if _, err := NewStatMetric(metricID, 0); err != nil {
+			t.Errorf("metricID: %s, error: %v", metricID, err)
+		}
+	}
+	for _, metricID := range []string{"*p0_acd", "*p101_acd", "*p95", "*p95_usage",
+		"*median_", "*histogram_pdd", "*histogram_pdd#", "*histogram_cost#1&a"} {
+		if _, err := NewStatMetric(metricID, 0); err == nil {
+			t.Errorf("metricID: %s, expecting error", metricID)
+		}
+	}
+}
+
+func TestStatPercentileACD(t *testing.T) {
+	p95, _ := NewStatMetric("*p95_acd", 2)
+	ev := &utils.CGREvent{Tenant: "cgrates.org", ID: "EVENT_1",
+		Event: map[string]interface{}{
+			"AnswerTime": time.Date(2014, 7, 14, 14, 25, 0, 0, time.UTC),
+			"Usage":      time.Duration(10 * time.Second)}}
+	p95.AddEvent(ev)
+	if strVal := p95.GetStringValue(""); strVal != utils.NOT_AVAILABLE {
+		t.Errorf("wrong p95 value: %s", strVal)
+	}
+	ev2 := &utils.CGREvent{Tenant: "cgrates.org", ID: "EVENT_2"} // unanswered
+	p95.AddEvent(ev2)
+	if strVal := p95.GetStringValue(""); strVal != "10s" {
+		t.Errorf("wrong p95 value: %s", strVal)
+	}
+	for i := 3; i <= 21; i++ {
+		p95.AddEvent(&utils.CGREvent{Tenant: "cgrates.org", ID: "EVENT_" + strconv.Itoa(i),
+			Event: map[string]interface{}{
+				"AnswerTime": time.Date(2014, 7, 14, 14, 25, 0, 0, time.UTC),
+				"Usage":      time.Duration(i) * time.Second}})
+	}
+	// 20 answered values: 3s..21s and 10s
+	if v := p95.GetValue(); v != time.Duration(20050*time.Millisecond) {
+		t.Errorf("wrong p95 value: %v", v)
+	}
+	if v := p95.GetFloat64Value(); v != 20.05 {
+		t.Errorf("wrong p95 value: %v", v)
+	}
+	p95.RemEvent("cgrates.org:EVENT_21")
+	p95.RemEvent("cgrates.org:EVENT_20")
+	if v := p95.GetValue(); v != time.Duration(18150*time.Millisecond) {
+		t.Errorf("wrong p95 value: %v", v)
+	}
+	if err := p95.RemEvent("cgrates.org:EVENT_21"); err != utils.ErrNotFound {
+		t.Error(err)
+	}
+	if err := p95.RemEvent(ev2.TenantID()); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestStatPercentileMedianCost(t *testing.T) {
+	median, _ := NewStatMetric("*median_cost", 0)
+	if v := median.GetFloat64Value(); v != STATS_NA {
+		t.Errorf("wrong median value: %v", v)
+	}
+	for i, cost := range []float64{1.2, 0.3, 4, -1} {
+		median.AddEvent(&utils.CGREvent{Tenant: "cgrates.org", ID: "EVENT_" + strconv.Itoa(i),
+			Event: map[string]interface{}{
+				"AnswerTime": time.Date(2014, 7, 14, 14, 25, 0, 0, time.UTC),
+				utils.COST:   cost}})
+	}
+	if v := median.GetValue(); v != 1.2 {
+		t.Errorf("wrong median value: %v", v)
+	}
+	median.RemEvent("cgrates.org:EVENT_2")
+	if strVal := median.GetStringValue(""); strVal != "0.75" {
+		t.Errorf("wrong median value: %s", strVal)
+	}
+}
+
+func TestStatHistogramPDD(t *testing.T) {
+	hist, _ := NewStatMetric("*histogram_pdd#3s&1s", 0)
+	if strVal := hist.GetStringValue(""); strVal != utils.NOT_AVAILABLE {
+		t.Errorf("wrong histogram value: %s", strVal)
+	}
+	for i, pdd := range []time.Duration{500 * time.Millisecond, time.Second,
+		2 * time.Second, 10 * time.Second} {
+		hist.AddEvent(&utils.CGREvent{Tenant: "cgrates.org", ID: "EVENT_" + strconv.Itoa(i),
+			Event: map[string]interface{}{utils.PDD: pdd}})
+	}
+	hist.AddEvent(&utils.CGREvent{Tenant: "cgrates.org", ID: "EVENT_NOPDD"})
+	if strVal := hist.GetStringValue(""); strVal != "1s:2&3s:1&+Inf:1" {
+		t.Errorf("wrong histogram value: %s", strVal)
+	}
+	if v := hist.GetFloat64Value(); v != 4 {
+		t.Errorf("wrong histogram value: %v", v)
+	}
+	hist.RemEvent("cgrates.org:EVENT_3")
+	hist.RemEvent("cgrates.org:EVENT_NOPDD")
+	eVal := map[string]int64{"1s": 2, "3s": 1, utils.HistogramInf: 0}
+	if v := hist.GetValue(); !reflect.DeepEqual(eVal, v) {
+		t.Errorf("expecting: %+v, received: %+v", eVal, v)
+	}
+}
+
+func TestStatHistogramReplaceEvent(t *testing.T) {
+	hist, _ := NewStatMetric("*histogram_pdd#1s&2s", 0)
+	hist.AddEvent(&utils.CGREvent{Tenant: "cgrates.org", ID: "EVENT_1",
+		Event: map[string]interface{}{utils.PDD: 500 * time.Millisecond}})
+	hist.AddEvent(&utils.CGREvent{Tenant: "cgrates.org", ID: "EVENT_1",
+		Event: map[string]interface{}{utils.PDD: 1500 * time.Millisecond}})
+	if strVal := hist.GetStringValue(""); strVal != "1s:0&2s:1&+Inf:0" {
+		t.Errorf("wrong histogram value: %s", strVal)
+	}
+	hist.AddEvent(&utils.CGREvent{Tenant: "cgrates.org", ID: "EVENT_1"})
+	if strVal := hist.GetStringValue(""); strVal != utils.NOT_AVAILABLE {
+		t.Errorf("wrong histogram value: %s", strVal)
+	}
+	hist.AddEvent(&utils.CGREvent{Tenant: "cgrates.org", ID: "EVENT_1",
+		Event: map[string]interface{}{utils.PDD: 3 * time.Second}})
+	if strVal := hist.GetStringValue(""); strVal != "1s:0&2s:0&+Inf:1" {
+		t.Errorf("wrong histogram value: %s", strVal)
+	}
+	if _, has := hist.(*StatHistogram).Ignored["cgrates.org:EVENT_1"]; has {
+		t.Error("event should not be ignored anymore")
+	}
+}
+
+func TestStatDistributionMarshal(t *testing.T) {
+	ms := NewCodecMsgpackMarshaler()
+	for _, metricID := range []string{"*p90_pdd", "*histogram_cost#0.5&1"} {
+		metric, _ := NewStatMetric(metricID, 0)
+		for i, pdd := range []time.Duration{500 * time.Millisecond, 2 * time.Second} {
+			metric.AddEvent(&utils.CGREvent{Tenant: "cgrates.org", ID: "EVENT_" + strconv.Itoa(i),
+				Event: map[string]interface{}{
+					"AnswerTime": time.Date(2014, 7, 14, 14, 25, 0, 0, time.UTC),
+					utils.PDD:    pdd,
+					utils.COST:   float64(i) + 0.2}})
+		}
+		marshaled, err := metric.Marshal(ms)
+		if err != nil {
+			t.Fatal(err)
+		}
+		loaded, _ := NewStatMetric(metricID, 0)
+		if err := loaded.LoadMarshaled(ms, marshaled); err != nil {
+			t.Fatal(err)
+		}
+		if metric.GetStringValue("") != loaded.GetStringValue("") {
+			t.Errorf("metricID: %s, expecting: %s, received: %s", metricID,
+				metric.GetStringValue(""), loaded.GetStringValue(""))
+		}
+		if err := loaded.RemEvent("cgrates.org:EVENT_1"); err != nil {
+			t.Error(err)
+		}
+	}
+}
+
+func TestNewStatMetricField(t *testing.T) {
+	for _, metricID := range []string{"*sum#Usage", "*average#Cost", "*max#PDD",
+		"*min#Usage", "*distinct#Account"} {
+		if _, err := NewStatMetric(metricID, 0); err != nil {
+			t.Errorf("metricID: %s, error: %v", metricID, err)
+		}
+	}
+	for _, metricID := range []string{"*sum", "*sum#", "*average", "*unknown#Usage"} {
+		if _, err := NewStatMetric(metricID, 0); err == nil {
+			t.Errorf("metricID: %s, expecting error", metricID)
+		}
+	}
+}
+
+func TestStatSumAverage(t *testing.T) {
+	sum, _ := NewStatMetric("*sum#Usage", 2)
+	avg, _ := NewStatMetric("*average#Usage", 2)
+	ev := &utils.CGREvent{Tenant: "cgrates.org", ID: "EVENT_1",
+		Event: map[string]interface{}{
+			utils.Usage: time.Duration(1024)}}
+	ev2 := &utils.CGREvent{Tenant: "cgrates.org", ID: "EVENT_2",
+		Event: map[string]interface{}{
+			utils.Usage: "2048"}}
+	ev3 := &utils.CGREvent{Tenant: "cgrates.org", ID: "EVENT_3"}
+	for _, metric := range []StatMetric{sum, avg} {
+		metric.AddEvent(ev)
+		if strVal := metric.GetStringValue(""); strVal != utils.NOT_AVAILABLE {
+			t.Errorf("wrong value: %s", strVal)
+		}
+		metric.AddEvent(ev2)
+		metric.AddEvent(ev3)
+	}
+	if v := sum.GetValue(); v != time.Duration(3072) {
+		t.Errorf("wrong sum value: %v", v)
+	}
+	if strVal := sum.GetStringValue(""); strVal != "3.072µs" {
+		t.Errorf("wrong sum value: %s", strVal)
+	}
+	if strVal := avg.GetStringValue(""); strVal != "1.536µs" {
+		t.Errorf("wrong average value: %s", strVal)
+	}
+	if v := avg.GetFloat64Value(); v != 1.536e-06 {
+		t.Errorf("wrong average value: %v", v)
+	}
+	for _, metric := range []StatMetric{sum, avg} {
+		if err := metric.RemEvent(ev3.TenantID()); err != nil {
+			t.Error(err)
+		}
+		metric.RemEvent(ev.TenantID())
+		if strVal := metric.GetStringValue(""); strVal != utils.NOT_AVAILABLE {
+			t.Errorf("wrong value: %s", strVal)
+		}
+	}
+	if err := sum.AddEvent(&utils.CGREvent{Tenant: "cgrates.org", ID: "EVENT_4",
+		Event: map[string]interface{}{utils.Usage: "notnumeric"}}); err == nil {
+		t.Error("expecting error")
+	}
+}
+
+func TestStatMaxMinDuration(t *testing.T) {
+	sMax, _ := NewStatMetric("*max#Usage", 0)
+	sMin, _ := NewStatMetric("*min#Duration", 0)
+	for i, usage := range []time.Duration{time.Minute, 90 * time.Second} {
+		ev := &utils.CGREvent{Tenant: "cgrates.org", ID: "EVENT_" + strconv.Itoa(i),
+			Event: map[string]interface{}{utils.Usage: usage, "Duration": usage}}
+		sMax.AddEvent(ev)
+		sMin.AddEvent(ev)
+	}
+	if strVal := sMax.GetStringValue(""); strVal != "1m30s" {
+		t.Errorf("wrong max value: %s", strVal)
+	}
+	if strVal := sMin.GetStringValue(""); strVal != "1m0s" {
+		t.Errorf("wrong min value: %s", strVal)
+	}
+	if v := sMin.GetFloat64Value(); v != 60 {
+		t.Errorf("wrong min value: %v", v)
+	}
+}
+
+func TestStatMaxMin(t *testing.T) {
+	sMax, _ := NewStatMetric("*max#Cost", 0)
+	sMin, _ := NewStatMetric("*min#Cost", 0)
+	for i, cost := range []float64{1.2, 0.3, 4} {
+		ev := &utils.CGREvent{Tenant: "cgrates.org", ID: "EVENT_" + strconv.Itoa(i),
+			Event: map[string]interface{}{utils.COST: cost}}
+		sMax.AddEvent(ev)
+		sMin.AddEvent(ev)
+	}
+	if v := sMax.GetValue(); v != 4.0 {
+		t.Errorf("wrong max value: %v", v)
+	}
+	if v := sMin.GetValue(); v != 0.3 {
+		t.Errorf("wrong min value: %v", v)
+	}
+	sMax.RemEvent("cgrates.org:EVENT_2")
+	sMin.RemEvent("cgrates.org:EVENT_1")
+	if strVal := sMax.GetStringValue(""); strVal != "1.2" {
+		t.Errorf("wrong max value: %s", strVal)
+	}
+	if strVal := sMin.GetStringValue(""); strVal != "1.2" {
+		t.Errorf("wrong min value: %s", strVal)
+	}
+}
+
+func TestStatDistinct(t *testing.T) {
+	dst, _ := NewStatMetric("*distinct#Account", 0)
+	if v := dst.GetValue(); v != STATS_NA {
+		t.Errorf("wrong distinct value: %v", v)
+	}
+	for i, acnt := range []string{"1001", "1002", "1001"} {
+		dst.AddEvent(&utils.CGREvent{Tenant: "cgrates.org", ID: "EVENT_" + strconv.Itoa(i),
+			Event: map[string]interface{}{utils.Account: acnt}})
+	}
+	dst.AddEvent(&utils.CGREvent{Tenant: "cgrates.org", ID: "EVENT_NOACNT"})
+	if v := dst.GetFloat64Value(); v != 2 {
+		t.Errorf("wrong distinct value: %v", v)
+	}
+	dst.RemEvent("cgrates.org:EVENT_1")
+	dst.RemEvent("cgrates.org:EVENT_NOACNT")
+	if strVal := dst.GetStringValue(""); strVal != "1" {
+		t.Errorf("wrong distinct value: %s", strVal)
+	}
+	ms := NewCodecMsgpackMarshaler()
+	marshaled, err := dst.Marshal(ms)
+	if err != nil {
+		t.Fatal(err)
+	}
+	loaded, _ := NewStatMetric("*distinct#Account", 0)
+	if err := loaded.LoadMarshaled(ms, marshaled); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(dst, loaded) {
+		t.Errorf("expecting: %+v, received: %+v", dst, loaded)
+	}
+}
@@ -55,6 +55,7 @@ type StatService struct {
 	thdS             rpcclient.RpcClientConnection // rpc connection towards ThresholdS
 	filterS          *FilterS
 	indexedFields    []string
+	ifMux            sync.RWMutex // protects indexedFields
 	stopBackup       chan struct{}
 	storedStatQueues utils.StringMap // keep a record of stats which need saving, map[statsTenantID]bool
 	ssqMux           sync.RWMutex    // protects storedStatQueues
@@ -77,6 +78,42 @@ func (sS *StatService) Shutdown() error {
 	return nil
 }
 
+// ReloadConfig applies the indexed_fields out of the running configuration
+func (sS *StatService) ReloadConfig(cfg *config.CGRConfig) error {
+	sS.ifMux.Lock()
+	sS.indexedFields = cfg.StatSCfg().IndexedFields
+	sS.ifMux.Unlock()
+	return nil
+}
+
+// PromMetrics exports the metric values of each StatQueue
+func (sS *StatService) PromMetrics() []*utils.PromMetric {
+	pm := &utils.PromMetric{Name: utils.PromNamespace + "_stat_queue_metric",
+		Help: "Current value of StatQueue metrics", Type: utils.PromGauge}
+	keys, err := sS.dm.DataDB().GetKeysForPrefix(utils.StatQueuePrefix)
+	if err != nil {
+		utils.Logger.Warning(fmt.Sprintf("<StatS> error: %s querying StatQueue keys for metrics", err.Error()))
+		return nil
+	}
+	for _, key := range keys {
+		tntID := utils.NewTenantID(key[len(utils.StatQueuePrefix):])
+		lockID := utils.StatQueuesStringIndex + tntID.ID
+		guardian.Guardian.GuardIDs(config.CgrConfig().LockingTimeout, lockID)
+		sq, err := sS.dm.GetStatQueue(tntID.Tenant, tntID.ID, false, utils.NonTransactional)
+		if err != nil {
+			guardian.Guardian.UnguardIDs(lockID)
+			continue
+		}
+		for metricID, metric := range sq.SQMetrics {
+			pm.Samples = append(pm.Samples, &utils.PromSample{
+				Labels: map[string]string{"tenant": sq.Tenant, "queue": sq.ID, "metric": metricID},
+				Value:  metric.GetFloat64Value()})
+		}
+		guardian.Guardian.UnguardIDs(lockID)
+	}
+	return []*utils.PromMetric{pm}
+}
+
 // runBackup will regularly store resources changed to dataDB
 func (sS *StatService) runBackup() {
 	if sS.storeInterval <= 0 {
@@ -141,7 +178,10 @@ func (sS *StatService) StoreStatQueue(sq *StatQueue) (err error) {
 // matchingStatQueuesForEvent returns ordered list of matching resources which are active by the time of the call
 func (sS *StatService) matchingStatQueuesForEvent(ev *utils.CGREvent) (sqs StatQueues, err error) {
 	matchingSQs := make(map[string]*StatQueue)
-	sqIDs, err := matchingItemIDsForEvent(ev.Event, sS.indexedFields, sS.dm, utils.StatQueuesStringIndex+ev.Tenant)
+	sS.ifMux.RLock()
+	indexedFields := sS.indexedFields
+	sS.ifMux.RUnlock()
+	sqIDs, err := matchingItemIDsForEvent(ev.Event, indexedFields, sS.dm, utils.StatQueuesStringIndex+ev.Tenant)
 	if err != nil {
 		return nil, err
 	}
@@ -317,6 +357,36 @@ func (sS *StatService) V1GetQueueFloatMetrics(args *utils.TenantID, reply *map[s
 	return
 }
 
+// V1ResetStatQueue clears the items of a StatQueue and rebuilds its metrics empty
+func (sS *StatService) V1ResetStatQueue(args *utils.TenantID, reply *string) (err error) {
+	if missing := utils.MissingStructFields(args, []string{"Tenant", "ID"}); len(missing) != 0 { //Params missing
+		return utils.NewErrMandatoryIeMissing(missing...)
+	}
+	lockID := utils.StatQueuesStringIndex + args.ID
+	guardian.Guardian.GuardIDs(config.CgrConfig().LockingTimeout, lockID)
+	defer guardian.Guardian.UnguardIDs(lockID)
+	sq, err := sS.dm.GetStatQueue(args.Tenant, args.ID, false, "")
+	if err != nil {
+		if err != utils.ErrNotFound {
+			err = utils.NewErrServerError(err)
+		}
+		return err
+	}
+	metrics := make(map[string]StatMetric, len(sq.SQMetrics))
+	for metricID := range sq.SQMetrics {
+		if metrics[metricID], err = NewStatMetric(metricID, sq.MinItems); err != nil {
+			return utils.NewErrServerError(err)
+		}
+	}
+	sq.SQItems = nil
+	sq.SQMetrics = metrics
+	if err = sS.dm.SetStatQueue(sq); err != nil {
+		return utils.NewErrServerError(err)
+	}
+	*reply = utils.OK
+	return
+}
+
 // V1GetQueueIDs returns list of queueIDs registered for a tenant
 func (sS *StatService) V1GetQueueIDs(tenant string, qIDs *[]string) (err error) {
 	prfx := utils.StatQueuePrefix + tenant + ":"
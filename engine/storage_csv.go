@@ -34,34 +34,40 @@ type CSVStorage struct {
 	// file names
 	destinationsFn, ratesFn, destinationratesFn, timingsFn, destinationratetimingsFn, ratingprofilesFn,
 	sharedgroupsFn, lcrFn, actionsFn, actiontimingsFn, actiontriggersFn, accountactionsFn, derivedChargersFn,
-	cdrStatsFn, usersFn, aliasesFn, resProfilesFn, statsFn, thresholdsFn, filterFn, suppProfilesFn, attributeProfilesFn string
+	cdrStatsFn, usersFn, aliasesFn, resProfilesFn, statsFn, thresholdsFn, filterFn, suppProfilesFn, attributeProfilesFn,
+	exchangeRatesFn, taxProfilesFn, feeProfilesFn string
 }
 
 func NewFileCSVStorage(sep rune,
 	destinationsFn, timingsFn, ratesFn, destinationratesFn, destinationratetimingsFn, ratingprofilesFn, sharedgroupsFn, lcrFn,
 	actionsFn, actiontimingsFn, actiontriggersFn, accountactionsFn, derivedChargersFn, cdrStatsFn, usersFn, aliasesFn,
-	resProfilesFn, statsFn, thresholdsFn, filterFn, suppProfilesFn, attributeProfilesFn string) *CSVStorage {
+	resProfilesFn, statsFn, thresholdsFn, filterFn, suppProfilesFn, attributeProfilesFn,
+	exchangeRatesFn, taxProfilesFn, feeProfilesFn string) *CSVStorage {
 	c := new(CSVStorage)
 	c.sep = sep
 	c.readerFunc = openFileCSVStorage
 	c.destinationsFn, c.timingsFn, c.ratesFn, c.destinationratesFn, c.destinationratetimingsFn, c.ratingprofilesFn,
 		c.sharedgroupsFn, c.lcrFn, c.actionsFn, c.actiontimingsFn, c.actiontriggersFn, c.accountactionsFn,
 		c.derivedChargersFn, c.cdrStatsFn, c.usersFn, c.aliasesFn, c.resProfilesFn, c.statsFn, c.thresholdsFn,
-		c.filterFn, c.suppProfilesFn, c.attributeProfilesFn = destinationsFn, timingsFn,
+		c.filterFn, c.suppProfilesFn, c.attributeProfilesFn, c.exchangeRatesFn, c.taxProfilesFn,
+		c.feeProfilesFn = destinationsFn, timingsFn,
 		ratesFn, destinationratesFn, destinationratetimingsFn, ratingprofilesFn, sharedgroupsFn, lcrFn,
 		actionsFn, actiontimingsFn, actiontriggersFn, accountactionsFn, derivedChargersFn, cdrStatsFn,
-		usersFn, aliasesFn, resProfilesFn, statsFn, thresholdsFn, filterFn, suppProfilesFn, attributeProfilesFn
+		usersFn, aliasesFn, resProfilesFn, statsFn, thresholdsFn, filterFn, suppProfilesFn, attributeProfilesFn,
+		exchangeRatesFn, taxProfilesFn, feeProfilesFn
 	return c
 }
 
 func NewStringCSVStorage(sep rune,
 	destinationsFn, timingsFn, ratesFn, destinationratesFn, destinationratetimingsFn, ratingprofilesFn, sharedgroupsFn, lcrFn,
 	actionsFn, actiontimingsFn, actiontriggersFn, accountactionsFn, derivedChargersFn, cdrStatsFn, usersFn,
-	aliasesFn, resProfilesFn, statsFn, thresholdsFn, filterFn, suppProfilesFn, attributeProfilesFn string) *CSVStorage {
+	aliasesFn, resProfilesFn, statsFn, thresholdsFn, filterFn, suppProfilesFn, attributeProfilesFn,
+	exchangeRatesFn, taxProfilesFn, feeProfilesFn string) *CSVStorage {
 	c := NewFileCSVStorage(sep, destinationsFn, timingsFn, ratesFn, destinationratesFn, destinationratetimingsFn,
 		ratingprofilesFn, sharedgroupsFn, lcrFn, actionsFn, actiontimingsFn, actiontriggersFn,
 		accountactionsFn, derivedChargersFn, cdrStatsFn, usersFn, aliasesFn, resProfilesFn,
-		statsFn, thresholdsFn, filterFn, suppProfilesFn, attributeProfilesFn)
+		statsFn, thresholdsFn, filterFn, suppProfilesFn, attributeProfilesFn,
+		exchangeRatesFn, taxProfilesFn, feeProfilesFn)
 	c.readerFunc = openStringCSVStorage
 	return c
 }
@@ -770,6 +776,90 @@ func (csvs *CSVStorage) GetTPAttributes(tpid, id string) ([]*utils.TPAttributePr
 	return tpAls.AsTPAttributes(), nil
 }
 
+func (csvs *CSVStorage) GetTPExchangeRates(tpid, fromCurrency, toCurrency string) ([]*utils.TPExchangeRate, error) {
+	csvReader, fp, err := csvs.readerFunc(csvs.exchangeRatesFn, csvs.sep, getColumnCount(TpExchangeRate{}))
+	if err != nil {
+		//log.Print("Could not load ExchangeRates file: ", err)
+		// allow writing of the other values
+		return nil, nil
+	}
+	if fp != nil {
+		defer fp.Close()
+	}
+	var tpXRs TpExchangeRates
+	for record, err := csvReader.Read(); err != io.EOF; record, err = csvReader.Read() {
+		if err != nil {
+			log.Printf("bad line in %s, %s\n", csvs.exchangeRatesFn, err.Error())
+			return nil, err
+		}
+		if exchangeRate, err := csvLoad(TpExchangeRate{}, record); err != nil {
+			log.Print("error loading tpExchangeRate: ", err)
+			return nil, err
+		} else {
+			exchangeRate := exchangeRate.(TpExchangeRate)
+			exchangeRate.Tpid = tpid
+			tpXRs = append(tpXRs, &exchangeRate)
+		}
+	}
+	return tpXRs.AsTPExchangeRates(), nil
+}
+
+func (csvs *CSVStorage) GetTPTaxProfiles(tpid, id string) ([]*utils.TPTaxProfile, error) {
+	csvReader, fp, err := csvs.readerFunc(csvs.taxProfilesFn, csvs.sep, getColumnCount(TpTaxProfile{}))
+	if err != nil {
+		//log.Print("Could not load TaxProfiles file: ", err)
+		// allow writing of the other values
+		return nil, nil
+	}
+	if fp != nil {
+		defer fp.Close()
+	}
+	var tpTXPs TpTaxProfiles
+	for record, err := csvReader.Read(); err != io.EOF; record, err = csvReader.Read() {
+		if err != nil {
+			log.Printf("bad line in %s, %s\n", csvs.taxProfilesFn, err.Error())
+			return nil, err
+		}
+		if taxProfile, err := csvLoad(TpTaxProfile{}, record); err != nil {
+			log.Print("error loading tpTaxProfile: ", err)
+			return nil, err
+		} else {
+			taxProfile := taxProfile.(TpTaxProfile)
+			taxProfile.Tpid = tpid
+			tpTXPs = append(tpTXPs, &taxProfile)
+		}
+	}
+	return tpTXPs.AsTPTaxProfiles()
+}
+
+func (csvs *CSVStorage) GetTPFeeProfiles(tpid, id string) ([]*utils.TPFeeProfile, error) {
+	csvReader, fp, err := csvs.readerFunc(csvs.feeProfilesFn, csvs.sep, getColumnCount(TpFeeProfile{}))
+	if err != nil {
+		//log.Print("Could not load FeeProfiles file: ", err)
+		// allow writing of the other values
+		return nil, nil
+	}
+	if fp != nil {
+		defer fp.Close()
+	}
+	var tpFEPs TpFeeProfiles
+	for record, err := csvReader.Read(); err != io.EOF; record, err = csvReader.Read() {
+		if err != nil {
+			log.Printf("bad line in %s, %s\n", csvs.feeProfilesFn, err.Error())
+			return nil, err
+		}
+		if feeProfile, err := csvLoad(TpFeeProfile{}, record); err != nil {
+			log.Print("error loading tpFeeProfile: ", err)
+			return nil, err
+		} else {
+			feeProfile := feeProfile.(TpFeeProfile)
+			feeProfile.Tpid = tpid
+			tpFEPs = append(tpFEPs, &feeProfile)
+		}
+	}
+	return tpFEPs.AsTPFeeProfiles(), nil
+}
+
 func (csvs *CSVStorage) GetTpIds(colName string) ([]string, error) {
 	return nil, utils.ErrNotImplemented
 }
@@ -0,0 +1,359 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package engine
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/gob"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sync"
+
+	"github.com/cgrates/cgrates/utils"
+)
+
+const (
+	embeddedSnapshotFile   = "datadb.snapshot"
+	embeddedJournalFile    = "datadb.journal" // suffixed with the generation
+	embeddedMaxJournalSize = 64 << 20         // journal is compacted into a new snapshot once reaching this size
+	mapJrnlHdrLen          = 8                // record length and checksum
+)
+
+// types of changes recorded in the journal
+const (
+	mapJrnlSet = iota
+	mapJrnlRem
+	mapJrnlFlush
+	mapJrnlPushTask
+	mapJrnlPopTask
+)
+
+var (
+	embeddedDBs    = make(map[string]*EmbeddedStorage) // opened databases indexed on path, shared within the process
+	embeddedDBsMux sync.Mutex
+)
+
+// mapJournalOp is one change done on MapStorage
+// the unexported fields keep the previous data so the change can be rolled back, they are not journaled
+type mapJournalOp struct {
+	Type     int
+	Key      string
+	Value    []byte
+	prev     []byte // previous value of Key or the popped task
+	hasPrev  bool
+	prevDict storage // data before flush
+}
+
+// mapJournal appends the changes done on MapStorage to a file, one record per write lock
+// each record is prefixed by its length and checksum so a partially written one can be detected on replay
+type mapJournal struct {
+	fPath string
+	f     *os.File
+	size  int64
+}
+
+func openMapJournal(fPath string, truncate bool) (*mapJournal, error) {
+	flags := os.O_RDWR | os.O_CREATE
+	if truncate {
+		flags |= os.O_TRUNC
+	}
+	f, err := os.OpenFile(fPath, flags, 0644)
+	if err != nil {
+		return nil, err
+	}
+	return &mapJournal{fPath: fPath, f: f}, nil
+}
+
+// replay applies the records in the journal on ms
+// an incomplete record at the end (eg: crash while writing) is discarded
+func (jrnl *mapJournal) replay(ms *MapStorage) (err error) {
+	fi, err := jrnl.f.Stat()
+	if err != nil {
+		return
+	}
+	rdr := bufio.NewReader(jrnl.f)
+	hdr := make([]byte, mapJrnlHdrLen)
+	var offset int64
+	for {
+		if _, err = io.ReadFull(rdr, hdr); err != nil {
+			break
+		}
+		recLen := int64(binary.BigEndian.Uint32(hdr[:4]))
+		if offset+mapJrnlHdrLen+recLen > fi.Size() {
+			err = io.ErrUnexpectedEOF
+			break
+		}
+		rec := make([]byte, recLen)
+		if _, err = io.ReadFull(rdr, rec); err != nil {
+			break
+		}
+		if crc32.ChecksumIEEE(rec) != binary.BigEndian.Uint32(hdr[4:]) {
+			err = fmt.Errorf("checksum mismatch")
+			break
+		}
+		var ops []*mapJournalOp
+		if err = gob.NewDecoder(bytes.NewReader(rec)).Decode(&ops); err != nil {
+			return fmt.Errorf("cannot decode journal <%s> at offset %d: %s", jrnl.fPath, offset, err.Error())
+		}
+		ms.applyJournalOps(ops)
+		offset += mapJrnlHdrLen + recLen
+	}
+	if err != io.EOF {
+		utils.Logger.Warning(fmt.Sprintf("<%s> discarding incomplete record in journal <%s> at offset %d: %s",
+			utils.MetaEmbedded, jrnl.fPath, offset, err.Error()))
+	}
+	if err = jrnl.f.Truncate(offset); err != nil {
+		return
+	}
+	if _, err = jrnl.f.Seek(offset, io.SeekStart); err != nil {
+		return
+	}
+	jrnl.size = offset
+	return
+}
+
+// write appends the ops as one record, returning only once the record is synced to disk
+func (jrnl *mapJournal) write(ops []*mapJournalOp) (err error) {
+	var buf bytes.Buffer
+	buf.Write(make([]byte, mapJrnlHdrLen))
+	if err = gob.NewEncoder(&buf).Encode(ops); err != nil {
+		return
+	}
+	rec := buf.Bytes()
+	binary.BigEndian.PutUint32(rec[:4], uint32(len(rec)-mapJrnlHdrLen))
+	binary.BigEndian.PutUint32(rec[4:mapJrnlHdrLen], crc32.ChecksumIEEE(rec[mapJrnlHdrLen:]))
+	if _, err = jrnl.f.Write(rec); err == nil {
+		err = jrnl.f.Sync()
+	}
+	if err != nil { // do not leave a partial record in front of the next ones
+		jrnl.f.Truncate(jrnl.size)
+		jrnl.f.Seek(jrnl.size, io.SeekStart)
+		return
+	}
+	jrnl.size += int64(len(rec))
+	return
+}
+
+func (jrnl *mapJournal) close() error {
+	return jrnl.f.Close()
+}
+
+// applyJournalOps replays the changes on the data, without journaling them again
+func (ms *MapStorage) applyJournalOps(ops []*mapJournalOp) {
+	for _, op := range ops {
+		switch op.Type {
+		case mapJrnlSet:
+			ms.dict[op.Key] = op.Value
+		case mapJrnlRem:
+			delete(ms.dict, op.Key)
+		case mapJrnlFlush:
+			ms.dict = make(map[string][]byte)
+		case mapJrnlPushTask:
+			ms.tasks = append(ms.tasks, op.Value)
+		case mapJrnlPopTask:
+			if len(ms.tasks) != 0 {
+				ms.tasks = ms.tasks[1:]
+			}
+		}
+	}
+}
+
+// rollbackJournalOps undoes the changes, in reverse order, restoring the data before them
+func (ms *MapStorage) rollbackJournalOps(ops []*mapJournalOp) {
+	for i := len(ops) - 1; i >= 0; i-- {
+		op := ops[i]
+		switch op.Type {
+		case mapJrnlSet, mapJrnlRem:
+			if op.hasPrev {
+				ms.dict[op.Key] = op.prev
+			} else {
+				delete(ms.dict, op.Key)
+			}
+		case mapJrnlFlush:
+			ms.dict = op.prevDict
+		case mapJrnlPushTask:
+			if len(ms.tasks) != 0 {
+				ms.tasks = ms.tasks[:len(ms.tasks)-1]
+			}
+		case mapJrnlPopTask:
+			ms.tasks = append([][]byte{op.prev}, ms.tasks...)
+		}
+	}
+}
+
+// journalPath returns the path of the journal following the current snapshot
+func (ms *MapStorage) journalPath() string {
+	return path.Join(path.Dir(ms.snapshotPath), fmt.Sprintf("%s.%d", embeddedJournalFile, ms.snapshotGen))
+}
+
+// commitJournal persists the changes done while holding the write lock
+// changes which could not be written are rolled back so the data served matches the one on disk
+func (ms *MapStorage) commitJournal() (err error) {
+	if len(ms.jrnlOps) == 0 {
+		return
+	}
+	ops := ms.jrnlOps
+	ms.jrnlOps = nil
+	if err = ms.journal.write(ops); err != nil {
+		ms.rollbackJournalOps(ops)
+		utils.Logger.Crit(fmt.Sprintf("<%s> could not write journal <%s>: %s",
+			utils.MetaEmbedded, ms.journal.fPath, err.Error()))
+		return fmt.Errorf("could not persist changes: %s", err.Error())
+	}
+	if ms.journal.size >= embeddedMaxJournalSize {
+		if err := ms.compactJournal(); err != nil {
+			utils.Logger.Crit(fmt.Sprintf("<%s> could not compact journal <%s>: %s",
+				utils.MetaEmbedded, ms.journal.fPath, err.Error()))
+		}
+	}
+	return nil
+}
+
+// compactJournal writes the data into a new snapshot and starts the next journal generation
+// the caller needs to hold the write lock
+func (ms *MapStorage) compactJournal() (err error) {
+	ms.snapshotGen++
+	if err = ms.writeSnapshot(); err != nil {
+		ms.snapshotGen--
+		return
+	}
+	jrnl, err := openMapJournal(ms.journalPath(), true)
+	if err != nil {
+		return
+	}
+	if ms.journal != nil {
+		ms.journal.close()
+		os.Remove(ms.journal.fPath)
+	}
+	ms.journal = jrnl
+	return
+}
+
+// EmbeddedStorage is a DataDB persisted on local disk, for single node deployments without a database daemon
+// Data is served out of memory while each change is appended to a journal and synced to disk before the write lock is released.
+// On open the journal is replayed over the last snapshot and compacted into a new one.
+type EmbeddedStorage struct {
+	*MapStorage
+	dbPath string
+	refs   int // users sharing the instance within the process
+}
+
+// NewEmbeddedStorage opens the database in dbPath directory, creating it if missing
+// opening the same path multiple times returns the same instance since only one writer is allowed
+func NewEmbeddedStorage(dbPath, mrshlerStr string) (es *EmbeddedStorage, err error) {
+	if dbPath, err = filepath.Abs(dbPath); err != nil {
+		return
+	}
+	embeddedDBsMux.Lock()
+	defer embeddedDBsMux.Unlock()
+	if es, has := embeddedDBs[dbPath]; has {
+		es.refs++
+		return es, nil
+	}
+	var mrshler Marshaler
+	if mrshlerStr == utils.MSGPACK {
+		mrshler = NewCodecMsgpackMarshaler()
+	} else if mrshlerStr == utils.JSON {
+		mrshler = new(JSONMarshaler)
+	} else {
+		return nil, fmt.Errorf("Unsupported marshaler: %v", mrshlerStr)
+	}
+	if err = os.MkdirAll(dbPath, 0755); err != nil {
+		return
+	}
+	ms, err := NewMapStorage()
+	if err != nil {
+		return
+	}
+	ms.ms = mrshler
+	ms.snapshotPath = path.Join(dbPath, embeddedSnapshotFile)
+	if err = ms.loadSnapshot(); err != nil {
+		return
+	}
+	if ms.journal, err = openMapJournal(ms.journalPath(), false); err != nil {
+		return
+	}
+	if err = ms.journal.replay(ms); err != nil {
+		ms.journal.close()
+		return
+	}
+	if err = ms.compactJournal(); err != nil {
+		ms.journal.close()
+		return
+	}
+	// journals left behind by an interrupted compaction are already part of the snapshot
+	if jrnlPaths, errGlob := filepath.Glob(path.Join(dbPath, embeddedJournalFile+".*")); errGlob == nil {
+		for _, jrnlPath := range jrnlPaths {
+			if jrnlPath != ms.journal.fPath {
+				os.Remove(jrnlPath)
+			}
+		}
+	}
+	ms.mu.commit = ms.commitJournal
+	es = &EmbeddedStorage{MapStorage: ms, dbPath: dbPath, refs: 1}
+	embeddedDBs[dbPath] = es
+	return
+}
+
+// Close compacts the journal once the last user of the database closes it
+func (es *EmbeddedStorage) Close() {
+	embeddedDBsMux.Lock()
+	defer embeddedDBsMux.Unlock()
+	if es.refs--; es.refs > 0 {
+		return
+	}
+	delete(embeddedDBs, es.dbPath)
+	es.mu.Lock()
+	defer es.mu.Unlock()
+	es.mu.commit = nil
+	if err := es.compactJournal(); err != nil {
+		utils.Logger.Err(fmt.Sprintf("<%s> could not compact journal <%s>: %s",
+			utils.MetaEmbedded, es.journal.fPath, err.Error()))
+	}
+	es.journal.close()
+	es.journal = nil
+}
+
+func (es *EmbeddedStorage) GetStorageType() string {
+	return utils.MetaEmbedded
+}
+
+// GetRawValue returns the value stored on key as it is in the database, used when migrating older formats
+func (es *EmbeddedStorage) GetRawValue(key string) ([]byte, error) {
+	es.mu.RLock()
+	defer es.mu.RUnlock()
+	if value, has := es.dict[key]; has {
+		return value, nil
+	}
+	return nil, utils.ErrNotFound
+}
+
+// SetRawValue stores the value on key without further processing, used when migrating older formats
+func (es *EmbeddedStorage) SetRawValue(key string, value []byte) (err error) {
+	es.mu.Lock()
+	defer es.mu.unlockErr(&err)
+	es.setKey(key, value)
+	return nil
+}
@@ -0,0 +1,174 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package engine
+
+import (
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/cgrates/cgrates/utils"
+)
+
+// closeEmbeddedWithoutCompact simulates a crash, leaving the journal as written
+func closeEmbeddedWithoutCompact(es *EmbeddedStorage) {
+	embeddedDBsMux.Lock()
+	delete(embeddedDBs, es.dbPath)
+	embeddedDBsMux.Unlock()
+	es.journal.close()
+}
+
+func TestEmbeddedStoragePersistence(t *testing.T) {
+	dbPath := t.TempDir()
+	es, err := NewEmbeddedStorage(dbPath, utils.MSGPACK)
+	if err != nil {
+		t.Fatal(err)
+	}
+	acnt := &Account{ID: "cgrates.org:1001", BalanceMap: map[string]Balances{
+		utils.MONETARY: {&Balance{ID: "BAL1", Value: 10}}}}
+	if err := es.SetAccount(acnt); err != nil {
+		t.Fatal(err)
+	}
+	if err := es.SetReverseDestination(&Destination{Id: "DST_1002", Prefixes: []string{"1002"}}, utils.NonTransactional); err != nil {
+		t.Fatal(err)
+	}
+	if err := es.PushTask(&Task{Uuid: "TASK1", AccountID: acnt.ID}); err != nil {
+		t.Fatal(err)
+	}
+	es.Close()
+	if es, err = NewEmbeddedStorage(dbPath, utils.MSGPACK); err != nil {
+		t.Fatal(err)
+	}
+	if rcv, err := es.GetAccount(acnt.ID); err != nil {
+		t.Error(err)
+	} else if rcv.BalanceMap[utils.MONETARY][0].Value != 10 {
+		t.Errorf("Received: %s", utils.ToJSON(rcv))
+	}
+	if rcv, err := es.GetReverseDestination("1002", true, utils.NonTransactional); err != nil {
+		t.Error(err)
+	} else if !reflect.DeepEqual([]string{"DST_1002"}, rcv) {
+		t.Errorf("Received: %+v", rcv)
+	}
+	if rcv, err := es.PopTask(); err != nil {
+		t.Error(err)
+	} else if rcv.Uuid != "TASK1" {
+		t.Errorf("Received: %+v", rcv)
+	}
+	es.Close()
+}
+
+func TestEmbeddedStorageJournalReplay(t *testing.T) {
+	dbPath := t.TempDir()
+	es, err := NewEmbeddedStorage(dbPath, utils.MSGPACK)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, acntID := range []string{"cgrates.org:1001", "cgrates.org:1002"} {
+		if err := es.SetAccount(&Account{ID: acntID}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := es.RemoveAccount("cgrates.org:1002"); err != nil {
+		t.Fatal(err)
+	}
+	jrnlPath := es.journal.fPath
+	closeEmbeddedWithoutCompact(es)
+	// partially written record at the end of the journal
+	f, err := os.OpenFile(jrnlPath, os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.Write([]byte{0, 0, 1, 0, 1, 2})
+	f.Close()
+	if es, err = NewEmbeddedStorage(dbPath, utils.MSGPACK); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := es.GetAccount("cgrates.org:1001"); err != nil {
+		t.Error(err)
+	}
+	if _, err := es.GetAccount("cgrates.org:1002"); err != utils.ErrNotFound {
+		t.Error(err)
+	}
+	if _, err := os.Stat(jrnlPath); !os.IsNotExist(err) {
+		t.Errorf("replayed journal not removed: %v", err)
+	}
+	es.Close()
+}
+
+func TestEmbeddedStorageShared(t *testing.T) {
+	dbPath := t.TempDir()
+	es1, err := NewEmbeddedStorage(dbPath, utils.MSGPACK)
+	if err != nil {
+		t.Fatal(err)
+	}
+	es2, err := NewEmbeddedStorage(dbPath, utils.MSGPACK)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if es1 != es2 {
+		t.Error("expecting the same instance for the same path")
+	}
+	es1.Close()
+	if err := es2.SetAccount(&Account{ID: "cgrates.org:1001"}); err != nil {
+		t.Error(err)
+	}
+	es2.Close()
+	if _, err := NewEmbeddedStorage(dbPath, "unknown"); err == nil {
+		t.Error("expecting error for unsupported marshaler")
+	}
+	if es1.GetStorageType() != utils.MetaEmbedded {
+		t.Error(es1.GetStorageType())
+	}
+}
+
+func TestEmbeddedStorageJournalWriteError(t *testing.T) {
+	es, err := NewEmbeddedStorage(t.TempDir(), utils.MSGPACK)
+	if err != nil {
+		t.Fatal(err)
+	}
+	acnt := &Account{ID: "cgrates.org:1001", BalanceMap: map[string]Balances{
+		utils.MONETARY: {&Balance{ID: "BAL1", Value: 10}}}}
+	if err := es.SetAccount(acnt); err != nil {
+		t.Fatal(err)
+	}
+	closeEmbeddedWithoutCompact(es) // writes on the closed journal fail
+	if err := es.SetAccount(&Account{ID: acnt.ID}); err == nil {
+		t.Error("expecting error when the journal cannot be written")
+	}
+	if err := es.RemoveAccount(acnt.ID); err == nil {
+		t.Error("expecting error when the journal cannot be written")
+	}
+	if rcv, err := es.GetAccount(acnt.ID); err != nil {
+		t.Error(err)
+	} else if rcv.BalanceMap[utils.MONETARY][0].Value != 10 {
+		t.Errorf("change not rolled back: %s", utils.ToJSON(rcv))
+	}
+	if err := es.Flush(""); err == nil {
+		t.Error("expecting error when the journal cannot be written")
+	}
+	if _, err := es.GetAccount(acnt.ID); err != nil {
+		t.Errorf("flush not rolled back: %v", err)
+	}
+	if err := es.PushTask(&Task{Uuid: "TASK1"}); err == nil {
+		t.Error("expecting error when the journal cannot be written")
+	}
+	if _, err := es.PopTask(); err != utils.ErrNotFound {
+		t.Errorf("push not rolled back: %v", err)
+	}
+}
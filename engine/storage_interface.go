@@ -145,6 +145,15 @@ type DataDB interface {
 	GetAttributeProfileDrv(string, string) (*AttributeProfile, error)
 	SetAttributeProfileDrv(*AttributeProfile) error
 	RemoveAttributeProfileDrv(string, string) error
+	GetExchangeRateDrv(string, string) (*ExchangeRate, error)
+	SetExchangeRateDrv(*ExchangeRate) error
+	RemoveExchangeRateDrv(string, string) error
+	GetTaxProfileDrv(string, string) (*TaxProfile, error)
+	SetTaxProfileDrv(*TaxProfile) error
+	RemoveTaxProfileDrv(string, string) error
+	GetFeeProfileDrv(string, string) (*FeeProfile, error)
+	SetFeeProfileDrv(*FeeProfile) error
+	RemoveFeeProfileDrv(string, string) error
 }
 
 type StorDB interface {
@@ -194,6 +203,9 @@ type LoadReader interface {
 	GetTPFilters(string, string) ([]*utils.TPFilterProfile, error)
 	GetTPSuppliers(string, string) ([]*utils.TPSupplierProfile, error)
 	GetTPAttributes(string, string) ([]*utils.TPAttributeProfile, error)
+	GetTPExchangeRates(string, string, string) ([]*utils.TPExchangeRate, error)
+	GetTPTaxProfiles(string, string) ([]*utils.TPTaxProfile, error)
+	GetTPFeeProfiles(string, string) ([]*utils.TPFeeProfile, error)
 }
 
 type LoadWriter interface {
@@ -220,6 +232,9 @@ type LoadWriter interface {
 	SetTPFilters([]*utils.TPFilterProfile) error
 	SetTPSuppliers([]*utils.TPSupplierProfile) error
 	SetTPAttributes([]*utils.TPAttributeProfile) error
+	SetTPExchangeRates([]*utils.TPExchangeRate) error
+	SetTPTaxProfiles([]*utils.TPTaxProfile) error
+	SetTPFeeProfiles([]*utils.TPFeeProfile) error
 }
 
 // NewMarshaler returns the marshaler type selected by mrshlerStr
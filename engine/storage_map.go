@@ -21,10 +21,15 @@ package engine
 import (
 	"bytes"
 	"compress/zlib"
+	"encoding/gob"
 	"errors"
+	"fmt"
 	"io/ioutil"
+	"os"
+	"path"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/cgrates/cgrates/cache"
 	"github.com/cgrates/cgrates/config"
@@ -32,33 +37,96 @@ import (
 )
 
 type MapStorage struct {
-	dict     storage
-	tasks    [][]byte
-	ms       Marshaler
-	mu       sync.RWMutex
-	cacheCfg config.CacheConfig
+	dict             storage
+	tasks            [][]byte
+	ms               Marshaler
+	mu               mapStorageMutex
+	cacheCfg         config.CacheConfig
+	cnter            *utils.Counter // used for OrderID for CDRs
+	snapshotPath     string         // file where the data is persisted, empty to keep it only in memory
+	snapshotInterval time.Duration  // interval between snapshots, 0 to save only when closing
+	stopSnapshots    chan struct{}
+	snapshotGen      uint64
+	journal          *mapJournal     // persists the changes, nil when journaling is disabled
+	jrnlOps          []*mapJournalOp // changes done while write locked, committed on Unlock
+}
+
+// mapStorageMutex commits the journaled changes before releasing the write lock
+// so the changes done by one method are persisted atomically
+type mapStorageMutex struct {
+	sync.RWMutex
+	commit func() error // rolls back the changes it could not persist
+}
+
+func (mu *mapStorageMutex) Unlock() {
+	mu.unlock()
+}
+
+// unlock releases the write lock, returning the error of persisting the changes
+func (mu *mapStorageMutex) unlock() (err error) {
+	if mu.commit != nil {
+		err = mu.commit()
+	}
+	mu.RWMutex.Unlock()
+	return
+}
+
+// unlockErr is unlock for deferred calls, reporting the commit error in err unless already set
+func (mu *mapStorageMutex) unlockErr(err *error) {
+	if cErr := mu.unlock(); cErr != nil && *err == nil {
+		*err = cErr
+	}
+}
+
+// mapSnapshot is the content of MapStorage written to disk
+type mapSnapshot struct {
+	Dict       storage
+	Tasks      [][]byte
+	Generation uint64 // journal generation following the snapshot
 }
 
 type storage map[string][]byte
 
-func (s storage) sadd(key, value string, ms Marshaler) {
+// setKey stores the value, journaling the change if persistence is enabled
+// the caller needs to hold the write lock
+func (ms *MapStorage) setKey(key string, value []byte) {
+	if ms.journal != nil {
+		prev, hasPrev := ms.dict[key]
+		ms.jrnlOps = append(ms.jrnlOps, &mapJournalOp{Type: mapJrnlSet, Key: key, Value: value,
+			prev: prev, hasPrev: hasPrev})
+	}
+	ms.dict[key] = value
+}
+
+// remKey removes the key, journaling the change if persistence is enabled
+// the caller needs to hold the write lock
+func (ms *MapStorage) remKey(key string) {
+	if ms.journal != nil {
+		prev, hasPrev := ms.dict[key]
+		ms.jrnlOps = append(ms.jrnlOps, &mapJournalOp{Type: mapJrnlRem, Key: key,
+			prev: prev, hasPrev: hasPrev})
+	}
+	delete(ms.dict, key)
+}
+
+func (ms *MapStorage) sadd(key, value string) {
 	idMap := utils.StringMap{}
-	if values, ok := s[key]; ok {
-		ms.Unmarshal(values, &idMap)
+	if values, ok := ms.dict[key]; ok {
+		ms.ms.Unmarshal(values, &idMap)
 	}
 	idMap[value] = true
-	values, _ := ms.Marshal(idMap)
-	s[key] = values
+	values, _ := ms.ms.Marshal(idMap)
+	ms.setKey(key, values)
 }
 
-func (s storage) srem(key, value string, ms Marshaler) {
+func (ms *MapStorage) srem(key, value string) {
 	idMap := utils.StringMap{}
-	if values, ok := s[key]; ok {
-		ms.Unmarshal(values, &idMap)
+	if values, ok := ms.dict[key]; ok {
+		ms.ms.Unmarshal(values, &idMap)
 	}
 	delete(idMap, value)
-	values, _ := ms.Marshal(idMap)
-	s[key] = values
+	values, _ := ms.ms.Marshal(idMap)
+	ms.setKey(key, values)
 }
 
 func (s storage) smembers(key string, ms Marshaler) (idMap utils.StringMap, ok bool) {
@@ -72,7 +140,99 @@ func (s storage) smembers(key string, ms Marshaler) (idMap utils.StringMap, ok b
 
 func NewMapStorage() (*MapStorage, error) {
 	return &MapStorage{dict: make(map[string][]byte), ms: NewCodecMsgpackMarshaler(),
-		cacheCfg: config.CgrConfig().CacheCfg()}, nil
+		cacheCfg: config.CgrConfig().CacheCfg(), cnter: utils.NewCounter(time.Now().UnixNano(), 0)}, nil
+}
+
+// NewMapStorageWithSnapshot returns a MapStorage persisted in snapshotPath
+// the data found in snapshotPath is loaded on start and saved back every snapshotInterval and on Close
+func NewMapStorageWithSnapshot(snapshotPath string, snapshotInterval time.Duration) (ms *MapStorage, err error) {
+	if ms, err = NewMapStorage(); err != nil {
+		return
+	}
+	if snapshotPath == "" {
+		return
+	}
+	ms.snapshotPath = snapshotPath
+	ms.snapshotInterval = snapshotInterval
+	if err = ms.loadSnapshot(); err != nil {
+		return nil, err
+	}
+	if snapshotInterval > 0 {
+		ms.stopSnapshots = make(chan struct{})
+		go ms.snapshotLoop()
+	}
+	return
+}
+
+// loadSnapshot restores the data out of snapshotPath, missing file means no data
+func (ms *MapStorage) loadSnapshot() error {
+	f, err := os.Open(ms.snapshotPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	defer f.Close()
+	var snp mapSnapshot
+	if err := gob.NewDecoder(f).Decode(&snp); err != nil {
+		return fmt.Errorf("cannot load snapshot <%s>: %s", ms.snapshotPath, err.Error())
+	}
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	if snp.Dict != nil {
+		ms.dict = snp.Dict
+	}
+	ms.tasks = snp.Tasks
+	ms.snapshotGen = snp.Generation
+	return nil
+}
+
+// Snapshot writes the data to snapshotPath, replacing the previous snapshot only once completely written
+func (ms *MapStorage) Snapshot() (err error) {
+	if ms.snapshotPath == "" {
+		return
+	}
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+	return ms.writeSnapshot()
+}
+
+// writeSnapshot does the work of Snapshot, the caller needs to hold the lock
+func (ms *MapStorage) writeSnapshot() (err error) {
+	if err = os.MkdirAll(path.Dir(ms.snapshotPath), 0755); err != nil {
+		return
+	}
+	tmpPath := ms.snapshotPath + ".tmp"
+	f, err := os.Create(tmpPath)
+	if err != nil {
+		return
+	}
+	if err = gob.NewEncoder(f).Encode(mapSnapshot{Dict: ms.dict, Tasks: ms.tasks,
+		Generation: ms.snapshotGen}); err == nil {
+		err = f.Sync()
+	}
+	if errClose := f.Close(); err == nil {
+		err = errClose
+	}
+	if err != nil {
+		os.Remove(tmpPath)
+		return
+	}
+	return os.Rename(tmpPath, ms.snapshotPath)
+}
+
+func (ms *MapStorage) snapshotLoop() {
+	for {
+		select {
+		case <-ms.stopSnapshots:
+			return
+		case <-time.After(ms.snapshotInterval):
+			if err := ms.Snapshot(); err != nil {
+				utils.Logger.Err(fmt.Sprintf("<MapStorage> Could not write snapshot <%s>: %s", ms.snapshotPath, err.Error()))
+			}
+		}
+	}
 }
 
 func NewMapStorageJson() (mpStorage *MapStorage, err error) {
@@ -81,11 +241,23 @@ func NewMapStorageJson() (mpStorage *MapStorage, err error) {
 	return
 }
 
-func (ms *MapStorage) Close() {}
+// Close saves the last snapshot if persistence is enabled
+func (ms *MapStorage) Close() {
+	if ms.stopSnapshots != nil {
+		close(ms.stopSnapshots)
+		ms.stopSnapshots = nil
+	}
+	if err := ms.Snapshot(); err != nil {
+		utils.Logger.Err(fmt.Sprintf("<MapStorage> Could not write snapshot <%s>: %s", ms.snapshotPath, err.Error()))
+	}
+}
 
-func (ms *MapStorage) Flush(ignore string) error {
+func (ms *MapStorage) Flush(ignore string) (err error) {
 	ms.mu.Lock()
-	defer ms.mu.Unlock()
+	defer ms.mu.unlockErr(&err)
+	if ms.journal != nil {
+		ms.jrnlOps = append(ms.jrnlOps, &mapJournalOp{Type: mapJrnlFlush, prevDict: ms.dict})
+	}
 	ms.dict = make(map[string][]byte)
 	return nil
 }
@@ -106,8 +278,10 @@ func (ms *MapStorage) RebuildReverseForPrefix(prefix string) error {
 	}
 	for _, key := range keys {
 		ms.mu.Lock()
-		delete(ms.dict, key)
-		ms.mu.Unlock()
+		ms.remKey(key)
+		if err = ms.mu.unlock(); err != nil {
+			return err
+		}
 	}
 	switch prefix {
 	case utils.REVERSE_DESTINATION_PREFIX:
@@ -154,8 +328,10 @@ func (ms *MapStorage) RemoveReverseForPrefix(prefix string) error {
 	}
 	for _, key := range keys {
 		ms.mu.Lock()
-		delete(ms.dict, key)
-		ms.mu.Unlock()
+		ms.remKey(key)
+		if err = ms.mu.unlock(); err != nil {
+			return err
+		}
 	}
 	switch prefix {
 	case utils.REVERSE_DESTINATION_PREFIX:
@@ -220,7 +396,8 @@ func (ms *MapStorage) HasDataDrv(categ, subject string) (bool, error) {
 	case utils.DESTINATION_PREFIX, utils.RATING_PLAN_PREFIX, utils.RATING_PROFILE_PREFIX,
 		utils.ACTION_PREFIX, utils.ACTION_PLAN_PREFIX, utils.ACCOUNT_PREFIX, utils.DERIVEDCHARGERS_PREFIX,
 		utils.ResourcesPrefix, utils.StatQueuePrefix, utils.ThresholdPrefix,
-		utils.FilterPrefix, utils.SupplierProfilePrefix, utils.AttributeProfilePrefix:
+		utils.FilterPrefix, utils.SupplierProfilePrefix, utils.AttributeProfilePrefix,
+		utils.ExchangeRatePrefix, utils.TaxProfilePrefix, utils.FeeProfilePrefix:
 		_, exists := ms.dict[categ+subject]
 		return exists, nil
 	}
@@ -251,13 +428,13 @@ func (ms *MapStorage) GetRatingPlanDrv(key string) (rp *RatingPlan, err error) {
 
 func (ms *MapStorage) SetRatingPlanDrv(rp *RatingPlan) (err error) {
 	ms.mu.Lock()
-	defer ms.mu.Unlock()
+	defer ms.mu.unlockErr(&err)
 	result, err := ms.ms.Marshal(rp)
 	var b bytes.Buffer
 	w := zlib.NewWriter(&b)
 	w.Write(result)
 	w.Close()
-	ms.dict[utils.RATING_PLAN_PREFIX+rp.Id] = b.Bytes()
+	ms.setKey(utils.RATING_PLAN_PREFIX+rp.Id, b.Bytes())
 	response := 0
 	if historyScribe != nil {
 		go historyScribe.Call("HistoryV1.Record", rp.GetHistoryRecord(), &response)
@@ -267,10 +444,10 @@ func (ms *MapStorage) SetRatingPlanDrv(rp *RatingPlan) (err error) {
 
 func (ms *MapStorage) RemoveRatingPlanDrv(key string) (err error) {
 	ms.mu.Lock()
-	defer ms.mu.Unlock()
+	defer ms.mu.unlockErr(&err)
 	for k := range ms.dict {
 		if strings.HasPrefix(k, key) {
-			delete(ms.dict, key)
+			ms.remKey(key)
 			response := 0
 			rpf := &RatingPlan{Id: key}
 			if historyScribe != nil {
@@ -297,9 +474,9 @@ func (ms *MapStorage) GetRatingProfileDrv(key string) (rpf *RatingProfile, err e
 
 func (ms *MapStorage) SetRatingProfileDrv(rpf *RatingProfile) (err error) {
 	ms.mu.Lock()
-	defer ms.mu.Unlock()
+	defer ms.mu.unlockErr(&err)
 	result, err := ms.ms.Marshal(rpf)
-	ms.dict[utils.RATING_PROFILE_PREFIX+rpf.Id] = result
+	ms.setKey(utils.RATING_PROFILE_PREFIX+rpf.Id, result)
 	response := 0
 	if historyScribe != nil {
 		go historyScribe.Call("HistoryV1.Record", rpf.GetHistoryRecord(false), &response)
@@ -309,10 +486,10 @@ func (ms *MapStorage) SetRatingProfileDrv(rpf *RatingProfile) (err error) {
 
 func (ms *MapStorage) RemoveRatingProfileDrv(key string) (err error) {
 	ms.mu.Lock()
-	defer ms.mu.Unlock()
+	defer ms.mu.unlockErr(&err)
 	for k := range ms.dict {
 		if strings.HasPrefix(k, key) {
-			delete(ms.dict, key)
+			ms.remKey(key)
 			response := 0
 			rpf := &RatingProfile{Id: key}
 			if historyScribe != nil {
@@ -337,16 +514,16 @@ func (ms *MapStorage) GetLCRDrv(id string) (lcr *LCR, err error) {
 
 func (ms *MapStorage) SetLCRDrv(lcr *LCR) (err error) {
 	ms.mu.Lock()
-	defer ms.mu.Unlock()
+	defer ms.mu.unlockErr(&err)
 	result, err := ms.ms.Marshal(lcr)
-	ms.dict[utils.LCR_PREFIX+lcr.GetId()] = result
+	ms.setKey(utils.LCR_PREFIX+lcr.GetId(), result)
 	return
 }
 
 func (ms *MapStorage) RemoveLCRDrv(id, transactionID string) (err error) {
 	ms.mu.Lock()
-	defer ms.mu.Unlock()
-	delete(ms.dict, utils.LCR_PREFIX+id)
+	defer ms.mu.unlockErr(&err)
+	ms.remKey(utils.LCR_PREFIX + id)
 	return
 }
 
@@ -392,14 +569,14 @@ func (ms *MapStorage) GetDestination(key string, skipCache bool, transactionID s
 
 func (ms *MapStorage) SetDestination(dest *Destination, transactionID string) (err error) {
 	ms.mu.Lock()
-	defer ms.mu.Unlock()
+	defer ms.mu.unlockErr(&err)
 	result, err := ms.ms.Marshal(dest)
 	var b bytes.Buffer
 	w := zlib.NewWriter(&b)
 	w.Write(result)
 	w.Close()
 	key := utils.DESTINATION_PREFIX + dest.Id
-	ms.dict[key] = b.Bytes()
+	ms.setKey(key, b.Bytes())
 	response := 0
 	if historyScribe != nil {
 		go historyScribe.Call("HistoryV1.Record", dest.GetHistoryRecord(false), &response)
@@ -434,8 +611,10 @@ func (ms *MapStorage) SetReverseDestination(dest *Destination, transactionID str
 	for _, p := range dest.Prefixes {
 		key := utils.REVERSE_DESTINATION_PREFIX + p
 		ms.mu.Lock()
-		ms.dict.sadd(key, dest.Id, ms.ms)
-		ms.mu.Unlock()
+		ms.sadd(key, dest.Id)
+		if err = ms.mu.unlock(); err != nil {
+			return
+		}
 		cache.RemKey(key, cacheCommit(transactionID), transactionID)
 	}
 	return
@@ -450,14 +629,18 @@ func (ms *MapStorage) RemoveDestination(destID string, transactionID string) (er
 	}
 
 	ms.mu.Lock()
-	delete(ms.dict, key)
-	ms.mu.Unlock()
+	ms.remKey(key)
+	if err = ms.mu.unlock(); err != nil {
+		return
+	}
 	cache.RemKey(key, cacheCommit(transactionID), transactionID)
 
 	for _, prefix := range d.Prefixes {
 		ms.mu.Lock()
-		ms.dict.srem(utils.REVERSE_DESTINATION_PREFIX+prefix, destID, ms.ms)
-		ms.mu.Unlock()
+		ms.srem(utils.REVERSE_DESTINATION_PREFIX+prefix, destID)
+		if err = ms.mu.unlock(); err != nil {
+			return
+		}
 		ms.GetReverseDestination(prefix, true, transactionID) // it will recache the destination
 	}
 
@@ -501,16 +684,20 @@ func (ms *MapStorage) UpdateReverseDestination(oldDest, newDest *Destination, tr
 	var err error
 	for _, obsoletePrefix := range obsoletePrefixes {
 		ms.mu.Lock()
-		ms.dict.srem(utils.REVERSE_DESTINATION_PREFIX+obsoletePrefix, oldDest.Id, ms.ms)
-		ms.mu.Unlock()
+		ms.srem(utils.REVERSE_DESTINATION_PREFIX+obsoletePrefix, oldDest.Id)
+		if err = ms.mu.unlock(); err != nil {
+			return err
+		}
 		cache.RemKey(utils.REVERSE_DESTINATION_PREFIX+obsoletePrefix, cCommit, transactionID)
 	}
 
 	// add the id to all new prefixes
 	for _, addedPrefix := range addedPrefixes {
 		ms.mu.Lock()
-		ms.dict.sadd(utils.REVERSE_DESTINATION_PREFIX+addedPrefix, newDest.Id, ms.ms)
-		ms.mu.Unlock()
+		ms.sadd(utils.REVERSE_DESTINATION_PREFIX+addedPrefix, newDest.Id)
+		if err = ms.mu.unlock(); err != nil {
+			return err
+		}
 		cache.RemKey(utils.REVERSE_DESTINATION_PREFIX+addedPrefix, cCommit, transactionID)
 	}
 	return err
@@ -530,18 +717,20 @@ func (ms *MapStorage) GetActionsDrv(key string) (as Actions, err error) {
 
 func (ms *MapStorage) SetActionsDrv(key string, as Actions) (err error) {
 	ms.mu.Lock()
-	defer ms.mu.Unlock()
+	defer ms.mu.unlockErr(&err)
 	cachekey := utils.ACTION_PREFIX + key
 	result, err := ms.ms.Marshal(&as)
-	ms.dict[cachekey] = result
+	ms.setKey(cachekey, result)
 	return
 }
 
 func (ms *MapStorage) RemoveActionsDrv(key string) (err error) {
 	cachekey := utils.ACTION_PREFIX + key
 	ms.mu.Lock()
-	delete(ms.dict, cachekey)
-	ms.mu.Unlock()
+	ms.remKey(cachekey)
+	if err = ms.mu.unlock(); err != nil {
+		return
+	}
 	return
 }
 
@@ -560,16 +749,16 @@ func (ms *MapStorage) GetSharedGroupDrv(key string) (sg *SharedGroup, err error)
 
 func (ms *MapStorage) SetSharedGroupDrv(sg *SharedGroup) (err error) {
 	ms.mu.Lock()
-	defer ms.mu.Unlock()
+	defer ms.mu.unlockErr(&err)
 	result, err := ms.ms.Marshal(sg)
-	ms.dict[utils.SHARED_GROUP_PREFIX+sg.Id] = result
+	ms.setKey(utils.SHARED_GROUP_PREFIX+sg.Id, result)
 	return
 }
 
 func (ms *MapStorage) RemoveSharedGroupDrv(id, transactionID string) (err error) {
 	ms.mu.Lock()
-	defer ms.mu.Unlock()
-	delete(ms.dict, utils.SHARED_GROUP_PREFIX+id)
+	defer ms.mu.unlockErr(&err)
+	ms.remKey(utils.SHARED_GROUP_PREFIX + id)
 	return
 }
 
@@ -602,20 +791,26 @@ func (ms *MapStorage) SetAccount(ub *Account) (err error) {
 			ac.UnitCounters = ub.UnitCounters
 			ac.AllowNegative = ub.AllowNegative
 			ac.Disabled = ub.Disabled
+			ac.ParentID = ub.ParentID
+			ac.CreditLimit = ub.CreditLimit
+			ac.CreditUsed = ub.CreditUsed
+			if len(ub.Subscriptions) != 0 {
+				ac.Subscriptions = ub.Subscriptions
+			}
 			ub = ac
 		}
 	}
 	ms.mu.Lock()
-	defer ms.mu.Unlock()
+	defer ms.mu.unlockErr(&err)
 	result, err := ms.ms.Marshal(ub)
-	ms.dict[utils.ACCOUNT_PREFIX+ub.ID] = result
+	ms.setKey(utils.ACCOUNT_PREFIX+ub.ID, result)
 	return
 }
 
 func (ms *MapStorage) RemoveAccount(key string) (err error) {
 	ms.mu.Lock()
-	defer ms.mu.Unlock()
-	delete(ms.dict, utils.ACCOUNT_PREFIX+key)
+	defer ms.mu.unlockErr(&err)
+	ms.remKey(utils.ACCOUNT_PREFIX + key)
 	return
 }
 
@@ -633,17 +828,17 @@ func (ms *MapStorage) GetCdrStatsQueueDrv(key string) (sq *CDRStatsQueue, err er
 
 func (ms *MapStorage) SetCdrStatsQueueDrv(sq *CDRStatsQueue) (err error) {
 	ms.mu.Lock()
-	defer ms.mu.Unlock()
+	defer ms.mu.unlockErr(&err)
 	result, err := ms.ms.Marshal(sq)
-	ms.dict[utils.CDR_STATS_QUEUE_PREFIX+sq.GetId()] = result
+	ms.setKey(utils.CDR_STATS_QUEUE_PREFIX+sq.GetId(), result)
 	return
 }
 
 func (ms *MapStorage) RemoveCdrStatsQueueDrv(id string) (err error) {
 	ms.mu.Lock()
-	defer ms.mu.Unlock()
+	defer ms.mu.unlockErr(&err)
 
-	delete(ms.dict, id)
+	ms.remKey(id)
 	return
 }
 
@@ -663,27 +858,27 @@ func (ms *MapStorage) GetSubscribersDrv() (result map[string]*SubscriberData, er
 }
 func (ms *MapStorage) SetSubscriberDrv(key string, sub *SubscriberData) (err error) {
 	ms.mu.Lock()
-	defer ms.mu.Unlock()
+	defer ms.mu.unlockErr(&err)
 	result, err := ms.ms.Marshal(sub)
-	ms.dict[utils.PUBSUB_SUBSCRIBERS_PREFIX+key] = result
+	ms.setKey(utils.PUBSUB_SUBSCRIBERS_PREFIX+key, result)
 	return
 }
 
 func (ms *MapStorage) RemoveSubscriberDrv(key string) (err error) {
 	ms.mu.Lock()
-	defer ms.mu.Unlock()
-	delete(ms.dict, utils.PUBSUB_SUBSCRIBERS_PREFIX+key)
+	defer ms.mu.unlockErr(&err)
+	ms.remKey(utils.PUBSUB_SUBSCRIBERS_PREFIX + key)
 	return
 }
 
-func (ms *MapStorage) SetUserDrv(up *UserProfile) error {
+func (ms *MapStorage) SetUserDrv(up *UserProfile) (err error) {
 	ms.mu.Lock()
-	defer ms.mu.Unlock()
+	defer ms.mu.unlockErr(&err)
 	result, err := ms.ms.Marshal(up)
 	if err != nil {
 		return err
 	}
-	ms.dict[utils.USERS_PREFIX+up.GetId()] = result
+	ms.setKey(utils.USERS_PREFIX+up.GetId(), result)
 	return nil
 }
 func (ms *MapStorage) GetUserDrv(key string) (up *UserProfile, err error) {
@@ -712,10 +907,10 @@ func (ms *MapStorage) GetUsersDrv() (result []*UserProfile, err error) {
 	return
 }
 
-func (ms *MapStorage) RemoveUserDrv(key string) error {
+func (ms *MapStorage) RemoveUserDrv(key string) (err error) {
 	ms.mu.Lock()
-	defer ms.mu.Unlock()
-	delete(ms.dict, utils.USERS_PREFIX+key)
+	defer ms.mu.unlockErr(&err)
+	ms.remKey(utils.USERS_PREFIX + key)
 	return nil
 }
 
@@ -747,7 +942,7 @@ func (ms *MapStorage) GetAlias(key string, skipCache bool, transactionID string)
 
 }
 
-func (ms *MapStorage) SetAlias(al *Alias, transactionID string) error {
+func (ms *MapStorage) SetAlias(al *Alias, transactionID string) (err error) {
 
 	result, err := ms.ms.Marshal(al.Values)
 	if err != nil {
@@ -755,8 +950,8 @@ func (ms *MapStorage) SetAlias(al *Alias, transactionID string) error {
 	}
 	key := utils.ALIASES_PREFIX + al.GetId()
 	ms.mu.Lock()
-	defer ms.mu.Unlock()
-	ms.dict[key] = result
+	defer ms.mu.unlockErr(&err)
+	ms.setKey(key, result)
 	cache.RemKey(key, cacheCommit(transactionID), transactionID)
 	return nil
 }
@@ -793,8 +988,10 @@ func (ms *MapStorage) SetReverseAlias(al *Alias, transactionID string) (err erro
 				rKey := strings.Join([]string{utils.REVERSE_ALIASES_PREFIX, alias, target, al.Context}, "")
 				id := utils.ConcatenatedKey(al.GetId(), value.DestinationId)
 				ms.mu.Lock()
-				ms.dict.sadd(rKey, id, ms.ms)
-				ms.mu.Unlock()
+				ms.sadd(rKey, id)
+				if err = ms.mu.unlock(); err != nil {
+					return
+				}
 
 				cache.RemKey(rKey, cCommit, transactionID)
 			}
@@ -803,7 +1000,7 @@ func (ms *MapStorage) SetReverseAlias(al *Alias, transactionID string) (err erro
 	return
 }
 
-func (ms *MapStorage) RemoveAlias(key string, transactionID string) error {
+func (ms *MapStorage) RemoveAlias(key string, transactionID string) (err error) {
 	// get alias for values list
 	al, err := ms.GetAlias(key, false, utils.NonTransactional)
 	if err != nil {
@@ -811,14 +1008,14 @@ func (ms *MapStorage) RemoveAlias(key string, transactionID string) error {
 	}
 
 	ms.mu.Lock()
-	defer ms.mu.Unlock()
+	defer ms.mu.unlockErr(&err)
 	key = utils.ALIASES_PREFIX + key
 
 	aliasValues := make(AliasValues, 0)
 	if values, ok := ms.dict[key]; ok {
 		ms.ms.Unmarshal(values, &aliasValues)
 	}
-	delete(ms.dict, key)
+	ms.remKey(key)
 	cCommit := cacheCommit(transactionID)
 	cache.RemKey(key, cCommit, transactionID)
 	for _, value := range al.Values {
@@ -826,7 +1023,7 @@ func (ms *MapStorage) RemoveAlias(key string, transactionID string) error {
 		for target, pairs := range value.Pairs {
 			for _, alias := range pairs {
 				rKey := utils.REVERSE_ALIASES_PREFIX + alias + target + al.Context
-				ms.dict.srem(rKey, tmpKey, ms.ms)
+				ms.srem(rKey, tmpKey)
 				cache.RemKey(rKey, cCommit, transactionID)
 				/*_, err = ms.GetReverseAlias(rKey, true) // recache
 				if err != nil {
@@ -864,21 +1061,21 @@ func (ms *MapStorage) GetActionTriggersDrv(key string) (atrs ActionTriggers, err
 
 func (ms *MapStorage) SetActionTriggersDrv(key string, atrs ActionTriggers) (err error) {
 	ms.mu.Lock()
-	defer ms.mu.Unlock()
+	defer ms.mu.unlockErr(&err)
 	if len(atrs) == 0 {
 		// delete the key
-		delete(ms.dict, utils.ACTION_TRIGGER_PREFIX+key)
+		ms.remKey(utils.ACTION_TRIGGER_PREFIX + key)
 		return
 	}
 	result, err := ms.ms.Marshal(&atrs)
-	ms.dict[utils.ACTION_TRIGGER_PREFIX+key] = result
+	ms.setKey(utils.ACTION_TRIGGER_PREFIX+key, result)
 	return
 }
 
 func (ms *MapStorage) RemoveActionTriggersDrv(key string) (err error) {
 	ms.mu.Lock()
-	defer ms.mu.Unlock()
-	delete(ms.dict, utils.ACTION_TRIGGER_PREFIX+key)
+	defer ms.mu.unlockErr(&err)
+	ms.remKey(utils.ACTION_TRIGGER_PREFIX + key)
 	return
 }
 
@@ -908,9 +1105,9 @@ func (ms *MapStorage) SetActionPlan(key string, ats *ActionPlan, overwrite bool,
 	cCommit := cacheCommit(transactionID)
 	if len(ats.ActionTimings) == 0 {
 		ms.mu.Lock()
-		defer ms.mu.Unlock()
+		defer ms.mu.unlockErr(&err)
 		// delete the key
-		delete(ms.dict, utils.ACTION_PLAN_PREFIX+key)
+		ms.remKey(utils.ACTION_PLAN_PREFIX + key)
 		cache.RemKey(utils.ACTION_PLAN_PREFIX+key, cCommit, transactionID)
 		return
 	}
@@ -926,18 +1123,18 @@ func (ms *MapStorage) SetActionPlan(key string, ats *ActionPlan, overwrite bool,
 		}
 	}
 	ms.mu.Lock()
-	defer ms.mu.Unlock()
+	defer ms.mu.unlockErr(&err)
 	result, err := ms.ms.Marshal(&ats)
-	ms.dict[utils.ACTION_PLAN_PREFIX+key] = result
+	ms.setKey(utils.ACTION_PLAN_PREFIX+key, result)
 	cache.RemKey(utils.ACTION_PLAN_PREFIX+key, cCommit, transactionID)
 	return
 }
 
-func (ms *MapStorage) RemoveActionPlan(key string, transactionID string) error {
+func (ms *MapStorage) RemoveActionPlan(key string, transactionID string) (err error) {
 	cCommit := cacheCommit(transactionID)
 	ms.mu.Lock()
-	defer ms.mu.Unlock()
-	delete(ms.dict, utils.ACTION_PLAN_PREFIX+key)
+	defer ms.mu.unlockErr(&err)
+	ms.remKey(utils.ACTION_PLAN_PREFIX + key)
 	cache.RemKey(utils.ACTION_PLAN_PREFIX+key, cCommit, transactionID)
 	return nil
 }
@@ -996,12 +1193,12 @@ func (ms *MapStorage) SetAccountActionPlans(acntID string, apIDs []string, overw
 		}
 	}
 	ms.mu.Lock()
-	defer ms.mu.Unlock()
+	defer ms.mu.unlockErr(&err)
 	result, err := ms.ms.Marshal(apIDs)
 	if err != nil {
 		return err
 	}
-	ms.dict[utils.AccountActionPlansPrefix+acntID] = result
+	ms.setKey(utils.AccountActionPlansPrefix+acntID, result)
 
 	return
 }
@@ -1009,7 +1206,11 @@ func (ms *MapStorage) SetAccountActionPlans(acntID string, apIDs []string, overw
 func (ms *MapStorage) RemAccountActionPlans(acntID string, apIDs []string) (err error) {
 	key := utils.AccountActionPlansPrefix + acntID
 	if len(apIDs) == 0 {
-		delete(ms.dict, key)
+		ms.mu.Lock()
+		ms.remKey(key)
+		if err = ms.mu.unlock(); err != nil {
+			return
+		}
 		return
 	}
 	oldaPlIDs, err := ms.GetAccountActionPlans(acntID, true, utils.NonTransactional)
@@ -1024,36 +1225,42 @@ func (ms *MapStorage) RemAccountActionPlans(acntID string, apIDs []string) (err
 		i++
 	}
 	ms.mu.Lock()
-	defer ms.mu.Unlock()
+	defer ms.mu.unlockErr(&err)
 	if len(oldaPlIDs) == 0 {
-		delete(ms.dict, key)
+		ms.remKey(key)
 		return
 	}
 	var result []byte
 	if result, err = ms.ms.Marshal(oldaPlIDs); err != nil {
 		return err
 	}
-	ms.dict[key] = result
+	ms.setKey(key, result)
 	return
 }
 
-func (ms *MapStorage) PushTask(t *Task) error {
+func (ms *MapStorage) PushTask(t *Task) (err error) {
 	ms.mu.Lock()
-	defer ms.mu.Unlock()
+	defer ms.mu.unlockErr(&err)
 	result, err := ms.ms.Marshal(t)
 	if err != nil {
 		return err
 	}
 	ms.tasks = append(ms.tasks, result)
+	if ms.journal != nil {
+		ms.jrnlOps = append(ms.jrnlOps, &mapJournalOp{Type: mapJrnlPushTask, Value: result})
+	}
 	return nil
 }
 
 func (ms *MapStorage) PopTask() (t *Task, err error) {
 	ms.mu.Lock()
-	defer ms.mu.Unlock()
+	defer ms.mu.unlockErr(&err)
 	if len(ms.tasks) > 0 {
 		var values []byte
 		values, ms.tasks = ms.tasks[0], ms.tasks[1:]
+		if ms.journal != nil {
+			ms.jrnlOps = append(ms.jrnlOps, &mapJournalOp{Type: mapJrnlPopTask, prev: values})
+		}
 		t = &Task{}
 		err = ms.ms.Unmarshal(values, t)
 	} else {
@@ -1074,36 +1281,36 @@ func (ms *MapStorage) GetDerivedChargersDrv(key string) (dcs *utils.DerivedCharg
 	return
 }
 
-func (ms *MapStorage) SetDerivedChargers(key string, dcs *utils.DerivedChargers, transactionID string) error {
+func (ms *MapStorage) SetDerivedChargers(key string, dcs *utils.DerivedChargers, transactionID string) (err error) {
 	ms.mu.Lock()
-	defer ms.mu.Unlock()
+	defer ms.mu.unlockErr(&err)
 	cCommit := cacheCommit(transactionID)
 	key = utils.DERIVEDCHARGERS_PREFIX + key
 	if dcs == nil || len(dcs.Chargers) == 0 {
-		delete(ms.dict, key)
+		ms.remKey(key)
 		cache.RemKey(key, cCommit, transactionID)
 		return nil
 	}
 	result, err := ms.ms.Marshal(dcs)
-	ms.dict[key] = result
+	ms.setKey(key, result)
 	cache.RemKey(key, cCommit, transactionID)
 	return err
 }
 
 func (ms *MapStorage) RemoveDerivedChargersDrv(id, transactionID string) (err error) {
 	ms.mu.Lock()
-	defer ms.mu.Unlock()
+	defer ms.mu.unlockErr(&err)
 	cCommit := cacheCommit(transactionID)
-	delete(ms.dict, id)
+	ms.remKey(id)
 	cache.RemKey(id, cCommit, transactionID)
 	return
 }
 
-func (ms *MapStorage) SetCdrStatsDrv(cs *CdrStats) error {
+func (ms *MapStorage) SetCdrStatsDrv(cs *CdrStats) (err error) {
 	ms.mu.Lock()
-	defer ms.mu.Unlock()
+	defer ms.mu.unlockErr(&err)
 	result, err := ms.ms.Marshal(cs)
-	ms.dict[utils.CDR_STATS_PREFIX+cs.Id] = result
+	ms.setKey(utils.CDR_STATS_PREFIX+cs.Id, result)
 	return err
 }
 
@@ -1132,25 +1339,6 @@ func (ms *MapStorage) GetAllCdrStatsDrv() (css []*CdrStats, err error) {
 	return
 }
 
-func (ms *MapStorage) SetSMCost(smCost *SMCost) error {
-	ms.mu.Lock()
-	defer ms.mu.Unlock()
-	result, err := ms.ms.Marshal(smCost)
-	ms.dict[utils.LOG_CALL_COST_PREFIX+smCost.CostSource+smCost.RunID+"_"+smCost.CGRID] = result
-	return err
-}
-
-func (ms *MapStorage) GetSMCost(cgrid, source, runid, originHost, originID string) (smCost *SMCost, err error) {
-	ms.mu.RLock()
-	defer ms.mu.RUnlock()
-	if values, ok := ms.dict[utils.LOG_CALL_COST_PREFIX+source+runid+"_"+cgrid]; ok {
-		err = ms.ms.Unmarshal(values, &smCost)
-	} else {
-		return nil, utils.ErrNotFound
-	}
-	return
-}
-
 func (ms *MapStorage) GetResourceProfileDrv(tenant, id string) (rsp *ResourceProfile, err error) {
 	ms.mu.RLock()
 	defer ms.mu.RUnlock()
@@ -1166,22 +1354,22 @@ func (ms *MapStorage) GetResourceProfileDrv(tenant, id string) (rsp *ResourcePro
 	return
 }
 
-func (ms *MapStorage) SetResourceProfileDrv(r *ResourceProfile) error {
+func (ms *MapStorage) SetResourceProfileDrv(r *ResourceProfile) (err error) {
 	ms.mu.Lock()
-	defer ms.mu.Unlock()
+	defer ms.mu.unlockErr(&err)
 	result, err := ms.ms.Marshal(r)
 	if err != nil {
 		return err
 	}
-	ms.dict[utils.ResourceProfilesPrefix+r.TenantID()] = result
+	ms.setKey(utils.ResourceProfilesPrefix+r.TenantID(), result)
 	return nil
 }
 
-func (ms *MapStorage) RemoveResourceProfileDrv(tenant, id string) error {
+func (ms *MapStorage) RemoveResourceProfileDrv(tenant, id string) (err error) {
 	ms.mu.Lock()
-	defer ms.mu.Unlock()
+	defer ms.mu.unlockErr(&err)
 	key := utils.ResourceProfilesPrefix + utils.ConcatenatedKey(tenant, id)
-	delete(ms.dict, key)
+	ms.remKey(key)
 	return nil
 }
 
@@ -1202,20 +1390,20 @@ func (ms *MapStorage) GetResourceDrv(tenant, id string) (r *Resource, err error)
 
 func (ms *MapStorage) SetResourceDrv(r *Resource) (err error) {
 	ms.mu.Lock()
-	defer ms.mu.Unlock()
+	defer ms.mu.unlockErr(&err)
 	result, err := ms.ms.Marshal(r)
 	if err != nil {
 		return err
 	}
-	ms.dict[utils.ResourcesPrefix+r.TenantID()] = result
+	ms.setKey(utils.ResourcesPrefix+r.TenantID(), result)
 	return
 }
 
 func (ms *MapStorage) RemoveResourceDrv(tenant, id string) (err error) {
 	ms.mu.Lock()
-	defer ms.mu.Unlock()
+	defer ms.mu.unlockErr(&err)
 	key := utils.ResourcesPrefix + utils.ConcatenatedKey(tenant, id)
-	delete(ms.dict, key)
+	ms.remKey(key)
 	return
 }
 
@@ -1234,23 +1422,23 @@ func (ms *MapStorage) GetTimingDrv(id string) (t *utils.TPTiming, err error) {
 
 }
 
-func (ms *MapStorage) SetTimingDrv(t *utils.TPTiming) error {
+func (ms *MapStorage) SetTimingDrv(t *utils.TPTiming) (err error) {
 	ms.mu.Lock()
-	defer ms.mu.Unlock()
+	defer ms.mu.unlockErr(&err)
 	result, err := ms.ms.Marshal(t)
 	if err != nil {
 		return err
 	}
 	key := utils.TimingsPrefix + t.ID
-	ms.dict[key] = result
+	ms.setKey(key, result)
 	return nil
 }
 
-func (ms *MapStorage) RemoveTimingDrv(id string) error {
+func (ms *MapStorage) RemoveTimingDrv(id string) (err error) {
 	ms.mu.Lock()
-	defer ms.mu.Unlock()
+	defer ms.mu.unlockErr(&err)
 	key := utils.TimingsPrefix + id
-	delete(ms.dict, key)
+	ms.remKey(key)
 	return nil
 }
 
@@ -1290,19 +1478,19 @@ func (ms *MapStorage) GetFilterIndexesDrv(dbKey string,
 
 func (ms *MapStorage) SetFilterIndexesDrv(dbKey string, indexes map[string]map[string]utils.StringMap) (err error) {
 	ms.mu.Lock()
-	defer ms.mu.Unlock()
+	defer ms.mu.unlockErr(&err)
 	result, err := ms.ms.Marshal(indexes)
 	if err != nil {
 		return err
 	}
-	ms.dict[dbKey] = result
+	ms.setKey(dbKey, result)
 	return
 }
 
 func (ms *MapStorage) RemoveFilterIndexesDrv(id string) (err error) {
 	ms.mu.Lock()
-	defer ms.mu.Unlock()
-	delete(ms.dict, id)
+	defer ms.mu.unlockErr(&err)
+	ms.remKey(id)
 	return
 }
 
@@ -1342,19 +1530,19 @@ func (ms *MapStorage) GetFilterReverseIndexesDrv(dbKey string,
 
 func (ms *MapStorage) SetFilterReverseIndexesDrv(dbKey string, indexes map[string]map[string]utils.StringMap) (err error) {
 	ms.mu.Lock()
-	defer ms.mu.Unlock()
+	defer ms.mu.unlockErr(&err)
 	result, err := ms.ms.Marshal(indexes)
 	if err != nil {
 		return err
 	}
-	ms.dict[dbKey] = result
+	ms.setKey(dbKey, result)
 	return
 }
 
 func (ms *MapStorage) RemoveFilterReverseIndexesDrv(dbKey, itemID string) (err error) {
 	ms.mu.Lock()
-	defer ms.mu.Unlock()
-	delete(ms.dict, utils.ConcatenatedKey(dbKey, itemID))
+	defer ms.mu.unlockErr(&err)
+	ms.remKey(utils.ConcatenatedKey(dbKey, itemID))
 	return
 }
 
@@ -1397,21 +1585,21 @@ func (ms *MapStorage) GetStatQueueProfileDrv(tenant string, id string) (sq *Stat
 // SetStatsQueueDrv stores a StatsQueue into DataDB
 func (ms *MapStorage) SetStatQueueProfileDrv(sqp *StatQueueProfile) (err error) {
 	ms.mu.Lock()
-	defer ms.mu.Unlock()
+	defer ms.mu.unlockErr(&err)
 	result, err := ms.ms.Marshal(sqp)
 	if err != nil {
 		return err
 	}
-	ms.dict[utils.StatQueueProfilePrefix+utils.ConcatenatedKey(sqp.Tenant, sqp.ID)] = result
+	ms.setKey(utils.StatQueueProfilePrefix+utils.ConcatenatedKey(sqp.Tenant, sqp.ID), result)
 	return
 }
 
 // RemStatsQueueDrv removes a StatsQueue from dataDB
 func (ms *MapStorage) RemStatQueueProfileDrv(tenant, id string) (err error) {
 	ms.mu.Lock()
-	defer ms.mu.Unlock()
+	defer ms.mu.unlockErr(&err)
 	key := utils.StatQueueProfilePrefix + utils.ConcatenatedKey(tenant, id)
-	delete(ms.dict, key)
+	ms.remKey(key)
 	return
 }
 
@@ -1430,21 +1618,21 @@ func (ms *MapStorage) GetStoredStatQueueDrv(tenant, id string) (sq *StoredStatQu
 // SetStatQueue stores the metrics for a StatsQueue
 func (ms *MapStorage) SetStoredStatQueueDrv(sq *StoredStatQueue) (err error) {
 	ms.mu.Lock()
-	defer ms.mu.Unlock()
+	defer ms.mu.unlockErr(&err)
 	var result []byte
 	result, err = ms.ms.Marshal(sq)
 	if err != nil {
 		return err
 	}
-	ms.dict[utils.StatQueuePrefix+sq.SqID()] = result
+	ms.setKey(utils.StatQueuePrefix+sq.SqID(), result)
 	return
 }
 
 // RemStatQueue removes a StatsQueue
 func (ms *MapStorage) RemStoredStatQueueDrv(tenant, id string) (err error) {
 	ms.mu.Lock()
-	defer ms.mu.Unlock()
-	delete(ms.dict, utils.StatQueuePrefix+utils.ConcatenatedKey(tenant, id))
+	defer ms.mu.unlockErr(&err)
+	ms.remKey(utils.StatQueuePrefix + utils.ConcatenatedKey(tenant, id))
 	return
 }
 
@@ -1467,21 +1655,21 @@ func (ms *MapStorage) GetThresholdProfileDrv(tenant, ID string) (tp *ThresholdPr
 // SetThresholdProfileDrv stores a ThresholdProfile into DataDB
 func (ms *MapStorage) SetThresholdProfileDrv(tp *ThresholdProfile) (err error) {
 	ms.mu.Lock()
-	defer ms.mu.Unlock()
+	defer ms.mu.unlockErr(&err)
 	result, err := ms.ms.Marshal(tp)
 	if err != nil {
 		return err
 	}
-	ms.dict[utils.ThresholdProfilePrefix+tp.TenantID()] = result
+	ms.setKey(utils.ThresholdProfilePrefix+tp.TenantID(), result)
 	return
 }
 
 // RemThresholdProfile removes a ThresholdProfile from dataDB/cache
 func (ms *MapStorage) RemThresholdProfileDrv(tenant, id string) (err error) {
 	ms.mu.Lock()
-	defer ms.mu.Unlock()
+	defer ms.mu.unlockErr(&err)
 	key := utils.ThresholdProfilePrefix + utils.ConcatenatedKey(tenant, id)
-	delete(ms.dict, key)
+	ms.remKey(key)
 	return
 }
 
@@ -1502,20 +1690,20 @@ func (ms *MapStorage) GetThresholdDrv(tenant, id string) (r *Threshold, err erro
 
 func (ms *MapStorage) SetThresholdDrv(r *Threshold) (err error) {
 	ms.mu.Lock()
-	defer ms.mu.Unlock()
+	defer ms.mu.unlockErr(&err)
 	result, err := ms.ms.Marshal(r)
 	if err != nil {
 		return err
 	}
-	ms.dict[utils.ThresholdPrefix+utils.ConcatenatedKey(r.Tenant, r.ID)] = result
+	ms.setKey(utils.ThresholdPrefix+utils.ConcatenatedKey(r.Tenant, r.ID), result)
 	return
 }
 
 func (ms *MapStorage) RemoveThresholdDrv(tenant, id string) (err error) {
 	ms.mu.Lock()
-	defer ms.mu.Unlock()
+	defer ms.mu.unlockErr(&err)
 	key := utils.ThresholdPrefix + utils.ConcatenatedKey(tenant, id)
-	delete(ms.dict, key)
+	ms.remKey(key)
 	return
 }
 
@@ -1540,20 +1728,20 @@ func (ms *MapStorage) GetFilterDrv(tenant, id string) (r *Filter, err error) {
 
 func (ms *MapStorage) SetFilterDrv(r *Filter) (err error) {
 	ms.mu.Lock()
-	defer ms.mu.Unlock()
+	defer ms.mu.unlockErr(&err)
 	result, err := ms.ms.Marshal(r)
 	if err != nil {
 		return err
 	}
-	ms.dict[utils.FilterPrefix+utils.ConcatenatedKey(r.Tenant, r.ID)] = result
+	ms.setKey(utils.FilterPrefix+utils.ConcatenatedKey(r.Tenant, r.ID), result)
 	return
 }
 
 func (ms *MapStorage) RemoveFilterDrv(tenant, id string) (err error) {
 	ms.mu.Lock()
-	defer ms.mu.Unlock()
+	defer ms.mu.unlockErr(&err)
 	key := utils.FilterPrefix + utils.ConcatenatedKey(tenant, id)
-	delete(ms.dict, key)
+	ms.remKey(key)
 	return
 }
 
@@ -1573,20 +1761,20 @@ func (ms *MapStorage) GetSupplierProfileDrv(tenant, id string) (r *SupplierProfi
 
 func (ms *MapStorage) SetSupplierProfileDrv(r *SupplierProfile) (err error) {
 	ms.mu.Lock()
-	defer ms.mu.Unlock()
+	defer ms.mu.unlockErr(&err)
 	result, err := ms.ms.Marshal(r)
 	if err != nil {
 		return err
 	}
-	ms.dict[utils.SupplierProfilePrefix+utils.ConcatenatedKey(r.Tenant, r.ID)] = result
+	ms.setKey(utils.SupplierProfilePrefix+utils.ConcatenatedKey(r.Tenant, r.ID), result)
 	return
 }
 
 func (ms *MapStorage) RemoveSupplierProfileDrv(tenant, id string) (err error) {
 	ms.mu.Lock()
-	defer ms.mu.Unlock()
+	defer ms.mu.unlockErr(&err)
 	key := utils.SupplierProfilePrefix + utils.ConcatenatedKey(tenant, id)
-	delete(ms.dict, key)
+	ms.remKey(key)
 	return
 }
 
@@ -1606,26 +1794,125 @@ func (ms *MapStorage) GetAttributeProfileDrv(tenant, id string) (r *AttributePro
 
 func (ms *MapStorage) SetAttributeProfileDrv(r *AttributeProfile) (err error) {
 	ms.mu.Lock()
-	defer ms.mu.Unlock()
+	defer ms.mu.unlockErr(&err)
 	result, err := ms.ms.Marshal(r)
 	if err != nil {
 		return err
 	}
-	ms.dict[utils.AttributeProfilePrefix+utils.ConcatenatedKey(r.Tenant, r.ID)] = result
+	ms.setKey(utils.AttributeProfilePrefix+utils.ConcatenatedKey(r.Tenant, r.ID), result)
 	return
 }
 
 func (ms *MapStorage) RemoveAttributeProfileDrv(tenant, id string) (err error) {
 	ms.mu.Lock()
-	defer ms.mu.Unlock()
+	defer ms.mu.unlockErr(&err)
 	key := utils.AttributeProfilePrefix + utils.ConcatenatedKey(tenant, id)
-	delete(ms.dict, key)
+	ms.remKey(key)
 	return
 }
 
-func (ms *MapStorage) GetVersions(itm string) (vrs Versions, err error) {
+func (ms *MapStorage) GetExchangeRateDrv(fromCurrency, toCurrency string) (r *ExchangeRate, err error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+	values, ok := ms.dict[utils.ExchangeRatePrefix+utils.ConcatenatedKey(fromCurrency, toCurrency)]
+	if !ok {
+		return nil, utils.ErrNotFound
+	}
+	err = ms.ms.Unmarshal(values, &r)
+	if err != nil {
+		return nil, err
+	}
+	return
+}
+
+func (ms *MapStorage) SetExchangeRateDrv(r *ExchangeRate) (err error) {
 	ms.mu.Lock()
-	defer ms.mu.Unlock()
+	defer ms.mu.unlockErr(&err)
+	result, err := ms.ms.Marshal(r)
+	if err != nil {
+		return err
+	}
+	ms.setKey(utils.ExchangeRatePrefix+r.ID(), result)
+	return
+}
+
+func (ms *MapStorage) RemoveExchangeRateDrv(fromCurrency, toCurrency string) (err error) {
+	ms.mu.Lock()
+	defer ms.mu.unlockErr(&err)
+	key := utils.ExchangeRatePrefix + utils.ConcatenatedKey(fromCurrency, toCurrency)
+	ms.remKey(key)
+	return
+}
+
+func (ms *MapStorage) GetTaxProfileDrv(tenant, id string) (r *TaxProfile, err error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+	values, ok := ms.dict[utils.TaxProfilePrefix+utils.ConcatenatedKey(tenant, id)]
+	if !ok {
+		return nil, utils.ErrNotFound
+	}
+	err = ms.ms.Unmarshal(values, &r)
+	if err != nil {
+		return nil, err
+	}
+	return
+}
+
+func (ms *MapStorage) SetTaxProfileDrv(r *TaxProfile) (err error) {
+	ms.mu.Lock()
+	defer ms.mu.unlockErr(&err)
+	result, err := ms.ms.Marshal(r)
+	if err != nil {
+		return err
+	}
+	ms.setKey(utils.TaxProfilePrefix+r.TenantID(), result)
+	return
+}
+
+func (ms *MapStorage) RemoveTaxProfileDrv(tenant, id string) (err error) {
+	ms.mu.Lock()
+	defer ms.mu.unlockErr(&err)
+	key := utils.TaxProfilePrefix + utils.ConcatenatedKey(tenant, id)
+	ms.remKey(key)
+	return
+}
+
+func (ms *MapStorage) GetFeeProfileDrv(tenant, id string) (r *FeeProfile, err error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+	values, ok := ms.dict[utils.FeeProfilePrefix+utils.ConcatenatedKey(tenant, id)]
+	if !ok {
+		return nil, utils.ErrNotFound
+	}
+	err = ms.ms.Unmarshal(values, &r)
+	if err != nil {
+		return nil, err
+	}
+	return
+}
+
+func (ms *MapStorage) SetFeeProfileDrv(r *FeeProfile) (err error) {
+	ms.mu.Lock()
+	defer ms.mu.unlockErr(&err)
+	result, err := ms.ms.Marshal(r)
+	if err != nil {
+		return err
+	}
+	ms.setKey(utils.FeeProfilePrefix+r.TenantID(), result)
+	return
+}
+
+func (ms *MapStorage) RemoveFeeProfileDrv(tenant, id string) (err error) {
+	ms.mu.Lock()
+	defer ms.mu.unlockErr(&err)
+	key := utils.FeeProfilePrefix + utils.ConcatenatedKey(tenant, id)
+	ms.remKey(key)
+	return
+}
+
+func (ms *MapStorage) GetVersions(itm string) (vrs Versions, err error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
 	values, ok := ms.dict[itm]
 	if !ok {
 		return nil, utils.ErrNotFound
@@ -1639,42 +1926,30 @@ func (ms *MapStorage) GetVersions(itm string) (vrs Versions, err error) {
 
 func (ms *MapStorage) SetVersions(vrs Versions, overwrite bool) (err error) {
 	ms.mu.Lock()
-	defer ms.mu.Unlock()
-	var result []byte
-	var x Versions
+	defer ms.mu.unlockErr(&err)
+	x := make(Versions)
 	if !overwrite {
-		x, err = ms.GetVersions(utils.TBLVersions)
-		if err != nil {
-			return err
-		}
-		for key, _ := range vrs {
-			if x[key] != vrs[key] {
-				x[key] = vrs[key]
+		if values, ok := ms.dict[utils.TBLVersions]; ok {
+			if err = ms.ms.Unmarshal(values, &x); err != nil {
+				return err
 			}
 		}
-		result, err = ms.ms.Marshal(x)
-		if err != nil {
-			return err
-		}
-		ms.dict[utils.TBLVersions] = result
-		return
-	} else {
-		result, err = ms.ms.Marshal(vrs)
-		if err != nil {
-			return err
-		}
-		if ms.RemoveVersions(vrs); err != nil {
-			return err
-		}
-		ms.dict[utils.TBLVersions] = result
-		return
 	}
+	for key := range vrs {
+		x[key] = vrs[key]
+	}
+	result, err := ms.ms.Marshal(x)
+	if err != nil {
+		return err
+	}
+	ms.setKey(utils.TBLVersions, result)
+	return
 }
 
 func (ms *MapStorage) RemoveVersions(vrs Versions) (err error) {
 	ms.mu.Lock()
-	defer ms.mu.Unlock()
-	delete(ms.dict, utils.TBLVersions)
+	defer ms.mu.unlockErr(&err)
+	ms.remKey(utils.TBLVersions)
 	return
 }
 
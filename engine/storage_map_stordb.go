@@ -0,0 +1,719 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package engine
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cgrates/cgrates/utils"
+)
+
+// mapTPItemKey builds the key of a TP item in the form <table>:<tpid>:<id>
+func mapTPItemKey(table, tpid string, ids ...string) string {
+	return utils.ConcatenatedKey(table, tpid, utils.ConcatenatedKey(ids...))
+}
+
+// mapTPFieldName normalizes the field names used in filters (eg: SQL column names) to the ones of the TP structs
+func mapTPFieldName(fldName string) string {
+	fldName = strings.ToLower(strings.Replace(fldName, "_", "", -1))
+	if fldName == "tag" { // API uses tag to be compatible with SQL models
+		fldName = "id"
+	}
+	return fldName
+}
+
+// mapTPFieldValue returns the value of a field within a TP item, matching names case insensitive
+func mapTPFieldValue(item reflect.Value, fldName string) (string, bool) {
+	item = reflect.Indirect(item)
+	fldName = mapTPFieldName(fldName)
+	for i := 0; i < item.NumField(); i++ {
+		if strings.ToLower(item.Type().Field(i).Name) == fldName {
+			return fmt.Sprintf("%v", item.Field(i).Interface()), true
+		}
+	}
+	return "", false
+}
+
+// mapTPItemPasses checks the TP item against the filter and the search term of the paginator
+func mapTPItemPasses(item reflect.Value, filter map[string]string, searchFlds []string, searchTerm string) bool {
+	for fldName, fldVal := range filter {
+		if fldVal == "" {
+			continue
+		}
+		if itmVal, has := mapTPFieldValue(item, fldName); !has || itmVal != fldVal {
+			return false
+		}
+	}
+	if searchTerm == "" {
+		return true
+	}
+	for _, fldName := range searchFlds {
+		if itmVal, _ := mapTPFieldValue(item, fldName); strings.Contains(itmVal, searchTerm) {
+			return true
+		}
+	}
+	return false
+}
+
+// setTPItems stores the items (slice of TP structs) indexed on TPid and the values of idFlds
+func (ms *MapStorage) setTPItems(table string, items interface{}, idFlds ...string) (err error) {
+	itmsVal := reflect.ValueOf(items)
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	for i := 0; i < itmsVal.Len(); i++ {
+		itm := itmsVal.Index(i)
+		tpid, _ := mapTPFieldValue(itm, "TPid")
+		ids := make([]string, len(idFlds))
+		for j, idFld := range idFlds {
+			ids[j], _ = mapTPFieldValue(itm, idFld)
+		}
+		var result []byte
+		if result, err = ms.ms.Marshal(itm.Interface()); err != nil {
+			return
+		}
+		ms.setKey(mapTPItemKey(table, tpid, ids...), result)
+	}
+	return
+}
+
+// getTPItems populates items (pointer to slice of TP structs) with the ones stored in table matching tpid and filter
+// items are returned sorted on their key so pagination is consistent
+func (ms *MapStorage) getTPItems(table, tpid string, filter map[string]string, pag *utils.Paginator, items interface{}) (err error) {
+	slcVal := reflect.ValueOf(items).Elem()
+	itmType := slcVal.Type().Elem().Elem()
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+	keyPrfx := utils.ConcatenatedKey(table, tpid) + utils.CONCATENATED_KEY_SEP
+	var keys []string
+	for key := range ms.dict {
+		if strings.HasPrefix(key, keyPrfx) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		itm := reflect.New(itmType)
+		if err = ms.ms.Unmarshal(ms.dict[key], itm.Interface()); err != nil {
+			return
+		}
+		if !mapTPItemPasses(itm, filter, nil, "") {
+			continue
+		}
+		slcVal.Set(reflect.Append(slcVal, itm))
+	}
+	if pag != nil {
+		slcVal.Set(mapPaginate(slcVal, pag.Limit, pag.Offset))
+	}
+	if slcVal.Len() == 0 {
+		return utils.ErrNotFound
+	}
+	return
+}
+
+// mapPaginate applies limit and offset on the slice
+func mapPaginate(slcVal reflect.Value, limit, offset *int) reflect.Value {
+	start, end := 0, slcVal.Len()
+	if offset != nil {
+		start = *offset
+	}
+	if start > end {
+		start = end
+	}
+	if limit != nil && start+*limit < end {
+		end = start + *limit
+	}
+	return slcVal.Slice(start, end)
+}
+
+func (ms *MapStorage) GetTpIds(colName string) ([]string, error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+	tpidMap := make(utils.StringMap)
+	for key := range ms.dict {
+		keySplt := strings.SplitN(key, utils.CONCATENATED_KEY_SEP, 3)
+		if len(keySplt) != 3 || !strings.HasPrefix(keySplt[0], "tp_") ||
+			(colName != "" && keySplt[0] != colName) {
+			continue
+		}
+		tpidMap[keySplt[1]] = true
+	}
+	tpids := tpidMap.Slice()
+	sort.Strings(tpids)
+	return tpids, nil
+}
+
+func (ms *MapStorage) GetTpTableIds(tpid, table string, distinct utils.TPDistinctIds, filter map[string]string, pag *utils.Paginator) ([]string, error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+	keyPrfx := table + utils.CONCATENATED_KEY_SEP
+	if tpid != "" {
+		keyPrfx = utils.ConcatenatedKey(table, tpid) + utils.CONCATENATED_KEY_SEP
+	}
+	var searchTerm string
+	if pag != nil {
+		searchTerm = pag.SearchTerm
+	}
+	distinctIds := make(utils.StringMap)
+	for key, values := range ms.dict {
+		if !strings.HasPrefix(key, keyPrfx) {
+			continue
+		}
+		var itmMp map[string]interface{} // TP items are decoded generically since we only need field values
+		if err := ms.ms.Unmarshal(values, &itmMp); err != nil {
+			return nil, err
+		}
+		itm := mapTPItemFromMap(itmMp)
+		if !mapTPItemPasses(itm, filter, distinct, searchTerm) {
+			continue
+		}
+		ids := make([]string, len(distinct))
+		for i, fldName := range distinct {
+			ids[i], _ = mapTPFieldValue(itm, fldName)
+		}
+		distinctIds[strings.Join(ids, utils.CONCATENATED_KEY_SEP)] = true
+	}
+	ids := distinctIds.Slice()
+	sort.Strings(ids)
+	if pag != nil {
+		ids = mapPaginate(reflect.ValueOf(ids), pag.Limit, pag.Offset).Interface().([]string)
+	}
+	return ids, nil
+}
+
+// mapTPItemFromMap converts a generically decoded TP item into a struct value usable by mapTPFieldValue
+func mapTPItemFromMap(itmMp map[string]interface{}) reflect.Value {
+	flds := make([]reflect.StructField, 0, len(itmMp))
+	vals := make([]interface{}, 0, len(itmMp))
+	for fldName, val := range itmMp {
+		if fldName == "" || strings.ToUpper(fldName[:1]) != fldName[:1] { // only exported fields can be used
+			continue
+		}
+		flds = append(flds, reflect.StructField{Name: fldName, Type: reflect.TypeOf("")})
+		vals = append(vals, val)
+	}
+	itm := reflect.New(reflect.StructOf(flds)).Elem()
+	for i, val := range vals {
+		if val != nil {
+			itm.Field(i).SetString(fmt.Sprintf("%v", val))
+		}
+	}
+	return itm
+}
+
+func (ms *MapStorage) RemTpData(table, tpid string, args map[string]string) error {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	for key, values := range ms.dict {
+		keySplt := strings.SplitN(key, utils.CONCATENATED_KEY_SEP, 3)
+		if len(keySplt) != 3 || !strings.HasPrefix(keySplt[0], "tp_") ||
+			(table != "" && keySplt[0] != table) ||
+			(tpid != "" && keySplt[1] != tpid) {
+			continue
+		}
+		if len(args) != 0 {
+			var itmMp map[string]interface{}
+			if err := ms.ms.Unmarshal(values, &itmMp); err != nil {
+				return err
+			}
+			if !mapTPItemPasses(mapTPItemFromMap(itmMp), args, nil, "") {
+				continue
+			}
+		}
+		ms.remKey(key)
+	}
+	return nil
+}
+
+func (ms *MapStorage) GetTPTimings(tpid, id string) (tps []*utils.ApierTPTiming, err error) {
+	err = ms.getTPItems(utils.TBLTPTimings, tpid, map[string]string{utils.ID: id}, nil, &tps)
+	return
+}
+
+func (ms *MapStorage) GetTPDestinations(tpid, id string) (tps []*utils.TPDestination, err error) {
+	err = ms.getTPItems(utils.TBLTPDestinations, tpid, map[string]string{utils.ID: id}, nil, &tps)
+	return
+}
+
+func (ms *MapStorage) GetTPRates(tpid, id string) (tps []*utils.TPRate, err error) {
+	if err = ms.getTPItems(utils.TBLTPRates, tpid, map[string]string{utils.ID: id}, nil, &tps); err != nil {
+		return
+	}
+	for _, r := range tps {
+		for _, rs := range r.RateSlots {
+			rs.SetDurations()
+		}
+	}
+	return
+}
+
+func (ms *MapStorage) GetTPDestinationRates(tpid, id string, pag *utils.Paginator) (tps []*utils.TPDestinationRate, err error) {
+	err = ms.getTPItems(utils.TBLTPDestinationRates, tpid, map[string]string{utils.ID: id}, pag, &tps)
+	return
+}
+
+func (ms *MapStorage) GetTPRatingPlans(tpid, id string, pag *utils.Paginator) (tps []*utils.TPRatingPlan, err error) {
+	err = ms.getTPItems(utils.TBLTPRatingPlans, tpid, map[string]string{utils.ID: id}, pag, &tps)
+	return
+}
+
+func (ms *MapStorage) GetTPRatingProfiles(tp *utils.TPRatingProfile) (tps []*utils.TPRatingProfile, err error) {
+	err = ms.getTPItems(utils.TBLTPRateProfiles, tp.TPid,
+		map[string]string{"LoadId": tp.LoadId, utils.DIRECTION: tp.Direction, utils.Tenant: tp.Tenant,
+			utils.Category: tp.Category, utils.SUBJECT: tp.Subject}, nil, &tps)
+	return
+}
+
+func (ms *MapStorage) GetTPSharedGroups(tpid, id string) (tps []*utils.TPSharedGroups, err error) {
+	err = ms.getTPItems(utils.TBLTPSharedGroups, tpid, map[string]string{utils.ID: id}, nil, &tps)
+	return
+}
+
+func (ms *MapStorage) GetTPCdrStats(tpid, id string) (tps []*utils.TPCdrStats, err error) {
+	err = ms.getTPItems(utils.TBLTPCdrStats, tpid, map[string]string{utils.ID: id}, nil, &tps)
+	return
+}
+
+func (ms *MapStorage) GetTPLCRs(tp *utils.TPLcrRules) (tps []*utils.TPLcrRules, err error) {
+	err = ms.getTPItems(utils.TBLTPLcrs, tp.TPid,
+		map[string]string{utils.DIRECTION: tp.Direction, utils.Tenant: tp.Tenant, utils.Category: tp.Category,
+			utils.Account: tp.Account, utils.SUBJECT: tp.Subject}, nil, &tps)
+	return
+}
+
+func (ms *MapStorage) GetTPUsers(tp *utils.TPUsers) (tps []*utils.TPUsers, err error) {
+	err = ms.getTPItems(utils.TBLTPUsers, tp.TPid,
+		map[string]string{utils.Tenant: tp.Tenant, "UserName": tp.UserName}, nil, &tps)
+	return
+}
+
+func (ms *MapStorage) GetTPAliases(tp *utils.TPAliases) (tps []*utils.TPAliases, err error) {
+	err = ms.getTPItems(utils.TBLTPAliases, tp.TPid,
+		map[string]string{utils.DIRECTION: tp.Direction, utils.Tenant: tp.Tenant, utils.Category: tp.Category,
+			utils.Account: tp.Account, utils.SUBJECT: tp.Subject, utils.Context: tp.Context}, nil, &tps)
+	return
+}
+
+func (ms *MapStorage) GetTPDerivedChargers(tp *utils.TPDerivedChargers) (tps []*utils.TPDerivedChargers, err error) {
+	err = ms.getTPItems(utils.TBLTPDerivedChargers, tp.TPid,
+		map[string]string{"LoadId": tp.LoadId, utils.DIRECTION: tp.Direction, utils.Tenant: tp.Tenant,
+			utils.Category: tp.Category, utils.Account: tp.Account, utils.SUBJECT: tp.Subject}, nil, &tps)
+	return
+}
+
+func (ms *MapStorage) GetTPActions(tpid, id string) (tps []*utils.TPActions, err error) {
+	err = ms.getTPItems(utils.TBLTPActions, tpid, map[string]string{utils.ID: id}, nil, &tps)
+	return
+}
+
+func (ms *MapStorage) GetTPActionPlans(tpid, id string) (tps []*utils.TPActionPlan, err error) {
+	err = ms.getTPItems(utils.TBLTPActionPlans, tpid, map[string]string{utils.ID: id}, nil, &tps)
+	return
+}
+
+func (ms *MapStorage) GetTPActionTriggers(tpid, id string) (tps []*utils.TPActionTriggers, err error) {
+	err = ms.getTPItems(utils.TBLTPActionTriggers, tpid, map[string]string{utils.ID: id}, nil, &tps)
+	return
+}
+
+func (ms *MapStorage) GetTPAccountActions(tp *utils.TPAccountActions) (tps []*utils.TPAccountActions, err error) {
+	err = ms.getTPItems(utils.TBLTPAccountActions, tp.TPid,
+		map[string]string{"LoadId": tp.LoadId, utils.Tenant: tp.Tenant, utils.Account: tp.Account}, nil, &tps)
+	return
+}
+
+func (ms *MapStorage) GetTPResources(tpid, id string) (tps []*utils.TPResource, err error) {
+	err = ms.getTPItems(utils.TBLTPResources, tpid, map[string]string{utils.ID: id}, nil, &tps)
+	return
+}
+
+func (ms *MapStorage) GetTPStats(tpid, id string) (tps []*utils.TPStats, err error) {
+	err = ms.getTPItems(utils.TBLTPStats, tpid, map[string]string{utils.ID: id}, nil, &tps)
+	return
+}
+
+func (ms *MapStorage) GetTPThresholds(tpid, id string) (tps []*utils.TPThreshold, err error) {
+	err = ms.getTPItems(utils.TBLTPThresholds, tpid, map[string]string{utils.ID: id}, nil, &tps)
+	return
+}
+
+func (ms *MapStorage) GetTPFilters(tpid, id string) (tps []*utils.TPFilterProfile, err error) {
+	err = ms.getTPItems(utils.TBLTPFilters, tpid, map[string]string{utils.ID: id}, nil, &tps)
+	return
+}
+
+func (ms *MapStorage) GetTPSuppliers(tpid, id string) (tps []*utils.TPSupplierProfile, err error) {
+	err = ms.getTPItems(utils.TBLTPSuppliers, tpid, map[string]string{utils.ID: id}, nil, &tps)
+	return
+}
+
+func (ms *MapStorage) GetTPAttributes(tpid, id string) (tps []*utils.TPAttributeProfile, err error) {
+	err = ms.getTPItems(utils.TBLTPAttributes, tpid, map[string]string{utils.ID: id}, nil, &tps)
+	return
+}
+
+func (ms *MapStorage) GetTPExchangeRates(tpid, fromCurrency, toCurrency string) (tps []*utils.TPExchangeRate, err error) {
+	err = ms.getTPItems(utils.TBLTPExchangeRates, tpid,
+		map[string]string{"FromCurrency": fromCurrency, "ToCurrency": toCurrency}, nil, &tps)
+	return
+}
+
+func (ms *MapStorage) GetTPTaxProfiles(tpid, id string) (tps []*utils.TPTaxProfile, err error) {
+	err = ms.getTPItems(utils.TBLTPTaxProfiles, tpid, map[string]string{utils.ID: id}, nil, &tps)
+	return
+}
+
+func (ms *MapStorage) GetTPFeeProfiles(tpid, id string) (tps []*utils.TPFeeProfile, err error) {
+	err = ms.getTPItems(utils.TBLTPFeeProfiles, tpid, map[string]string{utils.ID: id}, nil, &tps)
+	return
+}
+
+func (ms *MapStorage) SetTPTimings(tps []*utils.ApierTPTiming) error {
+	return ms.setTPItems(utils.TBLTPTimings, tps, utils.ID)
+}
+
+func (ms *MapStorage) SetTPDestinations(tps []*utils.TPDestination) error {
+	return ms.setTPItems(utils.TBLTPDestinations, tps, utils.ID)
+}
+
+func (ms *MapStorage) SetTPRates(tps []*utils.TPRate) error {
+	return ms.setTPItems(utils.TBLTPRates, tps, utils.ID)
+}
+
+func (ms *MapStorage) SetTPDestinationRates(tps []*utils.TPDestinationRate) error {
+	return ms.setTPItems(utils.TBLTPDestinationRates, tps, utils.ID)
+}
+
+func (ms *MapStorage) SetTPRatingPlans(tps []*utils.TPRatingPlan) error {
+	return ms.setTPItems(utils.TBLTPRatingPlans, tps, utils.ID)
+}
+
+func (ms *MapStorage) SetTPRatingProfiles(tps []*utils.TPRatingProfile) error {
+	return ms.setTPItems(utils.TBLTPRateProfiles, tps,
+		"LoadId", utils.DIRECTION, utils.Tenant, utils.Category, utils.SUBJECT)
+}
+
+func (ms *MapStorage) SetTPSharedGroups(tps []*utils.TPSharedGroups) error {
+	return ms.setTPItems(utils.TBLTPSharedGroups, tps, utils.ID)
+}
+
+func (ms *MapStorage) SetTPCdrStats(tps []*utils.TPCdrStats) error {
+	return ms.setTPItems(utils.TBLTPCdrStats, tps, utils.ID)
+}
+
+func (ms *MapStorage) SetTPUsers(tps []*utils.TPUsers) error {
+	return ms.setTPItems(utils.TBLTPUsers, tps, utils.Tenant, "UserName")
+}
+
+func (ms *MapStorage) SetTPAliases(tps []*utils.TPAliases) error {
+	return ms.setTPItems(utils.TBLTPAliases, tps,
+		utils.DIRECTION, utils.Tenant, utils.Category, utils.Account, utils.SUBJECT, utils.Context)
+}
+
+func (ms *MapStorage) SetTPDerivedChargers(tps []*utils.TPDerivedChargers) error {
+	return ms.setTPItems(utils.TBLTPDerivedChargers, tps,
+		"LoadId", utils.DIRECTION, utils.Tenant, utils.Category, utils.Account, utils.SUBJECT)
+}
+
+func (ms *MapStorage) SetTPLCRs(tps []*utils.TPLcrRules) error {
+	return ms.setTPItems(utils.TBLTPLcrs, tps,
+		utils.DIRECTION, utils.Tenant, utils.Category, utils.Account, utils.SUBJECT)
+}
+
+func (ms *MapStorage) SetTPActions(tps []*utils.TPActions) error {
+	return ms.setTPItems(utils.TBLTPActions, tps, utils.ID)
+}
+
+func (ms *MapStorage) SetTPActionPlans(tps []*utils.TPActionPlan) error {
+	return ms.setTPItems(utils.TBLTPActionPlans, tps, utils.ID)
+}
+
+func (ms *MapStorage) SetTPActionTriggers(tps []*utils.TPActionTriggers) error {
+	return ms.setTPItems(utils.TBLTPActionTriggers, tps, utils.ID)
+}
+
+func (ms *MapStorage) SetTPAccountActions(tps []*utils.TPAccountActions) error {
+	return ms.setTPItems(utils.TBLTPAccountActions, tps, "LoadId", utils.Tenant, utils.Account)
+}
+
+func (ms *MapStorage) SetTPResources(tps []*utils.TPResource) error {
+	return ms.setTPItems(utils.TBLTPResources, tps, utils.Tenant, utils.ID)
+}
+
+func (ms *MapStorage) SetTPStats(tps []*utils.TPStats) error {
+	return ms.setTPItems(utils.TBLTPStats, tps, utils.Tenant, utils.ID)
+}
+
+func (ms *MapStorage) SetTPThresholds(tps []*utils.TPThreshold) error {
+	return ms.setTPItems(utils.TBLTPThresholds, tps, utils.Tenant, utils.ID)
+}
+
+func (ms *MapStorage) SetTPFilters(tps []*utils.TPFilterProfile) error {
+	return ms.setTPItems(utils.TBLTPFilters, tps, utils.Tenant, utils.ID)
+}
+
+func (ms *MapStorage) SetTPSuppliers(tps []*utils.TPSupplierProfile) error {
+	return ms.setTPItems(utils.TBLTPSuppliers, tps, utils.Tenant, utils.ID)
+}
+
+func (ms *MapStorage) SetTPAttributes(tps []*utils.TPAttributeProfile) error {
+	return ms.setTPItems(utils.TBLTPAttributes, tps, utils.Tenant, utils.ID)
+}
+
+func (ms *MapStorage) SetTPExchangeRates(tps []*utils.TPExchangeRate) error {
+	return ms.setTPItems(utils.TBLTPExchangeRates, tps, "FromCurrency", "ToCurrency")
+}
+
+func (ms *MapStorage) SetTPTaxProfiles(tps []*utils.TPTaxProfile) error {
+	return ms.setTPItems(utils.TBLTPTaxProfiles, tps, utils.Tenant, utils.ID)
+}
+
+func (ms *MapStorage) SetTPFeeProfiles(tps []*utils.TPFeeProfile) error {
+	return ms.setTPItems(utils.TBLTPFeeProfiles, tps, utils.Tenant, utils.ID)
+}
+
+func (ms *MapStorage) SetSMCost(smCost *SMCost) error {
+	if smCost.CostDetails == nil {
+		return nil
+	}
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	result, err := ms.ms.Marshal(smCost)
+	if err != nil {
+		return err
+	}
+	ms.setKey(utils.LOG_CALL_COST_PREFIX+utils.ConcatenatedKey(smCost.CGRID, smCost.RunID, smCost.CostSource), result)
+	return nil
+}
+
+func (ms *MapStorage) RemoveSMCost(smCost *SMCost) error {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	keyPrfx := utils.LOG_CALL_COST_PREFIX + utils.ConcatenatedKey(smCost.CGRID, smCost.RunID) + utils.CONCATENATED_KEY_SEP
+	for key := range ms.dict {
+		if strings.HasPrefix(key, keyPrfx) {
+			ms.remKey(key)
+		}
+	}
+	return nil
+}
+
+func (ms *MapStorage) GetSMCosts(cgrid, runid, originHost, originIDPrefix string) (smcs []*SMCost, err error) {
+	ms.mu.RLock()
+	defer ms.mu.RUnlock()
+	keyPrfx := utils.LOG_CALL_COST_PREFIX
+	if cgrid != "" {
+		keyPrfx += cgrid + utils.CONCATENATED_KEY_SEP
+	}
+	for key, values := range ms.dict {
+		if !strings.HasPrefix(key, keyPrfx) {
+			continue
+		}
+		var smc SMCost
+		if err = ms.ms.Unmarshal(values, &smc); err != nil {
+			return nil, err
+		}
+		if (runid != "" && smc.RunID != runid) ||
+			(originHost != "" && smc.OriginHost != originHost) ||
+			(originIDPrefix != "" && !strings.HasPrefix(smc.OriginID, originIDPrefix)) {
+			continue
+		}
+		smcs = append(smcs, &smc)
+	}
+	if len(smcs) == 0 {
+		return smcs, utils.ErrNotFound
+	}
+	return smcs, nil
+}
+
+func (ms *MapStorage) SetCDR(cdr *CDR, allowUpdate bool) error {
+	if cdr.OrderID == 0 {
+		cdr.OrderID = ms.cnter.Next()
+	}
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	key := utils.CDRsTBL + utils.CONCATENATED_KEY_SEP + utils.ConcatenatedKey(cdr.CGRID, cdr.RunID)
+	if _, has := ms.dict[key]; has && !allowUpdate {
+		return utils.ErrExists
+	}
+	result, err := ms.ms.Marshal(cdr)
+	if err != nil {
+		return err
+	}
+	ms.setKey(key, result)
+	return nil
+}
+
+// GetCDRs filters the CDRs stored in memory
+// CreatedAt and UpdatedAt filters are ignored since the CDRs are stored without them
+func (ms *MapStorage) GetCDRs(qryFltr *utils.CDRsFilter, remove bool) ([]*CDR, int64, error) {
+	var minUsage, maxUsage *time.Duration
+	if len(qryFltr.MinUsage) != 0 {
+		if parsed, err := utils.ParseDurationWithNanosecs(qryFltr.MinUsage); err != nil {
+			return nil, 0, err
+		} else {
+			minUsage = &parsed
+		}
+	}
+	if len(qryFltr.MaxUsage) != 0 {
+		if parsed, err := utils.ParseDurationWithNanosecs(qryFltr.MaxUsage); err != nil {
+			return nil, 0, err
+		} else {
+			maxUsage = &parsed
+		}
+	}
+	if remove {
+		ms.mu.Lock()
+		defer ms.mu.Unlock()
+	} else {
+		ms.mu.RLock()
+		defer ms.mu.RUnlock()
+	}
+	keyPrfx := utils.CDRsTBL + utils.CONCATENATED_KEY_SEP
+	var cdrs []*CDR
+	cdrKeys := make(map[*CDR]string)
+	for key, values := range ms.dict {
+		if !strings.HasPrefix(key, keyPrfx) {
+			continue
+		}
+		cdr := new(CDR)
+		if err := ms.ms.Unmarshal(values, cdr); err != nil {
+			return nil, 0, err
+		}
+		if !cdrPassesFilter(cdr, qryFltr, minUsage, maxUsage) {
+			continue
+		}
+		cdrs = append(cdrs, cdr)
+		cdrKeys[cdr] = key
+	}
+	sort.Slice(cdrs, func(i, j int) bool { return cdrs[i].OrderID < cdrs[j].OrderID })
+	cdrs = mapPaginate(reflect.ValueOf(cdrs), qryFltr.Paginator.Limit, qryFltr.Paginator.Offset).Interface().([]*CDR)
+	if remove {
+		for _, cdr := range cdrs {
+			ms.remKey(cdrKeys[cdr])
+		}
+		return nil, int64(len(cdrs)), nil
+	}
+	if qryFltr.Count {
+		return nil, int64(len(cdrs)), nil
+	}
+	if len(cdrs) == 0 {
+		return cdrs, 0, utils.ErrNotFound
+	}
+	return cdrs, 0, nil
+}
+
+// cdrPassesFilter matches the CDR against the query filter
+func cdrPassesFilter(cdr *CDR, qryFltr *utils.CDRsFilter, minUsage, maxUsage *time.Duration) bool {
+	for _, inNotIn := range []struct {
+		val       string
+		in, notIn []string
+	}{
+		{cdr.CGRID, qryFltr.CGRIDs, qryFltr.NotCGRIDs},
+		{cdr.RunID, qryFltr.RunIDs, qryFltr.NotRunIDs},
+		{cdr.ToR, qryFltr.ToRs, qryFltr.NotToRs},
+		{cdr.OriginHost, qryFltr.OriginHosts, qryFltr.NotOriginHosts},
+		{cdr.Source, qryFltr.Sources, qryFltr.NotSources},
+		{cdr.RequestType, qryFltr.RequestTypes, qryFltr.NotRequestTypes},
+		{cdr.Tenant, qryFltr.Tenants, qryFltr.NotTenants},
+		{cdr.Category, qryFltr.Categories, qryFltr.NotCategories},
+		{cdr.Account, qryFltr.Accounts, qryFltr.NotAccounts},
+		{cdr.Subject, qryFltr.Subjects, qryFltr.NotSubjects},
+	} {
+		if len(inNotIn.in) != 0 && !utils.IsSliceMember(inNotIn.in, inNotIn.val) {
+			return false
+		}
+		if utils.IsSliceMember(inNotIn.notIn, inNotIn.val) {
+			return false
+		}
+	}
+	if len(qryFltr.DestinationPrefixes) != 0 {
+		var hasPrefix bool
+		for _, prfx := range qryFltr.DestinationPrefixes {
+			if strings.HasPrefix(cdr.Destination, prfx) {
+				hasPrefix = true
+				break
+			}
+		}
+		if !hasPrefix {
+			return false
+		}
+	}
+	for _, prfx := range qryFltr.NotDestinationPrefixes {
+		if prfx != "" && strings.HasPrefix(cdr.Destination, prfx) {
+			return false
+		}
+	}
+	for fldName, fldVal := range qryFltr.ExtraFields {
+		if cdrVal, has := cdr.ExtraFields[fldName]; !has ||
+			(fldVal != utils.MetaExists && cdrVal != fldVal) {
+			return false
+		}
+	}
+	for fldName, fldVal := range qryFltr.NotExtraFields {
+		if cdrVal, has := cdr.ExtraFields[fldName]; has &&
+			(fldVal == utils.MetaExists || cdrVal == fldVal) {
+			return false
+		}
+	}
+	if (qryFltr.OrderIDStart != nil && cdr.OrderID < *qryFltr.OrderIDStart) ||
+		(qryFltr.OrderIDEnd != nil && cdr.OrderID >= *qryFltr.OrderIDEnd) ||
+		(qryFltr.SetupTimeStart != nil && cdr.SetupTime.Before(*qryFltr.SetupTimeStart)) ||
+		(qryFltr.SetupTimeEnd != nil && !cdr.SetupTime.Before(*qryFltr.SetupTimeEnd)) ||
+		(qryFltr.AnswerTimeStart != nil && cdr.AnswerTime.Before(*qryFltr.AnswerTimeStart)) ||
+		(qryFltr.AnswerTimeEnd != nil && !cdr.AnswerTime.Before(*qryFltr.AnswerTimeEnd)) ||
+		(minUsage != nil && cdr.Usage < *minUsage) ||
+		(maxUsage != nil && cdr.Usage >= *maxUsage) {
+		return false
+	}
+	if len(qryFltr.Costs) != 0 && !float64SliceHasMember(qryFltr.Costs, cdr.Cost) {
+		return false
+	}
+	if float64SliceHasMember(qryFltr.NotCosts, cdr.Cost) {
+		return false
+	}
+	if qryFltr.MinCost != nil {
+		if qryFltr.MaxCost != nil && *qryFltr.MinCost == 0.0 && *qryFltr.MaxCost == -1.0 { // Special case when we want to skip errors
+			return cdr.Cost >= 0.0
+		}
+		if cdr.Cost < *qryFltr.MinCost ||
+			(qryFltr.MaxCost != nil && cdr.Cost >= *qryFltr.MaxCost) {
+			return false
+		}
+	} else if qryFltr.MaxCost != nil {
+		if *qryFltr.MaxCost == -1.0 { // Non-rated CDRs
+			return cdr.Cost == 0.0
+		}
+		return cdr.Cost < *qryFltr.MaxCost
+	}
+	return true
+}
+
+// float64SliceHasMember checks if the value is part of the slice
+func float64SliceHasMember(flts []float64, flt float64) bool {
+	for _, f := range flts {
+		if f == flt {
+			return true
+		}
+	}
+	return false
+}
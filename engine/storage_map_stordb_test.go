@@ -0,0 +1,213 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package engine
+
+import (
+	"path"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/cgrates/cgrates/utils"
+)
+
+func TestMapStorageTPDestinations(t *testing.T) {
+	ms, _ := NewMapStorage()
+	tpDsts := []*utils.TPDestination{
+		{TPid: "TP1", ID: "DST_1002", Prefixes: []string{"1002"}},
+		{TPid: "TP1", ID: "DST_1003", Prefixes: []string{"1003"}},
+		{TPid: "TP2", ID: "DST_1002", Prefixes: []string{"1002", "10021"}},
+	}
+	if err := ms.SetTPDestinations(tpDsts); err != nil {
+		t.Fatal(err)
+	}
+	if rcv, err := ms.GetTPDestinations("TP1", "DST_1002"); err != nil {
+		t.Error(err)
+	} else if !reflect.DeepEqual(tpDsts[:1], rcv) {
+		t.Errorf("Expecting: %s, received: %s", utils.ToJSON(tpDsts[:1]), utils.ToJSON(rcv))
+	}
+	if rcv, err := ms.GetTPDestinations("TP1", ""); err != nil {
+		t.Error(err)
+	} else if !reflect.DeepEqual(tpDsts[:2], rcv) {
+		t.Errorf("Expecting: %s, received: %s", utils.ToJSON(tpDsts[:2]), utils.ToJSON(rcv))
+	}
+	if _, err := ms.GetTPDestinations("TP3", ""); err != utils.ErrNotFound {
+		t.Error(err)
+	}
+	if rcv, err := ms.GetTpIds(""); err != nil {
+		t.Error(err)
+	} else if !reflect.DeepEqual([]string{"TP1", "TP2"}, rcv) {
+		t.Errorf("Received: %+v", rcv)
+	}
+	if rcv, err := ms.GetTpTableIds("TP1", utils.TBLTPDestinations,
+		utils.TPDistinctIds{"tag"}, nil, &utils.Paginator{SearchTerm: "1003"}); err != nil {
+		t.Error(err)
+	} else if !reflect.DeepEqual([]string{"DST_1003"}, rcv) {
+		t.Errorf("Received: %+v", rcv)
+	}
+	if err := ms.RemTpData(utils.TBLTPDestinations, "TP1", map[string]string{"tag": "DST_1002"}); err != nil {
+		t.Error(err)
+	}
+	if rcv, err := ms.GetTPDestinations("TP1", ""); err != nil {
+		t.Error(err)
+	} else if !reflect.DeepEqual(tpDsts[1:2], rcv) {
+		t.Errorf("Expecting: %s, received: %s", utils.ToJSON(tpDsts[1:2]), utils.ToJSON(rcv))
+	}
+	if err := ms.RemTpData("", "TP1", nil); err != nil {
+		t.Error(err)
+	}
+	if rcv, err := ms.GetTpIds(""); err != nil {
+		t.Error(err)
+	} else if !reflect.DeepEqual([]string{"TP2"}, rcv) {
+		t.Errorf("Received: %+v", rcv)
+	}
+}
+
+func TestMapStorageTPUsers(t *testing.T) {
+	ms, _ := NewMapStorage()
+	tpUsrs := []*utils.TPUsers{
+		{TPid: "TP1", Tenant: "cgrates.org", UserName: "1001", Weight: 10},
+		{TPid: "TP1", Tenant: "cgrates.org", UserName: "1002", Weight: 20},
+	}
+	if err := ms.SetTPUsers(tpUsrs); err != nil {
+		t.Fatal(err)
+	}
+	if rcv, err := ms.GetTPUsers(&utils.TPUsers{TPid: "TP1", UserName: "1002"}); err != nil {
+		t.Error(err)
+	} else if !reflect.DeepEqual(tpUsrs[1:], rcv) {
+		t.Errorf("Expecting: %s, received: %s", utils.ToJSON(tpUsrs[1:]), utils.ToJSON(rcv))
+	}
+	if rcv, err := ms.GetTpTableIds("TP1", utils.TBLTPUsers,
+		utils.TPDistinctIds{"tenant", "user_name"}, map[string]string{"user_name": "1001"}, nil); err != nil {
+		t.Error(err)
+	} else if !reflect.DeepEqual([]string{"cgrates.org:1001"}, rcv) {
+		t.Errorf("Received: %+v", rcv)
+	}
+}
+
+func TestMapStorageSMCosts(t *testing.T) {
+	ms, _ := NewMapStorage()
+	smc := &SMCost{CGRID: "CGRID1", RunID: utils.META_DEFAULT, OriginHost: "127.0.0.1",
+		OriginID: "ORIGIN1", CostSource: utils.SESSION_MANAGER_SOURCE, Usage: time.Minute,
+		CostDetails: &CallCost{Destination: "1002", Cost: 0.6}}
+	if err := ms.SetSMCost(smc); err != nil {
+		t.Fatal(err)
+	}
+	if err := ms.SetSMCost(&SMCost{CGRID: "CGRID2"}); err != nil { // no CostDetails, not stored
+		t.Fatal(err)
+	}
+	if rcv, err := ms.GetSMCosts("", "", "", "ORIG"); err != nil {
+		t.Error(err)
+	} else if len(rcv) != 1 || rcv[0].CGRID != smc.CGRID || rcv[0].CostDetails.Cost != smc.CostDetails.Cost {
+		t.Errorf("Received: %s", utils.ToJSON(rcv))
+	}
+	if _, err := ms.GetSMCosts("CGRID2", "", "", ""); err != utils.ErrNotFound {
+		t.Error(err)
+	}
+	if err := ms.RemoveSMCost(smc); err != nil {
+		t.Error(err)
+	}
+	if _, err := ms.GetSMCosts("CGRID1", "", "", ""); err != utils.ErrNotFound {
+		t.Error(err)
+	}
+}
+
+func TestMapStorageCDRs(t *testing.T) {
+	ms, _ := NewMapStorage()
+	tm := time.Date(2017, 12, 1, 14, 0, 0, 0, time.UTC)
+	cdrs := []*CDR{
+		{CGRID: "CGRID1", RunID: utils.META_DEFAULT, Tenant: "cgrates.org", Account: "1001",
+			Destination: "1002", AnswerTime: tm, Usage: time.Minute, Cost: 0.6,
+			ExtraFields: map[string]string{"Disconnect": "NORMAL"}},
+		{CGRID: "CGRID2", RunID: utils.META_DEFAULT, Tenant: "cgrates.org", Account: "1002",
+			Destination: "1003", AnswerTime: tm.Add(time.Hour), Usage: 2 * time.Minute, Cost: -1},
+		{CGRID: "CGRID2", RunID: "run2", Tenant: "itsyscom.com", Account: "1002",
+			Destination: "0049", AnswerTime: tm.Add(time.Hour), Usage: 2 * time.Minute, Cost: 1.2},
+	}
+	for _, cdr := range cdrs {
+		if err := ms.SetCDR(cdr, false); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := ms.SetCDR(cdrs[0], false); err != utils.ErrExists {
+		t.Error(err)
+	}
+	if rcv, _, err := ms.GetCDRs(&utils.CDRsFilter{}, false); err != nil {
+		t.Error(err)
+	} else if len(rcv) != 3 || rcv[0].CGRID != "CGRID1" || rcv[2].RunID != "run2" {
+		t.Errorf("Received: %s", utils.ToJSON(rcv))
+	}
+	for i, tc := range []struct {
+		fltr *utils.CDRsFilter
+		cnt  int64
+	}{
+		{&utils.CDRsFilter{Tenants: []string{"cgrates.org"}}, 2},
+		{&utils.CDRsFilter{NotRunIDs: []string{utils.META_DEFAULT}}, 1},
+		{&utils.CDRsFilter{DestinationPrefixes: []string{"100"}}, 2},
+		{&utils.CDRsFilter{ExtraFields: map[string]string{"Disconnect": utils.MetaExists}}, 1},
+		{&utils.CDRsFilter{AnswerTimeStart: utils.TimePointer(tm.Add(time.Minute))}, 2},
+		{&utils.CDRsFilter{MinUsage: "90s"}, 2},
+		{&utils.CDRsFilter{MinCost: utils.Float64Pointer(0.0), MaxCost: utils.Float64Pointer(-1.0)}, 2},
+		{&utils.CDRsFilter{Paginator: utils.Paginator{Limit: utils.IntPointer(1), Offset: utils.IntPointer(2)}}, 1},
+	} {
+		tc.fltr.Count = true
+		if _, cnt, err := ms.GetCDRs(tc.fltr, false); err != nil {
+			t.Errorf("case %d: %v", i, err)
+		} else if cnt != tc.cnt {
+			t.Errorf("case %d: expecting %d, received: %d", i, tc.cnt, cnt)
+		}
+	}
+	if _, cnt, err := ms.GetCDRs(&utils.CDRsFilter{CGRIDs: []string{"CGRID2"}}, true); err != nil {
+		t.Error(err)
+	} else if cnt != 2 {
+		t.Errorf("Removed: %d", cnt)
+	}
+	if _, _, err := ms.GetCDRs(&utils.CDRsFilter{CGRIDs: []string{"CGRID2"}}, false); err != utils.ErrNotFound {
+		t.Error(err)
+	}
+}
+
+func TestMapStorageSnapshot(t *testing.T) {
+	snapshotPath := path.Join(t.TempDir(), "internal", "stordb.snapshot")
+	ms, err := NewMapStorageWithSnapshot(snapshotPath, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tpDsts := []*utils.TPDestination{{TPid: "TP1", ID: "DST_1002", Prefixes: []string{"1002"}}}
+	if err := ms.SetTPDestinations(tpDsts); err != nil {
+		t.Fatal(err)
+	}
+	if err := ms.SetCDR(&CDR{CGRID: "CGRID1", RunID: utils.META_DEFAULT}, false); err != nil {
+		t.Fatal(err)
+	}
+	ms.Close()
+	if ms, err = NewMapStorageWithSnapshot(snapshotPath, 0); err != nil {
+		t.Fatal(err)
+	}
+	if rcv, err := ms.GetTPDestinations("TP1", ""); err != nil {
+		t.Error(err)
+	} else if !reflect.DeepEqual(tpDsts, rcv) {
+		t.Errorf("Expecting: %s, received: %s", utils.ToJSON(tpDsts), utils.ToJSON(rcv))
+	}
+	if rcv, _, err := ms.GetCDRs(&utils.CDRsFilter{CGRIDs: []string{"CGRID1"}}, false); err != nil {
+		t.Error(err)
+	} else if len(rcv) != 1 {
+		t.Errorf("Received: %s", utils.ToJSON(rcv))
+	}
+}
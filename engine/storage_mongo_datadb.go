@@ -68,6 +68,9 @@ const (
 	colFlt   = "filters"
 	colSpp   = "supplier_profiles"
 	colAttr  = "attribute_profiles"
+	colXrt   = "exchange_rates"
+	colTxp   = "tax_profiles"
+	colFep   = "fee_profiles"
 )
 
 var (
@@ -349,6 +352,9 @@ func (ms *MongoStorage) getColNameForPrefix(prefix string) (name string, ok bool
 		utils.FilterPrefix:           colFlt,
 		utils.SupplierProfilePrefix:  colSpp,
 		utils.AttributeProfilePrefix: colAttr,
+		utils.ExchangeRatePrefix:     colXrt,
+		utils.TaxProfilePrefix:       colTxp,
+		utils.FeeProfilePrefix:       colFep,
 	}
 	name, ok = colMap[prefix]
 	return
@@ -658,6 +664,22 @@ func (ms *MongoStorage) GetKeysForPrefix(prefix string) (result []string, err er
 		for iter.Next(&idResult) {
 			result = append(result, utils.AttributeProfilePrefix+utils.ConcatenatedKey(idResult.Tenant, idResult.Id))
 		}
+	case utils.ExchangeRatePrefix:
+		xrtResult := struct{ FromCurrency, ToCurrency string }{}
+		iter := db.C(colXrt).Find(bson.M{"fromcurrency": bson.M{"$regex": bson.RegEx{Pattern: subject}}}).Select(bson.M{"fromcurrency": 1, "tocurrency": 1}).Iter()
+		for iter.Next(&xrtResult) {
+			result = append(result, utils.ExchangeRatePrefix+utils.ConcatenatedKey(xrtResult.FromCurrency, xrtResult.ToCurrency))
+		}
+	case utils.TaxProfilePrefix:
+		iter := db.C(colTxp).Find(bson.M{"id": bson.M{"$regex": bson.RegEx{Pattern: subject}}}).Select(bson.M{"tenant": 1, "id": 1}).Iter()
+		for iter.Next(&idResult) {
+			result = append(result, utils.TaxProfilePrefix+utils.ConcatenatedKey(idResult.Tenant, idResult.Id))
+		}
+	case utils.FeeProfilePrefix:
+		iter := db.C(colFep).Find(bson.M{"id": bson.M{"$regex": bson.RegEx{Pattern: subject}}}).Select(bson.M{"tenant": 1, "id": 1}).Iter()
+		for iter.Next(&idResult) {
+			result = append(result, utils.FeeProfilePrefix+utils.ConcatenatedKey(idResult.Tenant, idResult.Id))
+		}
 	default:
 		err = fmt.Errorf("unsupported prefix in GetKeysForPrefix: %s", prefix)
 	}
@@ -706,6 +728,19 @@ func (ms *MongoStorage) HasDataDrv(category, subject string) (has bool, err erro
 	case utils.AttributeProfilePrefix:
 		count, err = db.C(colAttr).Find(bson.M{"id": subject}).Count()
 		has = count > 0
+	case utils.ExchangeRatePrefix:
+		xrtID := strings.SplitN(subject, utils.CONCATENATED_KEY_SEP, 2)
+		if len(xrtID) != 2 {
+			return false, utils.ErrInvalidKey
+		}
+		count, err = db.C(colXrt).Find(bson.M{"fromcurrency": xrtID[0], "tocurrency": xrtID[1]}).Count()
+		has = count > 0
+	case utils.TaxProfilePrefix:
+		count, err = db.C(colTxp).Find(bson.M{"id": subject}).Count()
+		has = count > 0
+	case utils.FeeProfilePrefix:
+		count, err = db.C(colFep).Find(bson.M{"id": subject}).Count()
+		has = count > 0
 	default:
 		err = fmt.Errorf("unsupported category in HasData: %s", category)
 	}
@@ -1152,6 +1187,12 @@ func (ms *MongoStorage) SetAccount(acc *Account) error {
 			ac.UnitCounters = acc.UnitCounters
 			ac.AllowNegative = acc.AllowNegative
 			ac.Disabled = acc.Disabled
+			ac.ParentID = acc.ParentID
+			ac.CreditLimit = acc.CreditLimit
+			ac.CreditUsed = acc.CreditUsed
+			if len(acc.Subscriptions) != 0 {
+				ac.Subscriptions = acc.Subscriptions
+			}
 			acc = ac
 		}
 	}
@@ -2284,3 +2325,87 @@ func (ms *MongoStorage) RemoveAttributeProfileDrv(tenant, id string) (err error)
 	}
 	return nil
 }
+
+func (ms *MongoStorage) GetExchangeRateDrv(fromCurrency, toCurrency string) (r *ExchangeRate, err error) {
+	session, col := ms.conn(colXrt)
+	defer session.Close()
+	if err = col.Find(bson.M{"fromcurrency": fromCurrency, "tocurrency": toCurrency}).One(&r); err != nil {
+		if err == mgo.ErrNotFound {
+			err = utils.ErrNotFound
+		}
+		return nil, err
+	}
+	return
+}
+
+func (ms *MongoStorage) SetExchangeRateDrv(r *ExchangeRate) (err error) {
+	session, col := ms.conn(colXrt)
+	defer session.Close()
+	_, err = col.Upsert(bson.M{"fromcurrency": r.FromCurrency, "tocurrency": r.ToCurrency}, r)
+	return
+}
+
+func (ms *MongoStorage) RemoveExchangeRateDrv(fromCurrency, toCurrency string) (err error) {
+	session, col := ms.conn(colXrt)
+	defer session.Close()
+	if err = col.Remove(bson.M{"fromcurrency": fromCurrency, "tocurrency": toCurrency}); err != nil {
+		return
+	}
+	return nil
+}
+
+func (ms *MongoStorage) GetTaxProfileDrv(tenant, id string) (r *TaxProfile, err error) {
+	session, col := ms.conn(colTxp)
+	defer session.Close()
+	if err = col.Find(bson.M{"tenant": tenant, "id": id}).One(&r); err != nil {
+		if err == mgo.ErrNotFound {
+			err = utils.ErrNotFound
+		}
+		return nil, err
+	}
+	return
+}
+
+func (ms *MongoStorage) SetTaxProfileDrv(r *TaxProfile) (err error) {
+	session, col := ms.conn(colTxp)
+	defer session.Close()
+	_, err = col.Upsert(bson.M{"tenant": r.Tenant, "id": r.ID}, r)
+	return
+}
+
+func (ms *MongoStorage) RemoveTaxProfileDrv(tenant, id string) (err error) {
+	session, col := ms.conn(colTxp)
+	defer session.Close()
+	if err = col.Remove(bson.M{"tenant": tenant, "id": id}); err != nil {
+		return
+	}
+	return nil
+}
+
+func (ms *MongoStorage) GetFeeProfileDrv(tenant, id string) (r *FeeProfile, err error) {
+	session, col := ms.conn(colFep)
+	defer session.Close()
+	if err = col.Find(bson.M{"tenant": tenant, "id": id}).One(&r); err != nil {
+		if err == mgo.ErrNotFound {
+			err = utils.ErrNotFound
+		}
+		return nil, err
+	}
+	return
+}
+
+func (ms *MongoStorage) SetFeeProfileDrv(r *FeeProfile) (err error) {
+	session, col := ms.conn(colFep)
+	defer session.Close()
+	_, err = col.Upsert(bson.M{"tenant": r.Tenant, "id": r.ID}, r)
+	return
+}
+
+func (ms *MongoStorage) RemoveFeeProfileDrv(tenant, id string) (err error) {
+	session, col := ms.conn(colFep)
+	defer session.Close()
+	if err = col.Remove(bson.M{"tenant": tenant, "id": id}); err != nil {
+		return
+	}
+	return nil
+}
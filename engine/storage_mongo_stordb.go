@@ -1289,6 +1289,102 @@ func (ms *MongoStorage) SetTPAttributes(tpSPs []*utils.TPAttributeProfile) (err
 	return
 }
 
+func (ms *MongoStorage) GetTPExchangeRates(tpid, fromCurrency, toCurrency string) ([]*utils.TPExchangeRate, error) {
+	filter := bson.M{
+		"tpid": tpid,
+	}
+	if fromCurrency != "" {
+		filter["fromcurrency"] = fromCurrency
+	}
+	if toCurrency != "" {
+		filter["tocurrency"] = toCurrency
+	}
+	var results []*utils.TPExchangeRate
+	session, col := ms.conn(utils.TBLTPExchangeRates)
+	defer session.Close()
+	err := col.Find(filter).All(&results)
+	if len(results) == 0 {
+		return results, utils.ErrNotFound
+	}
+	return results, err
+}
+
+func (ms *MongoStorage) SetTPExchangeRates(tpXRs []*utils.TPExchangeRate) (err error) {
+	if len(tpXRs) == 0 {
+		return
+	}
+	session, col := ms.conn(utils.TBLTPExchangeRates)
+	defer session.Close()
+	tx := col.Bulk()
+	for _, tp := range tpXRs {
+		tx.Upsert(bson.M{"tpid": tp.TPid, "fromcurrency": tp.FromCurrency, "tocurrency": tp.ToCurrency}, tp)
+	}
+	_, err = tx.Run()
+	return
+}
+
+func (ms *MongoStorage) GetTPTaxProfiles(tpid, id string) ([]*utils.TPTaxProfile, error) {
+	filter := bson.M{
+		"tpid": tpid,
+	}
+	if id != "" {
+		filter["id"] = id
+	}
+	var results []*utils.TPTaxProfile
+	session, col := ms.conn(utils.TBLTPTaxProfiles)
+	defer session.Close()
+	err := col.Find(filter).All(&results)
+	if len(results) == 0 {
+		return results, utils.ErrNotFound
+	}
+	return results, err
+}
+
+func (ms *MongoStorage) SetTPTaxProfiles(tpTXPs []*utils.TPTaxProfile) (err error) {
+	if len(tpTXPs) == 0 {
+		return
+	}
+	session, col := ms.conn(utils.TBLTPTaxProfiles)
+	defer session.Close()
+	tx := col.Bulk()
+	for _, tp := range tpTXPs {
+		tx.Upsert(bson.M{"tpid": tp.TPid, "tenant": tp.Tenant, "id": tp.ID}, tp)
+	}
+	_, err = tx.Run()
+	return
+}
+
+func (ms *MongoStorage) GetTPFeeProfiles(tpid, id string) ([]*utils.TPFeeProfile, error) {
+	filter := bson.M{
+		"tpid": tpid,
+	}
+	if id != "" {
+		filter["id"] = id
+	}
+	var results []*utils.TPFeeProfile
+	session, col := ms.conn(utils.TBLTPFeeProfiles)
+	defer session.Close()
+	err := col.Find(filter).All(&results)
+	if len(results) == 0 {
+		return results, utils.ErrNotFound
+	}
+	return results, err
+}
+
+func (ms *MongoStorage) SetTPFeeProfiles(tpFEPs []*utils.TPFeeProfile) (err error) {
+	if len(tpFEPs) == 0 {
+		return
+	}
+	session, col := ms.conn(utils.TBLTPFeeProfiles)
+	defer session.Close()
+	tx := col.Bulk()
+	for _, tp := range tpFEPs {
+		tx.Upsert(bson.M{"tpid": tp.TPid, "tenant": tp.Tenant, "id": tp.ID}, tp)
+	}
+	_, err = tx.Run()
+	return
+}
+
 func (ms *MongoStorage) GetVersions(itm string) (vrs Versions, err error) {
 	session, col := ms.conn(colVer)
 	defer session.Close()
@@ -269,7 +269,8 @@ func (rs *RedisStorage) HasDataDrv(category, subject string) (bool, error) {
 	case utils.DESTINATION_PREFIX, utils.RATING_PLAN_PREFIX, utils.RATING_PROFILE_PREFIX,
 		utils.ACTION_PREFIX, utils.ACTION_PLAN_PREFIX, utils.ACCOUNT_PREFIX, utils.DERIVEDCHARGERS_PREFIX,
 		utils.ResourcesPrefix, utils.StatQueuePrefix, utils.ThresholdPrefix,
-		utils.FilterPrefix, utils.SupplierProfilePrefix, utils.AttributeProfilePrefix:
+		utils.FilterPrefix, utils.SupplierProfilePrefix, utils.AttributeProfilePrefix,
+		utils.ExchangeRatePrefix, utils.TaxProfilePrefix, utils.FeeProfilePrefix:
 		i, err := rs.Cmd("EXISTS", category+subject).Int()
 		return i == 1, err
 	}
@@ -668,6 +669,12 @@ func (rs *RedisStorage) SetAccount(ub *Account) (err error) {
 			ac.UnitCounters = ub.UnitCounters
 			ac.AllowNegative = ub.AllowNegative
 			ac.Disabled = ub.Disabled
+			ac.ParentID = ub.ParentID
+			ac.CreditLimit = ub.CreditLimit
+			ac.CreditUsed = ub.CreditUsed
+			if len(ub.Subscriptions) != 0 {
+				ac.Subscriptions = ub.Subscriptions
+			}
 			ub = ac
 		}
 	}
@@ -1788,6 +1795,99 @@ func (rs *RedisStorage) RemoveAttributeProfileDrv(tenant, id string) (err error)
 	return
 }
 
+func (rs *RedisStorage) GetExchangeRateDrv(fromCurrency, toCurrency string) (r *ExchangeRate, err error) {
+	key := utils.ExchangeRatePrefix + utils.ConcatenatedKey(fromCurrency, toCurrency)
+	var values []byte
+	if values, err = rs.Cmd("GET", key).Bytes(); err != nil {
+		if err == redis.ErrRespNil {
+			err = utils.ErrNotFound
+		}
+		return
+	}
+	if err = rs.ms.Unmarshal(values, &r); err != nil {
+		return
+	}
+	return
+}
+
+func (rs *RedisStorage) SetExchangeRateDrv(r *ExchangeRate) (err error) {
+	result, err := rs.ms.Marshal(r)
+	if err != nil {
+		return err
+	}
+	return rs.Cmd("SET", utils.ExchangeRatePrefix+r.ID(), result).Err
+}
+
+func (rs *RedisStorage) RemoveExchangeRateDrv(fromCurrency, toCurrency string) (err error) {
+	key := utils.ExchangeRatePrefix + utils.ConcatenatedKey(fromCurrency, toCurrency)
+	if err = rs.Cmd("DEL", key).Err; err != nil {
+		return
+	}
+	return
+}
+
+func (rs *RedisStorage) GetTaxProfileDrv(tenant, id string) (r *TaxProfile, err error) {
+	key := utils.TaxProfilePrefix + utils.ConcatenatedKey(tenant, id)
+	var values []byte
+	if values, err = rs.Cmd("GET", key).Bytes(); err != nil {
+		if err == redis.ErrRespNil {
+			err = utils.ErrNotFound
+		}
+		return
+	}
+	if err = rs.ms.Unmarshal(values, &r); err != nil {
+		return
+	}
+	return
+}
+
+func (rs *RedisStorage) SetTaxProfileDrv(r *TaxProfile) (err error) {
+	result, err := rs.ms.Marshal(r)
+	if err != nil {
+		return err
+	}
+	return rs.Cmd("SET", utils.TaxProfilePrefix+r.TenantID(), result).Err
+}
+
+func (rs *RedisStorage) RemoveTaxProfileDrv(tenant, id string) (err error) {
+	key := utils.TaxProfilePrefix + utils.ConcatenatedKey(tenant, id)
+	if err = rs.Cmd("DEL", key).Err; err != nil {
+		return
+	}
+	return
+}
+
+func (rs *RedisStorage) GetFeeProfileDrv(tenant, id string) (r *FeeProfile, err error) {
+	key := utils.FeeProfilePrefix + utils.ConcatenatedKey(tenant, id)
+	var values []byte
+	if values, err = rs.Cmd("GET", key).Bytes(); err != nil {
+		if err == redis.ErrRespNil {
+			err = utils.ErrNotFound
+		}
+		return
+	}
+	if err = rs.ms.Unmarshal(values, &r); err != nil {
+		return
+	}
+	return
+}
+
+func (rs *RedisStorage) SetFeeProfileDrv(r *FeeProfile) (err error) {
+	result, err := rs.ms.Marshal(r)
+	if err != nil {
+		return err
+	}
+	return rs.Cmd("SET", utils.FeeProfilePrefix+r.TenantID(), result).Err
+}
+
+func (rs *RedisStorage) RemoveFeeProfileDrv(tenant, id string) (err error) {
+	key := utils.FeeProfilePrefix + utils.ConcatenatedKey(tenant, id)
+	if err = rs.Cmd("DEL", key).Err; err != nil {
+		return
+	}
+	return
+}
+
 func (rs *RedisStorage) GetStorageType() string {
 	return utils.REDIS
 }
@@ -109,6 +109,7 @@ func (self *SQLStorage) IsDBEmpty() (resp bool, err error) {
 		utils.TBLTPAliases, utils.TBLTPResources, utils.TBLTPStats, utils.TBLTPThresholds,
 		utils.TBLTPFilters, utils.SMCostsTBL, utils.CDRsTBL, utils.TBLTPActionPlans,
 		utils.TBLVersions, utils.TBLTPSuppliers, utils.TBLTPAttributes,
+		utils.TBLTPExchangeRates, utils.TBLTPTaxProfiles, utils.TBLTPFeeProfiles,
 	}
 	for _, tbl := range tbls {
 		if self.db.HasTable(tbl) {
@@ -124,10 +125,10 @@ func (self *SQLStorage) IsDBEmpty() (resp bool, err error) {
 func (self *SQLStorage) GetTpIds(colName string) ([]string, error) {
 	var rows *sql.Rows
 	var err error
-	qryStr := fmt.Sprintf(" (SELECT tpid FROM %s)", colName)
-	if colName == "" {
+	qryStr := fmt.Sprintf("SELECT tpid FROM %s", colName)
+	if colName == "" { // no parenthesis around SELECTs so the query works also with SQLite
 		qryStr = fmt.Sprintf(
-			"(SELECT tpid FROM %s) UNION (SELECT tpid FROM %s) UNION (SELECT tpid FROM %s) UNION (SELECT tpid FROM %s) UNION (SELECT tpid FROM %s) UNION (SELECT tpid FROM %s) UNION (SELECT tpid FROM %s) UNION (SELECT tpid FROM %s) UNION (SELECT tpid FROM %s) UNION (SELECT tpid FROM %s) UNION (SELECT tpid FROM %s) UNION (SELECT tpid FROM %s) UNION (SELECT tpid FROM %s) UNION (SELECT tpid FROM %s) UNION (SELECT tpid FROM %s) UNION (SELECT tpid FROM %s) UNION (SELECT tpid FROM %s) UNION (SELECT tpid FROM %s) UNION (SELECT tpid FROM %s) UNION (SELECT tpid FROM %s) UNION (SELECT tpid FROM %s) UNION (SELECT tpid FROM %s)",
+			"SELECT tpid FROM %s UNION SELECT tpid FROM %s UNION SELECT tpid FROM %s UNION SELECT tpid FROM %s UNION SELECT tpid FROM %s UNION SELECT tpid FROM %s UNION SELECT tpid FROM %s UNION SELECT tpid FROM %s UNION SELECT tpid FROM %s UNION SELECT tpid FROM %s UNION SELECT tpid FROM %s UNION SELECT tpid FROM %s UNION SELECT tpid FROM %s UNION SELECT tpid FROM %s UNION SELECT tpid FROM %s UNION SELECT tpid FROM %s UNION SELECT tpid FROM %s UNION SELECT tpid FROM %s UNION SELECT tpid FROM %s UNION SELECT tpid FROM %s UNION SELECT tpid FROM %s UNION SELECT tpid FROM %s UNION SELECT tpid FROM %s UNION SELECT tpid FROM %s UNION SELECT tpid FROM %s",
 			utils.TBLTPTimings,
 			utils.TBLTPDestinations,
 			utils.TBLTPRates,
@@ -149,7 +150,10 @@ func (self *SQLStorage) GetTpIds(colName string) ([]string, error) {
 			utils.TBLTPFilters,
 			utils.TBLTPActionPlans,
 			utils.TBLTPSuppliers,
-			utils.TBLTPAttributes)
+			utils.TBLTPAttributes,
+			utils.TBLTPExchangeRates,
+			utils.TBLTPTaxProfiles,
+			utils.TBLTPFeeProfiles)
 	}
 	rows, err = self.Db.Query(qryStr)
 	if err != nil {
@@ -239,7 +243,8 @@ func (self *SQLStorage) RemTpData(table, tpid string, args map[string]string) er
 			utils.TBLTPDestinationRates, utils.TBLTPRatingPlans, utils.TBLTPRateProfiles, utils.TBLTPSharedGroups,
 			utils.TBLTPCdrStats, utils.TBLTPLcrs, utils.TBLTPActions, utils.TBLTPActionPlans, utils.TBLTPActionTriggers,
 			utils.TBLTPAccountActions, utils.TBLTPDerivedChargers, utils.TBLTPAliases, utils.TBLTPUsers,
-			utils.TBLTPResources, utils.TBLTPStats, utils.TBLTPFilters, utils.TBLTPSuppliers, utils.TBLTPAttributes} {
+			utils.TBLTPResources, utils.TBLTPStats, utils.TBLTPFilters, utils.TBLTPSuppliers, utils.TBLTPAttributes,
+			utils.TBLTPExchangeRates, utils.TBLTPTaxProfiles, utils.TBLTPFeeProfiles} {
 			if err := tx.Table(tblName).Where("tpid = ?", tpid).Delete(nil).Error; err != nil {
 				tx.Rollback()
 				return err
@@ -738,6 +743,71 @@ func (self *SQLStorage) SetTPAttributes(tpAttrs []*utils.TPAttributeProfile) err
 	return nil
 }
 
+func (self *SQLStorage) SetTPExchangeRates(tpXRs []*utils.TPExchangeRate) error {
+	if len(tpXRs) == 0 {
+		return nil
+	}
+	tx := self.db.Begin()
+	for _, xr := range tpXRs {
+		// Remove previous
+		if err := tx.Where(&TpExchangeRate{Tpid: xr.TPid, FromCurrency: xr.FromCurrency,
+			ToCurrency: xr.ToCurrency}).Delete(TpExchangeRate{}).Error; err != nil {
+			tx.Rollback()
+			return err
+		}
+		for _, mxr := range APItoModelTPExchangeRate(xr) {
+			if err := tx.Save(&mxr).Error; err != nil {
+				tx.Rollback()
+				return err
+			}
+		}
+	}
+	tx.Commit()
+	return nil
+}
+
+func (self *SQLStorage) SetTPTaxProfiles(tpTXPs []*utils.TPTaxProfile) error {
+	if len(tpTXPs) == 0 {
+		return nil
+	}
+	tx := self.db.Begin()
+	for _, txp := range tpTXPs {
+		// Remove previous
+		if err := tx.Where(&TpTaxProfile{Tpid: txp.TPid, Tenant: txp.Tenant, ID: txp.ID}).Delete(TpTaxProfile{}).Error; err != nil {
+			tx.Rollback()
+			return err
+		}
+		for _, mtxp := range APItoModelTPTaxProfile(txp) {
+			if err := tx.Save(&mtxp).Error; err != nil {
+				tx.Rollback()
+				return err
+			}
+		}
+	}
+	tx.Commit()
+	return nil
+}
+
+func (self *SQLStorage) SetTPFeeProfiles(tpFEPs []*utils.TPFeeProfile) error {
+	if len(tpFEPs) == 0 {
+		return nil
+	}
+	tx := self.db.Begin()
+	for _, fep := range tpFEPs {
+		// Remove previous
+		if err := tx.Where(&TpFeeProfile{Tpid: fep.TPid, Tenant: fep.Tenant, ID: fep.ID}).Delete(TpFeeProfile{}).Error; err != nil {
+			tx.Rollback()
+			return err
+		}
+		if err := tx.Save(APItoModelTPFeeProfile(fep)).Error; err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+	tx.Commit()
+	return nil
+}
+
 func (self *SQLStorage) SetSMCost(smc *SMCost) error {
 	if smc.CostDetails == nil {
 		return nil
@@ -1613,6 +1683,60 @@ func (self *SQLStorage) GetTPAttributes(tpid, id string) ([]*utils.TPAttributePr
 	return arls, nil
 }
 
+func (self *SQLStorage) GetTPExchangeRates(tpid, fromCurrency, toCurrency string) ([]*utils.TPExchangeRate, error) {
+	var xrs TpExchangeRates
+	q := self.db.Where("tpid = ?", tpid)
+	if len(fromCurrency) != 0 {
+		q = q.Where("from_currency = ?", fromCurrency)
+	}
+	if len(toCurrency) != 0 {
+		q = q.Where("to_currency = ?", toCurrency)
+	}
+	if err := q.Find(&xrs).Error; err != nil {
+		return nil, err
+	}
+	txrs := xrs.AsTPExchangeRates()
+	if len(txrs) == 0 {
+		return txrs, utils.ErrNotFound
+	}
+	return txrs, nil
+}
+
+func (self *SQLStorage) GetTPTaxProfiles(tpid, id string) ([]*utils.TPTaxProfile, error) {
+	var txps TpTaxProfiles
+	q := self.db.Where("tpid = ?", tpid)
+	if len(id) != 0 {
+		q = q.Where("id = ?", id)
+	}
+	if err := q.Find(&txps).Error; err != nil {
+		return nil, err
+	}
+	ttxps, err := txps.AsTPTaxProfiles()
+	if err != nil {
+		return nil, err
+	}
+	if len(ttxps) == 0 {
+		return ttxps, utils.ErrNotFound
+	}
+	return ttxps, nil
+}
+
+func (self *SQLStorage) GetTPFeeProfiles(tpid, id string) ([]*utils.TPFeeProfile, error) {
+	var feps TpFeeProfiles
+	q := self.db.Where("tpid = ?", tpid)
+	if len(id) != 0 {
+		q = q.Where("id = ?", id)
+	}
+	if err := q.Find(&feps).Error; err != nil {
+		return nil, err
+	}
+	tfeps := feps.AsTPFeeProfiles()
+	if len(tfeps) == 0 {
+		return tfeps, utils.ErrNotFound
+	}
+	return tfeps, nil
+}
+
 // GetVersions returns slice of all versions or a specific version if tag is specified
 func (self *SQLStorage) GetVersions(itm string) (vrs Versions, err error) {
 	q := self.db.Model(&TBLVersion{})
@@ -0,0 +1,105 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package engine
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"path"
+	"time"
+
+	"github.com/cgrates/cgrates/utils"
+	"github.com/jinzhu/gorm"
+)
+
+// NewSQLiteStorage opens the SQLite database found at dbPath, creating the file if missing
+// tables are created out of data/storage/sqlite scripts, same as for the other SQL databases
+func NewSQLiteStorage(dbPath string, maxConn, maxIdleConn, connMaxLifetime int) (*SQLStorage, error) {
+	if !utils.IsSliceMember(sql.Drivers(), "sqlite3") { // driver needs cgo so it is only built in with the sqlite tag
+		return nil, fmt.Errorf("unsupported stor_db type: <%s>, rebuild with -tags sqlite", utils.SQLITE)
+	}
+	if err := os.MkdirAll(path.Dir(dbPath), 0755); err != nil {
+		return nil, err
+	}
+	// busy_timeout makes concurrent writers wait for each other instead of failing with database locked
+	connectString := fmt.Sprintf("file:%s?_busy_timeout=5000&_journal_mode=WAL&_loc=auto", dbPath)
+	db, err := gorm.Open("sqlite3", connectString)
+	if err != nil {
+		return nil, err
+	}
+	if err = db.DB().Ping(); err != nil {
+		return nil, err
+	}
+	db.DB().SetMaxIdleConns(maxIdleConn)
+	db.DB().SetMaxOpenConns(maxConn)
+	db.DB().SetConnMaxLifetime(time.Duration(connMaxLifetime) * time.Second)
+	//db.LogMode(true)
+	sqliteStorage := new(SQLiteStorage)
+	sqliteStorage.db = db
+	sqliteStorage.Db = db.DB()
+	return &SQLStorage{db.DB(), db, sqliteStorage, sqliteStorage}, nil
+}
+
+type SQLiteStorage struct {
+	SQLStorage
+}
+
+// SetVersions will set a slice of versions, updating existing
+func (self *SQLiteStorage) SetVersions(vrs Versions, overwrite bool) (err error) {
+	tx := self.db.Begin()
+	if overwrite {
+		tx.Table(utils.TBLVersions).Delete(nil)
+	}
+	for key, val := range vrs {
+		vrModel := &TBLVersion{Item: key, Version: val}
+		if !overwrite {
+			if err = tx.Where(TBLVersion{Item: vrModel.Item}).Delete(TBLVersion{}).Error; err != nil {
+				tx.Rollback()
+				return
+			}
+		}
+		if err = tx.Save(vrModel).Error; err != nil {
+			tx.Rollback()
+			return
+		}
+	}
+	tx.Commit()
+	return
+}
+
+func (self *SQLiteStorage) extraFieldsExistsQry(field string) string {
+	return fmt.Sprintf(" extra_fields LIKE '%%\"%s\":%%'", field)
+}
+
+func (self *SQLiteStorage) extraFieldsValueQry(field, value string) string {
+	return fmt.Sprintf(" extra_fields LIKE '%%\"%s\":\"%s\"%%'", field, value)
+}
+
+func (self *SQLiteStorage) notExtraFieldsExistsQry(field string) string {
+	return fmt.Sprintf(" extra_fields NOT LIKE '%%\"%s\":%%'", field)
+}
+
+func (self *SQLiteStorage) notExtraFieldsValueQry(field, value string) string {
+	return fmt.Sprintf(" extra_fields NOT LIKE '%%\"%s\":\"%s\"%%'", field, value)
+}
+
+func (self *SQLiteStorage) GetStorageType() string {
+	return utils.SQLITE
+}
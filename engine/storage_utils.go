@@ -48,9 +48,15 @@ func ConfigureDataStorage(db_type, host, port, name, user, pass, marshaler strin
 	case utils.MONGO:
 		d, err = NewMongoStorage(host, port, name, user, pass, utils.DataDB, nil, cacheCfg, loadHistorySize)
 		dm = NewDataManager(d.(DataDB))
+	case utils.MetaInternal:
+		d, err = NewMapStorageWithSnapshot(config.CgrConfig().DataDbSnapshotPath, config.CgrConfig().DataDbSnapshotInterval)
+		dm = NewDataManager(d.(DataDB))
+	case utils.MetaEmbedded:
+		d, err = NewEmbeddedStorage(name, marshaler)
+		dm = NewDataManager(d.(DataDB))
 	default:
-		err = errors.New(fmt.Sprintf("Unknown db '%s' valid options are '%s' or '%s'",
-			db_type, utils.REDIS, utils.MONGO))
+		err = errors.New(fmt.Sprintf("Unknown db '%s' valid options are [%s, %s, %s, %s]",
+			db_type, utils.REDIS, utils.MONGO, utils.MetaInternal, utils.MetaEmbedded))
 	}
 	if err != nil {
 		return nil, err
@@ -67,9 +73,13 @@ func ConfigureStorStorage(db_type, host, port, name, user, pass, marshaler strin
 		d, err = NewPostgresStorage(host, port, name, user, pass, maxConn, maxIdleConn, connMaxLifetime)
 	case utils.MYSQL:
 		d, err = NewMySQLStorage(host, port, name, user, pass, maxConn, maxIdleConn, connMaxLifetime)
+	case utils.SQLITE:
+		d, err = NewSQLiteStorage(name, maxConn, maxIdleConn, connMaxLifetime)
+	case utils.MetaInternal:
+		d, err = NewMapStorageWithSnapshot(config.CgrConfig().StorDBSnapshotPath, config.CgrConfig().StorDBSnapshotInterval)
 	default:
-		err = errors.New(fmt.Sprintf("Unknown db '%s' valid options are [%s, %s, %s]",
-			db_type, utils.MYSQL, utils.MONGO, utils.POSTGRES))
+		err = errors.New(fmt.Sprintf("Unknown db '%s' valid options are [%s, %s, %s, %s, %s]",
+			db_type, utils.MYSQL, utils.MONGO, utils.POSTGRES, utils.SQLITE, utils.MetaInternal))
 	}
 	if err != nil {
 		return nil, err
@@ -84,11 +94,15 @@ func ConfigureLoadStorage(db_type, host, port, name, user, pass, marshaler strin
 		d, err = NewPostgresStorage(host, port, name, user, pass, maxConn, maxIdleConn, connMaxLifetime)
 	case utils.MYSQL:
 		d, err = NewMySQLStorage(host, port, name, user, pass, maxConn, maxIdleConn, connMaxLifetime)
+	case utils.SQLITE:
+		d, err = NewSQLiteStorage(name, maxConn, maxIdleConn, connMaxLifetime)
 	case utils.MONGO:
 		d, err = NewMongoStorage(host, port, name, user, pass, utils.StorDB, cdrsIndexes, nil, 1)
+	case utils.MetaInternal:
+		d, err = NewMapStorageWithSnapshot(config.CgrConfig().StorDBSnapshotPath, config.CgrConfig().StorDBSnapshotInterval)
 	default:
-		err = errors.New(fmt.Sprintf("Unknown db '%s' valid options are [%s, %s, %s]",
-			db_type, utils.MYSQL, utils.MONGO, utils.POSTGRES))
+		err = errors.New(fmt.Sprintf("Unknown db '%s' valid options are [%s, %s, %s, %s, %s]",
+			db_type, utils.MYSQL, utils.MONGO, utils.POSTGRES, utils.SQLITE, utils.MetaInternal))
 	}
 	if err != nil {
 		return nil, err
@@ -103,11 +117,15 @@ func ConfigureCdrStorage(db_type, host, port, name, user, pass string, maxConn,
 		d, err = NewPostgresStorage(host, port, name, user, pass, maxConn, maxIdleConn, connMaxLifetime)
 	case utils.MYSQL:
 		d, err = NewMySQLStorage(host, port, name, user, pass, maxConn, maxIdleConn, connMaxLifetime)
+	case utils.SQLITE:
+		d, err = NewSQLiteStorage(name, maxConn, maxIdleConn, connMaxLifetime)
 	case utils.MONGO:
 		d, err = NewMongoStorage(host, port, name, user, pass, utils.StorDB, cdrsIndexes, nil, 1)
+	case utils.MetaInternal:
+		d, err = NewMapStorageWithSnapshot(config.CgrConfig().StorDBSnapshotPath, config.CgrConfig().StorDBSnapshotInterval)
 	default:
-		err = errors.New(fmt.Sprintf("Unknown db '%s' valid options are [%s, %s, %s]",
-			db_type, utils.MYSQL, utils.MONGO, utils.POSTGRES))
+		err = errors.New(fmt.Sprintf("Unknown db '%s' valid options are [%s, %s, %s, %s, %s]",
+			db_type, utils.MYSQL, utils.MONGO, utils.POSTGRES, utils.SQLITE, utils.MetaInternal))
 	}
 	if err != nil {
 		return nil, err
@@ -0,0 +1,60 @@
+// +build integration
+// +build sqlite
+
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+package engine
+
+import (
+	"path"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/cgrates/cgrates/config"
+)
+
+func TestStorDBitSQLite(t *testing.T) {
+	if cfg, err = config.NewCGRConfigFromFolder(path.Join(*dataDir, "conf", "samples", "storage", "sqlite")); err != nil {
+		t.Fatal(err)
+	}
+	if storDB, err = NewSQLiteStorage(cfg.StorDBName,
+		cfg.StorDBMaxOpenConns, cfg.StorDBMaxIdleConns, cfg.StorDBConnMaxLifetime); err != nil {
+		t.Fatal(err)
+	}
+	storDB2ndDBname = "sqlite"
+	for _, stest := range sTestsStorDBit {
+		stestFullName := runtime.FuncForPC(reflect.ValueOf(stest).Pointer()).Name()
+		split := strings.Split(stestFullName, ".")
+		stestName := split[len(split)-1]
+		if stestName == "testStorDBitIsDBEmpty" { // covered by testStorDBitSQLiteIsDBEmpty
+			stest = testStorDBitSQLiteIsDBEmpty
+		}
+		t.Run(stestName, stest)
+	}
+}
+
+// testStorDBitSQLiteIsDBEmpty checks the tables created by the setup scripts are seen, same as for the other SQL databases
+func testStorDBitSQLiteIsDBEmpty(t *testing.T) {
+	if test, err := storDB.IsDBEmpty(); err != nil {
+		t.Error(err)
+	} else if test != false {
+		t.Errorf("\nExpecting: false got :%+v", test)
+	}
+}
@@ -0,0 +1,196 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package engine
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/cgrates/cgrates/utils"
+)
+
+// FeeProfile is a recurrent fee charged once per period to the subscribed accounts
+type FeeProfile struct {
+	Tenant   string
+	ID       string
+	Period   string  // <*daily|*weekly|*monthly|*yearly>
+	Amount   float64 // fee for one full period
+	Category string  // category of the generated CDRs
+	ProRate  bool    // charge only the part of the period the subscription is active
+}
+
+func (fp *FeeProfile) TenantID() string {
+	return utils.ConcatenatedKey(fp.Tenant, fp.ID)
+}
+
+// PeriodBounds returns the start and the end of the fee period containing t
+func (fp *FeeProfile) PeriodBounds(t time.Time) (start, end time.Time, err error) {
+	y, m, d := t.Date()
+	switch fp.Period {
+	case utils.MetaDaily:
+		start = time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+		end = start.AddDate(0, 0, 1)
+	case utils.MetaWeekly: // weeks start on Monday
+		start = time.Date(y, m, d-(int(t.Weekday())+6)%7, 0, 0, 0, 0, t.Location())
+		end = start.AddDate(0, 0, 7)
+	case utils.MetaMonthly:
+		start = time.Date(y, m, 1, 0, 0, 0, 0, t.Location())
+		end = start.AddDate(0, 1, 0)
+	case utils.MetaYearly:
+		start = time.Date(y, time.January, 1, 0, 0, 0, 0, t.Location())
+		end = start.AddDate(1, 0, 0)
+	default:
+		err = fmt.Errorf("unsupported fee period: <%s>", fp.Period)
+	}
+	return
+}
+
+// fee returns the amount due for the interval between from and to, inside the period between pStart and pEnd
+func (fp *FeeProfile) fee(from, to, pStart, pEnd time.Time) float64 {
+	if !fp.ProRate {
+		return fp.Amount
+	}
+	return utils.Round(fp.Amount*float64(to.Sub(from))/float64(pEnd.Sub(pStart)),
+		globalRoundingDecimals, utils.ROUNDING_MIDDLE)
+}
+
+// Subscription attaches a FeeProfile to an account
+type Subscription struct {
+	FeeProfileID     string
+	ActivationTime   time.Time
+	DeactivationTime time.Time // zero for subscriptions without end
+	ChargedUntil     time.Time // end of the interval already charged, guards against charging twice the same period
+}
+
+// addSubscriptions subscribes the account to the fee profiles it is not already subscribed to
+func (acc *Account) addSubscriptions(fpIDs []string, aTime time.Time) {
+	if acc.Subscriptions == nil {
+		acc.Subscriptions = make(map[string]*Subscription)
+	}
+	for _, fpID := range fpIDs {
+		if _, has := acc.Subscriptions[fpID]; !has {
+			acc.Subscriptions[fpID] = &Subscription{FeeProfileID: fpID, ActivationTime: aTime}
+		}
+	}
+}
+
+// ChargeSubscriptions charges the fees due until now, one debit and CDR per period,
+// refunding the part charged in advance after a pro-rated deactivation
+func (acc *Account) ChargeSubscriptions(now time.Time) (cdrs []*CDR, err error) {
+	fpIDs := make([]string, 0, len(acc.Subscriptions))
+	for fpID := range acc.Subscriptions {
+		fpIDs = append(fpIDs, fpID)
+	}
+	sort.Strings(fpIDs)
+	tenant := utils.NewTenantID(acc.ID).Tenant
+	for _, fpID := range fpIDs {
+		sub := acc.Subscriptions[fpID]
+		fp, err := dm.GetFeeProfile(tenant, fpID, false, utils.NonTransactional)
+		if err != nil {
+			utils.Logger.Warning(fmt.Sprintf("<%s> account: %s, fee profile: %s, error: %s",
+				utils.SubscriptionS, acc.ID, fpID, err.Error()))
+			continue
+		}
+		subCDRs, err := acc.chargeSubscription(fp, sub, now)
+		cdrs = append(cdrs, subCDRs...)
+		if err != nil {
+			return cdrs, err
+		}
+		if !sub.DeactivationTime.IsZero() && !now.Before(sub.DeactivationTime) &&
+			!sub.ChargedUntil.Before(sub.DeactivationTime) { // nothing left to charge
+			delete(acc.Subscriptions, fpID)
+		}
+	}
+	return
+}
+
+func (acc *Account) chargeSubscription(fp *FeeProfile, sub *Subscription, now time.Time) (cdrs []*CDR, err error) {
+	if !sub.DeactivationTime.IsZero() && sub.ChargedUntil.After(sub.DeactivationTime) {
+		if fp.ProRate {
+			var to time.Time
+			for from := sub.DeactivationTime; from.Before(sub.ChargedUntil); from = to {
+				pStart, pEnd, err := fp.PeriodBounds(from)
+				if err != nil {
+					return cdrs, err
+				}
+				if to = pEnd; sub.ChargedUntil.Before(to) {
+					to = sub.ChargedUntil
+				}
+				cdr, err := acc.debitFee(fp, from, to, -fp.fee(from, to, pStart, pEnd))
+				if err != nil {
+					return cdrs, err
+				}
+				cdrs = append(cdrs, cdr)
+			}
+		}
+		sub.ChargedUntil = sub.DeactivationTime
+	}
+	from := sub.ActivationTime
+	if sub.ChargedUntil.After(from) {
+		from = sub.ChargedUntil
+	}
+	for !from.After(now) &&
+		(sub.DeactivationTime.IsZero() || from.Before(sub.DeactivationTime)) {
+		pStart, pEnd, err := fp.PeriodBounds(from)
+		if err != nil {
+			return cdrs, err
+		}
+		to := pEnd
+		if !sub.DeactivationTime.IsZero() && sub.DeactivationTime.Before(to) {
+			to = sub.DeactivationTime
+		}
+		cdr, err := acc.debitFee(fp, from, to, fp.fee(from, to, pStart, pEnd))
+		if err != nil {
+			return cdrs, err
+		}
+		cdrs = append(cdrs, cdr)
+		sub.ChargedUntil = to
+		from = to
+	}
+	return
+}
+
+// debitFee debits the amount out of the *default monetary balance and stores the CDR of the charge,
+// negative amounts are refunds
+func (acc *Account) debitFee(fp *FeeProfile, from, to time.Time, amount float64) (cdr *CDR, err error) {
+	if err = acc.debitBalanceAction(&Action{Balance: &BalanceFilter{
+		ID:    utils.StringPointer(utils.META_DEFAULT),
+		Type:  utils.StringPointer(utils.MONETARY),
+		Value: &utils.ValueFormula{Static: amount}}}, false); err != nil {
+		return
+	}
+	tntAcnt := utils.NewTenantID(acc.ID)
+	cdr = &CDR{RunID: utils.META_DEFAULT, Source: utils.SubscriptionS,
+		OriginID: utils.ConcatenatedKey(fp.ID, from.UTC().Format(time.RFC3339)),
+		ToR:      utils.GENERIC, RequestType: utils.META_PREPAID,
+		Tenant: tntAcnt.Tenant, Category: fp.Category, Account: tntAcnt.ID, Subject: tntAcnt.ID,
+		SetupTime: from, AnswerTime: from, Usage: to.Sub(from), Cost: amount,
+		ExtraFields: make(map[string]string)}
+	if amount < 0 {
+		cdr.RunID = utils.MetaRefund
+	}
+	// same period always produces the same CDR
+	cdr.CGRID = utils.Sha1(acc.ID, cdr.OriginID)
+	if cdrStorage == nil { // Only save if the cdrStorage is defined
+		return
+	}
+	err = cdrStorage.SetCDR(cdr, true)
+	return
+}
@@ -0,0 +1,98 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package engine
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cgrates/cgrates/utils"
+)
+
+func TestFeeProfilePeriodBounds(t *testing.T) {
+	tm := time.Date(2018, 5, 17, 14, 30, 0, 0, time.UTC) // Thursday
+	for _, tc := range []struct {
+		period     string
+		start, end time.Time
+	}{
+		{utils.MetaDaily, time.Date(2018, 5, 17, 0, 0, 0, 0, time.UTC), time.Date(2018, 5, 18, 0, 0, 0, 0, time.UTC)},
+		{utils.MetaWeekly, time.Date(2018, 5, 14, 0, 0, 0, 0, time.UTC), time.Date(2018, 5, 21, 0, 0, 0, 0, time.UTC)},
+		{utils.MetaMonthly, time.Date(2018, 5, 1, 0, 0, 0, 0, time.UTC), time.Date(2018, 6, 1, 0, 0, 0, 0, time.UTC)},
+		{utils.MetaYearly, time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC)},
+	} {
+		fp := &FeeProfile{Period: tc.period}
+		if start, end, err := fp.PeriodBounds(tm); err != nil {
+			t.Error(err)
+		} else if !start.Equal(tc.start) || !end.Equal(tc.end) {
+			t.Errorf("Period: %s, expecting: %v - %v, received: %v - %v", tc.period, tc.start, tc.end, start, end)
+		}
+	}
+	fp := &FeeProfile{Period: "*hourly"}
+	if _, _, err := fp.PeriodBounds(tm); err == nil {
+		t.Error("Expecting error for unsupported period")
+	}
+}
+
+func TestAccountChargeSubscriptions(t *testing.T) {
+	fp := &FeeProfile{Tenant: "cgrates.org", ID: "FEE_TEST_MONTHLY",
+		Period: utils.MetaMonthly, Amount: 30, Category: "subscription", ProRate: true}
+	if err := dm.SetFeeProfile(fp); err != nil {
+		t.Fatal(err)
+	}
+	acc := &Account{ID: "cgrates.org:fee_subscriber",
+		BalanceMap: map[string]Balances{utils.MONETARY: Balances{
+			&Balance{ID: utils.META_DEFAULT, Value: 100}}}}
+	acc.addSubscriptions([]string{fp.ID}, time.Date(2018, 4, 16, 0, 0, 0, 0, time.UTC))
+	now := time.Date(2018, 5, 10, 0, 0, 0, 0, time.UTC)
+	if cdrs, err := acc.ChargeSubscriptions(now); err != nil {
+		t.Fatal(err)
+	} else if len(cdrs) != 2 {
+		t.Fatalf("Expecting 2 CDRs, received: %s", utils.ToJSON(cdrs))
+	} else if cdrs[0].Cost != 15 || cdrs[1].Cost != 30 ||
+		cdrs[0].ToR != utils.GENERIC || cdrs[0].Usage != 15*24*time.Hour {
+		t.Errorf("Unexpected CDRs: %s", utils.ToJSON(cdrs))
+	}
+	if val := acc.BalanceMap[utils.MONETARY][0].GetValue(); val != 55 {
+		t.Errorf("Expecting 55, received: %v", val)
+	}
+	// charging again within the same period has no effect
+	if cdrs, err := acc.ChargeSubscriptions(now); err != nil {
+		t.Fatal(err)
+	} else if len(cdrs) != 0 {
+		t.Errorf("Expecting no CDRs, received: %s", utils.ToJSON(cdrs))
+	}
+	if val := acc.BalanceMap[utils.MONETARY][0].GetValue(); val != 55 {
+		t.Errorf("Expecting 55, received: %v", val)
+	}
+	// deactivation refunds the remaining part of the period
+	acc.Subscriptions[fp.ID].DeactivationTime = time.Date(2018, 5, 16, 0, 0, 0, 0, time.UTC)
+	if cdrs, err := acc.ChargeSubscriptions(time.Date(2018, 5, 20, 0, 0, 0, 0, time.UTC)); err != nil {
+		t.Fatal(err)
+	} else if len(cdrs) != 1 || cdrs[0].RunID != utils.MetaRefund {
+		t.Errorf("Unexpected CDRs: %s", utils.ToJSON(cdrs))
+	}
+	eVal := utils.Round(55+30*16.0/31.0, globalRoundingDecimals, utils.ROUNDING_MIDDLE)
+	if val := utils.Round(acc.BalanceMap[utils.MONETARY][0].GetValue(),
+		globalRoundingDecimals, utils.ROUNDING_MIDDLE); val != eVal {
+		t.Errorf("Expecting %v, received: %v", eVal, val)
+	}
+	if _, has := acc.Subscriptions[fp.ID]; has {
+		t.Error("Subscription should be removed after deactivation")
+	}
+}
@@ -19,8 +19,11 @@ along with this program.  If not, see <http://www.gnu.org/licenses/>
 package engine
 
 import (
+	"errors"
 	"fmt"
+	"reflect"
 	"sort"
+	"sync"
 	"time"
 
 	"github.com/cgrates/cgrates/cache"
@@ -39,6 +42,7 @@ type Supplier struct {
 	ResourceIDs   []string // queried in some strategies
 	StatIDs       []string // queried in some strategies
 	Weight        float64
+	Disabled      bool // set out of ThresholdS with *disable_supplier, not considered for sorting
 }
 
 // SupplierProfile represents the configuration of a Supplier profile
@@ -71,6 +75,12 @@ func (lps SupplierProfiles) Sort() {
 func NewSupplierService(dm *DataManager, timezone string,
 	filterS *FilterS, indexedFields []string, resourceS,
 	statS rpcclient.RpcClientConnection) (spS *SupplierService, err error) {
+	if resourceS != nil && reflect.ValueOf(resourceS).IsNil() { // fix nil value in interface
+		resourceS = nil
+	}
+	if statS != nil && reflect.ValueOf(statS).IsNil() {
+		statS = nil
+	}
 	spS = &SupplierService{
 		dm:            dm,
 		timezone:      timezone,
@@ -90,6 +100,7 @@ type SupplierService struct {
 	timezone      string
 	filterS       *FilterS
 	indexedFields []string
+	ifMux         sync.RWMutex // protects indexedFields
 	resourceS,
 	statS rpcclient.RpcClientConnection
 	sorter SupplierSortDispatcher
@@ -110,10 +121,21 @@ func (spS *SupplierService) Shutdown() error {
 	return nil
 }
 
+// ReloadConfig applies the indexed_fields out of the running configuration
+func (spS *SupplierService) ReloadConfig(cfg *config.CGRConfig) error {
+	spS.ifMux.Lock()
+	spS.indexedFields = cfg.SupplierSCfg().IndexedFields
+	spS.ifMux.Unlock()
+	return nil
+}
+
 // matchingSupplierProfilesForEvent returns ordered list of matching resources which are active by the time of the call
 func (spS *SupplierService) matchingSupplierProfilesForEvent(ev *utils.CGREvent) (sPrfls SupplierProfiles, err error) {
 	matchingLPs := make(map[string]*SupplierProfile)
-	sPrflIDs, err := matchingItemIDsForEvent(ev.Event, spS.indexedFields,
+	spS.ifMux.RLock()
+	indexedFields := spS.indexedFields
+	spS.ifMux.RUnlock()
+	sPrflIDs, err := matchingItemIDsForEvent(ev.Event, indexedFields,
 		spS.dm, utils.SupplierProfilesStringIndex+ev.Tenant)
 	if err != nil {
 		return nil, err
@@ -261,12 +283,51 @@ func (spS *SupplierService) costForEvent(ev *utils.CGREvent,
 
 // statMetrics will query a list of statIDs and return composed metric values
 // first metric found is always returned
-func (spS *SupplierService) statMetrics(statIDs []string, metricIDs []string) (sms map[string]StatMetric, err error) {
+func (spS *SupplierService) statMetrics(tenant string, statIDs []string,
+	metricIDs []string) (sms map[string]float64, err error) {
+	sms = make(map[string]float64)
+	if spS.statS == nil {
+		return nil, errors.New("no connection to StatS")
+	}
+	for _, statID := range statIDs {
+		var metrics map[string]float64
+		if err = spS.statS.Call(utils.StatSv1GetQueueFloatMetrics,
+			&utils.TenantID{Tenant: tenant, ID: statID}, &metrics); err != nil {
+			if err.Error() != utils.ErrNotFound.Error() {
+				return nil, err
+			}
+			err = nil
+			continue
+		}
+		for _, metricID := range metricIDs {
+			if _, has := sms[metricID]; has {
+				continue
+			}
+			if val, has := metrics[metricID]; has && val != STATS_NA {
+				sms[metricID] = val
+			}
+		}
+	}
 	return
 }
 
 // resourceUsage returns sum of all resource usages out of list
-func (spS *SupplierService) resourceUsage(resIDs []string) (tUsage float64, err error) {
+func (spS *SupplierService) resourceUsage(tenant string, resIDs []string) (tUsage float64, err error) {
+	if spS.resourceS == nil {
+		return 0, errors.New("no connection to ResourceS")
+	}
+	for _, resID := range resIDs {
+		var res Resource
+		if err = spS.resourceS.Call(utils.ResourceSv1GetResource,
+			&utils.TenantID{Tenant: tenant, ID: resID}, &res); err != nil {
+			if err.Error() != utils.ErrNotFound.Error() {
+				return 0, err
+			}
+			err = nil
+			continue
+		}
+		tUsage += res.totalUsage()
+	}
 	return
 }
 
@@ -282,6 +343,9 @@ func (spS *SupplierService) sortedSuppliersForEvent(ev *utils.CGREvent) (sortedS
 	splPrfl := suppPrfls[0] // pick up the first lcr profile as winner
 	var spls []*Supplier
 	for _, s := range splPrfl.Suppliers {
+		if s.Disabled {
+			continue
+		}
 		if len(s.FilterIDs) != 0 { // filters should be applied, check them here
 			if pass, err := spS.filterS.PassFiltersForEvent(ev.Tenant,
 				ev.Event, s.FilterIDs); err != nil {
@@ -292,7 +356,8 @@ func (spS *SupplierService) sortedSuppliersForEvent(ev *utils.CGREvent) (sortedS
 		}
 		spls = append(spls, s)
 	}
-	return spS.sorter.SortSuppliers(splPrfl.ID, splPrfl.Sorting, spls, ev)
+	return spS.sorter.SortSuppliers(splPrfl.ID, splPrfl.Sorting, spls, ev,
+		splPrfl.SortingParams)
 }
 
 // V1GetSuppliersForEvent returns the list of valid supplier IDs
@@ -0,0 +1,189 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package engine
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/cgrates/cgrates/config"
+	"github.com/cgrates/cgrates/utils"
+)
+
+// TaxTier applies Value as percentage on the part of the cost above Threshold
+type TaxTier struct {
+	Threshold float64
+	Value     float64
+}
+
+// Tax is one tax applied on top of the rated cost
+type Tax struct {
+	ID    string
+	Type  string // *percent, *fixed or *tiered
+	Value float64
+	Tiers []*TaxTier // sorted on Threshold, used by *tiered
+}
+
+// Amount computes the tax for the given cost
+func (tx *Tax) Amount(cost float64) (amount float64, err error) {
+	switch tx.Type {
+	case utils.MetaPercent:
+		amount = cost * tx.Value / 100
+	case utils.MetaFixed:
+		amount = tx.Value
+	case utils.MetaTiered:
+		for i, tr := range tx.Tiers {
+			if cost <= tr.Threshold {
+				break
+			}
+			upper := cost
+			if i+1 < len(tx.Tiers) && tx.Tiers[i+1].Threshold < cost {
+				upper = tx.Tiers[i+1].Threshold
+			}
+			amount += (upper - tr.Threshold) * tr.Value / 100
+		}
+	default:
+		return 0, fmt.Errorf("unsupported tax type: <%s>", tx.Type)
+	}
+	return
+}
+
+// TaxProfile groups the taxes applied for the events matching its filters
+type TaxProfile struct {
+	Tenant             string
+	ID                 string
+	FilterIDs          []string
+	ActivationInterval *utils.ActivationInterval // Activation interval
+	Taxes              []*Tax
+	Weight             float64
+}
+
+func (tp *TaxProfile) TenantID() string {
+	return utils.ConcatenatedKey(tp.Tenant, tp.ID)
+}
+
+// TaxProfiles is a sortable list of TaxProfiles
+type TaxProfiles []*TaxProfile
+
+// Sort is part of sort interface, sort based on Weight
+func (tps TaxProfiles) Sort() {
+	sort.Slice(tps, func(i, j int) bool { return tps[i].Weight > tps[j].Weight })
+}
+
+// TaxCharge is the tax amount applied on one cost
+type TaxCharge struct {
+	TaxProfileID string
+	TaxID        string
+	Type         string
+	Amount       float64
+}
+
+func (tc *TaxCharge) Clone() *TaxCharge {
+	cln := *tc
+	return &cln
+}
+
+func NewTaxService(dm *DataManager, filterS *FilterS) *TaxService {
+	return &TaxService{dm: dm, filterS: filterS}
+}
+
+// TaxService computes the local taxes for rated costs
+type TaxService struct {
+	dm      *DataManager
+	filterS *FilterS
+}
+
+// matchingTaxProfilesForEvent returns the TaxProfiles matching the event, ordered on weight
+func (txS *TaxService) matchingTaxProfilesForEvent(tenant string, ev map[string]interface{},
+	evTime time.Time) (tPrfls TaxProfiles, err error) {
+	tPrflIDs, err := matchingItemIDsForEvent(ev, nil, txS.dm,
+		utils.TaxProfilesStringIndex+tenant)
+	if err != nil {
+		return nil, err
+	}
+	for tpID := range tPrflIDs {
+		tPrfl, err := txS.dm.GetTaxProfile(tenant, tpID, false, utils.NonTransactional)
+		if err != nil {
+			if err == utils.ErrNotFound {
+				continue
+			}
+			return nil, err
+		}
+		if tPrfl.ActivationInterval != nil &&
+			!tPrfl.ActivationInterval.IsActiveAtTime(evTime) { // not active
+			continue
+		}
+		if pass, err := txS.filterS.PassFiltersForEvent(tenant,
+			ev, tPrfl.FilterIDs); err != nil {
+			return nil, err
+		} else if !pass {
+			continue
+		}
+		tPrfls = append(tPrfls, tPrfl)
+	}
+	tPrfls.Sort()
+	return
+}
+
+// TaxesForCost returns the taxes of the highest weighted TaxProfile matching the event
+func (txS *TaxService) TaxesForCost(tenant string, ev map[string]interface{},
+	evTime time.Time, cost float64) (taxes []*TaxCharge, err error) {
+	tPrfls, err := txS.matchingTaxProfilesForEvent(tenant, ev, evTime)
+	if err != nil {
+		return
+	}
+	if len(tPrfls) == 0 {
+		return nil, utils.ErrNotFound
+	}
+	for _, tx := range tPrfls[0].Taxes {
+		amount, err := tx.Amount(cost)
+		if err != nil {
+			return nil, err
+		}
+		taxes = append(taxes, &TaxCharge{
+			TaxProfileID: tPrfls[0].ID,
+			TaxID:        tx.ID,
+			Type:         tx.Type,
+			Amount: utils.Round(amount,
+				config.CgrConfig().RoundingDecimals, utils.ROUNDING_MIDDLE),
+		})
+	}
+	return
+}
+
+// ApplyTaxes populates the taxes on the CostDetails of a rated CDR
+func (txS *TaxService) ApplyTaxes(cdr *CDR) (err error) {
+	if cdr.CostDetails == nil || cdr.Cost <= 0 {
+		return
+	}
+	ev, err := cdr.AsMapStringIface()
+	if err != nil {
+		return
+	}
+	taxes, err := txS.TaxesForCost(cdr.Tenant, ev, cdr.AnswerTime, cdr.Cost)
+	if err != nil {
+		if err == utils.ErrNotFound {
+			err = nil
+		}
+		return
+	}
+	cdr.CostDetails.Taxes = taxes
+	return
+}
@@ -0,0 +1,93 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package engine
+
+import (
+	"testing"
+
+	"github.com/cgrates/cgrates/utils"
+)
+
+func TestTaxAmount(t *testing.T) {
+	tx := &Tax{ID: "VAT", Type: utils.MetaPercent, Value: 19}
+	if amount, err := tx.Amount(100); err != nil {
+		t.Error(err)
+	} else if amount != 19 {
+		t.Errorf("Expecting 19, received: %v", amount)
+	}
+	tx = &Tax{ID: "CONNECT", Type: utils.MetaFixed, Value: 0.01}
+	if amount, err := tx.Amount(100); err != nil {
+		t.Error(err)
+	} else if amount != 0.01 {
+		t.Errorf("Expecting 0.01, received: %v", amount)
+	}
+	tx = &Tax{ID: "USF", Type: utils.MetaTiered,
+		Tiers: []*TaxTier{
+			&TaxTier{Threshold: 0, Value: 5},
+			&TaxTier{Threshold: 100, Value: 2},
+		}}
+	if amount, err := tx.Amount(50); err != nil {
+		t.Error(err)
+	} else if amount != 2.5 {
+		t.Errorf("Expecting 2.5, received: %v", amount)
+	}
+	if amount, err := tx.Amount(200); err != nil { // 100*5% + 100*2%
+		t.Error(err)
+	} else if amount != 7 {
+		t.Errorf("Expecting 7, received: %v", amount)
+	}
+	tx = &Tax{ID: "UNKNOWN", Type: "*unknown"}
+	if _, err := tx.Amount(100); err == nil {
+		t.Error("Expecting error for unsupported tax type")
+	}
+}
+
+func TestTaxProfilesSort(t *testing.T) {
+	tps := TaxProfiles{
+		&TaxProfile{Tenant: "cgrates.org", ID: "TAX_1", Weight: 10},
+		&TaxProfile{Tenant: "cgrates.org", ID: "TAX_2", Weight: 20},
+	}
+	tps.Sort()
+	if tps[0].ID != "TAX_2" {
+		t.Errorf("Unexpected order: %s", utils.ToJSON(tps))
+	}
+}
+
+func TestCDRTaxCost(t *testing.T) {
+	cdr := &CDR{Cost: 1.5, CostDetails: &CallCost{Currency: "EUR",
+		Taxes: []*TaxCharge{
+			&TaxCharge{TaxProfileID: "TAX_DE", TaxID: "VAT", Type: utils.MetaPercent, Amount: 0.285},
+			&TaxCharge{TaxProfileID: "TAX_DE", TaxID: "CONNECT", Type: utils.MetaFixed, Amount: 0.01},
+		}}}
+	if taxCost := cdr.TaxCost(""); taxCost != 0.295 {
+		t.Errorf("Expecting 0.295, received: %v", taxCost)
+	}
+	if taxCost := cdr.TaxCost("VAT"); taxCost != 0.285 {
+		t.Errorf("Expecting 0.285, received: %v", taxCost)
+	}
+	if val := cdr.FieldAsString(&utils.RSRField{Id: utils.Currency}); val != "EUR" {
+		t.Errorf("Expecting EUR, received: %s", val)
+	}
+	if val := cdr.FieldAsString(&utils.RSRField{Id: utils.TaxCost + utils.CONCATENATED_KEY_SEP + "CONNECT"}); val != "0.01" {
+		t.Errorf("Expecting 0.01, received: %s", val)
+	}
+	if val := cdr.FormatTaxCost("", 0, 3); val != "0.295" {
+		t.Errorf("Expecting 0.295, received: %s", val)
+	}
+}
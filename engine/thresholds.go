@@ -21,6 +21,7 @@ package engine
 import (
 	"fmt"
 	"math/rand"
+	"reflect"
 	"sort"
 	"sync"
 	"time"
@@ -29,6 +30,7 @@ import (
 	"github.com/cgrates/cgrates/config"
 	"github.com/cgrates/cgrates/guardian"
 	"github.com/cgrates/cgrates/utils"
+	"github.com/cgrates/rpcclient"
 )
 
 type ThresholdProfile struct {
@@ -66,7 +68,7 @@ func (t *Threshold) TenantID() string {
 
 // ProcessEvent processes an ThresholdEvent
 // concurrentActions limits the number of simultaneous action sets executed
-func (t *Threshold) ProcessEvent(ev *utils.CGREvent, dm *DataManager) (err error) {
+func (t *Threshold) ProcessEvent(ev *utils.CGREvent, tS *ThresholdService) (err error) {
 	if t.Snooze.After(time.Now()) { // snoozed, not executing actions
 		return
 	}
@@ -79,28 +81,66 @@ func (t *Threshold) ProcessEvent(ev *utils.CGREvent, dm *DataManager) (err error
 		acntID = utils.ConcatenatedKey(ev.Tenant, acnt)
 	}
 	for _, actionSetID := range t.tPrfl.ActionIDs {
+		if t.tPrfl.Async {
+			thd := *t // snapshot of the threshold state for the actions
+			go func(actionSetID string) {
+				if errExec := thd.executeActions(actionSetID, acntID, ev, tS); errExec != nil {
+					utils.Logger.Log(utils.LOGLEVEL_WARNING, ev.ID, fmt.Sprintf("<ThresholdS> failed executing actions: %s, error: %s", actionSetID, errExec.Error()))
+				}
+			}(actionSetID)
+
+		} else {
+			if errExec := t.executeActions(actionSetID, acntID, ev, tS); errExec != nil {
+				utils.Logger.Log(utils.LOGLEVEL_WARNING, ev.ID, fmt.Sprintf("<ThresholdS> failed executing actions: %s, error: %s", actionSetID, errExec.Error()))
+				err = utils.ErrPartiallyExecuted
+			}
+		}
+	}
+	return
+}
+
+// executeActions executes one action set, the ThresholdS specific actions receive the event
+// while the rest of them are executed on the account within an ActionTiming
+func (t *Threshold) executeActions(actionSetID, acntID string, ev *utils.CGREvent, tS *ThresholdService) (err error) {
+	acts, err := tS.dm.GetActions(actionSetID, false, utils.NonTransactional)
+	if err != nil {
+		return
+	}
+	var acntActs, thdActs Actions
+	for _, a := range acts {
+		if _, has := getThresholdActionFunc(a.ActionType); has {
+			thdActs = append(thdActs, a)
+		} else {
+			acntActs = append(acntActs, a)
+		}
+	}
+	if len(acntActs) != 0 {
 		at := &ActionTiming{
 			Uuid:      utils.GenUUID(),
 			ActionsID: actionSetID,
 		}
+		at.SetActions(acntActs)
 		if acntID != "" {
 			at.accountIDs = utils.NewStringMap(acntID)
 		}
-		if t.tPrfl.Async {
-
-			go func() {
-				if errExec := at.Execute(nil, nil); errExec != nil {
-					utils.Logger.Warning(fmt.Sprintf("<ThresholdS> failed executing actions: %s, error: %s", actionSetID, errExec.Error()))
-				}
-			}()
-
-		} else {
-			if errExec := at.Execute(nil, nil); errExec != nil {
-				utils.Logger.Warning(fmt.Sprintf("<ThresholdS> failed executing actions: %s, error: %s", actionSetID, errExec.Error()))
-				err = utils.ErrPartiallyExecuted
-			}
+		if err = at.Execute(nil, nil); err != nil {
+			return
 		}
 	}
+	thdActs.Sort()
+	var withErrors bool
+	for _, a := range thdActs {
+		actionFunction, _ := getThresholdActionFunc(a.ActionType)
+		if errAct := actionFunction(tS, t, ev, a); errAct != nil {
+			utils.Logger.Log(utils.LOGLEVEL_WARNING, ev.ID,
+				fmt.Sprintf("<ThresholdS> threshold: %s, error executing action %s: %s",
+					t.TenantID(), a.ActionType, errAct.Error()))
+			withErrors = true
+		}
+	}
+	if withErrors {
+		err = utils.ErrPartiallyExecuted
+	}
 	return
 }
 
@@ -113,8 +153,16 @@ func (ts Thresholds) Sort() {
 }
 
 func NewThresholdService(dm *DataManager, indexedFields []string, storeInterval time.Duration,
-	filterS *FilterS) (tS *ThresholdService, err error) {
+	filterS *FilterS, statS, resourceS rpcclient.RpcClientConnection) (tS *ThresholdService, err error) {
+	if statS != nil && reflect.ValueOf(statS).IsNil() { // fix nil value in interface
+		statS = nil
+	}
+	if resourceS != nil && reflect.ValueOf(resourceS).IsNil() {
+		resourceS = nil
+	}
 	return &ThresholdService{dm: dm,
+		statS:         statS,
+		resourceS:     resourceS,
 		indexedFields: indexedFields,
 		storeInterval: storeInterval,
 		filterS:       filterS,
@@ -125,12 +173,51 @@ func NewThresholdService(dm *DataManager, indexedFields []string, storeInterval
 // ThresholdService manages Threshold execution and storing them to dataDB
 type ThresholdService struct {
 	dm            *DataManager
-	indexedFields []string // fields considered when searching for matching thresholds
+	indexedFields []string     // fields considered when searching for matching thresholds
+	ifMux         sync.RWMutex // protects indexedFields
 	storeInterval time.Duration
 	filterS       *FilterS
 	stopBackup    chan struct{}
-	storedTdIDs   utils.StringMap // keep a record of stats which need saving, map[statsTenantID]bool
-	stMux         sync.RWMutex    // protects storedTdIDs
+	storedTdIDs   utils.StringMap               // keep a record of stats which need saving, map[statsTenantID]bool
+	stMux         sync.RWMutex                  // protects storedTdIDs
+	statS         rpcclient.RpcClientConnection // used by threshold actions
+	resourceS     rpcclient.RpcClientConnection // used by threshold actions
+	connMux       sync.RWMutex                  // protects statS and resourceS
+}
+
+// SetStatSConnection sets the connection towards StatS after the service was started
+// since StatS can depend on ThresholdS on it's own
+func (tS *ThresholdService) SetStatSConnection(statS rpcclient.RpcClientConnection) {
+	if statS != nil && reflect.ValueOf(statS).IsNil() { // fix nil value in interface
+		statS = nil
+	}
+	tS.connMux.Lock()
+	tS.statS = statS
+	tS.connMux.Unlock()
+}
+
+// SetResourceSConnection sets the connection towards ResourceS after the service was started
+func (tS *ThresholdService) SetResourceSConnection(resourceS rpcclient.RpcClientConnection) {
+	if resourceS != nil && reflect.ValueOf(resourceS).IsNil() { // fix nil value in interface
+		resourceS = nil
+	}
+	tS.connMux.Lock()
+	tS.resourceS = resourceS
+	tS.connMux.Unlock()
+}
+
+func (tS *ThresholdService) statSConn() (statS rpcclient.RpcClientConnection) {
+	tS.connMux.RLock()
+	statS = tS.statS
+	tS.connMux.RUnlock()
+	return
+}
+
+func (tS *ThresholdService) resourceSConn() (resourceS rpcclient.RpcClientConnection) {
+	tS.connMux.RLock()
+	resourceS = tS.resourceS
+	tS.connMux.RUnlock()
+	return
 }
 
 // Called to start the service
@@ -150,6 +237,14 @@ func (tS *ThresholdService) Shutdown() error {
 	return nil
 }
 
+// ReloadConfig applies the indexed_fields out of the running configuration
+func (tS *ThresholdService) ReloadConfig(cfg *config.CGRConfig) error {
+	tS.ifMux.Lock()
+	tS.indexedFields = cfg.ThresholdSCfg().IndexedFields
+	tS.ifMux.Unlock()
+	return nil
+}
+
 // backup will regularly store resources changed to dataDB
 func (tS *ThresholdService) runBackup() {
 	if tS.storeInterval <= 0 {
@@ -215,7 +310,10 @@ func (tS *ThresholdService) StoreThreshold(t *Threshold) (err error) {
 // matchingThresholdsForEvent returns ordered list of matching thresholds which are active for an Event
 func (tS *ThresholdService) matchingThresholdsForEvent(ev *utils.CGREvent) (ts Thresholds, err error) {
 	matchingTs := make(map[string]*Threshold)
-	tIDs, err := matchingItemIDsForEvent(ev.Event, tS.indexedFields, tS.dm, utils.ThresholdStringIndex+ev.Tenant)
+	tS.ifMux.RLock()
+	indexedFields := tS.indexedFields
+	tS.ifMux.RUnlock()
+	tIDs, err := matchingItemIDsForEvent(ev.Event, indexedFields, tS.dm, utils.ThresholdStringIndex+ev.Tenant)
 	if err != nil {
 		return nil, err
 	}
@@ -234,6 +332,9 @@ func (tS *ThresholdService) matchingThresholdsForEvent(ev *utils.CGREvent) (ts T
 			!tPrfl.ActivationInterval.IsActiveAtTime(time.Now()) { // not active
 			continue
 		}
+		if thdID, has := ev.Event[utils.ThresholdID]; has && thdID == tPrfl.ID { // event emitted by the threshold itself
+			continue
+		}
 		if pass, err := tS.filterS.PassFiltersForEvent(ev.Tenant, ev.Event, tPrfl.FilterIDs); err != nil {
 			return nil, err
 		} else if !pass {
@@ -276,9 +377,9 @@ func (tS *ThresholdService) processEvent(ev *utils.CGREvent) (hits int, err erro
 	var withErrors bool
 	for _, t := range matchTs {
 		t.Hits += 1
-		err = t.ProcessEvent(ev, tS.dm)
+		err = t.ProcessEvent(ev, tS)
 		if err != nil {
-			utils.Logger.Warning(
+			utils.Logger.Log(utils.LOGLEVEL_WARNING, ev.ID,
 				fmt.Sprintf("<ThresholdService> threshold: %s, ignoring event: %s, error: %s",
 					t.TenantID(), ev.TenantID(), err.Error()))
 			withErrors = true
@@ -286,7 +387,7 @@ func (tS *ThresholdService) processEvent(ev *utils.CGREvent) (hits int, err erro
 		}
 		if t.dirty == nil { // one time threshold
 			if err = tS.dm.RemoveThreshold(t.Tenant, t.ID, utils.NonTransactional); err != nil {
-				utils.Logger.Warning(
+				utils.Logger.Log(utils.LOGLEVEL_WARNING, ev.ID,
 					fmt.Sprintf("<ThresholdService> failed removing non-recurrent threshold: %s, error: %s",
 						t.TenantID(), err.Error()))
 				withErrors = true
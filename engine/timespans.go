@@ -38,6 +38,7 @@ type TimeSpan struct {
 	MatchedSubject, MatchedPrefix, MatchedDestId, RatingPlanId string
 	CompressFactor                                             int
 	ratingInfo                                                 *RatingInfo
+	volumeUsage                                                map[string]time.Duration // usage cumulated on the account volume counters before this timespan's call
 }
 
 type Increment struct {
@@ -50,20 +51,23 @@ type Increment struct {
 
 // Holds information about the balance that made a specific payment
 type DebitInfo struct {
-	Unit      *UnitInfo
-	Monetary  *MonetaryInfo
-	AccountID string // used when debited from shared balance
+	Unit          *UnitInfo
+	Monetary      *MonetaryInfo
+	AccountID     string // used when debited from shared balance
+	VolumeCounter string // account volume counter the usage was counted on
 }
 
 func (di *DebitInfo) Equal(other *DebitInfo) bool {
 	return di.Unit.Equal(other.Unit) &&
 		di.Monetary.Equal(other.Monetary) &&
-		di.AccountID == other.AccountID
+		di.AccountID == other.AccountID &&
+		di.VolumeCounter == other.VolumeCounter
 }
 
 func (di *DebitInfo) Clone() *DebitInfo {
 	nDi := &DebitInfo{
-		AccountID: di.AccountID,
+		AccountID:     di.AccountID,
+		VolumeCounter: di.VolumeCounter,
 	}
 	if di.Unit != nil {
 		nDi.Unit = di.Unit.Clone()
@@ -79,6 +83,7 @@ type MonetaryInfo struct {
 	ID           string
 	Value        float64
 	RateInterval *RateInterval
+	ExchangeRate float64 // rate applied when the balance currency differs from the rating one
 }
 
 func (mi *MonetaryInfo) Clone() *MonetaryInfo {
@@ -505,11 +510,13 @@ func (ts *TimeSpan) SplitByRateInterval(i *RateInterval, data bool) (nts *TimeSp
 	// split by GroupStart
 	if i.Rating != nil {
 		i.Rating.Rates.Sort()
+		grpStart := ts.groupStartFor(i)
+		grpEnd := ts.DurationIndex + ts.volumeStart(i)
 		for _, rate := range i.Rating.Rates {
-			if ts.GetGroupStart() < rate.GroupIntervalStart && ts.GetGroupEnd() > rate.GroupIntervalStart {
+			if grpStart < rate.GroupIntervalStart && grpEnd > rate.GroupIntervalStart {
 				//log.Print("Splitting")
 				ts.SetRateInterval(i)
-				splitTime := ts.TimeStart.Add(rate.GroupIntervalStart - ts.GetGroupStart())
+				splitTime := ts.TimeStart.Add(rate.GroupIntervalStart - grpStart)
 				nts = &TimeSpan{
 					TimeStart: splitTime,
 					TimeEnd:   ts.TimeEnd,
@@ -668,15 +675,28 @@ func (ts *TimeSpan) SplitByDay() (newTs *TimeSpan) {
 
 // Returns the starting time of this timespan
 func (ts *TimeSpan) GetGroupStart() time.Duration {
+	return ts.groupStartFor(ts.RateInterval)
+}
+
+func (ts *TimeSpan) GetGroupEnd() time.Duration {
+	return ts.DurationIndex + ts.volumeStart(ts.RateInterval)
+}
+
+// groupStartFor returns the starting time of this timespan as seen by the rate groups of ri
+func (ts *TimeSpan) groupStartFor(ri *RateInterval) time.Duration {
 	s := ts.DurationIndex - ts.GetDuration()
 	if s < 0 {
 		s = 0
 	}
-	return s
+	return s + ts.volumeStart(ri)
 }
 
-func (ts *TimeSpan) GetGroupEnd() time.Duration {
-	return ts.DurationIndex
+// volumeStart returns the usage already cumulated in the period on the volume counter of ri
+func (ts *TimeSpan) volumeStart(ri *RateInterval) time.Duration {
+	if ri == nil || ri.Rating == nil || ri.Rating.VolumeCounter == "" {
+		return 0
+	}
+	return ts.volumeUsage[ri.Rating.VolumeCounter]
 }
 
 // sets the DurationIndex attribute to reflect new timespan
@@ -689,6 +709,7 @@ func (ts *TimeSpan) SetNewDurationIndex(nts *TimeSpan) {
 }
 
 func (nts *TimeSpan) copyRatingInfo(ts *TimeSpan) {
+	nts.volumeUsage = ts.volumeUsage
 	if ts.ratingInfo == nil {
 		return
 	}
@@ -755,7 +776,7 @@ func (ts *TimeSpan) hasBetterRateIntervalThan(interval *RateInterval) bool {
 		return false
 	}
 	ownPrice, _, _ := ts.RateInterval.GetRateParameters(ts.GetGroupStart())
-	otherPrice, _, _ := interval.GetRateParameters(ts.GetGroupStart())
+	otherPrice, _, _ := interval.GetRateParameters(ts.groupStartFor(interval))
 	// if own price is smaller than it's better
 	if ownPrice < otherPrice {
 		return true
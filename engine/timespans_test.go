@@ -304,6 +304,38 @@ func TestTSTimespanSplitGroupedRates(t *testing.T) {
 	}
 }
 
+func TestTSTimespanSplitVolumeUsage(t *testing.T) {
+	i := &RateInterval{
+		Timing: &RITiming{
+			EndTime: "17:59:00",
+		},
+		Rating: &RIRate{
+			VolumeCounter: "VOL_MONTH",
+			Rates:         RateGroups{&Rate{0, 2, 1 * time.Second, 1 * time.Second}, &Rate{900 * time.Second, 1, 1 * time.Second, 1 * time.Second}},
+		},
+	}
+	t1 := time.Date(2012, time.February, 3, 17, 30, 0, 0, time.UTC)
+	t2 := time.Date(2012, time.February, 3, 18, 00, 0, 0, time.UTC)
+	ts := &TimeSpan{TimeStart: t1, TimeEnd: t2, DurationIndex: 1800 * time.Second, ratingInfo: &RatingInfo{},
+		volumeUsage: map[string]time.Duration{"VOL_MONTH": 600 * time.Second}}
+	nts := ts.SplitByRateInterval(i, false)
+	splitTime := time.Date(2012, time.February, 3, 17, 35, 00, 0, time.UTC)
+	if ts.TimeStart != t1 || ts.TimeEnd != splitTime {
+		t.Error("Incorrect first half", ts.TimeStart, ts.TimeEnd)
+	}
+	if nts == nil || nts.TimeStart != splitTime || nts.TimeEnd != t2 {
+		t.Fatal("Incorrect second half", nts)
+	}
+	if ts.GetGroupStart() != 600*time.Second || nts.GetGroupStart() != 900*time.Second {
+		t.Error("Wrong group starts: ", ts.GetGroupStart(), nts.GetGroupStart())
+	}
+	c1 := ts.RateInterval.GetCost(ts.GetDuration(), ts.GetGroupStart())
+	c2 := nts.RateInterval.GetCost(nts.GetDuration(), nts.GetGroupStart())
+	if c1 != 600 || c2 != 1500 {
+		t.Error("Wrong costs: ", c1, c2)
+	}
+}
+
 func TestTSTimespanSplitGroupedRatesIncrements(t *testing.T) {
 	i := &RateInterval{
 		Timing: &RITiming{
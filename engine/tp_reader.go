@@ -24,6 +24,7 @@ import (
 	"log"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/cgrates/cgrates/cache"
 	"github.com/cgrates/cgrates/structmatcher"
@@ -59,6 +60,9 @@ type TpReader struct {
 	filters           map[utils.TenantID]*utils.TPFilterProfile
 	sppProfiles       map[utils.TenantID]*utils.TPSupplierProfile
 	attributeProfiles map[utils.TenantID]*utils.TPAttributeProfile
+	exchangeRates     map[string]*utils.TPExchangeRate // FromCurrency:ToCurrency, exchange rate
+	taxProfiles       map[utils.TenantID]*utils.TPTaxProfile
+	feeProfiles       map[utils.TenantID]*utils.TPFeeProfile
 	resources         []*utils.TenantID // IDs of resources which need creation based on resourceProfiles
 	statQueues        []*utils.TenantID // IDs of statQueues which need creation based on statQueueProfiles
 	thresholds        []*utils.TenantID // IDs of thresholds which need creation based on thresholdProfiles
@@ -72,6 +76,7 @@ type TpReader struct {
 	resIndexers  map[string]*ReqFilterIndexer // tenant, indexer
 	sppIndexers  map[string]*ReqFilterIndexer // tenant, indexer
 	attrIndexers map[string]*ReqFilterIndexer // tenant:context , indexer
+	txpIndexers  map[string]*ReqFilterIndexer // tenant, indexer
 }
 
 func NewTpReader(db DataDB, lr LoadReader, tpid, timezone string) *TpReader {
@@ -144,6 +149,9 @@ func (tpr *TpReader) Init() {
 	tpr.thProfiles = make(map[utils.TenantID]*utils.TPThreshold)
 	tpr.sppProfiles = make(map[utils.TenantID]*utils.TPSupplierProfile)
 	tpr.attributeProfiles = make(map[utils.TenantID]*utils.TPAttributeProfile)
+	tpr.exchangeRates = make(map[string]*utils.TPExchangeRate)
+	tpr.taxProfiles = make(map[utils.TenantID]*utils.TPTaxProfile)
+	tpr.feeProfiles = make(map[utils.TenantID]*utils.TPFeeProfile)
 	tpr.filters = make(map[utils.TenantID]*utils.TPFilterProfile)
 	tpr.revDests = make(map[string][]string)
 	tpr.revAliases = make(map[string][]string)
@@ -153,6 +161,7 @@ func (tpr *TpReader) Init() {
 	tpr.resIndexers = make(map[string]*ReqFilterIndexer)
 	tpr.sppIndexers = make(map[string]*ReqFilterIndexer)
 	tpr.attrIndexers = make(map[string]*ReqFilterIndexer)
+	tpr.txpIndexers = make(map[string]*ReqFilterIndexer)
 }
 
 func (tpr *TpReader) LoadDestinationsFiltered(tag string) (bool, error) {
@@ -274,9 +283,10 @@ func (tpr *TpReader) LoadRatingPlansFiltered(tag string) (bool, error) {
 	}
 
 	bindings := MapTPRatingPlanBindings(mpRpls)
+	currencies := MapTPRatingPlanCurrencies(mpRpls)
 
 	for tag, rplBnds := range bindings {
-		ratingPlan := &RatingPlan{Id: tag}
+		ratingPlan := &RatingPlan{Id: tag, Currency: currencies[tag]}
 		for _, rp := range rplBnds {
 			tptm, err := tpr.lr.GetTPTimings(tpr.tpid, rp.TimingId)
 			if err != nil || len(tptm) == 0 {
@@ -350,6 +360,7 @@ func (tpr *TpReader) LoadRatingPlans() (err error) {
 		return err
 	}
 	bindings := MapTPRatingPlanBindings(tps)
+	currencies := MapTPRatingPlanCurrencies(tps)
 	for tag, rplBnds := range bindings {
 		for _, rplBnd := range rplBnds {
 			t, exists := tpr.timings[rplBnd.TimingId]
@@ -363,7 +374,7 @@ func (tpr *TpReader) LoadRatingPlans() (err error) {
 			}
 			plan, exists := tpr.ratingPlans[tag]
 			if !exists {
-				plan = &RatingPlan{Id: tag}
+				plan = &RatingPlan{Id: tag, Currency: currencies[tag]}
 				tpr.ratingPlans[plan.Id] = plan
 			}
 			for _, dr := range drs.DestinationRates {
@@ -1149,6 +1160,13 @@ func (tpr *TpReader) LoadAccountActionsFiltered(qriedAA *utils.TPAccountActions)
 			}
 		}
 		ub.ActionTriggers = actionTriggers
+		if accountAction.FeeProfileIDs != "" {
+			fpIDs := strings.Split(accountAction.FeeProfileIDs, utils.INFIELD_SEP)
+			if err = tpr.checkFeeProfiles(accountAction.Tenant, fpIDs); err != nil {
+				return err
+			}
+			ub.addSubscriptions(fpIDs, time.Now())
+		}
 		// init counters
 		ub.InitCounters()
 		if err := tpr.dm.DataDB().SetAccount(ub); err != nil {
@@ -1186,6 +1204,13 @@ func (tpr *TpReader) LoadAccountActions() (err error) {
 			AllowNegative:  aa.AllowNegative,
 			Disabled:       aa.Disabled,
 		}
+		if aa.FeeProfileIDs != "" {
+			fpIDs := strings.Split(aa.FeeProfileIDs, utils.INFIELD_SEP)
+			if err = tpr.checkFeeProfiles(aa.Tenant, fpIDs); err != nil {
+				return err
+			}
+			ub.addSubscriptions(fpIDs, time.Now())
+		}
 		ub.InitCounters()
 		tpr.accountActions[aaKeyID] = ub
 		if aa.ActionPlanId != "" {
@@ -1860,6 +1885,94 @@ func (tpr *TpReader) LoadAttributeProfiles() error {
 	return tpr.LoadAttributeProfilesFiltered("")
 }
 
+func (tpr *TpReader) LoadExchangeRatesFiltered(fromCurrency, toCurrency string) (err error) {
+	xrs, err := tpr.lr.GetTPExchangeRates(tpr.tpid, fromCurrency, toCurrency)
+	if err != nil {
+		return err
+	}
+	mapXRs := make(map[string]*utils.TPExchangeRate)
+	for _, xr := range xrs {
+		mapXRs[utils.ConcatenatedKey(xr.FromCurrency, xr.ToCurrency)] = xr
+	}
+	tpr.exchangeRates = mapXRs
+	return nil
+}
+
+func (tpr *TpReader) LoadExchangeRates() error {
+	return tpr.LoadExchangeRatesFiltered("", "")
+}
+
+func (tpr *TpReader) LoadTaxProfilesFiltered(tag string) (err error) {
+	txps, err := tpr.lr.GetTPTaxProfiles(tpr.tpid, tag)
+	if err != nil {
+		return err
+	}
+	mapTxPrfls := make(map[utils.TenantID]*utils.TPTaxProfile)
+	for _, txp := range txps {
+		mapTxPrfls[utils.TenantID{Tenant: txp.Tenant, ID: txp.ID}] = txp
+	}
+	tpr.taxProfiles = mapTxPrfls
+	for tntID, txp := range mapTxPrfls {
+		// index tax profile for filters
+		if _, has := tpr.txpIndexers[tntID.Tenant]; !has {
+			tpr.txpIndexers[tntID.Tenant] = NewReqFilterIndexer(tpr.dm, utils.TaxProfilePrefix, tntID.Tenant)
+		}
+		if len(txp.FilterIDs) == 0 { // always checked
+			tpr.txpIndexers[tntID.Tenant].IndexTPFilter(&utils.TPFilterProfile{}, txp.ID)
+			continue
+		}
+		for _, fltrID := range txp.FilterIDs {
+			tpFltr, has := tpr.filters[utils.TenantID{Tenant: tntID.Tenant, ID: fltrID}]
+			if !has {
+				var fltr *Filter
+				if fltr, err = tpr.dm.GetFilter(tntID.Tenant, fltrID, false, utils.NonTransactional); err != nil {
+					if err == utils.ErrNotFound {
+						err = fmt.Errorf("broken reference to filter: %+v for tax profile: %+v", fltrID, txp)
+					}
+					return
+				}
+				tpFltr = FilterToTPFilter(fltr)
+			}
+			tpr.txpIndexers[tntID.Tenant].IndexTPFilter(tpFltr, txp.ID)
+		}
+	}
+	return nil
+}
+
+func (tpr *TpReader) LoadTaxProfiles() error {
+	return tpr.LoadTaxProfilesFiltered("")
+}
+
+func (tpr *TpReader) LoadFeeProfilesFiltered(tag string) (err error) {
+	feps, err := tpr.lr.GetTPFeeProfiles(tpr.tpid, tag)
+	if err != nil {
+		return err
+	}
+	mapFePrfls := make(map[utils.TenantID]*utils.TPFeeProfile)
+	for _, fep := range feps {
+		mapFePrfls[utils.TenantID{Tenant: fep.Tenant, ID: fep.ID}] = fep
+	}
+	tpr.feeProfiles = mapFePrfls
+	return nil
+}
+
+func (tpr *TpReader) LoadFeeProfiles() error {
+	return tpr.LoadFeeProfilesFiltered("")
+}
+
+// checkFeeProfiles makes sure the fee profiles referenced by account actions are defined in the TP or in the DataDB
+func (tpr *TpReader) checkFeeProfiles(tenant string, fpIDs []string) error {
+	for _, fpID := range fpIDs {
+		if _, has := tpr.feeProfiles[utils.TenantID{Tenant: tenant, ID: fpID}]; has {
+			continue
+		}
+		if _, err := tpr.dm.GetFeeProfile(tenant, fpID, false, utils.NonTransactional); err != nil {
+			return fmt.Errorf("could not get fee profile for tag %s", fpID)
+		}
+	}
+	return nil
+}
+
 func (tpr *TpReader) LoadAll() (err error) {
 	if err = tpr.LoadDestinations(); err != nil && err.Error() != utils.NotFoundCaps {
 		return
@@ -1894,6 +2007,9 @@ func (tpr *TpReader) LoadAll() (err error) {
 	if err = tpr.LoadActionTriggers(); err != nil && err.Error() != utils.NotFoundCaps {
 		return
 	}
+	if err = tpr.LoadFeeProfiles(); err != nil && err.Error() != utils.NotFoundCaps {
+		return
+	}
 	if err = tpr.LoadAccountActions(); err != nil && err.Error() != utils.NotFoundCaps {
 		return
 	}
@@ -1927,6 +2043,12 @@ func (tpr *TpReader) LoadAll() (err error) {
 	if err = tpr.LoadAttributeProfiles(); err != nil && err.Error() != utils.NotFoundCaps {
 		return
 	}
+	if err = tpr.LoadExchangeRates(); err != nil && err.Error() != utils.NotFoundCaps {
+		return
+	}
+	if err = tpr.LoadTaxProfiles(); err != nil && err.Error() != utils.NotFoundCaps {
+		return
+	}
 	return nil
 }
 
@@ -2097,6 +2219,13 @@ func (tpr *TpReader) WriteToDatabase(flush, verbose, disable_reverse bool) (err
 		log.Print("Account Actions:")
 	}
 	for _, ub := range tpr.accountActions {
+		if len(ub.Subscriptions) != 0 { // keep the charging state of the subscriptions already stored
+			if oldUb, err := tpr.dm.DataDB().GetAccount(ub.ID); err == nil {
+				for fpID, sub := range oldUb.Subscriptions {
+					ub.Subscriptions[fpID] = sub
+				}
+			}
+		}
 		err = tpr.dm.DataDB().SetAccount(ub)
 		if err != nil {
 			return err
@@ -2294,6 +2423,54 @@ func (tpr *TpReader) WriteToDatabase(flush, verbose, disable_reverse bool) (err
 		}
 	}
 
+	if verbose {
+		log.Print("ExchangeRates:")
+	}
+	for _, tpXR := range tpr.exchangeRates {
+		xr, err := APItoExchangeRate(tpXR, tpr.timezone)
+		if err != nil {
+			return err
+		}
+		if err = tpr.dm.SetExchangeRate(xr); err != nil {
+			return err
+		}
+		if verbose {
+			log.Print("\t", xr.ID())
+		}
+	}
+
+	if verbose {
+		log.Print("TaxProfiles:")
+	}
+	for _, tpTXP := range tpr.taxProfiles {
+		txp, err := APItoTaxProfile(tpTXP, tpr.timezone)
+		if err != nil {
+			return err
+		}
+		if err = tpr.dm.SetTaxProfile(txp); err != nil {
+			return err
+		}
+		if verbose {
+			log.Print("\t", txp.TenantID())
+		}
+	}
+
+	if verbose {
+		log.Print("FeeProfiles:")
+	}
+	for _, tpFEP := range tpr.feeProfiles {
+		fep, err := APItoFeeProfile(tpFEP)
+		if err != nil {
+			return err
+		}
+		if err = tpr.dm.SetFeeProfile(fep); err != nil {
+			return err
+		}
+		if verbose {
+			log.Print("\t", fep.TenantID())
+		}
+	}
+
 	if verbose {
 		log.Print("Timings:")
 	}
@@ -2405,6 +2582,18 @@ func (tpr *TpReader) WriteToDatabase(flush, verbose, disable_reverse bool) (err
 			log.Printf("Tenant:Context  %s, keys %+v", tntCntx, fltrIdxer.ChangedKeys(true).Slice())
 		}
 	}
+
+	if verbose {
+		log.Print("Indexing Tax Profiles")
+	}
+	for tenant, fltrIdxer := range tpr.txpIndexers {
+		if err := fltrIdxer.StoreIndexes(); err != nil {
+			return err
+		}
+		if verbose {
+			log.Printf("Tenant: %s, keys %+v", tenant, fltrIdxer.ChangedKeys(false).Slice())
+		}
+	}
 	return
 }
 
@@ -2477,6 +2666,12 @@ func (tpr *TpReader) ShowStatistics() {
 	log.Print("SupplierProfiles: ", len(tpr.sppProfiles))
 	// Attribute profiles
 	log.Print("AttributeProfiles: ", len(tpr.attributeProfiles))
+	// exchange rates
+	log.Print("ExchangeRates: ", len(tpr.exchangeRates))
+	// tax profiles
+	log.Print("TaxProfiles: ", len(tpr.taxProfiles))
+	// fee profiles
+	log.Print("FeeProfiles: ", len(tpr.feeProfiles))
 }
 
 // Returns the identities loaded for a specific category, useful for cache reloads
@@ -2650,6 +2845,30 @@ func (tpr *TpReader) GetLoadedIds(categ string) ([]string, error) {
 			i++
 		}
 		return keys, nil
+	case utils.ExchangeRatePrefix:
+		keys := make([]string, len(tpr.exchangeRates))
+		i := 0
+		for k := range tpr.exchangeRates {
+			keys[i] = k
+			i++
+		}
+		return keys, nil
+	case utils.TaxProfilePrefix:
+		keys := make([]string, len(tpr.taxProfiles))
+		i := 0
+		for k := range tpr.taxProfiles {
+			keys[i] = k.TenantID()
+			i++
+		}
+		return keys, nil
+	case utils.FeeProfilePrefix:
+		keys := make([]string, len(tpr.feeProfiles))
+		i := 0
+		for k := range tpr.feeProfiles {
+			keys[i] = k.TenantID()
+			i++
+		}
+		return keys, nil
 	}
 	return nil, errors.New("Unsupported load category")
 }
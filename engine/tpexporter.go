@@ -295,6 +295,36 @@ func (self *TPExporter) Run() error {
 		}
 	}
 
+	storDataExchangeRates, err := self.storDb.GetTPExchangeRates(self.tpID, "", "")
+	if err != nil && err.Error() != utils.ErrNotFound.Error() {
+		return err
+	}
+	for _, sd := range storDataExchangeRates {
+		sdModels := APItoModelTPExchangeRate(sd)
+		for _, sdModel := range sdModels {
+			toExportMap[utils.ExchangeRatesCsv] = append(toExportMap[utils.ExchangeRatesCsv], sdModel)
+		}
+	}
+
+	storDataTaxProfiles, err := self.storDb.GetTPTaxProfiles(self.tpID, "")
+	if err != nil && err.Error() != utils.ErrNotFound.Error() {
+		return err
+	}
+	for _, sd := range storDataTaxProfiles {
+		sdModels := APItoModelTPTaxProfile(sd)
+		for _, sdModel := range sdModels {
+			toExportMap[utils.TaxProfilesCsv] = append(toExportMap[utils.TaxProfilesCsv], sdModel)
+		}
+	}
+
+	storDataFeeProfiles, err := self.storDb.GetTPFeeProfiles(self.tpID, "")
+	if err != nil && err.Error() != utils.ErrNotFound.Error() {
+		return err
+	}
+	for _, sd := range storDataFeeProfiles {
+		toExportMap[utils.FeeProfilesCsv] = append(toExportMap[utils.FeeProfilesCsv], APItoModelTPFeeProfile(sd))
+	}
+
 	storDataUsers, err := self.storDb.GetTPUsers(&utils.TPUsers{TPid: self.tpID})
 	if err != nil && err.Error() != utils.ErrNotFound.Error() {
 		return err
@@ -63,6 +63,9 @@ var fileHandlers = map[string]func(*TPCSVImporter, string) error{
 	utils.FiltersCsv:            (*TPCSVImporter).importFilters,
 	utils.SuppliersCsv:          (*TPCSVImporter).importSuppliers,
 	utils.AttributesCsv:         (*TPCSVImporter).importAttributeProfiles,
+	utils.ExchangeRatesCsv:      (*TPCSVImporter).importExchangeRates,
+	utils.TaxProfilesCsv:        (*TPCSVImporter).importTaxProfiles,
+	utils.FeeProfilesCsv:        (*TPCSVImporter).importFeeProfiles,
 }
 
 func (self *TPCSVImporter) Run() error {
@@ -89,6 +92,9 @@ func (self *TPCSVImporter) Run() error {
 		path.Join(self.DirPath, utils.FiltersCsv),
 		path.Join(self.DirPath, utils.SuppliersCsv),
 		path.Join(self.DirPath, utils.AttributesCsv),
+		path.Join(self.DirPath, utils.ExchangeRatesCsv),
+		path.Join(self.DirPath, utils.TaxProfilesCsv),
+		path.Join(self.DirPath, utils.FeeProfilesCsv),
 	)
 	files, _ := ioutil.ReadDir(self.DirPath)
 	for _, f := range files {
@@ -423,3 +429,36 @@ func (self *TPCSVImporter) importAttributeProfiles(fn string) error {
 	}
 	return self.StorDb.SetTPAttributes(rls)
 }
+
+func (self *TPCSVImporter) importExchangeRates(fn string) error {
+	if self.Verbose {
+		log.Printf("Processing file: <%s> ", fn)
+	}
+	xrs, err := self.csvr.GetTPExchangeRates(self.TPid, "", "")
+	if err != nil {
+		return err
+	}
+	return self.StorDb.SetTPExchangeRates(xrs)
+}
+
+func (self *TPCSVImporter) importTaxProfiles(fn string) error {
+	if self.Verbose {
+		log.Printf("Processing file: <%s> ", fn)
+	}
+	txps, err := self.csvr.GetTPTaxProfiles(self.TPid, "")
+	if err != nil {
+		return err
+	}
+	return self.StorDb.SetTPTaxProfiles(txps)
+}
+
+func (self *TPCSVImporter) importFeeProfiles(fn string) error {
+	if self.Verbose {
+		log.Printf("Processing file: <%s> ", fn)
+	}
+	feps, err := self.csvr.GetTPFeeProfiles(self.TPid, "")
+	if err != nil {
+		return err
+	}
+	return self.StorDb.SetTPFeeProfiles(feps)
+}
@@ -73,12 +73,15 @@ func SetDBVersions(storage Storage) error {
 func (vers Versions) Compare(curent Versions, storType string) string {
 	var x map[string]string
 	m := map[string]string{
-		utils.Accounts:       "cgr-migrator -migrate=*accounts",
-		utils.Actions:        "cgr-migrator -migrate=*actions",
-		utils.ActionTriggers: "cgr-migrator -migrate=*action_triggers",
-		utils.ActionPlans:    "cgr-migrator -migrate=*action_plans",
-		utils.SharedGroups:   "cgr-migrator -migrate=*shared_groups",
-		utils.COST_DETAILS:   "cgr-migrator -migrate=*cost_details",
+		utils.Accounts:           "cgr-migrator -migrate=*accounts",
+		utils.Actions:            "cgr-migrator -migrate=*actions",
+		utils.ActionTriggers:     "cgr-migrator -migrate=*action_triggers",
+		utils.ActionPlans:        "cgr-migrator -migrate=*action_plans",
+		utils.SharedGroups:       "cgr-migrator -migrate=*shared_groups",
+		utils.COST_DETAILS:       "cgr-migrator -migrate=*cost_details",
+		utils.TpRatingPlans:      "cgr-migrator -migrate=*tp_rating_plans",
+		utils.TpDestinationRates: "cgr-migrator -migrate=*tp_destination_rates",
+		utils.TpAccountActionsV:  "cgr-migrator -migrate=*tp_account_actions",
 	}
 	data := map[string]string{
 		utils.Accounts:       "cgr-migrator -migrate=*accounts",
@@ -88,14 +91,17 @@ func (vers Versions) Compare(curent Versions, storType string) string {
 		utils.SharedGroups:   "cgr-migrator -migrate=*shared_groups",
 	}
 	stor := map[string]string{
-		utils.COST_DETAILS: "cgr-migrator -migrate=*cost_details",
+		utils.COST_DETAILS:       "cgr-migrator -migrate=*cost_details",
+		utils.TpRatingPlans:      "cgr-migrator -migrate=*tp_rating_plans",
+		utils.TpDestinationRates: "cgr-migrator -migrate=*tp_destination_rates",
+		utils.TpAccountActionsV:  "cgr-migrator -migrate=*tp_account_actions",
 	}
 	switch storType {
 	case utils.MONGO:
 		x = m
-	case utils.POSTGRES, utils.MYSQL:
+	case utils.POSTGRES, utils.MYSQL, utils.SQLITE:
 		x = stor
-	case utils.REDIS:
+	case utils.REDIS, utils.MetaEmbedded:
 		x = data
 	case utils.MAPSTOR:
 		x = m
@@ -138,12 +144,12 @@ func CurrentDataDBVersions() Versions {
 func CurrentStorDBVersions() Versions {
 	return Versions{
 		utils.COST_DETAILS:       2,
-		utils.TpRatingPlans:      1,
+		utils.TpRatingPlans:      2,
 		utils.TpLcrs:             1,
 		utils.TpFilters:          1,
-		utils.TpDestinationRates: 1,
+		utils.TpDestinationRates: 2,
 		utils.TpActionTriggers:   1,
-		utils.TpAccountActionsV:  1,
+		utils.TpAccountActionsV:  2,
 		utils.TpActionPlans:      1,
 		utils.TpActions:          1,
 		utils.TpDerivedCharges:   1,
@@ -162,7 +168,7 @@ func CurrentStorDBVersions() Versions {
 		utils.TpCdrStats:         1,
 		utils.TpDestinations:     1,
 		utils.TpLCR:              1,
-		utils.TpRatingPlan:       1,
+		utils.TpRatingPlan:       2,
 		utils.TpRatingProfile:    1,
 	}
 }
@@ -182,9 +188,9 @@ func CurrentDBVersions(storType string) Versions {
 	switch storType {
 	case utils.MONGO, utils.MAPSTOR:
 		return allVersions
-	case utils.POSTGRES, utils.MYSQL:
+	case utils.POSTGRES, utils.MYSQL, utils.SQLITE:
 		return storDbVersions
-	case utils.REDIS:
+	case utils.REDIS, utils.MetaEmbedded:
 		return dataDbVersions
 	}
 	return nil
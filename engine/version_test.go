@@ -47,3 +47,37 @@ func TestVersionCompare(t *testing.T) {
 		t.Errorf("Error failed to compare to curent version expected: %s received: %s", "cgr-migrator -migrate=*cost_details", message4)
 	}
 }
+
+func TestVersionCompareEmbedded(t *testing.T) {
+	x := Versions{utils.Accounts: 2, utils.Actions: 2, utils.ActionTriggers: 2, utils.ActionPlans: 2, utils.SharedGroups: 2, utils.COST_DETAILS: 2}
+	y := Versions{utils.Accounts: 1, utils.Actions: 2, utils.ActionTriggers: 2, utils.ActionPlans: 2, utils.SharedGroups: 2, utils.COST_DETAILS: 2}
+	c := Versions{utils.Accounts: 2, utils.Actions: 2, utils.ActionTriggers: 2, utils.ActionPlans: 2, utils.SharedGroups: 2, utils.COST_DETAILS: 1}
+	if message := y.Compare(x, utils.MetaEmbedded); message != "cgr-migrator -migrate=*accounts" {
+		t.Errorf("Error failed to compare to curent version expected: %s received: %s", "cgr-migrator -migrate=*accounts", message)
+	}
+	if message := c.Compare(x, utils.MetaEmbedded); message != "" { // cost details are kept in StorDB
+		t.Errorf("Expecting no migration for DataDB, received: %s", message)
+	}
+}
+
+func TestVersionCompareStorDB(t *testing.T) {
+	x := CurrentStorDBVersions()
+	y := CurrentStorDBVersions()
+	y[utils.TpRatingPlans] = 1
+	if message := y.Compare(x, utils.MYSQL); message != "cgr-migrator -migrate=*tp_rating_plans" {
+		t.Errorf("Expecting: %s, received: %s", "cgr-migrator -migrate=*tp_rating_plans", message)
+	}
+	z := CurrentStorDBVersions()
+	z[utils.TpDestinationRates] = 1
+	if message := z.Compare(x, utils.MONGO); message != "cgr-migrator -migrate=*tp_destination_rates" {
+		t.Errorf("Expecting: %s, received: %s", "cgr-migrator -migrate=*tp_destination_rates", message)
+	}
+	q := CurrentStorDBVersions()
+	q[utils.TpAccountActionsV] = 1
+	if message := q.Compare(x, utils.SQLITE); message != "cgr-migrator -migrate=*tp_account_actions" {
+		t.Errorf("Expecting: %s, received: %s", "cgr-migrator -migrate=*tp_account_actions", message)
+	}
+	if message := x.Compare(x, utils.POSTGRES); message != "" {
+		t.Errorf("Expecting no migration, received: %s", message)
+	}
+}
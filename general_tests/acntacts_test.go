@@ -62,7 +62,7 @@ ENABLE_ACNT,*enable_account,,,,,,,,,,,,,,false,false,10`
 	csvr := engine.NewTpReader(dbAcntActs.DataDB(), engine.NewStringCSVStorage(',', destinations, timings,
 		rates, destinationRates, ratingPlans, ratingProfiles, sharedGroups, lcrs,
 		actions, actionPlans, actionTriggers, accountActions, derivedCharges, cdrStats,
-		users, aliases, resLimits, stats, thresholds, filters, suppliers, aliasProfiles), "", "")
+		users, aliases, resLimits, stats, thresholds, filters, suppliers, aliasProfiles, "", "", ""), "", "")
 	if err := csvr.LoadAll(); err != nil {
 		t.Fatal(err)
 	}
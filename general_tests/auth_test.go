@@ -68,7 +68,7 @@ RP_ANY,DR_ANY_1CNT,*any,10`
 	aliasProfiles := ``
 	csvr := engine.NewTpReader(dbAuth.DataDB(), engine.NewStringCSVStorage(',', destinations, timings, rates, destinationRates,
 		ratingPlans, ratingProfiles, sharedGroups, lcrs, actions, actionPlans, actionTriggers, accountActions,
-		derivedCharges, cdrStats, users, aliases, resLimits, stats, thresholds, filters, suppliers, aliasProfiles), "", "")
+		derivedCharges, cdrStats, users, aliases, resLimits, stats, thresholds, filters, suppliers, aliasProfiles, "", "", ""), "", "")
 	if err := csvr.LoadAll(); err != nil {
 		t.Fatal(err)
 	}
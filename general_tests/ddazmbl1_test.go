@@ -71,7 +71,7 @@ TOPUP10_AT,TOPUP10_AC1,ASAP,10`
 			destinationRates, ratingPlans, ratingProfiles,
 			sharedGroups, lcrs, actions, actionPlans, actionTriggers, accountActions,
 			derivedCharges, cdrStats, users, aliases, resLimits, stats,
-			thresholds, filters, suppliers, aliasProfiles), "", "")
+			thresholds, filters, suppliers, aliasProfiles, "", "", ""), "", "")
 	if err := csvr.LoadDestinations(); err != nil {
 		t.Fatal(err)
 	}
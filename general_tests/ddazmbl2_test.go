@@ -69,7 +69,7 @@ TOPUP10_AT,TOPUP10_AC1,ASAP,10`
 	csvr := engine.NewTpReader(dataDB2.DataDB(), engine.NewStringCSVStorage(',', destinations, timings,
 		rates, destinationRates, ratingPlans, ratingProfiles, sharedGroups, lcrs, actions, actionPlans,
 		actionTriggers, accountActions, derivedCharges, cdrStats, users, aliases, resLimits,
-		stats, thresholds, filters, suppliers, aliasProfiles), "", "")
+		stats, thresholds, filters, suppliers, aliasProfiles, "", "", ""), "", "")
 	if err := csvr.LoadDestinations(); err != nil {
 		t.Fatal(err)
 	}
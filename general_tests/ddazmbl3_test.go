@@ -67,7 +67,7 @@ RP_UK,DR_UK_Mobile_BIG5,ALWAYS,10`
 	csvr := engine.NewTpReader(dataDB3.DataDB(), engine.NewStringCSVStorage(',', destinations, timings, rates,
 		destinationRates, ratingPlans, ratingProfiles, sharedGroups, lcrs, actions, actionPlans, actionTriggers,
 		accountActions, derivedCharges, cdrStats, users, aliases, resLimits, stats,
-		thresholds, filters, suppliers, aliasProfiles), "", "")
+		thresholds, filters, suppliers, aliasProfiles, "", "", ""), "", "")
 	if err := csvr.LoadDestinations(); err != nil {
 		t.Fatal(err)
 	}
@@ -71,6 +71,25 @@ func (m *Migrator) migrateTPaccountacction() (err error) {
 			return err
 		}
 		return
+	case 1: // FeeProfileIDs column added
+		if err = m.addTPColumn(utils.TBLTPAccountActions, "fee_profile_ids", "VARCHAR(255)"); err != nil {
+			return
+		}
+		if m.dryRun {
+			return
+		}
+		if !m.sameStorDB {
+			if err = m.migrateCurrentTPaccountAcction(); err != nil {
+				return
+			}
+		}
+		vrs = engine.Versions{utils.TpAccountActionsV: current[utils.TpAccountActionsV]}
+		if err = m.storDB.SetVersions(vrs, false); err != nil {
+			return utils.NewCGRError(utils.Migrator,
+				utils.ServerErrorCaps,
+				err.Error(),
+				fmt.Sprintf("error: <%s> when updating TpAccountActions version into StorDB", err.Error()))
+		}
 	}
 	return
 }
@@ -77,6 +77,25 @@ func (m *Migrator) migrateTPdestinationrates() (err error) {
 			return err
 		}
 		return
+	case 1: // VolumeCounter column added
+		if err = m.addTPColumn(utils.TBLTPDestinationRates, "volume_counter", "VARCHAR(64)"); err != nil {
+			return
+		}
+		if m.dryRun {
+			return
+		}
+		if !m.sameStorDB {
+			if err = m.migrateCurrentTPdestinationrates(); err != nil {
+				return
+			}
+		}
+		vrs = engine.Versions{utils.TpDestinationRates: current[utils.TpDestinationRates]}
+		if err = m.storDB.SetVersions(vrs, false); err != nil {
+			return utils.NewCGRError(utils.Migrator,
+				utils.ServerErrorCaps,
+				err.Error(),
+				fmt.Sprintf("error: <%s> when updating TpDestinationRates version into StorDB", err.Error()))
+		}
 	}
 	return
 }
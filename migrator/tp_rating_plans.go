@@ -79,6 +79,26 @@ func (m *Migrator) migrateTPratingplans() (err error) {
 			return err
 		}
 		return
+	case 1: // Currency column added
+		if err = m.addTPColumn(utils.TBLTPRatingPlans, "currency", "VARCHAR(3)"); err != nil {
+			return
+		}
+		if m.dryRun {
+			return
+		}
+		if !m.sameStorDB {
+			if err = m.migrateCurrentTPratingplans(); err != nil {
+				return
+			}
+		}
+		vrs = engine.Versions{utils.TpRatingPlans: current[utils.TpRatingPlans],
+			utils.TpRatingPlan: current[utils.TpRatingPlan]}
+		if err = m.storDB.SetVersions(vrs, false); err != nil {
+			return utils.NewCGRError(utils.Migrator,
+				utils.ServerErrorCaps,
+				err.Error(),
+				fmt.Sprintf("error: <%s> when updating TpRatingPlans version into StorDB", err.Error()))
+		}
 	}
 	return
 }
@@ -0,0 +1,210 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package migrator
+
+import (
+	"github.com/cgrates/cgrates/engine"
+	"github.com/cgrates/cgrates/utils"
+)
+
+// v1Embedded gives access to the older data formats stored in an *embedded DataDB
+type v1Embedded struct {
+	es       *engine.EmbeddedStorage
+	ms       engine.Marshaler
+	dataKeys []string
+	qryIdx   *int
+}
+
+func newv1EmbeddedStorage(dbPath, mrshlerStr string) (*v1Embedded, error) {
+	es, err := engine.NewEmbeddedStorage(dbPath, mrshlerStr)
+	if err != nil {
+		return nil, err
+	}
+	return &v1Embedded{es: es, ms: es.Marshaler()}, nil
+}
+
+func (v1es *v1Embedded) Close() {
+	v1es.es.Close()
+}
+
+func (v1es *v1Embedded) getKeysForPrefix(prefix string) ([]string, error) {
+	return v1es.es.GetKeysForPrefix(prefix)
+}
+
+// nextValue iterates over the values stored with prefix, returning utils.ErrNoMoreData at the end
+func (v1es *v1Embedded) nextValue(prefix string) (key string, value []byte, err error) {
+	if v1es.qryIdx == nil {
+		if v1es.dataKeys, err = v1es.getKeysForPrefix(prefix); err != nil {
+			return
+		} else if len(v1es.dataKeys) == 0 {
+			return "", nil, utils.ErrNotFound
+		}
+		v1es.qryIdx = utils.IntPointer(0)
+	}
+	if *v1es.qryIdx > len(v1es.dataKeys)-1 {
+		v1es.qryIdx = nil
+		return "", nil, utils.ErrNoMoreData
+	}
+	key = v1es.dataKeys[*v1es.qryIdx]
+	if value, err = v1es.es.GetRawValue(key); err != nil {
+		return
+	}
+	*v1es.qryIdx = *v1es.qryIdx + 1
+	return
+}
+
+// setValue marshals and stores the item on key
+func (v1es *v1Embedded) setValue(key string, itm interface{}) (err error) {
+	bit, err := v1es.ms.Marshal(itm)
+	if err != nil {
+		return err
+	}
+	return v1es.es.SetRawValue(key, bit)
+}
+
+// Account methods
+// V1
+func (v1es *v1Embedded) getv1Account() (v1Acnt *v1Account, err error) {
+	key, value, err := v1es.nextValue(v1AccountDBPrefix)
+	if err != nil {
+		return nil, err
+	}
+	v1Acnt = &v1Account{Id: key}
+	if err = v1es.ms.Unmarshal(value, v1Acnt); err != nil {
+		return nil, err
+	}
+	return
+}
+
+func (v1es *v1Embedded) setV1Account(x *v1Account) (err error) {
+	return v1es.setValue(v1AccountDBPrefix+x.Id, x)
+}
+
+// V2
+func (v1es *v1Embedded) getv2Account() (v2Acnt *v2Account, err error) {
+	key, value, err := v1es.nextValue(utils.ACCOUNT_PREFIX)
+	if err != nil {
+		return nil, err
+	}
+	v2Acnt = &v2Account{ID: key}
+	if err = v1es.ms.Unmarshal(value, v2Acnt); err != nil {
+		return nil, err
+	}
+	return
+}
+
+func (v1es *v1Embedded) setV2Account(x *v2Account) (err error) {
+	return v1es.setValue(utils.ACCOUNT_PREFIX+x.ID, x)
+}
+
+// ActionPlans methods
+func (v1es *v1Embedded) getV1ActionPlans() (v1aps *v1ActionPlans, err error) {
+	_, value, err := v1es.nextValue(utils.ACTION_PLAN_PREFIX)
+	if err != nil {
+		return nil, err
+	}
+	if err = v1es.ms.Unmarshal(value, &v1aps); err != nil {
+		return nil, err
+	}
+	return
+}
+
+func (v1es *v1Embedded) setV1ActionPlans(x *v1ActionPlans) (err error) {
+	return v1es.setValue(utils.ACTION_PLAN_PREFIX+(*x)[0].Id, x)
+}
+
+// Actions methods
+func (v1es *v1Embedded) getV1Actions() (v1acs *v1Actions, err error) {
+	_, value, err := v1es.nextValue(utils.ACTION_PREFIX)
+	if err != nil {
+		return nil, err
+	}
+	if err = v1es.ms.Unmarshal(value, &v1acs); err != nil {
+		return nil, err
+	}
+	return
+}
+
+func (v1es *v1Embedded) setV1Actions(x *v1Actions) (err error) {
+	return v1es.setValue(utils.ACTION_PREFIX+(*x)[0].Id, x)
+}
+
+// ActionTriggers methods
+func (v1es *v1Embedded) getV1ActionTriggers() (v1acts *v1ActionTriggers, err error) {
+	_, value, err := v1es.nextValue(utils.ACTION_TRIGGER_PREFIX)
+	if err != nil {
+		return nil, err
+	}
+	if err = v1es.ms.Unmarshal(value, &v1acts); err != nil {
+		return nil, err
+	}
+	return
+}
+
+func (v1es *v1Embedded) setV1ActionTriggers(x *v1ActionTriggers) (err error) {
+	return v1es.setValue(utils.ACTION_TRIGGER_PREFIX+(*x)[0].Id, x)
+}
+
+// SharedGroup methods
+func (v1es *v1Embedded) getV1SharedGroup() (v1sg *v1SharedGroup, err error) {
+	_, value, err := v1es.nextValue(utils.SHARED_GROUP_PREFIX)
+	if err != nil {
+		return nil, err
+	}
+	if err = v1es.ms.Unmarshal(value, &v1sg); err != nil {
+		return nil, err
+	}
+	return
+}
+
+func (v1es *v1Embedded) setV1SharedGroup(x *v1SharedGroup) (err error) {
+	return v1es.setValue(utils.SHARED_GROUP_PREFIX+x.Id, x)
+}
+
+// Stats methods
+func (v1es *v1Embedded) getV1Stats() (v1st *v1Stat, err error) {
+	_, value, err := v1es.nextValue(utils.CDR_STATS_PREFIX)
+	if err != nil {
+		return nil, err
+	}
+	if err = v1es.ms.Unmarshal(value, &v1st); err != nil {
+		return nil, err
+	}
+	return
+}
+
+func (v1es *v1Embedded) setV1Stats(x *v1Stat) (err error) {
+	return v1es.setValue(utils.CDR_STATS_PREFIX+x.Id, x)
+}
+
+// Thresholds methods
+func (v1es *v1Embedded) getV2ActionTrigger() (v2at *v2ActionTrigger, err error) {
+	_, value, err := v1es.nextValue(utils.ACTION_TRIGGER_PREFIX)
+	if err != nil {
+		return nil, err
+	}
+	if err = v1es.ms.Unmarshal(value, &v2at); err != nil {
+		return nil, err
+	}
+	return
+}
+
+func (v1es *v1Embedded) setV2ActionTrigger(x *v2ActionTrigger) (err error) {
+	return v1es.setValue(utils.ACTION_TRIGGER_PREFIX+x.ID, x)
+}
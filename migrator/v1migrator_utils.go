@@ -44,9 +44,11 @@ func ConfigureV1DataStorage(db_type, host, port, name, user, pass, marshaler str
 	case utils.MONGO:
 		d, err = newv1MongoStorage(host, port, name, user, pass, utils.DataDB, nil)
 		db = d.(MigratorDataDB)
+	case utils.MetaEmbedded:
+		d, err = newv1EmbeddedStorage(name, marshaler)
 	default:
-		err = errors.New(fmt.Sprintf("Unknown db '%s' valid options are '%s' or '%s'",
-			db_type, utils.REDIS, utils.MONGO))
+		err = errors.New(fmt.Sprintf("Unknown db '%s' valid options are [%s, %s, %s]",
+			db_type, utils.REDIS, utils.MONGO, utils.MetaEmbedded))
 	}
 	if err != nil {
 		return nil, err
@@ -61,3 +63,24 @@ func (m *Migrator) InStorDB() engine.LoadStorage {
 func (m *Migrator) OutStorDB() engine.LoadStorage {
 	return m.storDB.(engine.LoadStorage)
 }
+
+// addTPColumn adds to a SQL StorDB table the column introduced by a newer TariffPlan version,
+// the other StorDBs decode the missing field as empty so they need no change
+func (m *Migrator) addTPColumn(table, column, colType string) (err error) {
+	switch m.storDBType {
+	case utils.MYSQL, utils.POSTGRES, utils.SQLITE:
+	default:
+		return
+	}
+	if m.dryRun {
+		return
+	}
+	if _, err = m.storDB.(*engine.SQLStorage).Db.Exec(
+		fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s NOT NULL DEFAULT ''", table, column, colType)); err != nil {
+		return utils.NewCGRError(utils.Migrator,
+			utils.ServerErrorCaps,
+			err.Error(),
+			fmt.Sprintf("error: <%s> when adding column <%s> to table <%s>", err.Error(), column, table))
+	}
+	return
+}
@@ -253,6 +253,14 @@ func (s *Scheduler) GetScheduledActions(fltr ArgsGetScheduledActions) (schedActi
 	return
 }
 
+// QueueLength returns the number of ActionTimings waiting to be executed
+func (s *Scheduler) QueueLength() (qLen int) {
+	s.RLock()
+	qLen = len(s.queue)
+	s.RUnlock()
+	return
+}
+
 func (s *Scheduler) Shutdown() {
 	s.schedulerStarted = false // disable loop on next run
 	s.restartLoop <- true      // cancel waiting tasks
@@ -0,0 +1,190 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package servmanager
+
+import (
+	"encoding/json"
+	"net"
+	"net/http"
+
+	"github.com/cgrates/cgrates/config"
+	"github.com/cgrates/cgrates/engine"
+	"github.com/cgrates/cgrates/utils"
+	"github.com/cgrates/rpcclient"
+)
+
+// ServiceHealth reports the state of one engine service
+type ServiceHealth struct {
+	Started bool
+	Conns   map[string]bool // connections reachable, indexed on <service>:<address>
+}
+
+// EngineHealth is the health report of the engine
+type EngineHealth struct {
+	Ready      bool // all services started, cache loaded and databases reachable
+	CacheReady bool
+	DataDB     bool // DataDB reachable, true if not used
+	StorDB     bool // StorDB reachable, true if not used
+	Services   map[string]*ServiceHealth
+}
+
+// RegisterService makes the service part of the health reports
+// the service is considered started once intConnChan is populated
+// conns are the connections the service needs, indexed on the service they reach
+func (srvMngr *ServiceManager) RegisterService(serviceID string,
+	intConnChan chan rpcclient.RpcClientConnection, conns map[string][]*config.HaPoolConfig) {
+	srvMngr.Lock()
+	if srvMngr.rpcChans == nil {
+		srvMngr.rpcChans = make(map[string]chan rpcclient.RpcClientConnection)
+	}
+	if srvMngr.svcConns == nil {
+		srvMngr.svcConns = make(map[string]map[string][]*config.HaPoolConfig)
+	}
+	srvMngr.rpcChans[serviceID] = intConnChan
+	srvMngr.svcConns[serviceID] = conns
+	srvMngr.Unlock()
+}
+
+// SetServiceStarted marks as started the services without internal connection channel
+func (srvMngr *ServiceManager) SetServiceStarted(serviceID string, intConn rpcclient.RpcClientConnection) {
+	srvMngr.Lock()
+	if srvMngr.rpcServices == nil {
+		srvMngr.rpcServices = make(map[string]rpcclient.RpcClientConnection)
+	}
+	srvMngr.rpcServices[serviceID] = intConn
+	srvMngr.Unlock()
+}
+
+// serviceStarted checks without blocking if the service has started
+func (srvMngr *ServiceManager) serviceStarted(serviceID string) bool {
+	if serviceID == utils.SchedulerS {
+		return srvMngr.GetScheduler() != nil
+	}
+	srvMngr.RLock()
+	_, started := srvMngr.rpcServices[serviceID]
+	intConnChan := srvMngr.rpcChans[serviceID]
+	srvMngr.RUnlock()
+	if started || intConnChan == nil {
+		return started
+	}
+	select {
+	case intConn := <-intConnChan:
+		intConnChan <- intConn
+		srvMngr.SetServiceStarted(serviceID, intConn) // services do not go back, no need to query the channel again
+		return true
+	default:
+		return false
+	}
+}
+
+// connsHealth checks the connections of a service, remote ones only if checkRemote
+// internal connections are up as soon as the service they reach has started
+func (srvMngr *ServiceManager) connsHealth(serviceID string, checkRemote bool) (conns map[string]bool) {
+	srvMngr.RLock()
+	svcConns := srvMngr.svcConns[serviceID]
+	srvMngr.RUnlock()
+	conns = make(map[string]bool)
+	for connSvc, connCfgs := range svcConns {
+		for _, connCfg := range connCfgs {
+			if connCfg.Address == utils.MetaInternal {
+				conns[utils.ConcatenatedKey(connSvc, connCfg.Address)] = srvMngr.serviceStarted(connSvc)
+				continue
+			}
+			if !checkRemote {
+				continue
+			}
+			conn, err := net.DialTimeout("tcp", connCfg.Address, srvMngr.cfg.ConnectTimeout)
+			if err == nil {
+				conn.Close()
+			}
+			conns[utils.ConcatenatedKey(connSvc, connCfg.Address)] = err == nil
+		}
+	}
+	return
+}
+
+// storageReachable does a cheap query on the storage
+func storageReachable(storage engine.Storage) bool {
+	_, err := storage.GetVersions(utils.TBLVersions)
+	return err == nil || err == utils.ErrNotFound
+}
+
+// Health builds the health report, remote connections are dialed only if checkRemote
+func (srvMngr *ServiceManager) Health(checkRemote bool) (eh *EngineHealth) {
+	eh = &EngineHealth{
+		CacheReady: srvMngr.CacheReady(),
+		DataDB:     srvMngr.dm == nil || storageReachable(srvMngr.dm.DataDB()),
+		StorDB:     srvMngr.storDB == nil || storageReachable(srvMngr.storDB),
+		Services:   make(map[string]*ServiceHealth),
+	}
+	eh.Ready = eh.CacheReady && eh.DataDB && eh.StorDB
+	srvMngr.RLock()
+	svcIDs := make([]string, 0, len(srvMngr.rpcChans)+1)
+	for svcID := range srvMngr.rpcChans {
+		svcIDs = append(svcIDs, svcID)
+	}
+	srvMngr.RUnlock()
+	if srvMngr.cfg.SchedulerEnabled {
+		svcIDs = append(svcIDs, utils.SchedulerS)
+	}
+	for _, svcID := range svcIDs {
+		sh := &ServiceHealth{Started: srvMngr.serviceStarted(svcID),
+			Conns: srvMngr.connsHealth(svcID, checkRemote)}
+		eh.Services[svcID] = sh
+		if !sh.Started {
+			eh.Ready = false
+		}
+		for _, up := range sh.Conns {
+			if !up {
+				eh.Ready = false
+			}
+		}
+	}
+	return
+}
+
+// V1Status returns the health of the engine, including the remote connections
+func (srvMngr *ServiceManager) V1Status(ignore string, reply *EngineHealth) error {
+	*reply = *srvMngr.Health(true)
+	return nil
+}
+
+// LivenessHandler answers as long as the engine is able to serve HTTP
+func (srvMngr *ServiceManager) LivenessHandler(w http.ResponseWriter, r *http.Request) {
+	w.Write([]byte(utils.OK))
+}
+
+// ReadinessHandler answers with 503 until the engine is ready, remote connections are not dialed
+func (srvMngr *ServiceManager) ReadinessHandler(w http.ResponseWriter, r *http.Request) {
+	if !srvMngr.Health(false).Ready {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+	w.Write([]byte(utils.OK))
+}
+
+// HealthHandler writes the complete health report as JSON, with 503 if the engine is not ready
+func (srvMngr *ServiceManager) HealthHandler(w http.ResponseWriter, r *http.Request) {
+	eh := srvMngr.Health(true)
+	w.Header().Set("Content-Type", "application/json")
+	if !eh.Ready {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	json.NewEncoder(w).Encode(eh)
+}
@@ -0,0 +1,83 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package servmanager
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/cgrates/cgrates/config"
+	"github.com/cgrates/cgrates/engine"
+	"github.com/cgrates/cgrates/utils"
+	"github.com/cgrates/rpcclient"
+)
+
+func TestServiceManagerHealth(t *testing.T) {
+	cfg, _ := config.NewDefaultCGRConfig()
+	cfg.RALsEnabled = true // cache loaded by RALs
+	dataDB, _ := engine.NewMapStorage()
+	cacheDoneChan := make(chan struct{}, 1)
+	srvMngr := NewServiceManager(cfg, engine.NewDataManager(dataDB), nil, nil, cacheDoneChan)
+	statSChan := make(chan rpcclient.RpcClientConnection, 1)
+	thdSChan := make(chan rpcclient.RpcClientConnection, 1)
+	srvMngr.RegisterService(utils.StatService, statSChan, map[string][]*config.HaPoolConfig{
+		utils.ThresholdS: []*config.HaPoolConfig{&config.HaPoolConfig{Address: utils.MetaInternal}}})
+	srvMngr.RegisterService(utils.ThresholdS, thdSChan, nil)
+	srvMngr.RegisterService(utils.DiameterAgent, nil, nil)
+	if eh := srvMngr.Health(false); eh.Ready || eh.CacheReady || !eh.DataDB || !eh.StorDB {
+		t.Errorf("received: %s", utils.ToJSON(eh))
+	} else if eh.Services[utils.StatService].Started ||
+		eh.Services[utils.StatService].Conns["ThresholdS:*internal"] {
+		t.Errorf("received: %s", utils.ToJSON(eh.Services))
+	}
+	rec := httptest.NewRecorder()
+	srvMngr.ReadinessHandler(rec, httptest.NewRequest("GET", "/health/ready", nil))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("received code: %d", rec.Code)
+	}
+	cacheDoneChan <- struct{}{}
+	statSChan <- nil
+	thdSChan <- nil
+	srvMngr.SetServiceStarted(utils.DiameterAgent, nil)
+	if eh := srvMngr.Health(false); !eh.Ready {
+		t.Errorf("received: %s", utils.ToJSON(eh))
+	} else if !eh.Services[utils.StatService].Conns["ThresholdS:*internal"] {
+		t.Errorf("received: %s", utils.ToJSON(eh.Services))
+	}
+	rec = httptest.NewRecorder()
+	srvMngr.ReadinessHandler(rec, httptest.NewRequest("GET", "/health/ready", nil))
+	if rec.Code != http.StatusOK {
+		t.Errorf("received code: %d", rec.Code)
+	}
+}
+
+func TestServiceManagerHealthWithoutRALs(t *testing.T) {
+	cfg, _ := config.NewDefaultCGRConfig()
+	cfg.RALsEnabled = false
+	srvMngr := NewServiceManager(cfg, nil, nil, nil, make(chan struct{}, 1))
+	if eh := srvMngr.Health(false); !eh.Ready || !eh.CacheReady || !eh.DataDB || !eh.StorDB {
+		t.Errorf("received: %s", utils.ToJSON(eh))
+	}
+	rec := httptest.NewRecorder()
+	srvMngr.ReadinessHandler(rec, httptest.NewRequest("GET", "/health/ready", nil))
+	if rec.Code != http.StatusOK {
+		t.Errorf("received code: %d", rec.Code)
+	}
+}
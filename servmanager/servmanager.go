@@ -20,10 +20,12 @@ package servmanager
 
 import (
 	"errors"
+	"fmt"
 	"reflect"
 	"strings"
 	"sync"
 
+	"github.com/cgrates/cgrates/cache"
 	"github.com/cgrates/cgrates/config"
 	"github.com/cgrates/cgrates/engine"
 	"github.com/cgrates/cgrates/scheduler"
@@ -31,8 +33,10 @@ import (
 	"github.com/cgrates/rpcclient"
 )
 
-func NewServiceManager(cfg *config.CGRConfig, dm *engine.DataManager, engineShutdown chan bool, cacheDoneChan chan struct{}) *ServiceManager {
-	return &ServiceManager{cfg: cfg, dm: dm, engineShutdown: engineShutdown, cacheDoneChan: cacheDoneChan}
+func NewServiceManager(cfg *config.CGRConfig, dm *engine.DataManager, storDB engine.Storage,
+	engineShutdown chan bool, cacheDoneChan chan struct{}) *ServiceManager {
+	return &ServiceManager{cfg: cfg, dm: dm, storDB: storDB,
+		engineShutdown: engineShutdown, cacheDoneChan: cacheDoneChan}
 }
 
 // ServiceManager handles service management ran by the engine
@@ -40,11 +44,44 @@ type ServiceManager struct {
 	sync.RWMutex   // lock access to any shared data
 	cfg            *config.CGRConfig
 	dm             *engine.DataManager
+	storDB         engine.Storage // nil if StorDB is not used
 	engineShutdown chan bool
 	cacheDoneChan  chan struct{} // Wait for cache to load
 	sched          *scheduler.Scheduler
 	rpcChans       map[string]chan rpcclient.RpcClientConnection // services expected to start
 	rpcServices    map[string]rpcclient.RpcClientConnection      // services started
+	svcConns       map[string]map[string][]*config.HaPoolConfig  // connections needed by services, indexed on the service they reach
+	cfgReloaders   map[string][]ConfigReloader                   // services to notify on config reloads, indexed on config section
+}
+
+// ConfigReloader is implemented by the services able to apply configuration changes while running
+type ConfigReloader interface {
+	ReloadConfig(cfg *config.CGRConfig) error
+}
+
+// RegisterConfigReloader will notify rld when the config section is reloaded
+func (srvMngr *ServiceManager) RegisterConfigReloader(section string, rld ConfigReloader) {
+	srvMngr.Lock()
+	if srvMngr.cfgReloaders == nil {
+		srvMngr.cfgReloaders = make(map[string][]ConfigReloader)
+	}
+	srvMngr.cfgReloaders[section] = append(srvMngr.cfgReloaders[section], rld)
+	srvMngr.Unlock()
+}
+
+// CacheReady checks, without blocking, if the cache was loaded from DataDB
+// the cache is loaded by RALs so without it there is nothing to wait for
+func (srvMngr *ServiceManager) CacheReady() bool {
+	if !srvMngr.cfg.RALsEnabled {
+		return true
+	}
+	select {
+	case cacheDone := <-srvMngr.cacheDoneChan:
+		srvMngr.cacheDoneChan <- cacheDone
+		return true
+	default:
+		return false
+	}
 }
 
 func (srvMngr *ServiceManager) StartScheduler(waitCache bool) error {
@@ -103,6 +140,21 @@ func (srvMngr *ServiceManager) GetScheduler() *scheduler.Scheduler {
 	return srvMngr.sched
 }
 
+// PromMetrics exports the state of the managed scheduler
+func (srvMngr *ServiceManager) PromMetrics() []*utils.PromMetric {
+	var running, qLen float64
+	if sched := srvMngr.GetScheduler(); sched != nil {
+		running = 1
+		qLen = float64(sched.QueueLength())
+	}
+	return []*utils.PromMetric{
+		utils.NewPromGauge(utils.PromNamespace+"_scheduler_running",
+			"Set to 1 if the scheduler is running", running),
+		utils.NewPromGauge(utils.PromNamespace+"_scheduler_queue_length",
+			"ActionTimings queued for execution in the scheduler", qLen),
+	}
+}
+
 func (srvMngr *ServiceManager) Call(serviceMethod string, args interface{}, reply interface{}) error {
 	parts := strings.Split(serviceMethod, ".")
 	if len(parts) != 2 {
@@ -182,3 +234,56 @@ func (srvMngr *ServiceManager) V1ServiceStatus(args ArgStartService, reply *stri
 	}
 	return nil
 }
+
+// ArgReloadConfig is passed to V1ReloadConfig
+type ArgReloadConfig struct {
+	ConfigDir string // defaults to the folder the engine was started with
+}
+
+// ReloadConfigReply reports the outcome of a configuration reload
+type ReloadConfigReply struct {
+	Reloaded        []string // sections applied on the running engine
+	RestartRequired []string // sections changed but applied only after an engine restart
+}
+
+// V1ReloadConfig re-reads the configuration folder and applies the changed sections to the running services
+func (srvMngr *ServiceManager) V1ReloadConfig(args ArgReloadConfig, reply *ReloadConfigReply) (err error) {
+	if args.ConfigDir == "" {
+		args.ConfigDir = srvMngr.cfg.ConfigPath
+	}
+	if args.ConfigDir == "" {
+		args.ConfigDir = utils.CONFIG_DIR
+	}
+	newCfg, err := config.NewCGRConfigFromFolder(args.ConfigDir) // also checks the config sanity
+	if err != nil {
+		return err
+	}
+	srvMngr.Lock()
+	defer srvMngr.Unlock()
+	reloaded, restartRequired, err := srvMngr.cfg.ReloadConfig(newCfg)
+	if err != nil {
+		return err
+	}
+	for _, section := range reloaded {
+		if err = srvMngr.reloadSection(section); err != nil {
+			return
+		}
+	}
+	utils.Logger.Info(fmt.Sprintf("<%s> config reloaded from <%s>, reloaded sections: %v, restart required for: %v",
+		utils.ServiceManager, args.ConfigDir, reloaded, restartRequired))
+	*reply = ReloadConfigReply{Reloaded: reloaded, RestartRequired: restartRequired}
+	return
+}
+
+// reloadSection propagates a reloaded config section to the running services, srvMngr should be locked
+func (srvMngr *ServiceManager) reloadSection(section string) error {
+	if section == config.CACHE_JSN {
+		cache.ReloadCacheConfig(srvMngr.cfg.CacheCfg())
+	}
+	for _, rld := range srvMngr.cfgReloaders[section] {
+		if err := rld.ReloadConfig(srvMngr.cfg); err != nil {
+			return err
+		}
+	}
+	return nil
+}
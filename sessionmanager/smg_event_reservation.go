@@ -0,0 +1,224 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package sessionmanager
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/cgrates/cgrates/engine"
+	"github.com/cgrates/cgrates/guardian"
+	"github.com/cgrates/cgrates/utils"
+)
+
+// smgEventReservation holds the debits done for a one time event (eg: SMS) until they are committed or refunded
+type smgEventReservation struct {
+	event       SMGenericEvent
+	sessionRuns []*engine.SessionRun
+	maxUsage    time.Duration // usage reserved, number of message parts for *sms
+	timer       *time.Timer   // refunds the reservation if not confirmed in time
+}
+
+// eventReservationKey returns the message ID the reservation is indexed on
+func eventReservationKey(gev SMGenericEvent) (string, error) {
+	msgID := gev.GetOriginID(utils.META_DEFAULT)
+	if msgID == "" {
+		return "", utils.NewErrMandatoryIeMissing(utils.ACCID)
+	}
+	return msgID, nil
+}
+
+// recordEventReservation stores the reservation and schedules its expiry
+func (smg *SMGeneric) recordEventReservation(msgID string, rsrv *smgEventReservation) {
+	smg.eRsrvsMux.Lock()
+	defer smg.eRsrvsMux.Unlock()
+	if ttl := smg.cgrCfg.SmGenericConfig.EventReservationTTL; ttl != 0 {
+		rsrv.timer = time.AfterFunc(ttl, func() { smg.expireEventReservation(msgID, rsrv) })
+	}
+	smg.eventReservations[msgID] = rsrv
+}
+
+// unrecordEventReservation removes the reservation, returning it if found
+func (smg *SMGeneric) unrecordEventReservation(msgID string) (rsrv *smgEventReservation) {
+	smg.eRsrvsMux.Lock()
+	defer smg.eRsrvsMux.Unlock()
+	var has bool
+	if rsrv, has = smg.eventReservations[msgID]; !has {
+		return nil
+	}
+	if rsrv.timer != nil {
+		rsrv.timer.Stop()
+	}
+	delete(smg.eventReservations, msgID)
+	return
+}
+
+// getEventReservation returns the reservation for the message ID or nil if not found
+func (smg *SMGeneric) getEventReservation(msgID string) *smgEventReservation {
+	smg.eRsrvsMux.RLock()
+	defer smg.eRsrvsMux.RUnlock()
+	return smg.eventReservations[msgID]
+}
+
+// expireEventReservation refunds a reservation which was not confirmed within the TTL
+func (smg *SMGeneric) expireEventReservation(msgID string, rsrv *smgEventReservation) {
+	guardian.Guardian.Guard(func() (interface{}, error) {
+		smg.eRsrvsMux.Lock()
+		if crrRsrv, has := smg.eventReservations[msgID]; !has || crrRsrv != rsrv { // committed or refunded meanwhile
+			smg.eRsrvsMux.Unlock()
+			return nil, nil
+		}
+		delete(smg.eventReservations, msgID)
+		smg.eRsrvsMux.Unlock()
+		utils.Logger.Warning(fmt.Sprintf("<SMGeneric> Event reservation for message: %s expired, refunding", msgID))
+		if err := smg.refundEvent(rsrv.event.GetCGRID(utils.META_DEFAULT), rsrv.sessionRuns); err != nil {
+			utils.Logger.Err(fmt.Sprintf("<SMGeneric> Could not refund expired reservation for message: %s, error: %s", msgID, err.Error()))
+		}
+		return nil, nil
+	}, smg.cgrCfg.LockingTimeout, eventReservationLockID(msgID))
+}
+
+func eventReservationLockID(msgID string) string {
+	return "SMGEventReservation" + msgID
+}
+
+// ReserveEvent debits a one time event (eg: SMS) and keeps the debit on hold until
+// CommitEvent or RefundEvent are called for the same message ID (OriginID)
+// reserving again an already reserved message ID returns the initial reservation without debiting twice
+func (smg *SMGeneric) ReserveEvent(gev SMGenericEvent) (maxUsage time.Duration, err error) {
+	msgID, err := eventReservationKey(gev)
+	if err != nil {
+		return
+	}
+	_, err = guardian.Guardian.Guard(func() (interface{}, error) {
+		if rsrv := smg.getEventReservation(msgID); rsrv != nil {
+			maxUsage = rsrv.maxUsage
+			return nil, nil
+		}
+		sessionRuns, rsrvUsage, err := smg.debitEvent(gev)
+		if err != nil {
+			return nil, err
+		}
+		maxUsage = rsrvUsage
+		if len(sessionRuns) == 0 { // nothing to charge
+			return nil, nil
+		}
+		smg.recordEventReservation(msgID,
+			&smgEventReservation{event: gev, sessionRuns: sessionRuns, maxUsage: maxUsage})
+		return nil, nil
+	}, smg.cgrCfg.LockingTimeout, eventReservationLockID(msgID))
+	return
+}
+
+// CommitEvent confirms the reservation for the message ID and stores its costs
+// if the event carries a Usage lower than the reserved one (eg: less message parts delivered), the difference is refunded
+func (smg *SMGeneric) CommitEvent(gev SMGenericEvent) (err error) {
+	msgID, err := eventReservationKey(gev)
+	if err != nil {
+		return
+	}
+	_, err = guardian.Guardian.Guard(func() (interface{}, error) {
+		rsrv := smg.unrecordEventReservation(msgID)
+		if rsrv == nil {
+			return nil, utils.ErrNotFound
+		}
+		if usage, errUsage := gev.GetUsage(utils.META_DEFAULT); errUsage == nil && usage < rsrv.maxUsage {
+			if err := smg.trimEventReservation(rsrv, usage); err != nil {
+				return nil, err
+			}
+		}
+		return nil, smg.storeEventCosts(rsrv.event, rsrv.sessionRuns)
+	}, smg.cgrCfg.LockingTimeout, eventReservationLockID(msgID))
+	return
+}
+
+// RefundEvent cancels the reservation for the message ID, giving back the debited costs
+func (smg *SMGeneric) RefundEvent(gev SMGenericEvent) (err error) {
+	msgID, err := eventReservationKey(gev)
+	if err != nil {
+		return
+	}
+	_, err = guardian.Guardian.Guard(func() (interface{}, error) {
+		rsrv := smg.unrecordEventReservation(msgID)
+		if rsrv == nil {
+			return nil, utils.ErrNotFound
+		}
+		return nil, smg.refundEvent(rsrv.event.GetCGRID(utils.META_DEFAULT), rsrv.sessionRuns)
+	}, smg.cgrCfg.LockingTimeout, eventReservationLockID(msgID))
+	return
+}
+
+// trimEventReservation refunds the costs over usage out of the reservation
+func (smg *SMGeneric) trimEventReservation(rsrv *smgEventReservation, usage time.Duration) error {
+	cgrID := rsrv.event.GetCGRID(utils.META_DEFAULT)
+	for _, sR := range rsrv.sessionRuns {
+		if len(sR.CallCosts) == 0 {
+			continue
+		}
+		cc := sR.CallCosts[0]
+		for _, ccSR := range sR.CallCosts[1:] {
+			cc.Merge(ccSR)
+		}
+		ec := engine.NewEventCostFromCallCost(cc, cgrID, sR.DerivedCharger.RunID)
+		srplsEC, err := ec.Trim(usage)
+		if err != nil {
+			return err
+		}
+		sR.CallCosts = []*engine.CallCost{eventCostAsCallCost(ec, cc)}
+		if srplsEC == nil {
+			continue
+		}
+		if err := smg.refundEvent(cgrID,
+			[]*engine.SessionRun{&engine.SessionRun{DerivedCharger: sR.DerivedCharger,
+				CallDescriptor: sR.CallDescriptor, CallCosts: []*engine.CallCost{eventCostAsCallCost(srplsEC, cc)}}}); err != nil {
+			return err
+		}
+	}
+	rsrv.maxUsage = usage
+	rsrv.event[utils.Usage] = usage
+	return nil
+}
+
+// eventCostAsCallCost converts the EventCost back, restoring the rating subject out of the original CallCost
+func eventCostAsCallCost(ec *engine.EventCost, origCC *engine.CallCost) (cc *engine.CallCost) {
+	cc = ec.AsCallCost()
+	cc.Direction = origCC.Direction
+	cc.Category = origCC.Category
+	cc.Tenant = origCC.Tenant
+	cc.Subject = origCC.Subject
+	cc.Account = origCC.Account
+	cc.Destination = origCC.Destination
+	cc.TOR = origCC.TOR
+	return
+}
+
+// refundEventReservations gives back all the reservations still on hold, used on shutdown
+func (smg *SMGeneric) refundEventReservations() {
+	smg.eRsrvsMux.RLock()
+	msgIDs := make([]string, 0, len(smg.eventReservations))
+	for msgID := range smg.eventReservations {
+		msgIDs = append(msgIDs, msgID)
+	}
+	smg.eRsrvsMux.RUnlock()
+	for _, msgID := range msgIDs {
+		if err := smg.RefundEvent(SMGenericEvent{utils.ACCID: msgID}); err != nil && err != utils.ErrNotFound {
+			utils.Logger.Err(fmt.Sprintf("<SMGeneric> Could not refund reservation for message: %s, error: %s", msgID, err.Error()))
+		}
+	}
+}
@@ -0,0 +1,210 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package sessionmanager
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cgrates/cgrates/config"
+	"github.com/cgrates/cgrates/engine"
+	"github.com/cgrates/cgrates/utils"
+)
+
+// testEventRALs simulates RALs and CDRs for one time events, debiting one increment per message part
+type testEventRALs struct {
+	sync.Mutex
+	calls    []string
+	refunded time.Duration
+	noCredit bool // fail the debits with insufficient credit
+}
+
+func (rals *testEventRALs) Call(serviceMethod string, args interface{}, reply interface{}) error {
+	rals.Lock()
+	defer rals.Unlock()
+	rals.calls = append(rals.calls, serviceMethod)
+	switch serviceMethod {
+	case "Responder.GetSessionRuns":
+		cdr := args.(*engine.CDR)
+		*reply.(*[]*engine.SessionRun) = []*engine.SessionRun{
+			&engine.SessionRun{DerivedCharger: &utils.DerivedCharger{RunID: utils.META_DEFAULT},
+				CallDescriptor: &engine.CallDescriptor{CgrID: cdr.CGRID, RunID: utils.META_DEFAULT,
+					Direction: utils.OUT, Category: "sms", Tenant: cdr.Tenant, Subject: cdr.Account,
+					Account: cdr.Account, Destination: cdr.Destination, TOR: utils.SMS,
+					TimeStart: cdr.AnswerTime, TimeEnd: cdr.AnswerTime.Add(cdr.Usage)}},
+		}
+	case "Responder.MaxDebit":
+		if rals.noCredit {
+			return utils.ErrInsufficientCredit
+		}
+		cd := args.(*engine.CallDescriptor)
+		cc := reply.(*engine.CallCost)
+		cc.Direction, cc.Category, cc.Tenant, cc.Subject = cd.Direction, cd.Category, cd.Tenant, cd.Subject
+		cc.Account, cc.Destination, cc.TOR = cd.Account, cd.Destination, cd.TOR
+		cc.AccountSummary = &engine.AccountSummary{Tenant: cd.Tenant, ID: cd.Account}
+		parts := cd.TimeEnd.Sub(cd.TimeStart)
+		cc.Cost = 0.1 * float64(parts)
+		cc.Timespans = engine.TimeSpans{&engine.TimeSpan{TimeStart: cd.TimeStart, TimeEnd: cd.TimeEnd,
+			Cost: cc.Cost, DurationIndex: parts, CompressFactor: 1,
+			RateInterval: &engine.RateInterval{Rating: &engine.RIRate{RoundingDecimals: 4, RoundingMethod: utils.ROUNDING_MIDDLE,
+				Rates: engine.RateGroups{&engine.Rate{Value: 0.1, RateIncrement: time.Duration(1), RateUnit: time.Duration(1)}}}},
+			Increments: engine.Increments{&engine.Increment{Duration: time.Duration(1), Cost: 0.1,
+				CompressFactor: int(parts), BalanceInfo: &engine.DebitInfo{AccountID: "cgrates.org:" + cd.Account,
+					Monetary: &engine.MonetaryInfo{UUID: "balance1"}}}}}}
+	case "Responder.RefundIncrements":
+		for _, incr := range args.(*engine.CallDescriptor).Increments {
+			rals.refunded += incr.Duration * time.Duration(incr.CompressFactor)
+		}
+		*reply.(*float64) = 0
+	case "Responder.RefundRounding":
+		*reply.(*float64) = 0
+	case "CdrsV1.StoreSMCost":
+		*reply.(*string) = utils.OK
+	}
+	return nil
+}
+
+func (rals *testEventRALs) getCalls() (calls []string, refunded time.Duration) {
+	rals.Lock()
+	defer rals.Unlock()
+	calls = make([]string, len(rals.calls))
+	copy(calls, rals.calls)
+	rals.calls = nil
+	refunded = rals.refunded
+	rals.refunded = 0
+	return
+}
+
+func testSMSEvent(msgID string, parts int) SMGenericEvent {
+	return SMGenericEvent{
+		utils.EVENT_NAME:  "SMS",
+		utils.TOR:         utils.SMS,
+		utils.ACCID:       msgID,
+		utils.Tenant:      "cgrates.org",
+		utils.Account:     "1001",
+		utils.Destination: "1002",
+		utils.AnswerTime:  "2017-08-01T12:00:00Z",
+		utils.Usage:       parts,
+	}
+}
+
+func TestSMGEventReservationCommit(t *testing.T) {
+	rals := new(testEventRALs)
+	smg := NewSMGeneric(smgCfg, rals, rals, nil, "UTC")
+	if _, err := smg.ReserveEvent(SMGenericEvent{utils.TOR: utils.SMS}); err == nil {
+		t.Error("Expecting error on missing message ID")
+	}
+	for i := 0; i < 2; i++ { // second reservation should not debit again
+		if maxUsage, err := smg.ReserveEvent(testSMSEvent("msg1", 2)); err != nil {
+			t.Error(err)
+		} else if maxUsage != time.Duration(2) {
+			t.Errorf("Unexpected maxUsage: %v", maxUsage)
+		}
+	}
+	if calls, _ := rals.getCalls(); !reflect.DeepEqual([]string{"Responder.GetSessionRuns", "Responder.MaxDebit"}, calls) {
+		t.Errorf("Unexpected calls: %+v", calls)
+	}
+	var reply string
+	if err := smg.BiRPCV1CommitEvent(nil, SMGenericEvent{utils.ACCID: "msg1"}, &reply); err != nil {
+		t.Error(err)
+	} else if reply != utils.OK {
+		t.Errorf("Unexpected reply: %s", reply)
+	}
+	if calls, refunded := rals.getCalls(); !reflect.DeepEqual([]string{"CdrsV1.StoreSMCost"}, calls) {
+		t.Errorf("Unexpected calls: %+v", calls)
+	} else if refunded != 0 {
+		t.Errorf("Unexpected refund: %v", refunded)
+	}
+	if err := smg.BiRPCV1CommitEvent(nil, SMGenericEvent{utils.ACCID: "msg1"}, &reply); err != utils.ErrNotFound {
+		t.Errorf("Expecting ErrNotFound, received: %v", err)
+	}
+	// only one of the three parts delivered
+	if _, err := smg.ReserveEvent(testSMSEvent("msg2", 3)); err != nil {
+		t.Error(err)
+	}
+	rals.getCalls()
+	if err := smg.CommitEvent(SMGenericEvent{utils.ACCID: "msg2", utils.Usage: 1}); err != nil {
+		t.Error(err)
+	}
+	if calls, refunded := rals.getCalls(); !reflect.DeepEqual([]string{"Responder.RefundIncrements", "CdrsV1.StoreSMCost"}, calls) {
+		t.Errorf("Unexpected calls: %+v", calls)
+	} else if refunded != time.Duration(2) {
+		t.Errorf("Unexpected refund: %v", refunded)
+	}
+}
+
+func TestSMGEventReservationRefund(t *testing.T) {
+	rals := new(testEventRALs)
+	cfg, _ := config.NewDefaultCGRConfig()
+	cfg.SmGenericConfig.EventReservationTTL = 20 * time.Millisecond
+	smg := NewSMGeneric(cfg, rals, rals, nil, "UTC")
+	if _, err := smg.ReserveEvent(testSMSEvent("msg1", 2)); err != nil {
+		t.Error(err)
+	}
+	rals.getCalls()
+	var reply string
+	if err := smg.BiRPCV1RefundEvent(nil, SMGenericEvent{utils.ACCID: "msg1"}, &reply); err != nil {
+		t.Error(err)
+	}
+	if calls, refunded := rals.getCalls(); !reflect.DeepEqual([]string{"Responder.RefundIncrements"}, calls) {
+		t.Errorf("Unexpected calls: %+v", calls)
+	} else if refunded != time.Duration(2) {
+		t.Errorf("Unexpected refund: %v", refunded)
+	}
+	if err := smg.RefundEvent(SMGenericEvent{utils.ACCID: "msg1"}); err != utils.ErrNotFound {
+		t.Errorf("Expecting ErrNotFound, received: %v", err)
+	}
+	// unconfirmed reservation should be refunded after TTL
+	if _, err := smg.ReserveEvent(testSMSEvent("msg2", 1)); err != nil {
+		t.Error(err)
+	}
+	rals.getCalls()
+	time.Sleep(50 * time.Millisecond)
+	if calls, refunded := rals.getCalls(); !reflect.DeepEqual([]string{"Responder.RefundIncrements"}, calls) {
+		t.Errorf("Unexpected calls: %+v", calls)
+	} else if refunded != time.Duration(1) {
+		t.Errorf("Unexpected refund: %v", refunded)
+	}
+	if rsrv := smg.getEventReservation("msg2"); rsrv != nil {
+		t.Errorf("Reservation not expired: %+v", rsrv)
+	}
+}
+
+func TestSMGEventReservationResponseCache(t *testing.T) {
+	rals := &testEventRALs{noCredit: true}
+	cfg, _ := config.NewDefaultCGRConfig()
+	cfg.ResponseCacheTTL = time.Minute
+	smg := NewSMGeneric(cfg, rals, rals, nil, "UTC")
+	for i := 0; i < 2; i++ { // retries should not hide the failed debit
+		if _, err := smg.ReserveEvent(testSMSEvent("msg1", 2)); err == nil ||
+			err.Error() != utils.ErrInsufficientCredit.Error() {
+			t.Errorf("Expecting insufficient credit error, received: %v", err)
+		}
+	}
+	rals.noCredit = false
+	for i := 0; i < 2; i++ {
+		if maxUsage, err := smg.ReserveEvent(testSMSEvent("msg1", 2)); err != nil {
+			t.Error(err)
+		} else if maxUsage != time.Duration(2) {
+			t.Errorf("Unexpected maxUsage: %v", maxUsage)
+		}
+	}
+}
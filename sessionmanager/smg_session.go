@@ -62,19 +62,19 @@ func (self *SMGSession) debitLoop(debitInterval time.Duration) {
 			return
 		case <-time.After(sleepDur):
 			if maxDebit, err := self.debit(debitInterval, nil); err != nil {
-				utils.Logger.Err(fmt.Sprintf("<SMGeneric> Could not complete debit operation on session: %s, error: %s", self.CGRID, err.Error()))
+				utils.Logger.Log(utils.LOGLEVEL_ERROR, self.CGRID, fmt.Sprintf("<SMGeneric> Could not complete debit operation on session: %s, error: %s", self.CGRID, err.Error()))
 				disconnectReason := SYSTEM_ERROR
 				if err.Error() == utils.ErrUnauthorizedDestination.Error() {
 					disconnectReason = err.Error()
 				}
 				if err := self.disconnectSession(disconnectReason); err != nil {
-					utils.Logger.Err(fmt.Sprintf("<SMGeneric> Could not disconnect session: %s, error: %s", self.CGRID, err.Error()))
+					utils.Logger.Log(utils.LOGLEVEL_ERROR, self.CGRID, fmt.Sprintf("<SMGeneric> Could not disconnect session: %s, error: %s", self.CGRID, err.Error()))
 				}
 				return
 			} else if maxDebit < debitInterval {
 				time.Sleep(maxDebit)
 				if err := self.disconnectSession(INSUFFICIENT_FUNDS); err != nil {
-					utils.Logger.Err(fmt.Sprintf("<SMGeneric> Could not disconnect session: %s, error: %s", self.CGRID, err.Error()))
+					utils.Logger.Log(utils.LOGLEVEL_ERROR, self.CGRID, fmt.Sprintf("<SMGeneric> Could not disconnect session: %s, error: %s", self.CGRID, err.Error()))
 				}
 				return
 			}
@@ -165,6 +165,20 @@ func (self *SMGSession) disconnectSession(reason string) error {
 	return nil
 }
 
+// Send re-authorization order to remote connection
+func (self *SMGSession) reAuthSession() error {
+	if self.clntConn == nil || reflect.ValueOf(self.clntConn).IsNil() {
+		return errors.New("Calling SMGClientV1.ReAuthSession requires bidirectional JSON connection")
+	}
+	var reply string
+	if err := self.clntConn.Call("SMGClientV1.ReAuthSession", utils.AttrReAuthSession{EventStart: self.EventStart}, &reply); err != nil {
+		return err
+	} else if reply != utils.OK {
+		return errors.New(fmt.Sprintf("Unexpected re-auth reply: %s", reply))
+	}
+	return nil
+}
+
 // Session has ended, check debits and refund the extra charged duration
 func (self *SMGSession) close(usage time.Duration) (err error) {
 	self.mux.Lock()
@@ -80,6 +80,7 @@ func NewSMGeneric(cgrCfg *config.CGRConfig, rals rpcclient.RpcClientConnection,
 		pSessionsIndex:     make(map[string]map[string]map[string]utils.StringMap),
 		pSessionsRIndex:    make(map[string][]*riFieldNameVal),
 		sessionTerminators: make(map[string]*smgSessionTerminator),
+		eventReservations:  make(map[string]*smgEventReservation),
 		responseCache:      cache.NewResponseCache(cgrCfg.ResponseCacheTTL)}
 }
 
@@ -103,6 +104,8 @@ type SMGeneric struct {
 	sessionTerminators map[string]*smgSessionTerminator                 // terminate and cleanup the session if timer expires
 	sTsMux             sync.RWMutex                                     // protects sessionTerminators
 	responseCache      *cache.ResponseCache                             // cache replies here
+	eventReservations  map[string]*smgEventReservation                  // debits on hold for one time events, indexed on message ID
+	eRsrvsMux          sync.RWMutex                                     // protects eventReservations
 }
 
 // riFieldNameVal is a reverse index entry
@@ -412,15 +415,15 @@ func (smg *SMGeneric) sessionEnd(cgrID string, usage time.Duration) error {
 			}
 			aTime, err := s.EventStart.GetAnswerTime(utils.META_DEFAULT, smg.Timezone)
 			if err != nil || aTime.IsZero() {
-				utils.Logger.Err(fmt.Sprintf("<SMGeneric> Could not retrieve answer time for session: %s, runId: %s, aTime: %+v, error: %v",
+				utils.Logger.Log(utils.LOGLEVEL_ERROR, cgrID, fmt.Sprintf("<SMGeneric> Could not retrieve answer time for session: %s, runId: %s, aTime: %+v, error: %v",
 					cgrID, s.RunID, aTime, err))
 				continue // Unanswered session
 			}
 			if err := s.close(usage); err != nil {
-				utils.Logger.Err(fmt.Sprintf("<SMGeneric> Could not close session: %s, runId: %s, error: %s", cgrID, s.RunID, err.Error()))
+				utils.Logger.Log(utils.LOGLEVEL_ERROR, cgrID, fmt.Sprintf("<SMGeneric> Could not close session: %s, runId: %s, error: %s", cgrID, s.RunID, err.Error()))
 			}
 			if err := s.storeSMCost(); err != nil {
-				utils.Logger.Err(fmt.Sprintf("<SMGeneric> Could not save session: %s, runId: %s, error: %s", cgrID, s.RunID, err.Error()))
+				utils.Logger.Log(utils.LOGLEVEL_ERROR, cgrID, fmt.Sprintf("<SMGeneric> Could not save session: %s, runId: %s, error: %s", cgrID, s.RunID, err.Error()))
 			}
 		}
 		return nil, nil
@@ -593,6 +596,21 @@ func (smg *SMGeneric) passiveToActive(cgrID string) (pSessions map[string][]*SMG
 // asActiveSessions returns sessions from either active or passive table as []*ActiveSession
 func (smg *SMGeneric) asActiveSessions(fltrs map[string]string, count, passiveSessions bool) (aSessions []*ActiveSession, counter int, err error) {
 	aSessions = make([]*ActiveSession, 0) // Make sure we return at least empty list and not nil
+	remainingSessions, err := smg.filterSessions(fltrs, passiveSessions)
+	if err != nil {
+		return nil, 0, err
+	}
+	if count {
+		return nil, len(remainingSessions), nil
+	}
+	for _, s := range remainingSessions {
+		aSessions = append(aSessions, s.AsActiveSession(smg.Timezone)) // Expensive for large number of sessions
+	}
+	return
+}
+
+// filterSessions returns the sessions out of either active or passive table matching fltrs
+func (smg *SMGeneric) filterSessions(fltrs map[string]string, passiveSessions bool) (remainingSessions []*SMGSession, err error) {
 	// Check first based on indexes so we can downsize the list of matching sessions
 	matchingSessionIDs, checkedFilters := smg.getSessionIDsMatchingIndexes(fltrs, passiveSessions)
 	if len(matchingSessionIDs) == 0 && len(checkedFilters) != 0 {
@@ -603,7 +621,6 @@ func (smg *SMGeneric) asActiveSessions(fltrs map[string]string, count, passiveSe
 			delete(fltrs, fltrFldName)
 		}
 	}
-	var remainingSessions []*SMGSession // Survived index matching
 	var ss map[string][]*SMGSession
 	if passiveSessions {
 		ss = smg.getSessions(fltrs[utils.CGRID], true)
@@ -622,7 +639,7 @@ func (smg *SMGeneric) asActiveSessions(fltrs map[string]string, count, passiveSe
 		for i := 0; i < len(remainingSessions); {
 			sMp, err := remainingSessions[i].EventStart.AsMapStringString()
 			if err != nil {
-				return nil, 0, err
+				return nil, err
 			}
 			if _, hasRunID := sMp[utils.MEDI_RUNID]; !hasRunID {
 				sMp[utils.MEDI_RUNID] = utils.META_DEFAULT
@@ -641,12 +658,6 @@ func (smg *SMGeneric) asActiveSessions(fltrs map[string]string, count, passiveSe
 			i++
 		}
 	}
-	if count {
-		return nil, len(remainingSessions), nil
-	}
-	for _, s := range remainingSessions {
-		aSessions = append(aSessions, s.AsActiveSession(smg.Timezone)) // Expensive for large number of sessions
-	}
 	return
 }
 
@@ -768,7 +779,7 @@ func (smg *SMGeneric) UpdateSession(gev SMGenericEvent, clnt rpcclient.RpcClient
 	aSessions := smg.getSessions(cgrID, false)
 	if len(aSessions) == 0 {
 		if aSessions = smg.passiveToActive(cgrID); len(aSessions) == 0 {
-			utils.Logger.Err(fmt.Sprintf("<SMGeneric> SessionUpdate with no active sessions for event: <%s>", cgrID))
+			utils.Logger.Log(utils.LOGLEVEL_ERROR, cgrID, fmt.Sprintf("<SMGeneric> SessionUpdate with no active sessions for event: <%s>", cgrID))
 			err = rpcclient.ErrSessionNotFound
 			return
 		}
@@ -835,7 +846,7 @@ func (smg *SMGeneric) TerminateSession(gev SMGenericEvent, clnt rpcclient.RpcCli
 		aSessions := smg.getSessions(sessionID, false)
 		if len(aSessions) == 0 {
 			if aSessions = smg.passiveToActive(cgrID); len(aSessions) == 0 {
-				utils.Logger.Err(fmt.Sprintf("<SMGeneric> SessionTerminate with no active sessions for cgrID: <%s>", cgrID))
+				utils.Logger.Log(utils.LOGLEVEL_ERROR, cgrID, fmt.Sprintf("<SMGeneric> SessionTerminate with no active sessions for cgrID: <%s>", cgrID))
 				continue
 			}
 		}
@@ -865,6 +876,16 @@ func (smg *SMGeneric) ChargeEvent(gev SMGenericEvent) (maxUsage time.Duration, e
 	}
 	defer smg.responseCache.Cache(cacheKey, &cache.CacheItem{Value: maxUsage, Err: err})
 	var sessionRuns []*engine.SessionRun
+	if sessionRuns, maxUsage, err = smg.debitEvent(gev); err != nil || len(sessionRuns) == 0 {
+		return
+	}
+	err = smg.storeEventCosts(gev, sessionRuns)
+	return
+}
+
+// debitEvent debits all the session runs of a one time event
+// on error, the debits already done are refunded
+func (smg *SMGeneric) debitEvent(gev SMGenericEvent) (sessionRuns []*engine.SessionRun, maxUsage time.Duration, err error) {
 	if err = smg.rals.Call("Responder.GetSessionRuns", gev.AsCDR(smg.cgrCfg, smg.Timezone), &sessionRuns); err != nil {
 		return
 	} else if len(sessionRuns) == 0 {
@@ -874,7 +895,7 @@ func (smg *SMGeneric) ChargeEvent(gev SMGenericEvent) (maxUsage time.Duration, e
 	for _, sR := range sessionRuns {
 		cc := new(engine.CallCost)
 		if err = smg.rals.Call("Responder.MaxDebit", sR.CallDescriptor, cc); err != nil {
-			utils.Logger.Err(fmt.Sprintf("<SMGeneric> Could not Debit CD: %+v, RunID: %s, error: %s", sR.CallDescriptor, sR.DerivedCharger.RunID, err.Error()))
+			utils.Logger.Log(utils.LOGLEVEL_ERROR, sR.CallDescriptor.CgrID, fmt.Sprintf("<SMGeneric> Could not Debit CD: %+v, RunID: %s, error: %s", sR.CallDescriptor, sR.DerivedCharger.RunID, err.Error()))
 			break
 		}
 		sR.CallCosts = append(sR.CallCosts, cc) // Save it so we can revert on issues
@@ -886,38 +907,51 @@ func (smg *SMGeneric) ChargeEvent(gev SMGenericEvent) (maxUsage time.Duration, e
 		}
 	}
 	if err != nil { // Refund the ones already taken since we have error on one of the debits
-		for _, sR := range sessionRuns {
-			if len(sR.CallCosts) == 0 {
-				continue
-			}
-			cc := sR.CallCosts[0]
-			if len(sR.CallCosts) > 1 {
-				for _, ccSR := range sR.CallCosts {
-					cc.Merge(ccSR)
-				}
-			}
-			// collect increments
-			var refundIncrements engine.Increments
-			cc.Timespans.Decompress()
-			for _, ts := range cc.Timespans {
-				refundIncrements = append(refundIncrements, ts.Increments...)
+		if errRefund := smg.refundEvent(gev.GetCGRID(utils.META_DEFAULT), sessionRuns); errRefund != nil {
+			return nil, 0, errRefund
+		}
+		return nil, 0, err
+	}
+	return
+}
+
+// refundEvent gives back the costs debited for the session runs of a one time event
+func (smg *SMGeneric) refundEvent(cgrID string, sessionRuns []*engine.SessionRun) error {
+	for _, sR := range sessionRuns {
+		if len(sR.CallCosts) == 0 {
+			continue
+		}
+		cc := sR.CallCosts[0]
+		if len(sR.CallCosts) > 1 {
+			for _, ccSR := range sR.CallCosts[1:] {
+				cc.Merge(ccSR)
 			}
-			// refund cc
-			if len(refundIncrements) > 0 {
-				cd := cc.CreateCallDescriptor()
-				cd.Increments = refundIncrements
-				cd.CgrID = cgrID
-				cd.RunID = sR.CallDescriptor.RunID
-				cd.Increments.Compress()
-				var response float64
-				errRefund := smg.rals.Call("Responder.RefundIncrements", cd, &response)
-				if errRefund != nil {
-					return 0, errRefund
-				}
+		}
+		// collect increments
+		var refundIncrements engine.Increments
+		cc.Timespans.Decompress()
+		for _, ts := range cc.Timespans {
+			refundIncrements = append(refundIncrements, ts.Increments...)
+		}
+		// refund cc
+		if len(refundIncrements) > 0 {
+			cd := cc.CreateCallDescriptor()
+			cd.Increments = refundIncrements
+			cd.CgrID = cgrID
+			cd.RunID = sR.CallDescriptor.RunID
+			cd.Increments.Compress()
+			var response float64
+			if err := smg.rals.Call("Responder.RefundIncrements", cd, &response); err != nil {
+				return err
 			}
 		}
-		return
 	}
+	return nil
+}
+
+// storeEventCosts refunds the rounding and stores the costs of a one time event with the CDRs
+func (smg *SMGeneric) storeEventCosts(gev SMGenericEvent, sessionRuns []*engine.SessionRun) (err error) {
+	cgrID := gev.GetCGRID(utils.META_DEFAULT)
 	var withErrors bool
 	for _, sR := range sessionRuns {
 		if len(sR.CallCosts) == 0 {
@@ -951,12 +985,11 @@ func (smg *SMGeneric) ChargeEvent(gev SMGenericEvent) (maxUsage time.Duration, e
 		if errStore := smg.cdrsrv.Call("CdrsV1.StoreSMCost", engine.AttrCDRSStoreSMCost{Cost: smCost,
 			CheckDuplicate: true}, &reply); errStore != nil && !strings.HasSuffix(errStore.Error(), utils.ErrExists.Error()) {
 			withErrors = true
-			utils.Logger.Err(fmt.Sprintf("<SMGeneric> Could not save CC: %+v, RunID: %s error: %s", cc, sR.DerivedCharger.RunID, errStore.Error()))
+			utils.Logger.Log(utils.LOGLEVEL_ERROR, sR.CallDescriptor.CgrID, fmt.Sprintf("<SMGeneric> Could not save CC: %+v, RunID: %s error: %s", cc, sR.DerivedCharger.RunID, errStore.Error()))
 		}
 	}
 	if withErrors {
 		err = ErrPartiallyExecuted
-		return
 	}
 	return
 }
@@ -985,9 +1018,27 @@ func (smg *SMGeneric) Shutdown() error {
 	for ssId := range smg.getSessions("", false) { // Force sessions shutdown
 		smg.sessionEnd(ssId, time.Duration(smg.cgrCfg.MaxCallDuration))
 	}
+	smg.refundEventReservations()
 	return nil
 }
 
+// PromMetrics exports the number of active and passive sessions
+func (smg *SMGeneric) PromMetrics() []*utils.PromMetric {
+	smg.aSessionsMux.RLock()
+	aSessions := len(smg.activeSessions)
+	smg.aSessionsMux.RUnlock()
+	smg.pSessionsMux.RLock()
+	pSessions := len(smg.passiveSessions)
+	smg.pSessionsMux.RUnlock()
+	return []*utils.PromMetric{
+		&utils.PromMetric{Name: utils.PromNamespace + "_smg_sessions",
+			Help: "Sessions handled by SMGeneric", Type: utils.PromGauge,
+			Samples: []*utils.PromSample{
+				&utils.PromSample{Labels: map[string]string{"state": "active"}, Value: float64(aSessions)},
+				&utils.PromSample{Labels: map[string]string{"state": "passive"}, Value: float64(pSessions)}}},
+	}
+}
+
 // RpcClientConnection interface
 func (smg *SMGeneric) Call(serviceMethod string, args interface{}, reply interface{}) error {
 	return smg.CallBiRPC(nil, serviceMethod, args, reply) // Capture the version part out of original call
@@ -1148,6 +1199,50 @@ func (smg *SMGeneric) BiRPCV2ChargeEvent(clnt rpcclient.RpcClientConnection, ev
 	return nil
 }
 
+// Reserves the cost of an individual Event (eg SMS) until committed or refunded
+func (smg *SMGeneric) BiRPCV1ReserveEvent(clnt rpcclient.RpcClientConnection, ev SMGenericEvent, maxUsage *float64) error {
+	if minMaxUsage, err := smg.ReserveEvent(ev); err != nil {
+		return utils.NewErrServerError(err)
+	} else {
+		*maxUsage = minMaxUsage.Seconds()
+	}
+	return nil
+}
+
+// Reserves the cost of an individual Event (eg SMS) until committed or refunded
+func (smg *SMGeneric) BiRPCV2ReserveEvent(clnt rpcclient.RpcClientConnection, ev SMGenericEvent, maxUsage *time.Duration) error {
+	if minMaxUsage, err := smg.ReserveEvent(ev); err != nil {
+		return utils.NewErrServerError(err)
+	} else {
+		*maxUsage = minMaxUsage
+	}
+	return nil
+}
+
+// Confirms a reserved Event (eg SMS delivered), storing its cost
+func (smg *SMGeneric) BiRPCV1CommitEvent(clnt rpcclient.RpcClientConnection, ev SMGenericEvent, reply *string) error {
+	if err := smg.CommitEvent(ev); err != nil {
+		if err == utils.ErrNotFound {
+			return err
+		}
+		return utils.NewErrServerError(err)
+	}
+	*reply = utils.OK
+	return nil
+}
+
+// Cancels a reserved Event (eg SMS failed delivery), refunding its cost
+func (smg *SMGeneric) BiRPCV1RefundEvent(clnt rpcclient.RpcClientConnection, ev SMGenericEvent, reply *string) error {
+	if err := smg.RefundEvent(ev); err != nil {
+		if err == utils.ErrNotFound {
+			return err
+		}
+		return utils.NewErrServerError(err)
+	}
+	*reply = utils.OK
+	return nil
+}
+
 // Called on session end, should send the CDR to CDRS
 func (smg *SMGeneric) BiRPCV1ProcessCDR(clnt rpcclient.RpcClientConnection, ev SMGenericEvent, reply *string) error {
 	if err := smg.ProcessCDR(ev); err != nil {
@@ -1217,6 +1312,33 @@ func (smg *SMGeneric) BiRPCV1GetPassiveSessionsCount(clnt rpcclient.RpcClientCon
 	return nil
 }
 
+// BiRPCV1ReAuthorizeSessions asks the clients owning the active sessions matching fltr to re-authorize them
+func (smg *SMGeneric) BiRPCV1ReAuthorizeSessions(clnt rpcclient.RpcClientConnection, fltr map[string]string, reply *string) error {
+	for fldName, fldVal := range fltr {
+		if fldVal == "" {
+			fltr[fldName] = utils.META_NONE
+		}
+	}
+	ss, err := smg.filterSessions(fltr, false)
+	if err != nil {
+		return utils.NewErrServerError(err)
+	} else if len(ss) == 0 {
+		return utils.ErrNotFound
+	}
+	reAuthed := make(utils.StringMap) // one request per CGRID, the runs share the client
+	for _, s := range ss {
+		if _, has := reAuthed[s.CGRID]; has {
+			continue
+		}
+		reAuthed[s.CGRID] = true
+		if err := s.reAuthSession(); err != nil {
+			utils.Logger.Log(utils.LOGLEVEL_WARNING, s.CGRID, fmt.Sprintf("<SMGeneric> Could not re-authorize session: %s, error: %s", s.CGRID, err.Error()))
+		}
+	}
+	*reply = utils.OK
+	return nil
+}
+
 type ArgsSetPassiveSessions struct {
 	CGRID    string
 	Sessions []*SMGSession
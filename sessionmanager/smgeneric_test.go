@@ -532,3 +532,40 @@ func TestGetPassiveSessions(t *testing.T) {
 		t.Errorf("PassiveSessions: %+v", pSS)
 	}
 }
+
+// testSMGClient records the session requests received from SMG
+type testSMGClient struct {
+	calls []string
+}
+
+func (c *testSMGClient) Call(serviceMethod string, args interface{}, reply interface{}) error {
+	c.calls = append(c.calls, serviceMethod)
+	*reply.(*string) = utils.OK
+	return nil
+}
+
+func TestSMGReAuthorizeSessions(t *testing.T) {
+	smg := NewSMGeneric(smgCfg, nil, nil, nil, "UTC")
+	clnt := new(testSMGClient)
+	smGev := SMGenericEvent{
+		utils.EVENT_NAME: "TEST_EVENT",
+		utils.ACCID:      "reauth1",
+		utils.Account:    "account1",
+		utils.Tenant:     "cgrates.org",
+	}
+	cgrID := smGev.GetCGRID(utils.META_DEFAULT)
+	smg.recordASession(&SMGSession{CGRID: cgrID, RunID: utils.META_DEFAULT, EventStart: smGev, clntConn: clnt})
+	smg.recordASession(&SMGSession{CGRID: cgrID, RunID: "second_run", EventStart: smGev, clntConn: clnt})
+	var reply string
+	if err := smg.BiRPCV1ReAuthorizeSessions(nil, map[string]string{utils.Tenant: "itsyscom.com"}, &reply); err != utils.ErrNotFound {
+		t.Errorf("Expecting ErrNotFound, received: %v", err)
+	}
+	if err := smg.BiRPCV1ReAuthorizeSessions(nil, map[string]string{utils.Account: "account1"}, &reply); err != nil {
+		t.Error(err)
+	} else if reply != utils.OK {
+		t.Errorf("Received reply: %s", reply)
+	}
+	if eCalls := []string{"SMGClientV1.ReAuthSession"}; !reflect.DeepEqual(eCalls, clnt.calls) { // one request per CGRID
+		t.Errorf("Expecting: %+v, received: %+v", eCalls, clnt.calls)
+	}
+}
@@ -152,6 +152,7 @@ type DestinationRate struct {
 	RoundingDecimals int
 	MaxCost          float64
 	MaxCostStrategy  string
+	VolumeCounter    string // account counter used to apply the rate groups on the usage cumulated in a period
 }
 
 type ApierTPTiming struct {
@@ -192,6 +193,7 @@ func NewTiming(timingInfo ...string) (rt *TPTiming) {
 type TPRatingPlan struct {
 	TPid               string                 // Tariff plan id
 	ID                 string                 // RatingPlan profile id
+	Currency           string                 // Currency of the rates, empty for the default one
 	RatingPlanBindings []*TPRatingPlanBinding // Set of destinationid-rateid bindings
 }
 
@@ -617,6 +619,7 @@ type TPAccountActions struct {
 	ActionTriggersId string // Id of ActionTriggers profile to use
 	AllowNegative    bool
 	Disabled         bool
+	FeeProfileIDs    string // FeeProfiles the account subscribes to, separated by ;
 }
 
 // Returns the id used in some nosql dbs (eg: redis)
@@ -678,6 +681,9 @@ type ArgsCache struct {
 	FilterIDs             *[]string
 	SupplierProfileIDs    *[]string
 	AttributeProfileIDs   *[]string
+	ExchangeRateIDs       *[]string
+	TaxProfileIDs         *[]string
+	FeeProfileIDs         *[]string
 }
 
 // Data used to do remote cache reloads via api
@@ -695,6 +701,7 @@ type CacheKeys struct {
 }
 
 type AttrCacheStats struct { // Add in the future filters here maybe so we avoid counting complete cache
+	CacheIDs []string // limit the partition metrics to these cache partitions, all if empty
 }
 
 type CacheStats struct {
@@ -721,6 +728,22 @@ type CacheStats struct {
 	Filters             int
 	SupplierProfiles    int
 	AttributeProfiles   int
+	Partitions          map[string]*CachePartitionMetrics // usage metrics indexed on cache partition
+}
+
+// CachePartitionMetrics holds the usage counters of one cache partition
+type CachePartitionMetrics struct {
+	Items     int
+	Hits      int64
+	Misses    int64
+	Evictions int64 // items dropped because the partition limit was reached
+	Expiries  int64 // items dropped because their TTL expired
+}
+
+// PrecacheStatus reports the cache warm-up progress
+type PrecacheStatus struct {
+	Ready      bool              // all partitions were loaded from DataDB
+	Partitions map[string]string // status of each precached partition, <*loading|*ready>
 }
 
 type AttrExpFileCdrs struct {
@@ -1241,6 +1264,7 @@ type AttrSetBalance struct {
 	Value          *float64
 	ExpiryTime     *string
 	RatingSubject  *string
+	Currency       *string
 	Categories     *string
 	DestinationIds *string
 	TimingIds      *string
@@ -1313,6 +1337,11 @@ type AttrDisconnectSession struct {
 	Reason     string
 }
 
+// Attributes to send on SessionReAuth by SMG
+type AttrReAuthSession struct {
+	EventStart map[string]interface{}
+}
+
 // TPStats is used in APIs to manage remotely offline Stats config
 type TPStats struct {
 	TPid               string
@@ -1399,3 +1428,47 @@ type TPAttributeProfile struct {
 	Attributes         []*TPAttribute
 	Weight             float64
 }
+
+type TPCurrencyRate struct {
+	ActivationTime string  // Time when this rate becomes active, *now or datetime
+	Rate           float64 // Units of ToCurrency for one unit of FromCurrency
+}
+
+type TPExchangeRate struct {
+	TPid         string
+	FromCurrency string
+	ToCurrency   string
+	Rates        []*TPCurrencyRate
+}
+
+type TPTaxTier struct {
+	Threshold float64 // Cost from which the tier applies
+	Value     float64 // Percentage applied on the cost within the tier
+}
+
+type TPTax struct {
+	ID    string
+	Type  string  // <*percent|*fixed|*tiered>
+	Value float64 // Percentage for *percent, amount per event for *fixed
+	Tiers []*TPTaxTier
+}
+
+type TPTaxProfile struct {
+	TPid               string
+	Tenant             string
+	ID                 string
+	FilterIDs          []string
+	ActivationInterval *TPActivationInterval // Time when this profile becomes active and expires
+	Taxes              []*TPTax
+	Weight             float64
+}
+
+type TPFeeProfile struct {
+	TPid     string
+	Tenant   string
+	ID       string
+	Period   string  // <*daily|*weekly|*monthly|*yearly>
+	Amount   float64 // Fee for one full period
+	Category string
+	ProRate  bool // Charge only the part of the period the subscription is active
+}
@@ -66,6 +66,9 @@ var (
 		CacheFilters:             FilterPrefix,
 		CacheSupplierProfiles:    SupplierProfilePrefix,
 		CacheAttributeProfiles:   AttributeProfilePrefix,
+		CacheExchangeRates:       ExchangeRatePrefix,
+		CacheTaxProfiles:         TaxProfilePrefix,
+		CacheFeeProfiles:         FeeProfilePrefix,
 	}
 	CachePrefixToInstance map[string]string // will be built on init
 )
@@ -78,6 +81,7 @@ const (
 	REDIS_MAX_CONNS                 = 10
 	POSTGRES                        = "postgres"
 	MYSQL                           = "mysql"
+	SQLITE                          = "sqlite"
 	MONGO                           = "mongo"
 	REDIS                           = "redis"
 	MAPSTOR                         = "mapstor"
@@ -154,6 +158,8 @@ const (
 	DRYRUN                          = "dry_run"
 	META_COMBIMED                   = "*combimed"
 	MetaInternal                    = "*internal"
+	MaskedSecret                    = "******"
+	MetaEmbedded                    = "*embedded"
 	ZERO_RATING_SUBJECT_PREFIX      = "*zero"
 	OK                              = "OK"
 	CDRE_FIXED_WIDTH                = "fwv"
@@ -234,6 +240,11 @@ const (
 	AttributeProfilePrefix          = "alp_"
 	AttributeProfilesStringIndex    = "ali_"
 	AttributeProfilesStringRevIndex = "alr_"
+	ExchangeRatePrefix              = "xrt_"
+	TaxProfilePrefix                = "txp_"
+	TaxProfilesStringIndex          = "txi_"
+	TaxProfilesStringRevIndex       = "txr_"
+	FeeProfilePrefix                = "fep_"
 	ThresholdProfilePrefix          = "thp_"
 	StatQueuePrefix                 = "stq_"
 	LOADINST_KEY                    = "load_history"
@@ -387,6 +398,7 @@ const (
 	FileLockPrefix               = "file_"
 	ActionsPoster                = "act"
 	CDRPoster                    = "cdr"
+	BalanceEventsPoster          = "blc"
 	MetaFileCSV                  = "*file_csv"
 	MetaFileFWV                  = "*file_fwv"
 	Accounts                     = "Accounts"
@@ -397,6 +409,10 @@ const (
 	SharedGroups                 = "SharedGroups"
 	MetaEveryMinute              = "*every_minute"
 	MetaHourly                   = "*hourly"
+	MetaDaily                    = "*daily"
+	MetaWeekly                   = "*weekly"
+	MetaMonthly                  = "*monthly"
+	MetaYearly                   = "*yearly"
 	ID                           = "ID"
 	Thresholds                   = "Thresholds"
 	Suppliers                    = "Suppliers"
@@ -416,14 +432,24 @@ const (
 	ResourceID                   = "ResourceID"
 	TotalUsage                   = "TotalUsage"
 	StatID                       = "StatID"
+	ThresholdID                  = "ThresholdID"
+	Hits                         = "Hits"
+	EmitHops                     = "EmitHops"
 	BalanceType                  = "BalanceType"
 	BalanceID                    = "BalanceID"
 	Units                        = "Units"
 	ResourceS                    = "ResourceS"
 	AccountUpdate                = "AccountUpdate"
 	BalanceUpdate                = "BalanceUpdate"
+	BalanceCreated               = "BalanceCreated"
+	BalanceExpiring              = "BalanceExpiring"
+	BalanceExpired               = "BalanceExpired"
+	BalanceExhausted             = "BalanceExhausted"
+	BalanceUUID                  = "BalanceUUID"
+	LeadTime                     = "LeadTime"
 	StatUpdate                   = "StatUpdate"
 	ResourceUpdate               = "ResourceUpdate"
+	ThresholdHit                 = "ThresholdHit"
 	CDR                          = "CDR"
 	CDRs                         = "CDRs"
 	ExpiryTime                   = "ExpiryTime"
@@ -431,6 +457,27 @@ const (
 	Disabled                     = "Disabled"
 	Action                       = "Action"
 	MetaNow                      = "*now"
+	MetaLoading                  = "*loading"
+	MetaReady                    = "*ready"
+	MetaUrl                      = "*url"
+	MetaJSON                     = "*json"
+	MetaText                     = "*text"
+	MetaAuth                     = "*auth"
+	MetaInitiate                 = "*initiate"
+	MetaUpdate                   = "*update"
+	MetaTerminate                = "*terminate"
+	MetaEvent                    = "*event"
+	MetaCDR                      = "*cdr"
+	MetaReserve                  = "*reserve"
+	MetaCommit                   = "*commit"
+	MetaRefund                   = "*refund"
+	MetaPercent                  = "*percent"
+	MetaFixed                    = "*fixed"
+	MetaTiered                   = "*tiered"
+	Currency                     = "Currency"
+	TaxCost                      = "TaxCost"
+	TaxS                         = "TaxS"
+	SubscriptionS                = "SubscriptionS"
 	TpRatingPlans                = "TpRatingPlans"
 	TpLcrs                       = "TpLcrs"
 	TpFilters                    = "TpFilters"
@@ -519,6 +566,14 @@ const (
 	SupplierS                    = "SupplierS"
 	MetaWeight                   = "*weight"
 	MetaLeastCost                = "*least_cost"
+	MetaHighestQuality           = "*highest_quality"
+	MetaLoadDistribution         = "*load_distribution"
+	MetaLeastCostWithQuality     = "*least_cost_with_quality"
+	MetaAscending                = "*asc"
+	MetaDescending               = "*desc"
+	ResourceUsage                = "ResourceUsage"
+	Ratio                        = "Ratio"
+	Load                         = "Load"
 	Weight                       = "Weight"
 	Cost                         = "Cost"
 	RatingPlanID                 = "RatingPlanID"
@@ -527,13 +582,25 @@ const (
 
 //Meta
 const (
-	MetaASR = "*asr"
-	MetaACD = "*acd"
-	MetaTCD = "*tcd"
-	MetaACC = "*acc"
-	MetaTCC = "*tcc"
-	MetaPDD = "*pdd"
-	MetaDDC = "*ddc"
+	MetaASR        = "*asr"
+	MetaACD        = "*acd"
+	MetaTCD        = "*tcd"
+	MetaACC        = "*acc"
+	MetaTCC        = "*tcc"
+	MetaPDD        = "*pdd"
+	MetaDDC        = "*ddc"
+	MetaSum        = "*sum"
+	MetaAverage    = "*average"
+	MetaMax        = "*max"
+	MetaMin        = "*min"
+	MetaDistinct   = "*distinct"
+	MetaPercentile = "*p"
+	MetaMedian     = "*median"
+	MetaHistogram  = "*histogram"
+	HashtagSep     = "#"
+	UnderscoreSep  = "_"
+	HistogramInf   = "+Inf"
+	HistogramSep   = "&"
 )
 
 //Migrator Metas
@@ -564,6 +631,8 @@ const (
 	StatSv1ProcessEvent             = "StatSv1.ProcessEvent"
 	StatSv1GetQueueIDs              = "StatSv1.GetQueueIDs"
 	StatSv1GetGetQueueStringMetrics = "StatSv1.GetQueueStringMetrics"
+	StatSv1GetQueueFloatMetrics     = "StatSv1.GetQueueFloatMetrics"
+	StatSv1ResetStatQueue           = "StatSv1.ResetStatQueue"
 )
 
 //ResourceS APIs
@@ -572,6 +641,43 @@ const (
 	ResourceSv1AllocateResource     = "ResourceSv1.AllocateResource"
 	ResourceSv1ReleaseResource      = "ResourceSv1.ReleaseResource"
 	ResourceSv1AllowUsage           = "ResourceSv1.AllowUsage"
+	ResourceSv1GetResource          = "ResourceSv1.GetResource"
+)
+
+//CacheS APIs
+const (
+	CacheSv1ReplicateItems = "CacheSv1.ReplicateItems"
+)
+
+//ConfigS APIs
+const (
+	ConfigSv1GetJSONSection = "ConfigSv1.GetJSONSection"
+	ConfigSv1GetConfig      = "ConfigSv1.GetConfig"
+	ConfigSv1ReloadConfig   = "ConfigSv1.ReloadConfig"
+)
+
+//CoreS APIs
+const (
+	CoreSv1Status = "CoreSv1.Status"
+)
+
+// Services reported in engine health
+const (
+	SchedulerS    = "SchedulerS"
+	ThresholdS    = "ThresholdS"
+	SessionS      = "SessionS"
+	CDRStatS      = "CDRStatS"
+	HistoryS      = "HistoryS"
+	PubSubS       = "PubSubS"
+	UserS         = "UserS"
+	AliaseS       = "AliaseS"
+	DiameterAgent = "DiameterAgent"
+	RadiusAgent   = "RadiusAgent"
+	HTTPAgent     = "HTTPAgent"
+	SMAsteriskS   = "SMAsterisk"
+	SMFreeSWITCH  = "SMFreeSWITCH"
+	SMKamailio    = "SMKamailio"
+	SMOpenSIPS    = "SMOpenSIPS"
 )
 
 //CSV file name
@@ -598,6 +704,9 @@ const (
 	FiltersCsv            = "Filters.csv"
 	SuppliersCsv          = "Suppliers.csv"
 	AttributesCsv         = "Attributes.csv"
+	ExchangeRatesCsv      = "ExchangeRates.csv"
+	TaxProfilesCsv        = "TaxProfiles.csv"
+	FeeProfilesCsv        = "FeeProfiles.csv"
 )
 
 //Table Name
@@ -626,6 +735,9 @@ const (
 	CDRsTBL               = "cdrs"
 	TBLTPSuppliers        = "tp_suppliers"
 	TBLTPAttributes       = "tp_attributes"
+	TBLTPExchangeRates    = "tp_exchange_rates"
+	TBLTPTaxProfiles      = "tp_tax_profiles"
+	TBLTPFeeProfiles      = "tp_fee_profiles"
 	TBLVersions           = "versions"
 	FilterS               = "FilterS"
 )
@@ -657,6 +769,9 @@ const (
 	CacheFilters             = "filters"
 	CacheSupplierProfiles    = "supplier_profiles"
 	CacheAttributeProfiles   = "attribute_profiles"
+	CacheExchangeRates       = "exchange_rates"
+	CacheTaxProfiles         = "tax_profiles"
+	CacheFeeProfiles         = "fee_profiles"
 )
 
 func buildCacheInstRevPrefixes() {
@@ -47,6 +47,7 @@ var (
 	ErrNoActiveSession         = errors.New("NO_ACTIVE_SESSION")
 	ErrPartiallyExecuted       = errors.New("PARTIALLY_EXECUTED")
 	ErrMaxUsageExceeded        = errors.New("MAX_USAGE_EXCEEDED")
+	ErrExchangeRateNotFound    = errors.New("EXCHANGE_RATE_NOT_FOUND")
 )
 
 // NewCGRError initialises a new CGRError
@@ -19,11 +19,16 @@ along with this program.  If not, see <http://www.gnu.org/licenses/>
 package utils
 
 import (
+	"encoding/json"
 	"fmt"
+	"io"
 	"log"
 	"log/syslog"
+	"os"
 	"reflect"
 	"runtime"
+	"strings"
+	"time"
 )
 
 var Logger LoggerInterface
@@ -57,6 +62,7 @@ func Newlogger(loggertype string) (err error) {
 type LoggerInterface interface {
 	SetSyslog(log *syslog.Writer)
 	SetLogLevel(level int)
+	SetLogFormat(format string)
 	GetSyslog() *syslog.Writer
 	Close() error
 	Emerg(m string) error
@@ -67,6 +73,7 @@ type LoggerInterface interface {
 	Notice(m string) error
 	Info(m string) error
 	Debug(m string) error
+	Log(level int, correlationID, m string) error
 	Write(p []byte) (n int, err error)
 }
 
@@ -82,10 +89,32 @@ const (
 	LOGLEVEL_DEBUG
 )
 
+var logLevelNames = map[int]string{
+	LOGLEVEL_EMERGENCY: "emergency",
+	LOGLEVEL_ALERT:     "alert",
+	LOGLEVEL_CRITICAL:  "critical",
+	LOGLEVEL_ERROR:     "error",
+	LOGLEVEL_WARNING:   "warning",
+	LOGLEVEL_NOTICE:    "notice",
+	LOGLEVEL_INFO:      "info",
+	LOGLEVEL_DEBUG:     "debug",
+}
+
+// LogEntry is the structured form of a log message, used with *json log_format
+type LogEntry struct {
+	Time          string `json:"time"`
+	Level         string `json:"level"`
+	Subsystem     string `json:"subsystem,omitempty"`
+	CorrelationID string `json:"correlation_id,omitempty"`
+	Message       string `json:"msg"`
+}
+
 // Logs to standard output
 type StdLogger struct {
-	logLevel int
-	syslog   *syslog.Writer
+	logLevel  int
+	logFormat string // <*text|*json>
+	syslog    *syslog.Writer
+	out       io.Writer // stdout logs in *json format, defaults to os.Stderr as the log package
 }
 
 func (sl *StdLogger) Close() (err error) {
@@ -115,109 +144,112 @@ func (sl *StdLogger) SetLogLevel(level int) {
 	sl.logLevel = level
 }
 
-// Alert logs to syslog with alert level
-func (sl *StdLogger) Alert(m string) (err error) {
-	if sl.logLevel < LOGLEVEL_ALERT {
+// SetLogFormat switches between free text and JSON messages
+func (sl *StdLogger) SetLogFormat(format string) {
+	sl.logFormat = format
+}
+
+// Log writes a message together with the correlation ID of the request it belongs to
+// the subsystem is taken out of the <Subsystem> prefix of the message
+func (sl *StdLogger) Log(level int, correlationID, m string) (err error) {
+	if sl.logLevel < level {
 		return
 	}
+	if sl.logFormat == MetaJSON {
+		m = sl.jsonEntry(level, correlationID, m)
+	} else if correlationID != "" {
+		m += " <correlation_id: " + correlationID + ">"
+	}
 	if sl.syslog != nil {
-		sl.syslog.Alert(m)
+		switch level {
+		case LOGLEVEL_EMERGENCY:
+			err = sl.syslog.Emerg(m)
+		case LOGLEVEL_ALERT:
+			err = sl.syslog.Alert(m)
+		case LOGLEVEL_CRITICAL:
+			err = sl.syslog.Crit(m)
+		case LOGLEVEL_ERROR:
+			err = sl.syslog.Err(m)
+		case LOGLEVEL_WARNING:
+			err = sl.syslog.Warning(m)
+		case LOGLEVEL_NOTICE:
+			err = sl.syslog.Notice(m)
+		case LOGLEVEL_INFO:
+			err = sl.syslog.Info(m)
+		default:
+			err = sl.syslog.Debug(m)
+		}
+	} else if sl.logFormat == MetaJSON {
+		_, err = fmt.Fprintln(sl.output(), m)
 	} else {
-		log.Print("[ALERT] " + m)
+		log.Print("[" + strings.ToUpper(logLevelNames[level]) + "] " + m)
 	}
 	return
 }
 
-// Crit logs to syslog with critical level
-func (sl *StdLogger) Crit(m string) (err error) {
-	if sl.logLevel < LOGLEVEL_CRITICAL {
-		return
+// jsonEntry builds the JSON form of a log message
+func (sl *StdLogger) jsonEntry(level int, correlationID, m string) string {
+	entry := &LogEntry{
+		Time:          time.Now().Format(time.RFC3339Nano),
+		Level:         logLevelNames[level],
+		CorrelationID: correlationID,
+		Message:       m,
 	}
-	if sl.syslog != nil {
-		sl.syslog.Crit(m)
-	} else {
-		log.Print("[CRITICAL] " + m)
+	if strings.HasPrefix(m, "<") {
+		if idx := strings.Index(m, ">"); idx != -1 {
+			entry.Subsystem = m[1:idx]
+			entry.Message = strings.TrimLeft(m[idx+1:], " ")
+		}
 	}
-	return
+	b, _ := json.Marshal(entry)
+	return string(b)
+}
+
+func (sl *StdLogger) output() io.Writer {
+	if sl.out == nil {
+		return os.Stderr
+	}
+	return sl.out
+}
+
+// Alert logs to syslog with alert level
+func (sl *StdLogger) Alert(m string) (err error) {
+	return sl.Log(LOGLEVEL_ALERT, "", m)
+}
+
+// Crit logs to syslog with critical level
+func (sl *StdLogger) Crit(m string) (err error) {
+	return sl.Log(LOGLEVEL_CRITICAL, "", m)
 }
 
 // Debug logs to syslog with debug level
 func (sl *StdLogger) Debug(m string) (err error) {
-	if sl.logLevel < LOGLEVEL_DEBUG {
-		return
-	}
-	if sl.syslog != nil {
-		sl.syslog.Debug(m)
-	} else {
-		log.Print("[DEBUG] " + m)
-	}
-	return
+	return sl.Log(LOGLEVEL_DEBUG, "", m)
 }
 
 // Emerg logs to syslog with emergency level
 func (sl *StdLogger) Emerg(m string) (err error) {
-	if sl.logLevel < LOGLEVEL_EMERGENCY {
-		return
-	}
-	if sl.syslog != nil {
-		sl.syslog.Emerg(m)
-	} else {
-		log.Print("[EMERGENCY] " + m)
-	}
-	return
+	return sl.Log(LOGLEVEL_EMERGENCY, "", m)
 }
 
 // Err logs to syslog with error level
 func (sl *StdLogger) Err(m string) (err error) {
-	if sl.logLevel < LOGLEVEL_ERROR {
-		return
-	}
-	if sl.syslog != nil {
-		sl.syslog.Err(m)
-	} else {
-		log.Print("[ERROR] " + m)
-	}
-	return
+	return sl.Log(LOGLEVEL_ERROR, "", m)
 }
 
 // Info logs to syslog with info level
 func (sl *StdLogger) Info(m string) (err error) {
-	if sl.logLevel < LOGLEVEL_INFO {
-		return
-	}
-	if sl.syslog != nil {
-		sl.syslog.Info(m)
-	} else {
-		log.Print("[INFO] " + m)
-	}
-	return
+	return sl.Log(LOGLEVEL_INFO, "", m)
 }
 
 // Notice logs to syslog with notice level
 func (sl *StdLogger) Notice(m string) (err error) {
-	if sl.logLevel < LOGLEVEL_NOTICE {
-		return
-	}
-	if sl.syslog != nil {
-		sl.syslog.Notice(m)
-	} else {
-		log.Print("[NOTICE] " + m)
-	}
-	return
+	return sl.Log(LOGLEVEL_NOTICE, "", m)
 }
 
 // Warning logs to syslog with warning level
 func (sl *StdLogger) Warning(m string) (err error) {
-	if sl.logLevel < LOGLEVEL_WARNING {
-		return
-	}
-
-	if sl.syslog != nil {
-		sl.syslog.Warning(m)
-	} else {
-		log.Print("[WARNING] " + m)
-	}
-	return
+	return sl.Log(LOGLEVEL_WARNING, "", m)
 }
 
 // LogStack logs to syslog the stack trace using debug level
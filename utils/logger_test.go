@@ -0,0 +1,74 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package utils
+
+import (
+	"bytes"
+	"encoding/json"
+	"log"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestLoggerJSONFormat(t *testing.T) {
+	buf := new(bytes.Buffer)
+	sl := &StdLogger{logLevel: LOGLEVEL_INFO, out: buf}
+	sl.SetLogFormat(MetaJSON)
+	if err := sl.Log(LOGLEVEL_ERROR, "cgrid1", "<CDRS> Storing CDR failed"); err != nil {
+		t.Error(err)
+	}
+	var entry LogEntry
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatal(err)
+	}
+	if entry.Level != "error" || entry.Subsystem != "CDRS" ||
+		entry.CorrelationID != "cgrid1" || entry.Message != "Storing CDR failed" {
+		t.Errorf("unexpected entry: %+v", entry)
+	}
+	if entry.Time == "" {
+		t.Error("missing time")
+	}
+	buf.Reset()
+	sl.Debug("<SMGeneric> not logged")
+	if buf.Len() != 0 {
+		t.Errorf("expected nothing logged, got: %s", buf.String())
+	}
+	sl.Warning("no subsystem")
+	entry = LogEntry{}
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatal(err)
+	}
+	if entry.Level != "warning" || entry.Subsystem != "" ||
+		entry.CorrelationID != "" || entry.Message != "no subsystem" {
+		t.Errorf("unexpected entry: %+v", entry)
+	}
+}
+
+func TestLoggerTextCorrelationID(t *testing.T) {
+	buf := new(bytes.Buffer)
+	log.SetOutput(buf)
+	defer log.SetOutput(os.Stderr)
+	sl := &StdLogger{logLevel: LOGLEVEL_INFO}
+	sl.Log(LOGLEVEL_WARNING, "cgrid1", "<SMGeneric> Could not debit")
+	if out := buf.String(); !strings.HasSuffix(out,
+		"[WARNING] <SMGeneric> Could not debit <correlation_id: cgrid1>\n") {
+		t.Errorf("unexpected output: %q", out)
+	}
+}
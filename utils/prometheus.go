@@ -0,0 +1,168 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package utils
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	PromCounter     = "counter"
+	PromGauge       = "gauge"
+	PromSummary     = "summary"
+	PromContentType = "text/plain; version=0.0.4"
+	PromNamespace   = "cgrates"
+)
+
+var (
+	metricsCollectors    = make(map[string]MetricsCollector) // scraped on each request, indexed on subsystem
+	metricsCollectorsMux sync.RWMutex
+)
+
+// PromSample is one value of a metric family, identified by its labels
+type PromSample struct {
+	Suffix string // appended to the family name, eg: _sum or _count for summaries
+	Labels map[string]string
+	Value  float64
+}
+
+// PromMetric is a metric family exported in Prometheus text format
+type PromMetric struct {
+	Name    string
+	Help    string
+	Type    string // <counter|gauge|summary>
+	Samples []*PromSample
+}
+
+// MetricsCollector returns the current metrics of one subsystem
+type MetricsCollector func() []*PromMetric
+
+// RegisterMetricsCollector adds or replaces the collector of a subsystem
+func RegisterMetricsCollector(subsystem string, mc MetricsCollector) {
+	metricsCollectorsMux.Lock()
+	metricsCollectors[subsystem] = mc
+	metricsCollectorsMux.Unlock()
+}
+
+// UnregisterMetricsCollector stops exporting the metrics of a subsystem
+func UnregisterMetricsCollector(subsystem string) {
+	metricsCollectorsMux.Lock()
+	delete(metricsCollectors, subsystem)
+	metricsCollectorsMux.Unlock()
+}
+
+// CollectMetrics queries all registered collectors, in subsystem order
+func CollectMetrics() (metrics []*PromMetric) {
+	metricsCollectorsMux.RLock()
+	subsystems := make([]string, 0, len(metricsCollectors))
+	for subsystem := range metricsCollectors {
+		subsystems = append(subsystems, subsystem)
+	}
+	sort.Strings(subsystems)
+	collectors := make([]MetricsCollector, len(subsystems))
+	for i, subsystem := range subsystems {
+		collectors[i] = metricsCollectors[subsystem]
+	}
+	metricsCollectorsMux.RUnlock()
+	metrics = rpcMetrics.collect()
+	for _, mc := range collectors {
+		metrics = append(metrics, mc()...)
+	}
+	return
+}
+
+// WritePromMetrics writes the metrics in Prometheus text exposition format
+func WritePromMetrics(w io.Writer, metrics []*PromMetric) (err error) {
+	for _, m := range metrics {
+		if m.Help != "" {
+			if _, err = fmt.Fprintf(w, "# HELP %s %s\n", m.Name, escapePromHelp(m.Help)); err != nil {
+				return
+			}
+		}
+		if m.Type != "" {
+			if _, err = fmt.Fprintf(w, "# TYPE %s %s\n", m.Name, m.Type); err != nil {
+				return
+			}
+		}
+		for _, s := range m.Samples {
+			if _, err = fmt.Fprintf(w, "%s%s%s %s\n", m.Name, s.Suffix,
+				promLabels(s.Labels), promValue(s.Value)); err != nil {
+				return
+			}
+		}
+	}
+	return
+}
+
+// MetricsHandler serves the /metrics endpoint
+func MetricsHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", PromContentType)
+	if err := WritePromMetrics(w, CollectMetrics()); err != nil {
+		Logger.Warning(fmt.Sprintf("<HTTP> error: %s writing metrics", err.Error()))
+	}
+}
+
+// NewPromGauge builds a gauge family out of a single unlabeled value
+func NewPromGauge(name, help string, value float64) *PromMetric {
+	return &PromMetric{Name: name, Help: help, Type: PromGauge,
+		Samples: []*PromSample{&PromSample{Value: value}}}
+}
+
+func promLabels(labels map[string]string) string {
+	if len(labels) == 0 {
+		return ""
+	}
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	lbls := make([]string, len(keys))
+	for i, k := range keys {
+		lbls[i] = k + "=\"" + escapePromLabel(labels[k]) + "\""
+	}
+	return "{" + strings.Join(lbls, ",") + "}"
+}
+
+func promValue(val float64) string {
+	switch {
+	case math.IsNaN(val):
+		return "NaN"
+	case math.IsInf(val, 1):
+		return "+Inf"
+	case math.IsInf(val, -1):
+		return "-Inf"
+	}
+	return strconv.FormatFloat(val, 'g', -1, 64)
+}
+
+func escapePromLabel(val string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(val)
+}
+
+func escapePromHelp(help string) string {
+	return strings.NewReplacer(`\`, `\\`, "\n", `\n`).Replace(help)
+}
@@ -0,0 +1,72 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package utils
+
+import (
+	"bytes"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestWritePromMetrics(t *testing.T) {
+	metrics := []*PromMetric{
+		NewPromGauge("cgrates_test_gauge", "Gauge\nhelp", 1.5),
+		&PromMetric{Name: "cgrates_test_total", Type: PromCounter,
+			Samples: []*PromSample{
+				&PromSample{Labels: map[string]string{"queue": "Q\"1", "tenant": "cgrates.org"}, Value: 3},
+				&PromSample{Labels: map[string]string{"tenant": "cgrates.org"}, Value: math.NaN()}}},
+	}
+	eOut := `# HELP cgrates_test_gauge Gauge\nhelp
+# TYPE cgrates_test_gauge gauge
+cgrates_test_gauge 1.5
+# TYPE cgrates_test_total counter
+cgrates_test_total{queue="Q\"1",tenant="cgrates.org"} 3
+cgrates_test_total{tenant="cgrates.org"} NaN
+`
+	var buf bytes.Buffer
+	if err := WritePromMetrics(&buf, metrics); err != nil {
+		t.Error(err)
+	} else if buf.String() != eOut {
+		t.Errorf("expecting:\n%s\nreceived:\n%s", eOut, buf.String())
+	}
+}
+
+func TestRPCCallMetrics(t *testing.T) {
+	rm := &rpcCallMetrics{methods: make(map[string]*rpcMethodMetrics)}
+	rm.record("ApierV1.Ping", time.Duration(500*time.Millisecond), false)
+	rm.record("ApierV1.Ping", time.Duration(250*time.Millisecond), true)
+	eOut := `# HELP cgrates_rpc_requests_total RPC requests served, per API
+# TYPE cgrates_rpc_requests_total counter
+cgrates_rpc_requests_total{method="ApierV1.Ping"} 2
+# HELP cgrates_rpc_errors_total RPC requests answered with error, per API
+# TYPE cgrates_rpc_errors_total counter
+cgrates_rpc_errors_total{method="ApierV1.Ping"} 1
+# HELP cgrates_rpc_request_duration_seconds Time spent serving RPC requests, per API
+# TYPE cgrates_rpc_request_duration_seconds summary
+cgrates_rpc_request_duration_seconds_sum{method="ApierV1.Ping"} 0.75
+cgrates_rpc_request_duration_seconds_count{method="ApierV1.Ping"} 2
+`
+	var buf bytes.Buffer
+	if err := WritePromMetrics(&buf, rm.collect()); err != nil {
+		t.Error(err)
+	} else if buf.String() != eOut {
+		t.Errorf("expecting:\n%s\nreceived:\n%s", eOut, buf.String())
+	}
+}
@@ -83,6 +83,36 @@ func StringToInterface(s string) interface{} {
 	return s
 }
 
+// IfaceAsFloat64 converts a numeric interface into float64
+// durations are returned as nanoseconds so they can be aggregated together with data units
+func IfaceAsFloat64(itm interface{}) (f float64, err error) {
+	switch it := itm.(type) {
+	case float64:
+		return it, nil
+	case time.Duration:
+		return float64(it.Nanoseconds()), nil
+	case string:
+		if f, err = strconv.ParseFloat(it, 64); err == nil {
+			return
+		}
+		var d time.Duration
+		if d, err = ParseDurationWithNanosecs(it); err != nil {
+			return 0, fmt.Errorf("cannot convert %s to float64", it)
+		}
+		return float64(d.Nanoseconds()), nil
+	}
+	valItm := reflect.ValueOf(itm)
+	switch valItm.Kind() {
+	case reflect.Float32, reflect.Float64:
+		return valItm.Float(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(valItm.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(valItm.Uint()), nil
+	}
+	return 0, fmt.Errorf("cannot convert %+v to float64", itm)
+}
+
 // ReflectFieldInterface parses intf attepting to return the field as string or error otherwise
 // Supports "ExtraFields" where additional fields are dynamically inserted in map with field name: extraFieldsLabel
 func ReflectFieldInterface(intf interface{}, fldName, extraFieldsLabel string) (retIf interface{}, err error) {
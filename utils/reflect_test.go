@@ -246,3 +246,27 @@ func TestCastFieldIfToString(t *testing.T) {
 		t.Errorf("received: %s", strVal)
 	}
 }
+
+func TestIfaceAsFloat64(t *testing.T) {
+	for val, eVal := range map[interface{}]float64{
+		1.5:                            1.5,
+		int64(3):                       3,
+		uint8(2):                       2,
+		float32(0.5):                   0.5,
+		time.Duration(2 * time.Second): 2000000000,
+		"1.25":                         1.25,
+		"1m":                           60000000000,
+	} {
+		if f, err := IfaceAsFloat64(val); err != nil {
+			t.Error(err)
+		} else if f != eVal {
+			t.Errorf("val: %+v, expecting: %v, received: %v", val, eVal, f)
+		}
+	}
+	if _, err := IfaceAsFloat64("notnumeric"); err == nil {
+		t.Error("expecting error")
+	}
+	if _, err := IfaceAsFloat64(true); err == nil {
+		t.Error("expecting error")
+	}
+}
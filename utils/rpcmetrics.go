@@ -0,0 +1,168 @@
+/*
+Real-time Online/Offline Charging System (OCS) for Telecom & ISP environments
+Copyright (C) ITsysCOM GmbH
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU General Public License as published by
+the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU General Public License for more details.
+
+You should have received a copy of the GNU General Public License
+along with this program.  If not, see <http://www.gnu.org/licenses/>
+*/
+
+package utils
+
+import (
+	"bufio"
+	"encoding/gob"
+	"io"
+	"net/rpc"
+	"sort"
+	"sync"
+	"time"
+)
+
+var rpcMetrics = &rpcCallMetrics{methods: make(map[string]*rpcMethodMetrics)}
+
+// rpcMethodMetrics counts the calls received for one API
+type rpcMethodMetrics struct {
+	calls    uint64
+	errors   uint64
+	duration time.Duration
+}
+
+// rpcCallMetrics keeps the RPC metrics indexed on ServiceMethod
+type rpcCallMetrics struct {
+	sync.RWMutex
+	methods map[string]*rpcMethodMetrics
+}
+
+func (rm *rpcCallMetrics) record(method string, dur time.Duration, failed bool) {
+	rm.Lock()
+	mm, has := rm.methods[method]
+	if !has {
+		mm = new(rpcMethodMetrics)
+		rm.methods[method] = mm
+	}
+	mm.calls++
+	if failed {
+		mm.errors++
+	}
+	mm.duration += dur
+	rm.Unlock()
+}
+
+func (rm *rpcCallMetrics) collect() []*PromMetric {
+	calls := &PromMetric{Name: PromNamespace + "_rpc_requests_total",
+		Help: "RPC requests served, per API", Type: PromCounter}
+	errs := &PromMetric{Name: PromNamespace + "_rpc_errors_total",
+		Help: "RPC requests answered with error, per API", Type: PromCounter}
+	latency := &PromMetric{Name: PromNamespace + "_rpc_request_duration_seconds",
+		Help: "Time spent serving RPC requests, per API", Type: PromSummary}
+	rm.RLock()
+	methods := make([]string, 0, len(rm.methods))
+	for method := range rm.methods {
+		methods = append(methods, method)
+	}
+	sort.Strings(methods)
+	for _, method := range methods {
+		mm := rm.methods[method]
+		lbls := map[string]string{"method": method}
+		calls.Samples = append(calls.Samples, &PromSample{Labels: lbls, Value: float64(mm.calls)})
+		errs.Samples = append(errs.Samples, &PromSample{Labels: lbls, Value: float64(mm.errors)})
+		latency.Samples = append(latency.Samples,
+			&PromSample{Suffix: "_sum", Labels: lbls, Value: mm.duration.Seconds()},
+			&PromSample{Suffix: "_count", Labels: lbls, Value: float64(mm.calls)})
+	}
+	rm.RUnlock()
+	return []*PromMetric{calls, errs, latency}
+}
+
+// metricsServerCodec measures the requests passing through a rpc.ServerCodec
+type metricsServerCodec struct {
+	rpc.ServerCodec
+	sync.Mutex
+	started map[uint64]time.Time // request start, indexed on sequence
+}
+
+func newMetricsServerCodec(c rpc.ServerCodec) rpc.ServerCodec {
+	return &metricsServerCodec{ServerCodec: c, started: make(map[uint64]time.Time)}
+}
+
+func (c *metricsServerCodec) ReadRequestHeader(r *rpc.Request) (err error) {
+	if err = c.ServerCodec.ReadRequestHeader(r); err != nil {
+		return
+	}
+	c.Lock()
+	c.started[r.Seq] = time.Now()
+	c.Unlock()
+	return
+}
+
+func (c *metricsServerCodec) WriteResponse(r *rpc.Response, body interface{}) error {
+	c.Lock()
+	start, has := c.started[r.Seq]
+	delete(c.started, r.Seq)
+	c.Unlock()
+	if has {
+		rpcMetrics.record(r.ServiceMethod, time.Since(start), r.Error != "")
+	}
+	return c.ServerCodec.WriteResponse(r, body)
+}
+
+// gobServerCodec mirrors the codec used internally by rpc.ServeConn
+type gobServerCodec struct {
+	rwc    io.ReadWriteCloser
+	dec    *gob.Decoder
+	enc    *gob.Encoder
+	encBuf *bufio.Writer
+	closed bool
+}
+
+func newGobServerCodec(conn io.ReadWriteCloser) rpc.ServerCodec {
+	buf := bufio.NewWriter(conn)
+	return &gobServerCodec{
+		rwc:    conn,
+		dec:    gob.NewDecoder(conn),
+		enc:    gob.NewEncoder(buf),
+		encBuf: buf,
+	}
+}
+
+func (c *gobServerCodec) ReadRequestHeader(r *rpc.Request) error {
+	return c.dec.Decode(r)
+}
+
+func (c *gobServerCodec) ReadRequestBody(body interface{}) error {
+	return c.dec.Decode(body)
+}
+
+func (c *gobServerCodec) WriteResponse(r *rpc.Response, body interface{}) (err error) {
+	if err = c.enc.Encode(r); err != nil {
+		if c.encBuf.Flush() == nil { // gob couldn't encode the header, should not happen
+			c.Close()
+		}
+		return
+	}
+	if err = c.enc.Encode(body); err != nil {
+		if c.encBuf.Flush() == nil { // was a gob problem encoding the body but the header has been written
+			c.Close()
+		}
+		return
+	}
+	return c.encBuf.Flush()
+}
+
+func (c *gobServerCodec) Close() error {
+	if c.closed { // only call c.rwc.Close once, otherwise the semantics are undefined
+		return nil
+	}
+	c.closed = true
+	return c.rwc.Close()
+}
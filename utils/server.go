@@ -126,7 +126,7 @@ func (s *Server) ServeJSON(addr string) {
 			continue
 		}
 		//utils.Logger.Info(fmt.Sprintf("<CGRServer> New incoming connection: %v", conn.RemoteAddr()))
-		go jsonrpc.ServeConn(conn)
+		go rpc.ServeCodec(newMetricsServerCodec(jsonrpc.NewServerCodec(conn)))
 	}
 
 }
@@ -162,7 +162,7 @@ func (s *Server) ServeGOB(addr string) {
 		}
 
 		//utils.Logger.Info(fmt.Sprintf("<CGRServer> New incoming connection: %v", conn.RemoteAddr()))
-		go rpc.ServeConn(conn)
+		go rpc.ServeCodec(newMetricsServerCodec(newGobServerCodec(conn)))
 	}
 }
 
@@ -173,7 +173,8 @@ func handleRequest(w http.ResponseWriter, r *http.Request) {
 	io.Copy(w, res)
 }
 
-func (s *Server) ServeHTTP(addr string, jsonRPCURL string, wsRPCURL string, useBasicAuth bool, userList map[string]string) {
+func (s *Server) ServeHTTP(addr string, jsonRPCURL string, wsRPCURL string, metricsURL string,
+	useBasicAuth bool, userList map[string]string) {
 	s.RLock()
 	enabled := s.rpcEnabled
 	s.RUnlock()
@@ -198,7 +199,7 @@ func (s *Server) ServeHTTP(addr string, jsonRPCURL string, wsRPCURL string, useB
 		s.Unlock()
 		Logger.Info("<HTTP> enabling handler for WebSocket connections")
 		wsHandler := websocket.Handler(func(ws *websocket.Conn) {
-			jsonrpc.ServeConn(ws)
+			rpc.ServeCodec(newMetricsServerCodec(jsonrpc.NewServerCodec(ws)))
 		})
 		if useBasicAuth {
 			http.HandleFunc(wsRPCURL, use(func(w http.ResponseWriter, r *http.Request) {
@@ -209,6 +210,18 @@ func (s *Server) ServeHTTP(addr string, jsonRPCURL string, wsRPCURL string, useB
 		}
 	}
 
+	if enabled && metricsURL != "" {
+		s.Lock()
+		s.httpEnabled = true
+		s.Unlock()
+		Logger.Info("<HTTP> enabling handler for Prometheus metrics")
+		if useBasicAuth {
+			http.HandleFunc(metricsURL, use(MetricsHandler, basicAuth(userList)))
+		} else {
+			http.HandleFunc(metricsURL, MetricsHandler)
+		}
+	}
+
 	if !s.httpEnabled {
 		return
 	}
@@ -272,7 +285,7 @@ func (r *rpcRequest) Close() error {
 
 // Call invokes the RPC request, waits for it to complete, and returns the results.
 func (r *rpcRequest) Call() io.Reader {
-	go jsonrpc.ServeConn(r)
+	go rpc.ServeCodec(newMetricsServerCodec(jsonrpc.NewServerCodec(r)))
 	<-r.done
 	return r.rw
 }